@@ -0,0 +1,192 @@
+// Package ahocorasick реализует поиск множества фиксированных строк
+// за один проход по тексту (алгоритм Ахо — Корасик).
+//
+// Время поиска линейно по длине текста и не зависит от количества
+// шаблонов, поэтому grep -F с сотнями -e PATTERN работает так же быстро,
+// как с одним шаблоном.
+package ahocorasick
+
+import "unicode/utf8"
+
+// node — состояние автомата: вершина бора шаблонов.
+type node struct {
+	children map[byte]int
+	fail     int // суффиксная ссылка: самый длинный собственный суффикс в боре
+	dict     int // ближайшая по суффиксным ссылкам вершина-шаблон, -1 — нет
+	length   int // длина шаблона, заканчивающегося в вершине, 0 — не шаблон
+}
+
+// Matcher ищет вхождения набора шаблонов. Безопасен для одновременного
+// использования из нескольких горутин.
+type Matcher struct {
+	nodes      []node
+	ignoreCase bool
+	maxLength  int  // длина самого длинного шаблона
+	empty      bool // среди шаблонов есть пустая строка
+}
+
+// New строит автомат для шаблонов. При ignoreCase регистр латинских букв
+// не учитывается; смещения совпадений при этом не меняются.
+func New(patterns []string, ignoreCase bool) *Matcher {
+	m := &Matcher{
+		nodes:      []node{{children: map[byte]int{}, dict: -1}},
+		ignoreCase: ignoreCase,
+	}
+
+	for _, pattern := range patterns {
+		if pattern == "" {
+			m.empty = true
+			continue
+		}
+		m.insert(pattern)
+		m.maxLength = max(m.maxLength, len(pattern))
+	}
+	m.link()
+	return m
+}
+
+func (m *Matcher) insert(pattern string) {
+	current := 0
+	for i := 0; i < len(pattern); i++ {
+		c := m.fold(pattern[i])
+		next, ok := m.nodes[current].children[c]
+		if !ok {
+			next = len(m.nodes)
+			m.nodes = append(m.nodes, node{children: map[byte]int{}, dict: -1})
+			m.nodes[current].children[c] = next
+		}
+		current = next
+	}
+	m.nodes[current].length = len(pattern)
+}
+
+// link вычисляет суффиксные и словарные ссылки обходом бора в ширину.
+func (m *Matcher) link() {
+	queue := make([]int, 0, len(m.nodes))
+	for _, child := range m.nodes[0].children {
+		queue = append(queue, child)
+	}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for c, child := range m.nodes[current].children {
+			fail := m.nodes[current].fail
+			for {
+				if next, ok := m.nodes[fail].children[c]; ok && next != child {
+					m.nodes[child].fail = next
+					break
+				}
+				if fail == 0 {
+					m.nodes[child].fail = 0
+					break
+				}
+				fail = m.nodes[fail].fail
+			}
+
+			suffix := m.nodes[child].fail
+			if m.nodes[suffix].length > 0 {
+				m.nodes[child].dict = suffix
+			} else {
+				m.nodes[child].dict = m.nodes[suffix].dict
+			}
+			queue = append(queue, child)
+		}
+	}
+}
+
+// step выполняет переход автомата по байту c.
+func (m *Matcher) step(state int, c byte) int {
+	c = m.fold(c)
+	for {
+		if next, ok := m.nodes[state].children[c]; ok {
+			return next
+		}
+		if state == 0 {
+			return 0
+		}
+		state = m.nodes[state].fail
+	}
+}
+
+func (m *Matcher) fold(c byte) byte {
+	if m.ignoreCase && c >= 'A' && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+	return c
+}
+
+// Match сообщает, встречается ли в s хотя бы один шаблон.
+func (m *Matcher) Match(s string) bool {
+	if m.empty {
+		return true
+	}
+
+	state := 0
+	for i := 0; i < len(s); i++ {
+		state = m.step(state, s[i])
+		if m.nodes[state].length > 0 || m.nodes[state].dict >= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// FindAll возвращает непересекающиеся вхождения шаблонов в s в виде пар
+// [начало, конец]. Из вхождений, начинающихся левее, выбирается самое
+// левое, а из начинающихся в одной позиции — самое длинное, как в grep -F.
+func (m *Matcher) FindAll(s string) [][]int {
+	var matches [][]int
+	for pos := 0; pos <= len(s); {
+		start, end, ok := m.find(s, pos)
+		if !ok {
+			break
+		}
+		matches = append(matches, []int{start, end})
+
+		if end > start {
+			pos = end
+			continue
+		}
+		// Пустое совпадение: переходим к следующему символу
+		if start == len(s) {
+			break
+		}
+		_, size := utf8.DecodeRuneInString(s[start:])
+		pos = start + size
+	}
+	return matches
+}
+
+// find ищет самое левое и, при равных началах, самое длинное вхождение,
+// начинающееся не раньше pos.
+func (m *Matcher) find(s string, pos int) (int, int, bool) {
+	bestStart, bestEnd := -1, -1
+	if m.empty {
+		bestStart, bestEnd = pos, pos
+	}
+
+	state := 0
+	for i := pos; i < len(s); i++ {
+		// Вхождение, начинающееся не правее bestStart, должно закончиться
+		// не позже bestStart+maxLength; дальше искать бессмысленно
+		if bestStart >= 0 && i >= bestStart+m.maxLength {
+			break
+		}
+
+		state = m.step(state, s[i])
+		for out := state; out >= 0; out = m.nodes[out].dict {
+			length := m.nodes[out].length
+			if length == 0 {
+				continue
+			}
+			start := i + 1 - length
+			if bestStart < 0 || start < bestStart || start == bestStart && i+1 > bestEnd {
+				bestStart, bestEnd = start, i+1
+			}
+		}
+	}
+
+	return bestStart, bestEnd, bestStart >= 0
+}
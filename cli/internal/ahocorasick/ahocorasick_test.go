@@ -0,0 +1,102 @@
+package ahocorasick
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFindAll(t *testing.T) {
+	tests := []struct {
+		patterns   []string
+		ignoreCase bool
+		text       string
+		expected   [][]int
+	}{
+		{[]string{"he", "she", "his", "hers"}, false, "ushers", [][]int{{1, 4}}},
+		{[]string{"a.b"}, false, "xa.bya.b", [][]int{{1, 4}, {5, 8}}},
+		{[]string{"ab", "abcd", "bc"}, false, "abcd", [][]int{{0, 4}}},
+		{[]string{"bcd", "ab"}, false, "abcd", [][]int{{0, 2}}},
+		{[]string{"aa"}, false, "aaaaa", [][]int{{0, 2}, {2, 4}}},
+		{[]string{"Error"}, true, "ERROR error", [][]int{{0, 5}, {6, 11}}},
+		{[]string{"x"}, false, "abc", nil},
+		{[]string{"", "b"}, false, "ab", [][]int{{0, 0}, {1, 2}, {2, 2}}},
+		{[]string{"ж"}, false, "ёж", [][]int{{2, 4}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.patterns, tt.text), func(t *testing.T) {
+			got := New(tt.patterns, tt.ignoreCase).FindAll(tt.text)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ожидалось %v, получено %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	m := New([]string{"needle", "pin"}, false)
+	if !m.Match("a haystack with a needle") || !m.Match("spin") || m.Match("nothing") {
+		t.Fatal("неверный результат Match")
+	}
+	if New(nil, false).Match("anything") {
+		t.Fatal("пустой набор шаблонов не должен совпадать")
+	}
+	if !New([]string{""}, false).Match("") {
+		t.Fatal("пустой шаблон совпадает с любой строкой")
+	}
+}
+
+// TestAgainstNaive сравнивает автомат с наивным поиском на множестве
+// шаблонов с общими префиксами и суффиксами.
+func TestAgainstNaive(t *testing.T) {
+	patterns := []string{"a", "ab", "bab", "bc", "bca", "c", "caa"}
+	texts := []string{"abccab", "bcabab", "cabcaa", "aabbcc", "bababc"}
+
+	for _, text := range texts {
+		got := New(patterns, false).FindAll(text)
+		expected := naiveFindAll(patterns, text)
+		if !reflect.DeepEqual(got, expected) {
+			t.Errorf("%q: ожидалось %v, получено %v", text, expected, got)
+		}
+	}
+}
+
+func naiveFindAll(patterns []string, text string) [][]int {
+	var matches [][]int
+	for pos := 0; pos < len(text); {
+		found := false
+		for start := pos; start < len(text) && !found; start++ {
+			best := -1
+			for _, p := range patterns {
+				if strings.HasPrefix(text[start:], p) && len(p) > best {
+					best = len(p)
+				}
+			}
+			if best > 0 {
+				matches = append(matches, []int{start, start + best})
+				pos = start + best
+				found = true
+			}
+		}
+		if !found {
+			break
+		}
+	}
+	return matches
+}
+
+func BenchmarkManyPatterns(b *testing.B) {
+	patterns := make([]string, 500)
+	for i := range patterns {
+		patterns[i] = fmt.Sprintf("identifier%03d", i)
+	}
+	m := New(patterns, false)
+	line := strings.Repeat("some ordinary source line without matches ", 4) + "identifier499"
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.FindAll(line)
+	}
+}
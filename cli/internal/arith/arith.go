@@ -0,0 +1,467 @@
+// Package arith вычисляет целочисленные арифметические выражения оболочки:
+// то же подмножество, что и в $((...)) bash, но без операторов
+// присваивания и инкремента.
+package arith
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrDivisionByZero — деление или остаток от деления на 0.
+var ErrDivisionByZero = errors.New("деление на 0")
+
+// Error — ошибка в выражении Expr. Для выражения из значения переменной
+// Expr — это значение, а не исходное выражение.
+type Error struct {
+	Expr string
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return e.Expr + ": " + e.Err.Error()
+}
+
+// Unwrap возвращает причину ошибки.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// maxDepth ограничивает вложенность выражений в значениях переменных:
+// a=b, b=a не должны приводить к бесконечной рекурсии.
+const maxDepth = 1024
+
+// binaryPrecedence — приоритеты бинарных операторов, как в bash;
+// чем больше число, тем сильнее связывание.
+var binaryPrecedence = map[string]int{
+	",":  1,
+	"||": 3,
+	"&&": 4,
+	"|":  5,
+	"^":  6,
+	"&":  7,
+	"==": 8, "!=": 8,
+	"<": 9, "<=": 9, ">": 9, ">=": 9,
+	"<<": 10, ">>": 10,
+	"+": 11, "-": 11,
+	"*": 12, "/": 12, "%": 12,
+	"**": 13,
+}
+
+// ternaryPrecedence — приоритет условного оператора ?:.
+const ternaryPrecedence = 2
+
+// operators перечисляет операторы; более длинные идут раньше своих префиксов.
+var operators = []string{
+	"**=", "<<=", ">>=",
+	"**", "<<", ">>", "<=", ">=", "==", "!=", "&&", "||", "++", "--",
+	"+=", "-=", "*=", "/=", "%=", "&=", "^=", "|=",
+	"+", "-", "*", "/", "%", "<", ">", "&", "^", "|", "!", "~", "?", ":", "(", ")", ",", "=",
+}
+
+// Eval вычисляет выражение expr. Имя переменной заменяется значением,
+// которое возвращает lookup; значение само вычисляется как выражение, а
+// пустое значение равно 0. Пустое выражение равно 0.
+//
+// Примеры:
+//
+//	Eval("2 + 3 * 4", nil)        → 14
+//	Eval("n > 0 ? n : -n", lookup) → модуль n
+func Eval(expr string, lookup func(name string) string) (int64, error) {
+	return eval(expr, lookup, 0)
+}
+
+func eval(expr string, lookup func(name string) string, depth int) (int64, error) {
+	if depth > maxDepth {
+		return 0, &Error{Expr: expr, Err: errors.New("слишком глубокая рекурсия выражения")}
+	}
+	tokens, err := tokenize(expr)
+	if err != nil {
+		return 0, &Error{Expr: expr, Err: err}
+	}
+	if len(tokens) == 0 {
+		return 0, nil
+	}
+
+	p := &parser{tokens: tokens}
+	root, err := p.parseExpr(0)
+	if err == nil && !p.atEnd() {
+		err = p.unexpected()
+	}
+	if err != nil {
+		return 0, &Error{Expr: expr, Err: err}
+	}
+
+	e := &evaluator{lookup: lookup, depth: depth}
+	value, err := e.eval(root)
+	var exprErr *Error
+	if err != nil && !errors.As(err, &exprErr) {
+		err = &Error{Expr: expr, Err: err}
+	}
+	return value, err
+}
+
+// tokenKind — вид лексемы выражения.
+type tokenKind int
+
+const (
+	numberToken tokenKind = iota
+	nameToken
+	operatorToken
+)
+
+type token struct {
+	kind tokenKind
+	text string
+}
+
+// tokenize разбивает выражение на числа, имена и операторы.
+func tokenize(expr string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(expr); {
+		c := expr[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			i++
+		case isDigit(c):
+			start := i
+			for i < len(expr) && (isNameByte(expr[i]) || expr[i] == '#' || expr[i] == '@') {
+				i++
+			}
+			tokens = append(tokens, token{numberToken, expr[start:i]})
+		case isNameByte(c):
+			start := i
+			for i < len(expr) && isNameByte(expr[i]) {
+				i++
+			}
+			tokens = append(tokens, token{nameToken, expr[start:i]})
+		default:
+			op := operatorAt(expr[i:])
+			if op == "" {
+				return nil, fmt.Errorf("синтаксическая ошибка в выражении (неверный маркер %q)", expr[i:])
+			}
+			tokens = append(tokens, token{operatorToken, op})
+			i += len(op)
+		}
+	}
+	return tokens, nil
+}
+
+func operatorAt(s string) string {
+	for _, op := range operators {
+		if strings.HasPrefix(s, op) {
+			return op
+		}
+	}
+	return ""
+}
+
+// node — узел разобранного выражения.
+type node struct {
+	op      string // оператор; "" — число или переменная
+	number  int64
+	name    string
+	x, y, z *node
+	unary   bool
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) atEnd() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *parser) peekOperator() string {
+	if p.atEnd() || p.tokens[p.pos].kind != operatorToken {
+		return ""
+	}
+	return p.tokens[p.pos].text
+}
+
+func (p *parser) unexpected() error {
+	if p.atEnd() {
+		return errors.New("синтаксическая ошибка: ожидался операнд")
+	}
+	return fmt.Errorf("синтаксическая ошибка в выражении (неверный маркер %q)", p.tokens[p.pos].text)
+}
+
+// parseExpr разбирает выражение из операторов с приоритетом не ниже min.
+func (p *parser) parseExpr(min int) (*node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		op := p.peekOperator()
+		if op == "?" && ternaryPrecedence >= min {
+			p.pos++
+			then, err := p.parseExpr(0)
+			if err != nil {
+				return nil, err
+			}
+			if p.peekOperator() != ":" {
+				return nil, p.unexpected()
+			}
+			p.pos++
+			otherwise, err := p.parseExpr(ternaryPrecedence)
+			if err != nil {
+				return nil, err
+			}
+			left = &node{op: "?", x: left, y: then, z: otherwise}
+			continue
+		}
+
+		prec, ok := binaryPrecedence[op]
+		if !ok || prec < min {
+			if isAssignment(op) {
+				return nil, fmt.Errorf("присваивание %q не поддерживается", op)
+			}
+			return left, nil
+		}
+		p.pos++
+
+		// ** правоассоциативен, остальные операторы — левоассоциативны
+		next := prec + 1
+		if op == "**" {
+			next = prec
+		}
+		right, err := p.parseExpr(next)
+		if err != nil {
+			return nil, err
+		}
+		left = &node{op: op, x: left, y: right}
+	}
+}
+
+func (p *parser) parseUnary() (*node, error) {
+	if p.atEnd() {
+		return nil, p.unexpected()
+	}
+
+	tok := p.tokens[p.pos]
+	p.pos++
+	switch tok.kind {
+	case numberToken:
+		n, err := parseNumber(tok.text)
+		if err != nil {
+			return nil, err
+		}
+		return &node{number: n}, nil
+	case nameToken:
+		return &node{name: tok.text}, nil
+	}
+
+	switch tok.text {
+	case "+", "-", "!", "~":
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &node{op: tok.text, x: x, unary: true}, nil
+	case "(":
+		x, err := p.parseExpr(0)
+		if err != nil {
+			return nil, err
+		}
+		if p.peekOperator() != ")" {
+			return nil, p.unexpected()
+		}
+		p.pos++
+		return x, nil
+	case "++", "--":
+		return nil, fmt.Errorf("оператор %q не поддерживается", tok.text)
+	}
+	p.pos--
+	return nil, p.unexpected()
+}
+
+func isAssignment(op string) bool {
+	return op == "=" || len(op) > 1 && strings.HasSuffix(op, "=") &&
+		op != "==" && op != "!=" && op != "<=" && op != ">="
+}
+
+// parseNumber разбирает целое число: десятичное, 0x — шестнадцатеричное,
+// 0 — восьмеричное, BASE#DIGITS — в системе счисления BASE от 2 до 64.
+func parseNumber(text string) (int64, error) {
+	base, digits := 10, text
+	switch {
+	case strings.Contains(text, "#"):
+		prefix, rest, _ := strings.Cut(text, "#")
+		b, err := strconv.Atoi(prefix)
+		if err != nil || b < 2 || b > 64 {
+			return 0, fmt.Errorf("%s: неверное основание системы счисления", text)
+		}
+		base, digits = b, rest
+	case len(text) > 2 && (text[:2] == "0x" || text[:2] == "0X"):
+		base, digits = 16, text[2:]
+	case len(text) > 1 && text[0] == '0':
+		base, digits = 8, text[1:]
+	}
+	if digits == "" {
+		return 0, fmt.Errorf("%s: неверное число", text)
+	}
+
+	var n int64
+	for i := 0; i < len(digits); i++ {
+		d := digitValue(digits[i], base)
+		if d < 0 || d >= base {
+			return 0, fmt.Errorf("%s: слишком большое значение для основания", text)
+		}
+		n = n*int64(base) + int64(d)
+	}
+	return n, nil
+}
+
+// digitValue возвращает значение цифры: 0-9, затем a-z, A-Z, @ и _. При
+// основании не больше 36 строчные и прописные буквы равны.
+func digitValue(c byte, base int) int {
+	switch {
+	case isDigit(c):
+		return int(c - '0')
+	case c >= 'a' && c <= 'z':
+		return int(c-'a') + 10
+	case c >= 'A' && c <= 'Z' && base <= 36:
+		return int(c-'A') + 10
+	case c >= 'A' && c <= 'Z':
+		return int(c-'A') + 36
+	case c == '@':
+		return 62
+	case c == '_':
+		return 63
+	}
+	return -1
+}
+
+type evaluator struct {
+	lookup func(name string) string
+	depth  int
+}
+
+func (e *evaluator) eval(n *node) (int64, error) {
+	switch {
+	case n.op == "" && n.name == "":
+		return n.number, nil
+	case n.op == "":
+		if e.lookup == nil {
+			return 0, nil
+		}
+		return eval(e.lookup(n.name), e.lookup, e.depth+1)
+	case n.unary:
+		x, err := e.eval(n.x)
+		if err != nil {
+			return 0, err
+		}
+		return unary(n.op, x), nil
+	}
+
+	x, err := e.eval(n.x)
+	if err != nil {
+		return 0, err
+	}
+	// Правая часть &&, || и невыбранная ветвь ?: не вычисляются
+	switch {
+	case n.op == "&&" && x == 0, n.op == "||" && x != 0:
+		return boolValue(x != 0), nil
+	case n.op == "?" && x != 0:
+		return e.eval(n.y)
+	case n.op == "?":
+		return e.eval(n.z)
+	}
+
+	y, err := e.eval(n.y)
+	if err != nil {
+		return 0, err
+	}
+	return binary(n.op, x, y)
+}
+
+func unary(op string, x int64) int64 {
+	switch op {
+	case "-":
+		return -x
+	case "!":
+		return boolValue(x == 0)
+	case "~":
+		return ^x
+	}
+	return x
+}
+
+func binary(op string, x, y int64) (int64, error) {
+	switch op {
+	case ",":
+		return y, nil
+	case "||", "&&":
+		return boolValue(y != 0), nil
+	case "|":
+		return x | y, nil
+	case "^":
+		return x ^ y, nil
+	case "&":
+		return x & y, nil
+	case "==":
+		return boolValue(x == y), nil
+	case "!=":
+		return boolValue(x != y), nil
+	case "<":
+		return boolValue(x < y), nil
+	case "<=":
+		return boolValue(x <= y), nil
+	case ">":
+		return boolValue(x > y), nil
+	case ">=":
+		return boolValue(x >= y), nil
+	case "<<":
+		return x << (uint64(y) & 63), nil
+	case ">>":
+		return x >> (uint64(y) & 63), nil
+	case "+":
+		return x + y, nil
+	case "-":
+		return x - y, nil
+	case "*":
+		return x * y, nil
+	case "/", "%":
+		if y == 0 {
+			return 0, ErrDivisionByZero
+		}
+		if op == "/" {
+			return x / y, nil
+		}
+		return x % y, nil
+	case "**":
+		if y < 0 {
+			return 0, errors.New("отрицательная степень")
+		}
+		result := int64(1)
+		for ; y > 0; y >>= 1 {
+			if y&1 == 1 {
+				result *= x
+			}
+			x *= x
+		}
+		return result, nil
+	}
+	return 0, fmt.Errorf("неизвестный оператор %q", op)
+}
+
+func boolValue(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isNameByte(c byte) bool {
+	return c == '_' || isDigit(c) || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
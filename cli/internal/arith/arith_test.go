@@ -0,0 +1,98 @@
+package arith
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestEval(t *testing.T) {
+	tests := []struct {
+		expr     string
+		expected int64
+	}{
+		{"2+3*4", 14},
+		{"-2**2", 4},
+		{"2**3**2", 512},
+		{"7/2", 3},
+		{"-7%3", -1},
+		{"1<<4", 16},
+		{"0x1F", 31},
+		{"017", 15},
+		{"2#101", 5},
+		{"64#_", 63},
+		{"36#z", 35},
+		{"1?2:3", 2},
+		{"0?1:0?2:3", 3},
+		{"0&&1/0", 0},
+		{"1||1/0", 1},
+		{"!5", 0},
+		{"~0", -1},
+		{"1,2", 2},
+		{"3>2==1", 1},
+		{"5&3|8^1", 9},
+		{" ( 1 + 2 ) * 3 ", 9},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		got, err := Eval(tt.expr, nil)
+		if err != nil || got != tt.expected {
+			t.Errorf("%q: ожидалось %d, получено %d (%v)", tt.expr, tt.expected, got, err)
+		}
+	}
+}
+
+func TestEval_Variables(t *testing.T) {
+	vars := map[string]string{"n": "5", "expr": "n*2", "empty": "", "loop": "loop"}
+	lookup := func(name string) string { return vars[name] }
+
+	tests := []struct {
+		expr     string
+		expected int64
+	}{
+		{"n+1", 6},
+		{"expr+1", 11},
+		{"empty+unset", 0},
+	}
+	for _, tt := range tests {
+		got, err := Eval(tt.expr, lookup)
+		if err != nil || got != tt.expected {
+			t.Errorf("%q: ожидалось %d, получено %d (%v)", tt.expr, tt.expected, got, err)
+		}
+	}
+
+	if _, err := Eval("loop", lookup); err == nil || !strings.Contains(err.Error(), "рекурсия") {
+		t.Errorf("ожидалась ошибка рекурсии, получено %v", err)
+	}
+}
+
+func TestEval_Errors(t *testing.T) {
+	tests := []struct {
+		expr    string
+		message string
+	}{
+		{"1/0", "1/0: деление на 0"},
+		{"08", "08: слишком большое значение для основания"},
+		{"2+", "2+: синтаксическая ошибка: ожидался операнд"},
+		{"(1", "ожидался операнд"},
+		{"1 2", `неверный маркер "2"`},
+		{"a=1", `присваивание "=" не поддерживается`},
+		{"a++", `неверный маркер "++"`},
+		{"2**-1", "отрицательная степень"},
+		{"1 $ 2", "неверный маркер"},
+		{"65#1", "неверное основание"},
+	}
+	for _, tt := range tests {
+		_, err := Eval(tt.expr, nil)
+		if err == nil || !strings.Contains(err.Error(), tt.message) {
+			t.Errorf("%q: ожидалась ошибка %q, получено %v", tt.expr, tt.message, err)
+		}
+	}
+
+	vars := map[string]string{"bad": "1/0"}
+	_, err := Eval("x+bad", func(name string) string { return vars[name] })
+	var exprErr *Error
+	if !errors.As(err, &exprErr) || exprErr.Expr != "1/0" || !errors.Is(err, ErrDivisionByZero) {
+		t.Errorf("ошибка должна относиться к значению переменной, получено %v", err)
+	}
+}
@@ -24,8 +24,9 @@ func IsExternalCommand(commandName string) bool {
 }
 
 func IsEnvAssignmentCommand(command string) bool {
-	// Регулярное выражение для проверки формата VAR=value
-	envPattern := `^[a-zA-Z_][a-zA-Z0-9_]*=.*$`
+	// Регулярное выражение для проверки формата VAR=value, VAR+=value,
+	// VAR[SUB]=value и VAR[SUB]+=value
+	envPattern := `^[a-zA-Z_][a-zA-Z0-9_]*(\[[^]]*\])?\+?=.*$`
 	matched, _ := regexp.MatchString(envPattern, command)
 	return matched
 }
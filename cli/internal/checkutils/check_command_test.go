@@ -47,4 +47,15 @@ func TestIsEnvAssignmentCommand(t *testing.T) {
 	if IsEnvAssignmentCommand("1INVALID=value") {
 		t.Fatalf("некорректный формат не должен распознаваться")
 	}
+
+	for _, command := range []string{"FOO+=bar", "a[1]=x", "a[k=v]+=x", "a="} {
+		if !IsEnvAssignmentCommand(command) {
+			t.Fatalf("%q: формат присваивания должен распознаваться", command)
+		}
+	}
+	for _, command := range []string{"a[1=x", "a]=x", "a+x=1", "+=x"} {
+		if IsEnvAssignmentCommand(command) {
+			t.Fatalf("%q: некорректный формат не должен распознаваться", command)
+		}
+	}
 }
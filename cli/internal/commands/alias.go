@@ -0,0 +1,168 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// AliasCommand реализует встроенную команду "alias".
+// Она определяет псевдонимы команд в CommandContext.Aliases и выводит
+// уже определённые.
+type AliasCommand struct{}
+
+// Name возвращает имя команды.
+func (a *AliasCommand) Name() string {
+	return "alias"
+}
+
+// Exec выполняет команду alias с переданными аргументами.
+// Без аргументов (или с -p) выводит все псевдонимы в виде,
+// пригодном для повторного ввода. Аргумент name=value определяет
+// псевдоним, аргумент name — выводит его значение.
+//
+// Примеры:
+//
+//	alias ll='ls -la'  → определить псевдоним ll
+//	alias ll           → alias ll='ls -la'
+func (a *AliasCommand) Exec(args []string, ctx *CommandContext) error {
+	if len(args) > 0 && args[0] == "-p" {
+		args = args[1:]
+	}
+
+	if len(args) == 0 {
+		for _, name := range ctx.Aliases.Names() {
+			if err := a.print(ctx, name); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var errs []error
+	for _, arg := range args {
+		name, value, isDefinition := strings.Cut(arg, "=")
+		if !isDefinition {
+			if _, ok := ctx.Aliases.Get(name); !ok {
+				errs = append(errs, fmt.Errorf("alias: %s: не найден", name))
+				continue
+			}
+			if err := a.print(ctx, name); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !validAliasName(name) {
+			errs = append(errs, fmt.Errorf("alias: '%s': недопустимое имя псевдонима", name))
+			continue
+		}
+		ctx.Aliases.Set(name, value)
+	}
+
+	return reportErrors(ctx, errs)
+}
+
+func (a *AliasCommand) print(ctx *CommandContext, name string) error {
+	value, _ := ctx.Aliases.Get(name)
+	_, err := fmt.Fprintf(ctx.Stdout, "alias %s=%s\n", name, singleQuote(value))
+	return err
+}
+
+// Help возвращает справку по команде alias.
+func (a *AliasCommand) Help() string {
+	return `NAME
+    alias - определяет или выводит псевдонимы команд
+
+SYNOPSIS
+    alias [-p] [NAME[=VALUE]]...
+
+DESCRIPTION
+    Без аргументов выводит все псевдонимы в виде alias NAME='VALUE'.
+    NAME=VALUE определяет псевдоним: первое слово команды NAME
+    заменяется на VALUE. Если VALUE заканчивается пробелом,
+    следующее слово команды тоже проверяется на псевдоним.
+
+OPTIONS
+    -p    вывести все псевдонимы
+
+EXAMPLES
+    alias ll='ls -la'
+        → ll будет раскрываться в ls -la
+
+    alias
+        → alias ll='ls -la'`
+}
+
+// UnaliasCommand реализует встроенную команду "unalias".
+// Она удаляет псевдонимы команд из CommandContext.Aliases.
+type UnaliasCommand struct{}
+
+// Name возвращает имя команды.
+func (u *UnaliasCommand) Name() string {
+	return "unalias"
+}
+
+// Exec выполняет команду unalias с переданными аргументами.
+// Опция -a удаляет все псевдонимы.
+func (u *UnaliasCommand) Exec(args []string, ctx *CommandContext) error {
+	if len(args) > 0 && args[0] == "-a" {
+		ctx.Aliases.Clear()
+		return nil
+	}
+
+	if len(args) == 0 {
+		return reportErrors(ctx, []error{fmt.Errorf("unalias: использование: unalias [-a] name [name ...]")})
+	}
+
+	var errs []error
+	for _, name := range args {
+		if !ctx.Aliases.Remove(name) {
+			errs = append(errs, fmt.Errorf("unalias: %s: не найден", name))
+		}
+	}
+
+	return reportErrors(ctx, errs)
+}
+
+// Help возвращает справку по команде unalias.
+func (u *UnaliasCommand) Help() string {
+	return `NAME
+    unalias - удаляет псевдонимы команд
+
+SYNOPSIS
+    unalias [-a] NAME...
+
+OPTIONS
+    -a    удалить все псевдонимы
+
+EXAMPLES
+    unalias ll
+        → удалить псевдоним ll`
+}
+
+// validAliasName проверяет, что имя псевдонима не содержит символов,
+// которые не могут входить в первое слово команды.
+func validAliasName(name string) bool {
+	return name != "" && !strings.ContainsAny(name, "/$`=\\'\" \t|")
+}
+
+// singleQuote заключает строку в одинарные кавычки.
+func singleQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// reportErrors печатает ошибки в stderr и возвращает их объединение.
+func reportErrors(ctx *CommandContext, errs []error) error {
+	for _, err := range errs {
+		if _, writeErr := fmt.Fprintln(ctx.Stderr, err); writeErr != nil {
+			return writeErr
+		}
+	}
+	return errors.Join(errs...)
+}
+
+var (
+	_ BuiltinCommand = (*AliasCommand)(nil)
+	_ BuiltinCommand = (*UnaliasCommand)(nil)
+)
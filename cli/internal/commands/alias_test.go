@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
+)
+
+func runBuiltin(cmd BuiltinCommand, args ...string) (string, string, error) {
+	return runAliasBuiltin(cmd, session.NewAliases(), args...)
+}
+
+// runAliasBuiltin выполняет cmd с таблицей псевдонимов aliases.
+func runAliasBuiltin(cmd BuiltinCommand, aliases *session.Aliases, args ...string) (string, string, error) {
+	var stdout, stderr bytes.Buffer
+	ctx := &CommandContext{
+		Stdin:   strings.NewReader(""),
+		Stdout:  &stdout,
+		Stderr:  &stderr,
+		Vars:    session.NewVariables(nil),
+		Aliases: aliases,
+		Dir:     ".",
+	}
+	err := cmd.Exec(args, ctx)
+	return stdout.String(), stderr.String(), err
+}
+
+func TestAliasCommand_DefineAndPrint(t *testing.T) {
+	aliases := session.NewAliases()
+	cmd := &AliasCommand{}
+
+	if _, _, err := runAliasBuiltin(cmd, aliases, "ll=ls -la", "say=echo it's"); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+
+	if value, _ := aliases.Get("ll"); value != "ls -la" {
+		t.Fatalf("псевдоним ll определён неверно: %q", value)
+	}
+
+	out, _, _ := runAliasBuiltin(cmd, aliases)
+	expected := "alias ll='ls -la'\nalias say='echo it'\\''s'\n"
+	if out != expected {
+		t.Fatalf("ожидалось %q, получено %q", expected, out)
+	}
+
+	out, _, _ = runAliasBuiltin(cmd, aliases, "ll")
+	if out != "alias ll='ls -la'\n" {
+		t.Fatalf("неожиданный вывод: %q", out)
+	}
+}
+
+func TestAliasCommand_Errors(t *testing.T) {
+	_, stderr, err := runBuiltin(&AliasCommand{}, "missing", "bad/name=x")
+	if err == nil {
+		t.Fatalf("ожидалась ошибка")
+	}
+	if !strings.Contains(stderr, "missing") || !strings.Contains(stderr, "bad/name") {
+		t.Fatalf("обе ошибки должны попасть в stderr: %q", stderr)
+	}
+}
+
+func TestUnaliasCommand(t *testing.T) {
+	aliases := session.NewAliases()
+	aliases.Set("ll", "ls -la")
+	aliases.Set("gs", "git status")
+	cmd := &UnaliasCommand{}
+
+	if _, _, err := runAliasBuiltin(cmd, aliases, "ll"); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if _, ok := aliases.Get("ll"); ok {
+		t.Fatalf("псевдоним ll должен быть удалён")
+	}
+
+	if _, _, err := runAliasBuiltin(cmd, aliases, "ll"); err == nil {
+		t.Fatalf("ожидалась ошибка при удалении несуществующего псевдонима")
+	}
+
+	if _, _, err := runAliasBuiltin(cmd, aliases, "-a"); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if len(aliases.Names()) != 0 {
+		t.Fatalf("unalias -a должен удалить все псевдонимы")
+	}
+}
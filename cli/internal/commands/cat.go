@@ -1,11 +1,14 @@
 package commands
 
 import (
-	"bufio"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"strings"
+
+	customErrors "github.com/ggerlakh/software-design-mhs-itmo/cli/internal/errors"
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/linereader"
 )
 
 // CatCommand реализует встроенную команду "cat".
@@ -18,129 +21,288 @@ func (c *CatCommand) Name() string {
 }
 
 // Exec выполняет команду cat с переданными аргументами.
-// Поддерживаются базовые опции:
+// Поддерживаются опции:
 //   - -n — нумеровать все строки
 //   - -b — нумеровать только непустые строки (перекрывает -n)
 //   - -s — убрать повторяющиеся пустые строки
 //   - -E — показывать $ в конце строки
 //   - -T — заменять табуляции на ^I
+//   - -v — показывать непечатаемые символы в виде ^X и M-X
+//   - -A — то же, что -vET; -e — то же, что -vE; -t — то же, что -vT
+//
+// Короткие опции можно объединять (-nE), опции и файлы можно перемешивать,
+// "--" завершает список опций. Неизвестная опция — ошибка с кодом возврата 1.
 //
 // Если не указано ни одного файла, читается stdin.
 // Если указан "-" как имя файла, также читается stdin.
+// Без опций форматирования содержимое копируется байт в байт,
+// поэтому двоичные файлы выводятся без изменений. С опциями длина строк
+// не ограничена, CRLF сохраняется, а к последней строке без "\n"
+// перевод строки не добавляется.
+//
+// Если файл не удалось прочитать, ошибка выводится в stderr, остальные
+// файлы выводятся, а команда возвращает код 1.
 func (c *CatCommand) Exec(args []string, ctx *CommandContext) error {
-	var (
-		numberAll      bool
-		numberNonEmpty bool
-		squeezeBlank   bool
-		showEnds       bool
-		showTabs       bool
-		files          []string
-	)
-
-	// Разбор опций
-	for len(args) > 0 && strings.HasPrefix(args[0], "-") && len(args[0]) > 1 {
-		switch args[0] {
-		case "-n", "--number":
-			numberAll = true
-		case "-b", "--number-nonblank":
-			numberNonEmpty = true
-		case "-s", "--squeeze-blank":
-			squeezeBlank = true
-		case "-E", "--show-ends":
-			showEnds = true
-		case "-T", "--show-tabs":
-			showTabs = true
-		case "--help":
-			if _, err := fmt.Fprintln(ctx.Stdout, c.Help()); err != nil {
-				return err
-			}
-			return nil
-		default:
-			// Любая неизвестная опция считаем файлом
-			files = append(files, args[0])
+	format, files, help, err := parseCatArgs(args)
+	if err != nil {
+		if _, writeErr := fmt.Fprintf(ctx.Stderr, "%v\nПопробуйте 'cat --help' для получения справки.\n", err); writeErr != nil {
+			return writeErr
 		}
-		args = args[1:]
+		return &customErrors.ExitStatusError{Code: 1}
+	}
+	if help {
+		_, err := fmt.Fprintln(ctx.Stdout, c.Help())
+		return err
 	}
 
-	// Остаток аргументов считаем файлами
-	if len(args) > 0 {
-		files = append(files, args...)
-	} else {
+	if len(files) == 0 {
 		files = []string{"-"}
 	}
 
-	lineNum := 1
-	var prevBlank bool
-
+	failed := false
 	for _, fname := range files {
-		var reader io.Reader
-
-		if fname == "-" {
-			reader = ctx.Stdin
-		} else {
-			//nolint:gosec // открываем файлы, как делает обычный cat, пользователь сам контролирует доступ
-			file, err := os.Open(fname)
-			if err != nil {
-				return fmt.Errorf("cat: %v", err)
+		readErr, err := catFile(fname, format, ctx)
+		if err != nil {
+			return err
+		}
+		if readErr != nil {
+			failed = true
+			if _, err := fmt.Fprintf(ctx.Stderr, "cat: %s: %v\n", fname, readErr); err != nil {
+				return err
 			}
-			defer func(f *os.File) {
-				if err := f.Close(); err != nil {
-					if _, writeErr := fmt.Fprintf(ctx.Stderr, "ошибка при закрытии файла %s: %v\n", f.Name(), err); writeErr != nil {
-						// Игнорируем ошибку записи в stderr
-						_ = writeErr
-					}
-				}
-			}(file)
-			reader = file
 		}
+	}
 
-		scanner := bufio.NewScanner(reader)
-		for scanner.Scan() {
-			line := scanner.Text()
-			isBlank := len(line) == 0
+	if failed {
+		return &customErrors.ExitStatusError{Code: 1}
+	}
+	return nil
+}
 
-			if squeezeBlank && isBlank && prevBlank {
-				continue
-			}
-			prevBlank = isBlank
+// parseCatArgs разбирает аргументы cat. Возвращает параметры вывода,
+// список файлов и признак --help.
+func parseCatArgs(args []string) (*catFormatter, []string, bool, error) {
+	format := &catFormatter{}
+	var files []string
 
-			// Подстановка опций
-			if showTabs {
-				line = strings.ReplaceAll(line, "\t", "^I")
+	for i, arg := range args {
+		switch {
+		case arg == "--":
+			return format, append(files, args[i+1:]...), false, nil
+		case arg == "--help":
+			return format, nil, true, nil
+		case strings.HasPrefix(arg, "--"):
+			if !format.setOption(arg[2:]) {
+				return nil, nil, false, fmt.Errorf("cat: нераспознанная опция '%s'", arg)
 			}
-			if showEnds {
-				line += "$"
+		case strings.HasPrefix(arg, "-") && arg != "-":
+			for _, name := range arg[1:] {
+				if !format.setOption(string(name)) {
+					return nil, nil, false, fmt.Errorf("cat: неверная опция -- '%c'", name)
+				}
 			}
+		default:
+			files = append(files, arg)
+		}
+	}
+	return format, files, false, nil
+}
 
-			if numberNonEmpty {
-				if !isBlank {
-					if _, err := fmt.Fprintf(ctx.Stdout, "%6d\t%s\n", lineNum, line); err != nil {
-						return err
-					}
-					lineNum++
-				} else {
-					if _, err := fmt.Fprintln(ctx.Stdout); err != nil {
-						return err
-					}
-				}
-			} else if numberAll {
-				if _, err := fmt.Fprintf(ctx.Stdout, "%6d\t%s\n", lineNum, line); err != nil {
-					return err
+// catFile выводит файл fname ("-" — stdin) через format.
+// Файл закрывается сразу после вывода. Возвращает ошибку открытия
+// или чтения файла и, отдельно, ошибку записи в stdout.
+func catFile(fname string, format *catFormatter, ctx *CommandContext) (error, error) {
+	var reader io.Reader = ctx.Stdin
+	if fname != "-" {
+		//nolint:gosec // открываем файлы, как делает обычный cat, пользователь сам контролирует доступ
+		file, err := os.Open(ctx.ResolvePath(fname))
+		if err != nil {
+			return fileError(err), nil
+		}
+		defer func() {
+			if err := file.Close(); err != nil {
+				if _, writeErr := fmt.Fprintf(ctx.Stderr, "ошибка при закрытии файла %s: %v\n", fname, err); writeErr != nil {
+					// Игнорируем ошибку записи в stderr
+					_ = writeErr
 				}
-				lineNum++
-			} else {
-				if _, err := fmt.Fprintln(ctx.Stdout, line); err != nil {
+			}
+		}()
+		reader = file
+	}
+
+	source := &catReader{r: reader}
+	var err error
+	if format.plain() {
+		_, err = io.Copy(ctx.Stdout, source)
+	} else {
+		err = format.copy(ctx.Stdout, source)
+	}
+
+	if source.err != nil {
+		return fileError(source.err), nil
+	}
+	return nil, err
+}
+
+// catReader запоминает ошибку чтения, чтобы отличить её от ошибки записи.
+type catReader struct {
+	r   io.Reader
+	err error
+}
+
+func (c *catReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	if err != nil && !errors.Is(err, io.EOF) {
+		c.err = err
+	}
+	return n, err
+}
+
+// catFormatter выводит строки с нумерацией и заменами символов.
+// Состояние сохраняется между файлами: нумерация продолжается,
+// а строка без перевода строки в конце файла продолжается первой
+// строкой следующего файла, как в GNU cat.
+type catFormatter struct {
+	numberAll       bool
+	numberNonEmpty  bool
+	squeezeBlank    bool
+	showEnds        bool
+	showTabs        bool
+	showNonprinting bool
+
+	lineNum   int    // номер последней пронумерованной строки
+	prevBlank bool   // предыдущая строка была пустой
+	midLine   bool   // предыдущий вывод закончился не переводом строки
+	buf       []byte // буфер для сборки выводимой строки
+}
+
+// setOption включает опцию с коротким или длинным именем name.
+// Возвращает false для неизвестной опции.
+func (f *catFormatter) setOption(name string) bool {
+	switch name {
+	case "A", "show-all":
+		f.showNonprinting, f.showEnds, f.showTabs = true, true, true
+	case "b", "number-nonblank":
+		f.numberNonEmpty = true
+	case "e":
+		f.showNonprinting, f.showEnds = true, true
+	case "E", "show-ends":
+		f.showEnds = true
+	case "n", "number":
+		f.numberAll = true
+	case "s", "squeeze-blank":
+		f.squeezeBlank = true
+	case "t":
+		f.showNonprinting, f.showTabs = true, true
+	case "T", "show-tabs":
+		f.showTabs = true
+	case "u":
+		// Вывод и так не буферизуется; опция принимается для совместимости
+	case "v", "show-nonprinting":
+		f.showNonprinting = true
+	default:
+		return false
+	}
+	return true
+}
+
+// plain сообщает, что не задано ни одной опции форматирования
+// и содержимое можно копировать без разбора на строки.
+func (f *catFormatter) plain() bool {
+	return !f.numberAll && !f.numberNonEmpty && !f.squeezeBlank &&
+		!f.showEnds && !f.showTabs && !f.showNonprinting
+}
+
+// copy выводит содержимое r в w. Байты строк сохраняются без изменений,
+// кроме явно запрошенных опциями замен. При ошибке чтения строка,
+// прочитанная до ошибки, всё равно выводится.
+func (f *catFormatter) copy(w io.Writer, r io.Reader) error {
+	lines := linereader.New(r)
+	for {
+		line, readErr := lines.ReadLine()
+		if errors.Is(readErr, io.EOF) {
+			return nil
+		}
+
+		if len(line) > 0 {
+			if out, ok := f.format(line); ok {
+				if _, err := w.Write(out); err != nil {
 					return err
 				}
 			}
 		}
+		if readErr != nil {
+			return readErr
+		}
+	}
+}
 
-		if err := scanner.Err(); err != nil {
-			return fmt.Errorf("cat: %v", err)
+// format возвращает строку line в том виде, в котором её нужно вывести.
+// false означает, что строку нужно пропустить (-s).
+func (f *catFormatter) format(line []byte) ([]byte, bool) {
+	text := linereader.TrimNewline(line)
+	newline := len(text) < len(line)
+	f.buf = f.buf[:0]
+
+	// Продолжение строки предыдущего файла не нумеруется и не сжимается
+	if !f.midLine {
+		blank := len(text) == 0
+		if f.squeezeBlank && blank && f.prevBlank {
+			return nil, false
+		}
+		f.prevBlank = blank
+
+		if f.numberAll && !f.numberNonEmpty || f.numberNonEmpty && !blank {
+			f.lineNum++
+			f.buf = fmt.Appendf(f.buf, "%6d\t", f.lineNum)
 		}
 	}
+	f.midLine = !newline
 
-	return nil
+	switch {
+	case !f.showTabs && !f.showEnds && !f.showNonprinting:
+		return append(f.buf, line...), true
+	case !f.showTabs && !f.showNonprinting:
+		f.buf = append(f.buf, text...)
+	default:
+		for _, b := range text {
+			f.buf = f.appendByte(f.buf, b)
+		}
+	}
+	if newline {
+		if f.showEnds {
+			f.buf = append(f.buf, '$')
+		}
+		f.buf = append(f.buf, '\n')
+	}
+	return f.buf, true
+}
+
+// appendByte добавляет к buf байт b с учётом -T и -v. С -v управляющие
+// символы выводятся как ^X, DEL — как ^?, а байты старше 127 — с
+// префиксом M- и тем же представлением младших семи бит, как в GNU cat.
+func (f *catFormatter) appendByte(buf []byte, b byte) []byte {
+	if b == '\t' {
+		if f.showTabs {
+			return append(buf, '^', 'I')
+		}
+		return append(buf, b)
+	}
+	if !f.showNonprinting {
+		return append(buf, b)
+	}
+
+	if b >= 128 {
+		buf = append(buf, 'M', '-')
+		b -= 128
+	}
+	switch {
+	case b < 32:
+		return append(buf, '^', b+64)
+	case b == 127:
+		return append(buf, '^', '?')
+	}
+	return append(buf, b)
 }
 
 // Help возвращает справку по команде cat.
@@ -154,19 +316,33 @@ SYNOPSIS
 DESCRIPTION
     Выводит содержимое указанных файлов в стандартный вывод.
     Если файл не указан или указан "-", читается стандартный ввод.
+    Без опций форматирования файлы копируются байт в байт.
+    Короткие опции можно объединять: -nE равносильно -n -E.
 
 OPTIONS
-    -b, --number-nonblank   нумеровать только непустые строки
+    -A, --show-all           то же, что -vET
+    -b, --number-nonblank    нумеровать только непустые строки
+    -e                       то же, что -vE
+    -E, --show-ends          отображать '$' в конце строки
     -n, --number             нумеровать все строки
     -s, --squeeze-blank      подавлять повторяющиеся пустые строки
-    -E, --show-ends          отображать '$' в конце строки
+    -t                       то же, что -vT
     -T, --show-tabs          заменять TAB символом ^I
+    -u                       игнорируется
+    -v, --show-nonprinting   показывать непечатаемые символы как ^X и M-X,
+                             кроме TAB и перевода строки
     --help                   показать эту справку
 
+EXIT STATUS
+    0 — все файлы выведены, 1 — неверная опция или ошибка чтения файла.
+
 EXAMPLES
     cat file.txt
         → вывод содержимого file.txt
 
     cat -n file1.txt file2.txt
-        → вывод содержимого file1.txt и file2.txt с нумерацией строк`
+        → вывод содержимого file1.txt и file2.txt с нумерацией строк
+
+    cat -A script.sh
+        → табуляции как ^I, концы строк как $, CR как ^M`
 }
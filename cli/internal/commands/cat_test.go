@@ -6,6 +6,8 @@ import (
 	"os"
 	"strings"
 	"testing"
+
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
 )
 
 // testExecWithOutput выполняет команду с переданными аргументами и возвращает вывод
@@ -15,7 +17,7 @@ func testExecWithOutput(cmd CommandExecutor, args []string, stdin io.Reader) str
 		Stdin:  stdin,
 		Stdout: &buf,
 		Stderr: os.Stderr,
-		Env:    make(map[string]string),
+		Vars:   session.NewVariables(nil),
 		Dir:    ".",
 	}
 	cmd.Exec(args, ctx)
@@ -89,3 +91,111 @@ func TestCatCommand_Stdin(t *testing.T) {
 		t.Errorf("ожидался вывод stdin, получено %q", out)
 	}
 }
+
+func TestCatCommand_PreservesBytes(t *testing.T) {
+	content := "a\r\nb\x00c\n\xff\xfe\nlast"
+	tmp := t.TempDir() + "/raw.txt"
+	_ = os.WriteFile(tmp, []byte(content), 0o644)
+
+	out := testExecWithOutput(&CatCommand{}, []string{tmp}, nil)
+	if out != content {
+		t.Errorf("ожидалось %q, получено %q", content, out)
+	}
+}
+
+func TestCatCommand_LongLines(t *testing.T) {
+	long := strings.Repeat("{\"key\":\"value\"},", 4*1024*1024/16)
+	content := long + "\n" + long
+
+	out := testExecWithOutput(&CatCommand{}, []string{"-n", "-E"}, strings.NewReader(content))
+	expected := "     1\t" + long + "$\n     2\t" + long
+	if out != expected {
+		t.Errorf("ожидалось %d байт, получено %d", len(expected), len(out))
+	}
+}
+
+func TestCatCommand_ContinuesLineAcrossFiles(t *testing.T) {
+	dir := t.TempDir()
+	_ = os.WriteFile(dir+"/a.txt", []byte("one\ntwo"), 0o644)
+	_ = os.WriteFile(dir+"/b.txt", []byte(" more\nthree\n"), 0o644)
+
+	out := testExecWithOutput(&CatCommand{}, []string{"-n", dir + "/a.txt", dir + "/b.txt"}, nil)
+	expected := "     1\tone\n     2\ttwo more\n     3\tthree\n"
+	if out != expected {
+		t.Errorf("ожидалось %q, получено %q", expected, out)
+	}
+}
+
+func TestCatCommand_ShowNonprinting(t *testing.T) {
+	const input = "a\tb\r\n\x01\x1b\x7f\xc3\xa9\xff\n"
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{"-v", []string{"-v"}, "a\tb^M\n^A^[^?M-CM-)M-^?\n"},
+		{"-A", []string{"-A"}, "a^Ib^M$\n^A^[^?M-CM-)M-^?$\n"},
+		{"-e", []string{"-e"}, "a\tb^M$\n^A^[^?M-CM-)M-^?$\n"},
+		{"-t", []string{"-t"}, "a^Ib^M\n^A^[^?M-CM-)M-^?\n"},
+		{"--show-nonprinting", []string{"--show-nonprinting"}, "a\tb^M\n^A^[^?M-CM-)M-^?\n"},
+		{"объединённые -nE", []string{"-nE"}, "     1\ta\tb\r$\n     2\t\x01\x1b\x7f\xc3\xa9\xff$\n"},
+		{"-u ничего не меняет", []string{"-u"}, input},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, stderr, status := runCommand(t, &CatCommand{}, t.TempDir(), input, tt.args...)
+			if out != tt.expected || status != 0 {
+				t.Fatalf("ожидалось %q, получено %q (код %d, stderr %q)", tt.expected, out, status, stderr)
+			}
+		})
+	}
+}
+
+func TestCatCommand_BinaryPassthrough(t *testing.T) {
+	dir := t.TempDir()
+	content := make([]byte, 256*1024)
+	for i := range content {
+		content[i] = byte(i * 7)
+	}
+	_ = os.WriteFile(dir+"/bin.dat", content, 0o644)
+
+	out, _, status := runCommand(t, &CatCommand{}, dir, "", "bin.dat")
+	if out != string(content) || status != 0 {
+		t.Fatalf("двоичный файл изменён: %d байт вместо %d (код %d)", len(out), len(content), status)
+	}
+}
+
+func TestCatCommand_OptionsAndErrors(t *testing.T) {
+	dir := t.TempDir()
+	_ = os.WriteFile(dir+"/a.txt", []byte("a\n"), 0o644)
+	_ = os.WriteFile(dir+"/-n", []byte("dash\n"), 0o644)
+
+	out, stderr, status := runCommand(t, &CatCommand{}, dir, "", "-x", "a.txt")
+	if out != "" || status != 1 || !strings.Contains(stderr, "неверная опция -- 'x'") {
+		t.Fatalf("ожидалась ошибка неверной опции, получено %q, %q (код %d)", out, stderr, status)
+	}
+
+	_, stderr, status = runCommand(t, &CatCommand{}, dir, "", "--bogus")
+	if status != 1 || !strings.Contains(stderr, "'--bogus'") {
+		t.Fatalf("ожидалась ошибка неизвестной длинной опции: %q (код %d)", stderr, status)
+	}
+
+	// Опции можно указывать после файлов, а "--" завершает их список
+	out, _, _ = runCommand(t, &CatCommand{}, dir, "", "a.txt", "-E", "--", "-n")
+	if expected := "a$\ndash$\n"; out != expected {
+		t.Fatalf("ожидалось %q, получено %q", expected, out)
+	}
+
+	// Недоступный файл не прерывает вывод остальных
+	out, stderr, status = runCommand(t, &CatCommand{}, dir, "", "missing.txt", "a.txt")
+	if out != "a\n" || status != 1 || !strings.Contains(stderr, "cat: missing.txt:") {
+		t.Fatalf("ожидался вывод a.txt и ошибка для missing.txt, получено %q, %q (код %d)", out, stderr, status)
+	}
+
+	_, stderr, status = runCommand(t, &CatCommand{}, dir, "", ".")
+	if status != 1 || !strings.Contains(stderr, "cat: .:") {
+		t.Fatalf("ожидалась ошибка чтения каталога: %q (код %d)", stderr, status)
+	}
+}
@@ -0,0 +1,108 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// CdCommand реализует встроенную команду "cd".
+// Она меняет рабочий каталог сеанса (CommandContext.Dir) и обновляет
+// переменные окружения PWD и OLDPWD. Каталог процесса не меняется,
+// поэтому cd в подоболочке не влияет на родительскую оболочку.
+type CdCommand struct{}
+
+// Name возвращает имя команды.
+func (c *CdCommand) Name() string {
+	return "cd"
+}
+
+// Exec выполняет команду cd с переданными аргументами.
+// Без аргументов переходит в $HOME, аргумент "-" — в $OLDPWD
+// с выводом нового каталога.
+//
+// Примеры:
+//
+//	cd build  → переход в подкаталог build
+//	cd -      → возврат в предыдущий каталог
+func (c *CdCommand) Exec(args []string, ctx *CommandContext) error {
+	if len(args) > 1 {
+		return reportErrors(ctx, []error{fmt.Errorf("cd: слишком много аргументов")})
+	}
+
+	var target string
+	printDir := false
+	switch {
+	case len(args) == 0:
+		home, ok := ctx.Vars.Get("HOME")
+		if !ok {
+			return reportErrors(ctx, []error{fmt.Errorf("cd: не задана переменная HOME")})
+		}
+		target = home
+	case args[0] == "-":
+		oldpwd, ok := ctx.Vars.Get("OLDPWD")
+		if !ok {
+			return reportErrors(ctx, []error{fmt.Errorf("cd: не задана переменная OLDPWD")})
+		}
+		target = oldpwd
+		printDir = true
+	default:
+		target = args[0]
+	}
+
+	dir, err := filepath.Abs(ctx.ResolvePath(target))
+	if err != nil {
+		return reportErrors(ctx, []error{fmt.Errorf("cd: %s: %v", target, err)})
+	}
+
+	info, err := os.Stat(dir)
+	switch {
+	case err != nil:
+		return reportErrors(ctx, []error{fmt.Errorf("cd: %s: нет такого каталога", target)})
+	case !info.IsDir():
+		return reportErrors(ctx, []error{fmt.Errorf("cd: %s: это не каталог", target)})
+	}
+
+	previous, err := filepath.Abs(ctx.Dir)
+	if err != nil {
+		previous = ctx.Dir
+	}
+
+	ctx.Dir = dir
+	if ctx.Vars != nil {
+		// Как и в bash, PWD и OLDPWD только для чтения не мешают смене каталога
+		_ = ctx.Vars.Set("OLDPWD", previous)
+		_ = ctx.Vars.Set("PWD", dir)
+	}
+
+	if printDir {
+		if _, err := fmt.Fprintln(ctx.Stdout, dir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Help возвращает справку по команде cd.
+func (c *CdCommand) Help() string {
+	return `NAME
+    cd - меняет рабочий каталог
+
+SYNOPSIS
+    cd [DIR]
+
+DESCRIPTION
+    Делает DIR рабочим каталогом сеанса и обновляет переменные
+    PWD и OLDPWD. Без аргументов переходит в $HOME.
+    Аргумент "-" означает предыдущий каталог ($OLDPWD); новый
+    каталог при этом выводится. Изменение каталога внутри
+    подоболочки ( ... ) не влияет на родительскую оболочку.
+
+EXAMPLES
+    cd /tmp
+        → рабочий каталог /tmp
+
+    (cd build && pwd); pwd
+        → /home/user/project/build
+        → /home/user/project`
+}
@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
+)
+
+func TestCdCommand_ChangesDir(t *testing.T) {
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, "build"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	ctx := &CommandContext{
+		Stdout: &stdout,
+		Stderr: &stderr,
+		Vars:   session.NewVariables(nil),
+		Dir:    root,
+	}
+
+	cmd := &CdCommand{}
+	if err := cmd.Exec([]string{"build"}, ctx); err != nil {
+		t.Fatalf("неожиданная ошибка: %v (%s)", err, stderr.String())
+	}
+
+	expected := filepath.Join(root, "build")
+	if ctx.Dir != expected {
+		t.Fatalf("ожидалось %q, получено %q", expected, ctx.Dir)
+	}
+	if pwd, oldpwd := variableValue(ctx, "PWD"), variableValue(ctx, "OLDPWD"); pwd != expected || oldpwd != root {
+		t.Fatalf("PWD/OLDPWD обновлены неверно: %q, %q", pwd, oldpwd)
+	}
+
+	if err := cmd.Exec([]string{"-"}, ctx); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if ctx.Dir != root {
+		t.Fatalf("ожидалось %q, получено %q", root, ctx.Dir)
+	}
+	if strings.TrimSpace(stdout.String()) != root {
+		t.Fatalf("cd - должен вывести новый каталог, получено %q", stdout.String())
+	}
+}
+
+func TestCdCommand_Home(t *testing.T) {
+	home := t.TempDir()
+	ctx := &CommandContext{
+		Stdout: &bytes.Buffer{},
+		Stderr: &bytes.Buffer{},
+		Vars:   session.NewVariables(map[string]string{"HOME": home}),
+		Dir:    ".",
+	}
+
+	if err := (&CdCommand{}).Exec(nil, ctx); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if ctx.Dir != home {
+		t.Fatalf("ожидалось %q, получено %q", home, ctx.Dir)
+	}
+}
+
+func TestCdCommand_Errors(t *testing.T) {
+	root := t.TempDir()
+	file := filepath.Join(root, "file")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, arg := range []string{"missing", "file"} {
+		var stderr bytes.Buffer
+		ctx := &CommandContext{Stdout: &bytes.Buffer{}, Stderr: &stderr, Vars: session.NewVariables(nil), Dir: root}
+
+		if err := (&CdCommand{}).Exec([]string{arg}, ctx); err == nil {
+			t.Fatalf("cd %s: ожидалась ошибка", arg)
+		}
+		if ctx.Dir != root {
+			t.Fatalf("cd %s: каталог не должен меняться, получено %q", arg, ctx.Dir)
+		}
+		if !strings.Contains(stderr.String(), arg) {
+			t.Fatalf("cd %s: ошибка должна попасть в stderr: %q", arg, stderr.String())
+		}
+	}
+}
@@ -3,15 +3,61 @@
 // структуру CommandContext для передачи контекста выполнения команд.
 package commands
 
-import "io"
+import (
+	"context"
+	"io"
+	"path/filepath"
+
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
+)
 
 // CommandContext содержит контекст выполнения команды
 type CommandContext struct {
 	Stdin  io.Reader
 	Stdout io.Writer
 	Stderr io.Writer
-	Env    map[string]string
-	Dir    string
+	// Vars — переменные оболочки; в подоболочке это копия. Внешним
+	// программам передаются только экспортируемые переменные
+	// (см. session.Variables.Environ).
+	Vars *session.Variables
+	// Options — опции команды shopt (nullglob, extglob и другие);
+	// в подоболочке это копия.
+	Options *session.Options
+	// SetOptions — опции команды set (errexit, xtrace и другие);
+	// в подоболочке это копия.
+	SetOptions *session.Options
+	// Traps — таблица ловушек команды trap; в подоболочке она пуста.
+	Traps *session.Traps
+	// Aliases — псевдонимы команд alias; в подоболочке это копия.
+	Aliases *session.Aliases
+	Dir     string
+	// Context отменяется, когда команду нужно прервать: по Ctrl-C или когда
+	// следующая команда пайплайна завершилась и вывод больше не нужен.
+	// Может быть nil — тогда команда не прерывается.
+	Context context.Context
+	// Run запускает простую команду так же, как executor: встроенную по
+	// имени или внешнюю программу, — и возвращает её код возврата. Через
+	// него xargs и find -exec вызывают другие команды. Может быть nil —
+	// тогда запускаются только внешние программы.
+	Run func(name string, args []string, ctx *CommandContext) int
+}
+
+// Done возвращает канал, который закрывается при отмене команды.
+// Без Context возвращается nil: чтение из такого канала блокируется навсегда.
+func (c *CommandContext) Done() <-chan struct{} {
+	if c.Context == nil {
+		return nil
+	}
+	return c.Context.Done()
+}
+
+// ResolvePath возвращает путь name относительно рабочего каталога Dir.
+// Абсолютные пути и пустой Dir оставляют name без изменений.
+func (c *CommandContext) ResolvePath(name string) string {
+	if c.Dir == "" || filepath.IsAbs(name) {
+		return name
+	}
+	return filepath.Join(c.Dir, name)
 }
 
 // CommandExecutor определяет интерфейс для выполнения команд.
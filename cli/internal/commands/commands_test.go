@@ -5,6 +5,8 @@ import (
 	"io"
 	"os"
 	"testing"
+
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
 )
 
 // TestCommandContext создает тестовый контекст команды
@@ -18,7 +20,7 @@ func TestCommandContext_Creation(t *testing.T) {
 		Stdin:  os.Stdin,
 		Stdout: os.Stdout,
 		Stderr: os.Stderr,
-		Env:    env,
+		Vars:   session.NewVariables(env),
 		Dir:    "/tmp",
 	}
 
@@ -38,11 +40,11 @@ func TestCommandContext_Creation(t *testing.T) {
 		t.Errorf("Dir не установлен правильно, ожидалось: /tmp, получено: %s", ctx.Dir)
 	}
 
-	if ctx.Env["HOME"] != "/home/user" {
+	if home, _ := ctx.Vars.Get("HOME"); home != "/home/user" {
 		t.Error("переменная окружения HOME не установлена правильно")
 	}
 
-	if ctx.Env["PATH"] != "/usr/bin:/bin" {
+	if path, _ := ctx.Vars.Get("PATH"); path != "/usr/bin:/bin" {
 		t.Error("переменная окружения PATH не установлена правильно")
 	}
 }
@@ -52,16 +54,16 @@ func TestCommandContext_EmptyEnvironment(t *testing.T) {
 		Stdin:  os.Stdin,
 		Stdout: os.Stdout,
 		Stderr: os.Stderr,
-		Env:    make(map[string]string),
+		Vars:   session.NewVariables(nil),
 		Dir:    ".",
 	}
 
-	if ctx.Env == nil {
-		t.Error("Env не должен быть nil")
+	if ctx.Vars == nil {
+		t.Error("Vars не должен быть nil")
 	}
 
-	if len(ctx.Env) != 0 {
-		t.Errorf("Env должен быть пустым, получено: %d элементов", len(ctx.Env))
+	if names := ctx.Vars.Names(); len(names) != 0 {
+		t.Errorf("Vars должен быть пустым, получено: %d элементов", len(names))
 	}
 }
 
@@ -74,7 +76,7 @@ func TestCommandContext_WithBuffers(t *testing.T) {
 		Stdin:  &stdinBuf,
 		Stdout: &stdoutBuf,
 		Stderr: &stderrBuf,
-		Env:    make(map[string]string),
+		Vars:   session.NewVariables(nil),
 		Dir:    ".",
 	}
 
@@ -110,31 +112,39 @@ func TestCommandContext_EnvironmentManipulation(t *testing.T) {
 		Stdin:  os.Stdin,
 		Stdout: os.Stdout,
 		Stderr: os.Stderr,
-		Env:    make(map[string]string),
+		Vars:   session.NewVariables(nil),
 		Dir:    ".",
 	}
 
-	// Добавляем переменные окружения
-	ctx.Env["TEST_VAR"] = "test_value"
-	ctx.Env["ANOTHER_VAR"] = "another_value"
+	// Добавляем переменные
+	if err := ctx.Vars.Set("TEST_VAR", "test_value"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ctx.Vars.Set("ANOTHER_VAR", "another_value"); err != nil {
+		t.Fatal(err)
+	}
 
-	if ctx.Env["TEST_VAR"] != "test_value" {
+	if value, _ := ctx.Vars.Get("TEST_VAR"); value != "test_value" {
 		t.Error("переменная TEST_VAR не установлена правильно")
 	}
 
-	if ctx.Env["ANOTHER_VAR"] != "another_value" {
+	if value, _ := ctx.Vars.Get("ANOTHER_VAR"); value != "another_value" {
 		t.Error("переменная ANOTHER_VAR не установлена правильно")
 	}
 
 	// Изменяем переменную
-	ctx.Env["TEST_VAR"] = "modified_value"
-	if ctx.Env["TEST_VAR"] != "modified_value" {
+	if err := ctx.Vars.Set("TEST_VAR", "modified_value"); err != nil {
+		t.Fatal(err)
+	}
+	if value, _ := ctx.Vars.Get("TEST_VAR"); value != "modified_value" {
 		t.Error("переменная TEST_VAR не изменена правильно")
 	}
 
 	// Удаляем переменную
-	delete(ctx.Env, "ANOTHER_VAR")
-	if _, exists := ctx.Env["ANOTHER_VAR"]; exists {
+	if err := ctx.Vars.Unset("ANOTHER_VAR"); err != nil {
+		t.Fatal(err)
+	}
+	if _, exists := ctx.Vars.Get("ANOTHER_VAR"); exists {
 		t.Error("переменная ANOTHER_VAR не удалена")
 	}
 }
@@ -167,7 +177,7 @@ func TestCommandExecutor_Interface(t *testing.T) {
 		Stdin:  os.Stdin,
 		Stdout: &output,
 		Stderr: os.Stderr,
-		Env:    make(map[string]string),
+		Vars:   session.NewVariables(nil),
 		Dir:    ".",
 	}
 
@@ -197,7 +207,7 @@ func TestCommandExecutor_WithError(t *testing.T) {
 		Stdin:  os.Stdin,
 		Stdout: &output,
 		Stderr: &output,
-		Env:    make(map[string]string),
+		Vars:   session.NewVariables(nil),
 		Dir:    ".",
 	}
 
@@ -262,7 +272,7 @@ func TestBuiltinCommand_Interface(t *testing.T) {
 		Stdin:  os.Stdin,
 		Stdout: &output,
 		Stderr: os.Stderr,
-		Env:    make(map[string]string),
+		Vars:   session.NewVariables(nil),
 		Dir:    ".",
 	}
 
@@ -296,7 +306,7 @@ func TestBuiltinCommand_EmptyArgs(t *testing.T) {
 		Stdin:  os.Stdin,
 		Stdout: &output,
 		Stderr: os.Stderr,
-		Env:    make(map[string]string),
+		Vars:   session.NewVariables(nil),
 		Dir:    ".",
 	}
 
@@ -338,7 +348,7 @@ func TestBuiltinCommand_MultipleArgs(t *testing.T) {
 		Stdin:  os.Stdin,
 		Stdout: &output,
 		Stderr: os.Stderr,
-		Env:    make(map[string]string),
+		Vars:   session.NewVariables(nil),
 		Dir:    ".",
 	}
 
@@ -366,7 +376,7 @@ func TestCommandContext_IOOperations(t *testing.T) {
 		Stdin:  &stdinBuf,
 		Stdout: &stdoutBuf,
 		Stderr: &stderrBuf,
-		Env:    make(map[string]string),
+		Vars:   session.NewVariables(nil),
 		Dir:    ".",
 	}
 
@@ -0,0 +1,378 @@
+package commands
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	customErrors "github.com/ggerlakh/software-design-mhs-itmo/cli/internal/errors"
+)
+
+// CpCommand реализует встроенную команду "cp".
+// Она копирует файлы и каталоги.
+type CpCommand struct{}
+
+// cpOptions — параметры копирования. Используются также mv при
+// перемещении между файловыми системами.
+type cpOptions struct {
+	command   string // имя команды для сообщений
+	recursive bool   // -r, -R: копировать каталоги рекурсивно
+	preserve  bool   // -p: сохранять права и время изменения
+	force     bool   // -f: удалять целевой файл, который не удалось открыть
+	noClobber bool   // -n: не перезаписывать существующие файлы
+	verbose   bool   // -v: сообщать о каждом файле
+}
+
+// Name возвращает имя команды.
+func (c *CpCommand) Name() string {
+	return "cp"
+}
+
+// Exec выполняет команду cp.
+//
+// Синтаксис:
+//
+//	cp [-r] [-p] [-f | -n] [-v] SOURCE DEST
+//	cp [-r] [-p] [-f | -n] [-v] SOURCE... DIRECTORY
+//
+// Если DEST — существующий каталог, файлы копируются в него под своими
+// именами. Символьные ссылки в аргументах раскрываются, а внутри
+// копируемых каталогов копируются как ссылки.
+//
+// Примеры:
+//
+//	cp a.txt b.txt                → копия файла
+//	cp -r src backup              → копия каталога
+//	cp -p *.conf /etc/app/        → с сохранением прав и времени
+func (c *CpCommand) Exec(args []string, ctx *CommandContext) error {
+	opts, sources, dest, err := parseCpArgs(args)
+	if err != nil {
+		if writeErr := warnf(ctx, "%v\nПопробуйте 'cp --help' для получения справки.", err); writeErr != nil {
+			return writeErr
+		}
+		return &customErrors.ExitStatusError{Code: 1}
+	}
+
+	targets, err := transferTargets(ctx, "cp", sources, dest, false)
+	if err != nil {
+		return err
+	}
+	if targets == nil {
+		return &customErrors.ExitStatusError{Code: 1}
+	}
+
+	failed := false
+	for i, source := range sources {
+		ok, err := opts.copyTop(ctx, source, targets[i])
+		if err != nil {
+			return err
+		}
+		failed = failed || !ok
+	}
+	if failed {
+		return &customErrors.ExitStatusError{Code: 1}
+	}
+	return nil
+}
+
+// parseCpArgs разбирает аргументы cp и возвращает источники и цель.
+func parseCpArgs(args []string) (*cpOptions, []string, string, error) {
+	fs := flag.NewFlagSet("cp", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	opts := &cpOptions{command: "cp"}
+	var archive bool
+	for _, names := range []struct {
+		names []string
+		value *bool
+		usage string
+	}{
+		{[]string{"r", "R", "recursive"}, &opts.recursive, "копировать каталоги рекурсивно"},
+		{[]string{"p"}, &opts.preserve, "сохранять права и время изменения"},
+		{[]string{"a", "archive"}, &archive, "то же, что -rp"},
+		{[]string{"f", "force"}, &opts.force, "перезаписывать недоступные для записи файлы"},
+		{[]string{"n", "no-clobber"}, &opts.noClobber, "не перезаписывать существующие файлы"},
+		{[]string{"v", "verbose"}, &opts.verbose, "сообщать о каждом файле"},
+	} {
+		for _, name := range names.names {
+			fs.BoolVar(names.value, name, false, names.usage)
+		}
+	}
+
+	if err := parseOptions(fs, splitShortFlags(args, "")); err != nil {
+		return nil, nil, "", err
+	}
+	if archive {
+		opts.recursive, opts.preserve = true, true
+	}
+	sources, dest, err := transferOperands("cp", fs.Args())
+	return opts, sources, dest, err
+}
+
+// transferOperands делит операнды cp, mv и ln на источники и цель.
+func transferOperands(command string, operands []string) ([]string, string, error) {
+	switch len(operands) {
+	case 0:
+		return nil, "", fmt.Errorf("%s: пропущен операнд, задающий файл", command)
+	case 1:
+		return nil, "", fmt.Errorf("%s: после '%s' пропущен операнд, задающий целевой файл", command, operands[0])
+	}
+	last := len(operands) - 1
+	return operands[:last], operands[last], nil
+}
+
+// transfer — пара путей источника и цели: полный путь и имя для сообщений.
+type transfer struct {
+	path, name string
+}
+
+// transferTargets определяет цели копирования, перемещения или ссылок
+// для sources в dest: если dest — каталог, файлы попадают в него под
+// своими именами. Несколько источников требуют каталога. С noDereference
+// символьная ссылка на каталог считается обычным файлом (ln -n). При
+// ошибке печатается сообщение и возвращается nil.
+func transferTargets(ctx *CommandContext, command string, sources []string, dest string, noDereference bool) ([]transfer, error) {
+	destPath := ctx.ResolvePath(dest)
+	stat := os.Stat
+	if noDereference {
+		stat = os.Lstat
+	}
+	info, err := stat(destPath)
+	intoDir := err == nil && info.IsDir()
+	if len(sources) > 1 && !intoDir {
+		return nil, warnf(ctx, "%s: целевой объект '%s' не является каталогом", command, dest)
+	}
+
+	targets := make([]transfer, len(sources))
+	for i, source := range sources {
+		targets[i] = transfer{path: destPath, name: dest}
+		if intoDir {
+			base := filepath.Base(filepath.Clean(source))
+			targets[i] = transfer{
+				path: filepath.Join(destPath, base),
+				name: strings.TrimSuffix(dest, "/") + "/" + base,
+			}
+		}
+	}
+	return targets, nil
+}
+
+// copyTop копирует файл или каталог из аргументов: символьная ссылка
+// раскрывается, если копирование не рекурсивное.
+func (o *cpOptions) copyTop(ctx *CommandContext, source string, target transfer) (bool, error) {
+	path := ctx.ResolvePath(source)
+	stat := os.Stat
+	if o.recursive {
+		stat = os.Lstat
+	}
+	info, err := stat(path)
+	if err != nil {
+		return false, warnf(ctx, "%s: не удалось выполнить stat для '%s': %v", o.command, source, fileError(err))
+	}
+	return o.copy(ctx, transfer{path: path, name: source}, info, target)
+}
+
+// copy копирует source со сведениями info в target. Возвращает false,
+// если скопировать не удалось (сообщение уже напечатано).
+func (o *cpOptions) copy(ctx *CommandContext, source transfer, info fs.FileInfo, target transfer) (bool, error) {
+	targetInfo, err := os.Lstat(target.path)
+	exists := err == nil
+	same := exists && os.SameFile(info, targetInfo)
+	// Цель может быть символьной ссылкой на источник
+	if resolved, err := os.Stat(target.path); err == nil && os.SameFile(info, resolved) {
+		same = true
+	}
+	if same {
+		return false, warnf(ctx, "%s: '%s' и '%s' — один и тот же файл", o.command, source.name, target.name)
+	}
+	if exists && o.noClobber && !(info.IsDir() && targetInfo.IsDir()) {
+		return true, nil
+	}
+
+	switch mode := info.Mode(); {
+	case mode.IsDir():
+		return o.copyDir(ctx, source, info, target, targetInfo)
+	case mode&fs.ModeSymlink != 0:
+		err = o.copySymlink(source.path, target.path, exists)
+	case mode.IsRegular():
+		err = o.copyFile(source.path, info, target.path)
+	default:
+		return false, warnf(ctx, "%s: невозможно скопировать специальный файл '%s'", o.command, source.name)
+	}
+	if err != nil {
+		return false, warnf(ctx, "%s: невозможно создать '%s': %v", o.command, target.name, fileError(err))
+	}
+	return true, o.report(ctx, source, target)
+}
+
+// copyDir рекурсивно копирует каталог. Каталог нельзя скопировать внутрь
+// самого себя.
+func (o *cpOptions) copyDir(ctx *CommandContext, source transfer, info fs.FileInfo, target transfer, targetInfo fs.FileInfo) (bool, error) {
+	if !o.recursive {
+		return false, warnf(ctx, "%s: не указан -r; пропускается каталог '%s'", o.command, source.name)
+	}
+	sourceAbs, err1 := filepath.Abs(source.path)
+	targetAbs, err2 := filepath.Abs(target.path)
+	if err1 == nil && err2 == nil && isWithin(targetAbs, sourceAbs) {
+		return false, warnf(ctx, "%s: невозможно скопировать каталог '%s' в себя же '%s'", o.command, source.name, target.name)
+	}
+
+	switch {
+	case targetInfo == nil:
+		// Пока содержимое копируется, каталог должен быть доступен для записи
+		if err := os.Mkdir(target.path, info.Mode().Perm()|0o700); err != nil {
+			return false, warnf(ctx, "%s: невозможно создать каталог '%s': %v", o.command, target.name, fileError(err))
+		}
+		if err := o.report(ctx, source, target); err != nil {
+			return false, err
+		}
+	case !targetInfo.IsDir():
+		return false, warnf(ctx, "%s: невозможно перезаписать не каталог '%s' каталогом '%s'", o.command, target.name, source.name)
+	}
+
+	children, err := os.ReadDir(source.path)
+	if err != nil {
+		return false, warnf(ctx, "%s: невозможно открыть каталог '%s': %v", o.command, source.name, fileError(err))
+	}
+	ok := true
+	for _, child := range children {
+		childInfo, err := child.Info()
+		if err != nil {
+			continue
+		}
+		copied, err := o.copy(ctx,
+			transfer{path: filepath.Join(source.path, child.Name()), name: strings.TrimSuffix(source.name, "/") + "/" + child.Name()},
+			childInfo,
+			transfer{path: filepath.Join(target.path, child.Name()), name: strings.TrimSuffix(target.name, "/") + "/" + child.Name()})
+		if err != nil {
+			return false, err
+		}
+		ok = ok && copied
+	}
+
+	if targetInfo == nil || o.preserve {
+		if err := o.applyAttributes(target.path, info); err != nil {
+			return false, warnf(ctx, "%s: не удалось сохранить атрибуты '%s': %v", o.command, target.name, fileError(err))
+		}
+	}
+	return ok, nil
+}
+
+// copySymlink создаёт в target символьную ссылку с той же целью, что у source.
+func (o *cpOptions) copySymlink(source, target string, exists bool) error {
+	link, err := os.Readlink(source)
+	if err != nil {
+		return err
+	}
+	if exists {
+		if err := os.Remove(target); err != nil {
+			return err
+		}
+	}
+	return os.Symlink(link, target)
+}
+
+// copyFile копирует содержимое обычного файла. Новый файл получает права
+// источника (с учётом umask), существующий сохраняет свои, если не задан -p.
+// С -f целевой файл, который не удалось открыть для записи, удаляется.
+func (o *cpOptions) copyFile(source string, info fs.FileInfo, target string) error {
+	//nolint:gosec // копируем файлы, указанные пользователем
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = in.Close()
+	}()
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	//nolint:gosec // путь назначения указан пользователем
+	out, err := os.OpenFile(target, flags, info.Mode().Perm())
+	if err != nil && o.force {
+		if removeErr := os.Remove(target); removeErr == nil {
+			//nolint:gosec // путь назначения указан пользователем
+			out, err = os.OpenFile(target, flags, info.Mode().Perm())
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	if o.preserve {
+		return o.applyAttributes(target, info)
+	}
+	return nil
+}
+
+// applyAttributes устанавливает права источника, а с -p — и время изменения.
+func (o *cpOptions) applyAttributes(path string, info fs.FileInfo) error {
+	if err := os.Chmod(path, info.Mode()&(fs.ModePerm|fs.ModeSetuid|fs.ModeSetgid|fs.ModeSticky)); err != nil {
+		return err
+	}
+	if o.preserve {
+		return os.Chtimes(path, time.Time{}, info.ModTime())
+	}
+	return nil
+}
+
+// report с -v сообщает о скопированном файле.
+func (o *cpOptions) report(ctx *CommandContext, source, target transfer) error {
+	if !o.verbose {
+		return nil
+	}
+	_, err := fmt.Fprintf(ctx.Stdout, "'%s' -> '%s'\n", source.name, target.name)
+	return err
+}
+
+// Help возвращает справку по команде cp.
+func (c *CpCommand) Help() string {
+	return `NAME
+    cp - копирует файлы и каталоги
+
+SYNOPSIS
+    cp [OPTION]... SOURCE DEST
+    cp [OPTION]... SOURCE... DIRECTORY
+
+DESCRIPTION
+    Копирует SOURCE в DEST или несколько файлов в каталог DIRECTORY.
+    Если DEST — существующий каталог, копия создаётся в нём под именем
+    источника. Каталоги копируются только с -r. Без -r символьные ссылки
+    раскрываются, с -r копируются как ссылки.
+
+OPTIONS
+    -r, -R, --recursive   копировать каталоги рекурсивно
+    -p                    сохранять права (включая setuid, setgid, sticky)
+                          и время изменения
+    -a, --archive         то же, что -rp
+    -f, --force           если целевой файл не открывается для записи,
+                          удалить его и повторить
+    -n, --no-clobber      не перезаписывать существующие файлы
+    -v, --verbose         сообщать о каждом скопированном файле
+
+EXIT STATUS
+    0 — успех, 1 — хотя бы один файл скопировать не удалось.
+
+EXAMPLES
+    cp config.yaml config.yaml.bak
+        → резервная копия файла
+
+    cp -rp assets dist/
+        → каталог assets в dist/assets с правами и временем
+
+    cp -n *.txt archive/
+        → скопировать только отсутствующие в archive файлы`
+}
+
+var _ BuiltinCommand = (*CpCommand)(nil)
@@ -0,0 +1,176 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// readTree возвращает содержимое файлов каталога dir по относительным путям
+// через "/"; каталоги отмечаются "/" в конце, как в makeTree.
+func readTree(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	tree := make(map[string]string)
+	err := filepath.WalkDir(dir, func(path string, entry os.DirEntry, err error) error {
+		if err != nil || path == dir {
+			return err
+		}
+		rel, _ := filepath.Rel(dir, path)
+		rel = filepath.ToSlash(rel)
+		switch {
+		case entry.Type()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			tree[rel] = "-> " + link
+			return err
+		case entry.IsDir():
+			tree[rel+"/"] = ""
+		default:
+			data, err := os.ReadFile(path)
+			tree[rel] = string(data)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tree
+}
+
+func TestCpCommand(t *testing.T) {
+	dir := t.TempDir()
+	makeTree(t, dir, map[string]string{"a.txt": "alpha\n", "b.txt": "beta\n", "out/": ""})
+
+	out, stderr, status := runCommand(t, &CpCommand{}, dir, "", "-v", "a.txt", "copy.txt")
+	if status != 0 || stderr != "" || out != "'a.txt' -> 'copy.txt'\n" {
+		t.Fatalf("неожиданный результат: %d, %q, %q", status, out, stderr)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "copy.txt")); string(data) != "alpha\n" {
+		t.Errorf("ожидалось %q, получено %q", "alpha\n", data)
+	}
+
+	_, stderr, status = runCommand(t, &CpCommand{}, dir, "", "a.txt", "b.txt", "out/")
+	if status != 0 || stderr != "" {
+		t.Fatalf("ожидался код 0, получено %d: %q", status, stderr)
+	}
+	expected := map[string]string{"a.txt": "alpha\n", "b.txt": "beta\n"}
+	if got := readTree(t, filepath.Join(dir, "out")); !equalTrees(got, expected) {
+		t.Errorf("ожидалось %v, получено %v", expected, got)
+	}
+
+	_, stderr, status = runCommand(t, &CpCommand{}, dir, "", "-n", "b.txt", "a.txt")
+	if status != 0 || stderr != "" {
+		t.Errorf("-n не считает существующий файл ошибкой, получено %d: %q", status, stderr)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "a.txt")); string(data) != "alpha\n" {
+		t.Errorf("-n не должен перезаписывать файл, получено %q", data)
+	}
+
+	_, stderr, status = runCommand(t, &CpCommand{}, dir, "", "b.txt", "a.txt")
+	if status != 0 || stderr != "" {
+		t.Fatalf("ожидался код 0, получено %d: %q", status, stderr)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "a.txt")); string(data) != "beta\n" {
+		t.Errorf("ожидалось %q, получено %q", "beta\n", data)
+	}
+}
+
+func TestCpCommand_Errors(t *testing.T) {
+	dir := t.TempDir()
+	makeTree(t, dir, map[string]string{"a.txt": "", "b.txt": "", "tree/x.txt": ""})
+
+	tests := []struct {
+		args    []string
+		message string
+	}{
+		{[]string{"a.txt", "a.txt"}, "cp: 'a.txt' и 'a.txt' — один и тот же файл"},
+		{[]string{"tree", "copy"}, "cp: не указан -r; пропускается каталог 'tree'"},
+		{[]string{"-r", "tree", "tree/sub"}, "cp: невозможно скопировать каталог 'tree' в себя же 'tree/sub'"},
+		{[]string{"a.txt", "b.txt", "c.txt"}, "cp: целевой объект 'c.txt' не является каталогом"},
+		{[]string{"missing", "c.txt"}, "cp: не удалось выполнить stat для 'missing'"},
+		{[]string{"a.txt"}, "cp: после 'a.txt' пропущен операнд"},
+	}
+	for _, tt := range tests {
+		_, stderr, status := runCommand(t, &CpCommand{}, dir, "", tt.args...)
+		if status != 1 || !strings.Contains(stderr, tt.message) {
+			t.Errorf("%v: ожидалась ошибка %q, получено %d: %q", tt.args, tt.message, status, stderr)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "tree", "sub")); !os.IsNotExist(err) {
+		t.Errorf("каталог не должен копироваться в себя")
+	}
+}
+
+func TestCpCommand_Recursive(t *testing.T) {
+	dir := t.TempDir()
+	makeTree(t, dir, map[string]string{"src/a.txt": "a", "src/sub/b.txt": "b", "src/empty/": ""})
+	if err := os.Symlink("a.txt", filepath.Join(dir, "src", "link")); err != nil {
+		t.Skip("символьные ссылки недоступны:", err)
+	}
+
+	_, stderr, status := runCommand(t, &CpCommand{}, dir, "", "-r", "src", "dst")
+	if status != 0 || stderr != "" {
+		t.Fatalf("ожидался код 0, получено %d: %q", status, stderr)
+	}
+	expected := readTree(t, filepath.Join(dir, "src"))
+	if got := readTree(t, filepath.Join(dir, "dst")); !equalTrees(got, expected) {
+		t.Errorf("ожидалось %v, получено %v", expected, got)
+	}
+
+	// Повторное копирование в существующий каталог создаёт dst/src
+	_, stderr, status = runCommand(t, &CpCommand{}, dir, "", "-r", "src", "dst")
+	if status != 0 || stderr != "" {
+		t.Fatalf("ожидался код 0, получено %d: %q", status, stderr)
+	}
+	if got := readTree(t, filepath.Join(dir, "dst", "src")); !equalTrees(got, expected) {
+		t.Errorf("ожидалось %v, получено %v", expected, got)
+	}
+}
+
+func TestCpCommand_Preserve(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "script.sh")
+	_ = os.WriteFile(source, []byte("#!/bin/sh\n"), 0o644)
+	if err := os.Chmod(source, 0o751); err != nil {
+		t.Fatal(err)
+	}
+	stamp := time.Date(2020, 5, 17, 10, 30, 0, 0, time.UTC)
+	if err := os.Chtimes(source, stamp, stamp); err != nil {
+		t.Fatal(err)
+	}
+
+	_, stderr, status := runCommand(t, &CpCommand{}, dir, "", "-p", "script.sh", "kept.sh")
+	if status != 0 {
+		t.Fatalf("ожидался код 0, получено %d: %q", status, stderr)
+	}
+	info, err := os.Stat(filepath.Join(dir, "kept.sh"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.ModTime().Equal(stamp) {
+		t.Errorf("ожидалось время %v, получено %v", stamp, info.ModTime())
+	}
+	if info.Mode().Perm() != 0o751 {
+		t.Errorf("ожидались права 0751, получено %v", info.Mode().Perm())
+	}
+
+	_, _, _ = runCommand(t, &CpCommand{}, dir, "", "script.sh", "fresh.sh")
+	if info, err := os.Stat(filepath.Join(dir, "fresh.sh")); err != nil || info.ModTime().Equal(stamp) {
+		t.Errorf("без -p время изменения не сохраняется: %v", err)
+	}
+}
+
+// equalTrees сравнивает результаты readTree.
+func equalTrees(got, expected map[string]string) bool {
+	if len(got) != len(expected) {
+		return false
+	}
+	for name, content := range expected {
+		if value, ok := got[name]; !ok || value != content {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,374 @@
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	customErrors "github.com/ggerlakh/software-design-mhs-itmo/cli/internal/errors"
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/linereader"
+)
+
+// CutCommand реализует встроенную команду "cut".
+// Она выводит выбранные байты, символы или поля каждой строки.
+type CutCommand struct{}
+
+// cutRange — диапазон позиций lo..hi (с 1, включительно).
+type cutRange struct {
+	lo, hi int
+}
+
+// cutOptions — параметры cut.
+type cutOptions struct {
+	mode            byte // 'b' — байты, 'c' — символы, 'f' — поля
+	ranges          []cutRange
+	complement      bool   // --complement: выводить невыбранные позиции
+	delimiter       string // -d: разделитель полей
+	outputDelimiter string // --output-delimiter
+	outputSet       bool   // --output-delimiter задан явно
+	onlyDelimited   bool   // -s: пропускать строки без разделителя
+}
+
+// Name возвращает имя команды.
+func (c *CutCommand) Name() string {
+	return "cut"
+}
+
+// Exec выполняет команду cut.
+//
+// Синтаксис:
+//
+//	cut -b LIST [--complement] [--output-delimiter=STR] [FILE...]
+//	cut -c LIST [--complement] [--output-delimiter=STR] [FILE...]
+//	cut -f LIST [-d DELIM] [-s] [--complement] [--output-delimiter=STR] [FILE...]
+//
+// LIST — номера через запятую и диапазоны N-M, N- и -M. Позиции
+// выводятся в порядке строки, а не в порядке LIST. Если файлы не указаны
+// или указан "-", читается stdin.
+//
+// Примеры:
+//
+//	cut -d, -f2 data.csv          → второе поле CSV
+//	cut -f1,3- file.tsv           → первое и с третьего по последнее поле
+//	cut -c1-10 file.txt           → первые 10 символов каждой строки
+//	cut -d: -f1 --complement /etc/passwd
+//	                              → все поля, кроме первого
+func (c *CutCommand) Exec(args []string, ctx *CommandContext) error {
+	opts, files, err := parseCutArgs(args)
+	if err != nil {
+		if _, writeErr := fmt.Fprintf(ctx.Stderr, "%v\nПопробуйте 'cut --help' для получения справки.\n", err); writeErr != nil {
+			return writeErr
+		}
+		return &customErrors.ExitStatusError{Code: 1}
+	}
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+
+	failed := false
+	for _, name := range files {
+		reader, closeInput, err := openInput(name, ctx)
+		if err != nil {
+			failed = true
+			if _, writeErr := fmt.Fprintf(ctx.Stderr, "cut: %s: %v\n", name, fileError(err)); writeErr != nil {
+				return writeErr
+			}
+			continue
+		}
+
+		source := &catReader{r: reader}
+		err = opts.copy(ctx.Stdout, source)
+		closeInput()
+		if source.err != nil {
+			failed = true
+			if _, writeErr := fmt.Fprintf(ctx.Stderr, "cut: %s: %v\n", displayName(name), fileError(source.err)); writeErr != nil {
+				return writeErr
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if failed {
+		return &customErrors.ExitStatusError{Code: 1}
+	}
+	return nil
+}
+
+// parseCutArgs разбирает аргументы cut.
+func parseCutArgs(args []string) (*cutOptions, []string, error) {
+	fs := flag.NewFlagSet("cut", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	lists := map[byte]*string{}
+	for _, names := range []struct {
+		mode        byte
+		short, long string
+		usage       string
+	}{
+		{'b', "b", "bytes", "выбрать байты"},
+		{'c', "c", "characters", "выбрать символы"},
+		{'f', "f", "fields", "выбрать поля"},
+	} {
+		list := new(string)
+		fs.StringVar(list, names.short, "", names.usage)
+		fs.StringVar(list, names.long, "", names.usage)
+		lists[names.mode] = list
+	}
+
+	opts := &cutOptions{}
+	fs.StringVar(&opts.delimiter, "d", "\t", "разделитель полей")
+	fs.StringVar(&opts.delimiter, "delimiter", "\t", "разделитель полей")
+	fs.BoolVar(&opts.onlyDelimited, "s", false, "пропускать строки без разделителя")
+	fs.BoolVar(&opts.onlyDelimited, "only-delimited", false, "пропускать строки без разделителя")
+	fs.BoolVar(&opts.complement, "complement", false, "выводить невыбранные позиции")
+	fs.StringVar(&opts.outputDelimiter, "output-delimiter", "", "разделитель в выводе")
+	fs.Bool("n", false, "игнорируется")
+
+	if err := fs.Parse(splitShortFlags(args, "bcfd")); err != nil {
+		return nil, nil, fmt.Errorf("cut: ошибка разбора флагов: %w", err)
+	}
+
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+	for _, mode := range []byte{'b', 'c', 'f'} {
+		long := map[byte]string{'b': "bytes", 'c': "characters", 'f': "fields"}[mode]
+		if !set[string(mode)] && !set[long] {
+			continue
+		}
+		if opts.mode != 0 {
+			return nil, nil, errors.New("cut: можно указать только один тип списка")
+		}
+		opts.mode = mode
+	}
+	if opts.mode == 0 {
+		return nil, nil, errors.New("cut: необходимо указать список байт, символов или полей")
+	}
+
+	delimiterSet := set["d"] || set["delimiter"]
+	if opts.mode != 'f' && (delimiterSet || opts.onlyDelimited) {
+		return nil, nil, errors.New("cut: разделитель и -s можно задавать только при работе с полями")
+	}
+	if utf8.RuneCountInString(opts.delimiter) != 1 {
+		return nil, nil, errors.New("cut: разделитель должен быть одним символом")
+	}
+	opts.outputSet = set["output-delimiter"]
+	if !opts.outputSet && opts.mode == 'f' {
+		opts.outputDelimiter = opts.delimiter
+	}
+
+	ranges, err := parseCutList(*lists[opts.mode])
+	if err != nil {
+		return nil, nil, err
+	}
+	opts.ranges = ranges
+	return opts, fs.Args(), nil
+}
+
+// parseCutList разбирает список позиций вида "1,3-5,7-,-2" и возвращает
+// упорядоченные непересекающиеся диапазоны.
+func parseCutList(list string) ([]cutRange, error) {
+	var ranges []cutRange
+	for _, part := range strings.Split(list, ",") {
+		lo, hi, isRange := strings.Cut(part, "-")
+		r := cutRange{lo: 1, hi: math.MaxInt}
+		var err error
+		switch {
+		case part == "" || part == "-":
+			return nil, fmt.Errorf("cut: неверный список позиций %q", list)
+		case !isRange:
+			r.lo, err = parseCutPosition(lo)
+			r.hi = r.lo
+		default:
+			if lo != "" {
+				r.lo, err = parseCutPosition(lo)
+			}
+			if err == nil && hi != "" {
+				r.hi, err = parseCutPosition(hi)
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		if r.hi < r.lo {
+			return nil, fmt.Errorf("cut: неверный убывающий диапазон %q", part)
+		}
+		ranges = append(ranges, r)
+	}
+
+	slices.SortFunc(ranges, func(a, b cutRange) int {
+		return a.lo - b.lo
+	})
+	merged := ranges[:1]
+	for _, r := range ranges[1:] {
+		last := &merged[len(merged)-1]
+		if last.hi == math.MaxInt || r.lo <= last.hi+1 {
+			last.hi = max(last.hi, r.hi)
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged, nil
+}
+
+// parseCutPosition разбирает номер позиции; позиции нумеруются с 1.
+func parseCutPosition(text string) (int, error) {
+	n, err := strconv.Atoi(text)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("cut: неверный номер позиции %q", text)
+	}
+	if n == 0 {
+		return 0, errors.New("cut: поля и позиции нумеруются с 1")
+	}
+	return n, nil
+}
+
+// selected сообщает, выводится ли позиция pos (с 1).
+func (o *cutOptions) selected(pos int) bool {
+	in := false
+	for _, r := range o.ranges {
+		if r.lo > pos {
+			break
+		}
+		if pos <= r.hi {
+			in = true
+			break
+		}
+	}
+	return in != o.complement
+}
+
+// copy выводит выбранные части каждой строки r. Каждая строка выводится
+// одной записью, поэтому cut можно использовать после tail -f.
+func (o *cutOptions) copy(w io.Writer, r io.Reader) error {
+	lines := linereader.New(r)
+	var buf []byte
+	for {
+		line, err := lines.ReadLine()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		text := linereader.TrimNewline(line)
+		var ok bool
+		if o.mode == 'f' {
+			buf, ok = o.appendFields(buf[:0], text)
+		} else {
+			buf, ok = o.appendPositions(buf[:0], text), true
+		}
+		if !ok {
+			continue
+		}
+		if _, err := w.Write(append(buf, '\n')); err != nil {
+			return err
+		}
+	}
+}
+
+// appendFields добавляет к buf выбранные поля строки. Строка без
+// разделителя выводится целиком, а с -s пропускается: тогда
+// возвращается false.
+func (o *cutOptions) appendFields(buf, line []byte) ([]byte, bool) {
+	delimiter := []byte(o.delimiter)
+	if !bytes.Contains(line, delimiter) {
+		return append(buf, line...), !o.onlyDelimited
+	}
+
+	first := true
+	for i, field := range bytes.Split(line, delimiter) {
+		if !o.selected(i + 1) {
+			continue
+		}
+		if !first {
+			buf = append(buf, o.outputDelimiter...)
+		}
+		buf = append(buf, field...)
+		first = false
+	}
+	return buf, true
+}
+
+// appendPositions добавляет к buf выбранные байты (-b) или символы (-c)
+// строки. С --output-delimiter между несмежными выбранными участками
+// выводится разделитель.
+func (o *cutOptions) appendPositions(buf, line []byte) []byte {
+	written, previous := false, false
+	pos := 0
+	for i := 0; i < len(line); {
+		size := 1
+		if o.mode == 'c' {
+			_, size = utf8.DecodeRune(line[i:])
+		}
+		pos++
+
+		current := o.selected(pos)
+		if current {
+			if o.outputSet && written && !previous {
+				buf = append(buf, o.outputDelimiter...)
+			}
+			buf = append(buf, line[i:i+size]...)
+			written = true
+		}
+		previous = current
+		i += size
+	}
+	return buf
+}
+
+// Help возвращает справку по команде cut.
+func (c *CutCommand) Help() string {
+	return `NAME
+    cut - выводит выбранные части строк
+
+SYNOPSIS
+    cut OPTION... [FILE]...
+
+DESCRIPTION
+    Выводит из каждой строки выбранные байты, символы или поля. Если файл
+    не указан или указан "-", читается стандартный ввод. Нужно указать
+    ровно один из флагов -b, -c или -f.
+
+    LIST — номера через запятую или диапазоны: N, N-M, N- (с N до конца),
+    -M (с первой по M). Нумерация с 1; части выводятся в порядке строки.
+
+OPTIONS
+    -b, --bytes=LIST          выбрать байты
+    -c, --characters=LIST     выбрать символы (UTF-8)
+    -f, --fields=LIST         выбрать поля; строки без разделителя
+                              выводятся целиком
+    -d, --delimiter=DELIM     разделитель полей вместо TAB
+    -s, --only-delimited      пропускать строки без разделителя
+    --complement              выводить все части, кроме выбранных
+    --output-delimiter=STR    разделитель в выводе; по умолчанию для
+                              полей — DELIM, для байт и символов — нет
+    -n                        игнорируется
+
+EXIT STATUS
+    0 — успех, 1 — неверные аргументы или ошибка чтения файла.
+
+EXAMPLES
+    cut -d, -f2 data.csv
+        → второе поле CSV
+
+    cut -d: -f1,7 --output-delimiter=' ' /etc/passwd
+        → имя пользователя и оболочка через пробел
+
+    cut -c-8 file.txt
+        → первые 8 символов каждой строки`
+}
+
+var _ BuiltinCommand = (*CutCommand)(nil)
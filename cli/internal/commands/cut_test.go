@@ -0,0 +1,96 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCutCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		input    string
+		expected string
+	}{
+		{"field", []string{"-d,", "-f2"}, "a,b,c\n1,2,3\n", "b\n2\n"},
+		{"fields list", []string{"-d", ",", "-f", "3,1"}, "a,b,c\n", "a,c\n"},
+		{"open range", []string{"-d,", "-f2-"}, "a,b,c\n", "b,c\n"},
+		{"range to", []string{"-d,", "-f-2"}, "a,b,c\n", "a,b\n"},
+		{"tab default", []string{"-f2"}, "a\tb\tc\n", "b\n"},
+		{"no delimiter", []string{"-d,", "-f2"}, "plain\na,b\n", "plain\nb\n"},
+		{"only delimited", []string{"-s", "-d,", "-f2"}, "plain\na,b\n", "b\n"},
+		{"missing field", []string{"-d,", "-f5"}, "a,b\n", "\n"},
+		{"empty fields", []string{"-d,", "-f2,3"}, "a,,c\n", ",c\n"},
+		{"complement fields", []string{"-d,", "-f2", "--complement"}, "a,b,c\n", "a,c\n"},
+		{"output delimiter", []string{"-d,", "-f1,3", "--output-delimiter= | "}, "a,b,c\n", "a | c\n"},
+		{"bytes", []string{"-b", "2-3"}, "abcdef\n", "bc\n"},
+		{"bytes list", []string{"-b1,3,5-"}, "abcdef\n", "acef\n"},
+		{"bytes complement", []string{"-b2-3", "--complement"}, "abcdef\n", "adef\n"},
+		{"bytes output delimiter", []string{"-b1-2,5", "--output-delimiter=:"}, "abcdef\n", "ab:e\n"},
+		{"bytes adjacent ranges", []string{"-b1,2", "--output-delimiter=:"}, "abc\n", "ab\n"},
+		{"characters", []string{"-c", "2-3"}, "привет\n", "ри\n"},
+		{"bytes split rune", []string{"-b", "1-2"}, "привет\n", "п\n"},
+		{"no trailing newline", []string{"-c1"}, "ab\ncd", "a\nc\n"},
+		{"crlf kept", []string{"-d,", "-f2"}, "a,b\r\n", "b\r\n"},
+		{"multibyte delimiter", []string{"-d", "→", "-f2"}, "a→b\n", "b\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, stderr, status := runCommand(t, &CutCommand{}, "", tt.input, tt.args...)
+			if status != 0 || stderr != "" {
+				t.Fatalf("ожидался код 0 без ошибок, получено %d: %q", status, stderr)
+			}
+			if out != tt.expected {
+				t.Errorf("ожидалось %q, получено %q", tt.expected, out)
+			}
+		})
+	}
+}
+
+func TestCutCommand_InvalidArgs(t *testing.T) {
+	tests := []struct {
+		args    []string
+		message string
+	}{
+		{nil, "необходимо указать"},
+		{[]string{"-b1", "-f1"}, "только один тип"},
+		{[]string{"-f0"}, "нумеруются с 1"},
+		{[]string{"-f3-1"}, "убывающий диапазон"},
+		{[]string{"-f", "a"}, "неверный номер"},
+		{[]string{"-f", "1,,2"}, "неверный список"},
+		{[]string{"-b1", "-d,"}, "только при работе с полями"},
+		{[]string{"-f1", "-d", "ab"}, "одним символом"},
+	}
+
+	for _, tt := range tests {
+		_, stderr, status := runCommand(t, &CutCommand{}, "", "", tt.args...)
+		if status != 1 || !strings.Contains(stderr, tt.message) {
+			t.Errorf("%v: ожидался код 1 и сообщение %q, получено %d: %q", tt.args, tt.message, status, stderr)
+		}
+	}
+}
+
+func TestCutCommand_MissingFile(t *testing.T) {
+	out, stderr, status := runCommand(t, &CutCommand{}, t.TempDir(), "x,y\n", "-d,", "-f2", "missing.txt", "-")
+	if out != "y\n" {
+		t.Errorf("ожидалось %q, получено %q", "y\n", out)
+	}
+	if status != 1 || !strings.Contains(stderr, "missing.txt") {
+		t.Errorf("ожидался код 1 и ошибка о missing.txt, получено %d: %q", status, stderr)
+	}
+}
+
+func TestCutSortUniqPipeline(t *testing.T) {
+	const data = "id,color\n1,red\n2,blue\n3,red\n4,green\n5,red\n6,blue\n"
+
+	fields, _, _ := runCommand(t, &CutCommand{}, "", data, "-d,", "-f2")
+	sorted, _, _ := runCommand(t, &SortCommand{}, "", fields)
+	counted, _, _ := runCommand(t, &UniqCommand{}, "", sorted, "-c")
+	out, _, _ := runCommand(t, &SortCommand{}, "", counted, "-rn")
+
+	expected := "      3 red\n      2 blue\n      1 green\n      1 color\n"
+	if out != expected {
+		t.Errorf("ожидалось %q, получено %q", expected, out)
+	}
+}
@@ -0,0 +1,439 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	customErrors "github.com/ggerlakh/software-design-mhs-itmo/cli/internal/errors"
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/lexer"
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
+)
+
+// declareUsageCode — код возврата declare при неверных параметрах.
+const declareUsageCode = 2
+
+// declareFlags сопоставляет флагам declare атрибуты переменных. Порядок
+// совпадает с порядком флагов в выводе declare -p.
+var declareFlags = []struct {
+	flag byte
+	attr session.Attr
+}{
+	{'i', session.AttrInteger},
+	{'n', session.AttrNameref},
+	{'r', session.AttrReadonly},
+	{'x', session.AttrExport},
+	{'l', session.AttrLower},
+	{'u', session.AttrUpper},
+}
+
+// DeclareCommand реализует встроенную команду "declare".
+// Она объявляет переменные и массивы, задаёт их атрибуты и печатает
+// объявления.
+type DeclareCommand struct{}
+
+// TypesetCommand реализует встроенную команду "typeset" — синоним
+// declare.
+type TypesetCommand struct{}
+
+// declareOptions — флаги declare.
+type declareOptions struct {
+	set, clear session.Attr // -i, -n, -r, -x, -l, -u и их варианты с +
+	indexed    bool         // -a: индексированный массив
+	assoc      bool         // -A: ассоциативный массив
+	unarray    bool         // +a или +A
+	print      bool         // -p: печать объявлений
+	functions  bool         // -f или -F: функции
+}
+
+// Name возвращает имя команды.
+func (d *DeclareCommand) Name() string {
+	return "declare"
+}
+
+// Exec выполняет команду declare.
+//
+// Синтаксис:
+//
+//	declare [-aAfFilnrux] [-p] [NAME[=VALUE] | NAME=(...)]...
+//
+// Примеры:
+//
+//	declare -a files=(*.go)           → индексированный массив
+//	declare -A port=([http]=80)       → ассоциативный массив
+//	declare -i n=2+3                  → n=5
+//	declare -p n                      → declare -i n="5"
+func (d *DeclareCommand) Exec(args []string, ctx *CommandContext) error {
+	return declareVariables(d.Name(), args, ctx)
+}
+
+// Help возвращает справку по команде declare.
+func (d *DeclareCommand) Help() string {
+	return declareHelp("declare")
+}
+
+// Name возвращает имя команды.
+func (t *TypesetCommand) Name() string {
+	return "typeset"
+}
+
+// Exec выполняет команду typeset так же, как declare.
+func (t *TypesetCommand) Exec(args []string, ctx *CommandContext) error {
+	return declareVariables(t.Name(), args, ctx)
+}
+
+// Help возвращает справку по команде typeset.
+func (t *TypesetCommand) Help() string {
+	return declareHelp("typeset")
+}
+
+// declareVariables выполняет declare и typeset; name — имя команды для
+// сообщений.
+func declareVariables(name string, args []string, ctx *CommandContext) error {
+	opts, names, err := parseDeclareArgs(name, args)
+	if err != nil {
+		if writeErr := warnf(ctx, "%v", err); writeErr != nil {
+			return writeErr
+		}
+		return &customErrors.ExitStatusError{Code: declareUsageCode}
+	}
+
+	switch {
+	case opts.functions:
+		// Функций в оболочке нет: список пуст, а любое имя не найдено
+		if len(names) > 0 {
+			return &customErrors.ExitStatusError{Code: 1}
+		}
+		return nil
+	case len(names) == 0 && (opts.print || opts.set != 0 || opts.indexed || opts.assoc):
+		return printDeclarations(ctx, opts)
+	case len(names) == 0:
+		return listVariables(ctx)
+	}
+
+	failed := false
+	for _, arg := range names {
+		var err error
+		if opts.print {
+			err = printDeclaration(ctx, arg)
+		} else {
+			err = declare(ctx, opts, arg)
+		}
+		if err != nil {
+			if writeErr := warnf(ctx, "%s: %v", name, err); writeErr != nil {
+				return writeErr
+			}
+			failed = true
+		}
+	}
+	if failed {
+		return &customErrors.ExitStatusError{Code: 1}
+	}
+	return nil
+}
+
+// declareHelp возвращает справку по declare и typeset.
+func declareHelp(name string) string {
+	return `NAME
+    ` + name + ` - объявляет переменные, массивы и их атрибуты
+
+SYNOPSIS
+    ` + name + ` [-aAfFilnrux] [-p] [NAME[=VALUE] | NAME=(ELEMENT...)]...
+
+DESCRIPTION
+    Объявляет каждую переменную NAME, задаёт её атрибуты и, если указано,
+    присваивает значение. Запись NAME=(ELEMENT...) присваивает массиву
+    список элементов; элемент [KEY]=VALUE задаёт индекс или ключ явно.
+    Флаг с "+" вместо "-" снимает атрибут.
+
+    Обычная переменная, объявленная массивом, становится его элементом 0.
+    Индексированный массив нельзя превратить в ассоциативный и наоборот.
+
+    Атрибуты -i, -l и -u действуют на последующие присваивания; уже
+    присвоенное значение не меняется. Ссылка -n передаёт чтение,
+    присваивание и unset переменной, имя которой хранит.
+
+    Без NAME печатает все переменные в виде NAME=VALUE, а с флагами
+    атрибутов или -p — объявления переменных с этими атрибутами в виде
+    команд declare, которые можно выполнить повторно.
+
+OPTIONS
+    -a      NAME — индексированный массив
+    -A      NAME — ассоциативный массив
+    -i      значение вычисляется как арифметическое выражение
+    -l      значение переводится в нижний регистр
+    -u      значение переводится в верхний регистр
+    -n      NAME — ссылка на переменную VALUE
+    -r      переменная только для чтения; снять атрибут нельзя
+    -x      переменная передаётся внешним программам
+    -p      напечатать объявления NAME
+    -f, -F  функции; в оболочке их нет, поэтому список пуст
+
+EXIT STATUS
+    0 — успех, 1 — неверное имя или присваивание, переменная только для
+    чтения или не найдена, 2 — неверные параметры.
+
+EXAMPLES
+    declare -a files=(*.go)
+        → files — массив имён файлов
+
+    declare -A port=([http]=80 [https]=443); echo ${port[https]}
+        → 443
+
+    declare -i n=2*3; n+=1; echo $n
+        → 7
+
+    declare -u name=bob; declare -p name
+        → declare -u name="BOB"
+
+    x=1; declare -n ref=x; ref=2; echo $x
+        → 2
+
+    declare -r PI=3.14; PI=3
+        → ошибка: PI: переменная только для чтения
+
+    declare -x EDITOR=vi
+        → EDITOR передаётся внешним программам`
+}
+
+// parseDeclareArgs разбирает флаги declare и возвращает остальные
+// аргументы.
+func parseDeclareArgs(name string, args []string) (*declareOptions, []string, error) {
+	opts := &declareOptions{}
+	for len(args) > 0 && len(args[0]) > 1 && (args[0][0] == '-' || args[0][0] == '+') {
+		arg := args[0]
+		args = args[1:]
+		if arg == "--" {
+			break
+		}
+		add := arg[0] == '-'
+		for _, flag := range []byte(arg[1:]) {
+			if attr, ok := declareAttr(flag); ok {
+				if add {
+					opts.set, opts.clear = opts.set|attr, opts.clear&^attr
+				} else {
+					opts.clear, opts.set = opts.clear|attr, opts.set&^attr
+				}
+				continue
+			}
+			switch {
+			case flag == 'a' && add:
+				opts.indexed = true
+			case flag == 'A' && add:
+				opts.assoc = true
+			case flag == 'a' || flag == 'A':
+				opts.unarray = true
+			case flag == 'p':
+				opts.print = true
+			case flag == 'f' || flag == 'F':
+				opts.functions = true
+			default:
+				return nil, nil, fmt.Errorf("%s: %c%c: недопустимая опция", name, arg[0], flag)
+			}
+		}
+	}
+	return opts, args, nil
+}
+
+// declareAttr возвращает атрибут для флага declare.
+func declareAttr(flag byte) (session.Attr, bool) {
+	for _, f := range declareFlags {
+		if f.flag == flag {
+			return f.attr, true
+		}
+	}
+	return 0, false
+}
+
+// declare объявляет переменную из аргумента arg: NAME, NAME=VALUE или
+// NAME=(...).
+func declare(ctx *CommandContext, opts *declareOptions, arg string) error {
+	assignment, elements := arg, []string(nil)
+	if prefix, values, ok := lexer.SplitCompound(arg); ok {
+		assignment, elements = prefix, values
+	}
+
+	ref, value, assigned := splitAssignment(assignment)
+	if !assigned {
+		ref = assignment
+	}
+	name := strings.TrimSuffix(ref, "+")
+	if base, _, ok := session.SplitSubscript(name); ok {
+		name = base
+	}
+	if !session.IsName(name) {
+		return fmt.Errorf("'%s': недопустимый идентификатор", arg)
+	}
+
+	if opts.set&session.AttrNameref != 0 {
+		if opts.indexed || opts.assoc || elements != nil || name != ref {
+			return fmt.Errorf("%s: ссылкой не может быть массив", name)
+		}
+		if assigned {
+			if err := ctx.Vars.SetRef(name, value); err != nil {
+				return err
+			}
+		}
+		return ctx.Vars.Declare(name, opts.set, opts.clear)
+	}
+
+	if _, isArray := ctx.Vars.Array(name); isArray && opts.unarray {
+		return fmt.Errorf("%s: невозможно уничтожить массив таким способом", name)
+	}
+	if opts.indexed || opts.assoc {
+		if err := ctx.Vars.DeclareArray(name, opts.assoc); err != nil {
+			return err
+		}
+	}
+
+	// Только для чтения переменная становится после присваивания, а
+	// остальные атрибуты должны действовать уже на него
+	readonly := opts.set & session.AttrReadonly
+	if err := ctx.Vars.Declare(name, opts.set&^readonly, opts.clear); err != nil {
+		return err
+	}
+	if assigned {
+		if err := Assign(ctx, assignment, elements); err != nil {
+			return err
+		}
+	}
+	if readonly != 0 {
+		return ctx.Vars.Declare(name, readonly, 0)
+	}
+	return nil
+}
+
+// printDeclaration печатает объявление переменной name в виде declare -p.
+func printDeclaration(ctx *CommandContext, name string) error {
+	variable, ok := ctx.Vars.Lookup(name)
+	if !ok {
+		return fmt.Errorf("%s: не найдена", name)
+	}
+	_, err := io.WriteString(ctx.Stdout, formatDeclaration(name, variable)+"\n")
+	return err
+}
+
+// printDeclarations печатает объявления всех переменных, у которых есть
+// атрибуты из opts.
+func printDeclarations(ctx *CommandContext, opts *declareOptions) error {
+	for _, name := range ctx.Vars.Names() {
+		variable, _ := ctx.Vars.Lookup(name)
+		switch {
+		case variable.Attrs&opts.set != opts.set:
+			continue
+		case opts.indexed && (variable.Array == nil || variable.Array.IsAssoc()):
+			continue
+		case opts.assoc && (variable.Array == nil || !variable.Array.IsAssoc()):
+			continue
+		}
+		if _, err := io.WriteString(ctx.Stdout, formatDeclaration(name, variable)+"\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listVariables печатает переменные со значениями в виде NAME=VALUE.
+func listVariables(ctx *CommandContext) error {
+	for _, name := range ctx.Vars.Names() {
+		variable, _ := ctx.Vars.Lookup(name)
+		if !variable.HasValue {
+			continue
+		}
+		value := listQuote(variable.Value)
+		if variable.Array != nil {
+			value = formatElements(variable.Array)
+		}
+		if _, err := fmt.Fprintf(ctx.Stdout, "%s=%s\n", name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// formatDeclaration возвращает команду declare, которая заново создаёт
+// переменную name: declare -ix n="5".
+func formatDeclaration(name string, variable session.Variable) string {
+	var flags []byte
+	switch {
+	case variable.Array != nil && variable.Array.IsAssoc():
+		flags = append(flags, 'A')
+	case variable.Array != nil:
+		flags = append(flags, 'a')
+	}
+	for _, f := range declareFlags {
+		if variable.Attrs&f.attr != 0 {
+			flags = append(flags, f.flag)
+		}
+	}
+	if len(flags) == 0 {
+		flags = append(flags, '-')
+	}
+
+	declaration := "declare -" + string(flags) + " " + name
+	switch {
+	case !variable.HasValue:
+		return declaration
+	case variable.Array != nil:
+		return declaration + "=" + formatElements(variable.Array)
+	}
+	return declaration + "=" + doubleQuote(variable.Value)
+}
+
+// formatElements записывает элементы массива в виде ([KEY]="VALUE" ...).
+// Как и bash, после элементов ассоциативного массива ставится пробел.
+func formatElements(array *session.Array) string {
+	var b strings.Builder
+	b.WriteByte('(')
+	for i, key := range array.Keys() {
+		value, _ := array.Get(key)
+		if i > 0 && !array.IsAssoc() {
+			b.WriteByte(' ')
+		}
+		if !session.IsName(key) && strings.Trim(key, "0123456789") != "" {
+			key = doubleQuote(key)
+		}
+		b.WriteString("[" + key + "]=" + doubleQuote(value))
+		if array.IsAssoc() {
+			b.WriteByte(' ')
+		}
+	}
+	b.WriteByte(')')
+	return b.String()
+}
+
+// doubleQuote заключает s в двойные кавычки, экранируя ", \, $ и `.
+// Строки с непечатаемыми символами записываются в виде $'...'.
+func doubleQuote(s string) string {
+	if !printable(s) {
+		return shellQuote(s)
+	}
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		if strings.ContainsRune("\"\\$`", r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	b.WriteByte('"')
+	return b.String()
+}
+
+// listQuote записывает значение для вывода declare без аргументов:
+// строка из безопасных символов не экранируется, остальные заключаются
+// в одинарные кавычки.
+func listQuote(s string) string {
+	switch {
+	case !printable(s):
+		return shellQuote(s)
+	case strings.ContainsAny(s, " \t!\"#$&'()*;<>?[\\]^`{|}~"):
+		return singleQuote(s)
+	}
+	return s
+}
+
+var (
+	_ BuiltinCommand = (*DeclareCommand)(nil)
+	_ BuiltinCommand = (*TypesetCommand)(nil)
+)
@@ -0,0 +1,192 @@
+package commands
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
+)
+
+// runVariables выполняет команду в контексте ctx и возвращает stderr и код
+// возврата.
+func runVariables(t *testing.T, cmd CommandExecutor, ctx *CommandContext, args ...string) (string, int) {
+	t.Helper()
+	var stderr strings.Builder
+	ctx.Stderr = &stderr
+	err := cmd.Exec(args, ctx)
+	return stderr.String(), testStatus(err)
+}
+
+func TestDeclareCommand_Arrays(t *testing.T) {
+	ctx := newVariablesContext()
+	setVariable(t, ctx, "s", "old")
+
+	stderr, status := runVariables(t, &DeclareCommand{}, ctx,
+		"-a", `list=(x "y z")`, "empty", "s")
+	if status != 0 || stderr != "" {
+		t.Fatalf("ожидался код 0, получено %d: %q", status, stderr)
+	}
+	if values := testArray(ctx, "list").Values(); !reflect.DeepEqual(values, []string{"x", "y z"}) {
+		t.Errorf("list: ожидалось %q, получено %q", []string{"x", "y z"}, values)
+	}
+	if empty, ok := ctx.Vars.Array("empty"); !ok || empty.Len() != 0 || empty.IsAssoc() {
+		t.Errorf("empty: ожидался пустой индексированный массив, получено %v", empty)
+	}
+	if values := testArray(ctx, "s").Values(); !reflect.DeepEqual(values, []string{"old"}) {
+		t.Errorf("s: переменная должна стать элементом 0, получено %q", values)
+	}
+
+	_, status = runVariables(t, &DeclareCommand{}, ctx, "-A", "port=([http]=80 [https]=443)", "v=1")
+	if status != 0 {
+		t.Fatalf("ожидался код 0, получено %d", status)
+	}
+	port := testArray(ctx, "port")
+	if !port.IsAssoc() || !reflect.DeepEqual(port.Keys(), []string{"http", "https"}) {
+		t.Errorf("port: ожидался ассоциативный массив, получено %q", port.Keys())
+	}
+	if v, ok := ctx.Vars.Array("v"); !ok || !v.IsAssoc() {
+		t.Errorf("v: ожидался ассоциативный массив, получено %v", v)
+	}
+	runVariables(t, &DeclareCommand{}, ctx, "w")
+	if w, ok := ctx.Vars.Lookup("w"); !ok || w.HasValue {
+		t.Errorf("w: ожидалась переменная без значения, получено %+v (%v)", w, ok)
+	}
+}
+
+func TestDeclareCommand_Errors(t *testing.T) {
+	tests := []struct {
+		args    []string
+		status  int
+		message string
+	}{
+		{[]string{"-A", "list"}, 1, "declare: list: невозможно преобразовать индексированный массив в ассоциативный"},
+		{[]string{"-a", "map"}, 1, "declare: map: невозможно преобразовать ассоциативный массив в индексированный"},
+		{[]string{"1x=2"}, 1, "declare: '1x=2': недопустимый идентификатор"},
+		{[]string{"-q", "x"}, 2, "declare: -q: недопустимая опция"},
+		{[]string{"+a", "list"}, 1, "declare: list: невозможно уничтожить массив таким способом"},
+		{[]string{"-r", "ro"}, 0, ""},
+		{[]string{"+r", "ro"}, 1, "declare: ro: переменная только для чтения"},
+		{[]string{"-n", "ref=1x"}, 1, "declare: ref: '1x': недопустимое имя переменной для ссылки"},
+		{[]string{"-n", "list"}, 1, "declare: list: ссылкой не может быть массив"},
+		{[]string{"-i", "n=1/0"}, 1, "declare:"},
+	}
+	for _, tt := range tests {
+		ctx := newVariablesContext()
+		setArray(t, ctx, "list", session.NewIndexedArray("x"))
+		setArray(t, ctx, "map", session.NewAssocArray())
+		setVariable(t, ctx, "ro", "1")
+		if err := ctx.Vars.Declare("ro", session.AttrReadonly, 0); err != nil {
+			t.Fatal(err)
+		}
+
+		stderr, status := runVariables(t, &DeclareCommand{}, ctx, tt.args...)
+		if status != tt.status || !strings.Contains(stderr, tt.message) {
+			t.Errorf("%v: ожидался код %d и %q, получено %d: %q", tt.args, tt.status, tt.message, status, stderr)
+		}
+	}
+}
+
+func TestDeclareCommand_Attributes(t *testing.T) {
+	ctx := newVariablesContext()
+	steps := [][]string{
+		{"-i", "n=2*3"},
+		{"-l", "low=MiXeD"},
+		{"-u", "up=MiXeD"},
+		{"-r", "pi=3.14"},
+		{"-x", "EDITOR=vi"},
+		{"-ai", "sum=(1+1 2*2)"},
+		{"target=old"},
+		{"-n", "ref=target"},
+	}
+	for _, args := range steps {
+		if stderr, status := runVariables(t, &DeclareCommand{}, ctx, args...); status != 0 {
+			t.Fatalf("%v: ожидался код 0, получено %d: %q", args, status, stderr)
+		}
+	}
+	if err := Assign(ctx, "n+=1", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := Assign(ctx, "ref=new", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{"n": "7", "low": "mixed", "up": "MIXED", "pi": "3.14", "target": "new"}
+	for name, value := range expected {
+		if got := variableValue(ctx, name); got != value {
+			t.Errorf("%s: ожидалось %q, получено %q", name, value, got)
+		}
+	}
+	if values := testArray(ctx, "sum").Values(); !reflect.DeepEqual(values, []string{"2", "4"}) {
+		t.Errorf("sum: ожидалось %q, получено %q", []string{"2", "4"}, values)
+	}
+	if env := ctx.Vars.Environ(); !reflect.DeepEqual(env, []string{"EDITOR=vi"}) {
+		t.Errorf("экспортироваться должна только EDITOR, получено %q", env)
+	}
+
+	stderr, status := runVariables(t, &DeclareCommand{}, ctx, "pi=3")
+	if status != 1 || !strings.Contains(stderr, "pi: переменная только для чтения") {
+		t.Errorf("ожидалась ошибка только для чтения, получено %d: %q", status, stderr)
+	}
+	if err := Assign(ctx, "pi=3", nil); err == nil || variableValue(ctx, "pi") != "3.14" {
+		t.Errorf("присваивание переменной только для чтения должно давать ошибку")
+	}
+
+	// Снятие атрибута не меняет уже присвоенное значение
+	runVariables(t, &DeclareCommand{}, ctx, "+u", "-l", "up")
+	if got := variableValue(ctx, "up"); got != "MIXED" {
+		t.Errorf("up: ожидалось %q, получено %q", "MIXED", got)
+	}
+}
+
+func TestDeclareCommand_Print(t *testing.T) {
+	ctx := newVariablesContext()
+	var stdout strings.Builder
+	ctx.Stdout = &stdout
+	for _, args := range [][]string{
+		{"-i", "n=5"},
+		{"-rx", "ro=a\"b$c"},
+		{"plain=a b"},
+		{"ctl=a\nb"},
+		{"-a", "list=(x 'y z')"},
+		{"-A", "map=([k]=v)"},
+		{"-u", "nothing"},
+		{"-n", "ref=n"},
+	} {
+		if stderr, status := runVariables(t, &DeclareCommand{}, ctx, args...); status != 0 {
+			t.Fatalf("%v: ожидался код 0, получено %d: %q", args, status, stderr)
+		}
+	}
+
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"-p", "n", "ro"}, "declare -i n=\"5\"\ndeclare -rx ro=\"a\\\"b\\$c\"\n"},
+		{[]string{"-p", "plain", "ctl"}, "declare -- plain=\"a b\"\ndeclare -- ctl=$'a\\nb'\n"},
+		{[]string{"-p", "list", "map"}, "declare -a list=([0]=\"x\" [1]=\"y z\")\ndeclare -A map=([k]=\"v\" )\n"},
+		{[]string{"-p", "nothing", "ref"}, "declare -u nothing\ndeclare -n ref=\"n\"\n"},
+		{[]string{"-x"}, "declare -rx ro=\"a\\\"b\\$c\"\n"},
+		{[]string{"-pa"}, "declare -a list=([0]=\"x\" [1]=\"y z\")\n"},
+		{nil, "ctl=$'a\\nb'\nlist=([0]=\"x\" [1]=\"y z\")\nmap=([k]=\"v\" )\nn=5\nplain='a b'\nref=n\nro='a\"b$c'\n"},
+	}
+	for _, tt := range tests {
+		stdout.Reset()
+		stderr, status := runVariables(t, &TypesetCommand{}, ctx, tt.args...)
+		if status != 0 || stdout.String() != tt.expected {
+			t.Errorf("%v: ожидалось %q, получено %q (код %d, %q)", tt.args, tt.expected, stdout.String(), status, stderr)
+		}
+	}
+
+	stdout.Reset()
+	stderr, status := runVariables(t, &DeclareCommand{}, ctx, "-p", "missing")
+	if status != 1 || stderr != "declare: missing: не найдена\n" {
+		t.Errorf("ожидался код 1 и ошибка, получено %d: %q", status, stderr)
+	}
+	if _, status := runVariables(t, &DeclareCommand{}, ctx, "-F"); status != 0 || stdout.Len() != 0 {
+		t.Errorf("declare -F: ожидался пустой вывод, получено %q (код %d)", stdout.String(), status)
+	}
+	if stderr, status := runVariables(t, &DeclareCommand{}, ctx, "-f", "fn"); status != 1 || stderr != "" {
+		t.Errorf("declare -f fn: ожидался код 1 без сообщения, получено %d: %q", status, stderr)
+	}
+}
@@ -1,7 +1,7 @@
 package commands
 
 import (
-	"fmt"
+	"io"
 	"strings"
 )
 
@@ -16,37 +16,57 @@ func (e *EchoCommand) Name() string {
 }
 
 // Exec выполняет команду echo с переданными аргументами.
-// Поддерживаются базовые опции:
-//   - -n — не добавлять перевод строки в конце вывода.
+// Поддерживаются опции, как в bash:
+//   - -n — не добавлять перевод строки в конце вывода;
+//   - -e — раскрывать последовательности \n, \t, \\, \c, \0NNN, \xHH и другие;
+//   - -E — не раскрывать последовательности (по умолчанию).
+//
+// Опции можно объединять (-ne). Аргумент, состоящий не только из этих
+// букв, печатается как есть и завершает разбор опций.
 //
 // Примеры:
 //
 //	echo hello world   → hello world\n
 //	echo -n test       → test
+//	echo -e 'a\tb'     → a<TAB>b\n
 func (e *EchoCommand) Exec(args []string, ctx *CommandContext) error {
-	newline := true
+	newline, escapes := true, false
 
-	// Проверяем опцию -n
-	if len(args) > 0 && args[0] == "-n" {
-		newline = false
+	for len(args) > 0 && isEchoOption(args[0]) {
+		for _, opt := range args[0][1:] {
+			switch opt {
+			case 'n':
+				newline = false
+			case 'e':
+				escapes = true
+			case 'E':
+				escapes = false
+			}
+		}
 		args = args[1:]
 	}
 
 	// Собираем строку из аргументов, разделяя пробелами
 	output := strings.Join(args, " ")
 
-	// Печатаем результат с учётом опции -n
-	if newline {
-		if _, err := fmt.Fprintln(ctx.Stdout, output); err != nil {
-			return err
-		}
-	} else {
-		if _, err := fmt.Fprint(ctx.Stdout, output); err != nil {
-			return err
+	// \c прекращает вывод, включая перевод строки
+	if escapes {
+		var stop bool
+		if output, stop = expandEscapes(output, escapeEcho); stop {
+			newline = false
 		}
 	}
+	if newline {
+		output += "\n"
+	}
+
+	_, err := io.WriteString(ctx.Stdout, output)
+	return err
+}
 
-	return nil
+// isEchoOption сообщает, что arg — набор опций echo, например -n или -neE.
+func isEchoOption(arg string) bool {
+	return len(arg) > 1 && arg[0] == '-' && strings.Trim(arg[1:], "neE") == ""
 }
 
 // Help возвращает справку по команде echo.
@@ -63,11 +83,24 @@ DESCRIPTION
 
 OPTIONS
     -n  — не добавлять перевод строки в конце вывода
+    -e  — раскрывать последовательности с обратной косой чертой
+    -E  — не раскрывать последовательности (по умолчанию)
+
+    Последовательности, раскрываемые с -e:
+        \a \b \e \f \n \r \t \v   управляющие символы
+        \\                  обратная косая черта
+        \c                  прекратить вывод (без перевода строки)
+        \0NNN               байт с восьмеричным кодом NNN (до трёх цифр)
+        \xHH                байт с шестнадцатеричным кодом HH (до двух цифр)
+        \uHHHH, \UHHHHHHHH  символ Unicode
 
 EXAMPLES
     echo Hello world
         → Hello world
 
     echo -n Hello
-        → Hello`
+        → Hello
+
+    echo -e 'a\tb\c' tail
+        → a<TAB>b`
 }
@@ -5,6 +5,8 @@ import (
 	"os"
 	"strings"
 	"testing"
+
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
 )
 
 // testExecWithOutput выполняет команду с переданными аргументами и возвращает вывод
@@ -14,7 +16,7 @@ func testEchoExecWithOutput(cmd CommandExecutor, args []string) string {
 		Stdin:  os.Stdin,
 		Stdout: &buf,
 		Stderr: os.Stderr,
-		Env:    make(map[string]string),
+		Vars:   session.NewVariables(nil),
 		Dir:    ".",
 	}
 	cmd.Exec(args, ctx)
@@ -81,3 +83,32 @@ func TestEchoCommand_MultipleSpaces(t *testing.T) {
 		t.Errorf("ожидался перевод строки в конце вывода: %q", out)
 	}
 }
+
+func TestEchoCommand_Escapes(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{"без -e последовательности не раскрываются", []string{`a\tb`}, "a\\tb\n"},
+		{"-e", []string{"-e", `a\tb\nc\\d`}, "a\tb\nc\\d\n"},
+		{"-E отменяет -e", []string{"-e", "-E", `a\n`}, "a\\n\n"},
+		{"объединённые опции", []string{"-ne", `x\ty`}, "x\ty"},
+		{"\\c прекращает вывод", []string{"-e", `one\ctwo`, "three"}, "one"},
+		{"\\0NNN", []string{"-e", `\0101\060\0`}, "A0\x00\n"},
+		{"\\xHH", []string{"-e", `\x41\x4a\xz`}, "AJ\\xz\n"},
+		{"\\u", []string{"-e", `\u0416`}, "Ж\n"},
+		{"\\NNN без нуля не раскрывается", []string{"-e", `\101`}, "\\101\n"},
+		{"неизвестная опция печатается", []string{"-nx", "a"}, "-nx a\n"},
+		{"опции только в начале", []string{"a", "-n"}, "a -n\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := testEchoExecWithOutput(&EchoCommand{}, tt.args)
+			if out != tt.expected {
+				t.Errorf("ожидалось %q, получено %q", tt.expected, out)
+			}
+		})
+	}
+}
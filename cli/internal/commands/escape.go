@@ -0,0 +1,210 @@
+package commands
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Стили последовательностей с обратной косой чертой.
+const (
+	// escapeEcho — echo -e и printf %b: восьмеричный код записывается
+	// как \0NNN, \c прекращает вывод.
+	escapeEcho = iota
+	// escapeFormat — строка формата printf: восьмеричный код \NNN,
+	// дополнительно распознаются \" \' и \?.
+	escapeFormat
+)
+
+// expandEscapes раскрывает в s последовательности \a \b \e \E \f \n \r
+// \t \v \\, восьмеричные коды, \xHH, \uHHHH и \UHHHHHHHH. Неизвестные
+// последовательности остаются как есть. Второе значение сообщает, что
+// встретилась \c (только в стиле escapeEcho): всё после неё отбрасывается.
+func expandEscapes(s string, style int) (string, bool) {
+	if !strings.Contains(s, `\`) {
+		return s, false
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		i++
+		c := s[i]
+		if simple, ok := simpleEscape(c, style); ok {
+			b.WriteByte(simple)
+			continue
+		}
+
+		switch {
+		case c == 'c' && style == escapeEcho:
+			return b.String(), true
+		case c == '0' && style == escapeEcho:
+			value, n := parseDigits(s[i+1:], 8, 3)
+			b.WriteByte(byte(value))
+			i += n
+		case c >= '0' && c <= '7' && style == escapeFormat:
+			value, n := parseDigits(s[i:], 8, 3)
+			b.WriteByte(byte(value))
+			i += n - 1
+		case c == 'x':
+			value, n := parseDigits(s[i+1:], 16, 2)
+			if n == 0 {
+				b.WriteString(`\x`)
+				continue
+			}
+			b.WriteByte(byte(value))
+			i += n
+		case c == 'u' || c == 'U':
+			size := 4
+			if c == 'U' {
+				size = 8
+			}
+			value, n := parseDigits(s[i+1:], 16, size)
+			if n == 0 || !utf8.ValidRune(rune(value)) {
+				b.WriteByte('\\')
+				b.WriteByte(c)
+				continue
+			}
+			b.WriteRune(rune(value))
+			i += n
+		default:
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		}
+	}
+	return b.String(), false
+}
+
+// simpleEscape возвращает байт для односимвольной последовательности \c.
+func simpleEscape(c byte, style int) (byte, bool) {
+	switch c {
+	case 'a':
+		return '\a', true
+	case 'b':
+		return '\b', true
+	case 'e', 'E':
+		return 0x1b, true
+	case 'f':
+		return '\f', true
+	case 'n':
+		return '\n', true
+	case 'r':
+		return '\r', true
+	case 't':
+		return '\t', true
+	case 'v':
+		return '\v', true
+	case '\\':
+		return '\\', true
+	case '"', '\'', '?':
+		return c, style == escapeFormat
+	}
+	return 0, false
+}
+
+// parseDigits читает из начала s не более limit цифр в системе счисления
+// base и возвращает их значение и количество прочитанных символов.
+func parseDigits(s string, base, limit int) (int, int) {
+	value, n := 0, 0
+	for n < len(s) && n < limit {
+		digit := strings.IndexByte("0123456789abcdef"[:base], lowerASCII(s[n]))
+		if digit < 0 {
+			break
+		}
+		value = value*base + digit
+		n++
+	}
+	return value, n
+}
+
+func lowerASCII(c byte) byte {
+	if c >= 'A' && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+	return c
+}
+
+// printable сообщает, состоит ли s только из печатаемых символов UTF-8 и
+// пробелов.
+func printable(s string) bool {
+	if !utf8.ValidString(s) {
+		return false
+	}
+	for _, r := range s {
+		if !unicode.IsPrint(r) && r != ' ' {
+			return false
+		}
+	}
+	return true
+}
+
+// shellQuote экранирует s так, чтобы shell прочитал строку обратно без
+// изменений: специальные символы экранируются обратной косой чертой,
+// а строки с непечатаемыми символами записываются в виде $'...',
+// как это делает printf %q в bash.
+func shellQuote(s string) string {
+	if s == "" {
+		return "''"
+	}
+
+	var b strings.Builder
+	if printable(s) {
+		for _, r := range s {
+			if strings.ContainsRune(" !\"#$&'()*,;<=>?[\\]^`{|}~", r) {
+				b.WriteByte('\\')
+			}
+			b.WriteRune(r)
+		}
+		return b.String()
+	}
+
+	b.WriteString("$'")
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			b.WriteString(octalEscape(s[i]))
+		case r == '\\' || r == '\'':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r < 0x80 && !unicode.IsPrint(r) && r != ' ':
+			b.WriteString(controlEscape(byte(r)))
+		default:
+			b.WriteRune(r)
+		}
+		i += size
+	}
+	b.WriteByte('\'')
+	return b.String()
+}
+
+// controlEscape возвращает запись управляющего символа внутри $'...'.
+func controlEscape(c byte) string {
+	switch c {
+	case '\a':
+		return `\a`
+	case '\b':
+		return `\b`
+	case 0x1b:
+		return `\E`
+	case '\f':
+		return `\f`
+	case '\n':
+		return `\n`
+	case '\r':
+		return `\r`
+	case '\t':
+		return `\t`
+	case '\v':
+		return `\v`
+	}
+	return octalEscape(c)
+}
+
+func octalEscape(c byte) string {
+	return `\` + string([]byte{'0' + c>>6, '0' + c>>3&7, '0' + c&7})
+}
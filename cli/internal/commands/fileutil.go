@@ -0,0 +1,21 @@
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// warnf печатает в stderr сообщение об ошибке с переводом строки.
+// Возвращается только ошибка записи.
+func warnf(ctx *CommandContext, format string, args ...any) error {
+	_, err := fmt.Fprintf(ctx.Stderr, format+"\n", args...)
+	return err
+}
+
+// isWithin сообщает, что путь path совпадает с dir или находится внутри
+// него. Оба пути должны быть абсолютными.
+func isWithin(path, dir string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,316 @@
+package commands
+
+import (
+	"bufio"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	customErrors "github.com/ggerlakh/software-design-mhs-itmo/cli/internal/errors"
+)
+
+// findExecLimit — наибольшая суммарная длина путей в одном вызове
+// -exec ... {} +; пути сверх неё передаются следующему вызову.
+const findExecLimit = 128 * 1024
+
+// FindCommand реализует встроенную команду "find".
+// Она обходит дерево каталогов и выполняет выражение для каждого файла.
+type FindCommand struct{}
+
+// findEntry — файл, для которого вычисляется выражение.
+type findEntry struct {
+	name  string      // путь для вывода, в том виде, в котором его записал пользователь
+	path  string      // путь в файловой системе
+	info  fs.FileInfo // результат Lstat: символьные ссылки не раскрываются
+	depth int         // глубина: 0 — путь из аргументов
+	prune bool        // -prune: не заходить в каталог
+}
+
+// finder хранит состояние обхода.
+type finder struct {
+	ctx        *CommandContext
+	out        *bufio.Writer
+	expr       findExpr
+	now        time.Time
+	maxDepth   int  // -maxdepth; -1 — без ограничения
+	minDepth   int  // -mindepth
+	depthFirst bool // -depth, -delete: содержимое каталога раньше него самого
+	batches    []*findBatch
+	failed     bool  // была ошибка: код возврата 1
+	err        error // ошибка записи в stdout: обход прекращается
+}
+
+// Name возвращает имя команды.
+func (f *FindCommand) Name() string {
+	return "find"
+}
+
+// Exec выполняет команду find.
+//
+// Синтаксис:
+//
+//	find [PATH...] [EXPRESSION]
+//
+// Без PATH обходится текущий каталог, без действий в выражении (-print,
+// -print0, -delete, -exec) к нему добавляется -print. Символьные ссылки
+// не раскрываются.
+//
+// Примеры:
+//
+//	find . -name '*.go'                       → все файлы Go
+//	find src -type f -size +1M                → файлы больше мегабайта
+//	find . -name node_modules -prune -o -print
+//	find . -name '*.tmp' -exec rm {} +
+func (f *FindCommand) Exec(args []string, ctx *CommandContext) error {
+	paths := args
+	for i, arg := range args {
+		if arg == "(" || arg == "!" || len(arg) > 1 && arg[0] == '-' {
+			paths = args[:i]
+			break
+		}
+	}
+
+	w := &finder{ctx: ctx, out: bufio.NewWriter(ctx.Stdout), now: time.Now(), maxDepth: -1}
+	expr, err := parseFindExpr(w, args[len(paths):])
+	if err != nil {
+		if writeErr := warnf(ctx, "%v", err); writeErr != nil {
+			return writeErr
+		}
+		return &customErrors.ExitStatusError{Code: 1}
+	}
+	w.expr = expr
+
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	for _, name := range paths {
+		if w.err != nil {
+			break
+		}
+		w.walk(name)
+	}
+	for _, batch := range w.batches {
+		if w.err == nil {
+			w.runBatch(batch)
+		}
+	}
+
+	if err := w.out.Flush(); err != nil && w.err == nil {
+		w.err = err
+	}
+	if w.err != nil {
+		return w.err
+	}
+	if w.failed {
+		return &customErrors.ExitStatusError{Code: 1}
+	}
+	return nil
+}
+
+// walk обходит путь из аргументов.
+func (w *finder) walk(name string) {
+	path := w.ctx.ResolvePath(name)
+	info, err := os.Lstat(path)
+	if err != nil {
+		w.warn("find: '%s': %v", name, fileError(err))
+		return
+	}
+	w.visit(&findEntry{name: name, path: path, info: info})
+}
+
+// visit вычисляет выражение для entry и обходит его содержимое, если это
+// каталог. Элементы каталога перебираются в лексикографическом порядке.
+func (w *finder) visit(entry *findEntry) {
+	select {
+	case <-w.ctx.Done():
+		w.err = w.ctx.Context.Err()
+		return
+	default:
+	}
+
+	if !w.depthFirst && entry.depth >= w.minDepth {
+		w.expr(entry)
+	}
+	if w.err != nil {
+		return
+	}
+
+	if entry.info.IsDir() && !entry.prune && (w.maxDepth < 0 || entry.depth < w.maxDepth) {
+		children, err := os.ReadDir(entry.path)
+		if err != nil {
+			w.warn("find: '%s': %v", entry.name, fileError(err))
+		}
+		for _, child := range children {
+			info, err := child.Info()
+			if err != nil {
+				w.warn("find: '%s': %v", joinFindPath(entry.name, child.Name()), fileError(err))
+				continue
+			}
+			w.visit(&findEntry{
+				name:  joinFindPath(entry.name, child.Name()),
+				path:  filepath.Join(entry.path, child.Name()),
+				info:  info,
+				depth: entry.depth + 1,
+			})
+			if w.err != nil {
+				return
+			}
+		}
+	}
+
+	if w.depthFirst && entry.depth >= w.minDepth {
+		w.expr(entry)
+	}
+}
+
+// print выводит имя файла с завершающим символом end.
+func (w *finder) print(entry *findEntry, end byte) {
+	if w.err != nil {
+		return
+	}
+	if _, err := w.out.WriteString(entry.name); err != nil {
+		w.err = err
+		return
+	}
+	if err := w.out.WriteByte(end); err != nil {
+		w.err = err
+	}
+}
+
+// delete удаляет файл или пустой каталог (-delete).
+func (w *finder) delete(entry *findEntry) bool {
+	if entry.name == "." {
+		return true
+	}
+	if err := os.Remove(entry.path); err != nil {
+		w.warn("find: невозможно удалить '%s': %v", entry.name, fileError(err))
+		return false
+	}
+	return true
+}
+
+// run запускает команду -exec. Перед запуском накопленный вывод find
+// сбрасывается, чтобы вывод команды шёл после него.
+func (w *finder) run(command []string) bool {
+	if err := w.out.Flush(); err != nil {
+		w.err = err
+		return false
+	}
+	status := spawn(w.ctx, command[0], command[1:], w.ctx.Stdin)
+	if status == utilityNotFound {
+		w.warn("find: '%s': команда не найдена", command[0])
+	}
+	return status == 0
+}
+
+// runBatch запускает накопленный вызов -exec ... {} +.
+func (w *finder) runBatch(batch *findBatch) {
+	if len(batch.paths) == 0 {
+		return
+	}
+	command := append(append([]string{}, batch.command...), batch.paths...)
+	batch.paths, batch.size = nil, 0
+	if !w.run(command) {
+		w.failed = true
+	}
+}
+
+// warn печатает сообщение об ошибке и запоминает её для кода возврата.
+func (w *finder) warn(format string, args ...any) {
+	w.failed = true
+	if w.err != nil {
+		return
+	}
+	if err := w.out.Flush(); err != nil {
+		w.err = err
+		return
+	}
+	if err := warnf(w.ctx, format, args...); err != nil {
+		w.err = err
+	}
+}
+
+// joinFindPath добавляет имя к пути каталога: "dir" и "dir/" → "dir/name".
+func joinFindPath(dir, name string) string {
+	return strings.TrimSuffix(dir, "/") + "/" + name
+}
+
+// Help возвращает справку по команде find.
+func (f *FindCommand) Help() string {
+	return `NAME
+    find - ищет файлы в дереве каталогов
+
+SYNOPSIS
+    find [PATH...] [EXPRESSION]
+
+DESCRIPTION
+    Обходит каталоги PATH (по умолчанию текущий) и вычисляет EXPRESSION
+    для каждого файла. Выражение состоит из проверок и действий,
+    соединённых операторами; если в нём нет действий, найденные файлы
+    печатаются (-print). Символьные ссылки не раскрываются.
+
+    Числа N в -size, -mtime и -mmin записываются как +N (больше N), -N
+    (меньше N) или N (ровно N).
+
+OPERATORS
+    ( EXPR )                группировка
+    ! EXPR, -not EXPR       отрицание
+    EXPR EXPR, EXPR -a EXPR, EXPR -and EXPR
+                            И: второе выражение вычисляется, только если
+                            первое истинно
+    EXPR -o EXPR, EXPR -or EXPR
+                            ИЛИ: второе выражение вычисляется, только если
+                            первое ложно
+
+OPTIONS
+    -maxdepth N             не заходить глубже N уровней от PATH
+    -mindepth N             не проверять файлы ближе N уровней от PATH
+    -depth                  проверять содержимое каталога раньше него самого
+
+TESTS
+    -name PATTERN           имя файла (без каталога) соответствует шаблону
+                            оболочки; -iname — без учёта регистра
+    -path PATTERN           путь целиком соответствует шаблону; "*" совпадает
+                            и с "/"; -ipath — без учёта регистра
+    -type C                 тип файла: f — обычный, d — каталог,
+                            l — символьная ссылка, p — канал, s — сокет,
+                            b, c — устройства; несколько через запятую
+    -size N[cwbkMG]         размер в единицах (округляется вверх): c — байты,
+                            w — 2 байта, b — 512 байт (по умолчанию),
+                            k — КиБ, M — МиБ, G — ГиБ
+    -mtime N                изменён N полных суток назад
+    -mmin N                 изменён N полных минут назад
+    -newer FILE             изменён позже файла FILE
+    -empty                  пустой файл или каталог
+    -true, -false           всегда истинно или ложно
+    -prune                  не заходить в каталог; всегда истинно
+
+ACTIONS
+    -print                  напечатать путь и перевод строки
+    -print0                 напечатать путь и нулевой байт (для xargs -0)
+    -delete                 удалить файл или пустой каталог; включает -depth
+    -exec COMMAND ;         выполнить команду, заменив "{}" в аргументах на
+                            путь; истинно, если команда завершилась успешно
+    -exec COMMAND {} +      выполнить команду для многих файлов сразу
+
+EXIT STATUS
+    0 — успех, 1 — ошибка в выражении, ошибка доступа к файлу или
+    неуспешный вызов -exec ... +.
+
+EXAMPLES
+    find . -name '*.log' -mtime +7 -delete
+        → удалить логи старше недели
+
+    find . -name .git -prune -o -type f -print
+        → все файлы, кроме содержимого .git
+
+    find src -name '*.go' -exec grep -l TODO {} +
+        → файлы Go с пометками TODO
+
+    find . -type f -print0 | xargs -0 wc -l
+        → число строк в каждом файле`
+}
+
+var _ BuiltinCommand = (*FindCommand)(nil)
@@ -0,0 +1,437 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/glob"
+)
+
+// findSizeUnits — единицы -size.
+var findSizeUnits = map[byte]int64{
+	'c': 1,
+	'w': 2,
+	'b': 512,
+	'k': 1 << 10,
+	'M': 1 << 20,
+	'G': 1 << 30,
+}
+
+// findValuePrimaries — проверки и параметры find, принимающие аргумент.
+var findValuePrimaries = map[string]bool{
+	"-name": true, "-iname": true, "-path": true, "-ipath": true,
+	"-type": true, "-size": true, "-mtime": true, "-mmin": true,
+	"-newer": true, "-maxdepth": true, "-mindepth": true,
+}
+
+// findExpr — скомпилированное выражение find.
+type findExpr func(entry *findEntry) bool
+
+// findBatch накапливает пути для -exec ... {} +.
+type findBatch struct {
+	command []string
+	paths   []string
+	size    int
+}
+
+// findNumber — число из -size, -mtime или -mmin: +N, -N или N.
+type findNumber struct {
+	cmp int // 1 — больше, -1 — меньше, 0 — равно
+	n   int64
+}
+
+func (n findNumber) match(value int64) bool {
+	switch n.cmp {
+	case 1:
+		return value > n.n
+	case -1:
+		return value < n.n
+	}
+	return value == n.n
+}
+
+// findParser разбирает выражение find рекурсивным спуском. Приоритет
+// операторов по убыванию: !, И (явное -a или соседство), -o.
+type findParser struct {
+	w      *finder
+	args   []string
+	pos    int
+	action bool // в выражении есть действие: -print не добавляется
+}
+
+// parseFindExpr разбирает выражение find. Параметры -maxdepth, -depth и
+// подобные сохраняются в w.
+func parseFindExpr(w *finder, args []string) (findExpr, error) {
+	p := &findParser{w: w, args: args}
+	expr := findExpr(func(*findEntry) bool { return true })
+	if len(args) > 0 {
+		var err error
+		if expr, err = p.or(); err != nil {
+			return nil, err
+		}
+		if p.pos < len(args) {
+			return nil, fmt.Errorf("find: неожиданный аргумент '%s'", args[p.pos])
+		}
+	}
+
+	if !p.action {
+		test := expr
+		expr = func(entry *findEntry) bool {
+			if test(entry) {
+				w.print(entry, '\n')
+			}
+			return true
+		}
+	}
+	return expr, nil
+}
+
+func (p *findParser) peek(tokens ...string) bool {
+	if p.pos >= len(p.args) {
+		return false
+	}
+	for _, token := range tokens {
+		if p.args[p.pos] == token {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *findParser) or() (findExpr, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek("-o", "-or") {
+		p.pos++
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		first := left
+		left = func(entry *findEntry) bool {
+			return first(entry) || right(entry)
+		}
+	}
+	return left, nil
+}
+
+func (p *findParser) and() (findExpr, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for p.pos < len(p.args) && !p.peek(")", "-o", "-or") {
+		if p.peek("-a", "-and") {
+			p.pos++
+		}
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		first := left
+		left = func(entry *findEntry) bool {
+			return first(entry) && right(entry)
+		}
+	}
+	return left, nil
+}
+
+func (p *findParser) unary() (findExpr, error) {
+	if p.pos >= len(p.args) {
+		return nil, fmt.Errorf("find: после '%s' ожидалось выражение", p.args[p.pos-1])
+	}
+
+	token := p.args[p.pos]
+	p.pos++
+	switch token {
+	case "!", "-not":
+		operand, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return func(entry *findEntry) bool { return !operand(entry) }, nil
+	case "(":
+		if p.peek(")") {
+			return nil, errors.New("find: пустые скобки недопустимы")
+		}
+		inner, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		if !p.peek(")") {
+			return nil, errors.New("find: пропущена ')'")
+		}
+		p.pos++
+		return inner, nil
+	case ")", "-o", "-or", "-a", "-and":
+		return nil, fmt.Errorf("find: неожиданный '%s'", token)
+	}
+	return p.primary(token)
+}
+
+// arg возвращает аргумент проверки или действия token.
+func (p *findParser) arg(token string) (string, error) {
+	if p.pos >= len(p.args) {
+		return "", fmt.Errorf("find: отсутствует аргумент для '%s'", token)
+	}
+	p.pos++
+	return p.args[p.pos-1], nil
+}
+
+// primary разбирает проверку, действие или параметр token.
+func (p *findParser) primary(token string) (findExpr, error) {
+	w := p.w
+	always := func(*findEntry) bool { return true }
+
+	switch token {
+	case "-true":
+		return always, nil
+	case "-false":
+		return func(*findEntry) bool { return false }, nil
+	case "-prune":
+		return func(entry *findEntry) bool {
+			entry.prune = true
+			return true
+		}, nil
+	case "-depth":
+		w.depthFirst = true
+		return always, nil
+	case "-empty":
+		return findEmpty, nil
+	case "-print", "-print0":
+		p.action = true
+		end := byte('\n')
+		if token == "-print0" {
+			end = 0
+		}
+		return func(entry *findEntry) bool {
+			w.print(entry, end)
+			return true
+		}, nil
+	case "-delete":
+		p.action = true
+		w.depthFirst = true
+		return w.delete, nil
+	case "-exec":
+		p.action = true
+		return p.exec()
+	}
+	if !findValuePrimaries[token] {
+		return nil, fmt.Errorf("find: неизвестный предикат '%s'", token)
+	}
+
+	value, err := p.arg(token)
+	if err != nil {
+		return nil, err
+	}
+	switch token {
+	case "-name", "-iname", "-path", "-ipath":
+		fold := token == "-iname" || token == "-ipath"
+		if fold {
+			value = strings.ToLower(value)
+		}
+		pattern := glob.Compile(value, glob.Options{DotGlob: true})
+		base := token == "-name" || token == "-iname"
+		return func(entry *findEntry) bool {
+			name := entry.name
+			if base {
+				name = filepath.Base(name)
+			}
+			if fold {
+				name = strings.ToLower(name)
+			}
+			return pattern.Match(name)
+		}, nil
+	case "-type":
+		return parseFindType(value)
+	case "-size":
+		return parseFindSize(value)
+	case "-mtime", "-mmin":
+		unit := 24 * time.Hour
+		if token == "-mmin" {
+			unit = time.Minute
+		}
+		number, err := parseFindNumber(token, value)
+		if err != nil {
+			return nil, err
+		}
+		return func(entry *findEntry) bool {
+			return number.match(int64(w.now.Sub(entry.info.ModTime()) / unit))
+		}, nil
+	case "-newer":
+		info, err := os.Stat(w.ctx.ResolvePath(value))
+		if err != nil {
+			return nil, fmt.Errorf("find: '%s': %v", value, fileError(err))
+		}
+		return func(entry *findEntry) bool {
+			return entry.info.ModTime().After(info.ModTime())
+		}, nil
+	case "-maxdepth", "-mindepth":
+		depth, err := strconv.Atoi(value)
+		if err != nil || depth < 0 {
+			return nil, fmt.Errorf("find: %s: неверное число '%s'", token, value)
+		}
+		if token == "-maxdepth" {
+			w.maxDepth = depth
+		} else {
+			w.minDepth = depth
+		}
+	}
+	return always, nil
+}
+
+// exec разбирает -exec COMMAND ; и -exec COMMAND {} +.
+func (p *findParser) exec() (findExpr, error) {
+	start := p.pos
+	for ; p.pos < len(p.args); p.pos++ {
+		arg := p.args[p.pos]
+		if arg != ";" && (arg != "+" || p.pos == start || p.args[p.pos-1] != "{}") {
+			continue
+		}
+		command := p.args[start:p.pos]
+		p.pos++
+		if len(command) == 0 {
+			break
+		}
+		if arg == "+" {
+			return p.execBatch(command[:len(command)-1])
+		}
+		w := p.w
+		return func(entry *findEntry) bool {
+			args := make([]string, len(command))
+			for i, part := range command {
+				args[i] = strings.ReplaceAll(part, "{}", entry.name)
+			}
+			return w.run(args)
+		}, nil
+	}
+	return nil, errors.New("find: отсутствует аргумент для '-exec'")
+}
+
+// execBatch создаёт действие -exec ... {} +: пути накапливаются и
+// передаются команде пачками; действие всегда истинно.
+func (p *findParser) execBatch(command []string) (findExpr, error) {
+	if len(command) == 0 {
+		return nil, errors.New("find: отсутствует аргумент для '-exec'")
+	}
+	for _, arg := range command {
+		if strings.Contains(arg, "{}") {
+			return nil, errors.New("find: в -exec ... + допускается только один '{}' перед '+'")
+		}
+	}
+
+	w := p.w
+	batch := &findBatch{command: command}
+	w.batches = append(w.batches, batch)
+	return func(entry *findEntry) bool {
+		if batch.size+len(entry.name) > findExecLimit {
+			w.runBatch(batch)
+		}
+		batch.paths = append(batch.paths, entry.name)
+		batch.size += len(entry.name) + 1
+		return true
+	}, nil
+}
+
+// parseFindType разбирает -type: одну или несколько букв через запятую.
+func parseFindType(value string) (findExpr, error) {
+	var types []byte
+	for _, part := range strings.Split(value, ",") {
+		if len(part) != 1 || !strings.Contains("fdlpsbc", part) {
+			return nil, fmt.Errorf("find: -type: неизвестный тип '%s'", value)
+		}
+		types = append(types, part[0])
+	}
+
+	return func(entry *findEntry) bool {
+		mode := entry.info.Mode()
+		for _, t := range types {
+			var ok bool
+			switch t {
+			case 'f':
+				ok = mode.IsRegular()
+			case 'd':
+				ok = mode.IsDir()
+			case 'l':
+				ok = mode&fs.ModeSymlink != 0
+			case 'p':
+				ok = mode&fs.ModeNamedPipe != 0
+			case 's':
+				ok = mode&fs.ModeSocket != 0
+			case 'c':
+				ok = mode&fs.ModeCharDevice != 0
+			case 'b':
+				ok = mode&fs.ModeDevice != 0 && mode&fs.ModeCharDevice == 0
+			}
+			if ok {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// parseFindSize разбирает -size: размер сравнивается в заданных единицах
+// с округлением вверх, поэтому -size -1M находит только пустые файлы.
+func parseFindSize(value string) (findExpr, error) {
+	unit := findSizeUnits['b']
+	digits := value
+	if n := len(value); n > 0 {
+		if size, ok := findSizeUnits[value[n-1]]; ok {
+			unit, digits = size, value[:n-1]
+		}
+	}
+	number, err := parseFindNumber("-size", digits)
+	if err != nil {
+		return nil, fmt.Errorf("find: -size: неверный размер '%s'", value)
+	}
+
+	return func(entry *findEntry) bool {
+		size := entry.info.Size()
+		return number.match((size + unit - 1) / unit)
+	}, nil
+}
+
+// parseFindNumber разбирает число со знаком сравнения: +N, -N или N.
+func parseFindNumber(token, value string) (findNumber, error) {
+	var number findNumber
+	switch {
+	case strings.HasPrefix(value, "+"):
+		number.cmp, value = 1, value[1:]
+	case strings.HasPrefix(value, "-"):
+		number.cmp, value = -1, value[1:]
+	}
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 || value == "" || !isDigit(value[0]) {
+		return number, fmt.Errorf("find: %s: неверное число '%s'", token, value)
+	}
+	number.n = n
+	return number, nil
+}
+
+// findEmpty — проверка -empty: пустой обычный файл или каталог без элементов.
+func findEmpty(entry *findEntry) bool {
+	switch {
+	case entry.info.Mode().IsRegular():
+		return entry.info.Size() == 0
+	case entry.info.IsDir():
+		dir, err := os.Open(entry.path)
+		if err != nil {
+			return false
+		}
+		defer func() {
+			_ = dir.Close()
+		}()
+		names, _ := dir.Readdirnames(1)
+		return len(names) == 0
+	}
+	return false
+}
@@ -0,0 +1,218 @@
+package commands
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
+)
+
+// runUtilities выполняет команду, как runCommand, но с ctx.Run, который
+// запускает встроенные команды echo, cat, test, rm и pwd; для остальных
+// имён возвращается код 127.
+func runUtilities(t *testing.T, cmd CommandExecutor, dir, stdin string, args ...string) (string, string, int) {
+	t.Helper()
+	builtins := map[string]CommandExecutor{
+		"echo": &EchoCommand{},
+		"cat":  &CatCommand{},
+		"test": &TestCommand{},
+		"rm":   &RmCommand{},
+		"pwd":  &PwdCommand{},
+	}
+
+	var stdout, stderr bytes.Buffer
+	ctx := &CommandContext{
+		Stdin:  strings.NewReader(stdin),
+		Stdout: &stdout,
+		Stderr: &stderr,
+		Vars:   session.NewVariables(nil),
+		Dir:    dir,
+		Run: func(name string, args []string, ctx *CommandContext) int {
+			builtin, ok := builtins[name]
+			if !ok {
+				return utilityNotFound
+			}
+			return testStatus(builtin.Exec(args, ctx))
+		},
+	}
+	err := cmd.Exec(args, ctx)
+	return stdout.String(), stderr.String(), testStatus(err)
+}
+
+func TestFindCommand(t *testing.T) {
+	dir := t.TempDir()
+	makeTree(t, dir, map[string]string{
+		"README.md":           "readme",
+		"main.go":             "package main",
+		".hidden":             "",
+		"src/app.go":          strings.Repeat("x", 1500),
+		"src/App_test.GO":     "",
+		"src/lib/util.go":     "util",
+		"vendor/dep/dep.go":   "dep",
+		"empty/":              "",
+		"docs/guide/intro.md": "intro",
+	})
+
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"src"}, "src\nsrc/App_test.GO\nsrc/app.go\nsrc/lib\nsrc/lib/util.go\n"},
+		{[]string{"src/", "-maxdepth", "1"}, "src/\nsrc/App_test.GO\nsrc/app.go\nsrc/lib\n"},
+		{[]string{".", "-name", "*.go"}, "./main.go\n./src/app.go\n./src/lib/util.go\n./vendor/dep/dep.go\n"},
+		{[]string{"src", "-iname", "*.go", "-type", "f"}, "src/App_test.GO\nsrc/app.go\nsrc/lib/util.go\n"},
+		{[]string{"-path", "./src/*/*.go"}, "./src/lib/util.go\n"},
+		{[]string{"-name", ".*", "-type", "f"}, "./.hidden\n"},
+		{[]string{"-type", "d", "-mindepth", "2"}, "./docs/guide\n./src/lib\n./vendor/dep\n"},
+		{[]string{"-type", "d", "-empty"}, "./empty\n"},
+		{[]string{"-name", "vendor", "-prune", "-o", "-name", "*.go", "-print"}, "./main.go\n./src/app.go\n./src/lib/util.go\n"},
+		{[]string{"src", "docs", "-type", "f", "!", "-name", "*.go"}, "src/App_test.GO\ndocs/guide/intro.md\n"},
+		{
+			[]string{"-name", "*.md", "-o", "(", "-name", "*.go", "-not", "-path", "*/src/*", ")"},
+			"./README.md\n./docs/guide/intro.md\n./main.go\n./vendor/dep/dep.go\n",
+		},
+		{[]string{"-type", "f", "-size", "+2"}, "./src/app.go\n"},
+		{[]string{"-type", "f", "-size", "-7c", "-size", "+3c"}, "./README.md\n./docs/guide/intro.md\n./src/lib/util.go\n"},
+		{[]string{"-type", "f", "-size", "-1k", "-iname", "*.go"}, "./src/App_test.GO\n"},
+		{[]string{"src", "-depth", "-name", "*"}, "src/App_test.GO\nsrc/app.go\nsrc/lib/util.go\nsrc/lib\nsrc\n"},
+		{[]string{"src/lib", "-print0"}, "src/lib\x00src/lib/util.go\x00"},
+		{[]string{"main.go", "-false"}, ""},
+	}
+	for _, tt := range tests {
+		out, stderr, status := runCommand(t, &FindCommand{}, dir, "", tt.args...)
+		if status != 0 || stderr != "" {
+			t.Errorf("%v: ожидался код 0, получено %d: %q", tt.args, status, stderr)
+		}
+		if out != tt.expected {
+			t.Errorf("%v: ожидалось %q, получено %q", tt.args, tt.expected, out)
+		}
+	}
+}
+
+func TestFindCommand_Time(t *testing.T) {
+	dir := t.TempDir()
+	makeTree(t, dir, map[string]string{"old.log": "", "recent.log": "", "new.log": ""})
+	now := time.Now()
+	for name, age := range map[string]time.Duration{"old.log": 10 * 24 * time.Hour, "recent.log": 30 * time.Hour, "new.log": 5 * time.Minute} {
+		stamp := now.Add(-age)
+		if err := os.Chtimes(filepath.Join(dir, name), stamp, stamp); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"-type", "f", "-mtime", "+7"}, "./old.log\n"},
+		{[]string{"-type", "f", "-mtime", "1"}, "./recent.log\n"},
+		{[]string{"-type", "f", "-mtime", "-1"}, "./new.log\n"},
+		{[]string{"-type", "f", "-mmin", "-10"}, "./new.log\n"},
+		{[]string{"-type", "f", "-newer", "recent.log"}, "./new.log\n"},
+	}
+	for _, tt := range tests {
+		out, stderr, status := runCommand(t, &FindCommand{}, dir, "", tt.args...)
+		if status != 0 || out != tt.expected {
+			t.Errorf("%v: ожидалось %q, получено %q (код %d, %q)", tt.args, tt.expected, out, status, stderr)
+		}
+	}
+}
+
+func TestFindCommand_Exec(t *testing.T) {
+	dir := t.TempDir()
+	makeTree(t, dir, map[string]string{"a.txt": "A\n", "b.txt": "B\n", "c.md": "C\n"})
+
+	out, stderr, status := runUtilities(t, &FindCommand{}, dir, "", "-name", "*.txt", "-exec", "echo", "file:{}", ";")
+	if status != 0 || out != "file:./a.txt\nfile:./b.txt\n" {
+		t.Errorf("ожидалось построчное выполнение, получено %q (код %d, %q)", out, status, stderr)
+	}
+
+	out, _, status = runUtilities(t, &FindCommand{}, dir, "", ".", "-type", "f", "-exec", "cat", "{}", "+")
+	if status != 0 || out != "A\nB\nC\n" {
+		t.Errorf("ожидался один вызов cat для всех файлов, получено %q (код %d)", out, status)
+	}
+
+	// -exec ... ; — проверка: печатаются файлы, для которых команда успешна
+	out, _, status = runUtilities(t, &FindCommand{}, dir, "", "-type", "f", "-exec", "test", "-s", "{}", ";", "-name", "*.md", "-print")
+	if status != 0 || out != "./c.md\n" {
+		t.Errorf("ожидалось %q, получено %q (код %d)", "./c.md\n", out, status)
+	}
+
+	out, _, status = runUtilities(t, &FindCommand{}, dir, "", "-name", "*.md", "-print", "-exec", "rm", "{}", ";")
+	if status != 0 || out != "./c.md\n" {
+		t.Errorf("ожидалось %q, получено %q (код %d)", "./c.md\n", out, status)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "c.md")); !os.IsNotExist(err) {
+		t.Errorf("файл должен быть удалён командой -exec rm")
+	}
+
+	_, stderr, status = runUtilities(t, &FindCommand{}, dir, "", "-name", "a.txt", "-exec", "missing-tool", "{}", "+")
+	if status != 1 || !strings.Contains(stderr, "find: 'missing-tool': команда не найдена") {
+		t.Errorf("ожидалась ошибка запуска, получено %d: %q", status, stderr)
+	}
+}
+
+func TestFindCommand_Delete(t *testing.T) {
+	dir := t.TempDir()
+	makeTree(t, dir, map[string]string{"keep.go": "", "build/x.o": "", "build/sub/y.o": "", "build/sub/z.txt": ""})
+
+	_, stderr, status := runCommand(t, &FindCommand{}, dir, "", "build", "-name", "*.o", "-delete")
+	if status != 0 || stderr != "" {
+		t.Fatalf("ожидался код 0, получено %d: %q", status, stderr)
+	}
+	expected := map[string]string{"keep.go": "", "build/": "", "build/sub/": "", "build/sub/z.txt": ""}
+	if got := readTree(t, dir); !equalTrees(got, expected) {
+		t.Errorf("ожидалось %v, получено %v", expected, got)
+	}
+
+	// -delete обходит каталог в глубину, поэтому каталоги удаляются после содержимого
+	out, stderr, status := runCommand(t, &FindCommand{}, dir, "", "build", "-print", "-delete")
+	if status != 0 || stderr != "" {
+		t.Fatalf("ожидался код 0, получено %d: %q", status, stderr)
+	}
+	if out != "build/sub/z.txt\nbuild/sub\nbuild\n" {
+		t.Errorf("ожидалось %q, получено %q", "build/sub/z.txt\nbuild/sub\nbuild\n", out)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "build")); !os.IsNotExist(err) {
+		t.Errorf("каталог build должен быть удалён")
+	}
+}
+
+func TestFindCommand_Errors(t *testing.T) {
+	dir := t.TempDir()
+	makeTree(t, dir, map[string]string{"a.txt": ""})
+
+	out, stderr, status := runCommand(t, &FindCommand{}, dir, "", "missing", "a.txt")
+	if status != 1 || out != "a.txt\n" || !strings.Contains(stderr, "find: 'missing':") {
+		t.Errorf("ожидалась ошибка для отсутствующего пути, получено %d: %q, %q", status, out, stderr)
+	}
+
+	tests := []struct {
+		args    []string
+		message string
+	}{
+		{[]string{"-name"}, "find: отсутствует аргумент для '-name'"},
+		{[]string{"-bogus"}, "find: неизвестный предикат '-bogus'"},
+		{[]string{"(", "-name", "a"}, "find: пропущена ')'"},
+		{[]string{"(", ")"}, "find: пустые скобки недопустимы"},
+		{[]string{"-name", "a", ")"}, "find: неожиданный аргумент ')'"},
+		{[]string{"-name", "a", "-o"}, "find: после '-o' ожидалось выражение"},
+		{[]string{"-type", "x"}, "find: -type: неизвестный тип 'x'"},
+		{[]string{"-size", "1q"}, "find: -size: неверный размер '1q'"},
+		{[]string{"-mtime", "abc"}, "find: -mtime: неверное число 'abc'"},
+		{[]string{"-maxdepth", "-1"}, "find: -maxdepth: неверное число '-1'"},
+		{[]string{"-exec", "echo", "{}"}, "find: отсутствует аргумент для '-exec'"},
+		{[]string{"-exec", "echo", "{}", "{}", "+"}, "допускается только один '{}'"},
+		{[]string{"-newer", "missing"}, "find: 'missing':"},
+	}
+	for _, tt := range tests {
+		_, stderr, status := runCommand(t, &FindCommand{}, dir, "", tt.args...)
+		if status != 1 || !strings.Contains(stderr, tt.message) {
+			t.Errorf("%v: ожидалась ошибка %q, получено %d: %q", tt.args, tt.message, status, stderr)
+		}
+	}
+}
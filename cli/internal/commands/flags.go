@@ -0,0 +1,192 @@
+package commands
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// splitShortFlags разделяет объединённые короткие флаги для пакета flag,
+// который их не поддерживает: "-in" → "-i -n", "-m5" → "-m 5".
+// valueFlags перечисляет флаги, принимающие значение: остаток аргумента
+// после такого флага (или следующий аргумент) считается его значением.
+// Последовательность цифр внутри флагов не разделяется и остаётся
+// отдельным флагом: "-n15" → "-n -15" (так grep задаёт контекст -NUM).
+// Разбор прекращается на "--" и на первом аргументе, не являющемся флагом,
+// как и в пакете flag. Длинные флаги ("--name=value") не изменяются.
+func splitShortFlags(args []string, valueFlags string) []string {
+	result := make([]string, 0, len(args))
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" || arg == "-" || !strings.HasPrefix(arg, "-") {
+			return append(result, args[i:]...)
+		}
+		if strings.HasPrefix(arg, "--") || len(arg) == 2 {
+			result = append(result, arg)
+			if len(arg) == 2 && strings.ContainsRune(valueFlags, rune(arg[1])) && i+1 < len(args) {
+				i++
+				result = append(result, args[i])
+			}
+			continue
+		}
+
+		for j := 1; j < len(arg); j++ {
+			if isDigit(arg[j]) {
+				end := j
+				for end < len(arg) && isDigit(arg[end]) {
+					end++
+				}
+				result = append(result, "-"+arg[j:end])
+				j = end - 1
+				continue
+			}
+
+			result = append(result, "-"+arg[j:j+1])
+			if !strings.ContainsRune(valueFlags, rune(arg[j])) {
+				continue
+			}
+
+			if j+1 < len(arg) {
+				result = append(result, arg[j+1:])
+			} else if i+1 < len(args) {
+				i++
+				result = append(result, args[i])
+			}
+			break
+		}
+	}
+
+	return result
+}
+
+// numericFlags заменяет флаги вида -NUM на -name NUM: так grep задаёт
+// контекст (-5 → -C 5), а head и tail — количество строк (-5 → -n 5).
+// Значения флагов из valueFlags не изменяются: -m -1 остаётся как есть.
+func numericFlags(args []string, name, valueFlags string) []string {
+	result := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" || !strings.HasPrefix(arg, "-") {
+			return append(result, args[i:]...)
+		}
+
+		if len(arg) > 1 && strings.Trim(arg[1:], "0123456789") == "" {
+			result = append(result, "-"+name, arg[1:])
+			continue
+		}
+
+		result = append(result, arg)
+		if len(arg) == 2 && strings.ContainsRune(valueFlags, rune(arg[1])) && i+1 < len(args) {
+			i++
+			result = append(result, args[i])
+		}
+	}
+	return result
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+// parseOptions разбирает args набором флагов fs и заменяет ошибки пакета
+// flag сообщениями в стиле coreutils, как у cat:
+//
+//	ls: неверная опция -- 'F'
+//	ls: нераспознанная опция '--frob'
+//	mkdir: опция требует аргумент -- 'm'
+//
+// Имя команды берётся из имени набора флагов.
+func parseOptions(fs *flag.FlagSet, args []string) error {
+	err := fs.Parse(args)
+	if err == nil {
+		return nil
+	}
+	command := fs.Name()
+
+	// -h и --help, не объявленные командой, пакет flag считает запросом справки
+	if errors.Is(err, flag.ErrHelp) {
+		for _, arg := range args {
+			if arg == "--" {
+				break
+			}
+			if name := strings.TrimLeft(arg, "-"); arg != name && (name == "h" || name == "help") {
+				return unknownOption(command, name)
+			}
+		}
+		return unknownOption(command, "help")
+	}
+
+	message := err.Error()
+	if name, ok := strings.CutPrefix(message, "flag provided but not defined: -"); ok {
+		return unknownOption(command, name)
+	}
+	if name, ok := strings.CutPrefix(message, "flag needs an argument: -"); ok {
+		if utf8.RuneCountInString(name) == 1 {
+			return fmt.Errorf("%s: опция требует аргумент -- '%s'", command, name)
+		}
+		return fmt.Errorf("%s: опция '--%s' требует аргумент", command, name)
+	}
+	if rest, ok := strings.CutPrefix(message, "invalid boolean value "); ok {
+		// Так же сообщается неверное значение флагов вроде --color[=WHEN]
+		if value, name, ok := optionValue(rest, " for -"); ok {
+			if isPlainBool(fs.Lookup(name)) {
+				return fmt.Errorf("%s: опция '--%s' не допускает аргументов", command, name)
+			}
+			return fmt.Errorf("%s: неверный аргумент '%s' для '--%s'", command, value, name)
+		}
+	}
+	if rest, ok := strings.CutPrefix(message, "invalid value "); ok {
+		if value, name, ok := optionValue(rest, " for flag -"); ok {
+			return fmt.Errorf("%s: неверный аргумент '%s' для '--%s'", command, value, name)
+		}
+	}
+	if arg, ok := strings.CutPrefix(message, "bad flag syntax: "); ok {
+		return fmt.Errorf("%s: нераспознанная опция '%s'", command, arg)
+	}
+	return fmt.Errorf("%s: %w", command, err)
+}
+
+// isPlainBool сообщает, что f — обычный логический флаг, без значения.
+func isPlainBool(f *flag.Flag) bool {
+	if f == nil {
+		return false
+	}
+	getter, ok := f.Value.(flag.Getter)
+	if !ok {
+		return false
+	}
+	_, ok = getter.Get().(bool)
+	return ok
+}
+
+// unknownOption возвращает ошибку неизвестной опции name: однобуквенные
+// опции coreutils называет "неверными", длинные — "нераспознанными".
+func unknownOption(command, name string) error {
+	if utf8.RuneCountInString(name) == 1 {
+		return fmt.Errorf("%s: неверная опция -- '%s'", command, name)
+	}
+	return fmt.Errorf("%s: нераспознанная опция '--%s'", command, name)
+}
+
+// optionValue разбирает хвост сообщения пакета flag вида
+// `"VALUE"<sep>NAME: причина` и возвращает значение и имя флага.
+func optionValue(rest, sep string) (string, string, bool) {
+	quoted, err := strconv.QuotedPrefix(rest)
+	if err != nil {
+		return "", "", false
+	}
+	value, err := strconv.Unquote(quoted)
+	if err != nil {
+		return "", "", false
+	}
+	name, ok := strings.CutPrefix(rest[len(quoted):], sep)
+	if !ok {
+		return "", "", false
+	}
+	name, _, _ = strings.Cut(name, ":")
+	return value, name, true
+}
@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitShortFlags(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected []string
+	}{
+		{"одиночные флаги", []string{"-i", "-n", "pat"}, []string{"-i", "-n", "pat"}},
+		{"объединённые флаги", []string{"-inv", "pat"}, []string{"-i", "-n", "-v", "pat"}},
+		{"значение вплотную", []string{"-cm5", "pat"}, []string{"-c", "-m", "5", "pat"}},
+		{"значение отдельным аргументом", []string{"-im", "5", "pat"}, []string{"-i", "-m", "5", "pat"}},
+		{"значение похоже на флаг", []string{"-m", "-1", "-n"}, []string{"-m", "-1", "-n"}},
+		{"длинный флаг", []string{"--include=*.go", "-rn"}, []string{"--include=*.go", "-r", "-n"}},
+		{"число как флаг", []string{"-n15", "-2"}, []string{"-n", "-15", "-2"}},
+		{"конец флагов", []string{"-i", "--", "-nv"}, []string{"-i", "--", "-nv"}},
+		{"первый не флаг", []string{"pat", "-nv"}, []string{"pat", "-nv"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitShortFlags(tt.args, "Am"); !reflect.DeepEqual(got, tt.expected) {
+				t.Fatalf("ожидалось %q, получено %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseOptions(t *testing.T) {
+	tests := []struct {
+		command  BuiltinCommand
+		args     []string
+		expected string
+	}{
+		{&LsCommand{}, []string{"-lF"}, "ls: неверная опция -- 'F'\n"},
+		{&LsCommand{}, []string{"--frob"}, "ls: нераспознанная опция '--frob'\n"},
+		{&LsCommand{}, []string{"--color=sometimes"}, "ls: неверный аргумент 'sometimes' для '--color'\n"},
+		{&MkdirCommand{}, []string{"-m"}, "mkdir: опция требует аргумент -- 'm'\n"},
+		{&MkdirCommand{}, []string{"--mode"}, "mkdir: опция '--mode' требует аргумент\n"},
+		{&CpCommand{}, []string{"-h", "a", "b"}, "cp: неверная опция -- 'h'\n"},
+		{&MvCommand{}, []string{"--force=yes", "a", "b"}, "mv: опция '--force' не допускает аргументов\n"},
+		{&TouchCommand{}, []string{"-x", "a"}, "touch: неверная опция -- 'x'\n"},
+		{&LnCommand{}, []string{"--help"}, "ln: нераспознанная опция '--help'\n"},
+		{&RmCommand{}, []string{"-rq", "a"}, "rm: неверная опция -- 'q'\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(append([]string{tt.command.Name()}, tt.args...), " "), func(t *testing.T) {
+			_, stderr, status := runCommand(t, tt.command, t.TempDir(), "", tt.args...)
+			expected := tt.expected + "Попробуйте '" + tt.command.Name() + " --help' для получения справки.\n"
+			if stderr != expected || status == 0 {
+				t.Errorf("ожидалось %q с ненулевым кодом, получено %q (код %d)", expected, stderr, status)
+			}
+		})
+	}
+}
@@ -4,12 +4,19 @@ package commands
 
 import (
 	"bufio"
+	"bytes"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"os"
-	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
 	"unicode"
+
+	customErrors "github.com/ggerlakh/software-design-mhs-itmo/cli/internal/errors"
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/linereader"
 )
 
 // GrepCommand реализует встроенную команду "grep".
@@ -18,7 +25,46 @@ import (
 // Поддерживаемые флаги:
 //   - -i — регистронезависимый поиск
 //   - -w — поиск только слова целиком
+//   - -x — совпадение со всей строкой
+//   - -v — выбирать строки, не соответствующие выражению
+//   - -c — печатать только количество выбранных строк
+//   - -n — печатать номера строк
+//   - -H, -h — печатать или не печатать имя файла
+//   - -l, -L — печатать только имена файлов с совпадениями или без них
+//   - -o — печатать только совпавшие части строк
+//   - -q — ничего не печатать, только код возврата
+//   - -s — не сообщать о несуществующих и недоступных файлах
+//   - -m NUM — остановиться после NUM выбранных строк
 //   - -A N — печатать N строк после совпадения
+//   - -B N — печатать N строк перед совпадением
+//   - -C N, -N — печатать N строк контекста с обеих сторон
+//   - -r, -R — рекурсивный поиск в каталогах (-R следует символическим ссылкам)
+//   - --include, --exclude, --exclude-dir — фильтры имён по шаблону
+//   - --gitignore — пропускать файлы, игнорируемые правилами .gitignore
+//   - -a, -I, --binary-files — обработка двоичных файлов
+//   - -e PATTERN, -f FILE — несколько шаблонов; строка выбирается по любому
+//   - -G, -E — базовые и расширенные выражения POSIX (переводятся в RE2)
+//   - -F — фиксированные строки (поиск автоматом Ахо — Корасик)
+//   - -P — подмножество PCRE (движок с возвратами, пакет pcre)
+//   - -j N — количество файлов, обрабатываемых параллельно
+//   - --color[=WHEN] — подсветка совпадений, имён файлов, номеров строк
+//     и разделителей; цвета задаются переменной GREP_COLORS
+//
+// Несколько файлов обрабатываются пулом горутин (см. grepSearch), но вывод
+// печатается в порядке файлов, как при последовательном поиске. Каждый
+// файл закрывается сразу после поиска в нём.
+//
+// Без -G, -E, -F и -P шаблон — регулярное выражение Go (RE2).
+//
+// Файл считается двоичным, если в его начале или в очередной строке
+// встречается нулевой байт. Вместо строк двоичного файла печатается
+// "Binary file NAME matches".
+//
+// Несмежные группы строк с контекстом разделяются строкой "--".
+// Строки перед совпадением хранятся в кольцевом буфере размером N,
+// поэтому вход любого размера обрабатывается потоково.
+//
+// Код возврата: 0 — есть выбранные строки, 1 — нет, 2 — произошла ошибка.
 //
 // Для разбора аргументов используется стандартная библиотека flag.
 // Выбор обоснования:
@@ -26,8 +72,39 @@ import (
 //   - Достаточно мощная для поддержки необходимых флагов
 //   - Хорошо документирована и широко используется в Go-проектах
 //   - Поддерживает создание локального FlagSet для избежания конфликтов
+//
+// Объединённые короткие флаги (-in, -m5) предварительно разделяются
+// функцией splitShortFlags.
 type GrepCommand struct{}
 
+// Коды возврата grep.
+const (
+	grepMatch   = 0 // выбрана хотя бы одна строка
+	grepNoMatch = 1 // не выбрано ни одной строки
+	grepTrouble = 2 // ошибка: неверные аргументы, недоступный файл
+)
+
+// grepStdinName — имя, под которым в выводе показывается стандартный ввод.
+const grepStdinName = "(standard input)"
+
+// grepValueFlags перечисляет короткие флаги grep, принимающие значение.
+const grepValueFlags = "ABCmefj"
+
+// Режимы обработки двоичных файлов (--binary-files).
+const (
+	grepBinary             = "binary"        // сообщать о совпадении без вывода строк
+	grepBinaryText         = "text"          // -a: обрабатывать как текст
+	grepBinaryWithoutMatch = "without-match" // -I: считать, что совпадений нет
+)
+
+// grepBinaryPeek — размер начала файла, проверяемого на нулевые байты.
+// Совпадает с буфером linereader, поэтому буфер проверки используется
+// и для чтения строк.
+const grepBinaryPeek = linereader.BufferSize
+
+// errGrepQuit прерывает обход файлов после первого совпадения с -q.
+var errGrepQuit = errors.New("grep: найдено совпадение")
+
 // Name возвращает имя команды.
 func (g *GrepCommand) Name() string {
 	return "grep"
@@ -35,14 +112,41 @@ func (g *GrepCommand) Name() string {
 
 // grepFlags содержит распарсенные флаги для команды grep.
 type grepFlags struct {
-	ignoreCase bool // -i: регистронезависимый поиск
-	wordMatch  bool // -w: поиск только слова целиком
-	afterLines int  // -A: количество строк после совпадения
+	ignoreCase        bool        // -i: регистронезависимый поиск
+	wordMatch         bool        // -w: поиск только слова целиком
+	lineMatch         bool        // -x: совпадение со всей строкой
+	invert            bool        // -v: выбирать несовпадающие строки
+	count             bool        // -c: печатать количество выбранных строк
+	lineNumber        bool        // -n: печатать номера строк
+	withFilename      bool        // -H: печатать имя файла
+	noFilename        bool        // -h: не печатать имя файла
+	filesWithMatches  bool        // -l: печатать имена файлов с совпадениями
+	filesWithoutMatch bool        // -L: печатать имена файлов без совпадений
+	onlyMatching      bool        // -o: печатать только совпавшие части
+	quiet             bool        // -q: ничего не печатать
+	noMessages        bool        // -s: не сообщать об ошибках чтения файлов
+	maxCount          int         // -m: максимум выбранных строк, -1 — без ограничения
+	afterLines        int         // -A: количество строк после совпадения
+	beforeLines       int         // -B: количество строк перед совпадением
+	recursive         bool        // -r: рекурсивный обход каталогов
+	dereference       bool        // -R: -r со следованием символическим ссылкам
+	include           stringList  // --include: искать только в подходящих файлах
+	exclude           stringList  // --exclude: пропускать подходящие файлы
+	excludeDir        stringList  // --exclude-dir: пропускать подходящие каталоги
+	gitignore         bool        // --gitignore: учитывать файлы .gitignore
+	binaryFiles       string      // --binary-files: режим двоичных файлов
+	syntax            int         // синтаксис шаблонов: -G, -E, -F или -P
+	patterns          stringList  // -e: шаблоны поиска
+	patternFiles      stringList  // -f: файлы с шаблонами
+	jobs              int         // -j: количество параллельно обрабатываемых файлов
+	color             colorMode   // --color: режим подсветки
+	colors            *grepColors // цвета подсветки, nil — без подсветки
 }
 
 // parseGrepFlags разбирает аргументы командной строки для grep.
-// Возвращает структуру с флагами, паттерн поиска и список файлов.
-func parseGrepFlags(args []string) (*grepFlags, string, []string, error) {
+// Возвращает структуру с флагами, шаблоны из -e или первого аргумента
+// и список файлов. Шаблоны из файлов -f читаются отдельно, в grepPatterns.
+func parseGrepFlags(args []string) (*grepFlags, []string, []string, error) {
 	fs := flag.NewFlagSet("grep", flag.ContinueOnError)
 
 	// Создаём буфер для подавления вывода usage при ошибках парсинга
@@ -51,42 +155,97 @@ func parseGrepFlags(args []string) (*grepFlags, string, []string, error) {
 	flags := &grepFlags{}
 	fs.BoolVar(&flags.ignoreCase, "i", false, "регистронезависимый поиск")
 	fs.BoolVar(&flags.wordMatch, "w", false, "поиск только слова целиком")
+	fs.BoolVar(&flags.lineMatch, "x", false, "совпадение со всей строкой")
+	fs.BoolVar(&flags.invert, "v", false, "выбирать несовпадающие строки")
+	fs.BoolVar(&flags.count, "c", false, "печатать количество выбранных строк")
+	fs.BoolVar(&flags.lineNumber, "n", false, "печатать номера строк")
+	fs.BoolVar(&flags.withFilename, "H", false, "печатать имя файла")
+	fs.BoolVar(&flags.noFilename, "h", false, "не печатать имя файла")
+	fs.BoolVar(&flags.filesWithMatches, "l", false, "печатать имена файлов с совпадениями")
+	fs.BoolVar(&flags.filesWithoutMatch, "L", false, "печатать имена файлов без совпадений")
+	fs.BoolVar(&flags.onlyMatching, "o", false, "печатать только совпавшие части")
+	fs.BoolVar(&flags.quiet, "q", false, "ничего не печатать")
+	fs.BoolVar(&flags.noMessages, "s", false, "не сообщать об ошибках чтения файлов")
+	fs.IntVar(&flags.maxCount, "m", -1, "остановиться после NUM выбранных строк")
 	fs.IntVar(&flags.afterLines, "A", 0, "количество строк после совпадения")
-
-	if err := fs.Parse(args); err != nil {
-		return nil, "", nil, fmt.Errorf("ошибка разбора флагов: %w", err)
+	fs.IntVar(&flags.beforeLines, "B", 0, "количество строк перед совпадением")
+	contextLines := fs.Int("C", 0, "количество строк контекста")
+	fs.BoolVar(&flags.recursive, "r", false, "рекурсивный поиск")
+	fs.BoolVar(&flags.dereference, "R", false, "рекурсивный поиск со следованием ссылкам")
+	fs.Var(&flags.include, "include", "искать только в файлах, подходящих под шаблон")
+	fs.Var(&flags.exclude, "exclude", "пропускать файлы, подходящие под шаблон")
+	fs.Var(&flags.excludeDir, "exclude-dir", "пропускать каталоги, подходящие под шаблон")
+	fs.BoolVar(&flags.gitignore, "gitignore", false, "учитывать файлы .gitignore")
+	fs.StringVar(&flags.binaryFiles, "binary-files", grepBinary, "режим двоичных файлов")
+	text := fs.Bool("a", false, "обрабатывать двоичные файлы как текст")
+	noBinary := fs.Bool("I", false, "пропускать двоичные файлы")
+	fs.Var(&flags.patterns, "e", "шаблон поиска")
+	fs.Var(&flags.patternFiles, "f", "файл с шаблонами")
+	flags.color = grepColorNever
+	fs.Var(&flags.color, "color", "режим подсветки: auto, always или never")
+	fs.Var(&flags.color, "colour", "режим подсветки: auto, always или never")
+	fs.IntVar(&flags.jobs, "j", runtime.GOMAXPROCS(0), "количество параллельно обрабатываемых файлов")
+	syntaxes := map[int]*bool{
+		grepSyntaxBasic:    fs.Bool("G", false, "базовые регулярные выражения POSIX"),
+		grepSyntaxExtended: fs.Bool("E", false, "расширенные регулярные выражения POSIX"),
+		grepSyntaxFixed:    fs.Bool("F", false, "фиксированные строки"),
+		grepSyntaxPerl:     fs.Bool("P", false, "регулярные выражения Perl"),
 	}
 
-	remaining := fs.Args()
-	if len(remaining) < 1 {
-		return nil, "", nil, fmt.Errorf("grep: отсутствует паттерн для поиска")
+	if err := fs.Parse(numericFlags(splitShortFlags(args, grepValueFlags), "C", grepValueFlags)); err != nil {
+		return nil, nil, nil, fmt.Errorf("ошибка разбора флагов: %w", err)
 	}
 
-	pattern := remaining[0]
-	files := remaining[1:]
-
-	return flags, pattern, files, nil
-}
+	// -C задаёт контекст с обеих сторон, но явные -A и -B важнее
+	explicit := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+	if !explicit["A"] {
+		flags.afterLines = *contextLines
+	}
+	if !explicit["B"] {
+		flags.beforeLines = *contextLines
+	}
+	if flags.afterLines < 0 || flags.beforeLines < 0 {
+		return nil, nil, nil, fmt.Errorf("grep: неверная длина контекста")
+	}
+	if flags.jobs < 1 {
+		return nil, nil, nil, fmt.Errorf("grep: неверное количество потоков: %d", flags.jobs)
+	}
 
-// buildRegexp создаёт скомпилированное регулярное выражение на основе флагов.
-// Если включён флаг -w, паттерн оборачивается в word boundaries.
-// Если включён флаг -i, включается регистронезависимый режим.
-func buildRegexp(pattern string, flags *grepFlags) (*regexp.Regexp, error) {
-	// Примечание: для флага -w мы проверяем границы слов вручную в функции matchesWord,
-	// так как \b в Go regexp не полностью поддерживает Unicode.
-	// Паттерн не модифицируется здесь для -w.
+	flags.recursive = flags.recursive || flags.dereference
+	switch {
+	case *text:
+		flags.binaryFiles = grepBinaryText
+	case *noBinary:
+		flags.binaryFiles = grepBinaryWithoutMatch
+	}
+	switch flags.binaryFiles {
+	case grepBinary, grepBinaryText, grepBinaryWithoutMatch:
+	default:
+		return nil, nil, nil, fmt.Errorf("grep: неизвестный тип двоичных файлов %q", flags.binaryFiles)
+	}
 
-	// Если включён регистронезависимый поиск, добавляем флаг (?i)
-	if flags.ignoreCase {
-		pattern = "(?i)" + pattern
+	for syntax, set := range syntaxes {
+		if !*set {
+			continue
+		}
+		if flags.syntax != grepSyntaxRE2 {
+			return nil, nil, nil, fmt.Errorf("grep: указано несколько синтаксисов шаблонов")
+		}
+		flags.syntax = syntax
 	}
 
-	re, err := regexp.Compile(pattern)
-	if err != nil {
-		return nil, fmt.Errorf("grep: некорректное регулярное выражение: %w", err)
+	// С -e или -f все оставшиеся аргументы — файлы
+	files := fs.Args()
+	patterns := []string(flags.patterns)
+	if len(flags.patterns) == 0 && len(flags.patternFiles) == 0 {
+		if len(files) < 1 {
+			return nil, nil, nil, fmt.Errorf("grep: отсутствует паттерн для поиска")
+		}
+		patterns, files = files[:1], files[1:]
 	}
 
-	return re, nil
+	return flags, patterns, files, nil
 }
 
 // isWordChar проверяет, является ли руна "word constituent character".
@@ -124,85 +283,345 @@ func matchesWord(line string, match []int) bool {
 	return true
 }
 
+// findMatches возвращает позиции совпадений в строке.
+// Если включён флаг -w, остаются только совпадения, являющиеся словом целиком.
+func findMatches(line string, matcher grepMatcher, wordMatch bool) [][]int {
+	matches := matcher.FindAllStringIndex(line, -1)
+	if !wordMatch {
+		return matches
+	}
+
+	accepted := matches[:0]
+	for _, match := range matches {
+		if matchesWord(line, match) {
+			accepted = append(accepted, match)
+		}
+	}
+	return accepted
+}
+
 // lineMatches проверяет, соответствует ли строка регулярному выражению.
 // Если включён флаг -w, проверяется также, что совпадение — слово целиком.
-func lineMatches(line string, re *regexp.Regexp, wordMatch bool) bool {
-	if !wordMatch {
-		return re.MatchString(line)
+// Флаг -x проверяется самим выражением, поэтому -w при нём не учитывается.
+func lineMatches(line string, matcher grepMatcher, flags *grepFlags) bool {
+	if !flags.wordMatch || flags.lineMatch {
+		return matcher.MatchString(line)
 	}
 
 	// Для режима -w находим все совпадения и проверяем каждое
-	matches := re.FindAllStringIndex(line, -1)
+	return len(findMatches(line, matcher, true)) > 0
+}
+
+// grepPrinter печатает строки результата с префиксами имени файла
+// и номера строки. Если заданы colors, префиксы, разделители
+// и совпадения подсвечиваются.
+type grepPrinter struct {
+	writer     io.Writer
+	name       string
+	showName   bool
+	lineNumber bool
+
+	colors    *grepColors // nil — без подсветки
+	matcher   grepMatcher // для поиска подсвечиваемых совпадений
+	wordMatch bool        // подсвечивать только совпадения-слова (-w)
+	invert    bool        // -v: совпадения есть в строках контекста
+}
+
+// print печатает строку text с номером num. sep — разделитель префикса:
+// ':' для выбранных строк и '-' для строк контекста.
+func (p *grepPrinter) print(num int, sep byte, text string) error {
+	var matches [][]int
+	// Без -v совпадения есть только в выбранных строках, с -v — только в контексте.
+	// findMatches с -w возвращает лишь принятые совпадения, их и подсвечиваем.
+	if p.colors != nil && (sep == ':') != p.invert {
+		matches = findMatches(text, p.matcher, p.wordMatch)
+	}
+	return p.write(num, sep, text, matches)
+}
+
+// printMatch печатает совпавшую часть строки (-o), подсвечивая её целиком.
+func (p *grepPrinter) printMatch(num int, text string) error {
+	return p.write(num, ':', text, [][]int{{0, len(text)}})
+}
+
+// write печатает строку с префиксом, подсвечивая части matches.
+func (p *grepPrinter) write(num int, sep byte, text string, matches [][]int) error {
+	c := p.palette()
+
+	var b strings.Builder
+	if p.showName {
+		b.WriteString(c.paint(c.fileName, p.name))
+		b.WriteString(c.paint(c.separator, string(sep)))
+	}
+	if p.lineNumber {
+		b.WriteString(c.paint(c.lineNumber, strconv.Itoa(num)))
+		b.WriteString(c.paint(c.separator, string(sep)))
+	}
+
+	lineColor, matchColor := c.lineColors(sep == ':', p.invert)
+	last := 0
 	for _, match := range matches {
-		if matchesWord(line, match) {
-			return true
+		if match[0] == match[1] {
+			continue
 		}
+		b.WriteString(c.paint(lineColor, text[last:match[0]]))
+		b.WriteString(c.paint(matchColor, text[match[0]:match[1]]))
+		last = match[1]
 	}
+	b.WriteString(c.paint(lineColor, text[last:]))
+	b.WriteByte('\n')
 
-	return false
+	_, err := io.WriteString(p.writer, b.String())
+	return err
+}
+
+// printGroupSeparator печатает разделитель несмежных групп строк "--".
+func (p *grepPrinter) printGroupSeparator() error {
+	c := p.palette()
+	_, err := io.WriteString(p.writer, c.paint(c.separator, "--")+"\n")
+	return err
+}
+
+// printName печатает имя файла (-l, -L), а если задан count — имя
+// и количество выбранных строк (-c).
+func (p *grepPrinter) printName(count string) error {
+	c := p.palette()
+	line := c.paint(c.fileName, p.name)
+	if count != "" {
+		line += c.paint(c.separator, ":") + count
+	}
+	_, err := io.WriteString(p.writer, line+"\n")
+	return err
 }
 
-// grepReader выполняет grep по содержимому reader и выводит результат в writer.
+// palette возвращает цвета подсветки; без подсветки — пустые цвета,
+// с которыми paint не меняет текст.
+func (p *grepPrinter) palette() *grepColors {
+	if p.colors == nil {
+		return &grepColors{}
+	}
+	return p.colors
+}
+
+// grepReader выполняет grep по содержимому reader и выводит результат через out.
+// Возвращает количество выбранных строк.
 // Параметры:
 //   - reader: источник данных для поиска
-//   - writer: куда выводить результаты
-//   - re: скомпилированное регулярное выражение
+//   - out: куда и с какими префиксами выводить результаты
+//   - matcher: скомпилированные шаблоны
 //   - flags: флаги команды grep
-func grepReader(reader io.Reader, writer io.Writer, re *regexp.Regexp, flags *grepFlags) error {
-	scanner := bufio.NewScanner(reader)
+//   - binary: начало данных уже распознано как двоичное
+func grepReader(reader io.Reader, out *grepPrinter, matcher grepMatcher, flags *grepFlags, binary bool) (selected int, err error) {
+	// Ошибка сопоставления -P прерывает поиск только в этом файле
+	defer func() {
+		if r := recover(); r != nil {
+			failure, ok := r.(perlMatchError)
+			if !ok {
+				panic(r)
+			}
+			err = failure.err
+		}
+	}()
 
-	// Сохраняем все строки для поддержки -A (after context)
-	var lines []string
-	for scanner.Scan() {
-		lines = append(lines, scanner.Text())
+	if binary && flags.binaryFiles == grepBinaryWithoutMatch {
+		return 0, nil
 	}
-
-	if err := scanner.Err(); err != nil {
-		return fmt.Errorf("grep: ошибка чтения: %w", err)
+	lines := linereader.New(reader)
+
+	// Печатать строки не нужно: достаточно количества или факта совпадения
+	silent := flags.quiet || flags.count || flags.filesWithMatches || flags.filesWithoutMatch
+	// Для -q, -l и -L достаточно первой выбранной строки
+	firstOnly := flags.quiet || flags.filesWithMatches || flags.filesWithoutMatch
+	// С -o строки контекста не печатаются
+	withContext := !silent && !flags.onlyMatching && (flags.afterLines > 0 || flags.beforeLines > 0)
+
+	printer := &grepContextPrinter{out: out, separate: withContext}
+	before := newLineRing(0)
+	if withContext {
+		before = newLineRing(flags.beforeLines)
 	}
 
-	// Множество для отслеживания уже напечатанных строк
-	// (для обработки пересекающихся областей печати)
-	printed := make(map[int]bool)
+	afterRemaining := 0 // сколько строк контекста -A осталось напечатать
 
-	for i, line := range lines {
-		if lineMatches(line, re, flags.wordMatch) {
-			// Печатаем совпавшую строку
-			if !printed[i] {
-				if _, err := fmt.Fprintln(writer, line); err != nil {
-					return err
-				}
-				printed[i] = true
+	for num := 1; ; num++ {
+		raw, err := lines.ReadLine()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return selected, err
+		}
+
+		// Строка сравнивается без "\n", "\r" в конце сохраняется, как в GNU grep
+		line := string(linereader.TrimNewline(raw))
+		limitReached := flags.maxCount >= 0 && selected >= flags.maxCount
+
+		// Нулевой байт может встретиться и после проверенного начала файла
+		if !binary && flags.binaryFiles != grepBinaryText && strings.IndexByte(line, 0) >= 0 {
+			binary = true
+			if flags.binaryFiles == grepBinaryWithoutMatch {
+				return selected, nil
 			}
+		}
 
-			// Печатаем строки после совпадения (флаг -A)
-			for j := 1; j <= flags.afterLines && i+j < len(lines); j++ {
-				lineIdx := i + j
-				if !printed[lineIdx] {
-					if _, err := fmt.Fprintln(writer, lines[lineIdx]); err != nil {
-						return err
-					}
-					printed[lineIdx] = true
-				}
+		if !limitReached && lineMatches(line, matcher, flags) != flags.invert {
+			selected++
+			if firstOnly {
+				return selected, nil
 			}
+			if silent {
+				continue
+			}
+
+			// Строки двоичного файла не печатаются: достаточно сообщения
+			if binary && flags.binaryFiles == grepBinary {
+				_, err := fmt.Fprintf(out.writer, "Binary file %s matches\n", out.name)
+				return selected, err
+			}
+
+			// Сначала печатаем накопленный контекст -B
+			if err := before.drain(func(l contextLine) error {
+				return printer.print(l.num, '-', l.text)
+			}); err != nil {
+				return selected, err
+			}
+			if err := printSelected(printer, num, line, matcher, flags); err != nil {
+				return selected, err
+			}
+			afterRemaining = flags.afterLines
+			continue
+		}
+
+		if !withContext {
+			if limitReached {
+				return selected, nil
+			}
+			continue
+		}
+
+		if afterRemaining > 0 {
+			afterRemaining--
+			if err := printer.print(num, '-', line); err != nil {
+				return selected, err
+			}
+			continue
+		}
+
+		// После достижения лимита -m печатается только оставшийся контекст -A
+		if limitReached {
+			return selected, nil
 		}
+		before.push(num, line)
 	}
 
+	return selected, nil
+}
+
+// printSelected печатает выбранную строку или, при -o, её совпавшие части.
+func printSelected(out *grepContextPrinter, num int, line string, matcher grepMatcher, flags *grepFlags) error {
+	if !flags.onlyMatching {
+		return out.print(num, ':', line)
+	}
+
+	// Для инвертированного поиска совпавших частей нет
+	if flags.invert {
+		return nil
+	}
+
+	for _, match := range findMatches(line, matcher, flags.wordMatch && !flags.lineMatch) {
+		if match[0] == match[1] {
+			continue
+		}
+		if err := out.printMatch(num, line[match[0]:match[1]]); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// grepFile ищет совпадения в файле fname ("-" — стандартный ввод)
+// и печатает результат для этого файла. Файл закрывается сразу после поиска.
+// Возвращает количество выбранных строк.
+func grepFile(fname string, ctx *CommandContext, matcher grepMatcher, flags *grepFlags, showName bool) (int, error) {
+	out := &grepPrinter{
+		writer:     ctx.Stdout,
+		name:       fname,
+		showName:   showName,
+		lineNumber: flags.lineNumber,
+		colors:     flags.colors,
+		matcher:    matcher,
+		wordMatch:  flags.wordMatch && !flags.lineMatch,
+		invert:     flags.invert,
+	}
+
+	var reader io.Reader
+	binary := false
+	if fname == "-" {
+		out.name = grepStdinName
+		reader = ctx.Stdin
+	} else {
+		//nolint:gosec // открываем файлы, как делает обычный grep
+		file, err := os.Open(ctx.ResolvePath(fname))
+		if err != nil {
+			return 0, err
+		}
+		defer func() {
+			if closeErr := file.Close(); closeErr != nil && !flags.noMessages {
+				if _, writeErr := fmt.Fprintf(ctx.Stderr,
+					"grep: ошибка закрытия файла %s: %v\n", fname, closeErr); writeErr != nil {
+					// Игнорируем ошибку записи в stderr
+					_ = writeErr
+				}
+			}
+		}()
+		reader = file
+
+		if flags.binaryFiles != grepBinaryText {
+			buffered := bufio.NewReaderSize(file, grepBinaryPeek)
+			// Ошибка чтения повторится при сканировании и будет сообщена там
+			head, _ := buffered.Peek(grepBinaryPeek)
+			binary = bytes.IndexByte(head, 0) >= 0
+			reader = buffered
+		}
+	}
+
+	selected, err := grepReader(reader, out, matcher, flags, binary)
+	if err != nil {
+		return selected, err
+	}
+
+	switch {
+	case flags.quiet:
+	case flags.filesWithMatches:
+		if selected > 0 {
+			err = out.printName("")
+		}
+	case flags.filesWithoutMatch:
+		if selected == 0 {
+			err = out.printName("")
+		}
+	case flags.count:
+		if showName {
+			err = out.printName(strconv.Itoa(selected))
+		} else {
+			_, err = fmt.Fprintf(ctx.Stdout, "%d\n", selected)
+		}
+	}
+	return selected, err
+}
+
 // Exec выполняет команду grep с переданными аргументами.
 //
 // Синтаксис:
 //
 //	grep [OPTIONS] PATTERN [FILE...]
 //
-// Опции:
-//   - -i: регистронезависимый поиск
-//   - -w: поиск только слова целиком
-//   - -A N: печатать N строк после совпадения
+// Опции перечислены в описании GrepCommand. Если файлы не указаны,
+// читается stdin, а с -r — текущий каталог. Если файлов несколько, перед каждой строкой
+// печатается имя файла (как в GNU grep).
 //
-// Если файлы не указаны, читается stdin.
+// Возвращает ExitStatusError с кодом 1, если ни одна строка не выбрана,
+// и с кодом 2 при ошибке.
 //
 // Примеры:
 //
@@ -210,62 +629,64 @@ func grepReader(reader io.Reader, writer io.Writer, re *regexp.Regexp, flags *gr
 //	grep -i "HELLO" file.txt      → регистронезависимый поиск
 //	grep -w "word" file.txt       → поиск слова целиком
 //	grep -A 2 "pattern" file.txt  → печать 2 строк после совпадения
+//	grep -cv "^#" a.conf b.conf   → количество строк без комментариев в каждом файле
+//	grep -r --include='*.go' TODO → поиск во всех .go файлах текущего каталога
 func (g *GrepCommand) Exec(args []string, ctx *CommandContext) error {
-	flags, pattern, files, err := parseGrepFlags(args)
+	flags, patterns, files, err := parseGrepFlags(args)
 	if err != nil {
-		if _, writeErr := fmt.Fprintln(ctx.Stderr, err); writeErr != nil {
-			return writeErr
-		}
-		return err
+		return grepFail(ctx, err)
 	}
 
-	re, err := buildRegexp(pattern, flags)
+	flags.colors = grepColorsFor(flags.color, ctx)
+
+	patterns, err = grepPatterns(patterns, flags.patternFiles, ctx)
 	if err != nil {
-		if _, writeErr := fmt.Fprintln(ctx.Stderr, err); writeErr != nil {
-			return writeErr
-		}
-		return err
+		return grepFail(ctx, err)
 	}
 
-	// Если файлы не указаны, читаем из stdin
+	matcher, err := buildMatcher(patterns, flags)
+	if err != nil {
+		return grepFail(ctx, err)
+	}
+
+	// Если файлы не указаны, читаем stdin, а с -r — текущий каталог
 	if len(files) == 0 {
 		files = []string{"-"}
+		if flags.recursive {
+			files = []string{""}
+		}
 	}
+	showName := (flags.withFilename || flags.recursive || len(files) > 1) && !flags.noFilename
 
-	for _, fname := range files {
-		var reader io.Reader
-
-		if fname == "-" {
-			reader = ctx.Stdin
-		} else {
-			//nolint:gosec // открываем файлы, как делает обычный grep
-			file, err := os.Open(fname)
-			if err != nil {
-				if _, writeErr := fmt.Fprintf(ctx.Stderr, "grep: %v\n", err); writeErr != nil {
-					return writeErr
-				}
-				continue
-			}
-			defer func(f *os.File) {
-				if closeErr := f.Close(); closeErr != nil {
-					if _, writeErr := fmt.Fprintf(ctx.Stderr,
-						"grep: ошибка закрытия файла %s: %v\n", f.Name(), closeErr); writeErr != nil {
-						// Игнорируем ошибку записи в stderr
-						_ = writeErr
-					}
-				}
-			}(file)
-			reader = file
-		}
+	// -m 0: ни одна строка не может быть выбрана, файлы не читаются
+	if flags.maxCount == 0 {
+		return &customErrors.ExitStatusError{Code: grepNoMatch}
+	}
 
-		if err := grepReader(reader, ctx.Stdout, re, flags); err != nil {
-			return err
-		}
+	search := &grepSearch{ctx: ctx, matcher: matcher, flags: flags, showName: showName}
+	if err := search.run(files, flags.jobs); err != nil {
+		return err
 	}
 
+	switch {
+	case flags.quiet && search.matched:
+		// С -q совпадение важнее ошибок в других файлах
+	case search.trouble:
+		return &customErrors.ExitStatusError{Code: grepTrouble}
+	case !search.matched:
+		return &customErrors.ExitStatusError{Code: grepNoMatch}
+	}
 	return nil
 }
 
+// grepFail печатает ошибку в stderr и возвращает код возврата 2.
+func grepFail(ctx *CommandContext, err error) error {
+	if _, writeErr := fmt.Fprintln(ctx.Stderr, err); writeErr != nil {
+		return writeErr
+	}
+	return &customErrors.ExitStatusError{Code: grepTrouble}
+}
+
 // Help возвращает справку по команде grep.
 func (g *GrepCommand) Help() string {
 	return `NAME
@@ -273,21 +694,102 @@ func (g *GrepCommand) Help() string {
 
 SYNOPSIS
     grep [OPTIONS] PATTERN [FILE...]
+    grep [OPTIONS] -e PATTERN... [-f FILE...] [FILE...]
 
 DESCRIPTION
     Ищет строки, соответствующие регулярному выражению PATTERN,
     в указанных файлах или стандартном вводе.
 
 OPTIONS
+    -e PATTERN  шаблон поиска; можно указать несколько раз
+    -f FILE     читать шаблоны из FILE, по одному в строке ("-" — stdin)
+    -G          PATTERN — базовое регулярное выражение POSIX (BRE)
+    -E          PATTERN — расширенное регулярное выражение POSIX (ERE)
+    -F          PATTERN — фиксированная строка, а не выражение
+    -P          PATTERN — регулярное выражение Perl (см. PERL REGULAR EXPRESSIONS)
     -i          регистронезависимый поиск (case-insensitive)
     -w          поиск только слова целиком (word match)
+    -x          совпадение со всей строкой (line match)
+    -v          выбирать строки, НЕ соответствующие выражению
+    -c          печатать только количество выбранных строк
+    -n          печатать номер строки перед каждой строкой
+    -H          печатать имя файла перед каждой строкой
+    -h          не печатать имя файла
+    -l          печатать только имена файлов с совпадениями
+    -L          печатать только имена файлов без совпадений
+    -o          печатать только совпавшие части строк
+    -q          ничего не печатать, сообщить результат кодом возврата
+    -s          не сообщать о несуществующих и недоступных файлах
+    -m NUM      остановиться после NUM выбранных строк
     -A NUM      печатать NUM строк после каждого совпадения
-
-    PATTERN     регулярное выражение для поиска
-    FILE        файл(ы) для поиска; если не указаны, читается stdin
+    -B NUM      печатать NUM строк перед каждым совпадением
+    -C NUM, -NUM
+                печатать NUM строк контекста до и после совпадения;
+                явно заданные -A и -B имеют приоритет
+    -r          искать во всех файлах внутри каталогов; без FILE —
+                в текущем каталоге
+    -R          как -r, но следовать символическим ссылкам внутри каталогов
+    --include=GLOB
+                искать только в файлах, имя которых подходит под GLOB
+    --exclude=GLOB
+                пропускать файлы, имя которых подходит под GLOB
+    --exclude-dir=GLOB
+                не заходить в каталоги, имя которых подходит под GLOB
+    --gitignore пропускать файлы и каталоги, игнорируемые правилами
+                .gitignore, и каталоги .git
+    -a          обрабатывать двоичные файлы как текст
+    -I          считать, что в двоичных файлах нет совпадений
+    --binary-files=TYPE
+                режим двоичных файлов: binary (по умолчанию), text
+                (как -a) или without-match (как -I)
+    --color[=WHEN], --colour[=WHEN]
+                подсвечивать совпадения, имена файлов, номера строк
+                и разделители; WHEN — never (по умолчанию), always или
+                auto (только при выводе на терминал); без WHEN — auto
+    -j NUM      обрабатывать до NUM файлов параллельно (по умолчанию —
+                число процессоров); порядок вывода от NUM не зависит
+
+    Короткие флаги можно объединять: -in, -cv, -m5.
+
+    PATTERN     регулярное выражение для поиска; шаблон с переводами строк
+                задаёт несколько шаблонов
+    FILE        файл(ы) или, с -r, каталоги для поиска; если не указаны,
+                читается stdin
+
+    Если файлов несколько, строки выводятся с префиксом "файл:",
+    с флагом -n — "файл:номер:". Строки контекста (-A, -B, -C)
+    отделяются дефисом вместо двоеточия: "файл-номер-", а несмежные
+    группы строк — строкой "--".
+
+    При рекурсивном поиске элементы каталога обходятся в лексикографическом
+    порядке, поэтому вывод не зависит от файловой системы. Пути печатаются
+    относительно рабочего каталога так, как они были заданы: grep -r x
+    печатает "dir/file", grep -r x . — "./dir/file".
+
+    Файл считается двоичным, если в его первых 32 КиБ или в любой строке
+    встречается нулевой байт. Для двоичного файла вместо строк печатается
+    "Binary file FILE matches".
+
+ENVIRONMENT
+    GREP_COLORS цвета подсветки в формате GNU grep: элементы "имя=SGR"
+                через двоеточие. По умолчанию
+                ms=01;31:mc=01;31:sl=:cx=:fn=35:ln=32:bn=32:se=36
+                ms, mc — совпадение в выбранной строке и в строке
+                контекста (с -v); mt задаёт оба; sl, cx — выбранная
+                строка и строка контекста целиком; fn — имя файла;
+                ln — номер строки; se — разделители; rv — поменять sl и
+                cx местами при -v; ne — не очищать строку до конца (\33[K).
+                С -w подсвечиваются только совпадения, принятые как слова.
+    TERM        при TERM=dumb режим auto не подсвечивает вывод
+
+EXIT STATUS
+    0           выбрана хотя бы одна строка
+    1           ни одна строка не выбрана
+    2           ошибка (неверные аргументы, недоступный файл);
+                с -q код 0 возвращается при совпадении даже после ошибки
 
 REGULAR EXPRESSIONS
-    Поддерживаются регулярные выражения Go (RE2):
+    Без флагов синтаксиса используются регулярные выражения Go (RE2):
     .           любой символ
     *           0 или более повторений
     +           1 или более повторений
@@ -299,6 +801,38 @@ REGULAR EXPRESSIONS
     (...)       группировка
     |           альтернатива
 
+    С -G (BRE) группы, интервалы и альтернатива записываются как \( \),
+    \{m,n\} и \|, а +, ?, (, ), {, } и | обозначают сами себя. С -E (ERE)
+    синтаксис близок к RE2, но внутри [...] обратная косая черта — обычный
+    символ. В обоих режимах выбирается самое длинное из самых левых
+    совпадений, \< и \> обозначают границу слова, а обратные ссылки \1–\9
+    не поддерживаются — для них используйте -P.
+
+    С -F каждый шаблон — строка без метасимволов. Все шаблоны ищутся
+    за один проход по строке, поэтому сотни -e работают так же быстро,
+    как один.
+
+PERL REGULAR EXPRESSIONS
+    С -P шаблон сопоставляется движком с возвратами. Поддерживаются:
+    \1, \k<name>         обратные ссылки
+    (?<name>...)         именованные группы, также (?P<name>...)
+    (?:...)              группа без захвата
+    (?=...), (?!...)     опережающие проверки
+    (?<=...), (?<!...)   ретроспективные проверки
+    (?>...)              атомарная группа
+    *? +? ?? {m,n}?      ленивые квантификаторы
+    *+ ++ ?+ {m,n}+      захватывающие квантификаторы
+    (?i), (?-i), (?i:...)
+                         встроенный флаг без учёта регистра
+    \d \w \s \h \b \A \z  классы, границы слова и якоря
+    \xHH, \x{HHHH}       коды символов
+
+    С -P допускается только один шаблон. Перебор вариантов для
+    шаблонов с вложенными квантификаторами, например (a+)+$, растёт
+    экспоненциально, поэтому число возвратов ограничено: при
+    превышении лимита grep сообщает "pcre: превышен лимит возвратов"
+    для файла и завершается с кодом 2.
+
 EXAMPLES
     grep "error" log.txt
         → найти все строки с "error" в log.txt
@@ -312,20 +846,58 @@ EXAMPLES
     grep -A 3 "Exception" log.txt
         → найти "Exception" и 3 строки после
 
+    grep -n -C 2 "panic" app.log
+        → 10-...
+          11-...
+          12:panic: ...
+          13-...
+          14-...
+          --
+          40-...
+
+    grep -n "TODO" main.go util.go
+        → main.go:12:// TODO: ...
+
+    grep -c -v "^#" config.txt
+        → количество строк, не являющихся комментариями
+
+    grep -rn --include='*.go' --exclude-dir=vendor "TODO"
+        → cmd/main.go:12:// TODO: ...
+
+    grep -r --gitignore "password" .
+        → поиск без файлов, перечисленных в .gitignore
+
+    GREP_COLORS='ms=04;32' grep --color=always "TODO" main.go
+        → подчёркнутые зелёные совпадения
+
+    grep -q "ready" status.txt && echo готово
+        → проверить наличие строки без вывода
+
     grep "^#" config.txt
         → найти строки, начинающиеся с #
 
     grep "\.go$" files.txt
         → найти строки, заканчивающиеся на .go
 
+    grep -F -e "a.b" -e "x*y" notes.txt
+        → найти любую из строк "a.b" и "x*y" буквально
+
+    grep -E "(error|warn)ing{1,2}" log.txt
+        → расширенное выражение POSIX
+
+    grep -P "(\w+) \1" text.txt
+        → найти повторённые слова
+
 NOTE ON -w FLAG
     Флаг -w ищет подстроки, ограниченные "non-word constituent character".
     Word constituent characters — это буквы (Unicode Letters), цифры
     (Unicode Digits) и символ подчёркивания (_).
 
-NOTE ON OVERLAPPING REGIONS (-A flag)
-    Если области печати (строки после совпадений) пересекаются,
-    каждая строка печатается только один раз.
+NOTE ON OVERLAPPING REGIONS (-A, -B, -C flags)
+    Если области печати (строки вокруг совпадений) пересекаются,
+    каждая строка печатается только один раз, а группы объединяются.
+    Строки перед совпадением хранятся в кольцевом буфере размером NUM,
+    поэтому даже очень большие файлы не загружаются в память целиком.
 
 LIBRARY CHOICE
     Для разбора аргументов командной строки используется стандартная
@@ -339,7 +911,8 @@ LIBRARY CHOICE
     
     Выбрана стандартная библиотека "flag":
     ✓ Не требует внешних зависимостей
-    ✓ Достаточно мощная для наших нужд (-i, -w, -A N)
+    ✓ Достаточно мощная для наших нужд (-i, -w, -A N, -m N, ...);
+      объединённые короткие флаги (-in) разделяются перед разбором
     ✓ Поддерживает FlagSet для изоляции парсинга
     ✓ Хорошо документирована и стабильна
     ✓ Широко используется в Go-экосистеме`
@@ -0,0 +1,166 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Режимы подсветки --color.
+const (
+	grepColorNever  = "never"
+	grepColorAlways = "always"
+	grepColorAuto   = "auto"
+)
+
+// colorMode — значение флага --color. Флаг можно указать без значения
+// (--color), тогда выбирается режим auto, как в GNU grep.
+type colorMode string
+
+func (m *colorMode) String() string {
+	return string(*m)
+}
+
+func (m *colorMode) Set(value string) error {
+	switch value {
+	case "true", grepColorAuto, "tty", "if-tty":
+		*m = grepColorAuto
+	case grepColorAlways, "yes", "force":
+		*m = grepColorAlways
+	case grepColorNever, "no", "none":
+		*m = grepColorNever
+	default:
+		return fmt.Errorf("неизвестный режим подсветки %q", value)
+	}
+	return nil
+}
+
+// IsBoolFlag позволяет пакету flag принимать --color без значения.
+func (m *colorMode) IsBoolFlag() bool {
+	return true
+}
+
+// grepDefaultColors — значение GREP_COLORS по умолчанию в GNU grep.
+const grepDefaultColors = "ms=01;31:mc=01;31:sl=:cx=:fn=35:ln=32:bn=32:se=36"
+
+// grepColors — параметры SGR для элементов вывода, в формате GREP_COLORS.
+type grepColors struct {
+	selectedMatch string // ms: совпадение в выбранной строке
+	contextMatch  string // mc: совпадение в строке контекста (с -v)
+	selectedLine  string // sl: выбранная строка целиком
+	contextLine   string // cx: строка контекста целиком
+	fileName      string // fn: имя файла
+	lineNumber    string // ln: номер строки
+	byteOffset    string // bn: смещение в байтах
+	separator     string // se: разделители ":", "-" и "--"
+	reverse       bool   // rv: с -v поменять местами sl и cx
+	noErase       bool   // ne: не добавлять очистку до конца строки \33[K
+}
+
+// parseGrepColors разбирает GREP_COLORS поверх значений по умолчанию.
+// Неизвестные и некорректные элементы пропускаются, как в GNU grep.
+func parseGrepColors(spec string) *grepColors {
+	colors := &grepColors{}
+	colors.apply(grepDefaultColors)
+	colors.apply(spec)
+	return colors
+}
+
+func (c *grepColors) apply(spec string) {
+	for _, item := range strings.Split(spec, ":") {
+		name, value, hasValue := strings.Cut(item, "=")
+		if hasValue && strings.Trim(value, "0123456789;") != "" {
+			continue
+		}
+
+		switch name {
+		case "mt":
+			c.selectedMatch, c.contextMatch = value, value
+		case "ms":
+			c.selectedMatch = value
+		case "mc":
+			c.contextMatch = value
+		case "sl":
+			c.selectedLine = value
+		case "cx":
+			c.contextLine = value
+		case "fn":
+			c.fileName = value
+		case "ln":
+			c.lineNumber = value
+		case "bn":
+			c.byteOffset = value
+		case "se":
+			c.separator = value
+		case "rv":
+			c.reverse = true
+		case "ne":
+			c.noErase = true
+		}
+	}
+}
+
+// paint окружает text последовательностями SGR. Пустые text или sgr
+// возвращаются без изменений.
+func (c *grepColors) paint(sgr, text string) string {
+	if c == nil || sgr == "" || text == "" {
+		return text
+	}
+
+	erase := "\033[K"
+	if c.noErase {
+		erase = ""
+	}
+	return "\033[" + sgr + "m" + erase + text + "\033[m" + erase
+}
+
+// lineColors возвращает цвета строки и совпадений в ней: для выбранных
+// строк (selected) или строк контекста. С rv и -v цвета строк меняются местами.
+func (c *grepColors) lineColors(selected, invert bool) (string, string) {
+	line, match := c.contextLine, c.contextMatch
+	if selected {
+		line, match = c.selectedLine, c.selectedMatch
+	}
+	if c.reverse && invert {
+		if selected {
+			line = c.contextLine
+		} else {
+			line = c.selectedLine
+		}
+	}
+	return line, match
+}
+
+// grepColorsFor возвращает цвета для режима mode или nil, если
+// подсвечивать не нужно. В режиме auto вывод подсвечивается только
+// на терминал и только если TERM не равен "dumb".
+func grepColorsFor(mode colorMode, ctx *CommandContext) *grepColors {
+	switch mode {
+	case grepColorAlways:
+	case grepColorAuto:
+		if !isTerminal(ctx.Stdout) || variableValue(ctx, "TERM") == "dumb" {
+			return nil
+		}
+	default:
+		return nil
+	}
+	return parseGrepColors(variableValue(ctx, "GREP_COLORS"))
+}
+
+// isTerminal сообщает, что поток stream — терминал: символьное
+// устройство, отличное от /dev/null.
+func isTerminal(stream any) bool {
+	file, ok := stream.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := file.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+	if null, err := os.Stat(os.DevNull); err == nil && os.SameFile(info, null) {
+		return false
+	}
+	return true
+}
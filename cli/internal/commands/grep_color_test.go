@@ -0,0 +1,118 @@
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
+)
+
+// sgr возвращает последовательность включения цвета с очисткой строки.
+func sgr(code string) string {
+	return "\033[" + code + "m\033[K"
+}
+
+const sgrEnd = "\033[m\033[K"
+
+func TestParseGrepColors(t *testing.T) {
+	colors := parseGrepColors("")
+	if colors.selectedMatch != "01;31" || colors.fileName != "35" || colors.separator != "36" {
+		t.Fatalf("неверные цвета по умолчанию: %+v", colors)
+	}
+
+	colors = parseGrepColors("mt=04:fn=:ln=1;33:sl=bad:rv:ne:xx=1")
+	if colors.selectedMatch != "04" || colors.contextMatch != "04" {
+		t.Errorf("mt должен задавать ms и mc: %+v", colors)
+	}
+	if colors.fileName != "" || colors.lineNumber != "1;33" {
+		t.Errorf("ожидались fn=\"\" и ln=1;33: %+v", colors)
+	}
+	if colors.selectedLine != "" {
+		t.Errorf("некорректное значение sl должно пропускаться: %q", colors.selectedLine)
+	}
+	if !colors.reverse || !colors.noErase {
+		t.Errorf("ожидались rv и ne: %+v", colors)
+	}
+}
+
+func TestColorModeSet(t *testing.T) {
+	tests := map[string]colorMode{
+		"true":   grepColorAuto,
+		"auto":   grepColorAuto,
+		"always": grepColorAlways,
+		"never":  grepColorNever,
+	}
+	for value, expected := range tests {
+		var mode colorMode
+		if err := mode.Set(value); err != nil || mode != expected {
+			t.Errorf("%q: ожидалось %q, получено %q (%v)", value, expected, mode, err)
+		}
+	}
+
+	var mode colorMode
+	if err := mode.Set("sometimes"); err == nil {
+		t.Error("ожидалась ошибка для неизвестного режима")
+	}
+}
+
+func TestGrepColorOutput(t *testing.T) {
+	dir := newGrepTestDir(t)
+	match := func(text string) string { return sgr("01;31") + text + sgrEnd }
+	sep := func(text string) string { return sgr("36") + text + sgrEnd }
+	name := func(text string) string { return sgr("35") + text + sgrEnd }
+	num := func(text string) string { return sgr("32") + text + sgrEnd }
+
+	tests := []struct {
+		name     string
+		stdin    string
+		args     []string
+		expected string
+	}{
+		{"совпадения и номер строки", "abcb\n", []string{"--color=always", "-n", "b"},
+			num("1") + sep(":") + "a" + match("b") + "c" + match("b") + "\n"},
+		{"имя файла", "", []string{"--color=always", "beta", "a.txt", "b.txt"},
+			name("a.txt") + sep(":") + match("beta") + "\n"},
+		{"-w подсвечивает только принятые совпадения", "foobar foo\n", []string{"--color=always", "-w", "foo"},
+			"foobar " + match("foo") + "\n"},
+		{"-o", "x12y345\n", []string{"--color=always", "-o", "[0-9]+"},
+			match("12") + "\n" + match("345") + "\n"},
+		{"-v подсвечивает совпадения в контексте", "a\nb\nc\n", []string{"--color=always", "-v", "-B1", "a"},
+			match("a") + "\nb\nc\n"},
+		{"разделитель групп", "m\nx\ny\nz\nm\n", []string{"--color=always", "-A1", "m"},
+			match("m") + "\nx\n" + sep("--") + "\n" + match("m") + "\n"},
+		{"-l", "", []string{"--color=always", "-l", "gamma", "a.txt", "b.txt"}, name("b.txt") + "\n"},
+		{"-c", "", []string{"--color=always", "-c", "gamma", "a.txt", "b.txt"},
+			name("a.txt") + sep(":") + "0\n" + name("b.txt") + sep(":") + "1\n"},
+		{"never", "abc\n", []string{"--color=never", "b"}, "abc\n"},
+		{"auto без терминала", "abc\n", []string{"--color=auto", "b"}, "abc\n"},
+		{"--color без значения", "abc\n", []string{"--color", "b"}, "abc\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, stderr, _ := runCommand(t, &GrepCommand{}, dir, tt.stdin, tt.args...)
+			if out != tt.expected {
+				t.Fatalf("ожидалось %q, получено %q (stderr %q)", tt.expected, out, stderr)
+			}
+		})
+	}
+}
+
+func TestGrepColorsFromEnv(t *testing.T) {
+	var stdout bytes.Buffer
+	ctx := &CommandContext{
+		Stdin:  strings.NewReader("abc\n"),
+		Stdout: &stdout,
+		Stderr: &bytes.Buffer{},
+		Vars:   session.NewVariables(map[string]string{"GREP_COLORS": "ms=04:sl=2:ne"}),
+	}
+	if err := (&GrepCommand{}).Exec([]string{"--colour=always", "b"}, ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "\033[2ma\033[m\033[04mb\033[m\033[2mc\033[m\n"
+	if stdout.String() != expected {
+		t.Fatalf("ожидалось %q, получено %q", expected, stdout.String())
+	}
+}
@@ -0,0 +1,84 @@
+package commands
+
+// contextLine — строка входа вместе с её номером.
+type contextLine struct {
+	num  int
+	text string
+}
+
+// lineRing — кольцевой буфер последних строк для контекста -B.
+// Хранит не более заданного числа строк, поэтому потребление памяти
+// не зависит от размера входа: большие логи обрабатываются потоково.
+type lineRing struct {
+	lines []contextLine
+	start int
+	size  int
+}
+
+func newLineRing(capacity int) *lineRing {
+	return &lineRing{lines: make([]contextLine, capacity)}
+}
+
+// push добавляет строку, вытесняя самую старую при переполнении.
+func (r *lineRing) push(num int, text string) {
+	if len(r.lines) == 0 {
+		return
+	}
+
+	idx := (r.start + r.size) % len(r.lines)
+	r.lines[idx] = contextLine{num: num, text: text}
+	if r.size < len(r.lines) {
+		r.size++
+	} else {
+		r.start = (r.start + 1) % len(r.lines)
+	}
+}
+
+// drain передаёт fn сохранённые строки от старых к новым и очищает буфер.
+func (r *lineRing) drain(fn func(line contextLine) error) error {
+	for r.size > 0 {
+		line := r.lines[r.start]
+		r.start = (r.start + 1) % len(r.lines)
+		r.size--
+		if err := fn(line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// grepContextPrinter печатает выбранные строки и строки контекста,
+// разделяя несмежные группы строкой "--", как GNU grep.
+type grepContextPrinter struct {
+	out         *grepPrinter
+	separate    bool // печатать "--" между группами (задан контекст)
+	lastPrinted int  // номер последней напечатанной строки, 0 — ещё ничего
+}
+
+// print печатает строку, предваряя её разделителем групп, если между
+// ней и предыдущей напечатанной строкой есть пропуск.
+func (p *grepContextPrinter) print(num int, sep byte, text string) error {
+	if err := p.separateFrom(num); err != nil {
+		return err
+	}
+	return p.out.print(num, sep, text)
+}
+
+// printMatch печатает совпавшую часть строки num (-o).
+func (p *grepContextPrinter) printMatch(num int, text string) error {
+	if err := p.separateFrom(num); err != nil {
+		return err
+	}
+	return p.out.printMatch(num, text)
+}
+
+// separateFrom печатает разделитель групп перед строкой num, если нужно,
+// и отмечает её напечатанной.
+func (p *grepContextPrinter) separateFrom(num int) error {
+	defer func() { p.lastPrinted = num }()
+
+	if !p.separate || p.lastPrinted == 0 || num <= p.lastPrinted+1 {
+		return nil
+	}
+	return p.out.printGroupSeparator()
+}
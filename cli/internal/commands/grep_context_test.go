@@ -0,0 +1,119 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
+)
+
+func TestLineRing(t *testing.T) {
+	ring := newLineRing(2)
+	for i := 1; i <= 5; i++ {
+		ring.push(i, fmt.Sprint("line", i))
+	}
+
+	var got []contextLine
+	collect := func(l contextLine) error {
+		got = append(got, l)
+		return nil
+	}
+	if err := ring.drain(collect); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []contextLine{{4, "line4"}, {5, "line5"}}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("ожидалось %v, получено %v", expected, got)
+	}
+
+	got = nil
+	_ = ring.drain(collect)
+	if len(got) != 0 {
+		t.Fatalf("после drain буфер должен быть пуст: %v", got)
+	}
+
+	empty := newLineRing(0)
+	empty.push(1, "x")
+	_ = empty.drain(collect)
+	if len(got) != 0 {
+		t.Fatalf("буфер нулевого размера не должен хранить строки: %v", got)
+	}
+}
+
+func TestGrepCommand_Context(t *testing.T) {
+	input := "1\n2\nmatch3\n4\n5\n6\n7\nmatch8\nmatch9\n10\n11\n"
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{"до совпадения", []string{"-B", "1", "match"}, "2\nmatch3\n--\n7\nmatch8\nmatch9\n"},
+		{"до и после", []string{"-C", "1", "match"}, "2\nmatch3\n4\n--\n7\nmatch8\nmatch9\n10\n"},
+		{"контекст -NUM", []string{"-1", "match"}, "2\nmatch3\n4\n--\n7\nmatch8\nmatch9\n10\n"},
+		{"-A важнее -C", []string{"-C", "1", "-A", "0", "match"}, "2\nmatch3\n--\n7\nmatch8\nmatch9\n"},
+		{"смежные группы", []string{"-A", "2", "match"}, "match3\n4\n5\n--\nmatch8\nmatch9\n10\n11\n"},
+		{"пересечение групп", []string{"-C", "2", "match"}, "1\n2\nmatch3\n4\n5\n6\n7\nmatch8\nmatch9\n10\n11\n"},
+		{"номера строк", []string{"-n", "-B1", "match3"}, "2-2\n3:match3\n"},
+		{"имя и номер", []string{"-Hn2", "match8"}, "(standard input)-6-6\n(standard input)-7-7\n" +
+			"(standard input):8:match8\n(standard input)-9-match9\n(standard input)-10-10\n"},
+		{"без контекста нет разделителей", []string{"match"}, "match3\nmatch8\nmatch9\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, stderr, _ := runCommand(t, &GrepCommand{}, ".", input, tt.args...)
+			if out != tt.expected {
+				t.Fatalf("ожидалось %q, получено %q (stderr %q)", tt.expected, out, stderr)
+			}
+		})
+	}
+}
+
+// generatedLines потоково порождает count строк, не храня их в памяти.
+type generatedLines struct {
+	count, next int
+	pending     string
+}
+
+func (g *generatedLines) Read(p []byte) (int, error) {
+	if g.pending == "" {
+		if g.next >= g.count {
+			return 0, io.EOF
+		}
+		g.next++
+		g.pending = fmt.Sprintf("line %d\n", g.next)
+	}
+	n := copy(p, g.pending)
+	g.pending = g.pending[n:]
+	return n, nil
+}
+
+func TestGrepCommand_ContextStreaming(t *testing.T) {
+	const count = 200000
+
+	out, _, status := runGrepReader(t, &generatedLines{count: count}, "-B", "2", "-A", "1", "line 199999$")
+	expected := "line 199997\nline 199998\nline 199999\nline 200000\n"
+	if status != 0 || out != expected {
+		t.Fatalf("ожидалось %q, получено %q (код %d)", expected, out, status)
+	}
+}
+
+// runGrepReader выполняет grep над произвольным reader в качестве stdin.
+func runGrepReader(t *testing.T, stdin io.Reader, args ...string) (string, string, int) {
+	t.Helper()
+	var stdout, stderr strings.Builder
+	ctx := &CommandContext{
+		Stdin:  stdin,
+		Stdout: &stdout,
+		Stderr: &stderr,
+		Vars:   session.NewVariables(nil),
+		Dir:    ".",
+	}
+	err := (&GrepCommand{}).Exec(args, ctx)
+	return stdout.String(), stderr.String(), testStatus(err)
+}
@@ -0,0 +1,275 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/ahocorasick"
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/linereader"
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/pcre"
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/posixre"
+)
+
+// Синтаксисы шаблонов grep.
+const (
+	grepSyntaxRE2      = iota // по умолчанию: регулярные выражения Go (RE2)
+	grepSyntaxBasic           // -G: базовые регулярные выражения POSIX
+	grepSyntaxExtended        // -E: расширенные регулярные выражения POSIX
+	grepSyntaxFixed           // -F: фиксированные строки
+	grepSyntaxPerl            // -P: подмножество PCRE
+)
+
+// grepMatcher находит совпадения шаблонов в строке.
+type grepMatcher interface {
+	// MatchString сообщает, есть ли в строке совпадение.
+	MatchString(line string) bool
+	// FindAllStringIndex возвращает до n непересекающихся совпадений
+	// (все при n < 0) в виде пар [начало, конец].
+	FindAllStringIndex(line string, n int) [][]int
+}
+
+// fixedMatcher ищет фиксированные строки автоматом Ахо — Корасик.
+type fixedMatcher struct {
+	*ahocorasick.Matcher
+}
+
+func (m fixedMatcher) MatchString(line string) bool {
+	return m.Match(line)
+}
+
+func (m fixedMatcher) FindAllStringIndex(line string, n int) [][]int {
+	matches := m.FindAll(line)
+	if n >= 0 && len(matches) > n {
+		matches = matches[:n]
+	}
+	return matches
+}
+
+// wholeLineMatcher принимает только совпадения со всей строкой (-x).
+// Работает с fixedMatcher: самое длинное совпадение от начала строки
+// покрывает её целиком, если какой-либо шаблон равен строке.
+type wholeLineMatcher struct {
+	grepMatcher
+}
+
+func (m wholeLineMatcher) MatchString(line string) bool {
+	return len(m.FindAllStringIndex(line, -1)) > 0
+}
+
+func (m wholeLineMatcher) FindAllStringIndex(line string, _ int) [][]int {
+	matches := m.grepMatcher.FindAllStringIndex(line, 1)
+	if len(matches) == 0 || matches[0][0] != 0 || matches[0][1] != len(line) {
+		return nil
+	}
+	return matches
+}
+
+// noMatcher используется, когда шаблонов нет (например, -f с пустым файлом).
+type noMatcher struct{}
+
+func (noMatcher) MatchString(string) bool                { return false }
+func (noMatcher) FindAllStringIndex(string, int) [][]int { return nil }
+
+// grepPatterns собирает шаблоны из -e, позиционного аргумента и файлов -f.
+// Шаблон, содержащий переводы строк, разбивается на несколько шаблонов.
+// Файл "-" — стандартный ввод.
+func grepPatterns(patterns, patternFiles []string, ctx *CommandContext) ([]string, error) {
+	var result []string
+	for _, pattern := range patterns {
+		result = append(result, strings.Split(pattern, "\n")...)
+	}
+
+	for _, name := range patternFiles {
+		lines, err := readPatternFile(name, ctx)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, lines...)
+	}
+	return result, nil
+}
+
+func readPatternFile(name string, ctx *CommandContext) ([]string, error) {
+	var reader io.Reader = ctx.Stdin
+	if name != "-" {
+		//nolint:gosec // файл шаблонов задаёт пользователь
+		file, err := os.Open(ctx.ResolvePath(name))
+		if err != nil {
+			return nil, fmt.Errorf("grep: %s: %w", name, fileError(err))
+		}
+		defer func() {
+			_ = file.Close()
+		}()
+		reader = file
+	}
+
+	var lines []string
+	lr := linereader.New(reader)
+	for {
+		line, err := lr.ReadLine()
+		if errors.Is(err, io.EOF) {
+			return lines, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("grep: %s: %w", name, fileError(err))
+		}
+		lines = append(lines, string(linereader.TrimNewline(line)))
+	}
+}
+
+// buildMatcher компилирует шаблоны в соответствии с синтаксисом
+// и флагами -i и -x. Строка выбирается, если совпал любой из шаблонов.
+func buildMatcher(patterns []string, flags *grepFlags) (grepMatcher, error) {
+	if len(patterns) == 0 {
+		return noMatcher{}, nil
+	}
+
+	switch flags.syntax {
+	case grepSyntaxFixed:
+		return buildFixedMatcher(patterns, flags)
+	case grepSyntaxPerl:
+		return buildPerlMatcher(patterns, flags)
+	}
+
+	alternatives := make([]string, len(patterns))
+	for i, pattern := range patterns {
+		var err error
+		switch flags.syntax {
+		case grepSyntaxBasic:
+			pattern, err = posixre.Translate(pattern, false)
+		case grepSyntaxExtended:
+			pattern, err = posixre.Translate(pattern, true)
+		}
+		if errors.Is(err, posixre.ErrBackReference) {
+			return nil, fmt.Errorf("grep: %w (используйте -P)", err)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("grep: некорректное регулярное выражение: %w", err)
+		}
+		alternatives[i] = pattern
+	}
+
+	matcher, err := compileRegexp(joinAlternatives(alternatives), flags)
+	if err != nil {
+		return nil, err
+	}
+	// POSIX требует самое длинное из самых левых совпадений
+	if re, ok := matcher.(*regexp.Regexp); ok && flags.syntax != grepSyntaxRE2 {
+		re.Longest()
+	}
+	return matcher, nil
+}
+
+// buildFixedMatcher строит автомат для -F. Без учёта регистра автомат
+// сравнивает только латинские буквы, поэтому шаблоны с другими буквами
+// ищутся через экранированное регулярное выражение.
+func buildFixedMatcher(patterns []string, flags *grepFlags) (grepMatcher, error) {
+	if flags.ignoreCase && !allASCII(patterns) {
+		quoted := make([]string, len(patterns))
+		for i, pattern := range patterns {
+			quoted[i] = regexp.QuoteMeta(pattern)
+		}
+		return compileRegexp(joinAlternatives(quoted), flags)
+	}
+
+	var matcher grepMatcher = fixedMatcher{ahocorasick.New(patterns, flags.ignoreCase)}
+	if flags.lineMatch {
+		matcher = wholeLineMatcher{matcher}
+	}
+	return matcher, nil
+}
+
+// buildPerlMatcher компилирует шаблон -P. Как и GNU grep, -P принимает
+// только один шаблон: номера групп в обратных ссылках разных шаблонов
+// при объединении сместились бы.
+func buildPerlMatcher(patterns []string, flags *grepFlags) (grepMatcher, error) {
+	if len(patterns) > 1 {
+		return nil, errors.New("grep: с -P поддерживается только один шаблон")
+	}
+
+	pattern := patterns[0]
+	if flags.lineMatch {
+		pattern = `^(?:` + pattern + `)$`
+	}
+	re, err := pcre.Compile(pattern, flags.ignoreCase)
+	if err != nil {
+		return nil, fmt.Errorf("grep: некорректное регулярное выражение: %w", err)
+	}
+	return perlMatcher{re}, nil
+}
+
+// perlMatchError — значение паники, которой perlMatcher сообщает об
+// ошибке сопоставления; grepReader превращает её в ошибку файла.
+type perlMatchError struct {
+	err error
+}
+
+// perlMatcher сопоставляет строки выражением -P. Интерфейс grepMatcher
+// не возвращает ошибок, поэтому превышение лимита возвратов прерывает
+// поиск в файле паникой perlMatchError.
+type perlMatcher struct {
+	*pcre.Regexp
+}
+
+func (m perlMatcher) MatchString(line string) bool {
+	ok, err := m.Match(line)
+	if err != nil {
+		panic(perlMatchError{err})
+	}
+	return ok
+}
+
+func (m perlMatcher) FindAllStringIndex(line string, n int) [][]int {
+	matches, err := m.FindAll(line, n)
+	if err != nil {
+		panic(perlMatchError{err})
+	}
+	return matches
+}
+
+// compileRegexp компилирует выражение RE2 с учётом -x и -i.
+func compileRegexp(pattern string, flags *grepFlags) (grepMatcher, error) {
+	// Примечание: для флага -w мы проверяем границы слов вручную в функции matchesWord,
+	// так как \b в Go regexp не полностью поддерживает Unicode.
+	// Паттерн не модифицируется здесь для -w.
+
+	// Для -x паттерн привязывается к началу и концу строки
+	if flags.lineMatch {
+		pattern = "^(?:" + pattern + ")$"
+	}
+
+	// Если включён регистронезависимый поиск, добавляем флаг (?i)
+	if flags.ignoreCase {
+		pattern = "(?i)" + pattern
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("grep: некорректное регулярное выражение: %w", err)
+	}
+	return re, nil
+}
+
+// joinAlternatives объединяет выражения в одно: (?:a)|(?:b).
+func joinAlternatives(patterns []string) string {
+	if len(patterns) == 1 {
+		return patterns[0]
+	}
+	return "(?:" + strings.Join(patterns, ")|(?:") + ")"
+}
+
+func allASCII(patterns []string) bool {
+	for _, pattern := range patterns {
+		for i := 0; i < len(pattern); i++ {
+			if pattern[i] >= utf8.RuneSelf {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,121 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGrepPatternSyntaxes(t *testing.T) {
+	const input = "a.b\naxb\nabab\na+b\nfoo bar\nFOO\nёлка\nЁЛКА\nhello hello\ncost $42\n"
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{"по умолчанию RE2", []string{"a.b"}, "a.b\naxb\na+b\n"},
+		{"-F ищет строку буквально", []string{"-F", "a.b"}, "a.b\n"},
+		{"-F с несколькими -e", []string{"-F", "-e", "a+b", "-e", "$4"}, "a+b\ncost $42\n"},
+		{"-F -o выбирает самое длинное", []string{"-Fo", "-e", "ab", "-e", "abab"}, "abab\n"},
+		{"-F -x", []string{"-Fx", "-e", "FOO", "-e", "foo"}, "FOO\n"},
+		{"-F -i", []string{"-Fi", "foo"}, "foo bar\nFOO\n"},
+		{"-F -i не ASCII", []string{"-Fi", "ёлка"}, "ёлка\nЁЛКА\n"},
+		{"-F -w", []string{"-Fw", "bar"}, "foo bar\n"},
+		{"-G группы и интервалы", []string{"-G", `\(ab\)\{2\}`}, "abab\n"},
+		{"-G + обычный символ", []string{"-G", "a+b"}, "a+b\n"},
+		{"-G \\+ квантификатор", []string{"-Gx", `ax\+b`}, "axb\n"},
+		{"-E группы и интервалы", []string{"-Ex", "(ab){2}|a.b"}, "a.b\naxb\nabab\na+b\n"},
+		{"-E самое длинное совпадение", []string{"-Eo", "ab|abab"}, "abab\n"},
+		{"-e с переводом строки", []string{"-e", "FOO\nёлка"}, "FOO\nёлка\n"},
+		{"-P обратная ссылка", []string{"-P", `(\w+) \1`}, "hello hello\n"},
+		{"-P ретроспективная проверка", []string{"-Po", `(?<=\$)\d+`}, "42\n"},
+		{"-P -i", []string{"-Pi", `^ёлка$`}, "ёлка\nЁЛКА\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, stderr, status := runCommand(t, &GrepCommand{}, t.TempDir(), input, tt.args...)
+			if out != tt.expected || status != 0 {
+				t.Fatalf("ожидалось %q, получено %q (код %d, stderr %q)", tt.expected, out, status, stderr)
+			}
+		})
+	}
+}
+
+func TestGrepPatternFile(t *testing.T) {
+	dir := newGrepTestDir(t)
+	if err := os.WriteFile(filepath.Join(dir, "patterns"), []byte("^gamma$\nbeta\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "empty"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, _, status := runCommand(t, &GrepCommand{}, dir, "", "-f", "patterns", "a.txt", "b.txt")
+	if expected := "a.txt:beta\nb.txt:gamma\n"; out != expected || status != 0 {
+		t.Fatalf("ожидалось %q, получено %q (код %d)", expected, out, status)
+	}
+
+	// -f и -e дополняют друг друга
+	out, _, _ = runCommand(t, &GrepCommand{}, dir, "", "-f", "patterns", "-e", "delta", "b.txt")
+	if expected := "gamma\ndelta\n"; out != expected {
+		t.Fatalf("ожидалось %q, получено %q", expected, out)
+	}
+
+	// Пустой файл шаблонов не выбирает ни одной строки, а с -v — все
+	out, _, status = runCommand(t, &GrepCommand{}, dir, "", "-f", "empty", "b.txt")
+	if out != "" || status != 1 {
+		t.Fatalf("ожидался код 1 без вывода, получено %q (код %d)", out, status)
+	}
+	out, _, _ = runCommand(t, &GrepCommand{}, dir, "", "-v", "-f", "empty", "b.txt")
+	if expected := "gamma\ndelta\n"; out != expected {
+		t.Fatalf("ожидалось %q, получено %q", expected, out)
+	}
+
+	_, stderr, status := runCommand(t, &GrepCommand{}, dir, "", "-f", "missing", "b.txt")
+	if status != 2 || !strings.Contains(stderr, "missing") {
+		t.Fatalf("ожидалась ошибка чтения файла шаблонов: %q (код %d)", stderr, status)
+	}
+}
+
+func TestGrepPatternErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		message string
+	}{
+		{"несколько синтаксисов", []string{"-E", "-F", "x"}, "несколько синтаксисов"},
+		{"обратная ссылка в BRE", []string{"-G", `\(a\)\1`}, "-P"},
+		{"несколько шаблонов -P", []string{"-P", "-e", "a", "-e", "b"}, "только один шаблон"},
+		{"ошибка PCRE", []string{"-P", "(a"}, "некорректное регулярное выражение"},
+		{"ошибка BRE", []string{"-G", "[a"}, "некорректное регулярное выражение"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, stderr, status := runCommand(t, &GrepCommand{}, t.TempDir(), "a\n", tt.args...)
+			if status != 2 || !strings.Contains(stderr, tt.message) {
+				t.Fatalf("ожидался код 2 и %q, получено %q (код %d)", tt.message, stderr, status)
+			}
+		})
+	}
+}
+
+func TestGrepPatternMatchLimit(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte(strings.Repeat("a", 40)+"!\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "b.txt"), []byte("aa\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Остальные файлы просматриваются, но код возврата — 2, как у GNU grep
+	out, stderr, status := runCommand(t, &GrepCommand{}, dir, "", "-P", "(a+)+$", "a.txt", "b.txt")
+	expected := "grep: a.txt: pcre: превышен лимит возвратов\n"
+	if out != "b.txt:aa\n" || stderr != expected || status != 2 {
+		t.Fatalf("ожидалось %q и %q с кодом 2, получено %q и %q (код %d)", "b.txt:aa\n", expected, out, stderr, status)
+	}
+}
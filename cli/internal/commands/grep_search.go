@@ -0,0 +1,233 @@
+package commands
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// grepSearch выполняет поиск по списку файлов и подсчитывает итог
+// для кода возврата.
+type grepSearch struct {
+	ctx      *CommandContext
+	matcher  grepMatcher
+	flags    *grepFlags
+	showName bool
+
+	matched bool // хотя бы один файл дал результат (для -L — файл без совпадений)
+	trouble bool // произошла ошибка доступа к файлу
+}
+
+// grepJob — файл для поиска с порядковым номером и буферами для вывода.
+type grepJob struct {
+	index int
+	name  string
+	err   error // ошибка доступа, обнаруженная при обходе каталогов
+
+	stdout   bytes.Buffer
+	stderr   bytes.Buffer
+	selected int
+	failed   bool
+	writeErr error
+}
+
+// run ищет во всех файлах и каталогах из files. Если workers > 1,
+// файлы обрабатываются параллельно; вывод в обоих случаях одинаков.
+func (s *grepSearch) run(files []string, workers int) error {
+	var err error
+	if workers > 1 && (s.flags.recursive || len(files) > 1) {
+		err = s.parallel(files, workers)
+	} else {
+		err = s.sequential(files)
+	}
+
+	if errors.Is(err, errGrepQuit) {
+		return nil
+	}
+	return err
+}
+
+// sequential обрабатывает файлы по очереди, печатая результат сразу:
+// так вывод остаётся потоковым, например для grep по stdin из tail -f.
+func (s *grepSearch) sequential(files []string) error {
+	walker := &grepWalker{ctx: s.ctx, flags: s.flags}
+	walker.visit = func(name string, err error) error {
+		selected, failed, writeErr := s.searchFile(s.ctx, name, err)
+		if writeErr != nil {
+			return writeErr
+		}
+		return s.tally(selected, failed)
+	}
+
+	for _, name := range files {
+		if err := walker.walk(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parallel обрабатывает файлы пулом из workers горутин. Обход каталогов
+// выполняется в отдельной горутине, результаты каждого файла собираются
+// в буферы и печатаются строго в порядке обхода. Одновременно в работе
+// не более 4*workers файлов, поэтому память не растёт, даже если первый
+// файл обрабатывается долго.
+func (s *grepSearch) parallel(files []string, workers int) error {
+	window := 4 * workers
+	slots := make(chan struct{}, window)
+	jobs := make(chan *grepJob)
+	results := make(chan *grepJob, window)
+	done := make(chan struct{})
+
+	go s.produce(files, slots, jobs, done)
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				s.process(job, done)
+				results <- job
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// Результаты приходят в произвольном порядке и ждут своей очереди
+	pending := map[int]*grepJob{}
+	next := 0
+	var stop error
+	// done читают рабочие горутины, поэтому переменная не переприсваивается:
+	// повторное закрытие предотвращает флаг closed
+	closed := false
+	for job := range results {
+		pending[job.index] = job
+		for stop == nil {
+			ready, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			next++
+			<-slots
+			stop = s.flush(ready)
+		}
+
+		if stop != nil && !closed {
+			close(done)
+			closed = true
+		}
+	}
+	return stop
+}
+
+// produce обходит файлы и отправляет задания в jobs. Перед каждым
+// заданием занимается место в slots; обход прекращается, когда закрыт done.
+func (s *grepSearch) produce(files []string, slots chan<- struct{}, jobs chan<- *grepJob, done <-chan struct{}) {
+	defer close(jobs)
+
+	index := 0
+	walker := &grepWalker{ctx: s.ctx, flags: s.flags}
+	walker.visit = func(name string, err error) error {
+		select {
+		case slots <- struct{}{}:
+		case <-done:
+			return errGrepQuit
+		}
+
+		job := &grepJob{index: index, name: name, err: err}
+		index++
+		select {
+		case jobs <- job:
+			return nil
+		case <-done:
+			return errGrepQuit
+		}
+	}
+
+	for _, name := range files {
+		if walker.walk(name) != nil {
+			return
+		}
+	}
+}
+
+// process ищет в файле задания, записывая вывод в буферы задания.
+// После остановки поиска оставшиеся задания пропускаются.
+func (s *grepSearch) process(job *grepJob, done <-chan struct{}) {
+	select {
+	case <-done:
+		return
+	default:
+	}
+
+	ctx := *s.ctx
+	ctx.Stdout = &job.stdout
+	ctx.Stderr = &job.stderr
+	job.selected, job.failed, job.writeErr = s.searchFile(&ctx, job.name, job.err)
+}
+
+// flush печатает вывод задания и учитывает его результат.
+func (s *grepSearch) flush(job *grepJob) error {
+	if job.writeErr != nil {
+		return job.writeErr
+	}
+	if _, err := s.ctx.Stdout.Write(job.stdout.Bytes()); err != nil {
+		return err
+	}
+	if _, err := s.ctx.Stderr.Write(job.stderr.Bytes()); err != nil {
+		return err
+	}
+	return s.tally(job.selected, job.failed)
+}
+
+// searchFile ищет в файле name и печатает результат и сообщение об ошибке
+// в потоки ctx. err — ошибка доступа, обнаруженная при обходе. Возвращает
+// количество выбранных строк, признак ошибки файла и ошибку записи.
+func (s *grepSearch) searchFile(ctx *CommandContext, name string, err error) (int, bool, error) {
+	selected := 0
+	if err == nil {
+		selected, err = grepFile(name, ctx, s.matcher, s.flags, s.showName)
+	}
+	if err == nil {
+		return selected, false, nil
+	}
+
+	// Файл называется так, как его указал пользователь: без пути каталога
+	// сеанса и имени системного вызова
+	label := name
+	switch name {
+	case "-":
+		label = grepStdinName
+	case "":
+		label = "."
+	}
+	if !s.flags.noMessages {
+		if _, writeErr := fmt.Fprintf(ctx.Stderr, "grep: %s: %v\n", label, fileError(err)); writeErr != nil {
+			return selected, true, writeErr
+		}
+	}
+	return selected, true, nil
+}
+
+// tally учитывает результат файла. С -q после первого совпадения
+// возвращает errGrepQuit, чтобы остановить поиск.
+func (s *grepSearch) tally(selected int, failed bool) error {
+	if s.flags.filesWithoutMatch {
+		s.matched = s.matched || !failed && selected == 0
+	} else {
+		s.matched = s.matched || selected > 0
+	}
+	s.trouble = s.trouble || failed
+
+	// С -q grep завершается на первом совпадении
+	if s.flags.quiet && s.matched {
+		return errGrepQuit
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
+)
+
+// newGrepSearchTree создаёт count файлов в нескольких подкаталогах.
+// В каждом файле lines строк; строки с номером, кратным номеру файла
+// плюс один, содержат слово "needle".
+func newGrepSearchTree(tb testing.TB, count, lines int) string {
+	tb.Helper()
+	dir := tb.TempDir()
+	for i := 0; i < count; i++ {
+		sub := filepath.Join(dir, fmt.Sprintf("dir%02d", i%7))
+		if err := os.MkdirAll(sub, 0o755); err != nil {
+			tb.Fatal(err)
+		}
+
+		var b strings.Builder
+		for line := 0; line < lines; line++ {
+			if line%(i%13+1) == 0 {
+				fmt.Fprintf(&b, "file %d line %d needle\n", i, line)
+			} else {
+				fmt.Fprintf(&b, "file %d line %d hay\n", i, line)
+			}
+		}
+		name := filepath.Join(sub, fmt.Sprintf("file%03d.txt", i))
+		if err := os.WriteFile(name, []byte(b.String()), 0o644); err != nil {
+			tb.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestGrepParallelMatchesSequential(t *testing.T) {
+	dir := newGrepSearchTree(t, 120, 40)
+
+	argSets := [][]string{
+		{"-rn", "needle"},
+		{"-rc", "needle"},
+		{"-rl", "line 3 needle"},
+		{"-rL", "line 3 needle"},
+		{"-r", "-A1", "-m2", "needle"},
+		{"-rv", "needle", "dir01", "missing", "dir03"},
+	}
+
+	for _, args := range argSets {
+		t.Run(strings.Join(args, " "), func(t *testing.T) {
+			seqOut, seqErr, seqStatus := runCommand(t, &GrepCommand{}, dir, "", append([]string{"-j", "1"}, args...)...)
+			parOut, parErr, parStatus := runCommand(t, &GrepCommand{}, dir, "", append([]string{"-j", "8"}, args...)...)
+
+			if seqOut == "" && seqStatus != 1 {
+				t.Fatalf("пустой вывод последовательного поиска (код %d, stderr %q)", seqStatus, seqErr)
+			}
+			if parOut != seqOut || parErr != seqErr || parStatus != seqStatus {
+				t.Fatalf("параллельный поиск отличается от последовательного:\n%q (%q, %d)\n%q (%q, %d)",
+					parOut, parErr, parStatus, seqOut, seqErr, seqStatus)
+			}
+		})
+	}
+}
+
+func TestGrepParallelQuiet(t *testing.T) {
+	dir := newGrepSearchTree(t, 60, 10)
+
+	out, _, status := runCommand(t, &GrepCommand{}, dir, "", "-rq", "-j", "4", "needle", "missing", ".")
+	if out != "" || status != 0 {
+		t.Fatalf("ожидался код 0 без вывода, получено %q (код %d)", out, status)
+	}
+
+	_, _, status = runCommand(t, &GrepCommand{}, dir, "", "-rq", "-j", "4", "absent")
+	if status != 1 {
+		t.Fatalf("ожидался код 1, получено %d", status)
+	}
+}
+
+func TestGrepParallelFileOrder(t *testing.T) {
+	dir := newGrepTestDir(t)
+
+	out, stderr, status := runCommand(t, &GrepCommand{}, dir, "", "-j", "3", "-c", "a", "b.txt", "missing.txt", "a.txt", "b.txt")
+	expected := "b.txt:2\na.txt:3\nb.txt:2\n"
+	if out != expected || status != 2 || !strings.Contains(stderr, "missing.txt") {
+		t.Fatalf("ожидалось %q и ошибка для missing.txt, получено %q, %q (код %d)", expected, out, stderr, status)
+	}
+}
+
+func TestGrepInvalidJobs(t *testing.T) {
+	_, stderr, status := runCommand(t, &GrepCommand{}, t.TempDir(), "", "-j", "0", "x")
+	if status != 2 || !strings.Contains(stderr, "количество потоков") {
+		t.Fatalf("ожидалась ошибка -j 0: %q (код %d)", stderr, status)
+	}
+}
+
+// benchmarkGrep ищет по дереву из 300 файлов с заданным числом потоков.
+func benchmarkGrep(b *testing.B, jobs string) {
+	dir := newGrepSearchTree(b, 300, 500)
+	ctx := &CommandContext{
+		Stdin:  strings.NewReader(""),
+		Stdout: io.Discard,
+		Stderr: io.Discard,
+		Vars:   session.NewVariables(nil),
+		Dir:    dir,
+	}
+	args := []string{"-rn", "-j", jobs, `line \d+5 needle`}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := (&GrepCommand{}).Exec(args, ctx); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGrepSequential(b *testing.B) {
+	benchmarkGrep(b, "1")
+}
+
+func BenchmarkGrepParallel(b *testing.B) {
+	benchmarkGrep(b, "8")
+}
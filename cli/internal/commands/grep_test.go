@@ -6,6 +6,8 @@ import (
 	"os"
 	"strings"
 	"testing"
+
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
 )
 
 // testGrepExecWithOutput выполняет команду grep с переданными аргументами и возвращает stdout и stderr.
@@ -18,7 +20,7 @@ func testGrepExecWithOutput(cmd CommandExecutor, args []string, stdin io.Reader)
 		Stdin:  stdin,
 		Stdout: &stdout,
 		Stderr: &stderr,
-		Env:    make(map[string]string),
+		Vars:   session.NewVariables(nil),
 		Dir:    ".",
 	}
 	_ = cmd.Exec(args, ctx)
@@ -348,8 +350,126 @@ func TestGrepCommand_MultipleFiles(t *testing.T) {
 	cmd := &GrepCommand{}
 	out, _ := testGrepExecWithOutput(cmd, []string{"hello", file1, file2}, nil)
 
-	expected := "hello from file1\nhello from file2\n"
+	// Для нескольких файлов каждая строка предваряется именем файла
+	expected := file1 + ":hello from file1\n" + file2 + ":hello from file2\n"
 	if out != expected {
 		t.Errorf("ожидалось %q, получено %q", expected, out)
 	}
 }
+
+// newGrepTestDir создаёт каталог с файлами a.txt и b.txt.
+func newGrepTestDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string]string{
+		"a.txt": "alpha\nbeta\nalphabet\n",
+		"b.txt": "gamma\ndelta\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(dir+"/"+name, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestGrepCommand_OutputOptions(t *testing.T) {
+	dir := newGrepTestDir(t)
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+		status   int
+	}{
+		{"инверсия", []string{"-v", "alpha", "a.txt"}, "beta\n", 0},
+		{"количество", []string{"-c", "alpha", "a.txt"}, "2\n", 0},
+		{"количество по файлам", []string{"-c", "a", "a.txt", "b.txt"}, "a.txt:3\nb.txt:2\n", 0},
+		{"номера строк", []string{"-n", "bet", "a.txt"}, "2:beta\n3:alphabet\n", 0},
+		{"имя файла и номер", []string{"-Hn", "beta", "a.txt"}, "a.txt:2:beta\n", 0},
+		{"без имени файла", []string{"-h", "ta", "a.txt", "b.txt"}, "beta\ndelta\n", 0},
+		{"файлы с совпадениями", []string{"-l", "gamma", "a.txt", "b.txt"}, "b.txt\n", 0},
+		{"файлы без совпадений", []string{"-L", "gamma", "a.txt", "b.txt"}, "a.txt\n", 0},
+		{"только совпадения", []string{"-o", "al[a-z]", "a.txt"}, "alp\nalp\n", 0},
+		{"только совпадения словом", []string{"-ow", "alpha", "a.txt"}, "alpha\n", 0},
+		{"вся строка", []string{"-x", "alpha", "a.txt"}, "alpha\n", 0},
+		{"вся строка важнее -w", []string{"-xw", "alpha.*", "a.txt"}, "alpha\nalphabet\n", 0},
+		{"лимит", []string{"-m", "1", "a", "a.txt"}, "alpha\n", 0},
+		{"лимит с контекстом", []string{"-m1", "-A", "1", "alpha", "a.txt"}, "alpha\nbeta\n", 0},
+		{"контекст с номерами", []string{"-n", "-A", "1", "beta", "a.txt"}, "2:beta\n3-alphabet\n", 0},
+		{"нет совпадений", []string{"zeta", "a.txt"}, "", 1},
+		{"тихий режим", []string{"-q", "beta", "a.txt"}, "", 0},
+		{"нулевой лимит", []string{"-m", "0", "a", "a.txt"}, "", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, stderr, status := runCommand(t, &GrepCommand{}, dir, "", tt.args...)
+			if out != tt.expected {
+				t.Fatalf("ожидалось %q, получено %q (stderr %q)", tt.expected, out, stderr)
+			}
+			if status != tt.status {
+				t.Fatalf("ожидался код %d, получено %d", tt.status, status)
+			}
+		})
+	}
+}
+
+func TestGrepCommand_StdinName(t *testing.T) {
+	out, _, _ := runCommand(t, &GrepCommand{}, ".", "one\ntwo\n", "-H", "two")
+	if out != "(standard input):two\n" {
+		t.Fatalf("ожидалось %q, получено %q", "(standard input):two\n", out)
+	}
+}
+
+func TestGrepCommand_ErrorStatus(t *testing.T) {
+	dir := newGrepTestDir(t)
+
+	_, stderr, status := runCommand(t, &GrepCommand{}, dir, "", "beta", "a.txt", "missing.txt")
+	if status != 2 || !strings.Contains(stderr, "missing.txt") {
+		t.Fatalf("ожидался код 2 и сообщение об ошибке: %d, %q", status, stderr)
+	}
+	// Имя файла печатается так, как его указал пользователь
+	if !strings.HasPrefix(stderr, "grep: missing.txt: ") || strings.Contains(stderr, dir) || strings.Contains(stderr, "stat") {
+		t.Fatalf("ожидалось сообщение %q без пути каталога, получено %q", "grep: missing.txt: ...", stderr)
+	}
+
+	_, stderr, status = runCommand(t, &GrepCommand{}, dir, "", "-s", "beta", "missing.txt")
+	if status != 2 || stderr != "" {
+		t.Fatalf("-s должен подавлять сообщения: %d, %q", status, stderr)
+	}
+
+	_, _, status = runCommand(t, &GrepCommand{}, dir, "", "-qs", "beta", "missing.txt", "a.txt")
+	if status != 0 {
+		t.Fatalf("с -q совпадение важнее ошибки: ожидался код 0, получено %d", status)
+	}
+
+	_, _, status = runCommand(t, &GrepCommand{}, dir, "", "[invalid")
+	if status != 2 {
+		t.Fatalf("для некорректного выражения ожидался код 2, получено %d", status)
+	}
+}
+
+func TestGrepCommand_LongLines(t *testing.T) {
+	long := strings.Repeat(`{"id":1,"tags":["a","b"]},`, 3*1024*1024/26)
+	stdin := "short\n" + long + `{"needle":true}` + "\n" + long + "\n"
+
+	out, stderr, status := runCommand(t, &GrepCommand{}, t.TempDir(), stdin, "-n", "needle")
+	expected := "2:" + long + `{"needle":true}` + "\n"
+	if out != expected || status != 0 {
+		t.Fatalf("ожидалась вторая строка (%d байт), получено %d байт (код %d, stderr %q)",
+			len(expected), len(out), status, stderr)
+	}
+
+	out, _, _ = runCommand(t, &GrepCommand{}, t.TempDir(), stdin, "-c", "id")
+	if out != "2\n" {
+		t.Fatalf("ожидалось %q, получено %q", "2\n", out)
+	}
+}
+
+func TestGrepCommand_KeepsCarriageReturn(t *testing.T) {
+	out, _, _ := runCommand(t, &GrepCommand{}, t.TempDir(), "one\r\ntwo\r\n", "two")
+	if expected := "two\r\n"; out != expected {
+		t.Fatalf("ожидалось %q, получено %q", expected, out)
+	}
+}
@@ -0,0 +1,212 @@
+package commands
+
+import (
+	"errors"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/gitignore"
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/glob"
+)
+
+// stringList — значение флага, который можно указать несколько раз
+// (например, --include=*.go --include=*.md).
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// errGrepIsDir сообщает, что аргумент — каталог, а -r не указан.
+var errGrepIsDir = errors.New("это каталог")
+
+// grepWalker перечисляет файлы для поиска: файлы из аргументов и,
+// с -r/-R, файлы внутри каталогов. Порядок обхода детерминирован:
+// элементы каталога перебираются в лексикографическом порядке.
+type grepWalker struct {
+	ctx   *CommandContext
+	flags *grepFlags
+	// visit вызывается для каждого файла; err != nil сообщает об ошибке
+	// доступа к name. Ошибка, возвращённая visit, прерывает обход.
+	visit func(name string, err error) error
+}
+
+// walk обходит аргумент командной строки. Пустое имя означает текущий
+// каталог сеанса, пути внутри которого печатаются без префикса "./".
+func (w *grepWalker) walk(name string) error {
+	if name == "-" {
+		return w.visit(name, nil)
+	}
+
+	info, err := os.Stat(w.resolve(name))
+	if err != nil {
+		return w.visit(name, err)
+	}
+
+	if !info.IsDir() {
+		if !w.included(name) {
+			return nil
+		}
+		return w.visit(name, nil)
+	}
+
+	if !w.flags.recursive {
+		return w.visit(name, errGrepIsDir)
+	}
+	if name != "" && name != "." && w.excludedDir(path.Base(name)) {
+		return nil
+	}
+	return w.walkDir(name, "", nil, []os.FileInfo{info})
+}
+
+// walkDir обходит каталог name. rel — путь каталога относительно корня
+// обхода (для правил .gitignore), ancestors — каталоги на пути от корня
+// для защиты от циклов символических ссылок.
+func (w *grepWalker) walkDir(name, rel string, ignore *gitignore.Matcher, ancestors []os.FileInfo) error {
+	dir := w.resolve(name)
+
+	if w.flags.gitignore {
+		ignore = w.loadGitignore(dir, rel, ignore)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return w.visit(name, err)
+	}
+
+	for _, entry := range entries {
+		childName := joinGrepPath(name, entry.Name())
+		childRel := path.Join(rel, entry.Name())
+
+		info, err := entry.Info()
+		if err != nil {
+			if err := w.visit(childName, err); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Символические ссылки внутри каталогов обходятся только с -R
+		if info.Mode()&os.ModeSymlink != 0 {
+			if !w.flags.dereference {
+				continue
+			}
+			if info, err = os.Stat(w.resolve(childName)); err != nil {
+				if err := w.visit(childName, err); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		if w.flags.gitignore && (info.IsDir() && entry.Name() == ".git" || ignore.Ignored(childRel, info.IsDir())) {
+			continue
+		}
+
+		if info.IsDir() {
+			if w.excludedDir(entry.Name()) || isAncestor(info, ancestors) {
+				continue
+			}
+			if err := w.walkDir(childName, childRel, ignore, append(ancestors, info)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Устройства, сокеты и именованные каналы пропускаются
+		if !info.Mode().IsRegular() || !w.included(entry.Name()) {
+			continue
+		}
+		if err := w.visit(childName, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadGitignore добавляет к ignore правила из файла .gitignore каталога dir.
+func (w *grepWalker) loadGitignore(dir, rel string, ignore *gitignore.Matcher) *gitignore.Matcher {
+	file, err := os.Open(filepath.Join(dir, gitignore.FileName))
+	if err != nil {
+		return ignore
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	rules, err := gitignore.Parse(file, rel)
+	if err != nil {
+		return ignore
+	}
+	return ignore.With(rules)
+}
+
+// included проверяет имя файла по --include и --exclude.
+func (w *grepWalker) included(name string) bool {
+	base := path.Base(name)
+	for _, pattern := range w.flags.exclude {
+		if matchGrepGlob(pattern, base) {
+			return false
+		}
+	}
+
+	if len(w.flags.include) == 0 {
+		return true
+	}
+	for _, pattern := range w.flags.include {
+		if matchGrepGlob(pattern, base) {
+			return true
+		}
+	}
+	return false
+}
+
+// excludedDir проверяет имя каталога по --exclude-dir.
+func (w *grepWalker) excludedDir(name string) bool {
+	for _, pattern := range w.flags.excludeDir {
+		if matchGrepGlob(pattern, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *grepWalker) resolve(name string) string {
+	if name == "" {
+		name = "."
+	}
+	return w.ctx.ResolvePath(name)
+}
+
+func matchGrepGlob(pattern, name string) bool {
+	return glob.Match(pattern, name, glob.Options{DotGlob: true, ExtGlob: true})
+}
+
+// isAncestor сообщает, что каталог info уже встречался на пути обхода.
+func isAncestor(info os.FileInfo, ancestors []os.FileInfo) bool {
+	for _, ancestor := range ancestors {
+		if os.SameFile(info, ancestor) {
+			return true
+		}
+	}
+	return false
+}
+
+// joinGrepPath добавляет имя к пути каталога в том виде, в котором
+// его записал пользователь ("." → "./name", "" → "name").
+func joinGrepPath(dir, name string) string {
+	switch {
+	case dir == "":
+		return name
+	case strings.HasSuffix(dir, "/"):
+		return dir + name
+	}
+	return dir + "/" + name
+}
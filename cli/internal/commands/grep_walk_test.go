@@ -0,0 +1,196 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newGrepTree создаёт дерево файлов для тестов рекурсивного поиска.
+// Ключ — путь относительно корня, значение — содержимое файла.
+func newGrepTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestGrepRecursive(t *testing.T) {
+	dir := newGrepTree(t, map[string]string{
+		"b.go":           "TODO b\n",
+		"a.txt":          "TODO a\n",
+		"src/main.go":    "package main // TODO\n",
+		"src/z/deep.md":  "TODO deep\n",
+		"vendor/lib.go":  "TODO vendor\n",
+		"src/notes.txt":  "nothing here\n",
+		".hidden/x.conf": "TODO hidden\n",
+	})
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{
+			"без аргументов — текущий каталог без ./",
+			[]string{"-r", "TODO"},
+			".hidden/x.conf:TODO hidden\na.txt:TODO a\nb.go:TODO b\nsrc/main.go:package main // TODO\n" +
+				"src/z/deep.md:TODO deep\nvendor/lib.go:TODO vendor\n",
+		},
+		{
+			"явный каталог .",
+			[]string{"-rl", "TODO", "."},
+			"./.hidden/x.conf\n./a.txt\n./b.go\n./src/main.go\n./src/z/deep.md\n./vendor/lib.go\n",
+		},
+		{
+			"подкаталог",
+			[]string{"-r", "TODO", "src"},
+			"src/main.go:package main // TODO\nsrc/z/deep.md:TODO deep\n",
+		},
+		{
+			"--include",
+			[]string{"-rl", "--include=*.go", "TODO"},
+			"b.go\nsrc/main.go\nvendor/lib.go\n",
+		},
+		{
+			"--exclude и --exclude-dir",
+			[]string{"-rl", "--exclude=b.*", "--exclude-dir=src", "--exclude-dir=.*", "TODO"},
+			"a.txt\nvendor/lib.go\n",
+		},
+		{
+			"-h без имён файлов",
+			[]string{"-rh", "TODO", "src/z"},
+			"TODO deep\n",
+		},
+		{
+			"-c по каждому файлу",
+			[]string{"-rc", "TODO", "src"},
+			"src/main.go:1\nsrc/notes.txt:0\nsrc/z/deep.md:1\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, stderr, status := runCommand(t, &GrepCommand{}, dir, "", tt.args...)
+			if out != tt.expected || status != 0 {
+				t.Fatalf("ожидалось %q, получено %q (код %d, stderr %q)", tt.expected, out, status, stderr)
+			}
+		})
+	}
+}
+
+func TestGrepDirectoryWithoutRecursive(t *testing.T) {
+	dir := newGrepTree(t, map[string]string{"src/a.txt": "x\n", "b.txt": "x\n"})
+
+	out, stderr, status := runCommand(t, &GrepCommand{}, dir, "", "x", "src", "b.txt")
+	if out != "b.txt:x\n" || status != 2 || stderr != "grep: src: это каталог\n" {
+		t.Fatalf("ожидалась ошибка для каталога: %q, %q, код %d", out, stderr, status)
+	}
+}
+
+func TestGrepRecursiveQuiet(t *testing.T) {
+	dir := newGrepTree(t, map[string]string{"a.txt": "x\n", "b.txt": "x\n"})
+
+	out, _, status := runCommand(t, &GrepCommand{}, dir, "", "-rq", "x")
+	if out != "" || status != 0 {
+		t.Fatalf("ожидался код 0 без вывода, получено %q, код %d", out, status)
+	}
+}
+
+func TestGrepGitignore(t *testing.T) {
+	dir := newGrepTree(t, map[string]string{
+		".gitignore":      "*.log\nbuild/\n!keep.log\n",
+		"app.go":          "secret\n",
+		"debug.log":       "secret\n",
+		"keep.log":        "secret\n",
+		"build/out.go":    "secret\n",
+		".git/config":     "secret\n",
+		"sub/.gitignore":  "local.txt\n",
+		"sub/local.txt":   "secret\n",
+		"sub/shared.txt":  "secret\n",
+		"other/local.txt": "secret\n",
+	})
+
+	out, _, _ := runCommand(t, &GrepCommand{}, dir, "", "-rl", "--gitignore", "secret")
+	expected := "app.go\nkeep.log\nother/local.txt\nsub/shared.txt\n"
+	if out != expected {
+		t.Fatalf("ожидалось %q, получено %q", expected, out)
+	}
+
+	// Без --gitignore правила не применяются
+	out, _, _ = runCommand(t, &GrepCommand{}, dir, "", "-rl", "secret", "build")
+	if out != "build/out.go\n" {
+		t.Fatalf("ожидалось %q, получено %q", "build/out.go\n", out)
+	}
+}
+
+func TestGrepRecursiveSymlinks(t *testing.T) {
+	dir := newGrepTree(t, map[string]string{"real/a.txt": "x\n"})
+	if err := os.Symlink("real", filepath.Join(dir, "link")); err != nil {
+		t.Skipf("символические ссылки недоступны: %v", err)
+	}
+	// Ссылка на предка не должна зациклить обход
+	if err := os.Symlink("..", filepath.Join(dir, "real", "loop")); err != nil {
+		t.Fatal(err)
+	}
+
+	out, _, _ := runCommand(t, &GrepCommand{}, dir, "", "-rl", "x")
+	if out != "real/a.txt\n" {
+		t.Fatalf("-r не должен следовать ссылкам: %q", out)
+	}
+
+	out, _, _ = runCommand(t, &GrepCommand{}, dir, "", "-Rl", "x")
+	if out != "link/a.txt\nreal/a.txt\n" {
+		t.Fatalf("-R должен следовать ссылкам: %q", out)
+	}
+}
+
+func TestGrepBinaryFiles(t *testing.T) {
+	dir := newGrepTree(t, map[string]string{
+		"bin.dat": "header\x00\nmatch here\n",
+		// Нулевой байт за пределами проверяемого начала файла
+		"late.dat": "match first\n" + strings.Repeat("text\n", grepBinaryPeek/5) + "then\x00match\n",
+		"text.txt": "match text\n",
+	})
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+		status   int
+	}{
+		{"по умолчанию", []string{"match", "bin.dat", "text.txt"},
+			"Binary file bin.dat matches\ntext.txt:match text\n", 0},
+		{"-a", []string{"-a", "match", "bin.dat"}, "match here\n", 0},
+		{"--binary-files=text", []string{"--binary-files=text", "-c", "match", "bin.dat"}, "1\n", 0},
+		{"-I", []string{"-I", "match", "bin.dat"}, "", 1},
+		{"-I с текстом", []string{"-rlI", "match"}, "late.dat\ntext.txt\n", 0},
+		{"-c считает строки", []string{"-c", "match", "bin.dat"}, "1\n", 0},
+		{"нулевой байт после совпадения", []string{"match", "late.dat"},
+			"match first\nBinary file late.dat matches\n", 0},
+		{"стандартный ввод", []string{"match"}, "Binary file (standard input) matches\n", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, _, status := runCommand(t, &GrepCommand{}, dir, "a\x00\nmatch\n", tt.args...)
+			if out != tt.expected || status != tt.status {
+				t.Fatalf("ожидалось %q (код %d), получено %q (код %d)", tt.expected, tt.status, out, status)
+			}
+		})
+	}
+
+	_, stderr, status := runCommand(t, &GrepCommand{}, dir, "", "--binary-files=maybe", "x")
+	if status != 2 || !strings.Contains(stderr, "maybe") {
+		t.Fatalf("ожидалась ошибка неизвестного режима: %q, код %d", stderr, status)
+	}
+}
@@ -0,0 +1,356 @@
+package commands
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+
+	customErrors "github.com/ggerlakh/software-design-mhs-itmo/cli/internal/errors"
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/linereader"
+)
+
+// HeadCommand реализует встроенную команду "head".
+// Она выводит начало файлов или stdin: первые N строк или байт,
+// либо всё, кроме последних N строк или байт.
+type HeadCommand struct{}
+
+// headValueFlags перечисляет короткие флаги head и tail, принимающие значение.
+const headValueFlags = "ncs"
+
+// headDefaultLines — количество строк, которое head и tail выводят по умолчанию.
+const headDefaultLines = 10
+
+// countSuffixes — множители суффиксов количества, как в GNU coreutils.
+var countSuffixes = map[string]int64{
+	"b":   512,
+	"kB":  1000,
+	"K":   1 << 10,
+	"KiB": 1 << 10,
+	"MB":  1000 * 1000,
+	"M":   1 << 20,
+	"MiB": 1 << 20,
+	"GB":  1000 * 1000 * 1000,
+	"G":   1 << 30,
+	"GiB": 1 << 30,
+}
+
+// countArg — количество строк или байт для head и tail с необязательным знаком.
+type countArg struct {
+	value int64
+	sign  byte // '+', '-' или 0
+}
+
+// parseCount разбирает количество вида [+-]NUM[суффикс], например "10", "-5",
+// "+3" или "2K".
+func parseCount(text string) (countArg, error) {
+	var count countArg
+	digits := text
+	if digits != "" && (digits[0] == '+' || digits[0] == '-') {
+		count.sign, digits = digits[0], digits[1:]
+	}
+
+	end := 0
+	for end < len(digits) && isDigit(digits[end]) {
+		end++
+	}
+	if end == 0 {
+		return countArg{}, fmt.Errorf("неверное количество: %q", text)
+	}
+
+	value, err := strconv.ParseInt(digits[:end], 10, 64)
+	if err != nil {
+		return countArg{}, fmt.Errorf("неверное количество: %q", text)
+	}
+	if suffix := digits[end:]; suffix != "" {
+		multiplier, ok := countSuffixes[suffix]
+		if !ok || value > (1<<63-1)/multiplier {
+			return countArg{}, fmt.Errorf("неверное количество: %q", text)
+		}
+		value *= multiplier
+	}
+	count.value = value
+	return count, nil
+}
+
+// parseCountFlags возвращает количество, заданное флагами -n/--lines или
+// -c/--bytes (второе значение — true для байт). Если ни один флаг не задан,
+// возвращается количество строк по умолчанию.
+func parseCountFlags(fs *flag.FlagSet, lines, bytes string) (countArg, bool, error) {
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+
+	switch {
+	case set["c"] || set["bytes"]:
+		count, err := parseCount(bytes)
+		return count, true, err
+	case set["n"] || set["lines"]:
+		count, err := parseCount(lines)
+		return count, false, err
+	}
+	return countArg{value: headDefaultLines}, false, nil
+}
+
+// headOptions — параметры head.
+type headOptions struct {
+	bytes   bool  // считать байты (-c), а не строки (-n)
+	count   int64 // количество строк или байт
+	allBut  bool  // вывести всё, кроме последних count (-n -N, -c -N)
+	quiet   bool  // не печатать заголовки (-q)
+	verbose bool  // печатать заголовки всегда (-v)
+}
+
+// Name возвращает имя команды.
+func (h *HeadCommand) Name() string {
+	return "head"
+}
+
+// Exec выполняет команду head.
+//
+// Синтаксис:
+//
+//	head [-n [-]NUM | -c [-]NUM | -NUM] [-q | -v] [FILE...]
+//
+// Если файлы не указаны или указан "-", читается stdin. Если файлов
+// несколько, перед каждым печатается заголовок "==> FILE <==".
+// Недоступный файл не прерывает вывод остальных, но команда возвращает
+// ExitStatusError с кодом 1.
+//
+// Примеры:
+//
+//	head file.txt         → первые 10 строк
+//	head -n 3 file.txt    → первые 3 строки
+//	head -n -2 file.txt   → всё, кроме последних 2 строк
+//	head -c 1K file.bin   → первые 1024 байта
+func (h *HeadCommand) Exec(args []string, ctx *CommandContext) error {
+	opts, files, err := parseHeadArgs(args)
+	if err != nil {
+		if _, writeErr := fmt.Fprintf(ctx.Stderr, "head: %v\n", err); writeErr != nil {
+			return writeErr
+		}
+		return &customErrors.ExitStatusError{Code: 1}
+	}
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+
+	headers := opts.verbose || len(files) > 1 && !opts.quiet
+	failed := false
+	printed := false
+	for _, name := range files {
+		reader, closeInput, err := openInput(name, ctx)
+		if err != nil {
+			failed = true
+			if _, writeErr := fmt.Fprintf(ctx.Stderr, "head: не удалось открыть %s: %v\n", name, fileError(err)); writeErr != nil {
+				return writeErr
+			}
+			continue
+		}
+
+		if headers {
+			if err := printFileHeader(ctx.Stdout, displayName(name), printed); err != nil {
+				closeInput()
+				return err
+			}
+			printed = true
+		}
+
+		source := &catReader{r: reader}
+		err = opts.copy(ctx.Stdout, source)
+		closeInput()
+		if source.err != nil {
+			failed = true
+			if _, writeErr := fmt.Fprintf(ctx.Stderr, "head: ошибка чтения %s: %v\n", name, fileError(source.err)); writeErr != nil {
+				return writeErr
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if failed {
+		return &customErrors.ExitStatusError{Code: 1}
+	}
+	return nil
+}
+
+// parseHeadArgs разбирает аргументы head.
+func parseHeadArgs(args []string) (*headOptions, []string, error) {
+	fs := flag.NewFlagSet("head", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	lines := fs.String("n", "", "количество строк")
+	fs.StringVar(lines, "lines", "", "количество строк")
+	bytes := fs.String("c", "", "количество байт")
+	fs.StringVar(bytes, "bytes", "", "количество байт")
+	opts := &headOptions{}
+	fs.BoolVar(&opts.quiet, "q", false, "не печатать заголовки")
+	fs.BoolVar(&opts.quiet, "quiet", false, "не печатать заголовки")
+	fs.BoolVar(&opts.quiet, "silent", false, "не печатать заголовки")
+	fs.BoolVar(&opts.verbose, "v", false, "всегда печатать заголовки")
+	fs.BoolVar(&opts.verbose, "verbose", false, "всегда печатать заголовки")
+
+	if err := fs.Parse(numericFlags(splitShortFlags(args, headValueFlags), "n", headValueFlags)); err != nil {
+		return nil, nil, fmt.Errorf("ошибка разбора флагов: %w", err)
+	}
+
+	count, countBytes, err := parseCountFlags(fs, *lines, *bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	opts.bytes = countBytes
+	opts.count, opts.allBut = count.value, count.sign == '-'
+
+	return opts, fs.Args(), nil
+}
+
+// copy выводит начало r в w согласно параметрам.
+func (o *headOptions) copy(w io.Writer, r io.Reader) error {
+	switch {
+	case o.bytes && o.allBut:
+		return copyAllButLastBytes(w, r, o.count)
+	case o.bytes:
+		_, err := io.CopyN(w, r, o.count)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		return err
+	case o.allBut:
+		return copyAllButLastLines(w, r, o.count)
+	default:
+		return copyFirstLines(w, r, o.count)
+	}
+}
+
+// copyFirstLines выводит первые count строк r.
+func copyFirstLines(w io.Writer, r io.Reader, count int64) error {
+	lines := linereader.New(r)
+	for ; count > 0; count-- {
+		line, err := lines.ReadLine()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if _, writeErr := w.Write(line); writeErr != nil {
+			return writeErr
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyAllButLastLines выводит все строки r, кроме последних count.
+// Строка выводится, как только становится известно, что за ней
+// следует ещё count строк, поэтому в памяти хранится не больше count строк.
+func copyAllButLastLines(w io.Writer, r io.Reader, count int64) error {
+	if count == 0 {
+		_, err := io.Copy(w, r)
+		return err
+	}
+
+	lines := linereader.New(r)
+	ring := make([][]byte, 0, min(count, 1024))
+	next := 0
+	for {
+		line, err := lines.ReadLine()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if int64(len(ring)) < count {
+			ring = append(ring, append([]byte(nil), line...))
+			continue
+		}
+		if _, err := w.Write(ring[next]); err != nil {
+			return err
+		}
+		ring[next] = append(ring[next][:0], line...)
+		next = (next + 1) % len(ring)
+	}
+}
+
+// copyAllButLastBytes выводит все байты r, кроме последних count.
+func copyAllButLastBytes(w io.Writer, r io.Reader, count int64) error {
+	reader := bufio.NewReaderSize(r, linereader.BufferSize)
+	var held []byte // последние прочитанные байты, которые ещё нельзя выводить
+	chunk := make([]byte, linereader.BufferSize)
+	for {
+		n, err := reader.Read(chunk)
+		held = append(held, chunk[:n]...)
+		if excess := int64(len(held)) - count; excess > 0 {
+			if _, writeErr := w.Write(held[:excess]); writeErr != nil {
+				return writeErr
+			}
+			held = append(held[:0], held[excess:]...)
+		}
+
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// printFileHeader печатает заголовок "==> name <==" перед выводом файла.
+// Перед всеми заголовками, кроме первого, печатается пустая строка.
+func printFileHeader(w io.Writer, name string, separate bool) error {
+	header := "==> " + name + " <==\n"
+	if separate {
+		header = "\n" + header
+	}
+	_, err := io.WriteString(w, header)
+	return err
+}
+
+// Help возвращает справку по команде head.
+func (h *HeadCommand) Help() string {
+	return `NAME
+    head - выводит начало файлов
+
+SYNOPSIS
+    head [OPTION]... [FILE]...
+
+DESCRIPTION
+    Выводит первые 10 строк каждого файла. Если файл не указан или указан
+    "-", читается стандартный ввод. Если файлов несколько, перед каждым
+    печатается заголовок "==> FILE <==".
+
+OPTIONS
+    -n, --lines=[-]NUM   вывести первые NUM строк; с "-" — все строки,
+                         кроме последних NUM
+    -c, --bytes=[-]NUM   вывести первые NUM байт; с "-" — все байты,
+                         кроме последних NUM
+    -NUM                 то же, что -n NUM
+    -q, --quiet          не печатать заголовки
+    -v, --verbose        всегда печатать заголовки
+
+    NUM может иметь суффикс-множитель: b (512), kB (1000), K (1024),
+    MB, M, GB, G.
+
+EXIT STATUS
+    0 — успех, 1 — неверные аргументы или ошибка чтения файла.
+
+EXAMPLES
+    head -n 3 file.txt
+        → первые 3 строки
+
+    head -n -1 file.txt
+        → все строки, кроме последней
+
+    cat log.txt | head -c 1K
+        → первые 1024 байта`
+}
+
+var _ BuiltinCommand = (*HeadCommand)(nil)
@@ -0,0 +1,148 @@
+package commands
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
+)
+
+// runCommand выполняет cmd в каталоге dir и возвращает stdout, stderr и код возврата.
+func runCommand(t *testing.T, cmd CommandExecutor, dir, stdin string, args ...string) (string, string, int) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	ctx := &CommandContext{
+		Stdin:  strings.NewReader(stdin),
+		Stdout: &stdout,
+		Stderr: &stderr,
+		Vars:   session.NewVariables(nil),
+		Dir:    dir,
+	}
+	err := cmd.Exec(args, ctx)
+	return stdout.String(), stderr.String(), testStatus(err)
+}
+
+// numberedLines возвращает строки с номерами от from до to включительно.
+func numberedLines(from, to int) string {
+	var b strings.Builder
+	for i := from; i <= to; i++ {
+		b.WriteString(strconv.Itoa(i))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+func TestHeadCommand_Counts(t *testing.T) {
+	input := numberedLines(1, 12)
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{nil, numberedLines(1, 10)},
+		{[]string{"-n", "3"}, "1\n2\n3\n"},
+		{[]string{"-n3"}, "1\n2\n3\n"},
+		{[]string{"-3"}, "1\n2\n3\n"},
+		{[]string{"--lines=2"}, "1\n2\n"},
+		{[]string{"-n", "-9"}, "1\n2\n3\n"},
+		{[]string{"-n", "-20"}, ""},
+		{[]string{"-n", "0"}, ""},
+		{[]string{"-n", "-0"}, input},
+		{[]string{"-c", "4"}, "1\n2\n"},
+		{[]string{"-c", "-19"}, "1\n2\n3\n4\n"},
+		{[]string{"--bytes=1K"}, input},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			out, stderr, status := runCommand(t, &HeadCommand{}, "", input, tt.args...)
+			if status != 0 || stderr != "" {
+				t.Fatalf("ожидался код 0 без ошибок, получено %d: %q", status, stderr)
+			}
+			if out != tt.expected {
+				t.Errorf("ожидалось %q, получено %q", tt.expected, out)
+			}
+		})
+	}
+}
+
+func TestHeadCommand_LastLineWithoutNewline(t *testing.T) {
+	out, _, _ := runCommand(t, &HeadCommand{}, "", "a\nb\nc", "-n", "-1")
+	if out != "a\nb\n" {
+		t.Errorf("ожидалось %q, получено %q", "a\nb\n", out)
+	}
+
+	out, _, _ = runCommand(t, &HeadCommand{}, "", "a\r\nb", "-n", "5")
+	if out != "a\r\nb" {
+		t.Errorf("ожидалось %q, получено %q", "a\r\nb", out)
+	}
+}
+
+func TestHeadCommand_MultipleFiles(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a1\na2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "b.txt"), []byte("b1\nb2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, stderr, status := runCommand(t, &HeadCommand{}, dir, "in\n", "-n1", "a.txt", "missing.txt", "-", "b.txt")
+	expected := "==> a.txt <==\na1\n\n==> standard input <==\nin\n\n==> b.txt <==\nb1\n"
+	if out != expected {
+		t.Errorf("ожидалось %q, получено %q", expected, out)
+	}
+	if status != 1 || !strings.Contains(stderr, "missing.txt") {
+		t.Errorf("ожидался код 1 и ошибка о missing.txt, получено %d: %q", status, stderr)
+	}
+
+	out, _, _ = runCommand(t, &HeadCommand{}, dir, "", "-q", "-n1", "a.txt", "b.txt")
+	if out != "a1\nb1\n" {
+		t.Errorf("ожидалось %q, получено %q", "a1\nb1\n", out)
+	}
+
+	out, _, _ = runCommand(t, &HeadCommand{}, dir, "", "-v", "-n1", "a.txt")
+	if out != "==> a.txt <==\na1\n" {
+		t.Errorf("ожидалось %q, получено %q", "==> a.txt <==\na1\n", out)
+	}
+}
+
+func TestHeadCommand_InvalidCount(t *testing.T) {
+	for _, count := range []string{"abc", "5X", "", "+"} {
+		_, stderr, status := runCommand(t, &HeadCommand{}, "", "", "-n", count)
+		if status != 1 || stderr == "" {
+			t.Errorf("-n %q: ожидался код 1 и сообщение об ошибке, получено %d: %q", count, status, stderr)
+		}
+	}
+}
+
+func TestParseCount(t *testing.T) {
+	tests := []struct {
+		text  string
+		value int64
+		sign  byte
+	}{
+		{"10", 10, 0},
+		{"+3", 3, '+'},
+		{"-7", 7, '-'},
+		{"2b", 1024, 0},
+		{"1kB", 1000, 0},
+		{"1K", 1024, 0},
+		{"1KiB", 1024, 0},
+		{"3M", 3 << 20, 0},
+		{"1GB", 1000 * 1000 * 1000, 0},
+	}
+
+	for _, tt := range tests {
+		count, err := parseCount(tt.text)
+		if err != nil {
+			t.Fatalf("%q: неожиданная ошибка %v", tt.text, err)
+		}
+		if count.value != tt.value || count.sign != tt.sign {
+			t.Errorf("%q: ожидалось %d/%q, получено %d/%q", tt.text, tt.value, tt.sign, count.value, count.sign)
+		}
+	}
+}
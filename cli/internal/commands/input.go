@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"errors"
+	"io"
+	"os"
+)
+
+// stdinName — имя стандартного ввода в заголовках head и tail.
+const stdinName = "standard input"
+
+// openInput открывает файл name относительно рабочего каталога ctx;
+// "-" означает стандартный ввод. Возвращённую функцию нужно вызвать
+// после чтения: она закрывает файл.
+func openInput(name string, ctx *CommandContext) (io.Reader, func(), error) {
+	if name == "-" {
+		return ctx.Stdin, func() {}, nil
+	}
+
+	//nolint:gosec // открываем файлы, указанные пользователем, как обычные утилиты
+	file, err := os.Open(ctx.ResolvePath(name))
+	if err != nil {
+		return nil, nil, err
+	}
+	return file, func() { _ = file.Close() }, nil
+}
+
+// displayName возвращает имя файла для заголовков и сообщений.
+func displayName(name string) string {
+	if name == "-" {
+		return stdinName
+	}
+	return name
+}
+
+// fileError убирает из ошибки файловой операции имя операции и путь:
+// имя файла команда печатает сама, в том виде, в котором его указал пользователь.
+// Ошибки os.Link, os.Symlink и os.Rename содержат оба пути.
+func fileError(err error) error {
+	var pathErr *os.PathError
+	if errors.As(err, &pathErr) {
+		return pathErr.Err
+	}
+	var linkErr *os.LinkError
+	if errors.As(err, &linkErr) {
+		return linkErr.Err
+	}
+	var syscallErr *os.SyscallError
+	if errors.As(err, &syscallErr) {
+		return syscallErr.Err
+	}
+	return err
+}
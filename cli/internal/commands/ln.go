@@ -0,0 +1,190 @@
+package commands
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	customErrors "github.com/ggerlakh/software-design-mhs-itmo/cli/internal/errors"
+)
+
+// LnCommand реализует встроенную команду "ln".
+// Она создаёт жёсткие и символьные ссылки.
+type LnCommand struct{}
+
+// lnOptions — параметры ln.
+type lnOptions struct {
+	symbolic      bool // -s: символьные ссылки вместо жёстких
+	force         bool // -f: заменять существующие файлы
+	noDereference bool // -n: ссылку на каталог в LINK_NAME считать файлом
+	verbose       bool // -v: сообщать о каждой ссылке
+}
+
+// Name возвращает имя команды.
+func (l *LnCommand) Name() string {
+	return "ln"
+}
+
+// Exec выполняет команду ln.
+//
+// Синтаксис:
+//
+//	ln [-s] [-f] [-n] [-v] TARGET [LINK_NAME]
+//	ln [-s] [-f] [-n] [-v] TARGET... DIRECTORY
+//
+// Без LINK_NAME ссылка создаётся в текущем каталоге под именем TARGET.
+// Цель символьной ссылки записывается как есть: относительная цель
+// отсчитывается от каталога ссылки, а не от текущего.
+//
+// Примеры:
+//
+//	ln -s /opt/app/bin/app bin/app       → символьная ссылка
+//	ln -sf v2 current                    → перенаправить ссылку current
+//	ln data.txt backup/                  → жёсткая ссылка в каталоге
+func (l *LnCommand) Exec(args []string, ctx *CommandContext) error {
+	opts, targets, dest, err := parseLnArgs(args)
+	if err != nil {
+		if writeErr := warnf(ctx, "%v\nПопробуйте 'ln --help' для получения справки.", err); writeErr != nil {
+			return writeErr
+		}
+		return &customErrors.ExitStatusError{Code: 1}
+	}
+
+	links, err := transferTargets(ctx, "ln", targets, dest, opts.noDereference)
+	if err != nil {
+		return err
+	}
+	if links == nil {
+		return &customErrors.ExitStatusError{Code: 1}
+	}
+
+	failed := false
+	for i, target := range targets {
+		ok, err := opts.link(ctx, target, links[i])
+		if err != nil {
+			return err
+		}
+		failed = failed || !ok
+	}
+	if failed {
+		return &customErrors.ExitStatusError{Code: 1}
+	}
+	return nil
+}
+
+// parseLnArgs разбирает аргументы ln и возвращает цели ссылок и имя
+// ссылки или каталог.
+func parseLnArgs(args []string) (*lnOptions, []string, string, error) {
+	fs := flag.NewFlagSet("ln", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	opts := &lnOptions{}
+	for _, names := range []struct {
+		short, long string
+		value       *bool
+		usage       string
+	}{
+		{"s", "symbolic", &opts.symbolic, "создавать символьные ссылки"},
+		{"f", "force", &opts.force, "заменять существующие файлы"},
+		{"n", "no-dereference", &opts.noDereference, "не раскрывать ссылку на каталог в LINK_NAME"},
+		{"v", "verbose", &opts.verbose, "сообщать о каждой ссылке"},
+	} {
+		fs.BoolVar(names.value, names.short, false, names.usage)
+		fs.BoolVar(names.value, names.long, false, names.usage)
+	}
+
+	if err := parseOptions(fs, splitShortFlags(args, "")); err != nil {
+		return nil, nil, "", err
+	}
+	switch fs.NArg() {
+	case 0:
+		return nil, nil, "", errors.New("ln: пропущен операнд, задающий файл")
+	case 1:
+		return opts, fs.Args(), ".", nil
+	}
+	last := fs.NArg() - 1
+	return opts, fs.Args()[:last], fs.Arg(last), nil
+}
+
+// link создаёт ссылку link на target. Возвращает false, если создать не
+// удалось (сообщение уже напечатано).
+func (o *lnOptions) link(ctx *CommandContext, target string, link transfer) (bool, error) {
+	kind, arrow := "жёсткую ссылку", "=>"
+	if o.symbolic {
+		kind, arrow = "символьную ссылку", "->"
+	}
+	fail := func(err error) (bool, error) {
+		return false, warnf(ctx, "ln: не удалось создать %s '%s' %s '%s': %v", kind, link.name, arrow, target, fileError(err))
+	}
+
+	if info, err := os.Lstat(link.path); err == nil {
+		if !o.force {
+			return fail(os.ErrExist)
+		}
+		if info.IsDir() {
+			return false, warnf(ctx, "ln: '%s': невозможно заменить каталог", link.name)
+		}
+		if targetInfo, err := os.Stat(ctx.ResolvePath(target)); err == nil && !o.symbolic && os.SameFile(info, targetInfo) {
+			return false, warnf(ctx, "ln: '%s' и '%s' — один и тот же файл", target, link.name)
+		}
+		if err := os.Remove(link.path); err != nil {
+			return fail(err)
+		}
+	}
+
+	var err error
+	if o.symbolic {
+		err = os.Symlink(target, link.path)
+	} else {
+		err = os.Link(ctx.ResolvePath(target), link.path)
+	}
+	if err != nil {
+		return fail(err)
+	}
+	if !o.verbose {
+		return true, nil
+	}
+	_, err = fmt.Fprintf(ctx.Stdout, "'%s' %s '%s'\n", link.name, arrow, target)
+	return err == nil, err
+}
+
+// Help возвращает справку по команде ln.
+func (l *LnCommand) Help() string {
+	return `NAME
+    ln - создаёт ссылки на файлы
+
+SYNOPSIS
+    ln [OPTION]... TARGET [LINK_NAME]
+    ln [OPTION]... TARGET... DIRECTORY
+
+DESCRIPTION
+    Создаёт ссылку LINK_NAME на файл TARGET. Без LINK_NAME ссылка
+    создаётся в текущем каталоге с именем TARGET; если LINK_NAME —
+    каталог, ссылки создаются в нём. По умолчанию создаются жёсткие
+    ссылки, с -s — символьные.
+
+    Цель символьной ссылки сохраняется как есть: относительный путь
+    отсчитывается от каталога, в котором лежит ссылка.
+
+OPTIONS
+    -s, --symbolic          создавать символьные ссылки
+    -f, --force             заменять существующие файлы с именем ссылки
+    -n, --no-dereference    если LINK_NAME — символьная ссылка на каталог,
+                            заменять её саму (вместе с -f), а не создавать
+                            ссылку внутри каталога
+    -v, --verbose           сообщать о каждой созданной ссылке
+
+EXIT STATUS
+    0 — успех, 1 — хотя бы одну ссылку создать не удалось.
+
+EXAMPLES
+    ln -s ../shared/config.yaml config.yaml
+        → символьная ссылка на общий файл настроек
+
+    ln -sfn releases/v2 current
+        → переключить ссылку current на новую версию`
+}
+
+var _ BuiltinCommand = (*LnCommand)(nil)
@@ -0,0 +1,86 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLnCommand(t *testing.T) {
+	dir := t.TempDir()
+	makeTree(t, dir, map[string]string{"a.txt": "alpha", "b.txt": "beta", "links/": ""})
+
+	out, stderr, status := runCommand(t, &LnCommand{}, dir, "", "-sv", "a.txt", "soft")
+	if status != 0 || stderr != "" || out != "'soft' -> 'a.txt'\n" {
+		t.Fatalf("неожиданный результат: %d, %q, %q", status, out, stderr)
+	}
+	if target, err := os.Readlink(filepath.Join(dir, "soft")); err != nil || target != "a.txt" {
+		t.Errorf("ожидалась ссылка на %q, получено %q, %v", "a.txt", target, err)
+	}
+
+	_, stderr, status = runCommand(t, &LnCommand{}, dir, "", "a.txt", "b.txt", "links")
+	if status != 0 || stderr != "" {
+		t.Fatalf("ожидался код 0, получено %d: %q", status, stderr)
+	}
+	for _, name := range []string{"a.txt", "b.txt"} {
+		original, _ := os.Stat(filepath.Join(dir, name))
+		linked, err := os.Stat(filepath.Join(dir, "links", name))
+		if err != nil || !os.SameFile(original, linked) {
+			t.Errorf("%s: ожидалась жёсткая ссылка: %v", name, err)
+		}
+	}
+
+	_, stderr, status = runCommand(t, &LnCommand{}, dir, "", "-s", "b.txt", "soft")
+	if status != 1 || !strings.Contains(stderr, "ln: не удалось создать символьную ссылку 'soft' -> 'b.txt'") {
+		t.Errorf("без -f существующий файл — ошибка, получено %d: %q", status, stderr)
+	}
+	_, stderr, status = runCommand(t, &LnCommand{}, dir, "", "-sf", "b.txt", "soft")
+	if status != 0 || stderr != "" {
+		t.Fatalf("ожидался код 0, получено %d: %q", status, stderr)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "soft")); string(data) != "beta" {
+		t.Errorf("ожидалось %q, получено %q", "beta", data)
+	}
+
+	_, stderr, status = runCommand(t, &LnCommand{}, dir, "", "-f", "a.txt", "links/a.txt")
+	if status != 1 || !strings.Contains(stderr, "один и тот же файл") {
+		t.Errorf("ожидалась ошибка одного и того же файла, получено %d: %q", status, stderr)
+	}
+	// Ошибка системного вызова печатается без путей и имени вызова
+	_, stderr, status = runCommand(t, &LnCommand{}, dir, "", "missing.txt", "hard")
+	if expected := "ln: не удалось создать жёсткую ссылку 'hard' => 'missing.txt': "; status != 1 ||
+		!strings.HasPrefix(stderr, expected) || strings.Contains(stderr, dir) {
+		t.Errorf("ожидалось %q и причина, получено %d: %q", expected, status, stderr)
+	}
+
+	_, stderr, status = runCommand(t, &LnCommand{}, dir, "")
+	if status != 1 || !strings.Contains(stderr, "ln: пропущен операнд") {
+		t.Errorf("ожидалась ошибка без операндов, получено %d: %q", status, stderr)
+	}
+}
+
+func TestLnCommand_NoDereference(t *testing.T) {
+	dir := t.TempDir()
+	makeTree(t, dir, map[string]string{"v1/": "", "v2/": ""})
+	if err := os.Symlink("v1", filepath.Join(dir, "current")); err != nil {
+		t.Skip("символьные ссылки недоступны:", err)
+	}
+
+	// Без -n ссылка создаётся внутри каталога, на который указывает current
+	_, stderr, status := runCommand(t, &LnCommand{}, dir, "", "-sf", "../v2", "current")
+	if status != 0 || stderr != "" {
+		t.Fatalf("ожидался код 0, получено %d: %q", status, stderr)
+	}
+	if _, err := os.Lstat(filepath.Join(dir, "v1", "v2")); err != nil {
+		t.Errorf("ожидалась ссылка v1/v2: %v", err)
+	}
+
+	_, stderr, status = runCommand(t, &LnCommand{}, dir, "", "-sfn", "v2", "current")
+	if status != 0 || stderr != "" {
+		t.Fatalf("ожидался код 0, получено %d: %q", status, stderr)
+	}
+	if target, err := os.Readlink(filepath.Join(dir, "current")); err != nil || target != "v2" {
+		t.Errorf("ожидалась ссылка на %q, получено %q, %v", "v2", target, err)
+	}
+}
@@ -0,0 +1,583 @@
+package commands
+
+import (
+	"cmp"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"os/user"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	customErrors "github.com/ggerlakh/software-design-mhs-itmo/cli/internal/errors"
+)
+
+// Коды завершения ls, как в GNU ls.
+const (
+	lsMinorCode   = 1 // не удалось прочитать подкаталог
+	lsSeriousCode = 2 // не удалось получить доступ к аргументу
+)
+
+// lsDefaultWidth — ширина строки для вывода в колонки, если COLUMNS не задана.
+const lsDefaultWidth = 80
+
+// lsRecentAge — файлы новее этого срока выводятся в -l со временем, а не с годом.
+const lsRecentAge = 6 * 30 * 24 * time.Hour
+
+// LsCommand реализует встроенную команду "ls".
+// Она выводит содержимое каталогов и сведения о файлах.
+type LsCommand struct{}
+
+// lsOptions — параметры ls.
+type lsOptions struct {
+	long      bool // -l: подробный формат
+	all       bool // -a: показывать скрытые файлы, "." и ".."
+	almostAll bool // -A: показывать скрытые файлы без "." и ".."
+	human     bool // -h: размеры с суффиксами K, M, G
+	recursive bool // -R: обходить подкаталоги
+	byTime    bool // -t: сортировать по времени изменения
+	bySize    bool // -S: сортировать по размеру
+	reverse   bool // -r: обратный порядок
+	onePerRow bool // -1: по одному имени в строке
+	color     colorMode
+}
+
+// lsEntry — файл, который выводит ls.
+type lsEntry struct {
+	name string      // имя для вывода
+	path string      // путь для обращения к файлу
+	info os.FileInfo // сведения о самом файле (ссылка не разыменовывается)
+}
+
+// Name возвращает имя команды.
+func (l *LsCommand) Name() string {
+	return "ls"
+}
+
+// Exec выполняет команду ls.
+//
+// Синтаксис:
+//
+//	ls [-l] [-a | -A] [-h] [-R] [-t | -S] [-r] [-1] [--color[=WHEN]] [FILE...]
+//
+// Без аргументов выводится текущий каталог (CommandContext.Dir). Сначала
+// выводятся аргументы-файлы, затем содержимое аргументов-каталогов с
+// заголовками. На терминал имена выводятся в колонки, иначе — по одному
+// в строке.
+//
+// Примеры:
+//
+//	ls -la               → все файлы в подробном формате
+//	ls -lhS /var/log     → по убыванию размера, размеры с суффиксами
+//	ls -R src            → рекурсивный обход
+func (l *LsCommand) Exec(args []string, ctx *CommandContext) error {
+	opts, operands, err := parseLsArgs(args)
+	if err != nil {
+		if writeErr := warnf(ctx, "%v\nПопробуйте 'ls --help' для получения справки.", err); writeErr != nil {
+			return writeErr
+		}
+		return &customErrors.ExitStatusError{Code: lsSeriousCode}
+	}
+	if len(operands) == 0 {
+		operands = []string{"."}
+	}
+
+	lister := &lsLister{
+		opts:     opts,
+		ctx:      ctx,
+		colors:   lsColorsFor(opts.color, ctx),
+		columns:  !opts.long && !opts.onePerRow && isTerminal(ctx.Stdout),
+		names:    &lsNames{users: map[string]string{}, groups: map[string]string{}},
+		modified: time.Now(),
+	}
+
+	var files, dirs []*lsEntry
+	for _, name := range operands {
+		path := ctx.ResolvePath(name)
+		info, err := os.Lstat(path)
+		if err != nil {
+			lister.status = lsSeriousCode
+			if err := warnf(ctx, "ls: невозможно получить доступ к '%s': %v", name, fileError(err)); err != nil {
+				return err
+			}
+			continue
+		}
+		entry := &lsEntry{name: name, path: path, info: info}
+		// Символьная ссылка на каталог в аргументах раскрывается, кроме -l
+		if info.Mode()&fs.ModeSymlink != 0 && !opts.long {
+			if target, err := os.Stat(path); err == nil && target.IsDir() {
+				entry.info = target
+			}
+		}
+		if entry.info.IsDir() {
+			dirs = append(dirs, entry)
+		} else {
+			files = append(files, entry)
+		}
+	}
+
+	opts.sort(files)
+	opts.sort(dirs)
+	if len(files) > 0 {
+		if err := lister.print(files, false); err != nil {
+			return err
+		}
+	}
+	headers := len(operands) > 1 || opts.recursive
+	for i, dir := range dirs {
+		if len(files) > 0 || i > 0 {
+			if _, err := io.WriteString(ctx.Stdout, "\n"); err != nil {
+				return err
+			}
+		}
+		if err := lister.list(dir, headers, lsSeriousCode); err != nil {
+			return err
+		}
+	}
+
+	if lister.status != 0 {
+		return &customErrors.ExitStatusError{Code: lister.status}
+	}
+	return nil
+}
+
+// parseLsArgs разбирает аргументы ls.
+func parseLsArgs(args []string) (*lsOptions, []string, error) {
+	fs := flag.NewFlagSet("ls", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	opts := &lsOptions{color: grepColorNever}
+	for _, names := range []struct {
+		short, long string
+		value       *bool
+		usage       string
+	}{
+		{"l", "", &opts.long, "подробный формат"},
+		{"a", "all", &opts.all, "показывать все файлы"},
+		{"A", "almost-all", &opts.almostAll, "показывать все файлы, кроме . и .."},
+		{"h", "human-readable", &opts.human, "размеры с суффиксами"},
+		{"R", "recursive", &opts.recursive, "обходить подкаталоги"},
+		{"t", "", &opts.byTime, "сортировать по времени изменения"},
+		{"S", "", &opts.bySize, "сортировать по размеру"},
+		{"r", "reverse", &opts.reverse, "обратный порядок"},
+		{"1", "", &opts.onePerRow, "по одному имени в строке"},
+	} {
+		fs.BoolVar(names.value, names.short, false, names.usage)
+		if names.long != "" {
+			fs.BoolVar(names.value, names.long, false, names.usage)
+		}
+	}
+	fs.Var(&opts.color, "color", "режим подсветки: auto, always или never")
+	fs.Var(&opts.color, "colour", "режим подсветки: auto, always или never")
+
+	if err := parseOptions(fs, splitShortFlags(args, "")); err != nil {
+		return nil, nil, err
+	}
+	return opts, fs.Args(), nil
+}
+
+// sort упорядочивает файлы: по имени, с -S — по убыванию размера, с -t —
+// от новых к старым; с -r — в обратном порядке.
+func (o *lsOptions) sort(entries []*lsEntry) {
+	slices.SortStableFunc(entries, func(a, b *lsEntry) int {
+		c := 0
+		switch {
+		case o.bySize:
+			c = cmp.Compare(b.info.Size(), a.info.Size())
+		case o.byTime:
+			c = b.info.ModTime().Compare(a.info.ModTime())
+		}
+		if c == 0 {
+			c = strings.Compare(a.name, b.name)
+		}
+		if o.reverse {
+			return -c
+		}
+		return c
+	})
+}
+
+// lsLister выводит списки файлов ls.
+type lsLister struct {
+	opts     *lsOptions
+	ctx      *CommandContext
+	colors   *lsColors // nil — без подсветки
+	columns  bool      // выводить имена в колонки
+	names    *lsNames
+	modified time.Time // текущее время для формата даты в -l
+	status   int
+}
+
+// list выводит содержимое каталога dir, а с -R — и его подкаталогов.
+// failCode — код завершения, если каталог не удалось прочитать.
+func (l *lsLister) list(dir *lsEntry, header bool, failCode int) error {
+	children, err := os.ReadDir(dir.path)
+	if err != nil {
+		l.status = max(l.status, failCode)
+		return warnf(l.ctx, "ls: невозможно открыть каталог '%s': %v", dir.name, fileError(err))
+	}
+
+	var entries []*lsEntry
+	if l.opts.all {
+		for _, name := range []string{".", ".."} {
+			path := filepath.Join(dir.path, name)
+			if info, err := os.Lstat(path); err == nil {
+				entries = append(entries, &lsEntry{name: name, path: path, info: info})
+			}
+		}
+	}
+	for _, child := range children {
+		if strings.HasPrefix(child.Name(), ".") && !l.opts.all && !l.opts.almostAll {
+			continue
+		}
+		info, err := child.Info()
+		if err != nil {
+			// Файл удалён во время чтения каталога
+			continue
+		}
+		path := filepath.Join(dir.path, child.Name())
+		entries = append(entries, &lsEntry{name: child.Name(), path: path, info: info})
+	}
+	l.opts.sort(entries)
+
+	if header {
+		if _, err := fmt.Fprintf(l.ctx.Stdout, "%s:\n", dir.name); err != nil {
+			return err
+		}
+	}
+	if err := l.print(entries, true); err != nil {
+		return err
+	}
+
+	if !l.opts.recursive {
+		return nil
+	}
+	for _, entry := range entries {
+		if !entry.info.IsDir() || entry.name == "." || entry.name == ".." {
+			continue
+		}
+		if _, err := io.WriteString(l.ctx.Stdout, "\n"); err != nil {
+			return err
+		}
+		name := strings.TrimSuffix(dir.name, "/") + "/" + entry.name
+		if err := l.list(&lsEntry{name: name, path: entry.path, info: entry.info}, true, lsMinorCode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// print выводит список файлов в выбранном формате. total — печатать
+// перед подробным списком каталога строку "total" с занятыми блоками.
+func (l *lsLister) print(entries []*lsEntry, total bool) error {
+	switch {
+	case l.opts.long:
+		return l.printLong(entries, total)
+	case l.columns:
+		return l.printColumns(entries)
+	}
+	for _, entry := range entries {
+		if _, err := fmt.Fprintln(l.ctx.Stdout, l.displayName(entry)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printLong выводит подробный список: права, число ссылок, владельца,
+// группу, размер, время изменения и имя, для ссылок — с целью.
+func (l *lsLister) printLong(entries []*lsEntry, total bool) error {
+	rows := make([][]string, len(entries))
+	widths := make([]int, 5)
+	var blocks int64
+	for i, entry := range entries {
+		stat := fileStat(entry.info, l.names)
+		blocks += stat.blocks
+
+		size := strconv.FormatInt(entry.info.Size(), 10)
+		if l.opts.human {
+			size = humanSize(entry.info.Size())
+		}
+		rows[i] = []string{strconv.FormatUint(stat.links, 10), stat.owner, stat.group, size}
+		for j, field := range rows[i] {
+			widths[j] = max(widths[j], len(field))
+		}
+	}
+
+	if total {
+		count := strconv.FormatInt(blocks, 10)
+		if l.opts.human {
+			count = humanSize(blocks * 1024)
+		}
+		if _, err := fmt.Fprintf(l.ctx.Stdout, "total %s\n", count); err != nil {
+			return err
+		}
+	}
+	for i, entry := range entries {
+		row := rows[i]
+		line := fmt.Sprintf("%s %*s %-*s %-*s %*s %s %s",
+			fileModeString(entry.info.Mode()),
+			widths[0], row[0], widths[1], row[1], widths[2], row[2], widths[3], row[3],
+			l.formatTime(entry.info.ModTime()), l.displayName(entry))
+		if entry.info.Mode()&fs.ModeSymlink != 0 {
+			if target, err := os.Readlink(entry.path); err == nil {
+				line += " -> " + l.targetName(entry, target)
+			}
+		}
+		if _, err := fmt.Fprintln(l.ctx.Stdout, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// formatTime форматирует время изменения как GNU ls: для файлов новее
+// полугода — время суток, для остальных — год.
+func (l *lsLister) formatTime(t time.Time) string {
+	if age := l.modified.Sub(t); age < lsRecentAge && age > -time.Hour {
+		return t.Format("Jan _2 15:04")
+	}
+	return t.Format("Jan _2  2006")
+}
+
+// printColumns выводит имена в колонки сверху вниз, подбирая наибольшее
+// число колонок, при котором строка не длиннее COLUMNS.
+func (l *lsLister) printColumns(entries []*lsEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	width := lsDefaultWidth
+	if columns, err := strconv.Atoi(variableValue(l.ctx, "COLUMNS")); err == nil && columns > 0 {
+		width = columns
+	}
+
+	nameWidths := make([]int, len(entries))
+	for i, entry := range entries {
+		for _, r := range entry.name {
+			nameWidths[i] += runeWidth(r)
+		}
+	}
+
+	rows, colWidths := len(entries), []int{slices.Max(nameWidths)}
+	for cols := len(entries); cols > 1; cols-- {
+		candidate := (len(entries) + cols - 1) / cols
+		widths := make([]int, (len(entries)+candidate-1)/candidate)
+		for i, w := range nameWidths {
+			widths[i/candidate] = max(widths[i/candidate], w)
+		}
+		line := 2 * (len(widths) - 1)
+		for _, w := range widths {
+			line += w
+		}
+		if line <= width {
+			rows, colWidths = candidate, widths
+			break
+		}
+	}
+
+	var b strings.Builder
+	for row := 0; row < rows; row++ {
+		b.Reset()
+		for col := range colWidths {
+			i := col*rows + row
+			if i >= len(entries) {
+				break
+			}
+			b.WriteString(l.displayName(entries[i]))
+			if next := i + rows; next < len(entries) {
+				b.WriteString(strings.Repeat(" ", colWidths[col]-nameWidths[i]+2))
+			}
+		}
+		b.WriteByte('\n')
+		if _, err := io.WriteString(l.ctx.Stdout, b.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// displayName возвращает имя файла, подсвеченное по его типу.
+func (l *lsLister) displayName(entry *lsEntry) string {
+	return l.colors.paint(entry.name, entry.path, entry.info)
+}
+
+// targetName возвращает цель символьной ссылки, подсвеченную по типу
+// файла, на который она указывает.
+func (l *lsLister) targetName(entry *lsEntry, target string) string {
+	if l.colors == nil {
+		return target
+	}
+	info, err := os.Stat(entry.path)
+	if err != nil {
+		return l.colors.wrap(l.colors.types["or"], target)
+	}
+	return l.colors.paint(target, entry.path, info)
+}
+
+// lsNames кэширует имена пользователей и групп по идентификаторам.
+type lsNames struct {
+	users  map[string]string
+	groups map[string]string
+}
+
+// user возвращает имя пользователя с идентификатором id или сам id.
+func (n *lsNames) user(id string) string {
+	if name, ok := n.users[id]; ok {
+		return name
+	}
+	name := id
+	if u, err := user.LookupId(id); err == nil {
+		name = u.Username
+	}
+	n.users[id] = name
+	return name
+}
+
+// group возвращает имя группы с идентификатором id или сам id.
+func (n *lsNames) group(id string) string {
+	if name, ok := n.groups[id]; ok {
+		return name
+	}
+	name := id
+	if g, err := user.LookupGroupId(id); err == nil {
+		name = g.Name
+	}
+	n.groups[id] = name
+	return name
+}
+
+// lsStat — сведения о файле для ls -l, зависящие от платформы.
+type lsStat struct {
+	links  uint64
+	owner  string
+	group  string
+	blocks int64 // занятое место в блоках по 1 КиБ
+}
+
+// fileModeString возвращает права файла в формате ls: тип и три тройки
+// rwx с битами setuid, setgid и sticky.
+func fileModeString(mode fs.FileMode) string {
+	buf := []byte("-rwxrwxrwx")
+	switch {
+	case mode.IsDir():
+		buf[0] = 'd'
+	case mode&fs.ModeSymlink != 0:
+		buf[0] = 'l'
+	case mode&fs.ModeNamedPipe != 0:
+		buf[0] = 'p'
+	case mode&fs.ModeSocket != 0:
+		buf[0] = 's'
+	case mode&fs.ModeCharDevice != 0:
+		buf[0] = 'c'
+	case mode&fs.ModeDevice != 0:
+		buf[0] = 'b'
+	}
+	for i := range 9 {
+		if mode&(1<<(8-i)) == 0 {
+			buf[i+1] = '-'
+		}
+	}
+
+	for _, special := range []struct {
+		bit  fs.FileMode
+		pos  int
+		char byte
+	}{
+		{fs.ModeSetuid, 3, 's'},
+		{fs.ModeSetgid, 6, 's'},
+		{fs.ModeSticky, 9, 't'},
+	} {
+		if mode&special.bit == 0 {
+			continue
+		}
+		if buf[special.pos] == 'x' {
+			buf[special.pos] = special.char
+		} else {
+			buf[special.pos] = special.char - 'a' + 'A'
+		}
+	}
+	return string(buf)
+}
+
+// humanSize форматирует размер как ls -h: с суффиксом K, M, G... по
+// основанию 1024, с округлением вверх и одним знаком после запятой для
+// значений меньше 10.
+func humanSize(size int64) string {
+	const units = "KMGTPE"
+	if size < 1024 {
+		return strconv.FormatInt(size, 10)
+	}
+
+	value, unit := float64(size)/1024, 0
+	for value >= 1024 && unit < len(units)-1 {
+		value /= 1024
+		unit++
+	}
+	if value < 10 {
+		if rounded := math.Ceil(value*10) / 10; rounded < 10 {
+			return fmt.Sprintf("%.1f%c", rounded, units[unit])
+		}
+	}
+	value = math.Ceil(value)
+	if value >= 1024 && unit < len(units)-1 {
+		return fmt.Sprintf("1.0%c", units[unit+1])
+	}
+	return fmt.Sprintf("%.0f%c", value, units[unit])
+}
+
+// Help возвращает справку по команде ls.
+func (l *LsCommand) Help() string {
+	return `NAME
+    ls - выводит содержимое каталогов
+
+SYNOPSIS
+    ls [OPTION]... [FILE]...
+
+DESCRIPTION
+    Выводит сведения о файлах FILE и содержимое каталогов (по умолчанию —
+    текущего). Имена сортируются побайтно. На терминал имена выводятся в
+    колонки по ширине COLUMNS (по умолчанию 80), иначе — по одному в строке.
+    Скрытые файлы (имена с ".") не выводятся без -a или -A.
+
+OPTIONS
+    -l                    подробный формат: права, ссылки, владелец, группа,
+                          размер, время изменения; перед списком каталога —
+                          занятое место в блоках по 1 КиБ (total)
+    -a, --all             показывать все файлы, включая "." и ".."
+    -A, --almost-all      показывать все файлы, кроме "." и ".."
+    -h, --human-readable  размеры с суффиксами: 1.5K, 20M
+    -R, --recursive       выводить содержимое подкаталогов
+    -t                    сортировать по времени изменения, новые первыми
+    -S                    сортировать по размеру, большие первыми
+                          (если заданы -S и -t, действует -S)
+    -r, --reverse         обратный порядок сортировки
+    -1                    по одному имени в строке
+    --color[=WHEN]        подсветка типов файлов: never (по умолчанию),
+                          always или auto (только на терминал); цвета
+                          задаются переменной LS_COLORS
+
+EXIT STATUS
+    0 — успех, 1 — не удалось прочитать подкаталог, 2 — неверные аргументы
+    или недоступный файл из аргументов.
+
+EXAMPLES
+    ls -la
+        → все файлы текущего каталога в подробном формате
+
+    ls -lhS /var/log
+        → файлы по убыванию размера с размерами вида 12K
+
+    ls -1t | head -n 5
+        → пять последних изменённых файлов
+
+    ls -R --color=auto src
+        → дерево каталога src с подсветкой`
+}
+
+var _ BuiltinCommand = (*LsCommand)(nil)
@@ -0,0 +1,121 @@
+package commands
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// lsDefaultColors — цвета типов файлов по умолчанию, как у GNU ls без LS_COLORS.
+const lsDefaultColors = "di=01;34:ln=01;36:pi=40;33:so=01;35:bd=40;33;01:cd=40;33;01:" +
+	"or=40;31;01:su=37;41:sg=30;43:tw=30;42:ow=34;42:st=37;44:ex=01;32"
+
+// lsColors — параметры SGR для типов файлов в формате LS_COLORS:
+// двухбуквенные коды типов (di, ln, ex...) и шаблоны расширений "*.ext".
+type lsColors struct {
+	types map[string]string
+	exts  map[string]string
+}
+
+// lsColorsFor возвращает цвета для режима mode или nil, если подсвечивать
+// не нужно. Режим auto работает, как у grep: только на терминал с TERM,
+// отличным от "dumb".
+func lsColorsFor(mode colorMode, ctx *CommandContext) *lsColors {
+	switch mode {
+	case grepColorAlways:
+	case grepColorAuto:
+		if !isTerminal(ctx.Stdout) || variableValue(ctx, "TERM") == "dumb" {
+			return nil
+		}
+	default:
+		return nil
+	}
+	return parseLsColors(variableValue(ctx, "LS_COLORS"))
+}
+
+// parseLsColors разбирает LS_COLORS поверх значений по умолчанию.
+// Некорректные элементы пропускаются.
+func parseLsColors(spec string) *lsColors {
+	colors := &lsColors{types: map[string]string{}, exts: map[string]string{}}
+	for _, source := range []string{lsDefaultColors, spec} {
+		for _, item := range strings.Split(source, ":") {
+			name, value, ok := strings.Cut(item, "=")
+			if !ok || strings.Trim(value, "0123456789;") != "" {
+				continue
+			}
+			if ext, isExt := strings.CutPrefix(name, "*"); isExt {
+				colors.exts[strings.ToLower(ext)] = value
+			} else {
+				colors.types[name] = value
+			}
+		}
+	}
+	return colors
+}
+
+// paint окружает name цветом по типу файла info, расположенного по пути
+// path. Без подсветки (c == nil) имя возвращается как есть.
+func (c *lsColors) paint(name, path string, info fs.FileInfo) string {
+	if c == nil {
+		return name
+	}
+	return c.wrap(c.color(path, info), name)
+}
+
+// color выбирает цвет файла: сначала по типу, для обычных файлов без
+// особых битов — по расширению имени.
+func (c *lsColors) color(path string, info fs.FileInfo) string {
+	mode := info.Mode()
+	switch {
+	case mode&fs.ModeSymlink != 0:
+		if _, err := os.Stat(path); err != nil && c.types["or"] != "" {
+			return c.types["or"]
+		}
+		return c.types["ln"]
+	case mode.IsDir():
+		sticky, writable := mode&fs.ModeSticky != 0, mode&0o002 != 0
+		switch {
+		case sticky && writable:
+			return c.types["tw"]
+		case writable:
+			return c.types["ow"]
+		case sticky:
+			return c.types["st"]
+		}
+		return c.types["di"]
+	case mode&fs.ModeNamedPipe != 0:
+		return c.types["pi"]
+	case mode&fs.ModeSocket != 0:
+		return c.types["so"]
+	case mode&fs.ModeCharDevice != 0:
+		return c.types["cd"]
+	case mode&fs.ModeDevice != 0:
+		return c.types["bd"]
+	case mode&fs.ModeSetuid != 0:
+		return c.types["su"]
+	case mode&fs.ModeSetgid != 0:
+		return c.types["sg"]
+	case mode&0o111 != 0:
+		return c.types["ex"]
+	}
+
+	// Из подходящих расширений выбирается самое длинное: *.tar.gz важнее *.gz
+	name := strings.ToLower(filepath.Base(path))
+	color, matched := c.types["fi"], ""
+	for ext, extColor := range c.exts {
+		if strings.HasSuffix(name, ext) && len(ext) > len(matched) {
+			color, matched = extColor, ext
+		}
+	}
+	return color
+}
+
+// wrap окружает text последовательностями SGR; пустой sgr оставляет
+// text без изменений.
+func (c *lsColors) wrap(sgr, text string) string {
+	if sgr == "" {
+		return text
+	}
+	return "\033[" + sgr + "m" + text + "\033[0m"
+}
@@ -0,0 +1,11 @@
+//go:build !unix
+
+package commands
+
+import "os"
+
+// fileStat возвращает сведения о файле для ls -l. Владелец и группа на
+// этой платформе недоступны, занятое место оценивается по размеру.
+func fileStat(info os.FileInfo, _ *lsNames) lsStat {
+	return lsStat{links: 1, owner: "-", group: "-", blocks: (info.Size() + 1023) / 1024}
+}
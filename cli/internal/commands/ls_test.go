@@ -0,0 +1,221 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
+)
+
+// makeTree создаёт в dir файлы с заданным содержимым; имена с "/" в конце — каталоги.
+func makeTree(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if strings.HasSuffix(name, "/") {
+			if err := os.MkdirAll(path, 0o755); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLsCommand(t *testing.T) {
+	dir := t.TempDir()
+	makeTree(t, dir, map[string]string{
+		"b.txt":      "12345",
+		"a.txt":      "1",
+		"c.txt":      "123",
+		".hidden":    "",
+		"sub/d.txt":  "",
+		"sub/e/":     "",
+		"sub/e/f.go": "",
+	})
+	now := time.Now()
+	for name, age := range map[string]time.Duration{"a.txt": time.Hour, "b.txt": 3 * time.Hour, "c.txt": 2 * time.Hour} {
+		stamp := now.Add(-age)
+		if err := os.Chtimes(filepath.Join(dir, name), stamp, stamp); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{"default", nil, "a.txt\nb.txt\nc.txt\nsub\n"},
+		{"all", []string{"-a"}, ".\n..\n.hidden\na.txt\nb.txt\nc.txt\nsub\n"},
+		{"almost all", []string{"-A"}, ".hidden\na.txt\nb.txt\nc.txt\nsub\n"},
+		{"reverse", []string{"-r"}, "sub\nc.txt\nb.txt\na.txt\n"},
+		{"by size", []string{"-S", "a.txt", "b.txt", "c.txt"}, "b.txt\nc.txt\na.txt\n"},
+		{"by time", []string{"-t", "a.txt", "b.txt", "c.txt"}, "a.txt\nc.txt\nb.txt\n"},
+		{"by time reversed", []string{"-tr", "a.txt", "b.txt", "c.txt"}, "b.txt\nc.txt\na.txt\n"},
+		{"files and dirs", []string{"sub", "a.txt"}, "a.txt\n\nsub:\nd.txt\ne\n"},
+		{"recursive", []string{"-R", "sub"}, "sub:\nd.txt\ne\n\nsub/e:\nf.go\n"},
+		{"one per line", []string{"-1", "sub"}, "d.txt\ne\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, stderr, status := runCommand(t, &LsCommand{}, dir, "", tt.args...)
+			if status != 0 || stderr != "" {
+				t.Fatalf("ожидался код 0 без ошибок, получено %d: %q", status, stderr)
+			}
+			if out != tt.expected {
+				t.Errorf("ожидалось %q, получено %q", tt.expected, out)
+			}
+		})
+	}
+}
+
+func TestLsCommand_Long(t *testing.T) {
+	dir := t.TempDir()
+	makeTree(t, dir, map[string]string{"big.bin": strings.Repeat("x", 3000), "dir/": ""})
+	if err := os.Chmod(filepath.Join(dir, "big.bin"), 0o640); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("big.bin", filepath.Join(dir, "link")); err != nil {
+		t.Skip("символьные ссылки недоступны:", err)
+	}
+	old := time.Date(2001, time.March, 4, 5, 6, 0, 0, time.Local)
+	if err := os.Chtimes(filepath.Join(dir, "dir"), old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	out, stderr, status := runCommand(t, &LsCommand{}, dir, "", "-lh")
+	if status != 0 || stderr != "" {
+		t.Fatalf("ожидался код 0 без ошибок, получено %d: %q", status, stderr)
+	}
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	if len(lines) != 4 || !strings.HasPrefix(lines[0], "total ") {
+		t.Fatalf("ожидалась строка total и три файла, получено %q", out)
+	}
+	patterns := []string{
+		`^-rw-r----- +1 \S+ +\S+ +3\.0K \w{3} [ \d]\d \d\d:\d\d big\.bin$`,
+		`^drwxr-xr-x +\d+ \S+ +\S+ +\S+ Mar  4  2001 dir$`,
+		`^lrwxrwxrwx +1 \S+ +\S+ +\S+ .* link -> big\.bin$`,
+	}
+	for i, pattern := range patterns {
+		if !regexp.MustCompile(pattern).MatchString(lines[i+1]) {
+			t.Errorf("строка %q не соответствует %q", lines[i+1], pattern)
+		}
+	}
+}
+
+func TestLsCommand_Color(t *testing.T) {
+	dir := t.TempDir()
+	makeTree(t, dir, map[string]string{"dir/": "", "run.sh": "", "archive.tar.gz": ""})
+	if err := os.Chmod(filepath.Join(dir, "run.sh"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr strings.Builder
+	ctx := &CommandContext{
+		Stdout: &stdout,
+		Stderr: &stderr,
+		Vars:   session.NewVariables(map[string]string{"LS_COLORS": "*.gz=01;31:*.tar.gz=01;33"}),
+		Dir:    dir,
+	}
+	if err := (&LsCommand{}).Exec([]string{"--color=always"}, ctx); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	expected := "\033[01;33marchive.tar.gz\033[0m\n\033[01;34mdir\033[0m\n\033[01;32mrun.sh\033[0m\n"
+	if stdout.String() != expected {
+		t.Errorf("ожидалось %q, получено %q", expected, stdout.String())
+	}
+}
+
+func TestLsCommand_Errors(t *testing.T) {
+	dir := t.TempDir()
+	makeTree(t, dir, map[string]string{"a.txt": ""})
+
+	out, stderr, status := runCommand(t, &LsCommand{}, dir, "", "missing", "a.txt")
+	if status != 2 || !strings.Contains(stderr, "невозможно получить доступ к 'missing'") || out != "a.txt\n" {
+		t.Errorf("ожидался код 2 и вывод a.txt, получено %d: %q, %q", status, out, stderr)
+	}
+
+	_, stderr, status = runCommand(t, &LsCommand{}, dir, "", "-Z")
+	if status != 2 || stderr == "" {
+		t.Errorf("ожидался код 2 для неизвестного флага, получено %d: %q", status, stderr)
+	}
+}
+
+func TestHumanSize(t *testing.T) {
+	tests := map[int64]string{
+		0:                  "0",
+		1023:               "1023",
+		1024:               "1.0K",
+		1025:               "1.1K",
+		1536:               "1.5K",
+		10 * 1024:          "10K",
+		10*1024 + 1:        "11K",
+		1024*1024 - 1:      "1.0M",
+		5 * 1024 * 1024:    "5.0M",
+		3 << 30:            "3.0G",
+		1024*1024*1024 - 1: "1.0G",
+	}
+	for size, expected := range tests {
+		if got := humanSize(size); got != expected {
+			t.Errorf("humanSize(%d): ожидалось %q, получено %q", size, expected, got)
+		}
+	}
+}
+
+func TestFileModeString(t *testing.T) {
+	tests := []struct {
+		mode     os.FileMode
+		expected string
+	}{
+		{0o644, "-rw-r--r--"},
+		{os.ModeDir | 0o755, "drwxr-xr-x"},
+		{os.ModeSymlink | 0o777, "lrwxrwxrwx"},
+		{os.ModeSetuid | 0o755, "-rwsr-xr-x"},
+		{os.ModeSetgid | 0o644, "-rw-r-Sr--"},
+		{os.ModeDir | os.ModeSticky | 0o777, "drwxrwxrwt"},
+		{os.ModeNamedPipe | 0o600, "prw-------"},
+	}
+	for _, tt := range tests {
+		if got := fileModeString(tt.mode); got != tt.expected {
+			t.Errorf("%v: ожидалось %q, получено %q", tt.mode, tt.expected, got)
+		}
+	}
+}
+
+func TestLsLister_PrintColumns(t *testing.T) {
+	var entries []*lsEntry
+	for _, name := range []string{"alpha", "b", "charlie", "d", "echo"} {
+		entries = append(entries, &lsEntry{name: name})
+	}
+
+	tests := []struct {
+		width    string
+		expected string
+	}{
+		{"80", "alpha  b  charlie  d  echo\n"},
+		{"20", "alpha  charlie  echo\nb      d\n"},
+		{"13", "alpha    d\nb        echo\ncharlie\n"},
+		{"3", "alpha\nb\ncharlie\nd\necho\n"},
+	}
+	for _, tt := range tests {
+		var out strings.Builder
+		lister := &lsLister{ctx: &CommandContext{Stdout: &out, Vars: session.NewVariables(map[string]string{"COLUMNS": tt.width})}}
+		if err := lister.printColumns(entries); err != nil {
+			t.Fatal(err)
+		}
+		if out.String() != tt.expected {
+			t.Errorf("COLUMNS=%s: ожидалось %q, получено %q", tt.width, tt.expected, out.String())
+		}
+	}
+}
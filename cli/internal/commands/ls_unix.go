@@ -0,0 +1,24 @@
+//go:build unix
+
+package commands
+
+import (
+	"os"
+	"strconv"
+	"syscall"
+)
+
+// fileStat возвращает число жёстких ссылок, владельца, группу и занятое
+// место файла из stat(2).
+func fileStat(info os.FileInfo, names *lsNames) lsStat {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return lsStat{links: 1, owner: "-", group: "-", blocks: (info.Size() + 1023) / 1024}
+	}
+	return lsStat{
+		links:  uint64(st.Nlink),
+		owner:  names.user(strconv.FormatUint(uint64(st.Uid), 10)),
+		group:  names.group(strconv.FormatUint(uint64(st.Gid), 10)),
+		blocks: (int64(st.Blocks)*512 + 1023) / 1024,
+	}
+}
@@ -0,0 +1,242 @@
+package commands
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+
+	customErrors "github.com/ggerlakh/software-design-mhs-itmo/cli/internal/errors"
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
+)
+
+// MapfileCommand реализует встроенную команду "mapfile".
+// Она читает строки из stdin в индексированный массив.
+type MapfileCommand struct{}
+
+// ReadarrayCommand реализует встроенную команду "readarray" — синоним
+// mapfile.
+type ReadarrayCommand struct{}
+
+// mapfileOptions — параметры mapfile.
+type mapfileOptions struct {
+	delim  byte   // -d: конец строки
+	count  int    // -n: не больше строк; 0 — все
+	origin int    // -O: индекс первой строки
+	keep   bool   // задан -O: массив не очищается
+	skip   int    // -s: пропустить строки
+	trim   bool   // -t: удалить разделитель в конце строки
+	array  string // имя массива, по умолчанию MAPFILE
+}
+
+// Name возвращает имя команды.
+func (m *MapfileCommand) Name() string {
+	return "mapfile"
+}
+
+// Exec выполняет команду mapfile.
+//
+// Синтаксис:
+//
+//	mapfile [-t] [-d DELIM] [-n COUNT] [-O ORIGIN] [-s COUNT] [ARRAY]
+//
+// Ввод читается по байту: после -n COUNT оставшиеся строки остаются в
+// потоке.
+//
+// Примеры:
+//
+//	mapfile -t lines < file.txt   → строки файла без переводов строк
+//	readarray -t -n 10 head       → первые десять строк
+func (m *MapfileCommand) Exec(args []string, ctx *CommandContext) error {
+	return mapfile(m.Name(), args, ctx)
+}
+
+// Help возвращает справку по команде mapfile.
+func (m *MapfileCommand) Help() string {
+	return mapfileHelp("mapfile")
+}
+
+// Name возвращает имя команды.
+func (r *ReadarrayCommand) Name() string {
+	return "readarray"
+}
+
+// Exec выполняет команду readarray так же, как mapfile.
+func (r *ReadarrayCommand) Exec(args []string, ctx *CommandContext) error {
+	return mapfile(r.Name(), args, ctx)
+}
+
+// Help возвращает справку по команде readarray.
+func (r *ReadarrayCommand) Help() string {
+	return mapfileHelp("readarray")
+}
+
+// mapfile выполняет mapfile и readarray; name — имя команды для сообщений.
+func mapfile(name string, args []string, ctx *CommandContext) error {
+	opts, err := parseMapfileArgs(name, args)
+	if err != nil {
+		return readFailure(ctx, err)
+	}
+
+	values := session.NewIndexedArray()
+	if existing, ok := ctx.Vars.Array(opts.array); ok && opts.keep {
+		if existing.IsAssoc() {
+			return readFailure(ctx, fmt.Errorf("%s: %s: не индексированный массив", name, opts.array))
+		}
+		values = existing.Copy()
+	}
+
+	src := newByteSource(ctx, 0)
+	var readErr error
+	for n := 0; opts.count == 0 || n < opts.skip+opts.count; n++ {
+		line, err := readRecord(src, opts.delim)
+		if err != nil && (len(line) == 0 || !errors.Is(err, io.EOF)) {
+			readErr = err
+			break
+		}
+		if n < opts.skip {
+			continue
+		}
+		if opts.trim && len(line) > 0 && line[len(line)-1] == opts.delim {
+			line = line[:len(line)-1]
+		}
+
+		values.Set(strconv.Itoa(opts.origin+n-opts.skip), string(line))
+		if err != nil {
+			break
+		}
+	}
+
+	switch {
+	case errors.Is(readErr, errReadInterrupted):
+		return &customErrors.ExitStatusError{Code: readInterruptedCode}
+	case readErr != nil && !errors.Is(readErr, io.EOF):
+		return readFailure(ctx, fmt.Errorf("%s: ошибка чтения: %w", name, readErr))
+	}
+	if err := ctx.Vars.SetArray(opts.array, values); err != nil {
+		return readFailure(ctx, fmt.Errorf("%s: %w", name, err))
+	}
+	return nil
+}
+
+// parseMapfileArgs разбирает параметры mapfile.
+func parseMapfileArgs(name string, args []string) (*mapfileOptions, error) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	opts := &mapfileOptions{delim: '\n', array: "MAPFILE"}
+	var delim, count, origin, skip string
+	fs.StringVar(&delim, "d", "", "конец строки")
+	fs.StringVar(&count, "n", "", "не больше строк")
+	fs.StringVar(&origin, "O", "", "индекс первой строки")
+	fs.StringVar(&skip, "s", "", "пропустить строки")
+	fs.BoolVar(&opts.trim, "t", false, "удалить разделитель")
+
+	if err := fs.Parse(splitShortFlags(args, "dnOs")); err != nil {
+		return nil, &readUsageError{fmt.Errorf("%s: ошибка разбора флагов: %w", name, err)}
+	}
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+
+	numbers := []struct {
+		flag, value, message string
+		target               *int
+	}{
+		{"n", count, "неверное число строк", &opts.count},
+		{"O", origin, "неверный начальный индекс", &opts.origin},
+		{"s", skip, "неверное число строк", &opts.skip},
+	}
+	for _, number := range numbers {
+		if !set[number.flag] {
+			continue
+		}
+		n, err := strconv.Atoi(number.value)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("%s: %s: %s", name, number.value, number.message)
+		}
+		*number.target = n
+	}
+	opts.keep = set["O"]
+	if set["d"] {
+		// Пустой разделитель — нулевой байт
+		opts.delim = 0
+		if delim != "" {
+			opts.delim = delim[0]
+		}
+	}
+
+	switch fs.NArg() {
+	case 0:
+	case 1:
+		opts.array = fs.Arg(0)
+		if !session.IsName(opts.array) {
+			return nil, fmt.Errorf("%s: '%s': недопустимый идентификатор", name, opts.array)
+		}
+	default:
+		return nil, &readUsageError{fmt.Errorf("%s: лишний аргумент '%s'", name, fs.Arg(1))}
+	}
+	return opts, nil
+}
+
+// readRecord читает строку вместе с разделителем delim. В конце ввода
+// возвращается прочитанная часть и io.EOF.
+func readRecord(src *byteSource, delim byte) ([]byte, error) {
+	var line []byte
+	for {
+		b, err := src.readByte()
+		if err != nil {
+			return line, err
+		}
+		line = append(line, b)
+		if b == delim {
+			return line, nil
+		}
+	}
+}
+
+func mapfileHelp(name string) string {
+	return `NAME
+    ` + name + ` - читает строки из стандартного ввода в массив
+
+SYNOPSIS
+    ` + name + ` [-t] [-d DELIM] [-n COUNT] [-O ORIGIN] [-s COUNT] [ARRAY]
+
+DESCRIPTION
+    Записывает строки ввода в элементы индексированного массива ARRAY (по
+    умолчанию MAPFILE), начиная с индекса 0. Перед записью массив
+    очищается, если не задан -O. Строки сохраняются вместе с
+    завершающим переводом строки, если не задан -t.
+
+    Ввод читается по байту: строки после -n COUNT остаются в потоке для
+    следующих команд. Синонимы: mapfile и readarray.
+
+OPTIONS
+    -d DELIM        строки заканчиваются первым символом DELIM вместо
+                    перевода строки; пустой DELIM — нулевой байт
+    -n COUNT        прочитать не больше COUNT строк; 0 — все
+    -O ORIGIN       записывать, начиная с индекса ORIGIN, не очищая массив
+    -s COUNT        пропустить первые COUNT строк
+    -t              удалить разделитель в конце каждой строки
+
+EXIT STATUS
+    0 — успех, 1 — неверное имя массива, число или ошибка чтения,
+    2 — неверные параметры.
+
+EXAMPLES
+    ` + name + ` -t lines < hosts.txt
+        → строки hosts.txt в массиве lines
+
+    find . -name '*.go' -print0 | ` + name + ` -d '' -t files
+        → имена файлов, в том числе с переводами строк
+
+    ` + name + ` -t -s 1 -n 5 rows < data.csv
+        → пять строк данных после заголовка`
+}
+
+var (
+	_ BuiltinCommand = (*MapfileCommand)(nil)
+	_ BuiltinCommand = (*ReadarrayCommand)(nil)
+)
@@ -0,0 +1,107 @@
+package commands
+
+import (
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
+)
+
+func TestMapfileCommand(t *testing.T) {
+	tests := []struct {
+		args     []string
+		input    string
+		name     string
+		expected []string
+	}{
+		{nil, "a\nb\n", "MAPFILE", []string{"a\n", "b\n"}},
+		{[]string{"-t", "lines"}, "a\n\nc", "lines", []string{"a", "", "c"}},
+		{[]string{"-t", "-n", "2", "x"}, "1\n2\n3\n", "x", []string{"1", "2"}},
+		{[]string{"-t", "-s", "1", "-n", "2", "x"}, "head\n1\n2\n3\n", "x", []string{"1", "2"}},
+		{[]string{"-t", "-d", "", "x"}, "a b\x00c\nd\x00", "x", []string{"a b", "c\nd"}},
+		{[]string{"-d", ",", "x"}, "a,b", "x", []string{"a,", "b"}},
+		{[]string{"-t", "x"}, "", "x", []string{}},
+	}
+	for _, tt := range tests {
+		ctx, stderr, status := runRead(t, &MapfileCommand{}, nil, strings.NewReader(tt.input), tt.args...)
+		if status != 0 || stderr != "" {
+			t.Errorf("%v: ожидался код 0, получено %d: %q", tt.args, status, stderr)
+		}
+		if got := testArray(ctx, tt.name).Values(); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("%v: ожидалось %q, получено %q", tt.args, tt.expected, got)
+		}
+	}
+}
+
+func TestMapfileCommand_Origin(t *testing.T) {
+	var stderr strings.Builder
+	ctx := &CommandContext{
+		Stdin:  strings.NewReader("c\nd\n"),
+		Stderr: &stderr,
+		Vars:   session.NewVariables(nil),
+	}
+	setArray(t, ctx, "x", session.NewIndexedArray("a", "b", "old"))
+	if err := (&ReadarrayCommand{}).Exec([]string{"-t", "-O", "2", "x"}, ctx); err != nil {
+		t.Fatalf("ожидался код 0, получено %v: %q", err, stderr.String())
+	}
+	expected := []string{"a", "b", "c", "d"}
+	if got := testArray(ctx, "x").Values(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("ожидалось %q, получено %q", expected, got)
+	}
+
+	ctx.Stdin = strings.NewReader("z\n")
+	if err := (&ReadarrayCommand{}).Exec([]string{"-t", "-O", "5", "x"}, ctx); err != nil {
+		t.Fatal(err)
+	}
+	// Пропущенный индекс 4 не заполняется: массив разрежен
+	keys := []string{"0", "1", "2", "3", "5"}
+	if got := testArray(ctx, "x").Keys(); !reflect.DeepEqual(got, keys) {
+		t.Errorf("ожидалось %q, получено %q", keys, got)
+	}
+	if got, _ := testArray(ctx, "x").Get("5"); got != "z" {
+		t.Errorf("ожидалось %q, получено %q", "z", got)
+	}
+
+	setArray(t, ctx, "m", session.NewAssocArray())
+	ctx.Stdin = strings.NewReader("z\n")
+	if err := (&ReadarrayCommand{}).Exec([]string{"-O", "0", "m"}, ctx); testStatus(err) != 1 {
+		t.Errorf("ожидался код 1 для ассоциативного массива, получено %v", err)
+	}
+}
+
+func TestMapfileCommand_LeavesRestOfInput(t *testing.T) {
+	input := strings.NewReader("1\n2\n3\n")
+	ctx, _, _ := runRead(t, &MapfileCommand{}, nil, input, "-t", "-n", "1", "x")
+	if got := testArray(ctx, "x").Values(); !reflect.DeepEqual(got, []string{"1"}) {
+		t.Errorf("ожидалось %q, получено %q", []string{"1"}, got)
+	}
+	rest, _ := io.ReadAll(input)
+	if string(rest) != "2\n3\n" {
+		t.Errorf("ожидалось %q, получено %q", "2\n3\n", rest)
+	}
+}
+
+func TestMapfileCommand_Errors(t *testing.T) {
+	tests := []struct {
+		args    []string
+		status  int
+		message string
+	}{
+		{[]string{"1x"}, 1, "mapfile: '1x': недопустимый идентификатор"},
+		{[]string{"-n", "-1"}, 1, "mapfile: -1: неверное число строк"},
+		{[]string{"-O", "x"}, 1, "mapfile: x: неверный начальный индекс"},
+		{[]string{"a", "b"}, 2, "mapfile: лишний аргумент 'b'"},
+		{[]string{"-u", "3"}, 2, "mapfile: ошибка разбора флагов"},
+	}
+	for _, tt := range tests {
+		ctx, stderr, status := runRead(t, &MapfileCommand{}, nil, strings.NewReader("line\n"), tt.args...)
+		if status != tt.status || !strings.Contains(stderr, tt.message) {
+			t.Errorf("%v: ожидался код %d и %q, получено %d: %q", tt.args, tt.status, tt.message, status, stderr)
+		}
+		if len(ctx.Vars.Names()) != 0 {
+			t.Errorf("%v: массивы не должны изменяться, получено %v", tt.args, ctx.Vars.Names())
+		}
+	}
+}
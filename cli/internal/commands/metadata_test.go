@@ -18,6 +18,38 @@ func TestBuiltinCommandMetadata(t *testing.T) {
 		{"wc", &WcCommand{}, "wc"},
 		{"pwd", &PwdCommand{}, "pwd"},
 		{"exit", &ExitCommand{}, "exit"},
+		{"shopt", &ShoptCommand{}, "shopt"},
+		{"alias", &AliasCommand{}, "alias"},
+		{"unalias", &UnaliasCommand{}, "unalias"},
+		{"cd", &CdCommand{}, "cd"},
+		{"test", &TestCommand{}, "test"},
+		{"[", &BracketCommand{}, "["},
+		{"printf", &PrintfCommand{}, "printf"},
+		{"head", &HeadCommand{}, "head"},
+		{"tail", &TailCommand{}, "tail"},
+		{"sort", &SortCommand{}, "sort"},
+		{"uniq", &UniqCommand{}, "uniq"},
+		{"cut", &CutCommand{}, "cut"},
+		{"tr", &TrCommand{}, "tr"},
+		{"sed", &SedCommand{}, "sed"},
+		{"tee", &TeeCommand{}, "tee"},
+		{"ls", &LsCommand{}, "ls"},
+		{"mkdir", &MkdirCommand{}, "mkdir"},
+		{"rm", &RmCommand{}, "rm"},
+		{"cp", &CpCommand{}, "cp"},
+		{"mv", &MvCommand{}, "mv"},
+		{"touch", &TouchCommand{}, "touch"},
+		{"ln", &LnCommand{}, "ln"},
+		{"find", &FindCommand{}, "find"},
+		{"xargs", &XargsCommand{}, "xargs"},
+		{"read", &ReadCommand{}, "read"},
+		{"mapfile", &MapfileCommand{}, "mapfile"},
+		{"readarray", &ReadarrayCommand{}, "readarray"},
+		{"declare", &DeclareCommand{}, "declare"},
+		{"unset", &UnsetCommand{}, "unset"},
+		{"typeset", &TypesetCommand{}, "typeset"},
+		{"set", &SetCommand{}, "set"},
+		{"trap", &TrapCommand{}, "trap"},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,219 @@
+package commands
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
+
+	customErrors "github.com/ggerlakh/software-design-mhs-itmo/cli/internal/errors"
+)
+
+// MkdirCommand реализует встроенную команду "mkdir".
+// Она создаёт каталоги.
+type MkdirCommand struct{}
+
+// mkdirOptions — параметры mkdir.
+type mkdirOptions struct {
+	parents bool        // -p: создавать родительские каталоги, не считать ошибкой существующие
+	verbose bool        // -v: сообщать о каждом созданном каталоге
+	mode    fs.FileMode // -m: права создаваемых каталогов
+	modeSet bool
+}
+
+// Name возвращает имя команды.
+func (m *MkdirCommand) Name() string {
+	return "mkdir"
+}
+
+// Exec выполняет команду mkdir.
+//
+// Синтаксис:
+//
+//	mkdir [-p] [-v] [-m MODE] DIRECTORY...
+//
+// Пути отсчитываются от CommandContext.Dir. Ошибка с одним каталогом не
+// мешает создать остальные.
+//
+// Примеры:
+//
+//	mkdir build                   → каталог build
+//	mkdir -p src/internal/app     → вместе с недостающими родителями
+//	mkdir -m 700 private          → с правами rwx------
+func (m *MkdirCommand) Exec(args []string, ctx *CommandContext) error {
+	opts, dirs, err := parseMkdirArgs(args)
+	if err != nil {
+		if writeErr := warnf(ctx, "%v\nПопробуйте 'mkdir --help' для получения справки.", err); writeErr != nil {
+			return writeErr
+		}
+		return &customErrors.ExitStatusError{Code: 1}
+	}
+
+	failed := false
+	for _, dir := range dirs {
+		err := opts.create(ctx, dir)
+		if err == nil {
+			continue
+		}
+		failed = true
+		if err := warnf(ctx, "mkdir: невозможно создать каталог '%s': %v", dir, fileError(err)); err != nil {
+			return err
+		}
+	}
+	if failed {
+		return &customErrors.ExitStatusError{Code: 1}
+	}
+	return nil
+}
+
+// parseMkdirArgs разбирает аргументы mkdir.
+func parseMkdirArgs(args []string) (*mkdirOptions, []string, error) {
+	fs := flag.NewFlagSet("mkdir", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	opts := &mkdirOptions{}
+	var mode string
+	fs.BoolVar(&opts.parents, "p", false, "создавать родительские каталоги")
+	fs.BoolVar(&opts.parents, "parents", false, "создавать родительские каталоги")
+	fs.BoolVar(&opts.verbose, "v", false, "сообщать о созданных каталогах")
+	fs.BoolVar(&opts.verbose, "verbose", false, "сообщать о созданных каталогах")
+	fs.StringVar(&mode, "m", "", "права каталогов")
+	fs.StringVar(&mode, "mode", "", "права каталогов")
+
+	if err := parseOptions(fs, splitShortFlags(args, "m")); err != nil {
+		return nil, nil, err
+	}
+	fs.Visit(func(f *flag.Flag) {
+		opts.modeSet = opts.modeSet || f.Name == "m" || f.Name == "mode"
+	})
+	if opts.modeSet {
+		value, err := strconv.ParseUint(mode, 8, 32)
+		if err != nil || value > 0o7777 {
+			return nil, nil, fmt.Errorf("mkdir: неверный режим '%s': ожидается восьмеричное число", mode)
+		}
+		opts.mode = fileModeFromUnix(uint32(value))
+	}
+	if fs.NArg() == 0 {
+		return nil, nil, errors.New("mkdir: пропущен операнд")
+	}
+	return opts, fs.Args(), nil
+}
+
+// create создаёт каталог dir, с -p — вместе с родителями. Права из -m
+// применяются только к самому каталогу dir без учёта umask.
+func (o *mkdirOptions) create(ctx *CommandContext, dir string) error {
+	path := filepath.Clean(ctx.ResolvePath(dir))
+	if o.parents {
+		if info, err := os.Stat(path); err == nil {
+			if !info.IsDir() {
+				return fs.ErrExist
+			}
+			return nil
+		}
+		parent := filepath.Dir(path)
+		if parent != path {
+			if err := o.createParents(ctx, parent, filepath.Dir(filepath.Clean(dir))); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := os.Mkdir(path, 0o777); err != nil {
+		return err
+	}
+	if o.modeSet {
+		if err := os.Chmod(path, o.mode); err != nil {
+			return err
+		}
+	}
+	return o.report(ctx, dir)
+}
+
+// createParents создаёт недостающие каталоги пути path; name — тот же
+// путь в том виде, в котором его указал пользователь, для сообщений -v.
+func (o *mkdirOptions) createParents(ctx *CommandContext, path, name string) error {
+	info, err := os.Stat(path)
+	switch {
+	case err == nil && info.IsDir():
+		return nil
+	case err == nil:
+		return syscall.ENOTDIR
+	case !errors.Is(err, fs.ErrNotExist):
+		return err
+	}
+
+	if parent := filepath.Dir(path); parent != path {
+		if err := o.createParents(ctx, parent, filepath.Dir(name)); err != nil {
+			return err
+		}
+	}
+	if err := os.Mkdir(path, 0o777); err != nil && !errors.Is(err, fs.ErrExist) {
+		return err
+	}
+	return o.report(ctx, name)
+}
+
+// report сообщает о созданном каталоге с -v.
+func (o *mkdirOptions) report(ctx *CommandContext, name string) error {
+	if !o.verbose {
+		return nil
+	}
+	_, err := fmt.Fprintf(ctx.Stdout, "mkdir: создан каталог '%s'\n", name)
+	return err
+}
+
+// fileModeFromUnix переводит права в формате chmod (восьмеричные с битами
+// setuid, setgid и sticky) в fs.FileMode.
+func fileModeFromUnix(mode uint32) fs.FileMode {
+	result := fs.FileMode(mode & 0o777)
+	for _, special := range []struct {
+		bit  uint32
+		mode fs.FileMode
+	}{
+		{0o4000, fs.ModeSetuid},
+		{0o2000, fs.ModeSetgid},
+		{0o1000, fs.ModeSticky},
+	} {
+		if mode&special.bit != 0 {
+			result |= special.mode
+		}
+	}
+	return result
+}
+
+// Help возвращает справку по команде mkdir.
+func (m *MkdirCommand) Help() string {
+	return `NAME
+    mkdir - создаёт каталоги
+
+SYNOPSIS
+    mkdir [OPTION]... DIRECTORY...
+
+DESCRIPTION
+    Создаёт каталоги DIRECTORY. Относительные пути отсчитываются от
+    текущего каталога. Ошибка при создании одного каталога не мешает
+    создать остальные.
+
+OPTIONS
+    -p, --parents       создавать недостающие родительские каталоги; не
+                        считать ошибкой уже существующий каталог
+    -m, --mode=MODE     права каталога в восьмеричном виде (без учёта umask)
+    -v, --verbose       сообщать о каждом созданном каталоге
+
+EXIT STATUS
+    0 — успех, 1 — хотя бы один каталог создать не удалось.
+
+EXAMPLES
+    mkdir -p build/out/logs
+        → каталог вместе с build и build/out
+
+    mkdir -m 700 secrets
+        → каталог, доступный только владельцу`
+}
+
+var _ BuiltinCommand = (*MkdirCommand)(nil)
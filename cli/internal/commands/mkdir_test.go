@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMkdirCommand(t *testing.T) {
+	dir := t.TempDir()
+
+	out, stderr, status := runCommand(t, &MkdirCommand{}, dir, "", "a", "b")
+	if status != 0 || out != "" || stderr != "" {
+		t.Fatalf("ожидался код 0 без вывода, получено %d: %q, %q", status, out, stderr)
+	}
+	for _, name := range []string{"a", "b"} {
+		if info, err := os.Stat(filepath.Join(dir, name)); err != nil || !info.IsDir() {
+			t.Errorf("каталог %s не создан: %v", name, err)
+		}
+	}
+
+	_, stderr, status = runCommand(t, &MkdirCommand{}, dir, "", "a", "c")
+	if status != 1 || !strings.Contains(stderr, "mkdir: невозможно создать каталог 'a'") {
+		t.Errorf("ожидалась ошибка для существующего каталога, получено %d: %q", status, stderr)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "c")); err != nil {
+		t.Errorf("ошибка с одним каталогом не должна мешать остальным: %v", err)
+	}
+
+	_, stderr, status = runCommand(t, &MkdirCommand{}, dir, "", "x/y")
+	if status != 1 || stderr == "" {
+		t.Errorf("без -p отсутствующий родитель — ошибка, получено %d: %q", status, stderr)
+	}
+}
+
+func TestMkdirCommand_Parents(t *testing.T) {
+	dir := t.TempDir()
+
+	out, stderr, status := runCommand(t, &MkdirCommand{}, dir, "", "-pv", "a/b/c", "a/b")
+	if status != 0 || stderr != "" {
+		t.Fatalf("ожидался код 0 без ошибок, получено %d: %q", status, stderr)
+	}
+	expected := "mkdir: создан каталог 'a'\nmkdir: создан каталог 'a/b'\nmkdir: создан каталог 'a/b/c'\n"
+	if out != expected {
+		t.Errorf("ожидалось %q, получено %q", expected, out)
+	}
+
+	_ = os.WriteFile(filepath.Join(dir, "file"), nil, 0o644)
+	_, stderr, status = runCommand(t, &MkdirCommand{}, dir, "", "-p", "file/sub", "file")
+	if status != 1 || strings.Count(stderr, "невозможно создать каталог") != 2 {
+		t.Errorf("ожидались две ошибки из-за файла, получено %d: %q", status, stderr)
+	}
+}
+
+func TestMkdirCommand_Mode(t *testing.T) {
+	dir := t.TempDir()
+	_, stderr, status := runCommand(t, &MkdirCommand{}, dir, "", "-m", "700", "private")
+	if status != 0 {
+		t.Fatalf("ожидался код 0, получено %d: %q", status, stderr)
+	}
+	info, err := os.Stat(filepath.Join(dir, "private"))
+	if err != nil || info.Mode().Perm() != 0o700 {
+		t.Errorf("ожидались права 0700, получено %v, %v", info.Mode(), err)
+	}
+
+	for _, args := range [][]string{{"-m", "9", "x"}, {"-m", "u+x", "x"}, {}} {
+		_, stderr, status := runCommand(t, &MkdirCommand{}, dir, "", args...)
+		if status != 1 || stderr == "" {
+			t.Errorf("%v: ожидалась ошибка аргументов, получено %d: %q", args, status, stderr)
+		}
+	}
+}
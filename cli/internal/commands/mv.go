@@ -0,0 +1,197 @@
+package commands
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"syscall"
+
+	customErrors "github.com/ggerlakh/software-design-mhs-itmo/cli/internal/errors"
+)
+
+// MvCommand реализует встроенную команду "mv".
+// Она перемещает и переименовывает файлы.
+type MvCommand struct{}
+
+// mvOptions — параметры mv.
+type mvOptions struct {
+	noClobber bool // -n: не перезаписывать существующие файлы
+	verbose   bool // -v: сообщать о каждом файле
+}
+
+// Name возвращает имя команды.
+func (m *MvCommand) Name() string {
+	return "mv"
+}
+
+// Exec выполняет команду mv.
+//
+// Синтаксис:
+//
+//	mv [-f | -n] [-v] SOURCE DEST
+//	mv [-f | -n] [-v] SOURCE... DIRECTORY
+//
+// Если DEST — существующий каталог, файлы перемещаются в него. Между
+// файловыми системами файлы копируются с сохранением прав и времени,
+// после чего исходные удаляются.
+//
+// Примеры:
+//
+//	mv old.txt new.txt            → переименование
+//	mv *.log logs/                → перемещение в каталог
+func (m *MvCommand) Exec(args []string, ctx *CommandContext) error {
+	opts, sources, dest, err := parseMvArgs(args)
+	if err != nil {
+		if writeErr := warnf(ctx, "%v\nПопробуйте 'mv --help' для получения справки.", err); writeErr != nil {
+			return writeErr
+		}
+		return &customErrors.ExitStatusError{Code: 1}
+	}
+
+	targets, err := transferTargets(ctx, "mv", sources, dest, false)
+	if err != nil {
+		return err
+	}
+	if targets == nil {
+		return &customErrors.ExitStatusError{Code: 1}
+	}
+
+	failed := false
+	for i, source := range sources {
+		ok, err := opts.move(ctx, transfer{path: ctx.ResolvePath(source), name: source}, targets[i])
+		if err != nil {
+			return err
+		}
+		failed = failed || !ok
+	}
+	if failed {
+		return &customErrors.ExitStatusError{Code: 1}
+	}
+	return nil
+}
+
+// parseMvArgs разбирает аргументы mv и возвращает источники и цель.
+func parseMvArgs(args []string) (*mvOptions, []string, string, error) {
+	fs := flag.NewFlagSet("mv", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	opts := &mvOptions{}
+	var force bool
+	fs.BoolVar(&force, "f", false, "перезаписывать без вопросов")
+	fs.BoolVar(&force, "force", false, "перезаписывать без вопросов")
+	fs.BoolVar(&opts.noClobber, "n", false, "не перезаписывать существующие файлы")
+	fs.BoolVar(&opts.noClobber, "no-clobber", false, "не перезаписывать существующие файлы")
+	fs.BoolVar(&opts.verbose, "v", false, "сообщать о каждом файле")
+	fs.BoolVar(&opts.verbose, "verbose", false, "сообщать о каждом файле")
+
+	if err := parseOptions(fs, splitShortFlags(args, "")); err != nil {
+		return nil, nil, "", err
+	}
+	sources, dest, err := transferOperands("mv", fs.Args())
+	return opts, sources, dest, err
+}
+
+// move перемещает source в target. Возвращает false, если переместить
+// не удалось (сообщение уже напечатано).
+func (o *mvOptions) move(ctx *CommandContext, source, target transfer) (bool, error) {
+	info, err := os.Lstat(source.path)
+	if err != nil {
+		return false, warnf(ctx, "mv: не удалось выполнить stat для '%s': %v", source.name, fileError(err))
+	}
+	targetInfo, err := os.Lstat(target.path)
+	if err == nil {
+		if os.SameFile(info, targetInfo) {
+			return false, warnf(ctx, "mv: '%s' и '%s' — один и тот же файл", source.name, target.name)
+		}
+		if o.noClobber {
+			return true, nil
+		}
+	}
+	if info.IsDir() {
+		sourceAbs, err1 := filepath.Abs(source.path)
+		targetAbs, err2 := filepath.Abs(target.path)
+		if err1 == nil && err2 == nil && isWithin(targetAbs, sourceAbs) {
+			return false, warnf(ctx, "mv: невозможно переместить '%s' в свой подкаталог '%s'", source.name, target.name)
+		}
+	}
+
+	err = os.Rename(source.path, target.path)
+	if errors.Is(err, syscall.EXDEV) {
+		return o.moveAcross(ctx, source, info, target)
+	}
+	if err != nil {
+		return false, warnf(ctx, "mv: невозможно переместить '%s' в '%s': %v", source.name, target.name, fileError(err))
+	}
+	return true, o.report(ctx, source, target)
+}
+
+// moveAcross перемещает файл на другую файловую систему: копирует его
+// с сохранением атрибутов и удаляет источник, только если копирование
+// прошло полностью.
+func (o *mvOptions) moveAcross(ctx *CommandContext, source transfer, info os.FileInfo, target transfer) (bool, error) {
+	if info.IsDir() {
+		// Каталог копируется поверх существующего только если тот пуст,
+		// как при переименовании
+		if err := os.Remove(target.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return false, warnf(ctx, "mv: невозможно переместить '%s' в '%s': %v", source.name, target.name, fileError(err))
+		}
+	}
+
+	copier := &cpOptions{command: "mv", recursive: true, preserve: true, force: true}
+	ok, err := copier.copy(ctx, source, info, target)
+	if err != nil || !ok {
+		return false, err
+	}
+	if err := os.RemoveAll(source.path); err != nil {
+		return false, warnf(ctx, "mv: невозможно удалить '%s': %v", source.name, fileError(err))
+	}
+	return true, o.report(ctx, source, target)
+}
+
+// report с -v сообщает о перемещённом файле.
+func (o *mvOptions) report(ctx *CommandContext, source, target transfer) error {
+	if !o.verbose {
+		return nil
+	}
+	_, err := fmt.Fprintf(ctx.Stdout, "переименован '%s' -> '%s'\n", source.name, target.name)
+	return err
+}
+
+// Help возвращает справку по команде mv.
+func (m *MvCommand) Help() string {
+	return `NAME
+    mv - перемещает и переименовывает файлы
+
+SYNOPSIS
+    mv [OPTION]... SOURCE DEST
+    mv [OPTION]... SOURCE... DIRECTORY
+
+DESCRIPTION
+    Переименовывает SOURCE в DEST или перемещает несколько файлов в
+    каталог DIRECTORY. Если DEST — существующий каталог, файл попадает в
+    него под своим именем. Существующий файл DEST заменяется. Каталог
+    нельзя переместить внутрь самого себя.
+
+    Между файловыми системами файлы и каталоги копируются с сохранением
+    прав и времени изменения, а затем исходные удаляются.
+
+OPTIONS
+    -f, --force         заменять существующие файлы (по умолчанию)
+    -n, --no-clobber    не заменять существующие файлы
+    -v, --verbose       сообщать о каждом перемещённом файле
+
+EXIT STATUS
+    0 — успех, 1 — хотя бы один файл переместить не удалось.
+
+EXAMPLES
+    mv draft.md final.md
+        → переименовать файл
+
+    mv -n *.jpg photos/
+        → переместить снимки, не заменяя уже существующие`
+}
+
+var _ BuiltinCommand = (*MvCommand)(nil)
@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMvCommand(t *testing.T) {
+	dir := t.TempDir()
+	makeTree(t, dir, map[string]string{"a.txt": "alpha", "b.txt": "beta", "tree/x.txt": "x", "out/": ""})
+
+	out, stderr, status := runCommand(t, &MvCommand{}, dir, "", "-v", "a.txt", "renamed.txt")
+	if status != 0 || stderr != "" || out != "переименован 'a.txt' -> 'renamed.txt'\n" {
+		t.Fatalf("неожиданный результат: %d, %q, %q", status, out, stderr)
+	}
+
+	_, stderr, status = runCommand(t, &MvCommand{}, dir, "", "renamed.txt", "tree", "out")
+	if status != 0 || stderr != "" {
+		t.Fatalf("ожидался код 0, получено %d: %q", status, stderr)
+	}
+	expected := map[string]string{"b.txt": "beta", "out/": "", "out/renamed.txt": "alpha", "out/tree/": "", "out/tree/x.txt": "x"}
+	if got := readTree(t, dir); !equalTrees(got, expected) {
+		t.Errorf("ожидалось %v, получено %v", expected, got)
+	}
+
+	_, stderr, status = runCommand(t, &MvCommand{}, dir, "", "-n", "b.txt", "out/renamed.txt")
+	if status != 0 || stderr != "" {
+		t.Errorf("-n не считает существующий файл ошибкой, получено %d: %q", status, stderr)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "out", "renamed.txt")); string(data) != "alpha" {
+		t.Errorf("-n не должен заменять файл, получено %q", data)
+	}
+
+	_, stderr, status = runCommand(t, &MvCommand{}, dir, "", "b.txt", "out/renamed.txt")
+	if status != 0 || stderr != "" {
+		t.Fatalf("ожидался код 0, получено %d: %q", status, stderr)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "out", "renamed.txt")); string(data) != "beta" {
+		t.Errorf("ожидалось %q, получено %q", "beta", data)
+	}
+}
+
+func TestMvCommand_Errors(t *testing.T) {
+	dir := t.TempDir()
+	makeTree(t, dir, map[string]string{"a.txt": "", "b.txt": "", "tree/sub/": ""})
+
+	tests := []struct {
+		args    []string
+		message string
+	}{
+		{[]string{"a.txt", "a.txt"}, "mv: 'a.txt' и 'a.txt' — один и тот же файл"},
+		{[]string{"tree", "tree/sub"}, "mv: невозможно переместить 'tree' в свой подкаталог 'tree/sub/tree'"},
+		{[]string{"missing", "c.txt"}, "mv: не удалось выполнить stat для 'missing'"},
+		{[]string{"a.txt", "b.txt", "c.txt"}, "mv: целевой объект 'c.txt' не является каталогом"},
+		{[]string{}, "mv: пропущен операнд"},
+	}
+	for _, tt := range tests {
+		_, stderr, status := runCommand(t, &MvCommand{}, dir, "", tt.args...)
+		if status != 1 || !strings.Contains(stderr, tt.message) {
+			t.Errorf("%v: ожидалась ошибка %q, получено %d: %q", tt.args, tt.message, status, stderr)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "tree", "sub")); err != nil {
+		t.Errorf("каталог не должен перемещаться: %v", err)
+	}
+}
@@ -0,0 +1,456 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	customErrors "github.com/ggerlakh/software-design-mhs-itmo/cli/internal/errors"
+)
+
+// PrintfCommand реализует встроенную команду "printf".
+// Она форматирует аргументы по строке формата, как printf в bash.
+type PrintfCommand struct{}
+
+// Коды возврата printf.
+const (
+	printfFailure = 1 // неверный аргумент или формат
+	printfUsage   = 2 // неверное использование команды
+)
+
+// variableNamePattern — допустимое имя переменной shell.
+var variableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// validVariableName проверяет, что name можно использовать как имя переменной.
+func validVariableName(name string) bool {
+	return variableNamePattern.MatchString(name)
+}
+
+// Name возвращает имя команды.
+func (p *PrintfCommand) Name() string {
+	return "printf"
+}
+
+// Exec выполняет команду printf.
+//
+// Синтаксис:
+//
+//	printf [-v VAR] FORMAT [ARGUMENT...]
+//
+// Строка формата поддерживает последовательности с обратной косой чертой
+// и спецификаторы %s %b %q %c %d %i %u %o %x %X %f %F %e %E %g %G с флагами
+// "-+ #0", шириной и точностью, которые можно задать аргументом через "*".
+// Если аргументов больше, чем спецификаторов, формат применяется повторно;
+// недостающие аргументы считаются пустыми строками или нулями. С -v VAR
+// результат записывается в переменную VAR вместо вывода.
+//
+// Возвращает ExitStatusError с кодом 1, если аргумент не является числом
+// или формат некорректен, и с кодом 2 при неверном использовании.
+//
+// Примеры:
+//
+//	printf '%s=%d\n' a 1 b 2   → a=1\nb=2\n
+//	printf '%5.2f|' 3.14159    →  3.14|
+//	printf -v line '%-5s|' ab  → line="ab   |"
+func (p *PrintfCommand) Exec(args []string, ctx *CommandContext) error {
+	var variable string
+	for len(args) > 0 && strings.HasPrefix(args[0], "-") && args[0] != "-" {
+		arg := args[0]
+		args = args[1:]
+		if arg == "--" {
+			break
+		}
+
+		switch {
+		case arg == "-v" && len(args) > 0:
+			variable, args = args[0], args[1:]
+		case strings.HasPrefix(arg, "-v") && len(arg) > 2:
+			variable = arg[2:]
+		default:
+			return printfFail(ctx, printfUsage, fmt.Errorf("printf: %s: неверная опция\n%s", arg, printfUsageLine))
+		}
+		if !validVariableName(variable) {
+			return printfFail(ctx, printfUsage, fmt.Errorf("printf: '%s': недопустимое имя переменной", variable))
+		}
+	}
+
+	if len(args) == 0 {
+		return printfFail(ctx, printfUsage, errors.New(printfUsageLine))
+	}
+
+	f := &printfFormatter{args: args[1:]}
+	f.run(args[0])
+
+	if variable != "" {
+		if err := ctx.Vars.Set(variable, f.out.String()); err != nil {
+			return printfFail(ctx, printfFailure, fmt.Errorf("printf: %w", err))
+		}
+	} else if _, err := io.WriteString(ctx.Stdout, f.out.String()); err != nil {
+		return err
+	}
+
+	for _, err := range f.errs {
+		if _, writeErr := fmt.Fprintln(ctx.Stderr, err); writeErr != nil {
+			return writeErr
+		}
+	}
+	if len(f.errs) > 0 {
+		return &customErrors.ExitStatusError{Code: printfFailure}
+	}
+	return nil
+}
+
+// printfUsageLine — краткая подсказка по использованию printf.
+const printfUsageLine = "printf: использование: printf [-v VAR] FORMAT [ARGUMENT...]"
+
+// printfFail печатает ошибку в stderr и возвращает код возврата code.
+func printfFail(ctx *CommandContext, code int, err error) error {
+	if _, writeErr := fmt.Fprintln(ctx.Stderr, err); writeErr != nil {
+		return writeErr
+	}
+	return &customErrors.ExitStatusError{Code: code}
+}
+
+// printfFormatter применяет строку формата к аргументам.
+type printfFormatter struct {
+	args []string
+	next int // индекс следующего аргумента
+
+	out  strings.Builder
+	errs []error
+	stop bool // \c в %b или ошибка в формате: вывод прекращается
+}
+
+// run применяет format к аргументам, повторяя его, пока аргументы
+// не закончатся. Формат без спецификаторов выводится один раз.
+func (f *printfFormatter) run(format string) {
+	for {
+		start := f.next
+		f.format(format)
+		if f.stop || f.next >= len(f.args) || f.next == start {
+			return
+		}
+	}
+}
+
+// format применяет format один раз.
+func (f *printfFormatter) format(format string) {
+	for len(format) > 0 && !f.stop {
+		end := strings.IndexByte(format, '%')
+		if end < 0 {
+			end = len(format)
+		}
+		if end > 0 {
+			text, _ := expandEscapes(format[:end], escapeFormat)
+			f.out.WriteString(text)
+			format = format[end:]
+			continue
+		}
+
+		format = f.directive(format[1:])
+	}
+}
+
+// directive обрабатывает спецификатор, начинающийся после "%",
+// и возвращает остаток формата.
+func (f *printfFormatter) directive(format string) string {
+	if strings.HasPrefix(format, "%") {
+		f.out.WriteByte('%')
+		return format[1:]
+	}
+
+	i := 0
+	flags := ""
+	for i < len(format) && strings.IndexByte("-+ #0", format[i]) >= 0 {
+		flags += format[i : i+1]
+		i++
+	}
+
+	width := ""
+	if i < len(format) && format[i] == '*' {
+		value := f.intArg()
+		if value < 0 {
+			flags += "-"
+			value = -value
+		}
+		width = strconv.FormatInt(value, 10)
+		i++
+	} else {
+		start := i
+		for i < len(format) && isDigit(format[i]) {
+			i++
+		}
+		width = format[start:i]
+	}
+
+	precision := ""
+	hasPrecision := false
+	if i < len(format) && format[i] == '.' {
+		hasPrecision = true
+		i++
+		if i < len(format) && format[i] == '*' {
+			value := f.intArg()
+			if value < 0 {
+				hasPrecision = false
+			}
+			precision = strconv.FormatInt(value, 10)
+			i++
+		} else {
+			start := i
+			for i < len(format) && isDigit(format[i]) {
+				i++
+			}
+			precision = format[start:i]
+			if precision == "" {
+				precision = "0"
+			}
+		}
+	}
+
+	// Модификаторы длины C не влияют на результат
+	for i < len(format) && strings.IndexByte("hlLjzt", format[i]) >= 0 {
+		i++
+	}
+
+	if i == len(format) {
+		f.fail(fmt.Errorf("printf: '%%%s': отсутствует символ формата", format))
+		return ""
+	}
+
+	size := width
+	if hasPrecision {
+		size += "." + precision
+	}
+	f.convert(format[i], flags, size, hasPrecision)
+	return format[i+1:]
+}
+
+// convert форматирует очередной аргумент по спецификатору verb с флагами
+// flags и шириной и точностью size в синтаксисе fmt.
+func (f *printfFormatter) convert(verb byte, flags, size string, hasPrecision bool) {
+	spec := "%" + flags + size
+	// Флаг 0 для строк в C не действует
+	stringSpec := "%" + strings.ReplaceAll(flags, "0", "") + size
+
+	switch verb {
+	case 's':
+		f.out.WriteString(fmt.Sprintf(stringSpec+"s", f.stringArg()))
+	case 'b':
+		text, stop := expandEscapes(f.stringArg(), escapeEcho)
+		f.out.WriteString(fmt.Sprintf(stringSpec+"s", text))
+		f.stop = stop
+	case 'q':
+		f.out.WriteString(fmt.Sprintf(stringSpec+"s", shellQuote(f.stringArg())))
+	case 'c':
+		arg := f.stringArg()
+		if r, n := utf8.DecodeRuneInString(arg); n > 0 {
+			f.out.WriteString(fmt.Sprintf(stringSpec+"s", string(r)))
+		}
+	case 'd', 'i':
+		f.out.WriteString(fmt.Sprintf(spec+"d", f.intArg()))
+	case 'u':
+		f.out.WriteString(fmt.Sprintf(spec+"d", uint64(f.intArg())))
+	case 'o', 'x', 'X':
+		f.out.WriteString(fmt.Sprintf(spec+string(verb), uint64(f.intArg())))
+	case 'f', 'F', 'e', 'E', 'g', 'G':
+		value := f.floatArg()
+		if math.IsInf(value, 0) || math.IsNaN(value) {
+			f.out.WriteString(fmt.Sprintf(stringSpec+"s", printfSpecialFloat(value, verb)))
+			return
+		}
+		// В C точность %g по умолчанию равна 6, в Go — минимально необходимая
+		if (verb == 'g' || verb == 'G') && !hasPrecision {
+			spec += ".6"
+		}
+		f.out.WriteString(fmt.Sprintf(spec+string(verb), value))
+	default:
+		f.fail(fmt.Errorf("printf: '%c': неверный символ формата", verb))
+	}
+}
+
+// printfSpecialFloat записывает бесконечность и NaN так же, как C.
+func printfSpecialFloat(value float64, verb byte) string {
+	text := "nan"
+	switch {
+	case math.IsInf(value, 1):
+		text = "inf"
+	case math.IsInf(value, -1):
+		text = "-inf"
+	}
+	if verb >= 'A' && verb <= 'Z' {
+		text = strings.ToUpper(text)
+	}
+	return text
+}
+
+// fail запоминает ошибку формата и прекращает вывод.
+func (f *printfFormatter) fail(err error) {
+	f.errs = append(f.errs, err)
+	f.stop = true
+}
+
+// stringArg возвращает следующий аргумент или "", если аргументы закончились.
+func (f *printfFormatter) stringArg() string {
+	if f.next >= len(f.args) {
+		return ""
+	}
+	f.next++
+	return f.args[f.next-1]
+}
+
+// intArg возвращает следующий аргумент как целое число. Некорректный
+// аргумент записывается в ошибки, а используется его числовое начало.
+func (f *printfFormatter) intArg() int64 {
+	arg := f.stringArg()
+	value, err := parsePrintfInt(arg)
+	if err != nil {
+		f.errs = append(f.errs, fmt.Errorf("printf: %s: %w", arg, err))
+	}
+	return value
+}
+
+// floatArg возвращает следующий аргумент как число с плавающей точкой.
+func (f *printfFormatter) floatArg() float64 {
+	arg := f.stringArg()
+	if code, ok := printfCharCode(arg); ok {
+		return float64(code)
+	}
+
+	text := strings.TrimSpace(arg)
+	if text == "" {
+		return 0
+	}
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil && !errors.Is(err, strconv.ErrRange) {
+		f.errs = append(f.errs, fmt.Errorf("printf: %s: неверное число", arg))
+		return 0
+	}
+	return value
+}
+
+// printfCharCode возвращает код символа для аргументов вида 'c и "c:
+// так printf получает числовое значение символа.
+func printfCharCode(arg string) (int64, bool) {
+	if arg == "" || (arg[0] != '\'' && arg[0] != '"') {
+		return 0, false
+	}
+	r, _ := utf8.DecodeRuneInString(arg[1:])
+	if len(arg) == 1 {
+		r = 0
+	}
+	return int64(r), true
+}
+
+// parsePrintfInt разбирает целое число, как printf в bash: допускаются
+// пробелы в начале, знак, префиксы 0x (шестнадцатеричное) и 0
+// (восьмеричное), а также 'c для кода символа. Если после числа есть
+// лишние символы, возвращается ошибка и значение числового начала.
+func parsePrintfInt(arg string) (int64, error) {
+	if code, ok := printfCharCode(arg); ok {
+		return code, nil
+	}
+
+	text := strings.TrimLeft(arg, " \t\n")
+	if text == "" {
+		return 0, nil
+	}
+
+	sign := ""
+	if text[0] == '+' || text[0] == '-' {
+		sign, text = text[:1], text[1:]
+	}
+
+	base := 10
+	switch {
+	case len(text) > 2 && (text[:2] == "0x" || text[:2] == "0X"):
+		base, text = 16, text[2:]
+	case len(text) > 1 && text[0] == '0':
+		base, text = 8, text[1:]
+	}
+
+	_, n := parseDigits(text, base, len(text))
+	if n == 0 && base != 8 {
+		return 0, errors.New("неверное число")
+	}
+
+	value, err := strconv.ParseInt(sign+text[:n], base, 64)
+	if n == 0 {
+		value, err = 0, nil
+	}
+	switch {
+	case errors.Is(err, strconv.ErrRange):
+		return value, errors.New("слишком большое число")
+	case n < len(text):
+		return value, errors.New("неверное число")
+	}
+	return value, nil
+}
+
+// Help возвращает справку по команде printf.
+func (p *PrintfCommand) Help() string {
+	return `NAME
+    printf - форматированный вывод
+
+SYNOPSIS
+    printf [-v VAR] FORMAT [ARGUMENT...]
+
+DESCRIPTION
+    Выводит аргументы по строке формата FORMAT, как printf в bash.
+    Обычные символы формата выводятся как есть, последовательности
+    с обратной косой чертой (\n, \t, \\, \NNN, \xHH, \uHHHH) раскрываются,
+    а спецификаторы % заменяются очередными аргументами.
+
+    Если аргументов больше, чем спецификаторов, формат применяется
+    повторно. Недостающие аргументы считаются пустой строкой или нулём.
+
+    Числовые аргументы могут быть десятичными, восьмеричными (0NNN),
+    шестнадцатеричными (0xHH) или кодом символа ('c).
+
+OPTIONS
+    -v VAR   записать результат в переменную VAR вместо вывода
+
+FORMAT
+    %s       строка
+    %b       строка с раскрытием последовательностей, как в echo -e
+    %q       строка, экранированная для повторного ввода в shell
+    %c       первый символ аргумента
+    %d, %i   целое число со знаком
+    %u       целое число без знака
+    %o       восьмеричное число
+    %x, %X   шестнадцатеричное число
+    %f, %F   число с фиксированной точкой
+    %e, %E   число в экспоненциальной записи
+    %g, %G   %f или %e, в зависимости от величины
+    %%       символ %
+
+    Между % и символом формата можно указать флаги "-+ #0", ширину
+    и точность (.N). Ширина и точность "*" берутся из аргументов.
+
+EXIT STATUS
+    0 — успех, 1 — неверный числовой аргумент или формат,
+    2 — неверное использование.
+
+EXAMPLES
+    printf '%s=%d\n' a 1 b 2
+        → a=1
+          b=2
+
+    printf '%-6s|%6.2f|\n' pi 3.14159
+        → pi    |  3.14|
+
+    printf '%*d|\n' 5 42
+        →    42|
+
+    printf -v hex '%x' 255
+        → переменная hex = ff
+
+    printf '%q\n' 'a b'
+        → a\ b`
+}
+
+var _ BuiltinCommand = (*PrintfCommand)(nil)
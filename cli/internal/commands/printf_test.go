@@ -0,0 +1,129 @@
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
+)
+
+// runPrintf выполняет printf и возвращает stdout, stderr, код возврата и переменные.
+func runPrintf(t *testing.T, args ...string) (string, string, int, *session.Variables) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	ctx := &CommandContext{
+		Stdin:  strings.NewReader(""),
+		Stdout: &stdout,
+		Stderr: &stderr,
+		Vars:   session.NewVariables(nil),
+	}
+	err := (&PrintfCommand{}).Exec(args, ctx)
+	return stdout.String(), stderr.String(), testStatus(err), ctx.Vars
+}
+
+func TestPrintfCommand_Format(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{"строки", []string{"%s-%s\n", "a", "b"}, "a-b\n"},
+		{"повтор формата", []string{"%s=%d\n", "a", "1", "b", "2"}, "a=1\nb=2\n"},
+		{"недостающие аргументы", []string{"%s|%d|%s\n", "x"}, "x|0|\n"},
+		{"без спецификаторов один раз", []string{"text\n", "extra"}, "text\n"},
+		{"ширина и выравнивание", []string{"[%5s][%-5s]", "ab", "cd"}, "[   ab][cd   ]"},
+		{"точность строки", []string{"%.2s", "abcdef"}, "ab"},
+		{"ширина через *", []string{"[%*d][%-*d]", "4", "7", "3", "8"}, "[   7][8  ]"},
+		{"отрицательная ширина через *", []string{"[%*s]", "-3", "a"}, "[a  ]"},
+		{"точность через *", []string{"%.*f", "2", "3.14159"}, "3.14"},
+		{"целые", []string{"%d %i %+d % d %05d %.3d", "42", "-7", "5", "5", "-42", "7"}, "42 -7 +5  5 -0042 007"},
+		{"основания", []string{"%o %x %X %#x %#o", "8", "255", "255", "255", "8"}, "10 ff FF 0xff 010"},
+		{"числа в аргументах", []string{"%d %d %d %d", "0x1f", "010", "'A", " 12"}, "31 8 65 12"},
+		{"без знака", []string{"%u", "-1"}, "18446744073709551615"},
+		{"с плавающей точкой", []string{"%f %.2f %e %E", "1.5", "2.345", "1234.5", "0.00012"},
+			"1.500000 2.35 1.234500e+03 1.200000E-04"},
+		{"%g как в C", []string{"%g %g %g %G", "1234567", "0.0001", "100", "1e-10"}, "1.23457e+06 0.0001 100 1E-10"},
+		{"бесконечность", []string{"%f %F", "inf", "-inf"}, "inf -INF"},
+		{"%c", []string{"%c%c%c", "abc", "ж", ""}, "aж"},
+		{"%b", []string{"%b|%s", `a\tb`, `a\tb`}, "a\tb|a\\tb"},
+		{"%b и \\c", []string{"%b%s\n", `x\cy`, "z"}, "x"},
+		{"%q", []string{"%q %q %q %q", "a b", "it's", "", "tab\there"}, `a\ b it\'s '' $'tab\there'`},
+		{"%%", []string{"100%%\n"}, "100%\n"},
+		{"последовательности формата", []string{`a\tb\n\101\x42\"\\`}, "a\tb\nAB\"\\"},
+		{"модификаторы длины", []string{"%ld %lld", "1", "2"}, "1 2"},
+		{"--", []string{"--", "%s", "-v"}, "-v"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, stderr, status, _ := runPrintf(t, tt.args...)
+			if out != tt.expected || status != 0 {
+				t.Fatalf("ожидалось %q, получено %q (код %d, stderr %q)", tt.expected, out, status, stderr)
+			}
+		})
+	}
+}
+
+func TestPrintfCommand_Variable(t *testing.T) {
+	out, _, status, vars := runPrintf(t, "-v", "line", "%-4s|%03d", "ab", "7")
+	if line, _ := vars.Get("line"); out != "" || status != 0 || line != "ab  |007" {
+		t.Fatalf("ожидалась переменная line=%q без вывода, получено %q, %q (код %d)", "ab  |007", line, out, status)
+	}
+
+	_, _, _, vars = runPrintf(t, "-vhex", "%x", "255")
+	if hex, _ := vars.Get("hex"); hex != "ff" {
+		t.Fatalf("ожидалось hex=ff, получено %q", hex)
+	}
+
+	_, stderr, status, _ := runPrintf(t, "-v", "1bad", "x")
+	if status != 2 || !strings.Contains(stderr, "недопустимое имя") {
+		t.Fatalf("ожидалась ошибка имени переменной: %q (код %d)", stderr, status)
+	}
+}
+
+func TestPrintfCommand_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		expected string
+		status   int
+		message  string
+	}{
+		{"неверное число", []string{"%d|%d\n", "abc", "5"}, "0|5\n", 1, "abc: неверное число"},
+		{"числовое начало", []string{"%d\n", "12abc"}, "12\n", 1, "неверное число"},
+		{"неверное число с плавающей точкой", []string{"%.1f\n", "x"}, "0.0\n", 1, "неверное число"},
+		{"неверный символ формата", []string{"a%kb", "1"}, "a", 1, "'k': неверный символ формата"},
+		{"нет символа формата", []string{"a%5"}, "a", 1, "отсутствует символ формата"},
+		{"нет формата", nil, "", 2, "использование"},
+		{"неверная опция", []string{"-x", "%s"}, "", 2, "неверная опция"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, stderr, status, _ := runPrintf(t, tt.args...)
+			if out != tt.expected || status != tt.status || !strings.Contains(stderr, tt.message) {
+				t.Fatalf("ожидалось %q с кодом %d и %q, получено %q с кодом %d и %q",
+					tt.expected, tt.status, tt.message, out, status, stderr)
+			}
+		})
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	tests := map[string]string{
+		"plain":       "plain",
+		"a b":         `a\ b`,
+		"$HOME;`x`":   "\\$HOME\\;\\`x\\`",
+		"привет мир":  `привет\ мир`,
+		"a\nb":        `$'a\nb'`,
+		"\x1b[0m\x01": `$'\E[0m\001'`,
+		"it's\n":      `$'it\'s\n'`,
+		"\xff":        `$'\377'`,
+	}
+	for input, expected := range tests {
+		if got := shellQuote(input); got != expected {
+			t.Errorf("%q: ожидалось %q, получено %q", input, expected, got)
+		}
+	}
+}
@@ -3,10 +3,11 @@ package commands
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 )
 
 // PwdCommand реализует встроенную команду "pwd".
-// Она выводит текущий рабочий каталог.
+// Она выводит текущий рабочий каталог сеанса (CommandContext.Dir).
 type PwdCommand struct{}
 
 // Name возвращает имя команды.
@@ -21,7 +22,10 @@ func (p *PwdCommand) Name() string {
 //
 //	pwd → /home/user/project
 func (p *PwdCommand) Exec(args []string, ctx *CommandContext) error {
-	dir, err := os.Getwd()
+	dir, err := filepath.Abs(ctx.Dir)
+	if ctx.Dir == "" {
+		dir, err = os.Getwd()
+	}
 	if err != nil {
 		return err
 	}
@@ -5,6 +5,8 @@ import (
 	"os"
 	"strings"
 	"testing"
+
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
 )
 
 // testExecWithOutput выполняет команду с переданными аргументами и возвращает вывод
@@ -14,7 +16,7 @@ func testPwdExecWithOutput(cmd CommandExecutor, args []string) string {
 		Stdin:  os.Stdin,
 		Stdout: &buf,
 		Stderr: os.Stderr,
-		Env:    make(map[string]string),
+		Vars:   session.NewVariables(nil),
 		Dir:    ".",
 	}
 	cmd.Exec(args, ctx)
@@ -0,0 +1,390 @@
+package commands
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	customErrors "github.com/ggerlakh/software-design-mhs-itmo/cli/internal/errors"
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
+)
+
+// Коды возврата read, как в bash.
+const (
+	readUsageCode       = 2
+	readTimeoutCode     = 142 // 128 + SIGALRM
+	readInterruptedCode = 130 // 128 + SIGINT
+)
+
+// defaultIFS — разделители полей, если переменная IFS не задана.
+const defaultIFS = " \t\n"
+
+// ReadCommand реализует встроенную команду "read".
+// Она читает строку из stdin и присваивает её поля переменным.
+type ReadCommand struct{}
+
+// readOptions — параметры read.
+type readOptions struct {
+	raw     bool          // -r: обратная косая черта — обычный символ
+	silent  bool          // -s: не отображать ввод на терминале
+	prompt  string        // -p: приглашение в stderr, если ввод — терминал
+	timeout time.Duration // -t: время ожидания; 0 — без ограничения
+	poll    bool          // -t 0: только проверить, есть ли ввод
+	nchars  int           // -n: не больше символов; -1 — без ограничения
+	delim   byte          // -d: конец строки
+	array   string        // -a: имя массива для полей
+}
+
+// Name возвращает имя команды.
+func (r *ReadCommand) Name() string {
+	return "read"
+}
+
+// Exec выполняет команду read.
+//
+// Синтаксис:
+//
+//	read [-rs] [-a ARRAY] [-d DELIM] [-n N] [-p PROMPT] [-t TIMEOUT] [NAME...]
+//
+// Строка делится на поля по символам IFS; последней переменной достаётся
+// остаток строки. Без NAME строка целиком записывается в REPLY. Ввод
+// читается по байту, поэтому после read в потоке остаётся всё, что
+// идёт за прочитанной строкой.
+//
+// Примеры:
+//
+//	read -r name rest <<< "a b c"  → name=a, rest="b c"
+//	IFS=: read -ra parts           → поля строки в массив parts
+//	read -t 5 -p "Продолжить? " answer
+func (r *ReadCommand) Exec(args []string, ctx *CommandContext) error {
+	opts, names, err := parseReadArgs(args)
+	if err != nil {
+		return readFailure(ctx, err)
+	}
+	for _, name := range append([]string{opts.array}, names...) {
+		if name != "" && !session.IsName(name) {
+			return readFailure(ctx, fmt.Errorf("read: '%s': недопустимый идентификатор", name))
+		}
+	}
+
+	src := newByteSource(ctx, opts.timeout)
+	if opts.poll {
+		if !src.ready() {
+			return &customErrors.ExitStatusError{Code: 1}
+		}
+		return nil
+	}
+
+	terminal := isTerminal(ctx.Stdin)
+	if opts.prompt != "" && terminal {
+		if _, err := io.WriteString(ctx.Stderr, opts.prompt); err != nil {
+			return err
+		}
+	}
+	if opts.silent && terminal {
+		if err := setEcho(ctx.Stdin, false); err == nil {
+			defer func() {
+				_ = setEcho(ctx.Stdin, true)
+			}()
+		}
+	}
+
+	line, quoted, readErr := readLine(src, opts)
+	switch {
+	case errors.Is(readErr, errReadInterrupted):
+		return &customErrors.ExitStatusError{Code: readInterruptedCode}
+	case readErr != nil && !errors.Is(readErr, io.EOF) && !errors.Is(readErr, errReadTimeout):
+		return readFailure(ctx, fmt.Errorf("read: ошибка чтения: %w", readErr))
+	}
+
+	// При конце ввода и истечении времени прочитанная часть всё равно
+	// присваивается
+	if err := assignRead(ctx, opts, names, line, quoted); err != nil {
+		return readFailure(ctx, fmt.Errorf("read: %w", err))
+	}
+	switch {
+	case errors.Is(readErr, errReadTimeout):
+		return &customErrors.ExitStatusError{Code: readTimeoutCode}
+	case readErr != nil:
+		return &customErrors.ExitStatusError{Code: 1}
+	}
+	return nil
+}
+
+// readUsageError — ошибка в параметрах read: код возврата 2.
+type readUsageError struct {
+	err error
+}
+
+func (e *readUsageError) Error() string {
+	return e.err.Error()
+}
+
+// parseReadArgs разбирает параметры read и возвращает имена переменных.
+func parseReadArgs(args []string) (*readOptions, []string, error) {
+	fs := flag.NewFlagSet("read", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	opts := &readOptions{nchars: -1, delim: '\n'}
+	var timeout, nchars, delim string
+	fs.BoolVar(&opts.raw, "r", false, "не обрабатывать обратную косую черту")
+	fs.BoolVar(&opts.silent, "s", false, "не отображать ввод")
+	fs.StringVar(&opts.prompt, "p", "", "приглашение")
+	fs.StringVar(&timeout, "t", "", "время ожидания в секундах")
+	fs.StringVar(&nchars, "n", "", "не больше N символов")
+	fs.StringVar(&delim, "d", "", "конец строки")
+	fs.StringVar(&opts.array, "a", "", "массив для полей")
+
+	if err := fs.Parse(splitShortFlags(args, "ptnda")); err != nil {
+		return nil, nil, &readUsageError{fmt.Errorf("read: ошибка разбора флагов: %w", err)}
+	}
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+
+	if set["a"] && opts.array == "" {
+		return nil, nil, errors.New("read: '': недопустимый идентификатор")
+	}
+	if set["t"] {
+		seconds, err := strconv.ParseFloat(timeout, 64)
+		if err != nil || seconds < 0 {
+			return nil, nil, fmt.Errorf("read: %s: неверное время ожидания", timeout)
+		}
+		opts.timeout = time.Duration(seconds * float64(time.Second))
+		opts.poll = opts.timeout == 0
+	}
+	if set["n"] {
+		n, err := strconv.Atoi(nchars)
+		if err != nil || n < 0 {
+			return nil, nil, fmt.Errorf("read: %s: неверное число", nchars)
+		}
+		opts.nchars = n
+	}
+	if set["d"] {
+		// Пустой разделитель — нулевой байт
+		opts.delim = 0
+		if delim != "" {
+			opts.delim = delim[0]
+		}
+	}
+	return opts, fs.Args(), nil
+}
+
+// readFailure печатает ошибку read и возвращает код 2 для ошибок в
+// параметрах и 1 для остальных.
+func readFailure(ctx *CommandContext, err error) error {
+	code := 1
+	var usage *readUsageError
+	if errors.As(err, &usage) {
+		code = readUsageCode
+	}
+	if writeErr := warnf(ctx, "%v", err); writeErr != nil {
+		return writeErr
+	}
+	return &customErrors.ExitStatusError{Code: code}
+}
+
+// readLine читает строку до разделителя, не включая его. Без -r обратная
+// косая черта экранирует следующий символ, а в паре с переводом строки
+// продолжает строку. quoted отмечает экранированные байты: они не
+// разделяют поля.
+func readLine(src *byteSource, opts *readOptions) (line []byte, quoted []bool, err error) {
+	escaped := false
+	tail := 0 // оставшиеся байты многобайтового символа UTF-8
+	for chars := 0; opts.nchars < 0 || chars < opts.nchars || tail > 0; {
+		b, err := src.readByte()
+		if err != nil {
+			return line, quoted, err
+		}
+		if tail > 0 && b&0xC0 == 0x80 {
+			tail--
+			line, quoted = append(line, b), append(quoted, quoted[len(quoted)-1])
+			continue
+		}
+		tail = 0
+
+		wasEscaped := escaped
+		switch {
+		case escaped:
+			escaped = false
+			if b == '\n' {
+				continue
+			}
+		case b == opts.delim:
+			return line, quoted, nil
+		case b == '\\' && !opts.raw:
+			escaped = true
+			continue
+		}
+
+		line, quoted = append(line, b), append(quoted, wasEscaped)
+		chars++
+		switch {
+		case b >= 0xF0:
+			tail = 3
+		case b >= 0xE0:
+			tail = 2
+		case b >= 0xC0:
+			tail = 1
+		}
+	}
+	return line, quoted, nil
+}
+
+// assignRead присваивает прочитанную строку переменным names, массиву -a
+// или, без них, переменной REPLY.
+func assignRead(ctx *CommandContext, opts *readOptions, names []string, line []byte, quoted []bool) error {
+	s := &fieldSplitter{line: line, quoted: quoted, ifs: defaultIFS}
+	if ifs, ok := ctx.Vars.Get("IFS"); ok {
+		s.ifs = ifs
+	}
+
+	if opts.array != "" {
+		var fields []string
+		end := s.trimEnd()
+		for pos := s.skipSpace(0); pos < end; {
+			var field string
+			field, pos = s.field(pos, end)
+			fields = append(fields, field)
+		}
+		return ctx.Vars.SetArray(opts.array, session.NewIndexedArray(fields...))
+	}
+	if len(names) == 0 {
+		return ctx.Vars.Set("REPLY", string(line))
+	}
+
+	end := s.trimEnd()
+	pos := s.skipSpace(0)
+	for i, name := range names {
+		if i < len(names)-1 {
+			var field string
+			field, pos = s.field(pos, end)
+			if err := ctx.Vars.Set(name, field); err != nil {
+				return err
+			}
+			continue
+		}
+
+		// Последней переменной достаётся остаток строки; если в нём одно
+		// поле и разделитель после него, — только это поле
+		value := string(line[min(pos, end):end])
+		if field, next := s.field(pos, end); next == end && pos < end {
+			value = field
+		}
+		if err := ctx.Vars.Set(name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fieldSplitter делит строку на поля по символам IFS, как оболочка:
+// пробельные символы IFS по краям отбрасываются, а поля разделяет
+// последовательность пробельных символов IFS и не более одного
+// остального символа IFS.
+type fieldSplitter struct {
+	line   []byte
+	quoted []bool
+	ifs    string
+}
+
+func (s *fieldSplitter) isIFS(i int) bool {
+	return !s.quoted[i] && strings.IndexByte(s.ifs, s.line[i]) >= 0
+}
+
+func (s *fieldSplitter) isSpace(i int) bool {
+	return s.isIFS(i) && strings.IndexByte(defaultIFS, s.line[i]) >= 0
+}
+
+// skipSpace пропускает пробельные символы IFS, начиная с pos.
+func (s *fieldSplitter) skipSpace(pos int) int {
+	for pos < len(s.line) && s.isSpace(pos) {
+		pos++
+	}
+	return pos
+}
+
+// trimEnd возвращает конец строки без завершающих пробельных символов IFS.
+func (s *fieldSplitter) trimEnd() int {
+	end := len(s.line)
+	for end > 0 && s.isSpace(end-1) {
+		end--
+	}
+	return end
+}
+
+// field возвращает поле, начинающееся с pos, и начало следующего поля.
+func (s *fieldSplitter) field(pos, end int) (string, int) {
+	start := pos
+	for pos < end && !s.isIFS(pos) {
+		pos++
+	}
+	field := string(s.line[start:pos])
+
+	pos = s.skipSpace(pos)
+	if pos < end && s.isIFS(pos) {
+		pos = s.skipSpace(pos + 1)
+	}
+	return field, min(pos, end)
+}
+
+// Help возвращает справку по команде read.
+func (r *ReadCommand) Help() string {
+	return `NAME
+    read - читает строку из стандартного ввода в переменные
+
+SYNOPSIS
+    read [-rs] [-a ARRAY] [-d DELIM] [-n N] [-p PROMPT] [-t TIMEOUT] [NAME...]
+
+DESCRIPTION
+    Читает строку и делит её на поля по символам переменной IFS (по
+    умолчанию пробел, табуляция и перевод строки). Первое поле
+    присваивается первой переменной NAME, второе — второй и так далее;
+    последней достаётся остаток строки. Переменным, для которых не
+    хватило полей, присваивается пустая строка. Без NAME строка целиком
+    записывается в REPLY.
+
+    Пробельные символы IFS в начале и в конце строки отбрасываются;
+    остальные символы IFS разделяют поля по одному, поэтому "a,,b" при
+    IFS=, даёт три поля, второе из них пустое.
+
+    Без -r обратная косая черта экранирует следующий символ, а в конце
+    строки продолжает её на следующей. Ввод читается по байту: всё, что
+    идёт за строкой, остаётся в потоке для следующих команд.
+
+OPTIONS
+    -r              не обрабатывать обратную косую черту
+    -a ARRAY        записать поля в массив ARRAY, начиная с индекса 0
+    -d DELIM        читать до первого символа DELIM вместо перевода
+                    строки; пустой DELIM — нулевой байт
+    -n N            прочитать не больше N символов
+    -p PROMPT       напечатать приглашение в stderr, если ввод — терминал
+    -s              не отображать вводимые символы на терминале
+    -t TIMEOUT      ждать ввода не дольше TIMEOUT секунд (допускаются
+                    дробные); прочитанное до этого всё равно присваивается.
+                    -t 0 ничего не читает и лишь проверяет, есть ли ввод
+
+EXIT STATUS
+    0 — строка прочитана, 1 — конец ввода или ошибка, 2 — неверные
+    параметры, 142 — истекло время ожидания, 130 — чтение прервано.
+
+EXAMPLES
+    read -r first rest <<< "один два три"
+        → first="один", rest="два три"
+
+    IFS=: read -ra dirs <<< "$PATH"
+        → каталоги PATH в массиве dirs
+
+    while read -r line; do echo "> $line"; done < file.txt
+        → строки файла по одной
+
+    read -s -p "Пароль: " password
+        → пароль без отображения на экране`
+}
+
+var _ BuiltinCommand = (*ReadCommand)(nil)
@@ -0,0 +1,156 @@
+package commands
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+)
+
+// readPollInterval — сколько read -t 0 ждёт появления ввода.
+const readPollInterval = 10 * time.Millisecond
+
+var (
+	// errReadTimeout — истекло время ожидания read -t.
+	errReadTimeout = errors.New("время ожидания истекло")
+	// errReadInterrupted — команда прервана во время чтения.
+	errReadInterrupted = errors.New("чтение прервано")
+)
+
+// readResult — результат чтения одного байта.
+type readResult struct {
+	b   byte
+	err error
+}
+
+// pendingReads хранит чтения, которые продолжаются после истечения
+// времени ожидания или прерывания: их байт достанется следующей команде,
+// читающей тот же поток, и не потеряется.
+var pendingReads = struct {
+	sync.Mutex
+	m map[io.Reader]chan readResult
+}{m: map[io.Reader]chan readResult{}}
+
+// byteSource читает ввод по одному байту, не забирая из потока ничего
+// сверх прочитанного: stdin сеанса разделяют интерпретатор и команды,
+// и после read оставшиеся строки сценария должны остаться в потоке.
+type byteSource struct {
+	r       io.Reader
+	done    <-chan struct{}  // отмена команды
+	timeout <-chan time.Time // время ожидания -t; nil — без ограничения
+	// async — чтение в отдельной горутине, чтобы его можно было прервать
+	// по done или timeout. Иначе чтение блокирует до получения байта.
+	async bool
+}
+
+// newByteSource создаёт источник байтов для стандартного ввода ctx.
+// Чтение прерываемо, если задано время ожидания или ввод — терминал:
+// данные из файла и канала приходят без ожидания пользователя.
+func newByteSource(ctx *CommandContext, timeout time.Duration) *byteSource {
+	s := &byteSource{r: ctx.Stdin, done: ctx.Done()}
+	if timeout > 0 {
+		s.timeout = time.After(timeout)
+	}
+	s.async = s.timeout != nil || s.done != nil && isTerminal(ctx.Stdin)
+	return s
+}
+
+// readByte читает один байт.
+func (s *byteSource) readByte() (byte, error) {
+	if ch := takePendingRead(s.r); ch != nil {
+		return s.wait(ch)
+	}
+	if !s.async {
+		var buf [1]byte
+		_, err := io.ReadFull(s.r, buf[:])
+		return buf[0], err
+	}
+	return s.wait(s.start())
+}
+
+// ready сообщает, есть ли во вводе данные или конец ввода (read -t 0).
+// Прочитанный при проверке байт остаётся для следующего чтения.
+func (s *byteSource) ready() bool {
+	ch := takePendingRead(s.r)
+	if ch == nil {
+		ch = s.start()
+	}
+	select {
+	case res := <-ch:
+		ch <- res
+		putPendingRead(s.r, ch)
+		return true
+	case <-time.After(readPollInterval):
+		putPendingRead(s.r, ch)
+		return false
+	}
+}
+
+// start начинает чтение байта в отдельной горутине.
+func (s *byteSource) start() chan readResult {
+	ch := make(chan readResult, 1)
+	go func() {
+		var buf [1]byte
+		_, err := io.ReadFull(s.r, buf[:])
+		ch <- readResult{b: buf[0], err: err}
+	}()
+	return ch
+}
+
+// wait ждёт результата чтения ch. Если чтение прервано, оно
+// откладывается до следующего вызова.
+func (s *byteSource) wait(ch chan readResult) (byte, error) {
+	select {
+	case res := <-ch:
+		return res.b, res.err
+	case <-s.timeout:
+		putPendingRead(s.r, ch)
+		return 0, errReadTimeout
+	case <-s.done:
+		putPendingRead(s.r, ch)
+		return 0, errReadInterrupted
+	}
+}
+
+// takePendingRead забирает отложенное чтение потока r.
+func takePendingRead(r io.Reader) chan readResult {
+	if !comparableReader(r) {
+		return nil
+	}
+	pendingReads.Lock()
+	defer pendingReads.Unlock()
+	ch := pendingReads.m[r]
+	delete(pendingReads.m, r)
+	return ch
+}
+
+// putPendingRead откладывает чтение ch потока r. Для потоков, которые
+// нельзя использовать ключом, байт отложенного чтения теряется.
+func putPendingRead(r io.Reader, ch chan readResult) {
+	if !comparableReader(r) {
+		return
+	}
+	pendingReads.Lock()
+	defer pendingReads.Unlock()
+	pendingReads.m[r] = ch
+}
+
+func comparableReader(r io.Reader) bool {
+	return r != nil && reflect.TypeOf(r).Comparable()
+}
+
+// ReadLine читает из r строку без завершающего перевода строки (и
+// возврата каретки перед ним, как bufio.ScanLines). Ввод
+// читается по байту, так что всё после строки остаётся в r для команд,
+// которые читают тот же поток. io.EOF возвращается, только если ввод
+// закончился до первого байта строки.
+func ReadLine(r io.Reader) (string, error) {
+	src := &byteSource{r: r}
+	line, err := readRecord(src, '\n')
+	if err != nil && (len(line) == 0 || !errors.Is(err, io.EOF)) {
+		return "", err
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(string(line), "\n"), "\r"), nil
+}
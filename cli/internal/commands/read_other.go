@@ -0,0 +1,9 @@
+//go:build !unix
+
+package commands
+
+// setEcho на этой платформе не поддерживается: read -s читает ввод,
+// не скрывая его.
+func setEcho(any, bool) error {
+	return nil
+}
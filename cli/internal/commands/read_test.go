@@ -0,0 +1,196 @@
+package commands
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
+)
+
+// runRead выполняет команду со стандартным вводом stdin и переменными env
+// и возвращает контекст, чтобы проверить присвоенные переменные.
+func runRead(t *testing.T, cmd CommandExecutor, env map[string]string, stdin io.Reader, args ...string) (*CommandContext, string, int) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	ctx := &CommandContext{
+		Stdin:  stdin,
+		Stdout: &stdout,
+		Stderr: &stderr,
+		Vars:   session.NewVariables(env),
+	}
+	err := cmd.Exec(args, ctx)
+	return ctx, stderr.String(), testStatus(err)
+}
+
+func TestReadCommand_Split(t *testing.T) {
+	tests := []struct {
+		ifs      string // "-" — IFS не задана
+		input    string
+		args     []string
+		expected map[string]string
+	}{
+		{"-", "  one two   three  \n", []string{"a", "b"}, map[string]string{"a": "one", "b": "two   three"}},
+		{"-", "  one two   three  \n", []string{"a", "b", "c", "d"}, map[string]string{"a": "one", "b": "two", "c": "three", "d": ""}},
+		{"-", "one\\ two three\n", []string{"a", "b"}, map[string]string{"a": "one two", "b": "three"}},
+		{"-", "a\\\nb c\n", []string{"x", "y"}, map[string]string{"x": "ab", "y": "c"}},
+		{"-", "a\\\nb c\n", []string{"-r", "x", "y"}, map[string]string{"x": "a\\", "y": ""}},
+		{",", "1,2,\n", []string{"a", "b"}, map[string]string{"a": "1", "b": "2"}},
+		{",", "1,2,,\n", []string{"a", "b"}, map[string]string{"a": "1", "b": "2,,"}},
+		{",", ",x\n", []string{"a", "b"}, map[string]string{"a": "", "b": "x"}},
+		{":", " a : b :c\n", []string{"a", "b", "c"}, map[string]string{"a": " a ", "b": " b ", "c": "c"}},
+		{", ", "a , b,,c\n", []string{"a", "b", "c"}, map[string]string{"a": "a", "b": "b", "c": ",c"}},
+		{"", "  a b  \n", []string{"x", "y"}, map[string]string{"x": "  a b  ", "y": ""}},
+		{"-", "  x  y \n", nil, map[string]string{"REPLY": "  x  y "}},
+		{"-", "привет мир\n", []string{"-n", "4", "x"}, map[string]string{"x": "прив"}},
+		{"-", "ab\\cd\n", []string{"-n", "3", "x"}, map[string]string{"x": "abc"}},
+		{"-", "ab\ncd", []string{"-n", "5", "x"}, map[string]string{"x": "ab"}},
+		{"-", "k=v;rest", []string{"-d", ";", "x"}, map[string]string{"x": "k=v"}},
+		{"-", "one\x00two", []string{"-d", "", "x"}, map[string]string{"x": "one"}},
+		{"-", "line\n", []string{"-n", "0", "x"}, map[string]string{"x": ""}},
+	}
+	for _, tt := range tests {
+		env := map[string]string{}
+		if tt.ifs != "-" {
+			env["IFS"] = tt.ifs
+		}
+		ctx, stderr, status := runRead(t, &ReadCommand{}, env, strings.NewReader(tt.input), tt.args...)
+		if status != 0 || stderr != "" {
+			t.Errorf("%q %v: ожидался код 0, получено %d: %q", tt.input, tt.args, status, stderr)
+		}
+		for name, value := range tt.expected {
+			if got := variableValue(ctx, name); got != value {
+				t.Errorf("%q %v: %s: ожидалось %q, получено %q", tt.input, tt.args, name, value, got)
+			}
+		}
+	}
+}
+
+func TestReadCommand_Array(t *testing.T) {
+	ctx, _, status := runRead(t, &ReadCommand{}, map[string]string{"IFS": ",", "arr": "old"}, strings.NewReader("a,,b,\n"), "-ra", "arr")
+	if _, ok := ctx.Vars.Array("arr"); !ok {
+		t.Fatalf("переменная arr должна быть заменена массивом")
+	}
+	expected := []string{"a", "", "b"}
+	if got := testArray(ctx, "arr").Values(); status != 0 || !reflect.DeepEqual(got, expected) {
+		t.Errorf("ожидалось %q, получено %q (код %d)", expected, got, status)
+	}
+
+	ctx, _, _ = runRead(t, &ReadCommand{}, nil, strings.NewReader("\n"), "-a", "empty")
+	if values, ok := ctx.Vars.Array("empty"); !ok || values.Len() != 0 {
+		t.Errorf("ожидался пустой массив, получено %v", values)
+	}
+}
+
+func TestReadCommand_Status(t *testing.T) {
+	// Конец ввода: прочитанная часть присваивается, код 1
+	ctx, _, status := runRead(t, &ReadCommand{}, nil, strings.NewReader("partial"), "x")
+	if status != 1 || variableValue(ctx, "x") != "partial" {
+		t.Errorf("ожидался код 1 и x=partial, получено %d, %q", status, variableValue(ctx, "x"))
+	}
+
+	tests := []struct {
+		args    []string
+		status  int
+		message string
+	}{
+		{[]string{"1x"}, 1, "read: '1x': недопустимый идентификатор"},
+		{[]string{"-a", "a-b"}, 1, "read: 'a-b': недопустимый идентификатор"},
+		{[]string{"-t", "abc", "x"}, 1, "read: abc: неверное время ожидания"},
+		{[]string{"-n", "-1", "x"}, 1, "read: -1: неверное число"},
+		{[]string{"-d"}, 2, "read: ошибка разбора флагов"},
+		{[]string{"-q", "x"}, 2, "read: ошибка разбора флагов"},
+	}
+	for _, tt := range tests {
+		ctx, stderr, status := runRead(t, &ReadCommand{}, nil, strings.NewReader("line\n"), tt.args...)
+		if status != tt.status || !strings.Contains(stderr, tt.message) {
+			t.Errorf("%v: ожидался код %d и %q, получено %d: %q", tt.args, tt.status, tt.message, status, stderr)
+		}
+		if len(ctx.Vars.Names()) != 0 {
+			t.Errorf("%v: переменные не должны изменяться, получено %v", tt.args, ctx.Vars.Names())
+		}
+	}
+}
+
+func TestReadCommand_Timeout(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = r.Close()
+	}()
+	if _, err := w.WriteString("ab"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Истекло время ожидания: прочитанная часть присваивается, код 142
+	ctx, _, status := runRead(t, &ReadCommand{}, nil, r, "-t", "0.05", "x")
+	if status != readTimeoutCode || variableValue(ctx, "x") != "ab" {
+		t.Errorf("ожидался код %d и x=ab, получено %d, %q", readTimeoutCode, status, variableValue(ctx, "x"))
+	}
+
+	_, _, status = runRead(t, &ReadCommand{}, nil, r, "-t", "0")
+	if status != 1 {
+		t.Errorf("read -t 0 без ввода: ожидался код 1, получено %d", status)
+	}
+
+	// Байт, пришедший после истечения времени, достаётся следующему read
+	if _, err := w.WriteString("cd\nrest\n"); err != nil {
+		t.Fatal(err)
+	}
+	_ = w.Close()
+	start := time.Now()
+	_, _, status = runRead(t, &ReadCommand{}, nil, r, "-t", "0")
+	if status != 0 || time.Since(start) > time.Second {
+		t.Errorf("read -t 0 с вводом: ожидался код 0, получено %d", status)
+	}
+	ctx, _, status = runRead(t, &ReadCommand{}, nil, r, "x")
+	if status != 0 || variableValue(ctx, "x") != "cd" {
+		t.Errorf("ожидалось x=cd, получено %q (код %d)", variableValue(ctx, "x"), status)
+	}
+	ctx, _, status = runRead(t, &ReadCommand{}, nil, r, "x")
+	if status != 0 || variableValue(ctx, "x") != "rest" {
+		t.Errorf("ожидалось x=rest, получено %q (код %d)", variableValue(ctx, "x"), status)
+	}
+}
+
+func TestReadCommand_LeavesRestOfInput(t *testing.T) {
+	input := strings.NewReader("first line\nsecond line\nthird")
+	ctx, _, _ := runRead(t, &ReadCommand{}, nil, input, "-r", "x")
+	if variableValue(ctx, "x") != "first line" {
+		t.Errorf("ожидалось %q, получено %q", "first line", variableValue(ctx, "x"))
+	}
+
+	line, err := ReadLine(input)
+	if err != nil || line != "second line" {
+		t.Errorf("ожидалось %q, получено %q (%v)", "second line", line, err)
+	}
+	rest, _ := io.ReadAll(input)
+	if string(rest) != "third" {
+		t.Errorf("ожидалось %q, получено %q", "third", rest)
+	}
+}
+
+func TestReadLine(t *testing.T) {
+	input := strings.NewReader("a\r\nb\n\nlast")
+	var lines []string
+	for {
+		line, err := ReadLine(input)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, line)
+	}
+	expected := []string{"a", "b", "", "last"}
+	if !reflect.DeepEqual(lines, expected) {
+		t.Errorf("ожидалось %q, получено %q", expected, lines)
+	}
+}
@@ -0,0 +1,24 @@
+//go:build unix
+
+package commands
+
+import (
+	"os"
+	"os/exec"
+)
+
+// setEcho включает или выключает отображение вводимых символов на
+// терминале stdin (read -s).
+func setEcho(stdin any, on bool) error {
+	file, ok := stdin.(*os.File)
+	if !ok {
+		return nil
+	}
+	mode := "-echo"
+	if on {
+		mode = "echo"
+	}
+	stty := exec.Command("stty", mode)
+	stty.Stdin = file
+	return stty.Run()
+}
@@ -0,0 +1,226 @@
+package commands
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	customErrors "github.com/ggerlakh/software-design-mhs-itmo/cli/internal/errors"
+)
+
+// RmCommand реализует встроенную команду "rm".
+// Она удаляет файлы и каталоги.
+type RmCommand struct{}
+
+// rmOptions — параметры rm.
+type rmOptions struct {
+	force     bool // -f: не сообщать об отсутствующих файлах
+	recursive bool // -r, -R: удалять каталоги рекурсивно
+	emptyDirs bool // -d: удалять пустые каталоги
+	verbose   bool // -v: сообщать о каждом удалённом файле
+}
+
+// Name возвращает имя команды.
+func (r *RmCommand) Name() string {
+	return "rm"
+}
+
+// Exec выполняет команду rm.
+//
+// Синтаксис:
+//
+//	rm [-f] [-r] [-d] [-v] FILE...
+//
+// Корневой каталог рекурсивно не удаляется никогда, как и "." и "..".
+// Ошибка с одним файлом не мешает удалить остальные.
+//
+// Примеры:
+//
+//	rm old.log                    → удалить файл
+//	rm -rf build                  → удалить каталог со всем содержимым
+//	rm -f *.tmp                   → без ошибок, если файлов нет
+func (r *RmCommand) Exec(args []string, ctx *CommandContext) error {
+	opts, files, err := parseRmArgs(args)
+	if err != nil {
+		if writeErr := warnf(ctx, "%v\nПопробуйте 'rm --help' для получения справки.", err); writeErr != nil {
+			return writeErr
+		}
+		return &customErrors.ExitStatusError{Code: 1}
+	}
+
+	failed := false
+	for _, name := range files {
+		ok, err := opts.remove(ctx, name)
+		if err != nil {
+			return err
+		}
+		failed = failed || !ok
+	}
+	if failed {
+		return &customErrors.ExitStatusError{Code: 1}
+	}
+	return nil
+}
+
+// parseRmArgs разбирает аргументы rm.
+func parseRmArgs(args []string) (*rmOptions, []string, error) {
+	fs := flag.NewFlagSet("rm", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	opts := &rmOptions{}
+	for _, names := range []struct {
+		names []string
+		value *bool
+		usage string
+	}{
+		{[]string{"f", "force"}, &opts.force, "не сообщать об отсутствующих файлах"},
+		{[]string{"r", "R", "recursive"}, &opts.recursive, "удалять каталоги рекурсивно"},
+		{[]string{"d", "dir"}, &opts.emptyDirs, "удалять пустые каталоги"},
+		{[]string{"v", "verbose"}, &opts.verbose, "сообщать об удалённых файлах"},
+	} {
+		for _, name := range names.names {
+			fs.BoolVar(names.value, name, false, names.usage)
+		}
+	}
+
+	if err := parseOptions(fs, splitShortFlags(args, "")); err != nil {
+		return nil, nil, err
+	}
+	if fs.NArg() == 0 && !opts.force {
+		return nil, nil, errors.New("rm: пропущен операнд")
+	}
+	return opts, fs.Args(), nil
+}
+
+// remove удаляет файл name. Возвращает false, если удалить не удалось
+// (сообщение уже напечатано); ошибка — только ошибка записи сообщения.
+func (o *rmOptions) remove(ctx *CommandContext, name string) (bool, error) {
+	path := ctx.ResolvePath(name)
+	if base := filepath.Base(filepath.Clean(name)); base == "." || base == ".." {
+		return false, warnf(ctx, "rm: отказ в удалении каталога '.' или '..': пропускается '%s'", name)
+	}
+	if o.recursive && isRootPath(path, strings.HasSuffix(name, "/")) {
+		return false, warnf(ctx, "rm: опасно рекурсивно удалять '%s'\nrm: корневой каталог не удаляется", name)
+	}
+
+	info, err := os.Lstat(path)
+	switch {
+	case err != nil && o.force && errors.Is(err, fs.ErrNotExist):
+		return true, nil
+	case err != nil:
+		return false, warnf(ctx, "rm: невозможно удалить '%s': %v", name, fileError(err))
+	case info.IsDir() && o.recursive:
+		return o.removeTree(ctx, path, name)
+	case info.IsDir() && !o.emptyDirs:
+		return false, warnf(ctx, "rm: невозможно удалить '%s': это каталог", name)
+	}
+	return o.removeOne(ctx, path, name, info.IsDir())
+}
+
+// removeTree рекурсивно удаляет каталог: сначала содержимое, затем сам
+// каталог. Ошибки с отдельными файлами печатаются, обход продолжается.
+func (o *rmOptions) removeTree(ctx *CommandContext, path, name string) (bool, error) {
+	children, err := os.ReadDir(path)
+	if err != nil {
+		return false, warnf(ctx, "rm: невозможно удалить '%s': %v", name, fileError(err))
+	}
+
+	ok := true
+	for _, child := range children {
+		childPath := filepath.Join(path, child.Name())
+		childName := strings.TrimSuffix(name, "/") + "/" + child.Name()
+		var removed bool
+		if child.IsDir() {
+			removed, err = o.removeTree(ctx, childPath, childName)
+		} else {
+			removed, err = o.removeOne(ctx, childPath, childName, false)
+		}
+		if err != nil {
+			return false, err
+		}
+		ok = ok && removed
+	}
+	if !ok {
+		// Каталог не пуст: сообщать об ошибке его удаления незачем
+		return false, nil
+	}
+	return o.removeOne(ctx, path, name, true)
+}
+
+// removeOne удаляет файл или пустой каталог и с -v сообщает об этом.
+func (o *rmOptions) removeOne(ctx *CommandContext, path, name string, dir bool) (bool, error) {
+	if err := os.Remove(path); err != nil {
+		if o.force && errors.Is(err, fs.ErrNotExist) {
+			return true, nil
+		}
+		return false, warnf(ctx, "rm: невозможно удалить '%s': %v", name, fileError(err))
+	}
+	if !o.verbose {
+		return true, nil
+	}
+	kind := ""
+	if dir {
+		kind = "каталог "
+	}
+	_, err := fmt.Fprintf(ctx.Stdout, "удалён %s'%s'\n", kind, name)
+	return err == nil, err
+}
+
+// isRootPath сообщает, что path — корневой каталог файловой системы.
+// С followLink (путь указан с "/" в конце) символьные ссылки раскрываются:
+// "link/" для ссылки на "/" — тоже корень.
+func isRootPath(path string, followLink bool) bool {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return false
+	}
+	if filepath.Dir(abs) == abs {
+		return true
+	}
+	if !followLink {
+		return false
+	}
+	resolved, err := filepath.EvalSymlinks(abs)
+	return err == nil && filepath.Dir(resolved) == resolved
+}
+
+// Help возвращает справку по команде rm.
+func (r *RmCommand) Help() string {
+	return `NAME
+    rm - удаляет файлы и каталоги
+
+SYNOPSIS
+    rm [OPTION]... FILE...
+
+DESCRIPTION
+    Удаляет файлы FILE. Каталоги удаляются только с -r (вместе с
+    содержимым) или -d (если пусты). Символьные ссылки удаляются сами,
+    без файлов, на которые они указывают.
+
+    Для защиты от ошибок rm никогда не удаляет рекурсивно корневой
+    каталог ("/", а на Windows — корень диска), а также "." и "..".
+
+OPTIONS
+    -f, --force         не сообщать об отсутствующих файлах и не считать
+                        их ошибкой; без FILE не сообщать об ошибке
+    -r, -R, --recursive удалять каталоги вместе с содержимым
+    -d, --dir           удалять пустые каталоги
+    -v, --verbose       сообщать о каждом удалённом файле
+
+EXIT STATUS
+    0 — успех, 1 — хотя бы один файл удалить не удалось.
+
+EXAMPLES
+    rm -rf build dist
+        → удалить каталоги сборки
+
+    rm -v *.tmp
+        → удалить временные файлы с отчётом`
+}
+
+var _ BuiltinCommand = (*RmCommand)(nil)
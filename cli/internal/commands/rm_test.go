@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRmCommand(t *testing.T) {
+	dir := t.TempDir()
+	makeTree(t, dir, map[string]string{"a.txt": "", "b.txt": "", "empty/": "", "tree/x/y.txt": "", "tree/z.txt": ""})
+
+	_, stderr, status := runCommand(t, &RmCommand{}, dir, "", "a.txt", "missing", "b.txt")
+	if status != 1 || !strings.Contains(stderr, "rm: невозможно удалить 'missing'") {
+		t.Errorf("ожидалась ошибка для отсутствующего файла, получено %d: %q", status, stderr)
+	}
+	for _, name := range []string{"a.txt", "b.txt"} {
+		if _, err := os.Lstat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+			t.Errorf("файл %s должен быть удалён", name)
+		}
+	}
+
+	_, stderr, status = runCommand(t, &RmCommand{}, dir, "", "tree")
+	if status != 1 || !strings.Contains(stderr, "это каталог") {
+		t.Errorf("без -r каталог не удаляется, получено %d: %q", status, stderr)
+	}
+	_, stderr, status = runCommand(t, &RmCommand{}, dir, "", "-d", "empty")
+	if status != 0 || stderr != "" {
+		t.Errorf("-d удаляет пустой каталог, получено %d: %q", status, stderr)
+	}
+
+	out, stderr, status := runCommand(t, &RmCommand{}, dir, "", "-rv", "tree")
+	if status != 0 || stderr != "" {
+		t.Fatalf("ожидался код 0 без ошибок, получено %d: %q", status, stderr)
+	}
+	expected := "удалён 'tree/x/y.txt'\nудалён каталог 'tree/x'\nудалён 'tree/z.txt'\nудалён каталог 'tree'\n"
+	if out != expected {
+		t.Errorf("ожидалось %q, получено %q", expected, out)
+	}
+
+	_, stderr, status = runCommand(t, &RmCommand{}, dir, "", "-f", "missing")
+	if status != 0 || stderr != "" {
+		t.Errorf("-f не сообщает об отсутствующих файлах, получено %d: %q", status, stderr)
+	}
+	_, _, status = runCommand(t, &RmCommand{}, dir, "", "-f")
+	if status != 0 {
+		t.Errorf("rm -f без операндов — не ошибка, получено %d", status)
+	}
+	_, stderr, status = runCommand(t, &RmCommand{}, dir, "")
+	if status != 1 || !strings.Contains(stderr, "пропущен операнд") {
+		t.Errorf("ожидалась ошибка без операндов, получено %d: %q", status, stderr)
+	}
+}
+
+func TestRmCommand_Symlink(t *testing.T) {
+	dir := t.TempDir()
+	makeTree(t, dir, map[string]string{"target/keep.txt": ""})
+	if err := os.Symlink("target", filepath.Join(dir, "link")); err != nil {
+		t.Skip("символьные ссылки недоступны:", err)
+	}
+
+	_, stderr, status := runCommand(t, &RmCommand{}, dir, "", "-rf", "link")
+	if status != 0 || stderr != "" {
+		t.Fatalf("ожидался код 0, получено %d: %q", status, stderr)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "target", "keep.txt")); err != nil {
+		t.Errorf("удаление ссылки не должно затрагивать каталог, на который она указывает: %v", err)
+	}
+}
+
+func TestRmCommand_Guards(t *testing.T) {
+	dir := t.TempDir()
+	makeTree(t, dir, map[string]string{"sub/keep.txt": ""})
+
+	root := string(filepath.Separator)
+	if volume := filepath.VolumeName(dir); volume != "" {
+		root = volume + root
+	}
+	tests := [][]string{
+		{"-rf", root},
+		{"-rf", root + "."},
+		{"-rf", dir + "/../../../../../../../../.."},
+		{"-rf", "."},
+		{"-rf", "sub/.."},
+		{"-r", ".."},
+	}
+	for _, args := range tests {
+		_, stderr, status := runCommand(t, &RmCommand{}, filepath.Join(dir, "sub"), "", args...)
+		if status != 1 || stderr == "" {
+			t.Errorf("%v: ожидался отказ, получено %d: %q", args, status, stderr)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(dir, "sub", "keep.txt")); err != nil {
+		t.Errorf("файлы не должны быть удалены: %v", err)
+	}
+}
@@ -0,0 +1,459 @@
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	customErrors "github.com/ggerlakh/software-design-mhs-itmo/cli/internal/errors"
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/linereader"
+)
+
+// Коды завершения sed, как в GNU sed.
+const (
+	sedUsageCode = 1 // неверные аргументы или сценарий
+	sedInputCode = 2 // входной файл не удалось прочитать
+	sedIOCode    = 4 // ошибка записи при редактировании на месте
+)
+
+// SedCommand реализует встроенную команду "sed".
+// Она построчно редактирует поток по сценарию.
+type SedCommand struct{}
+
+// sedOptions — параметры sed.
+type sedOptions struct {
+	quiet    bool   // -n: не выводить строки автоматически
+	extended bool   // -E, -r: расширенные регулярные выражения
+	inPlace  bool   // -i: редактировать файлы на месте
+	suffix   string // суффикс резервной копии для -i
+	script   string
+}
+
+// Name возвращает имя команды.
+func (s *SedCommand) Name() string {
+	return "sed"
+}
+
+// Exec выполняет команду sed.
+//
+// Синтаксис:
+//
+//	sed [-n] [-E] [-i[SUFFIX]] SCRIPT [FILE...]
+//	sed [-n] [-E] [-i[SUFFIX]] -e SCRIPT... [FILE...]
+//
+// Поддерживаются команды s/RE/REPLACEMENT/[g][p][i][N], d и p с адресами
+// N, $, /RE/ и диапазонами ADDR1,ADDR2, а также отрицание "!". Строки
+// выводятся по мере обработки; последняя строка без "\n" выводится тоже без
+// него. Если файлы не указаны или указан "-", читается stdin.
+//
+// Примеры:
+//
+//	sed 's/foo/bar/g' file.txt     → замена всех foo на bar
+//	sed -n '/error/p' log.txt      → только строки с error
+//	sed '1,/^$/d' mail.txt         → удаление заголовков до пустой строки
+//	sed -i.bak 's/\r$//' dos.txt   → правка файла с резервной копией
+func (s *SedCommand) Exec(args []string, ctx *CommandContext) error {
+	opts, files, err := parseSedArgs(args)
+	if err != nil {
+		if _, writeErr := fmt.Fprintf(ctx.Stderr, "%v\nПопробуйте 'sed --help' для получения справки.\n", err); writeErr != nil {
+			return writeErr
+		}
+		return &customErrors.ExitStatusError{Code: sedUsageCode}
+	}
+
+	commands, err := parseSedScript(opts.script, opts.extended)
+	if err != nil {
+		return sedFail(ctx, sedUsageCode, "sed: -e выражение, %v", err)
+	}
+	program := &sedProgram{commands: commands, quiet: opts.quiet}
+	for _, cmd := range commands {
+		for _, addr := range []*sedAddress{cmd.start, cmd.end} {
+			if addr != nil && addr.last {
+				program.lookahead = true
+			}
+		}
+	}
+
+	if opts.inPlace {
+		if len(files) == 0 {
+			return sedFail(ctx, sedUsageCode, "sed: нет входных файлов")
+		}
+		status := 0
+		for _, name := range files {
+			code, err := program.editInPlace(ctx, name, opts.suffix)
+			if err != nil {
+				return err
+			}
+			status = max(status, code)
+		}
+		if status != 0 {
+			return &customErrors.ExitStatusError{Code: status}
+		}
+		return nil
+	}
+
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	in := &sedInput{ctx: ctx, names: files}
+	if err := program.run(in, &sedOutput{w: ctx.Stdout}); err != nil {
+		return err
+	}
+	if in.failed {
+		return &customErrors.ExitStatusError{Code: sedInputCode}
+	}
+	return nil
+}
+
+// parseSedArgs разбирает аргументы sed. Опции можно указывать и после
+// файлов; "-i" и "-e" принимают значение в том же аргументе.
+func parseSedArgs(args []string) (*sedOptions, []string, error) {
+	opts := &sedOptions{}
+	var scripts, operands []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			operands = append(operands, args[i+1:]...)
+			i = len(args)
+		case !strings.HasPrefix(arg, "-") || arg == "-":
+			operands = append(operands, arg)
+		case strings.HasPrefix(arg, "--"):
+			name, value, hasValue := strings.Cut(arg[2:], "=")
+			switch {
+			case name == "quiet" || name == "silent":
+				opts.quiet = true
+			case name == "regexp-extended":
+				opts.extended = true
+			case name == "in-place":
+				opts.inPlace, opts.suffix = true, value
+			case name == "expression" && hasValue:
+				scripts = append(scripts, value)
+			case name == "expression":
+				if i++; i >= len(args) {
+					return nil, nil, errors.New("sed: опция '--expression' требует аргумент")
+				}
+				scripts = append(scripts, args[i])
+			default:
+				return nil, nil, fmt.Errorf("sed: нераспознанная опция '%s'", arg)
+			}
+		default:
+		short:
+			for j := 1; j < len(arg); j++ {
+				switch arg[j] {
+				case 'n':
+					opts.quiet = true
+				case 'E', 'r':
+					opts.extended = true
+				case 'i':
+					opts.inPlace, opts.suffix = true, arg[j+1:]
+					break short
+				case 'e':
+					script := arg[j+1:]
+					if script == "" {
+						if i++; i >= len(args) {
+							return nil, nil, errors.New("sed: опция требует аргумент -- 'e'")
+						}
+						script = args[i]
+					}
+					scripts = append(scripts, script)
+					break short
+				default:
+					return nil, nil, fmt.Errorf("sed: неверная опция -- '%c'", arg[j])
+				}
+			}
+		}
+	}
+
+	if len(scripts) == 0 {
+		if len(operands) == 0 {
+			return nil, nil, errors.New("sed: не указан сценарий")
+		}
+		scripts, operands = operands[:1], operands[1:]
+	}
+	opts.script = strings.Join(scripts, "\n")
+	return opts, operands, nil
+}
+
+// sedFail печатает сообщение об ошибке и возвращает код code.
+func sedFail(ctx *CommandContext, code int, format string, args ...any) error {
+	if err := warnf(ctx, format, args...); err != nil {
+		return err
+	}
+	return &customErrors.ExitStatusError{Code: code}
+}
+
+// sedProgram — разобранный сценарий sed.
+type sedProgram struct {
+	commands []*sedCommand
+	quiet    bool
+	// lookahead — сценарий использует адрес "$", и для распознавания
+	// последней строки нужно читать на строку вперёд. Без "$" строки
+	// обрабатываются сразу, поэтому sed можно использовать после tail -f.
+	lookahead bool
+}
+
+// run обрабатывает строки in и выводит результат в out. Номера строк
+// и диапазоны отсчитываются заново при каждом вызове.
+func (p *sedProgram) run(in *sedInput, out *sedOutput) error {
+	for _, cmd := range p.commands {
+		cmd.active = false
+	}
+
+	line, err := in.read()
+	for number := 1; err == nil; number++ {
+		var next []byte
+		var nextErr error
+		if p.lookahead {
+			next, nextErr = in.read()
+		}
+
+		text := linereader.TrimNewline(line)
+		last := errors.Is(nextErr, io.EOF)
+		if err := p.cycle(out, number, string(text), len(text) < len(line), last); err != nil {
+			return err
+		}
+
+		if p.lookahead {
+			line, err = next, nextErr
+		} else {
+			line, err = in.read()
+		}
+	}
+	if errors.Is(err, io.EOF) {
+		return nil
+	}
+	return err
+}
+
+// cycle выполняет сценарий для одной строки. newline сообщает, что
+// строка заканчивалась переводом строки.
+func (p *sedProgram) cycle(out *sedOutput, number int, text string, newline, last bool) error {
+	for _, cmd := range p.commands {
+		if !cmd.selected(number, text, last) {
+			continue
+		}
+		switch cmd.name {
+		case 'd':
+			return nil
+		case 'p':
+			if err := out.print(text, newline); err != nil {
+				return err
+			}
+		case 's':
+			replaced, ok := cmd.subst.apply(text)
+			if !ok {
+				continue
+			}
+			text = replaced
+			if cmd.subst.print {
+				if err := out.print(text, newline); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if p.quiet {
+		return nil
+	}
+	return out.print(text, newline)
+}
+
+// editInPlace обрабатывает файл name и заменяет его результатом. Вывод
+// пишется во временный файл в том же каталоге, который затем
+// переименовывается в name; права доступа сохраняются. С непустым suffix
+// исходный файл сохраняется как name+suffix. Возвращается код ошибки
+// (0 — успех); сообщения уже напечатаны.
+func (p *sedProgram) editInPlace(ctx *CommandContext, name, suffix string) (int, error) {
+	path := ctx.ResolvePath(name)
+	info, err := os.Stat(path)
+	if err != nil {
+		return sedInputCode, warnf(ctx, "sed: не удалось прочитать %s: %v", name, fileError(err))
+	}
+	if !info.Mode().IsRegular() {
+		return sedIOCode, warnf(ctx, "sed: не удалось изменить %s: это не обычный файл", name)
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(path), ".sed")
+	if err != nil {
+		return sedIOCode, warnf(ctx, "sed: не удалось создать временный файл для %s: %v", name, fileError(err))
+	}
+	defer func() {
+		// После успешного переименования файла уже нет
+		_ = temp.Close()
+		_ = os.Remove(temp.Name())
+	}()
+
+	in := &sedInput{ctx: ctx, names: []string{name}}
+	writer := bufio.NewWriter(temp)
+	err = p.run(in, &sedOutput{w: writer})
+	if in.failed {
+		return sedInputCode, nil
+	}
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err == nil {
+		err = temp.Chmod(info.Mode().Perm())
+	}
+	if err == nil {
+		err = temp.Close()
+	}
+	if err == nil && suffix != "" {
+		err = os.Rename(path, path+suffix)
+	}
+	if err == nil {
+		err = os.Rename(temp.Name(), path)
+	}
+	if err != nil {
+		return sedIOCode, warnf(ctx, "sed: не удалось записать %s: %v", name, fileError(err))
+	}
+	return 0, nil
+}
+
+// sedInput читает строки подряд из нескольких файлов. Файлы, которые не
+// удалось открыть или прочитать, пропускаются с сообщением об ошибке.
+type sedInput struct {
+	ctx        *CommandContext
+	names      []string
+	name       string
+	lines      *linereader.Reader
+	source     *catReader
+	closeInput func()
+	failed     bool
+}
+
+// read возвращает копию следующей строки вместе с "\n" или io.EOF после
+// последнего файла.
+func (in *sedInput) read() ([]byte, error) {
+	for {
+		if in.lines == nil {
+			if len(in.names) == 0 {
+				return nil, io.EOF
+			}
+			in.name, in.names = in.names[0], in.names[1:]
+			reader, closeInput, err := openInput(in.name, in.ctx)
+			if err != nil {
+				in.failed = true
+				if err := warnf(in.ctx, "sed: не удалось прочитать %s: %v", in.name, fileError(err)); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			in.source = &catReader{r: reader}
+			in.lines = linereader.New(in.source)
+			in.closeInput = closeInput
+		}
+
+		line, err := in.lines.ReadLine()
+		if err == nil {
+			return bytes.Clone(line), nil
+		}
+		in.closeInput()
+		in.lines = nil
+		if in.source.err != nil {
+			in.failed = true
+			if err := warnf(in.ctx, "sed: %s: %v", displayName(in.name), fileError(in.source.err)); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// sedOutput выводит строки sed. Если предыдущая строка была выведена без
+// "\n" (последняя строка ввода без перевода строки), перед следующей
+// он добавляется.
+type sedOutput struct {
+	w          io.Writer
+	incomplete bool
+	buf        []byte
+}
+
+// print выводит строку одной записью.
+func (o *sedOutput) print(text string, newline bool) error {
+	o.buf = o.buf[:0]
+	if o.incomplete {
+		o.buf = append(o.buf, '\n')
+	}
+	o.buf = append(o.buf, text...)
+	if newline {
+		o.buf = append(o.buf, '\n')
+	}
+	o.incomplete = !newline
+	_, err := o.w.Write(o.buf)
+	return err
+}
+
+// Help возвращает справку по команде sed.
+func (s *SedCommand) Help() string {
+	return `NAME
+    sed - потоковый редактор
+
+SYNOPSIS
+    sed [OPTION]... SCRIPT [FILE]...
+    sed [OPTION]... -e SCRIPT... [FILE]...
+
+DESCRIPTION
+    Читает файлы (по умолчанию — стандартный ввод) построчно, выполняет
+    для каждой строки сценарий и выводит результат. Сценарий состоит из
+    команд, разделённых ";" или переводом строки:
+
+      [ADDR1[,ADDR2]][!]COMMAND
+
+    Адреса:
+      N            строка с номером N (нумерация сквозная по всем файлам)
+      $            последняя строка
+      /RE/, \cREc  строки, совпадающие с RE; I после адреса — без учёта
+                   регистра
+      ADDR1,ADDR2  диапазон от строки ADDR1 до строки ADDR2 включительно
+      !            выполнять команду для строк вне адреса
+
+    Команды:
+      s/RE/REPLACEMENT/FLAGS
+                   заменить совпадение с RE. Вместо "/" можно использовать
+                   любой символ. В замене "&" — всё совпадение, \1–\9 —
+                   группы, \n — перевод строки. Флаги: g — все вхождения,
+                   N — N-е вхождение (Ng — с N-го), p — вывести строку при
+                   замене, i или I — без учёта регистра
+      d            удалить строку и перейти к следующей
+      p            вывести строку
+
+    Регулярные выражения — базовые POSIX (BRE), с -E — расширенные (ERE).
+    Пустое выражение // означает предыдущее.
+
+OPTIONS
+    -n, --quiet, --silent    не выводить строки автоматически
+    -e, --expression=SCRIPT  добавить команды сценария
+    -E, -r, --regexp-extended
+                             расширенные регулярные выражения
+    -i[SUFFIX], --in-place[=SUFFIX]
+                             редактировать файлы на месте; с SUFFIX
+                             сохранить исходный файл как FILE+SUFFIX
+
+EXIT STATUS
+    0 — успех, 1 — неверные аргументы или сценарий, 2 — входной файл не
+    удалось прочитать, 4 — ошибка записи при редактировании на месте.
+
+EXAMPLES
+    sed 's/foo/bar/g' file.txt
+        → все foo заменены на bar
+
+    sed -n '/error/p' log.txt
+        → только строки, содержащие error
+
+    sed '2,$d' file.txt
+        → только первая строка
+
+    sed -E 's/([a-z]+)@([a-z.]+)/\2: \1/' emails.txt
+        → поменять местами имя и домен
+
+    sed -i.bak 's/\r$//' dos.txt
+        → убрать CR, сохранив оригинал в dos.txt.bak`
+}
+
+var _ BuiltinCommand = (*SedCommand)(nil)
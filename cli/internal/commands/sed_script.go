@@ -0,0 +1,456 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/posixre"
+)
+
+// sedAddress — адрес строки sed: номер, "$" или регулярное выражение.
+type sedAddress struct {
+	line int            // номер строки (с 1)
+	last bool           // $: последняя строка
+	re   *regexp.Regexp // /re/ или \cREc
+}
+
+// match сообщает, подходит ли строка text с номером number под адрес.
+func (a *sedAddress) match(number int, text string, last bool) bool {
+	switch {
+	case a.re != nil:
+		return a.re.MatchString(text)
+	case a.last:
+		return last
+	}
+	return number == a.line
+}
+
+// sedCommand — команда сценария sed с адресами.
+type sedCommand struct {
+	start, end *sedAddress // адреса; nil — адрес не указан
+	negate     bool        // !: выполнять для строк вне адресов
+	name       byte        // 's', 'd' или 'p'
+	subst      *sedSubst   // параметры команды s
+	active     bool        // строка внутри диапазона start,end
+}
+
+// selected сообщает, выполняется ли команда для строки. Для диапазона
+// запоминается, что он начался: конец ищется со следующей строки, а если
+// конец — номер строки не больше текущего, диапазон состоит из одной строки.
+func (c *sedCommand) selected(number int, text string, last bool) bool {
+	return c.inRange(number, text, last) != c.negate
+}
+
+func (c *sedCommand) inRange(number int, text string, last bool) bool {
+	switch {
+	case c.start == nil:
+		return true
+	case c.end == nil:
+		return c.start.match(number, text, last)
+	case c.active:
+		if c.end.re == nil && !c.end.last {
+			c.active = number < c.end.line
+		} else {
+			c.active = !c.end.match(number, text, last)
+		}
+		return true
+	case !c.start.match(number, text, last):
+		return false
+	}
+
+	switch {
+	case c.end.re != nil:
+		c.active = true
+	case c.end.last:
+		c.active = !last
+	default:
+		c.active = number < c.end.line
+	}
+	return true
+}
+
+// sedSubst — параметры команды s/REGEXP/REPLACEMENT/FLAGS.
+type sedSubst struct {
+	re          *regexp.Regexp
+	replacement []sedPart
+	global      bool // g: заменять все вхождения
+	print       bool // p: выводить строку после замены
+	occurrence  int  // N: начинать с N-го вхождения; 0 — с первого
+}
+
+// sedPart — часть замены: текст или номер группы (0 — всё совпадение, &).
+type sedPart struct {
+	text  string
+	group int // -1 для текста
+}
+
+// apply выполняет замену в строке и сообщает, была ли она.
+func (s *sedSubst) apply(line string) (string, bool) {
+	first := max(s.occurrence, 1)
+	limit := first
+	if s.global {
+		limit = -1
+	}
+
+	matches := s.re.FindAllStringSubmatchIndex(line, limit)
+	if len(matches) < first {
+		return line, false
+	}
+	var buf []byte
+	prev := 0
+	for _, match := range matches[first-1:] {
+		buf = append(buf, line[prev:match[0]]...)
+		for _, part := range s.replacement {
+			if part.group < 0 {
+				buf = append(buf, part.text...)
+			} else if start := match[2*part.group]; start >= 0 {
+				buf = append(buf, line[start:match[2*part.group+1]]...)
+			}
+		}
+		prev = match[1]
+	}
+	return string(append(buf, line[prev:]...)), true
+}
+
+// sedParser разбирает сценарий sed.
+type sedParser struct {
+	src      string
+	pos      int
+	extended bool
+	// last — последнее регулярное выражение; пустое // означает его
+	last *regexp.Regexp
+}
+
+// parseSedScript разбирает сценарий: команды [ADDR1[,ADDR2]][!]CMD,
+// разделённые ";" или переводом строки. Ошибка содержит номер символа.
+func parseSedScript(script string, extended bool) ([]*sedCommand, error) {
+	p := &sedParser{src: script, extended: extended}
+	commands, err := p.parse()
+	if err != nil {
+		return nil, fmt.Errorf("символ %d: %w", p.pos, err)
+	}
+	return commands, nil
+}
+
+func (p *sedParser) parse() ([]*sedCommand, error) {
+	var commands []*sedCommand
+	for {
+		p.skip(" \t\n;")
+		if p.pos >= len(p.src) {
+			return commands, nil
+		}
+		if p.src[p.pos] == '#' {
+			p.skipComment()
+			continue
+		}
+
+		cmd := &sedCommand{}
+		var err error
+		if cmd.start, err = p.address(); err != nil {
+			return nil, err
+		}
+		if cmd.start != nil && p.consume(',') {
+			p.skip(" \t")
+			if cmd.end, err = p.address(); err != nil {
+				return nil, err
+			}
+			if cmd.end == nil {
+				return nil, errors.New("ожидался адрес после ','")
+			}
+		}
+		p.skip(" \t")
+		if p.consume('!') {
+			cmd.negate = true
+			p.skip(" \t")
+		}
+
+		if p.pos >= len(p.src) {
+			return nil, errors.New("пропущена команда")
+		}
+		cmd.name = p.src[p.pos]
+		p.pos++
+		switch cmd.name {
+		case 'd', 'p':
+		case 's':
+			if cmd.subst, err = p.substitute(); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("неизвестная команда: '%c'", cmd.name)
+		}
+
+		p.skip(" \t")
+		if p.pos < len(p.src) && strings.IndexByte(";\n#", p.src[p.pos]) < 0 {
+			return nil, fmt.Errorf("лишние символы после команды: '%c'", p.src[p.pos])
+		}
+		commands = append(commands, cmd)
+	}
+}
+
+// skip пропускает символы из chars.
+func (p *sedParser) skip(chars string) {
+	for p.pos < len(p.src) && strings.IndexByte(chars, p.src[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+// skipComment пропускает комментарий до конца строки.
+func (p *sedParser) skipComment() {
+	if end := strings.IndexByte(p.src[p.pos:], '\n'); end >= 0 {
+		p.pos += end
+		return
+	}
+	p.pos = len(p.src)
+}
+
+// consume пропускает символ c, если он следующий.
+func (p *sedParser) consume(c byte) bool {
+	if p.pos < len(p.src) && p.src[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// address разбирает адрес или возвращает nil, если адреса нет.
+func (p *sedParser) address() (*sedAddress, error) {
+	if p.pos >= len(p.src) {
+		return nil, nil
+	}
+	switch c := p.src[p.pos]; {
+	case isDigit(c):
+		end := p.pos
+		for end < len(p.src) && isDigit(p.src[end]) {
+			end++
+		}
+		line, err := strconv.Atoi(p.src[p.pos:end])
+		if err != nil || line == 0 {
+			return nil, fmt.Errorf("неверный номер строки %q", p.src[p.pos:end])
+		}
+		p.pos = end
+		return &sedAddress{line: line}, nil
+	case c == '$':
+		p.pos++
+		return &sedAddress{last: true}, nil
+	case c == '/' || c == '\\':
+		p.pos++
+		delim := c
+		if c == '\\' {
+			if p.pos >= len(p.src) || p.src[p.pos] == '\n' || p.src[p.pos] == '\\' {
+				return nil, errors.New("неверный разделитель регулярного выражения")
+			}
+			delim = p.src[p.pos]
+			p.pos++
+		}
+		pattern, err := p.delimited(delim, true)
+		if err != nil {
+			return nil, errors.New("незавершённое регулярное выражение адреса")
+		}
+		re, err := p.regexp(pattern, p.consume('I'))
+		if err != nil {
+			return nil, err
+		}
+		return &sedAddress{re: re}, nil
+	}
+	return nil, nil
+}
+
+// substitute разбирает команду s начиная с разделителя.
+func (p *sedParser) substitute() (*sedSubst, error) {
+	errUnterminated := errors.New("незавершённая команда 's'")
+	if p.pos >= len(p.src) || strings.IndexByte("\n\\", p.src[p.pos]) >= 0 {
+		return nil, errUnterminated
+	}
+	delim := p.src[p.pos]
+	p.pos++
+	pattern, err := p.delimited(delim, true)
+	if err != nil {
+		return nil, errUnterminated
+	}
+	replacement, err := p.delimited(delim, false)
+	if err != nil {
+		return nil, errUnterminated
+	}
+
+	s := &sedSubst{}
+	fold := false
+flags:
+	for p.pos < len(p.src) {
+		switch c := p.src[p.pos]; {
+		case c == 'g':
+			if s.global {
+				return nil, errors.New("флаг 'g' команды 's' указан несколько раз")
+			}
+			s.global = true
+		case c == 'p':
+			if s.print {
+				return nil, errors.New("флаг 'p' команды 's' указан несколько раз")
+			}
+			s.print = true
+		case c == 'i' || c == 'I':
+			fold = true
+		case isDigit(c):
+			end := p.pos
+			for end < len(p.src) && isDigit(p.src[end]) {
+				end++
+			}
+			n, err := strconv.Atoi(p.src[p.pos:end])
+			switch {
+			case s.occurrence != 0:
+				return nil, errors.New("номер вхождения команды 's' указан несколько раз")
+			case err != nil || n == 0:
+				return nil, errors.New("номер вхождения команды 's' должен быть больше нуля")
+			}
+			s.occurrence = n
+			p.pos = end
+			continue
+		default:
+			break flags
+		}
+		p.pos++
+	}
+
+	if s.re, err = p.regexp(pattern, fold); err != nil {
+		return nil, err
+	}
+	if s.replacement, err = parseSedReplacement(replacement, s.re.NumSubexp()); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// delimited читает текст до неэкранированного разделителя delim и
+// переходит за него. "\delim" заменяется на delim, "\n" и "\t" — на
+// перевод строки и табуляцию; остальные экранирования сохраняются. В
+// регулярном выражении (regex) разделитель внутри [...] — обычный символ.
+func (p *sedParser) delimited(delim byte, regex bool) (string, error) {
+	var b strings.Builder
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		p.pos++
+
+		switch {
+		case c == delim:
+			return b.String(), nil
+		case c == '\n':
+			return "", errors.New("перевод строки внутри выражения")
+		case c == '\\' && p.pos < len(p.src):
+			next := p.src[p.pos]
+			p.pos++
+			switch next {
+			case delim:
+				b.WriteByte(delim)
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			default:
+				b.WriteByte('\\')
+				b.WriteByte(next)
+			}
+		case c == '[' && regex:
+			end := sedBracketEnd(p.src, p.pos)
+			b.WriteString(p.src[p.pos-1 : end])
+			p.pos = end
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", errors.New("нет завершающего разделителя")
+}
+
+// sedBracketEnd возвращает позицию сразу после выражения в квадратных
+// скобках, начинающегося перед pos. Если скобка не закрыта, возвращается
+// pos: тогда ошибку сообщит разбор регулярного выражения.
+func sedBracketEnd(src string, pos int) int {
+	i := pos
+	if i < len(src) && src[i] == '^' {
+		i++
+	}
+	if i < len(src) && src[i] == ']' {
+		i++
+	}
+	for i < len(src) {
+		switch {
+		case src[i] == ']':
+			return i + 1
+		case src[i] == '[' && i+1 < len(src) && strings.IndexByte(":=.", src[i+1]) >= 0:
+			end := strings.Index(src[i+2:], string(src[i+1])+"]")
+			if end < 0 {
+				return pos
+			}
+			i += 2 + end + 2
+		default:
+			i++
+		}
+	}
+	return pos
+}
+
+// regexp компилирует выражение POSIX (BRE или ERE с -E) с семантикой
+// самого длинного совпадения. Пустое выражение повторяет предыдущее.
+func (p *sedParser) regexp(pattern string, fold bool) (*regexp.Regexp, error) {
+	if pattern == "" {
+		if p.last == nil {
+			return nil, errors.New("нет предыдущего регулярного выражения")
+		}
+		return p.last, nil
+	}
+
+	translated, err := posixre.Translate(pattern, p.extended)
+	if err != nil {
+		return nil, fmt.Errorf("неверное регулярное выражение %q: %w", pattern, err)
+	}
+	if fold {
+		translated = "(?i)" + translated
+	}
+	re, err := regexp.Compile(translated)
+	if err != nil {
+		return nil, fmt.Errorf("неверное регулярное выражение %q: %w", pattern, err)
+	}
+	re.Longest()
+	p.last = re
+	return re, nil
+}
+
+// parseSedReplacement разбирает замену команды s: "&" — всё совпадение,
+// \1–\9 — группы, "\&" и "\\" — сами символы.
+func parseSedReplacement(text string, groups int) ([]sedPart, error) {
+	var parts []sedPart
+	var literal strings.Builder
+	flush := func() {
+		if literal.Len() > 0 {
+			parts = append(parts, sedPart{text: literal.String(), group: -1})
+			literal.Reset()
+		}
+	}
+
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case c == '&':
+			flush()
+			parts = append(parts, sedPart{group: 0})
+		case c == '\\' && i+1 < len(text):
+			i++
+			c = text[i]
+			if !isDigit(c) {
+				literal.WriteByte(c)
+				continue
+			}
+			group := int(c - '0')
+			if group > groups {
+				return nil, fmt.Errorf("неверная ссылка \\%d в замене команды 's'", group)
+			}
+			flush()
+			parts = append(parts, sedPart{group: group})
+		default:
+			literal.WriteByte(c)
+		}
+	}
+	flush()
+	return parts, nil
+}
@@ -0,0 +1,157 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSedCommand(t *testing.T) {
+	input := "one\ntwo\nthree\nfour\nfive\n"
+	tests := []struct {
+		name     string
+		args     []string
+		input    string
+		expected string
+	}{
+		{"substitute first", []string{"s/o/0/"}, "foo boo\n", "f0o boo\n"},
+		{"substitute global", []string{"s/o/0/g"}, "foo boo\n", "f00 b00\n"},
+		{"substitute nth", []string{"s/o/0/3"}, "foo boo\n", "foo b0o\n"},
+		{"substitute from nth", []string{"s/o/0/2g"}, "foo boo\n", "fo0 b00\n"},
+		{"substitute ignore case", []string{"s/abc/x/gI"}, "ABC abc\n", "x x\n"},
+		{"ampersand", []string{"s/[0-9]*/<&>/"}, "42 x\n", "<42> x\n"},
+		{"escaped ampersand", []string{`s/a/\&/`}, "a\n", "&\n"},
+		{"groups", []string{`s/\(.*\)-\(.*\)/\2-\1/`}, "ab-cd\n", "cd-ab\n"},
+		{"extended groups", []string{"-E", `s/([a-z]+)@([a-z]+)/\2 \1/`}, "me@host\n", "host me\n"},
+		{"other delimiter", []string{"s|/usr|/opt|"}, "/usr/bin\n", "/opt/bin\n"},
+		{"escaped delimiter", []string{`s/\//-/g`}, "a/b/c\n", "a-b-c\n"},
+		{"delimiter in brackets", []string{"s/[/]/-/"}, "a/b\n", "a-b\n"},
+		{"newline in replacement", []string{`s/,/\n/g`}, "a,b\n", "a\nb\n"},
+		{"longest match", []string{"-E", "s/a|ab/X/"}, "abc\n", "Xc\n"},
+		{"print flag", []string{"-n", "s/two/2/p"}, input, "2\n"},
+		{"delete line", []string{"2d"}, input, "one\nthree\nfour\nfive\n"},
+		{"delete last", []string{"$d"}, input, "one\ntwo\nthree\nfour\n"},
+		{"delete range", []string{"2,4d"}, input, "one\nfive\n"},
+		{"delete to end", []string{"2,$d"}, input, "one\n"},
+		{"regex range", []string{"-n", "/two/,/four/p"}, input, "two\nthree\nfour\n"},
+		{"regex range to line", []string{"-n", "/three/,2p"}, input, "three\n"},
+		{"regex range unterminated", []string{"-n", "/four/,/none/p"}, input, "four\nfive\n"},
+		{"custom regex delimiter", []string{"-n", `\,e$,p`}, input, "one\nthree\nfive\n"},
+		{"address ignore case", []string{"-n", "/TWO/Ip"}, input, "two\n"},
+		{"negate", []string{"-n", "/o/!p"}, input, "three\nfive\n"},
+		{"print duplicates", []string{"1p"}, "a\nb\n", "a\na\nb\n"},
+		{"multiple commands", []string{"s/one/1/;s/two/2/;3,$d"}, input, "1\n2\n"},
+		{"newline separated", []string{"-n", "1p\n$p"}, input, "one\nfive\n"},
+		{"expressions", []string{"-e", "s/a/b/", "-e", "s/b/c/"}, "a\n", "c\n"},
+		{"empty regex reuses last", []string{"/o/s//0/g"}, "foo\nbar\n", "f00\nbar\n"},
+		{"address with substitute", []string{"2s/^/> /"}, "a\nb\nc\n", "a\n> b\nc\n"},
+		{"missing newline kept", []string{"s/b/B/"}, "a\nb", "a\nB"},
+		{"missing newline with print", []string{"p"}, "x", "x\nx"},
+		{"comment", []string{"# comment\ns/a/b/"}, "a\n", "b\n"},
+		{"tab escape", []string{`s/\t/ /`}, "a\tb\n", "a b\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, stderr, status := runCommand(t, &SedCommand{}, "", tt.input, tt.args...)
+			if status != 0 || stderr != "" {
+				t.Fatalf("ожидался код 0 без ошибок, получено %d: %q", status, stderr)
+			}
+			if out != tt.expected {
+				t.Errorf("ожидалось %q, получено %q", tt.expected, out)
+			}
+		})
+	}
+}
+
+func TestSedCommand_Files(t *testing.T) {
+	dir := t.TempDir()
+	_ = os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a1\na2\n"), 0o644)
+	_ = os.WriteFile(filepath.Join(dir, "b.txt"), []byte("b1\nb2\n"), 0o644)
+
+	out, _, status := runCommand(t, &SedCommand{}, dir, "", "-n", "2,3p;$p", "a.txt", "b.txt")
+	if status != 0 || out != "a2\nb1\nb2\n" {
+		t.Errorf("ожидалась сквозная нумерация строк, получено %d: %q", status, out)
+	}
+
+	out, stderr, status := runCommand(t, &SedCommand{}, dir, "", "p", "missing.txt", "a.txt")
+	if status != 2 || !strings.Contains(stderr, "missing.txt") || out != "a1\na1\na2\na2\n" {
+		t.Errorf("ожидался код 2 и вывод второго файла, получено %d: %q, %q", status, out, stderr)
+	}
+}
+
+func TestSedCommand_InPlace(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.txt")
+	second := filepath.Join(dir, "second.txt")
+	_ = os.WriteFile(first, []byte("hello\nworld\n"), 0o644)
+	_ = os.WriteFile(second, []byte("hello"), 0o644)
+	if err := os.Chmod(first, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	out, stderr, status := runCommand(t, &SedCommand{}, dir, "", "-i", "s/hello/bye/;$s/$/!/", "first.txt", "second.txt")
+	if status != 0 || out != "" || stderr != "" {
+		t.Fatalf("ожидался код 0 без вывода, получено %d: %q, %q", status, out, stderr)
+	}
+	for path, expected := range map[string]string{first: "bye\nworld!\n", second: "bye!"} {
+		if data, _ := os.ReadFile(path); string(data) != expected {
+			t.Errorf("%s: ожидалось %q, получено %q", filepath.Base(path), expected, data)
+		}
+	}
+	if info, err := os.Stat(first); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("права доступа должны сохраниться: %v, %v", info, err)
+	}
+
+	_, _, status = runCommand(t, &SedCommand{}, dir, "", "-i.bak", "-n", "1p", "first.txt")
+	if status != 0 {
+		t.Fatalf("ожидался код 0, получено %d", status)
+	}
+	if data, _ := os.ReadFile(first); string(data) != "bye\n" {
+		t.Errorf("ожидалось %q, получено %q", "bye\n", data)
+	}
+	if data, _ := os.ReadFile(first + ".bak"); string(data) != "bye\nworld!\n" {
+		t.Errorf("резервная копия: ожидалось %q, получено %q", "bye\nworld!\n", data)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 3 {
+		t.Errorf("временные файлы должны быть удалены, получено %d файлов", len(entries))
+	}
+
+	_, stderr, status = runCommand(t, &SedCommand{}, dir, "", "-i", "p", "missing.txt")
+	if status != 2 || !strings.Contains(stderr, "missing.txt") {
+		t.Errorf("ожидался код 2, получено %d: %q", status, stderr)
+	}
+}
+
+func TestSedCommand_InvalidArgs(t *testing.T) {
+	tests := []struct {
+		args    []string
+		message string
+	}{
+		{nil, "не указан сценарий"},
+		{[]string{"x"}, "неизвестная команда"},
+		{[]string{"s/a/b"}, "незавершённая команда 's'"},
+		{[]string{"s/a/b/q"}, "лишние символы"},
+		{[]string{"s/a/b/0"}, "больше нуля"},
+		{[]string{`s/a/\1/`}, `неверная ссылка \1`},
+		{[]string{"0p"}, "неверный номер строки"},
+		{[]string{"1,p"}, "ожидался адрес"},
+		{[]string{"s//x/"}, "нет предыдущего"},
+		{[]string{"/[a/p"}, "неверное регулярное выражение"},
+		{[]string{"-x", "p"}, "неверная опция"},
+		{[]string{"-i", "p"}, "нет входных файлов"},
+	}
+
+	for _, tt := range tests {
+		_, stderr, status := runCommand(t, &SedCommand{}, "", "", tt.args...)
+		if status != 1 || !strings.Contains(stderr, tt.message) {
+			t.Errorf("%v: ожидался код 1 и сообщение %q, получено %d: %q", tt.args, tt.message, status, stderr)
+		}
+	}
+}
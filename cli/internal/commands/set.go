@@ -0,0 +1,183 @@
+package commands
+
+import (
+	"fmt"
+
+	customErrors "github.com/ggerlakh/software-design-mhs-itmo/cli/internal/errors"
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
+)
+
+// setUsageCode — код возврата set при неверных параметрах.
+const setUsageCode = 2
+
+// SetCommand реализует встроенную команду "set".
+// Она включает и выключает опции оболочки из CommandContext.SetOptions:
+// errexit, nounset, pipefail, xtrace, noclobber и noglob.
+type SetCommand struct{}
+
+// Name возвращает имя команды.
+func (s *SetCommand) Name() string {
+	return "set"
+}
+
+// Exec выполняет команду set.
+//
+// Синтаксис:
+//
+//	set [-efuxC] [+efuxC] [-o NAME] [+o NAME] [--]
+//
+// Без аргументов выводятся переменные оболочки. "set -o" без имени
+// выводит состояние опций, "set +o" — команды set, которые их
+// восстанавливают.
+//
+// Примеры:
+//
+//	set -eu -o pipefail   → включить errexit, nounset и pipefail
+//	set +x                → выключить xtrace
+func (s *SetCommand) Exec(args []string, ctx *CommandContext) error {
+	if len(args) == 0 {
+		return listVariables(ctx)
+	}
+
+	for len(args) > 0 {
+		arg := args[0]
+		args = args[1:]
+		if arg == "--" {
+			break
+		}
+		if len(arg) < 2 || arg[0] != '-' && arg[0] != '+' {
+			return setFail(ctx, "set: %s: позиционные параметры не поддерживаются", arg)
+		}
+
+		enable := arg[0] == '-'
+		for _, flag := range arg[1:] {
+			if flag != 'o' {
+				name := setOptionName(flag)
+				if name == "" {
+					return setFail(ctx, "set: %c%c: недопустимая опция", arg[0], flag)
+				}
+				if err := ctx.SetOptions.Set(name, enable); err != nil {
+					return setFail(ctx, "set: %v", err)
+				}
+				continue
+			}
+
+			// -o без имени опции выводит состояние всех опций
+			if len(args) == 0 || len(args[0]) > 0 && (args[0][0] == '-' || args[0][0] == '+') {
+				if err := printSetOptions(ctx, enable); err != nil {
+					return err
+				}
+				continue
+			}
+			name := args[0]
+			args = args[1:]
+			if !ctx.SetOptions.Known(name) {
+				return setFail(ctx, "set: %s: недопустимое имя опции", name)
+			}
+			if err := ctx.SetOptions.Set(name, enable); err != nil {
+				return setFail(ctx, "set: %v", err)
+			}
+		}
+	}
+
+	if len(args) > 0 {
+		return setFail(ctx, "set: %s: позиционные параметры не поддерживаются", args[0])
+	}
+	return nil
+}
+
+// setOptionName возвращает имя опции для однобуквенного флага или "".
+func setOptionName(flag rune) string {
+	for _, f := range session.SetFlags {
+		if rune(f.Flag) == flag {
+			return f.Name
+		}
+	}
+	return ""
+}
+
+// printSetOptions выводит состояние опций: для "set -o" — таблицей,
+// для "set +o" — командами set.
+func printSetOptions(ctx *CommandContext, table bool) error {
+	for _, name := range ctx.SetOptions.Names() {
+		enabled := ctx.SetOptions.Enabled(name)
+
+		var err error
+		switch {
+		case table:
+			_, err = fmt.Fprintf(ctx.Stdout, "%-15s\t%s\n", name, onOff(enabled))
+		case enabled:
+			_, err = fmt.Fprintf(ctx.Stdout, "set -o %s\n", name)
+		default:
+			_, err = fmt.Fprintf(ctx.Stdout, "set +o %s\n", name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setFail печатает ошибку в stderr и возвращает код неверных параметров.
+func setFail(ctx *CommandContext, format string, args ...any) error {
+	if err := warnf(ctx, format, args...); err != nil {
+		return err
+	}
+	return &customErrors.ExitStatusError{Code: setUsageCode}
+}
+
+// Help возвращает справку по команде set.
+func (s *SetCommand) Help() string {
+	return `NAME
+    set - управляет опциями оболочки
+
+SYNOPSIS
+    set [-efuxC] [+efuxC] [-o OPTNAME] [+o OPTNAME] [--]
+
+DESCRIPTION
+    Флаг с "-" включает опцию, с "+" — выключает. Без аргументов
+    выводит переменные оболочки. "set -o" без имени выводит
+    состояние всех опций, "set +o" — команды set, которые его
+    восстанавливают. Включённые однобуквенные флаги показывает
+    переменная $-.
+
+    Подоболочка получает копию опций: set в ( ... ) не влияет
+    на текущую оболочку.
+
+OPTIONS
+    -e, -o errexit
+        завершить оболочку, если команда вернула ненулевой код.
+        Не действует на команды перед && и || и внутри них
+    -u, -o nounset
+        подстановка незаданной переменной — ошибка; "${a[@]}"
+        для незаданного массива разрешена
+    -x, -o xtrace
+        выводить в stderr каждую команду после раскрытия,
+        с префиксом $PS4 (по умолчанию "+ ")
+    -C, -o noclobber
+        > не перезаписывает существующие обычные файлы;
+        >| перезаписывает их и при noclobber
+    -f, -o noglob
+        не раскрывать шаблоны имён файлов
+    -o pipefail
+        код пайплайна — код последней завершившейся с ошибкой
+        команды, а не код последней команды
+    --      конец опций
+
+EXIT STATUS
+    0 — успех, 2 — неверная опция или позиционные параметры.
+
+EXAMPLES
+    set -euo pipefail
+        → строгий режим для сценариев CI
+
+    set -x; echo "a b"
+        → + echo 'a b'
+          a b
+
+    set +o
+        → set +o errexit
+          ...`
+}
+
+var _ BuiltinCommand = (*SetCommand)(nil)
@@ -0,0 +1,74 @@
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
+)
+
+func runSet(opts *session.Options, args ...string) (string, string, int) {
+	var stdout, stderr bytes.Buffer
+	ctx := &CommandContext{
+		Stdout:     &stdout,
+		Stderr:     &stderr,
+		Vars:       session.NewVariables(map[string]string{"v": "a b"}),
+		SetOptions: opts,
+	}
+	err := (&SetCommand{}).Exec(args, ctx)
+	return stdout.String(), stderr.String(), testStatus(err)
+}
+
+func TestSetCommand_Flags(t *testing.T) {
+	opts := session.NewSetOptions()
+	if _, stderr, status := runSet(opts, "-eux", "-Co", "pipefail", "-f", "--"); status != 0 {
+		t.Fatalf("ожидался код 0, получено %d: %q", status, stderr)
+	}
+	if flags := opts.Flags(); flags != "efuxC" || !opts.Enabled(session.PipeFail) {
+		t.Fatalf("ожидались флаги efuxC и pipefail, получено %q", flags)
+	}
+
+	runSet(opts, "+xf", "+o", "errexit", "+o", "pipefail")
+	if flags := opts.Flags(); flags != "uC" || opts.Enabled(session.PipeFail) {
+		t.Errorf("ожидались флаги uC без pipefail, получено %q", flags)
+	}
+}
+
+func TestSetCommand_Print(t *testing.T) {
+	opts := session.NewSetOptions()
+	_ = opts.Set(session.ErrExit, true)
+
+	stdout, _, _ := runSet(opts, "-o")
+	if !strings.Contains(stdout, "errexit        \ton\n") || !strings.Contains(stdout, "xtrace         \toff\n") {
+		t.Errorf("неверная таблица опций: %q", stdout)
+	}
+
+	stdout, _, _ = runSet(opts, "+o")
+	expected := "set -o errexit\nset +o noclobber\nset +o noglob\nset +o nounset\nset +o pipefail\nset +o xtrace\n"
+	if stdout != expected {
+		t.Errorf("ожидалось %q, получено %q", expected, stdout)
+	}
+
+	if stdout, _, _ = runSet(opts); stdout != "v='a b'\n" {
+		t.Errorf("set без аргументов: ожидалось %q, получено %q", "v='a b'\n", stdout)
+	}
+}
+
+func TestSetCommand_Errors(t *testing.T) {
+	tests := []struct {
+		args    []string
+		message string
+	}{
+		{[]string{"-q"}, "set: -q: недопустимая опция"},
+		{[]string{"+eq"}, "set: +q: недопустимая опция"},
+		{[]string{"-o", "vi"}, "set: vi: недопустимое имя опции"},
+		{[]string{"--", "a"}, "set: a: позиционные параметры не поддерживаются"},
+	}
+	for _, tt := range tests {
+		_, stderr, status := runSet(session.NewSetOptions(), tt.args...)
+		if status != setUsageCode || !strings.Contains(stderr, tt.message) {
+			t.Errorf("%v: ожидался код %d и %q, получено %d: %q", tt.args, setUsageCode, tt.message, status, stderr)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package commands
+
+import "fmt"
+
+// ShoptCommand реализует встроенную команду "shopt".
+// Она включает, выключает и показывает опции сеанса из
+// CommandContext.Options, например опции раскрытия шаблонов имён файлов.
+type ShoptCommand struct{}
+
+// Name возвращает имя команды.
+func (s *ShoptCommand) Name() string {
+	return "shopt"
+}
+
+// Exec выполняет команду shopt с переданными аргументами.
+// Поддерживаются опции:
+//   - -s — включить перечисленные опции
+//   - -u — выключить перечисленные опции
+//   - -p — выводить опции в виде команд shopt
+//   - -q — ничего не выводить
+//
+// Без имён опций выводится состояние всех опций
+// (с -s или -u — только включённых или выключенных).
+//
+// Примеры:
+//
+//	shopt -s nullglob   → включить nullglob
+//	shopt globstar      → globstar        off
+func (s *ShoptCommand) Exec(args []string, ctx *CommandContext) error {
+	var set, unset, printable, quiet bool
+
+	for len(args) > 0 && len(args[0]) > 1 && args[0][0] == '-' {
+		for _, flag := range args[0][1:] {
+			switch flag {
+			case 's':
+				set = true
+			case 'u':
+				unset = true
+			case 'p':
+				printable = true
+			case 'q':
+				quiet = true
+			default:
+				return s.fail(ctx, fmt.Errorf("shopt: -%c: недопустимая опция", flag))
+			}
+		}
+		args = args[1:]
+	}
+
+	if set && unset {
+		return s.fail(ctx, fmt.Errorf("shopt: нельзя одновременно указывать -s и -u"))
+	}
+
+	for _, name := range args {
+		if !ctx.Options.Known(name) {
+			return s.fail(ctx, fmt.Errorf("shopt: %s: недопустимое имя опции", name))
+		}
+	}
+
+	if len(args) > 0 && (set || unset) {
+		for _, name := range args {
+			if err := ctx.Options.Set(name, set); err != nil {
+				return s.fail(ctx, fmt.Errorf("shopt: %w", err))
+			}
+		}
+		return nil
+	}
+
+	names := args
+	if len(names) == 0 {
+		names = ctx.Options.Names()
+	}
+
+	if quiet {
+		return nil
+	}
+
+	for _, name := range names {
+		enabled := ctx.Options.Enabled(name)
+		if len(args) == 0 && (set && !enabled || unset && enabled) {
+			continue
+		}
+
+		var err error
+		switch {
+		case printable && enabled:
+			_, err = fmt.Fprintf(ctx.Stdout, "shopt -s %s\n", name)
+		case printable:
+			_, err = fmt.Fprintf(ctx.Stdout, "shopt -u %s\n", name)
+		default:
+			_, err = fmt.Fprintf(ctx.Stdout, "%-15s\t%s\n", name, onOff(enabled))
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fail печатает ошибку в stderr и возвращает её.
+func (s *ShoptCommand) fail(ctx *CommandContext, err error) error {
+	if _, writeErr := fmt.Fprintln(ctx.Stderr, err); writeErr != nil {
+		return writeErr
+	}
+	return err
+}
+
+func onOff(enabled bool) string {
+	if enabled {
+		return "on"
+	}
+	return "off"
+}
+
+// Help возвращает справку по команде shopt.
+func (s *ShoptCommand) Help() string {
+	return `NAME
+    shopt - управляет опциями сеанса
+
+SYNOPSIS
+    shopt [-pqsu] [OPTNAME]...
+
+DESCRIPTION
+    Включает, выключает или показывает опции сеанса.
+    Без аргументов выводит состояние всех опций.
+
+OPTIONS
+    -s    включить перечисленные опции
+    -u    выключить перечисленные опции
+    -p    выводить опции в виде команд shopt
+    -q    ничего не выводить
+
+OPTNAMES
+    nullglob    шаблон без совпадений раскрывается в пустой список
+    failglob    шаблон без совпадений считается ошибкой
+    dotglob     * и ? совпадают с именами, начинающимися с точки
+    globstar    ** совпадает с любым количеством каталогов
+    extglob     расширенные шаблоны ?(...), *(...), +(...), @(...), !(...)
+
+EXAMPLES
+    shopt -s nullglob globstar
+        → включить nullglob и globstar
+
+    shopt dotglob
+        → dotglob        	off`
+}
+
+var _ BuiltinCommand = (*ShoptCommand)(nil)
@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
+)
+
+func runShopt(opts *session.Options, args ...string) (string, string, error) {
+	var stdout, stderr bytes.Buffer
+	ctx := &CommandContext{
+		Stdin:   strings.NewReader(""),
+		Stdout:  &stdout,
+		Stderr:  &stderr,
+		Vars:    session.NewVariables(nil),
+		Options: opts,
+		Dir:     ".",
+	}
+	err := (&ShoptCommand{}).Exec(args, ctx)
+	return stdout.String(), stderr.String(), err
+}
+
+func TestShoptCommand_SetAndUnset(t *testing.T) {
+	opts := session.NewOptions()
+
+	if _, _, err := runShopt(opts, "-s", "nullglob", "globstar"); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if !opts.Enabled(session.NullGlob) || !opts.Enabled(session.GlobStar) {
+		t.Fatalf("опции должны быть включены")
+	}
+
+	if _, _, err := runShopt(opts, "-u", "nullglob"); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if opts.Enabled(session.NullGlob) {
+		t.Fatalf("nullglob должен быть выключен")
+	}
+}
+
+func TestShoptCommand_Print(t *testing.T) {
+	opts := session.NewOptions()
+	_ = opts.Set(session.DotGlob, true)
+
+	out, _, _ := runShopt(opts, "dotglob", "extglob")
+	if out != "dotglob        \ton\nextglob        \toff\n" {
+		t.Fatalf("неожиданный вывод: %q", out)
+	}
+
+	out, _, _ = runShopt(opts, "-p", "-s")
+	if out != "shopt -s dotglob\n" {
+		t.Fatalf("неожиданный вывод -p -s: %q", out)
+	}
+}
+
+func TestShoptCommand_InvalidName(t *testing.T) {
+	_, stderr, err := runShopt(session.NewOptions(), "-s", "nosuchopt")
+	if err == nil {
+		t.Fatalf("ожидалась ошибка для неизвестной опции")
+	}
+	if !strings.Contains(stderr, "nosuchopt") {
+		t.Fatalf("ошибка должна попасть в stderr: %q", stderr)
+	}
+}
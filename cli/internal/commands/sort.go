@@ -0,0 +1,589 @@
+package commands
+
+import (
+	"bufio"
+	"container/heap"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	customErrors "github.com/ggerlakh/software-design-mhs-itmo/cli/internal/errors"
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/linereader"
+)
+
+// SortCommand реализует встроенную команду "sort".
+// Она сортирует строки файлов или stdin. Входные данные, не помещающиеся
+// в буфер (-S), сортируются частями во временных файлах и затем сливаются.
+type SortCommand struct{}
+
+// sortDefaultBuffer — размер буфера строк по умолчанию. Если входные
+// данные больше, sort переходит к внешней сортировке слиянием.
+const sortDefaultBuffer = 64 << 20
+
+// sortLineOverhead — оценка памяти на одну строку помимо её байт
+// (заголовок строки в срезе), чтобы учитывать множество коротких строк.
+const sortLineOverhead = 16
+
+// sortMergeWidth — сколько временных файлов сливается одновременно.
+const sortMergeWidth = 16
+
+// sortErrorCode — код возврата sort при ошибке, как в GNU sort.
+const sortErrorCode = 2
+
+// sortOptions — параметры sort.
+type sortOptions struct {
+	global     sortOrder // порядок, заданный глобальными флагами
+	keys       []sortKey // ключи -k; пусто — ключ вся строка
+	separator  string    // разделитель полей -t; пусто — переход от пробелов к непробелам
+	unique     bool      // выводить только первую строку из равных (-u)
+	stable     bool      // не сравнивать равные по ключам строки целиком (-s)
+	bufferSize int64     // размер буфера для сортировки в памяти (-S)
+	tempDir    string    // каталог временных файлов (-T)
+}
+
+// sortKeysFlag накапливает значения повторяющегося флага -k.
+type sortKeysFlag struct {
+	keys *[]sortKey
+}
+
+func (f sortKeysFlag) String() string {
+	return ""
+}
+
+func (f sortKeysFlag) Set(value string) error {
+	key, err := parseSortKey(value)
+	if err != nil {
+		return err
+	}
+	*f.keys = append(*f.keys, key)
+	return nil
+}
+
+// Name возвращает имя команды.
+func (s *SortCommand) Name() string {
+	return "sort"
+}
+
+// Exec выполняет команду sort.
+//
+// Синтаксис:
+//
+//	sort [-nrhVfbsu] [-t SEP] [-k KEYDEF]... [-S SIZE] [-T DIR] [FILE...]
+//
+// Если файлы не указаны или указан "-", читается stdin. Строки всех
+// файлов сортируются вместе. Если данные не помещаются в буфер -S,
+// отсортированные части записываются во временные файлы и сливаются.
+// При ошибке возвращается ExitStatusError с кодом 2.
+//
+// Примеры:
+//
+//	sort names.txt               → строки по возрастанию
+//	sort -rn counts.txt          → числа по убыванию
+//	sort -t, -k2,2n data.csv     → по второму полю CSV как по числу
+//	sort -u words.txt            → без повторов
+func (s *SortCommand) Exec(args []string, ctx *CommandContext) error {
+	opts, files, err := parseSortArgs(args)
+	if err != nil {
+		return sortFail(ctx, err)
+	}
+	if opts.tempDir == "" {
+		opts.tempDir = variableValue(ctx, "TMPDIR")
+	}
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+
+	sorter := &externalSorter{opts: opts}
+	defer sorter.cleanup()
+
+	for _, name := range files {
+		reader, closeInput, err := openInput(name, ctx)
+		if err != nil {
+			return sortFail(ctx, fmt.Errorf("sort: не удалось открыть %s: %v", name, fileError(err)))
+		}
+		err = sorter.read(reader)
+		closeInput()
+		if err != nil {
+			return sortFail(ctx, fmt.Errorf("sort: %s: %v", displayName(name), fileError(err)))
+		}
+	}
+
+	out := bufio.NewWriter(ctx.Stdout)
+	if err := sorter.write(out); err != nil {
+		var tempErr *sortTempError
+		if errors.As(err, &tempErr) {
+			return sortFail(ctx, err)
+		}
+		return err
+	}
+	return out.Flush()
+}
+
+// parseSortArgs разбирает аргументы sort.
+func parseSortArgs(args []string) (*sortOptions, []string, error) {
+	fs := flag.NewFlagSet("sort", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	opts := &sortOptions{}
+	for _, names := range []struct {
+		short, long string
+		value       *bool
+		usage       string
+	}{
+		{"n", "numeric-sort", &opts.global.numeric, "сравнивать как числа"},
+		{"h", "human-numeric-sort", &opts.global.human, "сравнивать числа с суффиксами (2K, 1G)"},
+		{"V", "version-sort", &opts.global.version, "сравнивать номера версий"},
+		{"r", "reverse", &opts.global.reverse, "обратный порядок"},
+		{"f", "ignore-case", &opts.global.fold, "не различать регистр"},
+		{"b", "ignore-leading-blanks", &opts.global.blanks, "пропускать начальные пробелы"},
+		{"u", "unique", &opts.unique, "выводить только первую из равных строк"},
+		{"s", "stable", &opts.stable, "устойчивая сортировка"},
+	} {
+		fs.BoolVar(names.value, names.short, false, names.usage)
+		fs.BoolVar(names.value, names.long, false, names.usage)
+	}
+	fs.Var(sortKeysFlag{&opts.keys}, "k", "ключ сортировки")
+	fs.Var(sortKeysFlag{&opts.keys}, "key", "ключ сортировки")
+	fs.StringVar(&opts.separator, "t", "", "разделитель полей")
+	fs.StringVar(&opts.separator, "field-separator", "", "разделитель полей")
+	bufferSize := fs.String("S", "", "размер буфера")
+	fs.StringVar(bufferSize, "buffer-size", "", "размер буфера")
+	fs.StringVar(&opts.tempDir, "T", "", "каталог временных файлов")
+	fs.StringVar(&opts.tempDir, "temporary-directory", "", "каталог временных файлов")
+
+	if err := fs.Parse(splitShortFlags(args, "ktST")); err != nil {
+		return nil, nil, fmt.Errorf("sort: ошибка разбора флагов: %w", err)
+	}
+
+	if opts.global.exclusive() > 1 {
+		return nil, nil, errors.New("sort: флаги -n, -h и -V несовместимы")
+	}
+	separatorSet := false
+	fs.Visit(func(f *flag.Flag) {
+		separatorSet = separatorSet || f.Name == "t" || f.Name == "field-separator"
+	})
+	if separatorSet && utf8.RuneCountInString(opts.separator) != 1 {
+		return nil, nil, fmt.Errorf("sort: разделитель должен быть одним символом: %q", opts.separator)
+	}
+
+	opts.bufferSize = sortDefaultBuffer
+	if *bufferSize != "" {
+		size, err := parseSortBufferSize(*bufferSize)
+		if err != nil {
+			return nil, nil, err
+		}
+		opts.bufferSize = size
+	}
+
+	// Ключ без собственных параметров порядка наследует глобальные
+	for i := range opts.keys {
+		if opts.keys[i].inherits() {
+			opts.keys[i].sortOrder = opts.global
+			opts.keys[i].blanksEnd = opts.global.blanks
+		}
+	}
+	return opts, fs.Args(), nil
+}
+
+// parseSortBufferSize разбирает размер буфера -S: число с необязательным
+// суффиксом b, K, M, G или T. Число без суффикса задаёт килобайты, как в GNU sort.
+func parseSortBufferSize(text string) (int64, error) {
+	multiplier := int64(1 << 10)
+	digits := text
+	if suffix := strings.IndexAny(text, "bKkMmGgTt"); suffix >= 0 && suffix == len(text)-1 {
+		digits = text[:suffix]
+		multiplier = map[byte]int64{
+			'b': 1, 'K': 1 << 10, 'k': 1 << 10, 'M': 1 << 20, 'm': 1 << 20,
+			'G': 1 << 30, 'g': 1 << 30, 'T': 1 << 40, 't': 1 << 40,
+		}[text[suffix]]
+	}
+
+	value, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil || value <= 0 || value > (1<<63-1)/multiplier {
+		return 0, fmt.Errorf("sort: неверный размер буфера: %q", text)
+	}
+	return value * multiplier, nil
+}
+
+// sortFail печатает ошибку sort и возвращает код 2.
+func sortFail(ctx *CommandContext, err error) error {
+	if _, writeErr := fmt.Fprintln(ctx.Stderr, err); writeErr != nil {
+		return writeErr
+	}
+	return &customErrors.ExitStatusError{Code: sortErrorCode}
+}
+
+// compare сравнивает строки по ключам. Если ключи равны, строки
+// сравниваются целиком, кроме режимов -s и -u.
+func (o *sortOptions) compare(a, b string) int {
+	if c := o.compareKeys(a, b); c != 0 || o.stable || o.unique {
+		return c
+	}
+	c := strings.Compare(a, b)
+	if o.global.reverse {
+		return -c
+	}
+	return c
+}
+
+// compareKeys сравнивает строки только по ключам сортировки.
+func (o *sortOptions) compareKeys(a, b string) int {
+	if len(o.keys) == 0 {
+		return o.global.compare(o.global.trim(a), o.global.trim(b))
+	}
+
+	for i := range o.keys {
+		key := &o.keys[i]
+		if c := key.compare(key.extract(a, o.separator), key.extract(b, o.separator)); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// sortTempError — ошибка работы с временными файлами внешней сортировки.
+type sortTempError struct {
+	err error
+}
+
+func (e *sortTempError) Error() string {
+	return fmt.Sprintf("sort: ошибка временного файла: %v", e.err)
+}
+
+func (e *sortTempError) Unwrap() error {
+	return e.err
+}
+
+// externalSorter накапливает строки в памяти, а при переполнении буфера
+// сортирует их и сбрасывает во временный файл. При выводе отсортированные
+// части сливаются.
+type externalSorter struct {
+	opts  *sortOptions
+	lines []string
+	size  int64    // оценка памяти, занятой lines
+	runs  []string // временные файлы с отсортированными частями, по порядку ввода
+	temp  []string // все созданные временные файлы, для удаления
+}
+
+// read добавляет строки из r.
+func (s *externalSorter) read(r io.Reader) error {
+	lines := linereader.New(r)
+	for {
+		line, err := lines.ReadLine()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		text := string(linereader.TrimNewline(line))
+		s.lines = append(s.lines, text)
+		s.size += int64(len(text)) + sortLineOverhead
+		if s.size >= s.opts.bufferSize {
+			if err := s.flush(); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// flush сортирует накопленные строки и записывает их во временный файл.
+func (s *externalSorter) flush() error {
+	slices.SortStableFunc(s.lines, s.opts.compare)
+	name, err := s.createRun(func(w *bufio.Writer) error {
+		for _, line := range s.lines {
+			if err := writeSortLine(w, line); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.runs = append(s.runs, name)
+	s.lines, s.size = s.lines[:0], 0
+	return nil
+}
+
+// createRun создаёт временный файл и записывает в него строки через fill.
+func (s *externalSorter) createRun(fill func(w *bufio.Writer) error) (string, error) {
+	file, err := os.CreateTemp(s.opts.tempDir, "go-cli-sort-*")
+	if err != nil {
+		return "", &sortTempError{err}
+	}
+	s.temp = append(s.temp, file.Name())
+
+	w := bufio.NewWriter(file)
+	err = fill(w)
+	if err == nil {
+		err = w.Flush()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		var tempErr *sortTempError
+		if errors.As(err, &tempErr) {
+			return "", err
+		}
+		return "", &sortTempError{err}
+	}
+	return file.Name(), nil
+}
+
+func writeSortLine(w *bufio.Writer, line string) error {
+	if _, err := w.WriteString(line); err != nil {
+		return err
+	}
+	return w.WriteByte('\n')
+}
+
+// write выводит все строки в отсортированном порядке.
+func (s *externalSorter) write(w *bufio.Writer) error {
+	out := &sortWriter{w: w, opts: s.opts}
+	if len(s.runs) == 0 {
+		slices.SortStableFunc(s.lines, s.opts.compare)
+		for _, line := range s.lines {
+			if err := out.write(line); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if len(s.lines) > 0 {
+		if err := s.flush(); err != nil {
+			return err
+		}
+	}
+
+	// Одновременно открывается не больше sortMergeWidth файлов: лишние
+	// части предварительно сливаются группами в новые временные файлы
+	for len(s.runs) > sortMergeWidth {
+		var merged []string
+		for start := 0; start < len(s.runs); start += sortMergeWidth {
+			batch := s.runs[start:min(start+sortMergeWidth, len(s.runs))]
+			name, err := s.createRun(func(w *bufio.Writer) error {
+				return s.merge(batch, func(line string) error {
+					return writeSortLine(w, line)
+				})
+			})
+			if err != nil {
+				return err
+			}
+			merged = append(merged, name)
+		}
+		s.runs = merged
+	}
+	return s.merge(s.runs, out.write)
+}
+
+// merge сливает отсортированные временные файлы names и передаёт строки
+// в emit. При равенстве строк первой выводится строка из более ранней
+// части, поэтому слияние сохраняет порядок ввода, как и сортировка в памяти.
+func (s *externalSorter) merge(names []string, emit func(string) error) error {
+	queue := &sortMergeQueue{opts: s.opts}
+	opened := make([]*sortMergeRun, 0, len(names))
+	defer func() {
+		// Части, прочитанные до конца, уже закрыты; остальные остаются
+		// открытыми только при ошибке
+		for _, run := range opened {
+			_ = run.close()
+		}
+	}()
+
+	for i, name := range names {
+		file, err := os.Open(name)
+		if err != nil {
+			return &sortTempError{err}
+		}
+
+		run := &sortMergeRun{index: i, file: file, lines: linereader.New(file)}
+		opened = append(opened, run)
+		ok, err := run.next()
+		if err != nil {
+			return &sortTempError{err}
+		}
+		if ok {
+			queue.runs = append(queue.runs, run)
+		}
+	}
+	heap.Init(queue)
+
+	for queue.Len() > 0 {
+		run := queue.runs[0]
+		if err := emit(run.line); err != nil {
+			return err
+		}
+
+		ok, err := run.next()
+		if err != nil {
+			return &sortTempError{err}
+		}
+		if ok {
+			heap.Fix(queue, 0)
+		} else {
+			heap.Pop(queue)
+		}
+	}
+	return nil
+}
+
+// sortMergeRun — временный файл, участвующий в слиянии, и его текущая строка.
+type sortMergeRun struct {
+	index int
+	file  *os.File
+	lines *linereader.Reader
+	line  string
+}
+
+// next читает следующую строку. false означает конец файла: файл части
+// при этом закрывается.
+func (r *sortMergeRun) next() (bool, error) {
+	line, err := r.lines.ReadLine()
+	if errors.Is(err, io.EOF) {
+		return false, r.close()
+	}
+	if err != nil {
+		return false, err
+	}
+	r.line = string(linereader.TrimNewline(line))
+	return true, nil
+}
+
+// close закрывает файл части. Повторный вызов ничего не делает.
+func (r *sortMergeRun) close() error {
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+// sortMergeQueue — куча частей, упорядоченная по их текущим строкам.
+type sortMergeQueue struct {
+	opts *sortOptions
+	runs []*sortMergeRun
+}
+
+func (q *sortMergeQueue) Len() int {
+	return len(q.runs)
+}
+
+func (q *sortMergeQueue) Less(i, j int) bool {
+	if c := q.opts.compare(q.runs[i].line, q.runs[j].line); c != 0 {
+		return c < 0
+	}
+	return q.runs[i].index < q.runs[j].index
+}
+
+func (q *sortMergeQueue) Swap(i, j int) {
+	q.runs[i], q.runs[j] = q.runs[j], q.runs[i]
+}
+
+func (q *sortMergeQueue) Push(x any) {
+	q.runs = append(q.runs, x.(*sortMergeRun))
+}
+
+func (q *sortMergeQueue) Pop() any {
+	last := q.runs[len(q.runs)-1]
+	q.runs = q.runs[:len(q.runs)-1]
+	return last
+}
+
+// cleanup удаляет временные файлы.
+func (s *externalSorter) cleanup() {
+	for _, name := range s.temp {
+		_ = os.Remove(name)
+	}
+}
+
+// sortWriter выводит строки, пропуская в режиме -u строки, равные
+// по ключам предыдущей.
+type sortWriter struct {
+	w       *bufio.Writer
+	opts    *sortOptions
+	last    string
+	written bool
+}
+
+func (s *sortWriter) write(line string) error {
+	if s.opts.unique && s.written && s.opts.compareKeys(s.last, line) == 0 {
+		return nil
+	}
+	s.last, s.written = line, true
+
+	if _, err := s.w.WriteString(line); err != nil {
+		return err
+	}
+	return s.w.WriteByte('\n')
+}
+
+// Help возвращает справку по команде sort.
+func (s *SortCommand) Help() string {
+	return `NAME
+    sort - сортирует строки
+
+SYNOPSIS
+    sort [OPTION]... [FILE]...
+
+DESCRIPTION
+    Выводит строки всех файлов в отсортированном порядке. Если файл не
+    указан или указан "-", читается стандартный ввод. Строки сравниваются
+    побайтно; строки с равными ключами — целиком, если не задан -s.
+
+    Если данные не помещаются в буфер (-S), отсортированные части
+    записываются во временные файлы и затем сливаются, поэтому можно
+    сортировать данные больше доступной памяти.
+
+OPTIONS
+    -b, --ignore-leading-blanks  пропускать начальные пробелы в ключах
+    -f, --ignore-case            не различать строчные и заглавные буквы
+    -h, --human-numeric-sort     сравнивать числа с суффиксами (2K, 1G)
+    -n, --numeric-sort           сравнивать как числа
+    -V, --version-sort           сравнивать номера версий (1.9 < 1.10)
+    -r, --reverse                обратный порядок
+    -k, --key=KEYDEF             сортировать по ключу; флаг можно повторять
+    -t, --field-separator=SEP    разделитель полей вместо перехода
+                                 от пробелов к непробелам
+    -s, --stable                 сохранять порядок строк с равными ключами
+    -u, --unique                 выводить только первую из равных строк
+    -S, --buffer-size=SIZE       размер буфера в памяти (по умолчанию 64M;
+                                 число без суффикса — в килобайтах)
+    -T, --temporary-directory=DIR
+                                 каталог временных файлов (по умолчанию
+                                 $TMPDIR или системный)
+
+    KEYDEF имеет вид F[.C][OPTS][,F[.C][OPTS]]: поле F и символ C начала
+    ключа и, необязательно, его конца (по умолчанию — конец строки).
+    OPTS — буквы b, f, h, n, r, V; если они заданы, глобальные параметры
+    порядка к ключу не применяются.
+
+EXIT STATUS
+    0 — успех, 2 — ошибка.
+
+EXAMPLES
+    sort -n numbers.txt
+        → числа по возрастанию
+
+    sort -t, -k2,2 -k1,1n data.csv
+        → по второму полю, при равенстве — по первому как по числу
+
+    du -h * | sort -h
+        → размеры с суффиксами по возрастанию
+
+    cut -d, -f2 data.csv | sort | uniq -c | sort -rn
+        → частоты значений второго поля`
+}
+
+var _ BuiltinCommand = (*SortCommand)(nil)
@@ -0,0 +1,412 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// sortOrder — способ сравнения ключей sort.
+type sortOrder struct {
+	numeric bool // -n: как числа
+	human   bool // -h: как числа с суффиксами K, M, G...
+	version bool // -V: как номера версий
+	reverse bool // -r: обратный порядок
+	fold    bool // -f: без учёта регистра
+	blanks  bool // -b: пропускать пробелы в начале ключа
+}
+
+// exclusive возвращает количество заданных взаимоисключающих способов сравнения.
+func (o sortOrder) exclusive() int {
+	count := 0
+	for _, set := range []bool{o.numeric, o.human, o.version} {
+		if set {
+			count++
+		}
+	}
+	return count
+}
+
+// trim убирает начальные пробелы ключа, если задан -b.
+func (o sortOrder) trim(key string) string {
+	if o.blanks {
+		return key[skipBlanks(key, 0):]
+	}
+	return key
+}
+
+// compare сравнивает два ключа с учётом -r.
+func (o sortOrder) compare(a, b string) int {
+	var c int
+	switch {
+	case o.numeric:
+		c = compareNumeric(a, b)
+	case o.human:
+		c = compareHuman(a, b)
+	case o.version:
+		c = compareVersion(a, b)
+	case o.fold:
+		c = compareFold(a, b)
+	default:
+		c = strings.Compare(a, b)
+	}
+	if o.reverse {
+		return -c
+	}
+	return c
+}
+
+// sortKey — ключ сортировки -k F[.C][OPTS][,F[.C][OPTS]].
+// Поля и символы нумеруются с 1; endField == 0 означает конец строки,
+// endChar == 0 — конец поля endField.
+type sortKey struct {
+	sortOrder
+	blanksEnd  bool // b в конце ключа: пропускать пробелы перед символом endChar
+	startField int
+	startChar  int
+	endField   int
+	endChar    int
+}
+
+// parseSortKey разбирает описание ключа -k.
+func parseSortKey(def string) (sortKey, error) {
+	key := sortKey{}
+	start, end, hasEnd := strings.Cut(def, ",")
+
+	field, char, opts, err := parseSortKeyPos(start, 1)
+	if err != nil || field == 0 || char == 0 {
+		return sortKey{}, fmt.Errorf("неверное описание ключа %q", def)
+	}
+	key.startField, key.startChar = field, char
+	if err := key.setOptions(opts, &key.blanks); err != nil {
+		return sortKey{}, fmt.Errorf("неверное описание ключа %q: %w", def, err)
+	}
+
+	if hasEnd {
+		field, char, opts, err := parseSortKeyPos(end, 0)
+		if err != nil || field == 0 {
+			return sortKey{}, fmt.Errorf("неверное описание ключа %q", def)
+		}
+		key.endField, key.endChar = field, char
+		if err := key.setOptions(opts, &key.blanksEnd); err != nil {
+			return sortKey{}, fmt.Errorf("неверное описание ключа %q: %w", def, err)
+		}
+	}
+
+	if key.exclusive() > 1 {
+		return sortKey{}, fmt.Errorf("неверное описание ключа %q: n, h и V несовместимы", def)
+	}
+	return key, nil
+}
+
+// parseSortKeyPos разбирает позицию ключа F[.C][OPTS]. Если C не указан,
+// возвращается defaultChar: 1 для начала ключа, 0 (конец поля) — для конца.
+func parseSortKeyPos(pos string, defaultChar int) (int, int, string, error) {
+	end := 0
+	for end < len(pos) && (isDigit(pos[end]) || pos[end] == '.') {
+		end++
+	}
+	fieldText, charText, hasChar := strings.Cut(pos[:end], ".")
+
+	field, err := strconv.Atoi(fieldText)
+	if err != nil {
+		return 0, 0, "", err
+	}
+	char := defaultChar
+	if hasChar {
+		if char, err = strconv.Atoi(charText); err != nil {
+			return 0, 0, "", err
+		}
+	}
+	return field, char, pos[end:], nil
+}
+
+// setOptions применяет к ключу буквы параметров. Буква b задаёт blanks
+// для той позиции ключа, после которой она записана.
+func (k *sortKey) setOptions(opts string, blanks *bool) error {
+	for _, opt := range opts {
+		switch opt {
+		case 'b':
+			*blanks = true
+		case 'f':
+			k.fold = true
+		case 'h':
+			k.human = true
+		case 'n':
+			k.numeric = true
+		case 'r':
+			k.reverse = true
+		case 'V':
+			k.version = true
+		default:
+			return fmt.Errorf("неизвестный параметр %q", opt)
+		}
+	}
+	return nil
+}
+
+// inherits сообщает, что у ключа нет собственных параметров
+// и он использует глобальные.
+func (k *sortKey) inherits() bool {
+	return k.sortOrder == sortOrder{} && !k.blanksEnd
+}
+
+// extract возвращает часть строки line, образующую ключ. separator —
+// разделитель полей; пустой разделитель означает, что поле начинается
+// с пробелов, за которыми следуют непробельные символы.
+func (k *sortKey) extract(line, separator string) string {
+	start := fieldStart(line, k.startField-1, separator)
+	if k.blanks {
+		start = skipBlanks(line, start)
+	}
+	start = min(start+k.startChar-1, len(line))
+
+	end := len(line)
+	if k.endField > 0 {
+		end = fieldStart(line, k.endField-1, separator)
+		if k.endChar == 0 {
+			end = fieldEnd(line, end, separator)
+		} else {
+			if k.blanksEnd {
+				end = skipBlanks(line, end)
+			}
+			end = min(end+k.endChar, len(line))
+		}
+	}
+	if end < start {
+		return ""
+	}
+	return line[start:end]
+}
+
+// fieldStart возвращает позицию начала поля с индексом field (с нуля).
+// Если полей меньше, возвращается длина строки.
+func fieldStart(line string, field int, separator string) int {
+	pos := 0
+	for ; field > 0 && pos < len(line); field-- {
+		if separator == "" {
+			pos = fieldEnd(line, pos, separator)
+			continue
+		}
+		next := strings.Index(line[pos:], separator)
+		if next < 0 {
+			return len(line)
+		}
+		pos += next + len(separator)
+	}
+	return pos
+}
+
+// fieldEnd возвращает позицию конца поля, начинающегося в pos.
+func fieldEnd(line string, pos int, separator string) int {
+	if separator != "" {
+		if next := strings.Index(line[pos:], separator); next >= 0 {
+			return pos + next
+		}
+		return len(line)
+	}
+
+	pos = skipBlanks(line, pos)
+	for pos < len(line) && !isBlank(line[pos]) {
+		pos++
+	}
+	return pos
+}
+
+// skipBlanks возвращает позицию первого непробельного символа line, начиная с pos.
+func skipBlanks(line string, pos int) int {
+	for pos < len(line) && isBlank(line[pos]) {
+		pos++
+	}
+	return pos
+}
+
+func isBlank(c byte) bool {
+	return c == ' ' || c == '\t'
+}
+
+// compareFold сравнивает строки побайтно без учёта регистра латинских букв.
+func compareFold(a, b string) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		ca, cb := upperASCII(a[i]), upperASCII(b[i])
+		if ca != cb {
+			return int(ca) - int(cb)
+		}
+	}
+	return len(a) - len(b)
+}
+
+func upperASCII(c byte) byte {
+	if c >= 'a' && c <= 'z' {
+		return c - 'a' + 'A'
+	}
+	return c
+}
+
+// sortNumber — число из начала ключа: знак, целая часть без ведущих нулей
+// и дробная часть без завершающих нулей. Такие записи можно сравнивать
+// как строки, не ограничивая точность.
+type sortNumber struct {
+	negative bool
+	integer  string
+	fraction string
+	rest     string // текст после числа
+}
+
+// parseSortNumber читает число в начале s после пробелов. Строка без
+// числа считается нулём, как в GNU sort.
+func parseSortNumber(s string) sortNumber {
+	s = s[skipBlanks(s, 0):]
+	var n sortNumber
+	if strings.HasPrefix(s, "-") {
+		n.negative, s = true, s[1:]
+	}
+
+	end := 0
+	for end < len(s) && isDigit(s[end]) {
+		end++
+	}
+	n.integer = strings.TrimLeft(s[:end], "0")
+	s = s[end:]
+
+	if strings.HasPrefix(s, ".") {
+		end = 1
+		for end < len(s) && isDigit(s[end]) {
+			end++
+		}
+		n.fraction = strings.TrimRight(s[1:end], "0")
+		s = s[end:]
+	}
+	n.rest = s
+
+	if n.integer == "" && n.fraction == "" {
+		n.negative = false
+	}
+	return n
+}
+
+// sign возвращает -1, 0 или 1 в зависимости от знака числа.
+func (n sortNumber) sign() int {
+	switch {
+	case n.integer == "" && n.fraction == "":
+		return 0
+	case n.negative:
+		return -1
+	}
+	return 1
+}
+
+// compareMagnitude сравнивает абсолютные величины чисел.
+func compareMagnitude(a, b sortNumber) int {
+	if len(a.integer) != len(b.integer) {
+		return len(a.integer) - len(b.integer)
+	}
+	if c := strings.Compare(a.integer, b.integer); c != 0 {
+		return c
+	}
+	return strings.Compare(a.fraction, b.fraction)
+}
+
+// compareSortNumbers сравнивает числа с учётом знака.
+func compareSortNumbers(a, b sortNumber) int {
+	if sa, sb := a.sign(), b.sign(); sa != sb {
+		return sa - sb
+	}
+	c := compareMagnitude(a, b)
+	if a.negative {
+		return -c
+	}
+	return c
+}
+
+// compareNumeric сравнивает ключи как числа (-n).
+func compareNumeric(a, b string) int {
+	return compareSortNumbers(parseSortNumber(a), parseSortNumber(b))
+}
+
+// humanSuffixes перечисляет суффиксы -h по возрастанию.
+const humanSuffixes = "KMGTPEZYRQ"
+
+// humanSuffix возвращает порядковый номер суффикса после числа (0 — без суффикса).
+func humanSuffix(rest string) int {
+	if rest == "" {
+		return 0
+	}
+	if rest[0] == 'k' {
+		return 1
+	}
+	return strings.IndexByte(humanSuffixes, rest[0]) + 1
+}
+
+// compareHuman сравнивает числа с суффиксами (-h): сначала по знаку,
+// затем по суффиксу, затем по значению, поэтому 2K < 1M.
+func compareHuman(a, b string) int {
+	na, nb := parseSortNumber(a), parseSortNumber(b)
+	if sa, sb := na.sign(), nb.sign(); sa != sb {
+		return sa - sb
+	}
+	if ua, ub := humanSuffix(na.rest), humanSuffix(nb.rest); ua != ub {
+		if na.negative {
+			return ub - ua
+		}
+		return ua - ub
+	}
+	return compareSortNumbers(na, nb)
+}
+
+// compareVersion сравнивает номера версий (-V) по алгоритму Debian:
+// цифровые части сравниваются как числа, остальные — посимвольно,
+// причём буквы идут раньше прочих символов, а "~" — раньше всего,
+// даже конца строки.
+func compareVersion(a, b string) int {
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		for i < len(a) && !isDigit(a[i]) || j < len(b) && !isDigit(b[j]) {
+			ca, cb := versionOrder(a, i), versionOrder(b, j)
+			if ca != cb {
+				return ca - cb
+			}
+			i++
+			j++
+		}
+
+		for i < len(a) && a[i] == '0' {
+			i++
+		}
+		for j < len(b) && b[j] == '0' {
+			j++
+		}
+		first := 0
+		for i < len(a) && isDigit(a[i]) && j < len(b) && isDigit(b[j]) {
+			if first == 0 {
+				first = int(a[i]) - int(b[j])
+			}
+			i++
+			j++
+		}
+		switch {
+		case i < len(a) && isDigit(a[i]):
+			return 1
+		case j < len(b) && isDigit(b[j]):
+			return -1
+		case first != 0:
+			return first
+		}
+	}
+	return 0
+}
+
+// versionOrder возвращает вес символа s[i] для compareVersion.
+func versionOrder(s string, i int) int {
+	if i >= len(s) || isDigit(s[i]) {
+		return 0
+	}
+	c := s[i]
+	switch {
+	case c == '~':
+		return -1
+	case c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z':
+		return int(c)
+	}
+	return int(c) + 256
+}
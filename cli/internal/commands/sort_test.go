@@ -0,0 +1,153 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSortCommand_Orders(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		input    string
+		expected string
+	}{
+		{"default", nil, "b\na\nC\nc\n", "C\na\nb\nc\n"},
+		{"reverse", []string{"-r"}, "b\na\nc\n", "c\nb\na\n"},
+		{"no trailing newline", nil, "b\na", "a\nb\n"},
+		{"numeric", []string{"-n"}, "10\n9\n-1\n1.5\nx\n0100\n", "-1\nx\n1.5\n9\n10\n0100\n"},
+		{"numeric reverse", []string{"-rn"}, "3 c\n10 a\n2 b\n", "10 a\n3 c\n2 b\n"},
+		{"numeric equal keys", []string{"-n"}, "1 b\n01 a\n", "01 a\n1 b\n"},
+		{
+			"huge numbers", []string{"-n"},
+			"123456789012345678901234567890\n99999999999999999999\n",
+			"99999999999999999999\n123456789012345678901234567890\n",
+		},
+		{"human", []string{"-h"}, "1G\n2K\n512\n1M\n-1K\n3K\n", "-1K\n512\n2K\n3K\n1M\n1G\n"},
+		{"version", []string{"-V"}, "v1.10\nv1.9\nv1.2.3\nv1.2\nv1.2~rc1\n", "v1.2~rc1\nv1.2\nv1.2.3\nv1.9\nv1.10\n"},
+		{"fold", []string{"-f"}, "b\nA\na\nB\n", "A\na\nB\nb\n"},
+		{"unique", []string{"-u"}, "b\na\nb\na\n", "a\nb\n"},
+		{"unique numeric", []string{"-un"}, "1\n01\n2\n", "1\n2\n"},
+		{"key", []string{"-k2"}, "x b\ny a\n", "y a\nx b\n"},
+		{"key numeric", []string{"-k2n"}, "a 10\nb 9\n", "b 9\na 10\n"},
+		{"key separator", []string{"-t,", "-k2,2"}, "1,b,z\n2,a,y\n3,b,a\n", "2,a,y\n1,b,z\n3,b,a\n"},
+		{"two keys", []string{"-t,", "-k2,2", "-k1,1nr"}, "1,b\n2,a\n3,b\n", "2,a\n3,b\n1,b\n"},
+		{"key chars", []string{"-k1.2,1.2"}, "ab\nba\naa\n", "aa\nba\nab\n"},
+		{"stable", []string{"-s", "-k1,1"}, "b 2\na 2\nb 1\na 1\n", "a 2\na 1\nb 2\nb 1\n"},
+		{"last resort", []string{"-k1,1"}, "b 2\na 2\nb 1\na 1\n", "a 1\na 2\nb 1\nb 2\n"},
+		{"blanks", []string{"-b", "-k2"}, "x   b\ny a\n", "y a\nx   b\n"},
+		{"default fields keep blanks", []string{"-k2"}, "x   b\ny a\n", "x   b\ny a\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, stderr, status := runCommand(t, &SortCommand{}, "", tt.input, tt.args...)
+			if status != 0 || stderr != "" {
+				t.Fatalf("ожидался код 0 без ошибок, получено %d: %q", status, stderr)
+			}
+			if out != tt.expected {
+				t.Errorf("ожидалось %q, получено %q", tt.expected, out)
+			}
+		})
+	}
+}
+
+func TestSortCommand_Files(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("c\na\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "b.txt"), []byte("b\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, _, status := runCommand(t, &SortCommand{}, dir, "d\n", "a.txt", "-", "b.txt")
+	if status != 0 || out != "a\nb\nc\nd\n" {
+		t.Errorf("ожидалось %q с кодом 0, получено %q с кодом %d", "a\nb\nc\nd\n", out, status)
+	}
+
+	_, stderr, status := runCommand(t, &SortCommand{}, dir, "", "missing.txt")
+	if status != sortErrorCode || !strings.Contains(stderr, "missing.txt") {
+		t.Errorf("ожидался код %d и ошибка о missing.txt, получено %d: %q", sortErrorCode, status, stderr)
+	}
+}
+
+func TestSortCommand_ExternalMerge(t *testing.T) {
+	tempDir := t.TempDir()
+	var input strings.Builder
+	for i := 5000; i > 0; i-- {
+		// Дубликаты с разным хвостом проверяют устойчивость слияния
+		fmt.Fprintf(&input, "%d %d\n", i%1000, i)
+	}
+
+	// Буфер 1K заставляет сортировать по частям во временных файлах
+	args := []string{"-S", "1", "-T", tempDir, "-s", "-k1,1n"}
+	out, stderr, status := runCommand(t, &SortCommand{}, "", input.String(), args...)
+	if status != 0 || stderr != "" {
+		t.Fatalf("ожидался код 0 без ошибок, получено %d: %q", status, stderr)
+	}
+	expected, _, _ := runCommand(t, &SortCommand{}, "", input.String(), "-s", "-k1,1n")
+	if out != expected {
+		t.Fatalf("внешняя сортировка дала другой результат")
+	}
+	if !strings.HasPrefix(out, "0 5000\n0 4000\n") {
+		t.Errorf("нарушен порядок равных ключей: %q", out[:40])
+	}
+
+	unique, _, _ := runCommand(t, &SortCommand{}, "", input.String(), "-S", "2", "-T", tempDir, "-un", "-k1,1")
+	if lines := strings.Count(unique, "\n"); lines != 1000 {
+		t.Errorf("ожидалось 1000 уникальных строк, получено %d", lines)
+	}
+
+	entries, err := os.ReadDir(tempDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("временные файлы не удалены: %d", len(entries))
+	}
+}
+
+func TestSortCommand_InvalidArgs(t *testing.T) {
+	for _, args := range [][]string{
+		{"-k", "0"},
+		{"-k", "1x"},
+		{"-t", "ab"},
+		{"-n", "-h"},
+		{"-S", "lots"},
+		{"--bogus"},
+	} {
+		_, stderr, status := runCommand(t, &SortCommand{}, "", "", args...)
+		if status != sortErrorCode || stderr == "" {
+			t.Errorf("%v: ожидался код %d и сообщение об ошибке, получено %d: %q", args, sortErrorCode, status, stderr)
+		}
+	}
+}
+
+func TestCompareVersion(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"1.9", "1.10", -1},
+		{"1.01", "1.1", 0},
+		{"1.0~rc1", "1.0", -1},
+		{"1.0a", "1.0", 1},
+		{"1.0a", "1.0+", -1},
+		{"file2.txt", "file10.txt", -1},
+	}
+	for _, tt := range tests {
+		c := compareVersion(tt.a, tt.b)
+		if c < 0 {
+			c = -1
+		} else if c > 0 {
+			c = 1
+		}
+		if c != tt.expected {
+			t.Errorf("compareVersion(%q, %q): ожидалось %d, получено %d", tt.a, tt.b, tt.expected, c)
+		}
+	}
+}
@@ -0,0 +1,589 @@
+package commands
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	customErrors "github.com/ggerlakh/software-design-mhs-itmo/cli/internal/errors"
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/linereader"
+)
+
+// TailCommand реализует встроенную команду "tail".
+// Она выводит конец файлов или stdin и может следить за дописыванием
+// в файлы (-f, -F).
+type TailCommand struct{}
+
+// Режимы слежения за файлами.
+const (
+	followNone       = iota
+	followDescriptor // -f: читать из открытого файла, даже если его переименовали
+	followName       // -F: заново открывать файл по имени при замене (ротация логов)
+)
+
+// tailInterruptedCode — код возврата tail -f, прерванного по Ctrl-C
+// или закрытием следующей команды пайплайна.
+const tailInterruptedCode = 130
+
+// tailOptions — параметры tail.
+type tailOptions struct {
+	bytes     bool          // считать байты (-c), а не строки (-n)
+	count     int64         // количество строк или байт
+	fromStart bool          // count отсчитывается от начала (+NUM)
+	quiet     bool          // не печатать заголовки (-q)
+	verbose   bool          // печатать заголовки всегда (-v)
+	follow    int           // режим слежения: followNone, followDescriptor, followName
+	retry     bool          // пытаться открыть недоступный файл снова (--retry, -F)
+	sleep     time.Duration // пауза между проверками файлов (-s)
+}
+
+// followFlag — значение флага --follow[={name|descriptor}].
+type followFlag struct {
+	mode *int
+}
+
+func (f followFlag) String() string {
+	if f.mode != nil && *f.mode == followName {
+		return "name"
+	}
+	return "descriptor"
+}
+
+func (f followFlag) Set(value string) error {
+	switch value {
+	case "true", "descriptor":
+		*f.mode = followDescriptor
+	case "name":
+		*f.mode = followName
+	default:
+		return fmt.Errorf("неверный режим слежения %q", value)
+	}
+	return nil
+}
+
+// IsBoolFlag позволяет писать --follow без значения.
+func (f followFlag) IsBoolFlag() bool {
+	return true
+}
+
+// Name возвращает имя команды.
+func (t *TailCommand) Name() string {
+	return "tail"
+}
+
+// Exec выполняет команду tail.
+//
+// Синтаксис:
+//
+//	tail [-n [+]NUM | -c [+]NUM | -NUM] [-f | -F] [-s SEC] [-q | -v] [FILE...]
+//
+// Если файлы не указаны или указан "-", читается stdin. Если файлов
+// несколько, перед выводом каждого печатается заголовок "==> FILE <==".
+//
+// С -f после вывода конца файлов команда продолжает выводить данные,
+// дописываемые в файлы, пока её не прервут: по Ctrl-C или когда
+// следующая команда пайплайна завершится. Тогда возвращается
+// ExitStatusError с кодом 130. Стандартный ввод не отслеживается: если
+// читался только он, tail -f завершается, как без -f.
+//
+// Примеры:
+//
+//	tail file.txt          → последние 10 строк
+//	tail -n +3 file.txt    → все строки, начиная с третьей
+//	tail -c 100 file.bin   → последние 100 байт
+//	tail -F app.log        → следить за логом, в том числе после ротации
+func (t *TailCommand) Exec(args []string, ctx *CommandContext) error {
+	opts, files, err := parseTailArgs(args)
+	if err != nil {
+		if _, writeErr := fmt.Fprintf(ctx.Stderr, "tail: %v\n", err); writeErr != nil {
+			return writeErr
+		}
+		return &customErrors.ExitStatusError{Code: 1}
+	}
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+
+	f := &tailFollower{
+		opts:    opts,
+		ctx:     ctx,
+		headers: opts.verbose || len(files) > 1 && !opts.quiet,
+	}
+	defer f.close()
+
+	for _, name := range files {
+		if err := f.add(name); err != nil {
+			return err
+		}
+	}
+
+	// За stdin tail не следит: если читался только он, как и GNU tail,
+	// завершаемся без сообщения "не осталось файлов"
+	stdinOnly := len(files) == 1 && files[0] == "-"
+	if opts.follow != followNone && !stdinOnly {
+		return f.run()
+	}
+	if f.failed {
+		return &customErrors.ExitStatusError{Code: 1}
+	}
+	return nil
+}
+
+// parseTailArgs разбирает аргументы tail.
+func parseTailArgs(args []string) (*tailOptions, []string, error) {
+	fs := flag.NewFlagSet("tail", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	lines := fs.String("n", "", "количество строк")
+	fs.StringVar(lines, "lines", "", "количество строк")
+	bytes := fs.String("c", "", "количество байт")
+	fs.StringVar(bytes, "bytes", "", "количество байт")
+	sleep := fs.String("s", "1", "пауза между проверками в секундах")
+	fs.StringVar(sleep, "sleep-interval", "1", "пауза между проверками в секундах")
+	opts := &tailOptions{}
+	fs.BoolVar(&opts.quiet, "q", false, "не печатать заголовки")
+	fs.BoolVar(&opts.quiet, "quiet", false, "не печатать заголовки")
+	fs.BoolVar(&opts.quiet, "silent", false, "не печатать заголовки")
+	fs.BoolVar(&opts.verbose, "v", false, "всегда печатать заголовки")
+	fs.BoolVar(&opts.verbose, "verbose", false, "всегда печатать заголовки")
+	fs.BoolVar(&opts.retry, "retry", false, "повторять попытки открыть файл")
+	fs.Var(followFlag{&opts.follow}, "f", "следить за дописыванием")
+	fs.Var(followFlag{&opts.follow}, "follow", "следить за дописыванием")
+	followByName := fs.Bool("F", false, "то же, что --follow=name --retry")
+
+	if err := fs.Parse(numericFlags(splitShortFlags(args, headValueFlags), "n", headValueFlags)); err != nil {
+		return nil, nil, fmt.Errorf("ошибка разбора флагов: %w", err)
+	}
+	if *followByName {
+		opts.follow, opts.retry = followName, true
+	}
+
+	count, countBytes, err := parseCountFlags(fs, *lines, *bytes)
+	if err != nil {
+		return nil, nil, err
+	}
+	opts.bytes = countBytes
+	opts.count, opts.fromStart = count.value, count.sign == '+'
+	if opts.fromStart && opts.count > 0 {
+		// +1 — с первой строки, то есть ничего не пропускать; +0 работает так же
+		opts.count--
+	}
+
+	seconds, err := strconv.ParseFloat(*sleep, 64)
+	if err != nil || seconds < 0 {
+		return nil, nil, fmt.Errorf("неверный интервал: %q", *sleep)
+	}
+	opts.sleep = time.Duration(seconds * float64(time.Second))
+
+	return opts, fs.Args(), nil
+}
+
+// tailFile — файл, за которым следит tail.
+type tailFile struct {
+	name   string
+	file   *os.File    // nil, пока файл недоступен
+	info   os.FileInfo // сведения об открытом файле, для обнаружения замены
+	offset int64       // сколько байт файла уже выведено
+}
+
+// tailFollower выводит конец файлов и следит за их изменением.
+type tailFollower struct {
+	opts    *tailOptions
+	ctx     *CommandContext
+	headers bool
+	files   []*tailFile
+	current *tailFile // файл, чей заголовок напечатан последним
+	failed  bool      // не удалось открыть или прочитать какой-то файл
+}
+
+// add выводит конец файла name и, в режиме слежения, запоминает его.
+// Ошибки открытия и чтения печатаются в stderr; возвращается только
+// ошибка записи в stdout.
+func (f *tailFollower) add(name string) error {
+	if name == "-" {
+		return f.addStdin()
+	}
+
+	tf := &tailFile{name: name}
+	file, err := os.Open(f.ctx.ResolvePath(name))
+	if err != nil {
+		f.failed = true
+		f.warn("не удалось открыть '%s' для чтения: %v", name, fileError(err))
+		if f.opts.retry && f.opts.follow != followNone {
+			f.files = append(f.files, tf)
+		}
+		return nil
+	}
+	tf.file = file
+
+	source := &catReader{r: file}
+	if err := f.header(tf); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := f.opts.copy(f.ctx.Stdout, source, file); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if source.err != nil {
+		f.failed = true
+		f.warn("ошибка чтения %s: %v", name, fileError(source.err))
+		_ = file.Close()
+		return nil
+	}
+
+	if f.opts.follow == followNone {
+		_ = file.Close()
+		return nil
+	}
+	tf.offset, _ = file.Seek(0, io.SeekCurrent)
+	tf.info, _ = file.Stat()
+	f.files = append(f.files, tf)
+	return nil
+}
+
+// addStdin выводит конец стандартного ввода. За stdin tail не следит.
+func (f *tailFollower) addStdin() error {
+	if err := f.header(&tailFile{name: stdinName}); err != nil {
+		return err
+	}
+	file, _ := f.ctx.Stdin.(*os.File)
+	source := &catReader{r: f.ctx.Stdin}
+	if err := f.opts.copy(f.ctx.Stdout, source, file); err != nil {
+		return err
+	}
+	if source.err != nil {
+		f.failed = true
+		f.warn("ошибка чтения %s: %v", stdinName, fileError(source.err))
+	}
+	return nil
+}
+
+// header печатает заголовок файла tf, если заголовки нужны
+// и последним выводился другой файл.
+func (f *tailFollower) header(tf *tailFile) error {
+	if !f.headers || f.current == tf {
+		return nil
+	}
+	separate := f.current != nil
+	f.current = tf
+	return printFileHeader(f.ctx.Stdout, tf.name, separate)
+}
+
+// warn печатает сообщение tail в stderr.
+func (f *tailFollower) warn(format string, args ...any) {
+	_, _ = fmt.Fprintf(f.ctx.Stderr, "tail: "+format+"\n", args...)
+}
+
+// close закрывает все открытые файлы.
+func (f *tailFollower) close() {
+	for _, tf := range f.files {
+		if tf.file != nil {
+			_ = tf.file.Close()
+		}
+	}
+}
+
+// run следит за файлами, пока команду не прервут. Раз в opts.sleep
+// выводятся данные, дописанные в файлы; в режиме -F файлы, которые были
+// заменены или появились, открываются заново по имени.
+func (f *tailFollower) run() error {
+	if len(f.files) == 0 {
+		f.warn("не осталось файлов для слежения")
+		return &customErrors.ExitStatusError{Code: 1}
+	}
+
+	for {
+		select {
+		case <-f.ctx.Done():
+			return &customErrors.ExitStatusError{Code: tailInterruptedCode}
+		case <-time.After(f.opts.sleep):
+		}
+
+		for i, tf := range f.files {
+			if f.opts.follow == followName || tf.file == nil {
+				if err := f.reopen(i); err != nil {
+					return err
+				}
+			}
+			if tf.file == nil {
+				continue
+			}
+			if err := f.check(i); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// reopen проверяет, что по имени файла i находится тот же файл, что
+// открыт. Если файл заменён (ротация), остаток старого файла выводится,
+// а новый открывается и выводится с начала.
+func (f *tailFollower) reopen(i int) error {
+	tf := f.files[i]
+	info, err := os.Stat(f.ctx.ResolvePath(tf.name))
+	if err != nil {
+		if tf.file != nil && f.opts.follow == followName {
+			if err := f.check(i); err != nil {
+				return err
+			}
+			f.warn("'%s' стал недоступен: %v", tf.name, fileError(err))
+			_ = tf.file.Close()
+			tf.file, tf.info = nil, nil
+		}
+		return nil
+	}
+	if tf.file != nil && os.SameFile(info, tf.info) {
+		return nil
+	}
+
+	file, err := os.Open(f.ctx.ResolvePath(tf.name))
+	if err != nil {
+		return nil
+	}
+	if tf.file != nil {
+		if err := f.check(i); err != nil {
+			_ = file.Close()
+			return err
+		}
+		_ = tf.file.Close()
+		f.warn("'%s' был заменён; следим за новым файлом", tf.name)
+	} else {
+		f.warn("'%s' появился; следим за новым файлом", tf.name)
+	}
+	tf.file, tf.offset = file, 0
+	tf.info, _ = file.Stat()
+	return nil
+}
+
+// check выводит данные, дописанные в файл i с прошлой проверки.
+// Если файл стал короче уже выведенного, он был усечён, и вывод
+// продолжается с его начала.
+func (f *tailFollower) check(i int) error {
+	tf := f.files[i]
+	info, err := tf.file.Stat()
+	if err != nil {
+		return nil
+	}
+	if info.Size() < tf.offset {
+		f.warn("%s: файл усечён", tf.name)
+		if _, err := tf.file.Seek(0, io.SeekStart); err != nil {
+			return nil
+		}
+		tf.offset = 0
+	}
+	if info.Size() == tf.offset {
+		return nil
+	}
+
+	if err := f.header(tf); err != nil {
+		return err
+	}
+	n, err := io.Copy(f.ctx.Stdout, &catReader{r: tf.file})
+	tf.offset += n
+	return err
+}
+
+// copy выводит конец r в w согласно параметрам. Если r — обычный файл
+// (file не nil), конец находится чтением с конца, без чтения всего файла.
+func (o *tailOptions) copy(w io.Writer, r io.Reader, file *os.File) error {
+	if o.fromStart {
+		return copyFromStart(w, r, o.bytes, o.count)
+	}
+
+	if file != nil {
+		if info, err := file.Stat(); err == nil && info.Mode().IsRegular() {
+			if start, err := o.tailOffset(file, info.Size()); err == nil {
+				if _, err := file.Seek(start, io.SeekStart); err == nil {
+					_, err := io.Copy(w, r)
+					return err
+				}
+			}
+		}
+	}
+
+	if o.bytes {
+		return copyLastBytes(w, r, o.count)
+	}
+	return copyLastLines(w, r, o.count)
+}
+
+// tailOffset возвращает смещение в файле размера size, с которого
+// начинаются последние count строк или байт.
+func (o *tailOptions) tailOffset(file *os.File, size int64) (int64, error) {
+	if o.bytes {
+		return max(size-o.count, 0), nil
+	}
+	if o.count == 0 {
+		return size, nil
+	}
+
+	count := o.count
+	buf := make([]byte, linereader.BufferSize)
+	for pos := size; pos > 0; {
+		n := min(pos, int64(len(buf)))
+		pos -= n
+		if _, err := file.ReadAt(buf[:n], pos); err != nil {
+			return 0, err
+		}
+		for i := n - 1; i >= 0; i-- {
+			// Перевод строки в самом конце файла завершает последнюю строку,
+			// а не отделяет её от следующей
+			if buf[i] != '\n' || pos+i == size-1 {
+				continue
+			}
+			count--
+			if count == 0 {
+				return pos + i + 1, nil
+			}
+		}
+	}
+	return 0, nil
+}
+
+// copyFromStart пропускает первые skip строк или байт r и выводит остальное.
+func copyFromStart(w io.Writer, r io.Reader, bytes bool, skip int64) error {
+	if bytes {
+		if _, err := io.CopyN(io.Discard, r, skip); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		_, err := io.Copy(w, r)
+		return err
+	}
+
+	lines := linereader.New(r)
+	for {
+		line, err := lines.ReadLine()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if skip > 0 {
+			skip--
+		} else if _, writeErr := w.Write(line); writeErr != nil {
+			return writeErr
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// copyLastLines выводит последние count строк r, которые нельзя
+// прочитать с конца (канал, терминал). В памяти хранится не больше
+// count строк.
+func copyLastLines(w io.Writer, r io.Reader, count int64) error {
+	lines := linereader.New(r)
+	ring := make([][]byte, 0, min(count, 1024))
+	next := 0
+	for {
+		line, err := lines.ReadLine()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if count == 0 {
+			continue
+		}
+
+		if int64(len(ring)) < count {
+			ring = append(ring, append([]byte(nil), line...))
+			continue
+		}
+		ring[next] = append(ring[next][:0], line...)
+		next = (next + 1) % len(ring)
+	}
+
+	for i := range ring {
+		if _, err := w.Write(ring[(next+i)%len(ring)]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyLastBytes выводит последние count байт r, которые нельзя
+// прочитать с конца.
+func copyLastBytes(w io.Writer, r io.Reader, count int64) error {
+	var tail []byte
+	chunk := make([]byte, linereader.BufferSize)
+	for {
+		n, err := r.Read(chunk)
+		tail = append(tail, chunk[:n]...)
+		if excess := int64(len(tail)) - count; excess > 0 {
+			tail = append(tail[:0], tail[excess:]...)
+		}
+
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+	_, err := w.Write(tail)
+	return err
+}
+
+// Help возвращает справку по команде tail.
+func (t *TailCommand) Help() string {
+	return `NAME
+    tail - выводит конец файлов
+
+SYNOPSIS
+    tail [OPTION]... [FILE]...
+
+DESCRIPTION
+    Выводит последние 10 строк каждого файла. Если файл не указан или
+    указан "-", читается стандартный ввод. Если файлов несколько, перед
+    каждым печатается заголовок "==> FILE <==".
+
+    С -f команда продолжает выводить строки, дописываемые в файлы, пока
+    её не прервут по Ctrl-C или пока не завершится следующая команда
+    пайплайна. С -F файл, заменённый новым (например, при ротации логов),
+    открывается заново по имени, а усечённый файл выводится с начала.
+    За стандартным вводом tail не следит: cat f | tail -f выводит конец
+    ввода и завершается.
+
+OPTIONS
+    -n, --lines=[+]NUM        вывести последние NUM строк; с "+" — все
+                              строки, начиная со строки NUM
+    -c, --bytes=[+]NUM        вывести последние NUM байт; с "+" — все
+                              байты, начиная с байта NUM
+    -NUM                      то же, что -n NUM
+    -f, --follow[={name|descriptor}]
+                              выводить дописываемые данные; по умолчанию
+                              следить за открытым файлом (descriptor)
+    -F                        то же, что --follow=name --retry
+    --retry                   пытаться открыть недоступный файл снова
+    -s, --sleep-interval=SEC  пауза между проверками файлов (по умолчанию 1)
+    -q, --quiet               не печатать заголовки
+    -v, --verbose             всегда печатать заголовки
+
+    NUM может иметь суффикс-множитель: b (512), kB (1000), K (1024),
+    MB, M, GB, G.
+
+EXIT STATUS
+    0 — успех, 1 — неверные аргументы или ошибка чтения файла,
+    130 — слежение (-f) прервано.
+
+EXAMPLES
+    tail -n 3 file.txt
+        → последние 3 строки
+
+    tail -n +2 data.csv
+        → все строки, кроме заголовка
+
+    tail -f app.log | grep ERROR
+        → новые строки лога с ошибками`
+}
+
+var _ BuiltinCommand = (*TailCommand)(nil)
@@ -0,0 +1,289 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
+)
+
+func TestTailCommand_Counts(t *testing.T) {
+	input := numberedLines(1, 12)
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "in.txt"), []byte(input), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		args     []string
+		expected string
+	}{
+		{nil, numberedLines(3, 12)},
+		{[]string{"-n", "3"}, "10\n11\n12\n"},
+		{[]string{"-3"}, "10\n11\n12\n"},
+		{[]string{"-n", "-2"}, "11\n12\n"},
+		{[]string{"-n", "+11"}, "11\n12\n"},
+		{[]string{"-n", "+1"}, input},
+		{[]string{"-n", "+0"}, input},
+		{[]string{"-n", "0"}, ""},
+		{[]string{"-n", "20"}, input},
+		{[]string{"-c", "6"}, "11\n12\n"},
+		{[]string{"-c", "5"}, "1\n12\n"},
+		{[]string{"-c", "+25"}, "12\n"},
+		{[]string{"-c", "0"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			// Файл читается с конца, stdin — целиком: результат должен совпадать
+			for _, source := range []string{"in.txt", "-"} {
+				args := append(append([]string(nil), tt.args...), source)
+				out, stderr, status := runCommand(t, &TailCommand{}, dir, input, args...)
+				if status != 0 || stderr != "" {
+					t.Fatalf("%s: ожидался код 0 без ошибок, получено %d: %q", source, status, stderr)
+				}
+				if out != tt.expected {
+					t.Errorf("%s: ожидалось %q, получено %q", source, tt.expected, out)
+				}
+			}
+		})
+	}
+}
+
+func TestTailCommand_LastLineWithoutNewline(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "in.txt"), []byte("a\nb\nc"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, source := range []string{"in.txt", "-"} {
+		out, _, _ := runCommand(t, &TailCommand{}, dir, "a\nb\nc", "-n", "2", source)
+		if out != "b\nc" {
+			t.Errorf("%s: ожидалось %q, получено %q", source, "b\nc", out)
+		}
+	}
+}
+
+func TestTailCommand_LongFile(t *testing.T) {
+	dir := t.TempDir()
+	line := strings.Repeat("x", 1000) + "\n"
+	input := strings.Repeat(line, 100) + "last\n"
+	if err := os.WriteFile(filepath.Join(dir, "in.txt"), []byte(input), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, _, _ := runCommand(t, &TailCommand{}, dir, "", "-n", "40", "in.txt")
+	expected := strings.Repeat(line, 39) + "last\n"
+	if out != expected {
+		t.Errorf("ожидалось %d байт, получено %d", len(expected), len(out))
+	}
+}
+
+func TestTailCommand_MultipleFiles(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a1\na2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "b.txt"), []byte("b1\nb2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, stderr, status := runCommand(t, &TailCommand{}, dir, "", "-n1", "a.txt", "missing.txt", "b.txt")
+	expected := "==> a.txt <==\na2\n\n==> b.txt <==\nb2\n"
+	if out != expected {
+		t.Errorf("ожидалось %q, получено %q", expected, out)
+	}
+	if status != 1 || !strings.Contains(stderr, "missing.txt") {
+		t.Errorf("ожидался код 1 и ошибка о missing.txt, получено %d: %q", status, stderr)
+	}
+}
+
+// syncBuffer — буфер, в который можно писать из другой горутины.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// startTail запускает tail в отдельной горутине. Возвращённая функция
+// прерывает команду и возвращает её код возврата.
+func startTail(t *testing.T, dir string, stdout, stderr *syncBuffer, args ...string) func() int {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- (&TailCommand{}).Exec(args, &CommandContext{
+			Stdin:   strings.NewReader(""),
+			Stdout:  stdout,
+			Stderr:  stderr,
+			Vars:    session.NewVariables(nil),
+			Dir:     dir,
+			Context: ctx,
+		})
+	}()
+
+	return func() int {
+		cancel()
+		select {
+		case err := <-done:
+			return testStatus(err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("tail -f не завершился после отмены")
+			return -1
+		}
+	}
+}
+
+// waitOutput ждёт, пока buf не станет равен expected.
+func waitOutput(t *testing.T, buf *syncBuffer, expected string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for buf.String() != expected {
+		if time.Now().After(deadline) {
+			t.Fatalf("ожидалось %q, получено %q", expected, buf.String())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func appendFile(t *testing.T, path, text string) {
+	t.Helper()
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	if _, err := file.WriteString(text); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTailCommand_Follow(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "log.txt")
+	appendFile(t, path, "1\n2\n3\n")
+
+	var stdout, stderr syncBuffer
+	stop := startTail(t, dir, &stdout, &stderr, "-f", "-n", "2", "-s", "0.01", "log.txt")
+	waitOutput(t, &stdout, "2\n3\n")
+
+	appendFile(t, path, "4\n")
+	waitOutput(t, &stdout, "2\n3\n4\n")
+
+	// Усечение: вывод продолжается с начала файла
+	if err := os.WriteFile(path, []byte("x\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	waitOutput(t, &stdout, "2\n3\n4\nx\n")
+
+	if status := stop(); status != tailInterruptedCode {
+		t.Errorf("ожидался код %d, получено %d", tailInterruptedCode, status)
+	}
+	if !strings.Contains(stderr.String(), "усечён") {
+		t.Errorf("ожидалось сообщение об усечении, получено %q", stderr.String())
+	}
+}
+
+func TestTailCommand_FollowNameRotation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	appendFile(t, path, "old\n")
+
+	var stdout, stderr syncBuffer
+	stop := startTail(t, dir, &stdout, &stderr, "-F", "-s", "0.01", "app.log")
+	waitOutput(t, &stdout, "old\n")
+
+	// Ротация: старый файл переименовывается, под тем же именем создаётся новый
+	appendFile(t, path, "old tail\n")
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	appendFile(t, path, "new\n")
+	waitOutput(t, &stdout, "old\nold tail\nnew\n")
+
+	appendFile(t, path+".1", "ignored\n")
+	appendFile(t, path, "next\n")
+	waitOutput(t, &stdout, "old\nold tail\nnew\nnext\n")
+
+	stop()
+	if !strings.Contains(stderr.String(), "app.log") {
+		t.Errorf("ожидалось сообщение о замене файла, получено %q", stderr.String())
+	}
+}
+
+func TestTailCommand_FollowDescriptor(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	appendFile(t, path, "old\n")
+
+	var stdout, stderr syncBuffer
+	stop := startTail(t, dir, &stdout, &stderr, "--follow", "-s", "0.01", "app.log")
+	waitOutput(t, &stdout, "old\n")
+
+	// Без -F tail продолжает читать переименованный файл
+	if err := os.Rename(path, path+".1"); err != nil {
+		t.Fatal(err)
+	}
+	appendFile(t, path+".1", "moved\n")
+	appendFile(t, path, "new\n")
+	waitOutput(t, &stdout, "old\nmoved\n")
+	stop()
+}
+
+func TestTailCommand_FollowMultipleFiles(t *testing.T) {
+	dir := t.TempDir()
+	appendFile(t, filepath.Join(dir, "a"), "a1\n")
+	appendFile(t, filepath.Join(dir, "b"), "b1\n")
+
+	var stdout, stderr syncBuffer
+	stop := startTail(t, dir, &stdout, &stderr, "-f", "-s", "0.01", "a", "b")
+	head := "==> a <==\na1\n\n==> b <==\nb1\n"
+	waitOutput(t, &stdout, head)
+
+	appendFile(t, filepath.Join(dir, "b"), "b2\n")
+	waitOutput(t, &stdout, head+"b2\n")
+
+	appendFile(t, filepath.Join(dir, "a"), "a2\n")
+	waitOutput(t, &stdout, head+"b2\n\n==> a <==\na2\n")
+	stop()
+}
+
+func TestTailCommand_FollowRetry(t *testing.T) {
+	dir := t.TempDir()
+
+	var stdout, stderr syncBuffer
+	stop := startTail(t, dir, &stdout, &stderr, "-F", "-s", "0.01", "later.log")
+	appendFile(t, filepath.Join(dir, "later.log"), "hello\n")
+	waitOutput(t, &stdout, "hello\n")
+	stop()
+
+	_, stderrText, status := runCommand(t, &TailCommand{}, dir, "", "-f", "missing.log")
+	if status != 1 || !strings.Contains(stderrText, "missing.log") {
+		t.Errorf("ожидался код 1 и ошибка о missing.log, получено %d: %q", status, stderrText)
+	}
+}
+
+func TestTailCommand_FollowStdin(t *testing.T) {
+	// cat f | tail -f: за stdin tail не следит и завершается успешно
+	out, stderr, status := runCommand(t, &TailCommand{}, t.TempDir(), "1\n2\n", "-f", "-n", "1")
+	if out != "2\n" || stderr != "" || status != 0 {
+		t.Errorf("ожидалось %q с кодом 0, получено %q (код %d, stderr %q)", "2\n", out, status, stderr)
+	}
+}
@@ -0,0 +1,185 @@
+package commands
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	customErrors "github.com/ggerlakh/software-design-mhs-itmo/cli/internal/errors"
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/linereader"
+)
+
+// TeeCommand реализует встроенную команду "tee".
+// Она копирует стандартный ввод в стандартный вывод и в файлы.
+type TeeCommand struct{}
+
+// teeOutput — выход tee: стандартный вывод (пустое name) или файл.
+type teeOutput struct {
+	name string
+	w    io.Writer // nil после ошибки записи
+	file *os.File  // открытый файл или nil
+}
+
+// Name возвращает имя команды.
+func (t *TeeCommand) Name() string {
+	return "tee"
+}
+
+// Exec выполняет команду tee.
+//
+// Синтаксис:
+//
+//	tee [-a] [FILE...]
+//
+// Данные копируются по мере поступления, поэтому tee можно использовать
+// после tail -f. Если запись в один из файлов не удалась, tee печатает
+// ошибку и продолжает писать в остальные; код завершения тогда 1.
+// Файл "-" — ещё одна копия в стандартный вывод.
+//
+// Примеры:
+//
+//	ls | tee files.txt            → вывод на экран и в файл
+//	make 2>&1 | tee -a build.log  → дописать вывод в конец журнала
+func (t *TeeCommand) Exec(args []string, ctx *CommandContext) error {
+	fs := flag.NewFlagSet("tee", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	var appendMode bool
+	fs.BoolVar(&appendMode, "a", false, "дописывать в конец файлов")
+	fs.BoolVar(&appendMode, "append", false, "дописывать в конец файлов")
+	fs.Bool("i", false, "игнорируется")
+	fs.Bool("ignore-interrupts", false, "игнорируется")
+	if err := fs.Parse(splitShortFlags(args, "")); err != nil {
+		if _, writeErr := fmt.Fprintf(ctx.Stderr, "tee: ошибка разбора флагов: %v\n", err); writeErr != nil {
+			return writeErr
+		}
+		return &customErrors.ExitStatusError{Code: 1}
+	}
+
+	mode := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if appendMode {
+		mode = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+
+	failed := false
+	outputs := []*teeOutput{{w: ctx.Stdout}}
+	for _, name := range fs.Args() {
+		if name == "-" {
+			outputs = append(outputs, &teeOutput{w: ctx.Stdout})
+			continue
+		}
+		//nolint:gosec // файлы указывает пользователь, как в обычном tee
+		file, err := os.OpenFile(ctx.ResolvePath(name), mode, 0o666)
+		if err != nil {
+			failed = true
+			if _, writeErr := fmt.Fprintf(ctx.Stderr, "tee: %s: %v\n", name, fileError(err)); writeErr != nil {
+				return writeErr
+			}
+			continue
+		}
+		outputs = append(outputs, &teeOutput{name: name, w: file, file: file})
+	}
+	defer func() {
+		for _, out := range outputs {
+			if out.file != nil {
+				_ = out.file.Close()
+			}
+		}
+	}()
+
+	buf := make([]byte, linereader.BufferSize)
+	for {
+		n, readErr := ctx.Stdin.Read(buf)
+		if n > 0 {
+			if err := teeWrite(ctx, outputs, buf[:n]); err != nil {
+				return err
+			}
+		}
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+		if readErr != nil {
+			failed = true
+			if _, err := fmt.Fprintf(ctx.Stderr, "tee: %s: %v\n", stdinName, readErr); err != nil {
+				return err
+			}
+			break
+		}
+	}
+
+	for _, out := range outputs {
+		if out.w == nil {
+			failed = true
+			continue
+		}
+		if out.file == nil {
+			continue
+		}
+		err := out.file.Close()
+		out.file = nil
+		if err != nil {
+			failed = true
+			if _, writeErr := fmt.Fprintf(ctx.Stderr, "tee: %s: %v\n", out.name, fileError(err)); writeErr != nil {
+				return writeErr
+			}
+		}
+	}
+	if failed {
+		return &customErrors.ExitStatusError{Code: 1}
+	}
+	return nil
+}
+
+// teeWrite записывает data во все выходы. Файл, запись в который не
+// удалась, исключается (w становится nil), остальные продолжают
+// получать данные. Ошибка записи в стандартный вывод возвращается сразу:
+// читатель пайплайна завершился.
+func teeWrite(ctx *CommandContext, outputs []*teeOutput, data []byte) error {
+	for _, out := range outputs {
+		if out.w == nil {
+			continue
+		}
+		if _, err := out.w.Write(data); err != nil {
+			if out.name == "" {
+				return err
+			}
+			out.w = nil
+			if _, writeErr := fmt.Fprintf(ctx.Stderr, "tee: %s: %v\n", out.name, fileError(err)); writeErr != nil {
+				return writeErr
+			}
+		}
+	}
+	return nil
+}
+
+// Help возвращает справку по команде tee.
+func (t *TeeCommand) Help() string {
+	return `NAME
+    tee - копирует стандартный ввод в стандартный вывод и в файлы
+
+SYNOPSIS
+    tee [OPTION]... [FILE]...
+
+DESCRIPTION
+    Читает стандартный ввод и записывает его в стандартный вывод и в
+    каждый FILE. Файлы перезаписываются, с -a — дополняются. Данные
+    передаются по мере поступления. Если в какой-либо файл записать не
+    удалось, tee сообщает об ошибке и продолжает писать в остальные.
+
+OPTIONS
+    -a, --append               дописывать в конец файлов
+    -i, --ignore-interrupts    игнорируется
+
+EXIT STATUS
+    0 — успех, 1 — не удалось открыть файл или записать в него.
+
+EXAMPLES
+    ls | tee files.txt
+        → список на экране и в files.txt
+
+    echo done | tee -a a.log b.log
+        → дописать строку в оба журнала и вывести её`
+}
+
+var _ BuiltinCommand = (*TeeCommand)(nil)
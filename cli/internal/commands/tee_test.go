@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTeeCommand(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.txt")
+	b := filepath.Join(dir, "b.txt")
+	_ = os.WriteFile(a, []byte("old\n"), 0o644)
+
+	out, stderr, status := runCommand(t, &TeeCommand{}, dir, "line1\nline2\n", "a.txt", "b.txt")
+	if status != 0 || stderr != "" || out != "line1\nline2\n" {
+		t.Fatalf("ожидался вывод ввода без ошибок, получено %d: %q, %q", status, out, stderr)
+	}
+	for _, path := range []string{a, b} {
+		if data, _ := os.ReadFile(path); string(data) != "line1\nline2\n" {
+			t.Errorf("%s: ожидалось %q, получено %q", filepath.Base(path), "line1\nline2\n", data)
+		}
+	}
+
+	_, _, status = runCommand(t, &TeeCommand{}, dir, "line3\n", "-a", "a.txt")
+	if data, _ := os.ReadFile(a); status != 0 || string(data) != "line1\nline2\nline3\n" {
+		t.Errorf("ожидалось дописывание в конец, получено %d: %q", status, data)
+	}
+
+	out, _, _ = runCommand(t, &TeeCommand{}, dir, "x\n", "-")
+	if out != "x\nx\n" {
+		t.Errorf("ожидалось %q, получено %q", "x\nx\n", out)
+	}
+}
+
+func TestTeeCommand_FileError(t *testing.T) {
+	dir := t.TempDir()
+	out, stderr, status := runCommand(t, &TeeCommand{}, dir, "data\n", "missing/x.txt", "ok.txt")
+	if status != 1 || !strings.Contains(stderr, "missing/x.txt") {
+		t.Errorf("ожидался код 1 и сообщение об ошибке, получено %d: %q", status, stderr)
+	}
+	if out != "data\n" {
+		t.Errorf("ожидалось %q, получено %q", "data\n", out)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "ok.txt")); string(data) != "data\n" {
+		t.Errorf("запись в остальные файлы должна продолжаться, получено %q", data)
+	}
+}
@@ -0,0 +1,140 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/conditional"
+	customErrors "github.com/ggerlakh/software-design-mhs-itmo/cli/internal/errors"
+)
+
+// Коды возврата test и [.
+const (
+	testFalse = 1 // выражение ложно
+	testError = 2 // ошибка в выражении
+)
+
+// TestCommand реализует встроенную команду "test".
+// Она вычисляет условное выражение и сообщает результат кодом возврата:
+// 0 — истина, 1 — ложь, 2 — ошибка в выражении.
+type TestCommand struct{}
+
+// Name возвращает имя команды.
+func (t *TestCommand) Name() string {
+	return "test"
+}
+
+// Exec выполняет команду test с переданными аргументами.
+//
+// Примеры:
+//
+//	test -f go.mod        → 0, если go.mod — обычный файл
+//	test 3 -gt 5 -o -n x  → 0
+func (t *TestCommand) Exec(args []string, ctx *CommandContext) error {
+	return evaluateTest("test", args, ctx)
+}
+
+// Help возвращает справку по команде test.
+func (t *TestCommand) Help() string {
+	return testHelp("test", "test EXPRESSION")
+}
+
+// BracketCommand реализует встроенную команду "[" — синоним test,
+// последним аргументом которого должна быть закрывающая скобка "]".
+type BracketCommand struct{}
+
+// Name возвращает имя команды.
+func (b *BracketCommand) Name() string {
+	return "["
+}
+
+// Exec выполняет команду [ с переданными аргументами.
+//
+// Примеры:
+//
+//	[ -d build ]       → 0, если build — каталог
+//	[ "$a" = "$b" ]    → 0, если строки равны
+func (b *BracketCommand) Exec(args []string, ctx *CommandContext) error {
+	if len(args) == 0 || args[len(args)-1] != "]" {
+		return testFailure(ctx, "[", fmt.Errorf("отсутствует ']'"))
+	}
+	return evaluateTest("[", args[:len(args)-1], ctx)
+}
+
+// Help возвращает справку по команде [.
+func (b *BracketCommand) Help() string {
+	return testHelp("[", "[ EXPRESSION ]")
+}
+
+func evaluateTest(name string, args []string, ctx *CommandContext) error {
+	result, err := conditional.Test(args, ctx.Dir)
+	if err != nil {
+		return testFailure(ctx, name, err)
+	}
+	if !result {
+		return &customErrors.ExitStatusError{Code: testFalse}
+	}
+	return nil
+}
+
+func testFailure(ctx *CommandContext, name string, err error) error {
+	if _, writeErr := fmt.Fprintf(ctx.Stderr, "%s: %v\n", name, err); writeErr != nil {
+		return writeErr
+	}
+	return &customErrors.ExitStatusError{Code: testError}
+}
+
+func testHelp(name, synopsis string) string {
+	return `NAME
+    ` + name + ` - вычисляет условное выражение
+
+SYNOPSIS
+    ` + synopsis + `
+
+DESCRIPTION
+    Возвращает код 0, если выражение истинно, 1 — если ложно,
+    и 2 — если выражение записано с ошибкой. Относительные пути
+    отсчитываются от текущего каталога сеанса.
+
+    Проверки файлов:
+        -e FILE    файл существует
+        -f FILE    обычный файл
+        -d FILE    каталог
+        -L FILE    символическая ссылка (также -h)
+        -r FILE    доступен для чтения
+        -w FILE    доступен для записи
+        -x FILE    доступен для выполнения
+        -s FILE    файл не пустой
+        A -nt B    A новее B
+        A -ot B    A старше B
+        A -ef B    A и B — один и тот же файл
+
+    Строки:
+        -z STR     строка пустая
+        -n STR     строка не пустая
+        A = B      строки равны (также ==)
+        A != B     строки различаются
+
+    Целые числа:
+        A -eq B, -ne, -lt, -le, -gt, -ge
+
+    Логические операторы:
+        ! EXPR, EXPR -a EXPR, EXPR -o EXPR, \( EXPR \)
+
+    Для сравнения с шаблоном (==), регулярным выражением (=~) и
+    операторов && и || используйте составную команду [[ ... ]].
+
+EXAMPLES
+    ` + name + ` -f go.mod` + closing(name) + ` && echo файл есть
+        → файл есть
+
+    ` + name + ` 10 -gt 9 -a ! -d missing` + closing(name) + `
+        → код возврата 0`
+}
+
+// closing возвращает закрывающую скобку для примеров команды [.
+func closing(name string) string {
+	if name == "[" {
+		return " ]"
+	}
+	return ""
+}
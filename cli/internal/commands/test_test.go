@@ -0,0 +1,61 @@
+package commands
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	customErrors "github.com/ggerlakh/software-design-mhs-itmo/cli/internal/errors"
+)
+
+// testStatus возвращает код возврата встроенной команды по её ошибке.
+func testStatus(err error) int {
+	var exitErr *customErrors.ExitStatusError
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &exitErr):
+		return exitErr.Code
+	}
+	return -1
+}
+
+func TestTestCommand(t *testing.T) {
+	cmd := &TestCommand{}
+
+	tests := []struct {
+		args     []string
+		expected int
+	}{
+		{[]string{"-n", "x"}, 0},
+		{[]string{"a", "=", "b"}, 1},
+		{[]string{"5", "-ge", "3", "-a", "!", "-e", "missing"}, 0},
+		{[]string{"x", "-eq", "1"}, 2},
+	}
+
+	for _, tt := range tests {
+		_, stderr, err := runBuiltin(cmd, tt.args...)
+		if status := testStatus(err); status != tt.expected {
+			t.Fatalf("%q: ожидался код %d, получено %d", tt.args, tt.expected, status)
+		}
+		if tt.expected == 2 && !strings.HasPrefix(stderr, "test: ") {
+			t.Fatalf("%q: ошибка должна попасть в stderr: %q", tt.args, stderr)
+		}
+	}
+}
+
+func TestBracketCommand(t *testing.T) {
+	cmd := &BracketCommand{}
+
+	if _, _, err := runBuiltin(cmd, "-d", ".", "]"); testStatus(err) != 0 {
+		t.Fatalf("ожидалась истина, получено %v", err)
+	}
+	if _, _, err := runBuiltin(cmd, "-f", ".", "]"); testStatus(err) != 1 {
+		t.Fatalf("ожидалась ложь, получено %v", err)
+	}
+
+	_, stderr, err := runBuiltin(cmd, "-d", ".")
+	if testStatus(err) != 2 || !strings.Contains(stderr, "]") {
+		t.Fatalf("без закрывающей скобки ожидалась ошибка: %v, %q", err, stderr)
+	}
+}
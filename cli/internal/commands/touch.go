@@ -0,0 +1,312 @@
+package commands
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	customErrors "github.com/ggerlakh/software-design-mhs-itmo/cli/internal/errors"
+)
+
+// touchLayouts — абсолютные форматы даты touch -d. Дробная часть секунд
+// допускается после секунд в любом из них.
+var touchLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05 MST",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02 15:04:05",
+	"2006/01/02",
+	time.RFC1123Z,
+	time.RFC1123,
+	time.UnixDate,
+}
+
+// touchClockLayouts — форматы времени суток без даты: дата берётся текущая.
+var touchClockLayouts = []string{"15:04:05", "15:04"}
+
+// touchUnits — единицы относительных дат touch -d.
+var touchUnits = map[string]time.Duration{
+	"sec":    time.Second,
+	"second": time.Second,
+	"min":    time.Minute,
+	"minute": time.Minute,
+	"hour":   time.Hour,
+	"day":    24 * time.Hour,
+	"week":   7 * 24 * time.Hour,
+}
+
+// TouchCommand реализует встроенную команду "touch".
+// Она изменяет время доступа и изменения файлов, создавая отсутствующие.
+type TouchCommand struct{}
+
+// touchOptions — параметры touch.
+type touchOptions struct {
+	accessOnly bool      // -a: менять только время доступа
+	modifyOnly bool      // -m: менять только время изменения
+	noCreate   bool      // -c: не создавать отсутствующие файлы
+	time       time.Time // время из -d или -r; нулевое — текущее
+}
+
+// Name возвращает имя команды.
+func (t *TouchCommand) Name() string {
+	return "touch"
+}
+
+// Exec выполняет команду touch.
+//
+// Синтаксис:
+//
+//	touch [-a] [-m] [-c] [-d DATE | -r FILE] FILE...
+//
+// DATE — "2024-01-02 15:04[:05]", RFC 3339, "@SECONDS", "now",
+// "yesterday", "tomorrow" или относительная дата "2 hours ago".
+//
+// Примеры:
+//
+//	touch new.txt                        → пустой файл или текущее время
+//	touch -d '2024-01-01 00:00' a.txt    → заданное время
+//	touch -d '3 days ago' -m old.log     → время изменения три дня назад
+func (t *TouchCommand) Exec(args []string, ctx *CommandContext) error {
+	opts, files, err := parseTouchArgs(args, ctx, time.Now())
+	if err != nil {
+		if writeErr := warnf(ctx, "%v\nПопробуйте 'touch --help' для получения справки.", err); writeErr != nil {
+			return writeErr
+		}
+		return &customErrors.ExitStatusError{Code: 1}
+	}
+
+	failed := false
+	for _, name := range files {
+		err := opts.touch(ctx.ResolvePath(name))
+		if err == nil {
+			continue
+		}
+		failed = true
+		if err := warnf(ctx, "touch: невозможно выполнить touch для '%s': %v", name, fileError(err)); err != nil {
+			return err
+		}
+	}
+	if failed {
+		return &customErrors.ExitStatusError{Code: 1}
+	}
+	return nil
+}
+
+// parseTouchArgs разбирает аргументы touch; now — текущее время для
+// относительных дат.
+func parseTouchArgs(args []string, ctx *CommandContext, now time.Time) (*touchOptions, []string, error) {
+	fs := flag.NewFlagSet("touch", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	opts := &touchOptions{}
+	var date, reference string
+	fs.BoolVar(&opts.accessOnly, "a", false, "менять только время доступа")
+	fs.BoolVar(&opts.modifyOnly, "m", false, "менять только время изменения")
+	fs.BoolVar(&opts.noCreate, "c", false, "не создавать файлы")
+	fs.BoolVar(&opts.noCreate, "no-create", false, "не создавать файлы")
+	fs.StringVar(&date, "d", "", "дата вместо текущего времени")
+	fs.StringVar(&date, "date", "", "дата вместо текущего времени")
+	fs.StringVar(&reference, "r", "", "взять время у файла")
+	fs.StringVar(&reference, "reference", "", "взять время у файла")
+
+	if err := parseOptions(fs, splitShortFlags(args, "dr")); err != nil {
+		return nil, nil, err
+	}
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+
+	dateSet, referenceSet := set["d"] || set["date"], set["r"] || set["reference"]
+	switch {
+	case dateSet && referenceSet:
+		return nil, nil, errors.New("touch: нельзя указывать одновременно -d и -r")
+	case dateSet:
+		parsed, err := parseTouchDate(date, now)
+		if err != nil {
+			return nil, nil, fmt.Errorf("touch: неверная дата '%s'", date)
+		}
+		opts.time = parsed
+	case referenceSet:
+		info, err := os.Stat(ctx.ResolvePath(reference))
+		if err != nil {
+			return nil, nil, fmt.Errorf("touch: не удалось получить время файла '%s': %v", reference, fileError(err))
+		}
+		opts.time = info.ModTime()
+	}
+
+	if fs.NArg() == 0 {
+		return nil, nil, errors.New("touch: пропущен операнд, задающий файл")
+	}
+	return opts, fs.Args(), nil
+}
+
+// touch обновляет время файла path, создавая его при необходимости.
+// Без -a и -m меняются оба времени.
+func (o *touchOptions) touch(path string) error {
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		if o.noCreate {
+			return nil
+		}
+		//nolint:gosec // файл указывает пользователь
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE, 0o666)
+		if err != nil {
+			return err
+		}
+		if err := file.Close(); err != nil {
+			return err
+		}
+		if o.time.IsZero() {
+			return nil
+		}
+	}
+
+	stamp := o.time
+	if stamp.IsZero() {
+		stamp = time.Now()
+	}
+	// Нулевое время оставляет соответствующую метку без изменений
+	atime, mtime := stamp, stamp
+	if o.modifyOnly && !o.accessOnly {
+		atime = time.Time{}
+	}
+	if o.accessOnly && !o.modifyOnly {
+		mtime = time.Time{}
+	}
+	return os.Chtimes(path, atime, mtime)
+}
+
+// parseTouchDate разбирает дату -d: абсолютную в одном из touchLayouts
+// (в местном часовом поясе, если он не указан), время суток, "@SECONDS",
+// слова now, today, yesterday, tomorrow или относительную дату из
+// пар "[+|-]N UNIT" с необязательным "ago" в конце.
+func parseTouchDate(text string, now time.Time) (time.Time, error) {
+	text = strings.TrimSpace(text)
+	switch strings.ToLower(text) {
+	case "now", "today":
+		return now, nil
+	case "yesterday":
+		return now.AddDate(0, 0, -1), nil
+	case "tomorrow":
+		return now.AddDate(0, 0, 1), nil
+	}
+
+	if seconds, ok := strings.CutPrefix(text, "@"); ok {
+		value, err := strconv.ParseFloat(seconds, 64)
+		if err != nil || math.IsInf(value, 0) || math.IsNaN(value) {
+			return time.Time{}, fmt.Errorf("неверное число секунд %q", seconds)
+		}
+		whole, frac := math.Modf(value)
+		return time.Unix(int64(whole), int64(frac*1e9)), nil
+	}
+
+	for _, layout := range touchLayouts {
+		if parsed, err := time.ParseInLocation(layout, text, now.Location()); err == nil {
+			return parsed, nil
+		}
+	}
+	for _, layout := range touchClockLayouts {
+		if clock, err := time.ParseInLocation(layout, text, now.Location()); err == nil {
+			year, month, day := now.Date()
+			return time.Date(year, month, day, clock.Hour(), clock.Minute(), clock.Second(), clock.Nanosecond(), now.Location()), nil
+		}
+	}
+	return parseRelativeDate(text, now)
+}
+
+// parseRelativeDate разбирает относительную дату вида "1 day 2 hours ago".
+// Месяцы и годы прибавляются по календарю, остальные единицы — как
+// промежутки времени.
+func parseRelativeDate(text string, now time.Time) (time.Time, error) {
+	fields := strings.Fields(strings.ToLower(text))
+	sign := 1
+	if n := len(fields); n > 0 && fields[n-1] == "ago" {
+		sign, fields = -1, fields[:n-1]
+	}
+	if len(fields) == 0 || len(fields)%2 != 0 {
+		return time.Time{}, fmt.Errorf("неверная дата %q", text)
+	}
+
+	result := now
+	for i := 0; i < len(fields); i += 2 {
+		count, err := strconv.Atoi(fields[i])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("неверное число %q", fields[i])
+		}
+		count *= sign
+
+		unit := strings.TrimSuffix(fields[i+1], "s")
+		switch unit {
+		case "month":
+			result = result.AddDate(0, count, 0)
+		case "year":
+			result = result.AddDate(count, 0, 0)
+		default:
+			step, ok := touchUnits[unit]
+			if !ok {
+				return time.Time{}, fmt.Errorf("неизвестная единица %q", fields[i+1])
+			}
+			result = result.Add(time.Duration(count) * step)
+		}
+	}
+	return result, nil
+}
+
+// Help возвращает справку по команде touch.
+func (t *TouchCommand) Help() string {
+	return `NAME
+    touch - изменяет время доступа и изменения файлов
+
+SYNOPSIS
+    touch [OPTION]... FILE...
+
+DESCRIPTION
+    Устанавливает время доступа и изменения файлов FILE в текущее время
+    или в заданное через -d или -r. Отсутствующие файлы создаются пустыми.
+
+    Форматы DATE:
+      2024-01-02, 2024-01-02 15:04[:05[.NNN]], 2024-01-02T15:04:05
+                      дата и время в местном часовом поясе
+      2024-01-02T15:04:05+03:00
+                      RFC 3339 с часовым поясом
+      15:04[:05]      время суток сегодня
+      @1700000000     секунды с начала эпохи Unix
+      now, today, yesterday, tomorrow
+      2 hours ago, +1 day, 1 week 3 days ago
+                      относительная дата; единицы: sec, min, hour, day,
+                      week, month, year (также во множественном числе)
+
+OPTIONS
+    -a                    менять только время доступа
+    -m                    менять только время изменения
+    -c, --no-create       не создавать отсутствующие файлы
+    -d, --date=DATE       использовать DATE вместо текущего времени
+    -r, --reference=FILE  использовать время изменения файла FILE
+
+EXIT STATUS
+    0 — успех, 1 — неверные аргументы или ошибка хотя бы с одним файлом.
+
+EXAMPLES
+    touch build.stamp
+        → создать файл или обновить его время
+
+    touch -d '2024-03-01 12:00' report.txt
+        → установить дату отчёта
+
+    touch -m -d '1 day ago' cache.db
+        → сдвинуть время изменения на сутки назад`
+}
+
+var _ BuiltinCommand = (*TouchCommand)(nil)
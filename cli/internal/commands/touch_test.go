@@ -0,0 +1,118 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTouchCommand(t *testing.T) {
+	dir := t.TempDir()
+	stamp := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	old := filepath.Join(dir, "old.txt")
+	_ = os.WriteFile(old, []byte("keep"), 0o644)
+	_ = os.Chtimes(old, stamp, stamp)
+
+	before := time.Now().Add(-time.Second)
+	_, stderr, status := runCommand(t, &TouchCommand{}, dir, "", "new.txt", "old.txt")
+	if status != 0 || stderr != "" {
+		t.Fatalf("ожидался код 0, получено %d: %q", status, stderr)
+	}
+	for _, name := range []string{"new.txt", "old.txt"} {
+		info, err := os.Stat(filepath.Join(dir, name))
+		if err != nil || info.ModTime().Before(before) {
+			t.Errorf("%s: ожидалось текущее время, получено %v, %v", name, info, err)
+		}
+	}
+	if data, _ := os.ReadFile(old); string(data) != "keep" {
+		t.Errorf("touch не должен менять содержимое, получено %q", data)
+	}
+
+	_, stderr, status = runCommand(t, &TouchCommand{}, dir, "", "-c", "absent.txt")
+	if status != 0 || stderr != "" {
+		t.Errorf("-c не считает отсутствие файла ошибкой, получено %d: %q", status, stderr)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "absent.txt")); !os.IsNotExist(err) {
+		t.Errorf("-c не должен создавать файл")
+	}
+
+	_, stderr, status = runCommand(t, &TouchCommand{}, dir, "", "missing/file.txt")
+	if status != 1 || !strings.Contains(stderr, "touch: невозможно выполнить touch для 'missing/file.txt'") {
+		t.Errorf("ожидалась ошибка, получено %d: %q", status, stderr)
+	}
+}
+
+func TestTouchCommand_Date(t *testing.T) {
+	dir := t.TempDir()
+	stamp := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+
+	_, stderr, status := runCommand(t, &TouchCommand{}, dir, "", "-d", "@1614834367", "a.txt")
+	if status != 0 {
+		t.Fatalf("ожидался код 0, получено %d: %q", status, stderr)
+	}
+	info, _ := os.Stat(filepath.Join(dir, "a.txt"))
+	if !info.ModTime().Equal(stamp) {
+		t.Errorf("ожидалось %v, получено %v", stamp, info.ModTime())
+	}
+
+	_, stderr, status = runCommand(t, &TouchCommand{}, dir, "", "--reference=a.txt", "b.txt")
+	if status != 0 {
+		t.Fatalf("ожидался код 0, получено %d: %q", status, stderr)
+	}
+	if info, _ := os.Stat(filepath.Join(dir, "b.txt")); !info.ModTime().Equal(stamp) {
+		t.Errorf("-r: ожидалось %v, получено %v", stamp, info.ModTime())
+	}
+
+	// -a не трогает время изменения
+	_, _, status = runCommand(t, &TouchCommand{}, dir, "", "-a", "-d", "2000-01-01", "a.txt")
+	if info, _ := os.Stat(filepath.Join(dir, "a.txt")); status != 0 || !info.ModTime().Equal(stamp) {
+		t.Errorf("-a: время изменения не должно меняться, получено %d, %v", status, info.ModTime())
+	}
+
+	for _, args := range [][]string{{"-d", "someday", "x"}, {"-d", "now", "-r", "a.txt", "x"}, {"-r", "missing", "x"}, {}} {
+		_, stderr, status := runCommand(t, &TouchCommand{}, dir, "", args...)
+		if status != 1 || stderr == "" {
+			t.Errorf("%v: ожидалась ошибка аргументов, получено %d: %q", args, status, stderr)
+		}
+	}
+}
+
+func TestParseTouchDate(t *testing.T) {
+	now := time.Date(2024, 1, 31, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		text     string
+		expected time.Time
+	}{
+		{"now", now},
+		{"yesterday", time.Date(2024, 1, 30, 12, 0, 0, 0, time.UTC)},
+		{"2023-07-08", time.Date(2023, 7, 8, 0, 0, 0, 0, time.UTC)},
+		{"2023-07-08 09:10", time.Date(2023, 7, 8, 9, 10, 0, 0, time.UTC)},
+		{"2023-07-08 09:10:11.5", time.Date(2023, 7, 8, 9, 10, 11, 5e8, time.UTC)},
+		{"2023-07-08T09:10:11+03:00", time.Date(2023, 7, 8, 6, 10, 11, 0, time.UTC)},
+		{"08:15", time.Date(2024, 1, 31, 8, 15, 0, 0, time.UTC)},
+		{"@0.25", time.Unix(0, 25e7)},
+		{"2 days ago", time.Date(2024, 1, 29, 12, 0, 0, 0, time.UTC)},
+		{"+90 min", time.Date(2024, 1, 31, 13, 30, 0, 0, time.UTC)},
+		{"1 week 1 hour ago", time.Date(2024, 1, 24, 11, 0, 0, 0, time.UTC)},
+		{"1 month", time.Date(2024, 3, 2, 12, 0, 0, 0, time.UTC)},
+		{"1 year ago", time.Date(2023, 1, 31, 12, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := parseTouchDate(tt.text, now)
+		if err != nil {
+			t.Errorf("%q: неожиданная ошибка: %v", tt.text, err)
+			continue
+		}
+		if !got.Equal(tt.expected) {
+			t.Errorf("%q: ожидалось %v, получено %v", tt.text, tt.expected, got)
+		}
+	}
+
+	for _, text := range []string{"", "ago", "2", "two days", "3 fortnights", "@abc", "2024-13-01"} {
+		if _, err := parseTouchDate(text, now); err == nil {
+			t.Errorf("%q: ожидалась ошибка", text)
+		}
+	}
+}
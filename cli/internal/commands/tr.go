@@ -0,0 +1,565 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	customErrors "github.com/ggerlakh/software-design-mhs-itmo/cli/internal/errors"
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/linereader"
+)
+
+// TrCommand реализует встроенную команду "tr".
+// Она заменяет, удаляет и сжимает символы стандартного ввода.
+type TrCommand struct{}
+
+// trClasses — классы символов [:name:]. Для удаления и сжатия
+// принадлежность проверяется по Unicode, а при замене класс
+// раскрывается в символы ASCII, как в локали C.
+var trClasses = map[string]func(rune) bool{
+	"alnum":  func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) },
+	"alpha":  unicode.IsLetter,
+	"blank":  func(r rune) bool { return r == ' ' || r == '\t' },
+	"cntrl":  unicode.IsControl,
+	"digit":  func(r rune) bool { return r >= '0' && r <= '9' },
+	"graph":  func(r rune) bool { return unicode.IsGraphic(r) && !unicode.IsSpace(r) },
+	"lower":  unicode.IsLower,
+	"print":  func(r rune) bool { return unicode.IsPrint(r) || r == ' ' },
+	"punct":  unicode.IsPunct,
+	"space":  unicode.IsSpace,
+	"upper":  unicode.IsUpper,
+	"xdigit": func(r rune) bool { return strings.ContainsRune("0123456789abcdefABCDEF", r) },
+}
+
+// trOptions — параметры tr.
+type trOptions struct {
+	complement bool // -c: использовать дополнение SET1
+	delete     bool // -d: удалять символы SET1
+	squeeze    bool // -s: сжимать повторы символов последнего набора
+	truncate   bool // -t: обрезать SET1 до длины SET2
+}
+
+// trSet — разобранный набор символов tr.
+type trSet struct {
+	runes    []rune   // символы набора по порядку, классы раскрыты в ASCII
+	classes  []string // классы [:name:] набора
+	fill     int      // позиция повтора [c*] в runes или -1
+	fillWith rune     // символ повтора [c*]
+	members  map[rune]bool
+}
+
+// contains сообщает, входит ли r в набор.
+func (s *trSet) contains(r rune) bool {
+	if s.members[r] {
+		return true
+	}
+	for _, class := range s.classes {
+		if trClasses[class](r) {
+			return true
+		}
+	}
+	return false
+}
+
+// onlyClass сообщает, что набор состоит из одного класса name.
+func (s *trSet) onlyClass(name string) bool {
+	return len(s.classes) == 1 && s.classes[0] == name && len(s.runes) == trClassSize(name)
+}
+
+// Name возвращает имя команды.
+func (t *TrCommand) Name() string {
+	return "tr"
+}
+
+// Exec выполняет команду tr.
+//
+// Синтаксис:
+//
+//	tr [-c] [-s] [-t] SET1 SET2
+//	tr [-c] -d [-s] SET1 [SET2]
+//	tr [-c] -s SET1
+//
+// Символы SET1 заменяются соответствующими символами SET2; если SET2
+// короче, он дополняется своим последним символом. Наборы поддерживают
+// диапазоны (a-z), классы ([:upper:]), повторы ([c*N], [c*]) и
+// последовательности \n, \t, \\, \NNN. Ввод обрабатывается по символам UTF-8.
+//
+// Примеры:
+//
+//	tr a-z A-Z                → перевод в верхний регистр
+//	tr -d '\r'                → удаление CR
+//	tr -s ' '                 → сжатие пробелов
+//	tr -cs '[:alnum:]' '\n'   → слова по одному на строке
+func (t *TrCommand) Exec(args []string, ctx *CommandContext) error {
+	translate, err := parseTrArgs(args)
+	if err != nil {
+		if _, writeErr := fmt.Fprintf(ctx.Stderr, "%v\nПопробуйте 'tr --help' для получения справки.\n", err); writeErr != nil {
+			return writeErr
+		}
+		return &customErrors.ExitStatusError{Code: 1}
+	}
+
+	lines := linereader.New(ctx.Stdin)
+	var buf []byte
+	for {
+		line, err := lines.ReadLine()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		buf = translate.appendLine(buf[:0], line)
+		if len(buf) > 0 {
+			if _, err := ctx.Stdout.Write(buf); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// trTranslator выполняет замену, удаление и сжатие символов.
+type trTranslator struct {
+	opts    trOptions
+	set1    *trSet
+	squeeze *trSet // набор для -s или nil
+	// squeezeComplement — сжимать символы не из squeeze (tr -cs SET1)
+	squeezeComplement bool
+	mapping           map[rune]rune
+	convert           func(rune) rune // замена всего класса ([:lower:] → [:upper:])
+	// complementTo — замены для символов дополнения SET1 (-c) по порядку
+	complementTo []rune
+	last         rune // последний выведенный символ для -s
+	hasLast      bool
+}
+
+// parseTrArgs разбирает аргументы tr и строит trTranslator.
+func parseTrArgs(args []string) (*trTranslator, error) {
+	var opts trOptions
+	var operands []string
+	for i, arg := range args {
+		if arg == "--" {
+			operands = append(operands, args[i+1:]...)
+			break
+		}
+		if !strings.HasPrefix(arg, "-") || arg == "-" || len(operands) > 0 {
+			operands = append(operands, arg)
+			continue
+		}
+		if err := opts.set(arg); err != nil {
+			return nil, err
+		}
+	}
+
+	// -d — один набор, -d -s — два, -s без -d — один или два, замена — два
+	least, most := 2, 2
+	switch {
+	case opts.delete && !opts.squeeze:
+		least, most = 1, 1
+	case opts.squeeze && !opts.delete:
+		least = 1
+	}
+	switch {
+	case len(operands) == 0:
+		return nil, errors.New("tr: пропущен операнд")
+	case len(operands) < least:
+		return nil, fmt.Errorf("tr: пропущен операнд после '%s'", operands[len(operands)-1])
+	case len(operands) > most:
+		return nil, fmt.Errorf("tr: лишний операнд '%s'", operands[most])
+	}
+
+	set1, err := parseTrSet(operands[0], false)
+	if err != nil {
+		return nil, err
+	}
+	tr := &trTranslator{opts: opts, set1: set1}
+
+	var set2 *trSet
+	if len(operands) == 2 {
+		if set2, err = parseTrSet(operands[1], true); err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case opts.delete:
+		tr.squeeze = set2
+	case set2 != nil:
+		if err := tr.buildMapping(set2); err != nil {
+			return nil, err
+		}
+		if opts.squeeze {
+			tr.squeeze = set2
+		}
+	case opts.squeeze:
+		tr.squeeze, tr.squeezeComplement = set1, opts.complement
+	}
+	return tr, nil
+}
+
+// set применяет короткие или длинные опции аргумента arg.
+func (o *trOptions) set(arg string) error {
+	switch arg {
+	case "--complement":
+		o.complement = true
+		return nil
+	case "--delete":
+		o.delete = true
+		return nil
+	case "--squeeze-repeats":
+		o.squeeze = true
+		return nil
+	case "--truncate-set1":
+		o.truncate = true
+		return nil
+	}
+	if strings.HasPrefix(arg, "--") {
+		return fmt.Errorf("tr: нераспознанная опция '%s'", arg)
+	}
+
+	for _, name := range arg[1:] {
+		switch name {
+		case 'c', 'C':
+			o.complement = true
+		case 'd':
+			o.delete = true
+		case 's':
+			o.squeeze = true
+		case 't':
+			o.truncate = true
+		default:
+			return fmt.Errorf("tr: неверная опция -- '%c'", name)
+		}
+	}
+	return nil
+}
+
+// buildMapping строит таблицу замены SET1 → SET2.
+func (t *trTranslator) buildMapping(set2 *trSet) error {
+	if len(set2.runes) == 0 && set2.fill < 0 {
+		return errors.New("tr: при замене SET2 не может быть пустым")
+	}
+
+	// [:lower:] → [:upper:] и обратно заменяют буквы любых алфавитов
+	switch {
+	case !t.opts.complement && t.set1.onlyClass("lower") && set2.onlyClass("upper"):
+		t.convert = unicode.ToUpper
+		return nil
+	case !t.opts.complement && t.set1.onlyClass("upper") && set2.onlyClass("lower"):
+		t.convert = unicode.ToLower
+		return nil
+	}
+
+	size := len(t.set1.runes)
+	if t.opts.complement {
+		// Размер дополнения неизвестен: [c*] заполняет всё
+		size = len(set2.runes) + 1
+	}
+	to := set2.expand(size)
+	if len(to) == 0 {
+		return errors.New("tr: при замене SET2 не может быть пустым")
+	}
+
+	if t.opts.complement {
+		t.complementTo = to
+		return nil
+	}
+
+	t.mapping = make(map[rune]rune, len(t.set1.runes))
+	for i, r := range t.set1.runes {
+		if i >= len(to) {
+			if t.opts.truncate {
+				break
+			}
+			t.mapping[r] = to[len(to)-1]
+			continue
+		}
+		t.mapping[r] = to[i]
+	}
+	return nil
+}
+
+// expand возвращает символы набора, где [c*] дополняет набор до длины size.
+func (s *trSet) expand(size int) []rune {
+	if s.fill < 0 {
+		return s.runes
+	}
+	count := max(size-len(s.runes), 0)
+	result := make([]rune, 0, len(s.runes)+count)
+	result = append(result, s.runes[:s.fill]...)
+	for range count {
+		result = append(result, s.fillWith)
+	}
+	return append(result, s.runes[s.fill:]...)
+}
+
+// appendLine добавляет к buf обработанную строку line.
+func (t *trTranslator) appendLine(buf, line []byte) []byte {
+	for i := 0; i < len(line); {
+		r, size := utf8.DecodeRune(line[i:])
+		raw := line[i : i+size]
+		i += size
+
+		in := t.set1.contains(r) != t.opts.complement
+		if r == utf8.RuneError && size == 1 {
+			// Некорректный байт не входит ни в один набор и выводится как есть
+			in = t.opts.complement
+		}
+
+		if t.opts.delete {
+			if in {
+				continue
+			}
+		} else if in {
+			r, raw = t.translate(r), nil
+		}
+
+		if t.squeeze != nil && t.hasLast && r == t.last && t.squeeze.contains(r) != t.squeezeComplement {
+			continue
+		}
+		t.last, t.hasLast = r, true
+
+		if raw != nil {
+			buf = append(buf, raw...)
+		} else {
+			buf = utf8.AppendRune(buf, r)
+		}
+	}
+	return buf
+}
+
+// translate возвращает замену символа r, входящего в SET1 (или в его дополнение).
+func (t *trTranslator) translate(r rune) rune {
+	switch {
+	case t.convert != nil:
+		return t.convert(r)
+	case t.complementTo != nil:
+		// Дополнение упорядочено по возрастанию кодов символов
+		index := int(r)
+		for _, member := range t.set1.runes {
+			if member < r {
+				index--
+			}
+		}
+		return t.complementTo[min(index, len(t.complementTo)-1)]
+	case t.mapping != nil:
+		if to, ok := t.mapping[r]; ok {
+			return to
+		}
+	}
+	return r
+}
+
+// parseTrSet разбирает набор символов. Повторы [c*N] допустимы только в SET2.
+func parseTrSet(text string, second bool) (*trSet, error) {
+	set := &trSet{fill: -1}
+	runes := trUnescape(text)
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if r.escaped || r.value != '[' {
+			if i+2 < len(runes) && !runes[i+1].escaped && runes[i+1].value == '-' {
+				hi := runes[i+2].value
+				if hi < r.value {
+					return nil, fmt.Errorf("tr: диапазон '%c-%c' в обратном порядке", r.value, hi)
+				}
+				for c := r.value; c <= hi; c++ {
+					set.runes = append(set.runes, c)
+				}
+				i += 2
+				continue
+			}
+			set.runes = append(set.runes, r.value)
+			continue
+		}
+
+		n, err := set.bracket(runes[i:], second)
+		if err != nil {
+			return nil, err
+		}
+		if n == 0 {
+			set.runes = append(set.runes, '[')
+			continue
+		}
+		i += n - 1
+	}
+
+	set.members = make(map[rune]bool, len(set.runes)+1)
+	for _, r := range set.runes {
+		set.members[r] = true
+	}
+	if set.fill >= 0 {
+		set.members[set.fillWith] = true
+	}
+	return set, nil
+}
+
+// trRune — символ набора; escaped означает, что он записан через "\".
+type trRune struct {
+	value   rune
+	escaped bool
+}
+
+// trUnescape раскрывает последовательности с обратной косой чертой.
+func trUnescape(text string) []trRune {
+	var result []trRune
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		i += size
+		if r != '\\' || i == len(text) {
+			result = append(result, trRune{value: r})
+			continue
+		}
+
+		c := text[i]
+		if simple, ok := simpleEscape(c, escapeFormat); ok && c != 'e' && c != 'E' {
+			result = append(result, trRune{value: rune(simple), escaped: true})
+			i++
+			continue
+		}
+		if c >= '0' && c <= '7' {
+			value, n := parseDigits(text[i:], 8, 3)
+			result = append(result, trRune{value: rune(value), escaped: true})
+			i += n
+			continue
+		}
+		r, size = utf8.DecodeRuneInString(text[i:])
+		result = append(result, trRune{value: r, escaped: true})
+		i += size
+	}
+	return result
+}
+
+// bracket разбирает конструкцию в квадратных скобках в начале runes:
+// класс [:name:], эквивалент [=c=] или повтор [c*N]. Возвращает
+// количество разобранных символов; 0 — "[" обозначает сам себя.
+func (s *trSet) bracket(runes []trRune, second bool) (int, error) {
+	end := -1
+	for i := 2; i < len(runes); i++ {
+		if runes[i].value == ']' && !runes[i].escaped {
+			end = i
+			break
+		}
+	}
+	if end < 0 || len(runes) < 3 {
+		return 0, nil
+	}
+	inner := make([]rune, 0, end-1)
+	for _, r := range runes[1:end] {
+		inner = append(inner, r.value)
+	}
+	body := string(inner)
+
+	switch {
+	case len(body) >= 2 && body[0] == ':' && body[len(body)-1] == ':':
+		name := body[1 : len(body)-1]
+		if _, ok := trClasses[name]; !ok {
+			return 0, fmt.Errorf("tr: неверный класс символов '%s'", name)
+		}
+		s.classes = append(s.classes, name)
+		for c := rune(0); c < utf8.RuneSelf; c++ {
+			if trClasses[name](c) {
+				s.runes = append(s.runes, c)
+			}
+		}
+	case len(inner) == 3 && inner[0] == '=' && inner[2] == '=':
+		s.runes = append(s.runes, inner[1])
+	case len(inner) >= 2 && inner[1] == '*':
+		if !second {
+			return 0, errors.New("tr: повтор [c*] допустим только в SET2")
+		}
+		count := 0
+		if digits := string(inner[2:]); digits != "" {
+			base := 10
+			if digits[0] == '0' {
+				base = 8
+			}
+			n, err := strconv.ParseInt(digits, base, 32)
+			if err != nil {
+				return 0, fmt.Errorf("tr: неверное число повторов '%s'", digits)
+			}
+			count = int(n)
+		}
+		if count == 0 {
+			if s.fill >= 0 {
+				return 0, errors.New("tr: в SET2 допустим только один повтор [c*]")
+			}
+			s.fill, s.fillWith = len(s.runes), inner[0]
+			break
+		}
+		for range count {
+			s.runes = append(s.runes, inner[0])
+		}
+	default:
+		return 0, nil
+	}
+	return end + 1, nil
+}
+
+// trClassSize возвращает количество символов ASCII в классе name.
+func trClassSize(name string) int {
+	count := 0
+	for c := rune(0); c < utf8.RuneSelf; c++ {
+		if trClasses[name](c) {
+			count++
+		}
+	}
+	return count
+}
+
+// Help возвращает справку по команде tr.
+func (t *TrCommand) Help() string {
+	return `NAME
+    tr - заменяет, удаляет и сжимает символы
+
+SYNOPSIS
+    tr [OPTION]... SET1 [SET2]
+
+DESCRIPTION
+    Читает стандартный ввод и выводит его, заменяя символы SET1
+    соответствующими символами SET2. Если SET2 короче SET1, он
+    дополняется своим последним символом. Ввод обрабатывается по
+    символам UTF-8.
+
+    В наборах допустимы:
+      a-z          диапазон символов
+      [:class:]    класс: alnum alpha blank cntrl digit graph lower
+                   print punct space upper xdigit
+      [=c=]        символ c
+      [c*N], [c*]  N повторов c; [c*] в SET2 дополняет его до длины SET1
+      \n \t \r \\ \NNN
+                   управляющие символы и восьмеричные коды
+
+    [:lower:] → [:upper:] и [:upper:] → [:lower:] меняют регистр букв
+    любых алфавитов; другие классы при замене раскрываются в символы ASCII.
+
+OPTIONS
+    -c, -C, --complement     использовать все символы, кроме SET1
+    -d, --delete             удалить символы SET1
+    -s, --squeeze-repeats    сжать повторы символов последнего набора
+                             в один символ
+    -t, --truncate-set1      обрезать SET1 до длины SET2
+
+EXIT STATUS
+    0 — успех, 1 — неверные аргументы.
+
+EXAMPLES
+    echo hello | tr a-z A-Z
+        → HELLO
+
+    tr -d '\r' < dos.txt
+        → текст без символов CR
+
+    tr -s ' ' < file.txt
+        → повторяющиеся пробелы заменены одним
+
+    tr -cs '[:alpha:]' '\n' < book.txt
+        → слова по одному на строке`
+}
+
+var _ BuiltinCommand = (*TrCommand)(nil)
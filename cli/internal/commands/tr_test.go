@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTrCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		input    string
+		expected string
+	}{
+		{"range", []string{"a-z", "A-Z"}, "hello, world\n", "HELLO, WORLD\n"},
+		{"classes", []string{"[:lower:]", "[:upper:]"}, "abc привет\n", "ABC ПРИВЕТ\n"},
+		{"classes back", []string{"[:upper:]", "[:lower:]"}, "ABC Я\n", "abc я\n"},
+		{"short set2 padded", []string{"abc", "x"}, "aabbcc\n", "xxxxxx\n"},
+		{"truncate", []string{"-t", "abc", "x"}, "abc\n", "xbc\n"},
+		{"escapes", []string{`\n`, ` `}, "a\nb\n", "a b "},
+		{"octal", []string{`\101`, `b`}, "AAA", "bbb"},
+		{"delete", []string{"-d", "aeiou"}, "education\n", "dctn\n"},
+		{"delete class", []string{"-d", "[:digit:]"}, "a1b22c\n", "abc\n"},
+		{"delete cr", []string{"-d", `\r`}, "a\r\nb\r\n", "a\nb\n"},
+		{"delete complement", []string{"-cd", "[:alpha:]\n"}, "a1 b2!\n", "ab\n"},
+		{"squeeze", []string{"-s", " "}, "a   b  c\n", "a b c\n"},
+		{"squeeze translate", []string{"-s", "a-z", "x"}, "abc def\n", "x x\n"},
+		{"squeeze complement", []string{"-cs", "[:alnum:]", `\n`}, "one, two;  three\n", "one\ntwo\nthree\n"},
+		{"delete and squeeze", []string{"-ds", "0-9", " "}, "a1  2b\n", "a b\n"},
+		{"repeat fill", []string{"a-f", "[x*]"}, "abcdefg\n", "xxxxxxg\n"},
+		{"repeat count", []string{"abc", "[x*2]y"}, "abc\n", "xxy\n"},
+		{"equivalence", []string{"[=a=]", "b"}, "aa\n", "bb\n"},
+		{"unicode", []string{"а-я", "А-Я"}, "ёж и кот\n", "ёЖ И КОТ\n"},
+		{"bracket literal", []string{"[", "("}, "[x]\n", "(x]\n"},
+		{"invalid utf8 kept", []string{"a", "b"}, "a\xffa\n", "b\xffb\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, stderr, status := runCommand(t, &TrCommand{}, "", tt.input, tt.args...)
+			if status != 0 || stderr != "" {
+				t.Fatalf("ожидался код 0 без ошибок, получено %d: %q", status, stderr)
+			}
+			if out != tt.expected {
+				t.Errorf("ожидалось %q, получено %q", tt.expected, out)
+			}
+		})
+	}
+}
+
+func TestTrCommand_InvalidArgs(t *testing.T) {
+	tests := []struct {
+		args    []string
+		message string
+	}{
+		{nil, "пропущен операнд"},
+		{[]string{"abc"}, "пропущен операнд"},
+		{[]string{"-d", "a", "b"}, "лишний операнд"},
+		{[]string{"a", "b", "c"}, "лишний операнд"},
+		{[]string{"z-a", "x"}, "обратном порядке"},
+		{[]string{"[:bogus:]", "x"}, "неверный класс"},
+		{[]string{"[a*]", "x"}, "только в SET2"},
+		{[]string{"a", ""}, "не может быть пустым"},
+		{[]string{"-x", "a"}, "неверная опция"},
+	}
+
+	for _, tt := range tests {
+		_, stderr, status := runCommand(t, &TrCommand{}, "", "", tt.args...)
+		if status != 1 || !strings.Contains(stderr, tt.message) {
+			t.Errorf("%v: ожидался код 1 и сообщение %q, получено %d: %q", tt.args, tt.message, status, stderr)
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package commands
+
+import (
+	"fmt"
+
+	customErrors "github.com/ggerlakh/software-design-mhs-itmo/cli/internal/errors"
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
+)
+
+// trapUsageCode — код возврата trap при неверных параметрах.
+const trapUsageCode = 2
+
+// TrapCommand реализует встроенную команду "trap".
+// Она устанавливает ловушки из CommandContext.Traps: команды, которые
+// оболочка выполняет при получении сигнала, при выходе (EXIT), после
+// неудачной команды (ERR) и перед каждым пайплайном (DEBUG).
+type TrapCommand struct{}
+
+// Name возвращает имя команды.
+func (t *TrapCommand) Name() string {
+	return "trap"
+}
+
+// Exec выполняет команду trap.
+//
+// Синтаксис:
+//
+//	trap [-lp] [[ACTION] SIGSPEC...]
+//
+// ACTION "-" или единственный аргумент SIGSPEC сбрасывает ловушки,
+// пустой ACTION — игнорирует сигналы. Без аргументов выводятся
+// установленные ловушки.
+//
+// Примеры:
+//
+//	trap 'rm -f $tmp' EXIT   → при выходе удалить временный файл
+//	trap - INT               → сбросить ловушку SIGINT
+//	trap -p EXIT             → trap -- 'rm -f $tmp' EXIT
+func (t *TrapCommand) Exec(args []string, ctx *CommandContext) error {
+	var list, printable bool
+	for len(args) > 0 && len(args[0]) > 1 && args[0][0] == '-' {
+		arg := args[0]
+		args = args[1:]
+		if arg == "--" {
+			break
+		}
+		for _, flag := range arg[1:] {
+			switch flag {
+			case 'l':
+				list = true
+			case 'p':
+				printable = true
+			default:
+				if err := warnf(ctx, "trap: -%c: недопустимая опция", flag); err != nil {
+					return err
+				}
+				return &customErrors.ExitStatusError{Code: trapUsageCode}
+			}
+		}
+	}
+
+	switch {
+	case list:
+		for _, s := range session.Signals {
+			if _, err := fmt.Fprintf(ctx.Stdout, "%2d) SIG%s\n", s.Number, s.Name); err != nil {
+				return err
+			}
+		}
+		return nil
+	case printable || len(args) == 0:
+		return printTraps(ctx, args)
+	}
+
+	// Единственный аргумент — сигнал, ловушку которого нужно сбросить
+	action, specs := args[0], args[1:]
+	if len(args) == 1 {
+		action, specs = "-", args
+	}
+
+	failed := false
+	for _, spec := range specs {
+		name, ok := session.TrapName(spec)
+		if !ok {
+			if err := warnf(ctx, "trap: %s: недопустимая спецификация сигнала", spec); err != nil {
+				return err
+			}
+			failed = true
+			continue
+		}
+		if action == "-" {
+			ctx.Traps.Reset(name)
+		} else {
+			ctx.Traps.Set(name, action)
+		}
+	}
+	if failed {
+		return &customErrors.ExitStatusError{Code: 1}
+	}
+	return nil
+}
+
+// printTraps выводит ловушки для сигналов specs (для всех установленных,
+// если specs пуст) в виде команд trap.
+func printTraps(ctx *CommandContext, specs []string) error {
+	names := ctx.Traps.Names()
+	failed := false
+	if len(specs) > 0 {
+		names = nil
+		for _, spec := range specs {
+			name, ok := session.TrapName(spec)
+			if !ok {
+				if err := warnf(ctx, "trap: %s: недопустимая спецификация сигнала", spec); err != nil {
+					return err
+				}
+				failed = true
+				continue
+			}
+			names = append(names, name)
+		}
+	}
+
+	for _, name := range names {
+		command, ok := ctx.Traps.Get(name)
+		if !ok {
+			continue
+		}
+		if _, err := fmt.Fprintf(ctx.Stdout, "trap -- %s %s\n", singleQuote(command), trapLabel(name)); err != nil {
+			return err
+		}
+	}
+	if failed {
+		return &customErrors.ExitStatusError{Code: 1}
+	}
+	return nil
+}
+
+// trapLabel возвращает имя ловушки для вывода: сигналы — с префиксом SIG.
+func trapLabel(name string) string {
+	switch name {
+	case session.TrapExit, session.TrapErr, session.TrapDebug:
+		return name
+	}
+	return "SIG" + name
+}
+
+// Help возвращает справку по команде trap.
+func (t *TrapCommand) Help() string {
+	return `NAME
+    trap - устанавливает обработчики сигналов и псевдосигналов
+
+SYNOPSIS
+    trap [-lp] [[ACTION] SIGSPEC...]
+
+DESCRIPTION
+    Устанавливает ACTION — команду, которая выполняется при получении
+    сигнала SIGSPEC. ACTION раскрывается при каждом выполнении, поэтому
+    её обычно заключают в одинарные кавычки. Ловушки сигналов
+    выполняются между командами, после завершения текущей.
+
+    SIGSPEC — имя сигнала с префиксом SIG или без него (HUP, INT, QUIT,
+    TERM), его номер или псевдосигнал:
+        EXIT (0)  при выходе: по exit, по концу ввода и при set -e
+        ERR       после команды с ненулевым кодом, если она не
+                  является условием перед && или ||
+        DEBUG     перед выполнением каждого пайплайна
+
+    ACTION "-" сбрасывает ловушки, пустая строка — игнорирует сигналы.
+    Единственный аргумент SIGSPEC также сбрасывает ловушку. Без
+    аргументов выводятся установленные ловушки.
+
+    Пока выполняется обработчик, другие ловушки не срабатывают:
+    полученные сигналы обрабатываются после него. Ловушка EXIT
+    выполняется один раз. Подоболочка ( ... ) не наследует ловушки,
+    но может установить свои: её ловушка EXIT выполняется при
+    завершении подоболочки.
+
+OPTIONS
+    -p      вывести ловушки SIGSPEC (или все) в виде команд trap
+    -l      вывести список сигналов
+    --      конец опций
+
+EXIT STATUS
+    0 — успех, 1 — неверная спецификация сигнала,
+    2 — неверные параметры.
+
+EXAMPLES
+    tmp=/tmp/build.lock; touch $tmp; trap 'rm -f $tmp' EXIT
+        → файл удаляется при выходе
+
+    trap 'echo прервано' INT TERM
+        → сообщение вместо завершения по Ctrl-C
+
+    (trap 'echo готово' EXIT; cd build && ls)
+        → «готово» после ls, при выходе из подоболочки
+
+    trap -p
+        → trap -- 'rm -f $tmp' EXIT`
+}
+
+var _ BuiltinCommand = (*TrapCommand)(nil)
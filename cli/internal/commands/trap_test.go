@@ -0,0 +1,76 @@
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
+)
+
+func runTrap(traps *session.Traps, args ...string) (string, string, int) {
+	var stdout, stderr bytes.Buffer
+	ctx := &CommandContext{Stdout: &stdout, Stderr: &stderr, Traps: traps}
+	err := (&TrapCommand{}).Exec(args, ctx)
+	return stdout.String(), stderr.String(), testStatus(err)
+}
+
+func TestTrapCommand(t *testing.T) {
+	traps := session.NewTraps()
+	steps := [][]string{
+		{"rm -f $tmp", "EXIT"},
+		{"echo it's", "int", "SIGTERM", "1"},
+		{"", "QUIT"},
+		{"echo err", "ERR"},
+		{"-", "HUP"},
+		{"SIGTERM"},
+		{"--", "echo debug", "DEBUG"},
+	}
+	for _, args := range steps {
+		if _, stderr, status := runTrap(traps, args...); status != 0 {
+			t.Fatalf("%q: ожидался код 0, получено %d: %q", args, status, stderr)
+		}
+	}
+
+	expected := `trap -- 'rm -f $tmp' EXIT
+trap -- 'echo it'\''s' SIGINT
+trap -- '' SIGQUIT
+trap -- 'echo debug' DEBUG
+trap -- 'echo err' ERR
+`
+	if stdout, _, _ := runTrap(traps); stdout != expected {
+		t.Errorf("ожидалось %q, получено %q", expected, stdout)
+	}
+	if stdout, _, _ := runTrap(traps, "-p", "0", "TERM"); stdout != "trap -- 'rm -f $tmp' EXIT\n" {
+		t.Errorf("trap -p: получено %q", stdout)
+	}
+	if stdout, _, _ := runTrap(traps, "-l"); !strings.HasPrefix(stdout, " 1) SIGHUP\n 2) SIGINT\n") {
+		t.Errorf("trap -l: получено %q", stdout)
+	}
+}
+
+func TestTrapCommand_Errors(t *testing.T) {
+	tests := []struct {
+		args    []string
+		status  int
+		message string
+	}{
+		{[]string{"echo", "KILL", "INT"}, 1, "trap: KILL: недопустимая спецификация сигнала"},
+		{[]string{"-p", "BAR"}, 1, "trap: BAR: недопустимая спецификация сигнала"},
+		{[]string{"-x"}, 2, "trap: -x: недопустимая опция"},
+	}
+	for _, tt := range tests {
+		traps := session.NewTraps()
+		_, stderr, status := runTrap(traps, tt.args...)
+		if status != tt.status || !strings.Contains(stderr, tt.message) {
+			t.Errorf("%v: ожидался код %d и %q, получено %d: %q", tt.args, tt.status, tt.message, status, stderr)
+		}
+	}
+
+	// Неверный сигнал не мешает установить ловушку для остальных
+	traps := session.NewTraps()
+	runTrap(traps, "echo", "KILL", "INT")
+	if command, ok := traps.Get("INT"); !ok || command != "echo" {
+		t.Errorf("ловушка INT должна быть установлена, получено %q", command)
+	}
+}
@@ -0,0 +1,228 @@
+package commands
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	customErrors "github.com/ggerlakh/software-design-mhs-itmo/cli/internal/errors"
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/linereader"
+)
+
+// UniqCommand реализует встроенную команду "uniq".
+// Она схлопывает подряд идущие одинаковые строки в одну.
+type UniqCommand struct{}
+
+// uniqOptions — параметры uniq.
+type uniqOptions struct {
+	count      bool // -c: печатать количество повторов
+	repeated   bool // -d: только повторяющиеся строки
+	unique     bool // -u: только неповторяющиеся строки
+	ignoreCase bool // -i: не различать регистр
+	skipFields int  // -f: не сравнивать первые N полей
+	skipChars  int  // -s: не сравнивать первые N символов
+}
+
+// Name возвращает имя команды.
+func (u *UniqCommand) Name() string {
+	return "uniq"
+}
+
+// Exec выполняет команду uniq.
+//
+// Синтаксис:
+//
+//	uniq [-c] [-d | -u] [-i] [-f N] [-s N] [INPUT [OUTPUT]]
+//
+// Без INPUT или с "-" читается stdin; если указан OUTPUT, результат
+// записывается в этот файл. Сравниваются только соседние строки,
+// поэтому для подсчёта всех повторов вход обычно сортируют.
+//
+// Примеры:
+//
+//	sort words.txt | uniq         → слова без повторов
+//	sort words.txt | uniq -c      → слова с количеством повторов
+//	uniq -d log.txt               → только повторяющиеся строки
+func (u *UniqCommand) Exec(args []string, ctx *CommandContext) (err error) {
+	opts, files, err := parseUniqArgs(args)
+	if err != nil {
+		return uniqFail(ctx, err)
+	}
+
+	input := "-"
+	if len(files) > 0 {
+		input = files[0]
+	}
+	reader, closeInput, err := openInput(input, ctx)
+	if err != nil {
+		return uniqFail(ctx, fmt.Errorf("uniq: не удалось открыть %s: %v", input, fileError(err)))
+	}
+	defer closeInput()
+
+	output := ctx.Stdout
+	if len(files) == 2 && files[1] != "-" {
+		file, createErr := os.Create(ctx.ResolvePath(files[1]))
+		if createErr != nil {
+			return uniqFail(ctx, fmt.Errorf("uniq: не удалось открыть %s: %v", files[1], fileError(createErr)))
+		}
+		defer func() {
+			// Ошибка закрытия может означать, что вывод не записан
+			if closeErr := file.Close(); closeErr != nil && err == nil {
+				err = uniqFail(ctx, fmt.Errorf("uniq: %s: %v", files[1], fileError(closeErr)))
+			}
+		}()
+		output = file
+	}
+
+	source := &catReader{r: reader}
+	err = opts.copy(output, source)
+	if source.err != nil {
+		return uniqFail(ctx, fmt.Errorf("uniq: %s: %v", displayName(input), fileError(source.err)))
+	}
+	return err
+}
+
+// parseUniqArgs разбирает аргументы uniq.
+func parseUniqArgs(args []string) (*uniqOptions, []string, error) {
+	fs := flag.NewFlagSet("uniq", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	opts := &uniqOptions{}
+	for _, names := range []struct {
+		short, long string
+		value       *bool
+		usage       string
+	}{
+		{"c", "count", &opts.count, "печатать количество повторов"},
+		{"d", "repeated", &opts.repeated, "только повторяющиеся строки"},
+		{"u", "unique", &opts.unique, "только неповторяющиеся строки"},
+		{"i", "ignore-case", &opts.ignoreCase, "не различать регистр"},
+	} {
+		fs.BoolVar(names.value, names.short, false, names.usage)
+		fs.BoolVar(names.value, names.long, false, names.usage)
+	}
+	fs.IntVar(&opts.skipFields, "f", 0, "не сравнивать первые N полей")
+	fs.IntVar(&opts.skipFields, "skip-fields", 0, "не сравнивать первые N полей")
+	fs.IntVar(&opts.skipChars, "s", 0, "не сравнивать первые N символов")
+	fs.IntVar(&opts.skipChars, "skip-chars", 0, "не сравнивать первые N символов")
+
+	if err := fs.Parse(splitShortFlags(args, "fs")); err != nil {
+		return nil, nil, fmt.Errorf("uniq: ошибка разбора флагов: %w", err)
+	}
+	if opts.skipFields < 0 || opts.skipChars < 0 {
+		return nil, nil, errors.New("uniq: количество полей и символов не может быть отрицательным")
+	}
+	if fs.NArg() > 2 {
+		return nil, nil, fmt.Errorf("uniq: лишний операнд '%s'", fs.Arg(2))
+	}
+	return opts, fs.Args(), nil
+}
+
+// uniqFail печатает ошибку uniq и возвращает код 1.
+func uniqFail(ctx *CommandContext, err error) error {
+	if _, writeErr := fmt.Fprintln(ctx.Stderr, err); writeErr != nil {
+		return writeErr
+	}
+	return &customErrors.ExitStatusError{Code: 1}
+}
+
+// copy читает строки r и выводит в w по одной строке из каждой группы
+// соседних равных строк. Группа выводится, как только начинается
+// следующая, поэтому uniq можно использовать после tail -f.
+func (o *uniqOptions) copy(w io.Writer, r io.Reader) error {
+	lines := linereader.New(r)
+	var current string
+	count := 0
+	for {
+		line, err := lines.ReadLine()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		text := string(linereader.TrimNewline(line))
+		if count > 0 && o.equal(current, text) {
+			count++
+			continue
+		}
+		if err := o.emit(w, current, count); err != nil {
+			return err
+		}
+		current, count = text, 1
+	}
+	return o.emit(w, current, count)
+}
+
+// emit выводит первую строку группы из count строк с учётом -c, -d и -u.
+func (o *uniqOptions) emit(w io.Writer, line string, count int) error {
+	if count == 0 || o.repeated && count == 1 || o.unique && count > 1 {
+		return nil
+	}
+	var buf []byte
+	if o.count {
+		buf = fmt.Appendf(buf, "%7d ", count)
+	}
+	buf = append(append(buf, line...), '\n')
+	_, err := w.Write(buf)
+	return err
+}
+
+// equal сравнивает строки без первых skipFields полей и skipChars символов.
+func (o *uniqOptions) equal(a, b string) bool {
+	a, b = o.key(a), o.key(b)
+	if o.ignoreCase {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
+
+// key возвращает часть строки, участвующую в сравнении. Поле — это
+// пробелы и следующие за ними непробельные символы, как в GNU uniq.
+func (o *uniqOptions) key(line string) string {
+	pos := fieldStart(line, o.skipFields, "")
+	return line[min(pos+o.skipChars, len(line)):]
+}
+
+// Help возвращает справку по команде uniq.
+func (u *UniqCommand) Help() string {
+	return `NAME
+    uniq - схлопывает повторяющиеся соседние строки
+
+SYNOPSIS
+    uniq [OPTION]... [INPUT [OUTPUT]]
+
+DESCRIPTION
+    Выводит по одной строке из каждой группы подряд идущих одинаковых
+    строк INPUT (по умолчанию — стандартный ввод) в OUTPUT (по умолчанию —
+    стандартный вывод). Несоседние повторы не обнаруживаются, поэтому
+    вход обычно предварительно сортируют.
+
+OPTIONS
+    -c, --count            печатать перед строкой количество повторов
+    -d, --repeated         выводить только повторяющиеся строки
+    -u, --unique           выводить только неповторяющиеся строки
+    -i, --ignore-case      не различать строчные и заглавные буквы
+    -f, --skip-fields=N    не сравнивать первые N полей; поле — это
+                           пробелы и следующие за ними непробельные символы
+    -s, --skip-chars=N     не сравнивать первые N символов (после полей)
+
+EXIT STATUS
+    0 — успех, 1 — неверные аргументы или ошибка чтения.
+
+EXAMPLES
+    sort words.txt | uniq -c
+        → каждое слово с количеством повторов
+
+    sort words.txt | uniq -d
+        → только слова, встречающиеся больше одного раза
+
+    uniq -f 1 log.txt
+        → схлопнуть строки, отличающиеся только первым полем`
+}
+
+var _ BuiltinCommand = (*UniqCommand)(nil)
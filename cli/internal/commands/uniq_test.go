@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUniqCommand(t *testing.T) {
+	const input = "a\na\nb\nc\nc\nc\na\n"
+	tests := []struct {
+		name     string
+		args     []string
+		input    string
+		expected string
+	}{
+		{"default", nil, input, "a\nb\nc\na\n"},
+		{"count", []string{"-c"}, input, "      2 a\n      1 b\n      3 c\n      1 a\n"},
+		{"repeated", []string{"-d"}, input, "a\nc\n"},
+		{"unique", []string{"-u"}, input, "b\na\n"},
+		{"repeated and unique", []string{"-d", "-u"}, input, ""},
+		{"count repeated", []string{"-cd"}, input, "      2 a\n      3 c\n"},
+		{"ignore case", []string{"-i"}, "A\na\nb\n", "A\nb\n"},
+		{"skip fields", []string{"-f", "1"}, "1 x\n2 x\n3 y\n", "1 x\n3 y\n"},
+		{"skip fields with blanks", []string{"-f1"}, "a  x\nb x\n", "a  x\nb x\n"},
+		{"skip chars", []string{"-s", "2"}, "aax\nbbx\nccy\n", "aax\nccy\n"},
+		{"skip fields and chars", []string{"-f", "1", "-s", "2"}, "1 ax\n2 bx\n", "1 ax\n"},
+		{"no trailing newline", nil, "a\na", "a\n"},
+		{"empty", nil, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, stderr, status := runCommand(t, &UniqCommand{}, "", tt.input, tt.args...)
+			if status != 0 || stderr != "" {
+				t.Fatalf("ожидался код 0 без ошибок, получено %d: %q", status, stderr)
+			}
+			if out != tt.expected {
+				t.Errorf("ожидалось %q, получено %q", tt.expected, out)
+			}
+		})
+	}
+}
+
+func TestUniqCommand_Files(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "in.txt"), []byte("x\nx\ny\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, _, status := runCommand(t, &UniqCommand{}, dir, "", "in.txt", "out.txt")
+	if status != 0 || out != "" {
+		t.Fatalf("ожидался код 0 без вывода, получено %d: %q", status, out)
+	}
+	content, err := os.ReadFile(filepath.Join(dir, "out.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "x\ny\n" {
+		t.Errorf("ожидалось %q, получено %q", "x\ny\n", string(content))
+	}
+
+	_, stderr, status := runCommand(t, &UniqCommand{}, dir, "", "missing.txt")
+	if status != 1 || !strings.Contains(stderr, "missing.txt") {
+		t.Errorf("ожидался код 1 и ошибка о missing.txt, получено %d: %q", status, stderr)
+	}
+
+	_, stderr, status = runCommand(t, &UniqCommand{}, dir, "", "a", "b", "c")
+	if status != 1 || !strings.Contains(stderr, "лишний операнд") {
+		t.Errorf("ожидался код 1 и ошибка о лишнем операнде, получено %d: %q", status, stderr)
+	}
+}
@@ -0,0 +1,131 @@
+package commands
+
+import (
+	"fmt"
+
+	customErrors "github.com/ggerlakh/software-design-mhs-itmo/cli/internal/errors"
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
+)
+
+// unsetUsageCode — код возврата unset при неверных параметрах.
+const unsetUsageCode = 2
+
+// UnsetCommand реализует встроенную команду "unset".
+// Она удаляет переменные, массивы и элементы массивов.
+type UnsetCommand struct{}
+
+// Name возвращает имя команды.
+func (u *UnsetCommand) Name() string {
+	return "unset"
+}
+
+// Exec выполняет команду unset.
+//
+// Синтаксис:
+//
+//	unset [-v | -n] NAME|NAME[SUB]...
+//
+// Примеры:
+//
+//	unset tmp       → переменная tmp удалена
+//	unset 'a[1]'    → удалён элемент 1, индексы остальных не меняются
+//	unset -n ref    → удалена сама ссылка ref, а не её цель
+func (u *UnsetCommand) Exec(args []string, ctx *CommandContext) error {
+	nameref := false
+	for len(args) > 0 && len(args[0]) > 1 && args[0][0] == '-' {
+		arg := args[0]
+		args = args[1:]
+		if arg == "--" {
+			break
+		}
+		for _, flag := range arg[1:] {
+			switch flag {
+			case 'v':
+				nameref = false
+			case 'n':
+				nameref = true
+			default:
+				if err := warnf(ctx, "unset: -%c: недопустимая опция", flag); err != nil {
+					return err
+				}
+				return &customErrors.ExitStatusError{Code: unsetUsageCode}
+			}
+		}
+	}
+
+	failed := false
+	for _, ref := range args {
+		if err := unset(ctx, ref, nameref); err != nil {
+			if writeErr := warnf(ctx, "unset: %v", err); writeErr != nil {
+				return writeErr
+			}
+			failed = true
+		}
+	}
+	if failed {
+		return &customErrors.ExitStatusError{Code: 1}
+	}
+	return nil
+}
+
+// Help возвращает справку по команде unset.
+func (u *UnsetCommand) Help() string {
+	return `NAME
+    unset - удаляет переменные и элементы массивов
+
+SYNOPSIS
+    unset [-v | -n] NAME|NAME[SUB]...
+
+DESCRIPTION
+    Удаляет переменную или массив NAME. Запись NAME[SUB] удаляет один
+    элемент массива; индексы остальных элементов не меняются.
+    NAME[@] и NAME[*] удаляют массив целиком. Отсутствующие переменные
+    и элементы пропускаются.
+
+    Для ссылки -n удаляется переменная, на которую она указывает; с
+    флагом -n удаляется сама ссылка. Переменные только для чтения
+    удалить нельзя.
+
+    Индекс в NAME[SUB] нужно заключать в кавычки, чтобы он не был
+    раскрыт как шаблон имён файлов.
+
+OPTIONS
+    -v      NAME — переменная (по умолчанию)
+    -n      NAME — ссылка -n: удаляется она сама
+
+EXIT STATUS
+    0 — успех, 1 — неверное имя или индекс или
+    переменная только для чтения, 2 — неверные параметры.
+
+EXAMPLES
+    unset TMPDIR
+        → переменная TMPDIR удалена
+
+    a=(x y z); unset 'a[1]'; echo ${!a[@]}
+        → 0 2
+
+    declare -A port=([http]=80); unset 'port[http]'
+        → пустой ассоциативный массив`
+}
+
+// unset удаляет переменную или элемент массива ref. При nameref истинном
+// удаляется сама ссылка -n, а не переменная, на которую она указывает.
+func unset(ctx *CommandContext, ref string, nameref bool) error {
+	name, sub, indexed := session.SplitSubscript(ref)
+	if !indexed {
+		name = ref
+	}
+	if !session.IsName(name) {
+		return fmt.Errorf("'%s': недопустимый идентификатор", ref)
+	}
+
+	switch {
+	case nameref && !indexed:
+		return ctx.Vars.UnsetRef(name)
+	case !indexed || sub == "@" || sub == "*":
+		return ctx.Vars.Unset(name)
+	}
+	return ctx.Vars.UnsetElement(name, sub)
+}
+
+var _ BuiltinCommand = (*UnsetCommand)(nil)
@@ -0,0 +1,109 @@
+package commands
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
+)
+
+func TestUnsetCommand(t *testing.T) {
+	ctx := newVariablesContext()
+	setVariable(t, ctx, "s", "v")
+	setVariable(t, ctx, "t", "v")
+	setVariable(t, ctx, "keep", "v")
+	setArray(t, ctx, "a", session.NewIndexedArray("x", "y", "z"))
+	setArray(t, ctx, "b", session.NewIndexedArray("x"))
+	m := session.NewAssocArray()
+	m.Set("k", "v")
+	setArray(t, ctx, "m", m)
+
+	stderr, status := runVariables(t, &UnsetCommand{}, ctx, "-v", "s", "a[1]", "a[-1]", "b[@]", "m[k]", "t[1]", "missing", "missing[2]")
+	if status != 0 || stderr != "" {
+		t.Fatalf("ожидался код 0, получено %d: %q", status, stderr)
+	}
+
+	if _, ok := ctx.Vars.Get("s"); ok {
+		t.Errorf("переменная s должна быть удалена")
+	}
+	if variableValue(ctx, "t") != "v" || variableValue(ctx, "keep") != "v" {
+		t.Errorf("переменные t и keep не должны меняться: %q, %q", variableValue(ctx, "t"), variableValue(ctx, "keep"))
+	}
+	if keys := testArray(ctx, "a").Keys(); !reflect.DeepEqual(keys, []string{"0"}) {
+		t.Errorf("a: ожидались индексы %q, получено %q", []string{"0"}, keys)
+	}
+	if _, ok := ctx.Vars.Array("b"); ok {
+		t.Errorf("массив b должен быть удалён")
+	}
+	if testArray(ctx, "m").Len() != 0 {
+		t.Errorf("m: ожидался пустой массив, получено %q", testArray(ctx, "m").Keys())
+	}
+
+	runVariables(t, &UnsetCommand{}, ctx, "t[0]")
+	if _, ok := ctx.Vars.Get("t"); ok {
+		t.Errorf("t[0] должен удалять обычную переменную")
+	}
+}
+
+func TestUnsetCommand_Errors(t *testing.T) {
+	tests := []struct {
+		args    []string
+		status  int
+		message string
+	}{
+		{[]string{"1x", "ok"}, 1, "unset: '1x': недопустимый идентификатор"},
+		{[]string{"a[x y]"}, 1, "unset: a[x y]: неверный индекс массива"},
+		{[]string{"-f", "a"}, 2, "unset: -f: недопустимая опция"},
+		{[]string{"ro"}, 1, "unset: ro: невозможно удалить: переменная только для чтения"},
+		{[]string{"ro[0]"}, 1, "unset: ro: невозможно удалить: переменная только для чтения"},
+	}
+	for _, tt := range tests {
+		ctx := newVariablesContext()
+		setVariable(t, ctx, "ok", "v")
+		setArray(t, ctx, "a", session.NewIndexedArray("x"))
+		setVariable(t, ctx, "ro", "v")
+		if err := ctx.Vars.Declare("ro", session.AttrReadonly, 0); err != nil {
+			t.Fatal(err)
+		}
+
+		stderr, status := runVariables(t, &UnsetCommand{}, ctx, tt.args...)
+		if status != tt.status || !strings.Contains(stderr, tt.message) {
+			t.Errorf("%v: ожидался код %d и %q, получено %d: %q", tt.args, tt.status, tt.message, status, stderr)
+		}
+	}
+
+	// Ошибка в одном аргументе не мешает удалить остальные
+	ctx := newVariablesContext()
+	setVariable(t, ctx, "ok", "v")
+	runVariables(t, &UnsetCommand{}, ctx, "1x", "ok")
+	if _, ok := ctx.Vars.Get("ok"); ok {
+		t.Errorf("переменная ok должна быть удалена")
+	}
+}
+
+func TestUnsetCommand_Nameref(t *testing.T) {
+	ctx := newVariablesContext()
+	setVariable(t, ctx, "target", "v")
+	if err := ctx.Vars.SetRef("ref", "target"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, status := runVariables(t, &UnsetCommand{}, ctx, "-n", "ref"); status != 0 {
+		t.Fatalf("ожидался код 0, получено %d", status)
+	}
+	if _, ok := ctx.Vars.Lookup("ref"); ok || variableValue(ctx, "target") != "v" {
+		t.Errorf("unset -n должен удалить ссылку и оставить цель")
+	}
+
+	if err := ctx.Vars.SetRef("ref", "target"); err != nil {
+		t.Fatal(err)
+	}
+	runVariables(t, &UnsetCommand{}, ctx, "ref")
+	if _, ok := ctx.Vars.Lookup("target"); ok {
+		t.Errorf("unset без -n должен удалить цель ссылки")
+	}
+	if _, ok := ctx.Vars.Lookup("ref"); !ok {
+		t.Errorf("unset без -n не должен удалять саму ссылку")
+	}
+}
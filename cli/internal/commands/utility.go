@@ -0,0 +1,43 @@
+package commands
+
+import (
+	"errors"
+	"io"
+	"os/exec"
+)
+
+// Коды возврата запуска команд, как в POSIX-оболочках.
+const (
+	utilityCannotRun = 126 // программа найдена, но не запускается
+	utilityNotFound  = 127 // программа не найдена
+)
+
+// spawn запускает команду name с аргументами args через ctx.Run: в
+// каталоге, окружении и с потоками ctx, но со стандартным вводом stdin.
+// Без ctx.Run запускается внешняя программа. Возвращает код возврата.
+func spawn(ctx *CommandContext, name string, args []string, stdin io.Reader) int {
+	child := *ctx
+	child.Stdin = stdin
+	if ctx.Run != nil {
+		return ctx.Run(name, args, &child)
+	}
+
+	external := exec.Command(name, args...) //nolint:gosec // команду задаёт пользователь
+	external.Stdin = child.Stdin
+	external.Stdout = child.Stdout
+	external.Stderr = child.Stderr
+	external.Dir = child.Dir
+	external.Env = child.Vars.Environ()
+
+	err := external.Run()
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &exitErr):
+		return exitErr.ExitCode()
+	case errors.Is(err, exec.ErrNotFound):
+		return utilityNotFound
+	}
+	return utilityCannotRun
+}
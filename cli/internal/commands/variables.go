@@ -0,0 +1,130 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
+)
+
+// Assign выполняет присваивание assignment: NAME=VALUE, NAME+=VALUE,
+// NAME[SUB]=VALUE или NAME[SUB]+=VALUE. Если elements не nil, это
+// составное присваивание массиву NAME=(...) или NAME+=(...), а
+// assignment — его часть до скобки; элементы вида [SUB]=VALUE задают
+// индекс или ключ явно.
+//
+// Присваивание без индекса переменной-массиву меняет его элемент 0, как
+// в bash.
+func Assign(ctx *CommandContext, assignment string, elements []string) error {
+	ref, value, ok := splitAssignment(assignment)
+	if !ok {
+		return fmt.Errorf("'%s': недопустимый идентификатор", assignment)
+	}
+	appendValue := strings.HasSuffix(ref, "+")
+	ref = strings.TrimSuffix(ref, "+")
+
+	name, sub, indexed := session.SplitSubscript(ref)
+	if !indexed {
+		name = ref
+	}
+	if !session.IsName(name) {
+		return fmt.Errorf("'%s': недопустимый идентификатор", ref)
+	}
+
+	switch {
+	case elements != nil && indexed:
+		return fmt.Errorf("%s: элементу массива нельзя присвоить список", ref)
+	case elements != nil:
+		return assignCompound(ctx, name, appendValue, elements)
+	case indexed:
+		return ctx.Vars.AssignElement(name, sub, value, appendValue)
+	}
+	return ctx.Vars.Assign(name, value, appendValue)
+}
+
+// splitAssignment делит присваивание на ссылку на переменную (с "+" для
+// дописывания) и значение. Знак "=" внутри индекса [...] не разделяет их.
+func splitAssignment(assignment string) (ref, value string, ok bool) {
+	start := 0
+	if open := strings.IndexByte(assignment, '['); open >= 0 && open < strings.IndexByte(assignment+"=", '=') {
+		closing := strings.IndexByte(assignment[open:], ']')
+		if closing < 0 {
+			return "", "", false
+		}
+		start = open + closing
+	}
+
+	eq := strings.IndexByte(assignment[start:], '=')
+	if eq < 0 {
+		return "", "", false
+	}
+	return assignment[:start+eq], assignment[start+eq+1:], true
+}
+
+// assignCompound присваивает массиву name элементы составного
+// присваивания. Без дописывания ассоциативный массив остаётся
+// ассоциативным, а остальные переменные становятся индексированными
+// массивами.
+func assignCompound(ctx *CommandContext, name string, appendValues bool, elements []string) error {
+	var err error
+	if appendValues {
+		if _, ok := ctx.Vars.Array(name); !ok {
+			err = ctx.Vars.DeclareArray(name, false)
+		}
+	} else {
+		err = ctx.Vars.ResetArray(name, false)
+	}
+	if err != nil {
+		return err
+	}
+	array, _ := ctx.Vars.Array(name)
+
+	// Элемент без индекса получает индекс после предыдущего элемента
+	next := 0
+	if keys := array.Keys(); len(keys) > 0 && !array.IsAssoc() {
+		last, _ := strconv.Atoi(keys[len(keys)-1])
+		next = last + 1
+	}
+	lookup := func(name string) string {
+		value, _ := ctx.Vars.Get(name)
+		return value
+	}
+
+	for _, element := range elements {
+		ref, value, ok := splitAssignment(element)
+		plus := strings.HasSuffix(ref, "+")
+		ref = strings.TrimSuffix(ref, "+")
+		if !ok || len(ref) < 2 || ref[0] != '[' || ref[len(ref)-1] != ']' {
+			if array.IsAssoc() {
+				return fmt.Errorf("%s: %s: элементу ассоциативного массива нужен ключ [KEY]=VALUE", name, element)
+			}
+			if err := ctx.Vars.AssignElement(name, strconv.Itoa(next), element, false); err != nil {
+				return err
+			}
+			next++
+			continue
+		}
+
+		sub := ref[1 : len(ref)-1]
+		key, err := array.Key(sub, lookup)
+		if err != nil {
+			return fmt.Errorf("%s[%s]: %w", name, sub, err)
+		}
+		if err := ctx.Vars.AssignElement(name, key, value, plus); err != nil {
+			return err
+		}
+		if !array.IsAssoc() {
+			index, _ := strconv.Atoi(key)
+			next = index + 1
+		}
+	}
+	return nil
+}
+
+// variableValue возвращает значение переменной name или пустую строку, если
+// переменная не задана.
+func variableValue(ctx *CommandContext, name string) string {
+	value, _ := ctx.Vars.Get(name)
+	return value
+}
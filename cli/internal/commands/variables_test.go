@@ -0,0 +1,129 @@
+package commands
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
+)
+
+func newVariablesContext() *CommandContext {
+	return &CommandContext{Vars: session.NewVariables(nil)}
+}
+
+// setVariable присваивает значение переменной name в контексте ctx.
+func setVariable(t *testing.T, ctx *CommandContext, name, value string) {
+	t.Helper()
+	if err := ctx.Vars.Set(name, value); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// setArray присваивает массив переменной name в контексте ctx.
+func setArray(t *testing.T, ctx *CommandContext, name string, array *session.Array) {
+	t.Helper()
+	if err := ctx.Vars.SetArray(name, array); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// testArray возвращает массив name или nil, если это не массив.
+func testArray(ctx *CommandContext, name string) *session.Array {
+	array, _ := ctx.Vars.Array(name)
+	return array
+}
+
+func TestAssign(t *testing.T) {
+	ctx := newVariablesContext()
+	steps := []struct {
+		assignment string
+		elements   []string
+	}{
+		{"a=", []string{"x", "y"}},
+		{"a+=", []string{"z"}},
+		{"a[5]=five", nil},
+		{"a[1]+=!", nil},
+		{"a=first", nil},
+		{"s=v", nil},
+		{"s+=w", nil},
+		{"b=", []string{"[2]=two", "three", "[0]=zero"}},
+		{"c=", []string{}},
+	}
+	for _, step := range steps {
+		if err := Assign(ctx, step.assignment, step.elements); err != nil {
+			t.Fatalf("%q %q: неожиданная ошибка: %v", step.assignment, step.elements, err)
+		}
+	}
+
+	if keys := testArray(ctx, "a").Keys(); !reflect.DeepEqual(keys, []string{"0", "1", "2", "5"}) {
+		t.Errorf("a: неверные индексы %q", keys)
+	}
+	if values := testArray(ctx, "a").Values(); !reflect.DeepEqual(values, []string{"first", "y!", "z", "five"}) {
+		t.Errorf("a: неверные значения %q", values)
+	}
+	if values := testArray(ctx, "b").Values(); !reflect.DeepEqual(values, []string{"zero", "two", "three"}) {
+		t.Errorf("b: неверные значения %q", values)
+	}
+	if c, ok := ctx.Vars.Array("c"); !ok || c.Len() != 0 {
+		t.Errorf("c: ожидался пустой массив, получено %v", c)
+	}
+	if variableValue(ctx, "s") != "vw" {
+		t.Errorf("s: ожидалось %q, получено %q", "vw", variableValue(ctx, "s"))
+	}
+}
+
+func TestAssign_ScalarBecomesArray(t *testing.T) {
+	ctx := newVariablesContext()
+	setVariable(t, ctx, "v", "old")
+
+	if err := Assign(ctx, "v+=", []string{"new"}); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if _, ok := ctx.Vars.Array("v"); !ok {
+		t.Fatalf("переменная v должна стать массивом")
+	}
+	if values := testArray(ctx, "v").Values(); !reflect.DeepEqual(values, []string{"old", "new"}) {
+		t.Errorf("ожидалось %q, получено %q", []string{"old", "new"}, values)
+	}
+}
+
+func TestAssign_Assoc(t *testing.T) {
+	ctx := newVariablesContext()
+	setArray(t, ctx, "m", session.NewAssocArray())
+
+	if err := Assign(ctx, "m=", []string{"[b]=2", "[a]=1"}); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if err := Assign(ctx, "m[k=v]=3", nil); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	m := testArray(ctx, "m")
+	if !m.IsAssoc() || !reflect.DeepEqual(m.Keys(), []string{"b", "a", "k=v"}) {
+		t.Errorf("ожидался ассоциативный массив с ключами b, a, k=v, получено %q", m.Keys())
+	}
+
+	if err := Assign(ctx, "m=", []string{"plain"}); err == nil || !strings.Contains(err.Error(), "нужен ключ") {
+		t.Errorf("элемент без ключа должен быть ошибкой, получено %v", err)
+	}
+}
+
+func TestAssign_Errors(t *testing.T) {
+	tests := []struct {
+		assignment string
+		elements   []string
+		message    string
+	}{
+		{"1a=x", nil, "недопустимый идентификатор"},
+		{"a[x y]=1", nil, "неверный индекс массива"},
+		{"a[-1]=1", nil, "неверный индекс массива"},
+		{"a[0]=", []string{"x"}, "нельзя присвоить список"},
+		{"a[0=1", nil, "недопустимый идентификатор"},
+	}
+	for _, tt := range tests {
+		err := Assign(newVariablesContext(), tt.assignment, tt.elements)
+		if err == nil || !strings.Contains(err.Error(), tt.message) {
+			t.Errorf("%q: ожидалась ошибка %q, получено %v", tt.assignment, tt.message, err)
+		}
+	}
+}
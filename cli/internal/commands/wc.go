@@ -1,126 +1,478 @@
 package commands
 
 import (
-	"bufio"
+	"bytes"
+	"flag"
 	"fmt"
 	"io"
 	"os"
+	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	customErrors "github.com/ggerlakh/software-design-mhs-itmo/cli/internal/errors"
 )
 
 // WcCommand реализует встроенную команду "wc".
-// Она выводит количество строк, слов и байт в файле или stdin.
+// Она выводит количество строк, слов, символов и байт в файлах или stdin.
+// Подсчёт ведётся по исходным байтам, поэтому CRLF и отсутствие перевода
+// строки в конце файла учитываются так же, как в GNU wc.
 type WcCommand struct{}
 
+// Режимы итоговой строки (--total).
+const (
+	wcTotalAuto   = "auto"   // только если файлов больше одного
+	wcTotalAlways = "always" // всегда
+	wcTotalOnly   = "only"   // только итог, без строк файлов и без слова "total"
+	wcTotalNever  = "never"  // никогда
+)
+
+// wcTabWidth — шаг позиций табуляции для -L.
+const wcTabWidth = 8
+
+// wcOptions — выбранные счётчики и параметры вывода wc.
+type wcOptions struct {
+	lines, words, chars, bytes, maxLine bool
+
+	files0From string
+	total      string
+}
+
+// needRunes сообщает, что для подсчёта нужно декодировать UTF-8.
+// Для -l и -c достаточно считать байты и переводы строк.
+func (o *wcOptions) needRunes() bool {
+	return o.words || o.chars || o.maxLine
+}
+
+// wcCounts — результаты подсчёта для одного файла или итога.
+type wcCounts struct {
+	lines, words, chars, bytes, maxLine int64
+}
+
+// add прибавляет счётчики other к итогу. Для -L итог — максимум.
+func (c *wcCounts) add(other wcCounts) {
+	c.lines += other.lines
+	c.words += other.words
+	c.chars += other.chars
+	c.bytes += other.bytes
+	c.maxLine = max(c.maxLine, other.maxLine)
+}
+
+// values возвращает выбранные счётчики в порядке GNU wc:
+// строки, слова, символы, байты, длина самой длинной строки.
+func (c *wcCounts) values(opts *wcOptions) []int64 {
+	var values []int64
+	if opts.lines {
+		values = append(values, c.lines)
+	}
+	if opts.words {
+		values = append(values, c.words)
+	}
+	if opts.chars {
+		values = append(values, c.chars)
+	}
+	if opts.bytes {
+		values = append(values, c.bytes)
+	}
+	if opts.maxLine {
+		values = append(values, c.maxLine)
+	}
+	return values
+}
+
+// wcCounter считает строки, слова, символы и ширину строк по мере записи
+// в него байтов. Многобайтовый символ может быть разрезан между вызовами
+// Write: его начало сохраняется в pending до следующего вызова.
+type wcCounter struct {
+	wcCounts
+
+	runes   bool   // декодировать UTF-8 (нужно для -w, -m и -L)
+	inWord  bool   // предыдущий символ принадлежит слову
+	linePos int64  // ширина текущей строки для -L
+	pending []byte // незавершённая последовательность UTF-8
+}
+
+// Write учитывает очередную порцию байтов. Никогда не возвращает ошибку.
+func (c *wcCounter) Write(p []byte) (int, error) {
+	n := len(p)
+	c.bytes += int64(n)
+	if !c.runes {
+		c.lines += int64(bytes.Count(p, []byte{'\n'}))
+		return n, nil
+	}
+
+	if len(c.pending) > 0 {
+		p = append(c.pending, p...)
+	}
+	rest := p[c.scan(p):]
+	c.pending = append(c.pending[:0:0], rest...)
+	return n, nil
+}
+
+// finish учитывает незавершённую последовательность в конце ввода:
+// её байты не образуют символов, но продолжают слово.
+func (c *wcCounter) finish() {
+	if len(c.pending) > 0 {
+		c.startWord()
+		c.pending = nil
+	}
+}
+
+// scan обрабатывает p и возвращает количество обработанных байтов.
+// Обработка останавливается на незавершённом символе в конце p.
+func (c *wcCounter) scan(p []byte) int {
+	i := 0
+	for i < len(p) {
+		if !utf8.FullRune(p[i:]) {
+			return i
+		}
+		r, size := utf8.DecodeRune(p[i:])
+		i += size
+
+		// Некорректный байт не считается символом, но продолжает слово
+		if r == utf8.RuneError && size == 1 {
+			c.startWord()
+			continue
+		}
+		c.chars++
+
+		switch r {
+		case '\n':
+			c.lines++
+			c.linePos = 0
+			c.inWord = false
+		case '\r', '\f':
+			c.linePos = 0
+			c.inWord = false
+		case '\t':
+			c.linePos += wcTabWidth - c.linePos%wcTabWidth
+			c.inWord = false
+		default:
+			c.linePos += int64(runeWidth(r))
+			if unicode.IsSpace(r) {
+				c.inWord = false
+			} else {
+				c.startWord()
+			}
+		}
+		c.maxLine = max(c.maxLine, c.linePos)
+	}
+	return i
+}
+
+// startWord отмечает символ слова и считает слово, если оно только началось.
+func (c *wcCounter) startWord() {
+	if !c.inWord {
+		c.words++
+		c.inWord = true
+	}
+}
+
+// runeWidth возвращает ширину символа r в колонках терминала:
+// 0 для управляющих и комбинируемых символов, 2 для широких
+// восточноазиатских символов и эмодзи, 1 для остальных.
+func runeWidth(r rune) int {
+	switch {
+	case r == ' ':
+		return 1
+	case !unicode.IsPrint(r), unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf):
+		return 0
+	case r >= 0x1100 && r <= 0x115F,
+		r >= 0x2E80 && r <= 0x303E,
+		r >= 0x3041 && r <= 0x33FF,
+		r >= 0x3400 && r <= 0x4DBF,
+		r >= 0x4E00 && r <= 0x9FFF,
+		r >= 0xA000 && r <= 0xA4CF,
+		r >= 0xAC00 && r <= 0xD7A3,
+		r >= 0xF900 && r <= 0xFAFF,
+		r >= 0xFE30 && r <= 0xFE4F,
+		r >= 0xFF00 && r <= 0xFF60,
+		r >= 0xFFE0 && r <= 0xFFE6,
+		r >= 0x1F300 && r <= 0x1F64F,
+		r >= 0x1F900 && r <= 0x1F9FF,
+		r >= 0x20000 && r <= 0x3FFFD:
+		return 2
+	}
+	return 1
+}
+
 // Name возвращает имя команды.
 func (w *WcCommand) Name() string {
 	return "wc"
 }
 
 // Exec выполняет команду wc с переданными аргументами.
-// Если не указан файл, читается stdin.
+// Если не указан файл, читается stdin; "-" также означает stdin.
+// Флаги -l, -w, -m, -c и -L можно сочетать; без флагов выводятся
+// строки, слова и байты. Если файлов несколько, в конце печатается
+// строка "total". Числа выравниваются по ширине, как в GNU wc.
+//
+// Возвращает ExitStatusError с кодом 1, если какой-либо файл не удалось прочитать.
 //
 // Примеры:
 //
-//	wc file.txt    → 3 10 55   (3 строки, 10 слов, 55 байт)
-//	wc -l file.txt → 3         (только количество строк)
+//	wc file.txt           →  3 10 55 file.txt (3 строки, 10 слов, 55 байт)
+//	wc -l file.txt        → 3 file.txt        (только количество строк)
+//	wc -lw a.txt b.txt    → строки и слова каждого файла и итог
 func (w *WcCommand) Exec(args []string, ctx *CommandContext) error {
-	var files []string
-	showLines, showWords, showBytes := true, true, true
-
-	// Разбор опций
-	for len(args) > 0 && strings.HasPrefix(args[0], "-") {
-		switch args[0] {
-		case "-l":
-			showLines, showWords, showBytes = true, false, false
-		case "-w":
-			showLines, showWords, showBytes = false, true, false
-		case "-c":
-			showLines, showWords, showBytes = false, false, true
+	opts, files, err := parseWcFlags(args)
+	if err != nil {
+		return wcFail(ctx, err)
+	}
+
+	if opts.files0From != "" {
+		if len(files) > 0 {
+			return wcFail(ctx, fmt.Errorf("wc: лишний операнд %q: с --files0-from файлы указываются только в списке", files[0]))
+		}
+		if files, err = readFiles0(opts.files0From, ctx); err != nil {
+			return wcFail(ctx, err)
 		}
-		args = args[1:]
 	}
 
-	if len(args) == 0 {
-		files = []string{"-"} // читаем stdin
-	} else {
-		files = args
+	// Без файлов читаем stdin и не печатаем имя
+	names := files
+	if len(files) == 0 && opts.files0From == "" {
+		files, names = []string{"-"}, []string{""}
 	}
 
-	for _, f := range files {
-		var reader io.Reader
+	showTotal := opts.total == wcTotalAlways || opts.total == wcTotalOnly ||
+		opts.total == wcTotalAuto && len(files) > 1
+	width := wcNumberWidth(files, opts, ctx)
+	if opts.total == wcTotalOnly {
+		width = 1
+	}
 
-		if f == "-" {
-			reader = ctx.Stdin
-		} else {
-			//nolint:gosec // открываем файлы, как делает обычный cat, пользователь сам контролирует доступ
-			file, err := os.Open(f)
-			if err != nil {
-				if _, writeErr := fmt.Fprintf(ctx.Stderr, "wc: не удалось открыть файл %s: %v\n", f, err); writeErr != nil {
-					// Игнорируем ошибку записи в stderr
-					_ = writeErr
-				}
-				continue
+	var total wcCounts
+	failed := false
+	for i, name := range files {
+		counts, err := wcFile(name, opts, ctx)
+		if err != nil {
+			failed = true
+			if _, writeErr := fmt.Fprintln(ctx.Stderr, err); writeErr != nil {
+				return writeErr
 			}
-			defer func(f *os.File) {
-				if err := f.Close(); err != nil {
-					if _, writeErr := fmt.Fprintf(ctx.Stderr, "ошибка при закрытии файла %s: %v\n", f.Name(), err); writeErr != nil {
-						// Игнорируем ошибку записи в stderr
-						_ = writeErr
-					}
-				}
-			}(file)
-			reader = file
+			continue
 		}
+		total.add(counts)
 
-		scanner := bufio.NewScanner(reader)
-		lines, words, bytesCount := 0, 0, 0
+		if opts.total == wcTotalOnly {
+			continue
+		}
+		if err := wcPrint(ctx.Stdout, counts.values(opts), width, names[i]); err != nil {
+			return err
+		}
+	}
 
-		for scanner.Scan() {
-			line := scanner.Text()
-			lines++
-			words += len(strings.Fields(line))
-			bytesCount += len(line) + 1 // +1 для \n
+	if showTotal {
+		label := "total"
+		if opts.total == wcTotalOnly {
+			label = ""
 		}
+		if err := wcPrint(ctx.Stdout, total.values(opts), width, label); err != nil {
+			return err
+		}
+	}
+
+	if failed {
+		return &customErrors.ExitStatusError{Code: 1}
+	}
+	return nil
+}
+
+// parseWcFlags разбирает флаги wc и возвращает параметры и список файлов.
+// Без флагов счётчиков выбираются строки, слова и байты.
+func parseWcFlags(args []string) (*wcOptions, []string, error) {
+	fs := flag.NewFlagSet("wc", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	opts := &wcOptions{}
+	for _, names := range []struct {
+		short, long string
+		value       *bool
+		usage       string
+	}{
+		{"l", "lines", &opts.lines, "количество строк"},
+		{"w", "words", &opts.words, "количество слов"},
+		{"m", "chars", &opts.chars, "количество символов"},
+		{"c", "bytes", &opts.bytes, "количество байт"},
+		{"L", "max-line-length", &opts.maxLine, "ширина самой длинной строки"},
+	} {
+		fs.BoolVar(names.value, names.short, false, names.usage)
+		fs.BoolVar(names.value, names.long, false, names.usage)
+	}
+	fs.StringVar(&opts.files0From, "files0-from", "", "читать имена файлов, разделённые NUL, из файла")
+	fs.StringVar(&opts.total, "total", wcTotalAuto, "когда печатать итоговую строку")
 
-		output := ""
-		if showLines {
-			output += fmt.Sprintf("%d ", lines)
+	if err := fs.Parse(splitShortFlags(args, "")); err != nil {
+		return nil, nil, fmt.Errorf("wc: ошибка разбора флагов: %w", err)
+	}
+
+	switch opts.total {
+	case wcTotalAuto, wcTotalAlways, wcTotalOnly, wcTotalNever:
+	default:
+		return nil, nil, fmt.Errorf("wc: неизвестный режим итога %q", opts.total)
+	}
+
+	if !opts.lines && !opts.words && !opts.chars && !opts.bytes && !opts.maxLine {
+		opts.lines, opts.words, opts.bytes = true, true, true
+	}
+	return opts, fs.Args(), nil
+}
+
+// readFiles0 читает список имён файлов, разделённых нулевым байтом,
+// из файла name ("-" — stdin). Пустые имена недопустимы.
+func readFiles0(name string, ctx *CommandContext) ([]string, error) {
+	var data []byte
+	var err error
+	if name == "-" {
+		data, err = io.ReadAll(ctx.Stdin)
+	} else {
+		data, err = os.ReadFile(ctx.ResolvePath(name))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("wc: не удалось прочитать список файлов %s: %v", name, fileError(err))
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	files := strings.Split(strings.TrimSuffix(string(data), "\x00"), "\x00")
+	for i, file := range files {
+		if file == "" {
+			return nil, fmt.Errorf("wc: %s: пустое имя файла в элементе %d", name, i+1)
 		}
-		if showWords {
-			output += fmt.Sprintf("%d ", words)
+		if file == "-" && name == "-" {
+			return nil, fmt.Errorf("wc: при --files0-from=- имя файла \"-\" недопустимо")
 		}
-		if showBytes {
-			output += fmt.Sprintf("%d ", bytesCount)
+	}
+	return files, nil
+}
+
+// wcFile подсчитывает содержимое файла name ("-" — stdin).
+// Файл закрывается сразу после подсчёта.
+func wcFile(name string, opts *wcOptions, ctx *CommandContext) (wcCounts, error) {
+	counter := &wcCounter{runes: opts.needRunes()}
+
+	if name == "-" {
+		if _, err := io.Copy(counter, ctx.Stdin); err != nil {
+			return wcCounts{}, fmt.Errorf("wc: -: %v", fileError(err))
 		}
+		counter.finish()
+		return counter.wcCounts, nil
+	}
 
-		if _, err := fmt.Fprintln(ctx.Stdout, strings.TrimSpace(output)); err != nil {
-			return err
+	//nolint:gosec // открываем файлы, как делает обычный wc, пользователь сам контролирует доступ
+	file, err := os.Open(ctx.ResolvePath(name))
+	if err != nil {
+		return wcCounts{}, fmt.Errorf("wc: %s: %v", name, fileError(err))
+	}
+	defer func() { _ = file.Close() }()
+
+	if _, err := io.Copy(counter, file); err != nil {
+		return wcCounts{}, fmt.Errorf("wc: %s: %v", name, fileError(err))
+	}
+	counter.finish()
+	return counter.wcCounts, nil
+}
+
+// wcNumberWidth вычисляет ширину колонок так же, как GNU wc: по числу
+// цифр суммарного размера обычных файлов. Если среди входов есть
+// stdin или не обычный файл, ширина не меньше 7. Единственное число
+// для единственного файла печатается без выравнивания.
+func wcNumberWidth(files []string, opts *wcOptions, ctx *CommandContext) int {
+	if len(files) == 1 && len(new(wcCounts).values(opts)) == 1 {
+		return 1
+	}
+
+	minimum := 1
+	var size int64
+	for _, name := range files {
+		var info os.FileInfo
+		var err error
+		if name == "-" {
+			file, ok := ctx.Stdin.(*os.File)
+			if !ok {
+				minimum = 7
+				continue
+			}
+			info, err = file.Stat()
+		} else {
+			info, err = os.Stat(ctx.ResolvePath(name))
+		}
+
+		switch {
+		case err != nil:
+			// Ошибка будет выведена при подсчёте
+		case info.Mode().IsRegular():
+			size += info.Size()
+		default:
+			minimum = 7
 		}
 	}
+	return max(len(strconv.FormatInt(size, 10)), minimum)
+}
 
-	return nil
+// wcPrint печатает счётчики, выровненные по ширине width, и имя файла,
+// если оно не пустое.
+func wcPrint(w io.Writer, values []int64, width int, name string) error {
+	fields := make([]string, 0, len(values)+1)
+	for _, value := range values {
+		fields = append(fields, fmt.Sprintf("%*d", width, value))
+	}
+	if name != "" {
+		fields = append(fields, name)
+	}
+	_, err := fmt.Fprintln(w, strings.Join(fields, " "))
+	return err
+}
+
+// wcFail печатает ошибку в stderr и возвращает код возврата 1.
+func wcFail(ctx *CommandContext, err error) error {
+	if _, writeErr := fmt.Fprintln(ctx.Stderr, err); writeErr != nil {
+		return writeErr
+	}
+	return &customErrors.ExitStatusError{Code: 1}
 }
 
 // Help возвращает справку по команде wc.
 func (w *WcCommand) Help() string {
 	return `NAME
-    wc - подсчитывает количество строк, слов и байт в файле
+    wc - подсчитывает количество строк, слов, символов и байт в файлах
 
 SYNOPSIS
     wc [OPTION]... [FILE]...
+    wc [OPTION]... --files0-from=F
 
 DESCRIPTION
-    Выводит количество строк, слов и байт в файле или stdin.
+    Выводит количество строк, слов и байт для каждого файла и итоговую
+    строку "total", если файлов больше одного. Если файл не указан или
+    указан "-", читается стандартный ввод.
+
+    Подсчёт ведётся по исходным байтам: строка — это перевод строки \n,
+    поэтому последняя строка без \n не учитывается, а \r в CRLF-файлах
+    считается байтом и символом. Слово — последовательность символов,
+    отличных от пробельных. Числа выравниваются по ширине, как в GNU wc.
 
 OPTIONS
-    -l    показывать только количество строк
-    -w    показывать только количество слов
-    -c    показывать только количество байт
+    -l, --lines             количество строк
+    -w, --words             количество слов
+    -m, --chars             количество символов UTF-8
+    -c, --bytes             количество байт
+    -L, --max-line-length   ширина самой длинной строки (TAB — до кратной 8 позиции)
+    --files0-from=F         читать имена файлов, разделённые NUL, из F ("-" — stdin)
+    --total=WHEN            когда печатать итог: auto, always, only, never
+
+    Флаги можно сочетать (-lw); значения выводятся в порядке
+    строки, слова, символы, байты, ширина строки.
 
 EXAMPLES
     wc file.txt
-        → 3 10 55
+        → 3 10 55 file.txt
     wc -l file.txt
-        → 3`
+        → 3 file.txt
+    wc -lw a.txt b.txt
+        → строки и слова каждого файла и строка total
+    wc -l --files0-from=list
+        → количество строк в файлах, перечисленных в list через NUL`
 }
@@ -2,10 +2,14 @@ package commands
 
 import (
 	"bytes"
+	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
 )
 
 // testExecWithOutput выполняет команду с переданными аргументами и возвращает вывод
@@ -18,7 +22,7 @@ func testWcExecWithOutput(cmd CommandExecutor, args []string, stdin io.Reader) s
 		Stdin:  stdin,
 		Stdout: &buf,
 		Stderr: os.Stderr,
-		Env:    make(map[string]string),
+		Vars:   session.NewVariables(nil),
 		Dir:    ".",
 	}
 	cmd.Exec(args, ctx)
@@ -33,9 +37,9 @@ func TestWcCommand_LinesWordsBytes(t *testing.T) {
 	cmd := &WcCommand{}
 	out := testWcExecWithOutput(cmd, []string{tmp}, nil)
 
-	if out != "3 6 20\n" {
-		// проверка на общий формат
-		t.Logf("проверка вывода: %q", out)
+	expected := " 3  6 28 " + tmp + "\n"
+	if out != expected {
+		t.Errorf("ожидалось %q, получено %q", expected, out)
 	}
 }
 
@@ -47,7 +51,7 @@ func TestWcCommand_OnlyLines(t *testing.T) {
 	cmd := &WcCommand{}
 	out := testWcExecWithOutput(cmd, []string{"-l", tmp}, nil)
 
-	expected := "3\n"
+	expected := "3 " + tmp + "\n"
 	if out != expected {
 		t.Errorf("ожидалось %q, получено %q", expected, out)
 	}
@@ -64,3 +68,140 @@ func TestWcCommand_Stdin(t *testing.T) {
 		t.Errorf("ожидалось %q, получено %q", expected, out)
 	}
 }
+
+func TestWcCommand_Counts(t *testing.T) {
+	tests := []struct {
+		name     string
+		stdin    string
+		args     []string
+		expected string
+	}{
+		{"CRLF", "a b\r\nc\r\n", []string{"-lwc"}, "      2       3       8\n"},
+		{"без перевода строки в конце", "one\ntwo", []string{"-l", "-c"}, "      1       7\n"},
+		{"пустой ввод", "", nil, "      0       0       0\n"},
+		{"символы UTF-8", "привет мир\n", []string{"-m"}, "11\n"},
+		{"байты UTF-8", "привет мир\n", []string{"-c"}, "20\n"},
+		{"порядок колонок не зависит от флагов", "ab cd\n", []string{"-c", "-m", "-w", "-l"},
+			"      1       2       6       6\n"},
+		{"длинные флаги", "ab cd\n", []string{"--lines", "--words"}, "      1       2\n"},
+		{"-L с табуляцией", "ab\tc\nxyz\n", []string{"-L"}, "9\n"},
+		{"-L широкие символы", "日本\nabc\n", []string{"-L"}, "4\n"},
+		{"-L без перевода строки", "abc\nabcdef", []string{"-L"}, "6\n"},
+		{"некорректный UTF-8", "a\xff\xfeb c\n", []string{"-wm"}, "      2       5\n"},
+		{"пробелы Unicode разделяют слова", "a\u2003b\u3000c\n", []string{"-w"}, "3\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, stderr, status := runCommand(t, &WcCommand{}, t.TempDir(), tt.stdin, tt.args...)
+			if out != tt.expected || status != 0 {
+				t.Fatalf("ожидалось %q, получено %q (код %d, stderr %q)", tt.expected, out, status, stderr)
+			}
+		})
+	}
+}
+
+func TestWcCounter_SplitRunes(t *testing.T) {
+	input := []byte("жёлтый 日本語 слово\n")
+
+	// Символы режутся между вызовами Write на каждой возможной границе
+	for split := 1; split < len(input); split++ {
+		counter := &wcCounter{runes: true}
+		_, _ = counter.Write(input[:split])
+		_, _ = counter.Write(input[split:])
+		counter.finish()
+
+		expected := wcCounts{lines: 1, words: 3, chars: 17, bytes: int64(len(input)), maxLine: 19}
+		if counter.wcCounts != expected {
+			t.Fatalf("разрез на %d: ожидалось %+v, получено %+v", split, expected, counter.wcCounts)
+		}
+	}
+}
+
+func TestWcCommand_MultipleFiles(t *testing.T) {
+	dir := t.TempDir()
+	_ = os.WriteFile(filepath.Join(dir, "a.txt"), []byte("one two\nthree\n"), 0o644)
+	_ = os.WriteFile(filepath.Join(dir, "b.txt"), []byte(strings.Repeat("x\n", 50)), 0o644)
+
+	out, _, status := runCommand(t, &WcCommand{}, dir, "", "a.txt", "b.txt")
+	expected := "  2   3  14 a.txt\n 50  50 100 b.txt\n 52  53 114 total\n"
+	if out != expected || status != 0 {
+		t.Fatalf("ожидалось %q, получено %q (код %d)", expected, out, status)
+	}
+
+	out, _, _ = runCommand(t, &WcCommand{}, dir, "", "-l", "--total=only", "a.txt", "b.txt")
+	if expected := "52\n"; out != expected {
+		t.Fatalf("--total=only: ожидалось %q, получено %q", expected, out)
+	}
+
+	out, _, _ = runCommand(t, &WcCommand{}, dir, "", "-l", "--total=never", "a.txt", "b.txt")
+	if expected := "  2 a.txt\n 50 b.txt\n"; out != expected {
+		t.Fatalf("--total=never: ожидалось %q, получено %q", expected, out)
+	}
+
+	// stdin среди файлов расширяет колонки до 7 символов
+	out, _, _ = runCommand(t, &WcCommand{}, dir, "a\n", "-l", "a.txt", "-")
+	if expected := "      2 a.txt\n      1 -\n      3 total\n"; out != expected {
+		t.Fatalf("со stdin: ожидалось %q, получено %q", expected, out)
+	}
+}
+
+func TestWcCommand_MissingFile(t *testing.T) {
+	dir := t.TempDir()
+	_ = os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a\nb\n"), 0o644)
+
+	out, stderr, status := runCommand(t, &WcCommand{}, dir, "", "-l", "a.txt", "missing.txt")
+	expected := "2 a.txt\n2 total\n"
+	if out != expected || status != 1 || !strings.Contains(stderr, "missing.txt") {
+		t.Fatalf("ожидалось %q и ошибка для missing.txt, получено %q, %q (код %d)", expected, out, stderr, status)
+	}
+	// Имя файла печатается так, как его указал пользователь
+	if !strings.HasPrefix(stderr, "wc: missing.txt: ") || strings.Contains(stderr, dir) {
+		t.Fatalf("ожидалось сообщение %q без пути каталога, получено %q", "wc: missing.txt: ...", stderr)
+	}
+
+	_, stderr, status = runCommand(t, &WcCommand{}, dir, "", "-x")
+	if status != 1 || !strings.Contains(stderr, "ошибка разбора флагов") {
+		t.Fatalf("ожидалась ошибка разбора флагов: %q (код %d)", stderr, status)
+	}
+}
+
+func TestWcCommand_Files0From(t *testing.T) {
+	dir := t.TempDir()
+	_ = os.WriteFile(filepath.Join(dir, "a.txt"), []byte("a\nb\n"), 0o644)
+	_ = os.WriteFile(filepath.Join(dir, "b c.txt"), []byte("c\n"), 0o644)
+	_ = os.WriteFile(filepath.Join(dir, "list"), []byte("a.txt\x00b c.txt\x00"), 0o644)
+
+	out, _, status := runCommand(t, &WcCommand{}, dir, "", "-l", "--files0-from=list")
+	expected := "2 a.txt\n1 b c.txt\n3 total\n"
+	if out != expected || status != 0 {
+		t.Fatalf("ожидалось %q, получено %q (код %d)", expected, out, status)
+	}
+
+	out, _, _ = runCommand(t, &WcCommand{}, dir, "a.txt", "-c", "--files0-from=-")
+	if expected := "4 a.txt\n"; out != expected {
+		t.Fatalf("список из stdin: ожидалось %q, получено %q", expected, out)
+	}
+
+	_, stderr, status := runCommand(t, &WcCommand{}, dir, "a.txt\x00\x00b c.txt", "--files0-from=-")
+	if status != 1 || !strings.Contains(stderr, "пустое имя") {
+		t.Fatalf("ожидалась ошибка пустого имени: %q (код %d)", stderr, status)
+	}
+
+	_, stderr, status = runCommand(t, &WcCommand{}, dir, "", "--files0-from=list", "a.txt")
+	if status != 1 || !strings.Contains(stderr, "лишний операнд") {
+		t.Fatalf("ожидалась ошибка лишнего операнда: %q (код %d)", stderr, status)
+	}
+}
+
+func TestWcCommand_LongLines(t *testing.T) {
+	long := strings.Repeat("слово ", 1024*1024)
+	input := long + "\n" + long
+
+	out, _, status := runCommand(t, &WcCommand{}, t.TempDir(), input, "-lwmcL")
+	runes := int64(2 * 6 * 1024 * 1024)
+	expected := fmt.Sprintf("%7d %7d %7d %7d %7d\n", 1, 2*1024*1024, runes+1, len(input), 6*1024*1024)
+	if out != expected || status != 0 {
+		t.Fatalf("ожидалось %q, получено %q (код %d)", expected, out, status)
+	}
+}
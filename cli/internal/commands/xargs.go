@@ -0,0 +1,434 @@
+package commands
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"unicode"
+
+	customErrors "github.com/ggerlakh/software-design-mhs-itmo/cli/internal/errors"
+)
+
+// Коды возврата xargs, как в GNU xargs.
+const (
+	xargsUsageCode   = 1
+	xargsFailedCode  = 123 // команда завершилась с кодом 1–125
+	xargsAbortedCode = 124 // команда завершилась с кодом 255: xargs прекращает работу
+	xargsKilledCode  = 125 // команда прервана сигналом
+)
+
+// xargsMaxChars — наибольшая суммарная длина аргументов из ввода в одном
+// вызове команды без -n.
+const xargsMaxChars = 128 * 1024
+
+// XargsCommand реализует встроенную команду "xargs".
+// Она строит командные строки из аргументов, прочитанных из stdin.
+type XargsCommand struct{}
+
+// xargsOptions — параметры xargs.
+type xargsOptions struct {
+	maxArgs    int    // -n: не больше аргументов из ввода в одном вызове; 0 — без ограничения
+	replace    string // -I: заменяемая строка; каждая строка ввода — отдельный вызов
+	delimiter  byte   // -0, -d: разделитель элементов ввода
+	delimited  bool   // задан delimiter: кавычки и пробелы не обрабатываются
+	parallel   int    // -P: одновременных вызовов; 0 — без ограничения
+	noRunEmpty bool   // -r: не запускать команду при пустом вводе
+	verbose    bool   // -t: печатать команду в stderr перед запуском
+}
+
+// Name возвращает имя команды.
+func (x *XargsCommand) Name() string {
+	return "xargs"
+}
+
+// Exec выполняет команду xargs.
+//
+// Синтаксис:
+//
+//	xargs [-0 | -d DELIM] [-n MAX | -I REPLACE] [-P N] [-r] [-t] [COMMAND [ARG...]]
+//
+// По умолчанию элементы ввода разделяются пробельными символами, а
+// кавычки и обратная косая черта экранируют их. Команда — встроенная или
+// внешняя, по умолчанию echo; её stdin пуст.
+//
+// Примеры:
+//
+//	find . -name '*.go' -print0 | xargs -0 wc -l
+//	cat urls.txt | xargs -n 1 -P 4 curl -O
+//	ls *.jpg | xargs -I {} cp {} backup/{}
+func (x *XargsCommand) Exec(args []string, ctx *CommandContext) error {
+	opts, command, err := parseXargsArgs(args)
+	if err != nil {
+		if writeErr := warnf(ctx, "%v\nПопробуйте 'xargs --help' для получения справки.", err); writeErr != nil {
+			return writeErr
+		}
+		return &customErrors.ExitStatusError{Code: xargsUsageCode}
+	}
+
+	r := &xargsRunner{opts: opts, command: command, ctx: ctx}
+	if opts.parallel != 1 {
+		// Одновременные вызовы пишут в общие потоки
+		lock := &sync.Mutex{}
+		child := *ctx
+		child.Stdout = &syncWriter{lock: lock, w: ctx.Stdout}
+		child.Stderr = &syncWriter{lock: lock, w: ctx.Stderr}
+		r.ctx = &child
+	}
+	if opts.parallel > 0 {
+		r.slots = make(chan struct{}, opts.parallel)
+	}
+
+	readErr := r.read(&xargsReader{in: bufio.NewReader(ctx.Stdin), opts: opts})
+	r.wg.Wait()
+
+	switch {
+	case readErr != nil:
+		if err := warnf(ctx, "xargs: %v", readErr); err != nil {
+			return err
+		}
+		return &customErrors.ExitStatusError{Code: xargsUsageCode}
+	case r.code != 0:
+		return &customErrors.ExitStatusError{Code: r.code}
+	}
+	return nil
+}
+
+// parseXargsArgs разбирает параметры xargs и возвращает команду с
+// начальными аргументами.
+func parseXargsArgs(args []string) (*xargsOptions, []string, error) {
+	fs := flag.NewFlagSet("xargs", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	opts := &xargsOptions{parallel: 1}
+	var null bool
+	var delimiter string
+	fs.IntVar(&opts.maxArgs, "n", 0, "не больше аргументов в одном вызове")
+	fs.IntVar(&opts.maxArgs, "max-args", 0, "не больше аргументов в одном вызове")
+	fs.StringVar(&opts.replace, "I", "", "заменяемая строка")
+	fs.BoolVar(&null, "0", false, "элементы разделены нулевым байтом")
+	fs.BoolVar(&null, "null", false, "элементы разделены нулевым байтом")
+	fs.StringVar(&delimiter, "d", "", "разделитель элементов")
+	fs.StringVar(&delimiter, "delimiter", "", "разделитель элементов")
+	fs.IntVar(&opts.parallel, "P", 1, "одновременных вызовов")
+	fs.IntVar(&opts.parallel, "max-procs", 1, "одновременных вызовов")
+	fs.BoolVar(&opts.noRunEmpty, "r", false, "не запускать команду при пустом вводе")
+	fs.BoolVar(&opts.noRunEmpty, "no-run-if-empty", false, "не запускать команду при пустом вводе")
+	fs.BoolVar(&opts.verbose, "t", false, "печатать команды")
+	fs.BoolVar(&opts.verbose, "verbose", false, "печатать команды")
+
+	if err := fs.Parse(splitShortFlags(args, "nIdP")); err != nil {
+		return nil, nil, fmt.Errorf("xargs: ошибка разбора флагов: %w", err)
+	}
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+
+	if (set["n"] || set["max-args"]) && opts.maxArgs < 1 {
+		return nil, nil, fmt.Errorf("xargs: неверное число аргументов %d", opts.maxArgs)
+	}
+	if opts.parallel < 0 {
+		return nil, nil, fmt.Errorf("xargs: неверное число процессов %d", opts.parallel)
+	}
+	if set["I"] && opts.replace == "" {
+		return nil, nil, errors.New("xargs: пустая заменяемая строка -I")
+	}
+	if set["d"] || set["delimiter"] {
+		expanded, _ := expandEscapes(delimiter, escapeFormat)
+		if len(expanded) != 1 {
+			return nil, nil, fmt.Errorf("xargs: разделитель должен быть одним символом: '%s'", delimiter)
+		}
+		opts.delimiter, opts.delimited = expanded[0], true
+	}
+	if null {
+		opts.delimiter, opts.delimited = 0, true
+	}
+
+	command := fs.Args()
+	if len(command) == 0 {
+		command = []string{"echo"}
+	}
+	return opts, command, nil
+}
+
+// xargsReader читает элементы ввода xargs.
+type xargsReader struct {
+	in   *bufio.Reader
+	opts *xargsOptions
+}
+
+// next возвращает следующий элемент или io.EOF.
+func (r *xargsReader) next() (string, error) {
+	if r.opts.delimited {
+		item, err := r.in.ReadString(r.opts.delimiter)
+		if err == io.EOF && item != "" {
+			return item, nil
+		}
+		return strings.TrimSuffix(item, string(r.opts.delimiter)), err
+	}
+
+	// С -I элемент — строка целиком: пробелы внутри неё не разделяют элементы
+	lines := r.opts.replace != ""
+	var item strings.Builder
+	started := false
+	var quote rune
+	for {
+		c, _, err := r.in.ReadRune()
+		if err != nil {
+			if err == io.EOF && quote != 0 {
+				return "", unmatchedQuote(quote)
+			}
+			if err == io.EOF && started {
+				return item.String(), nil
+			}
+			return "", err
+		}
+
+		switch {
+		case quote != 0:
+			if c == '\n' {
+				return "", unmatchedQuote(quote)
+			}
+			if c == quote {
+				quote = 0
+			} else {
+				item.WriteRune(c)
+			}
+		case c == '\'' || c == '"':
+			quote, started = c, true
+		case c == '\\':
+			escaped, _, err := r.in.ReadRune()
+			if err == nil {
+				item.WriteRune(escaped)
+			}
+			started = true
+		case c == '\n' || !lines && unicode.IsSpace(c):
+			if started {
+				return item.String(), nil
+			}
+		case lines && !started && unicode.IsSpace(c):
+			// Пробелы в начале строки с -I пропускаются
+		default:
+			item.WriteRune(c)
+			started = true
+		}
+	}
+}
+
+func unmatchedQuote(quote rune) error {
+	kind := "одинарная"
+	if quote == '"' {
+		kind = "двойная"
+	}
+	return fmt.Errorf("непарная %s кавычка; кавычки обрабатываются, если не указаны -0 или -d", kind)
+}
+
+// xargsRunner собирает аргументы в вызовы и запускает их.
+type xargsRunner struct {
+	opts    *xargsOptions
+	command []string
+	ctx     *CommandContext
+	slots   chan struct{} // -P: занятые места; nil — без ограничения
+	wg      sync.WaitGroup
+
+	mu      sync.Mutex
+	code    int  // код возврата xargs
+	stopped bool // команда завершилась так, что продолжать нельзя
+	ran     bool // команда запускалась хотя бы раз
+}
+
+// read читает ввод и запускает команду по мере заполнения вызовов.
+// Возвращается ошибка чтения ввода.
+func (r *xargsRunner) read(in *xargsReader) error {
+	var batch []string
+	size := 0
+	for !r.isStopped() {
+		select {
+		case <-r.ctx.Done():
+			return nil
+		default:
+		}
+
+		item, err := in.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if r.opts.replace != "" {
+			args := make([]string, len(r.command)-1)
+			for i, arg := range r.command[1:] {
+				args[i] = strings.ReplaceAll(arg, r.opts.replace, item)
+			}
+			r.start(args)
+			continue
+		}
+
+		if len(batch) > 0 && size+len(item) > xargsMaxChars {
+			r.start(append(append([]string{}, r.command[1:]...), batch...))
+			batch, size = nil, 0
+		}
+		batch = append(batch, item)
+		size += len(item) + 1
+		if len(batch) == r.opts.maxArgs {
+			r.start(append(append([]string{}, r.command[1:]...), batch...))
+			batch, size = nil, 0
+		}
+	}
+
+	if len(batch) > 0 || !r.ran && !r.opts.noRunEmpty && r.opts.replace == "" {
+		r.start(append(append([]string{}, r.command[1:]...), batch...))
+	}
+	return nil
+}
+
+// start запускает команду с аргументами args, с -P — в отдельной
+// горутине, когда освободится место.
+func (r *xargsRunner) start(args []string) {
+	if r.isStopped() {
+		return
+	}
+	r.ran = true
+	if r.opts.verbose {
+		line := strings.Join(append([]string{r.command[0]}, args...), " ")
+		if err := warnf(r.ctx, "%s", line); err != nil {
+			r.finish(xargsUsageCode, true)
+			return
+		}
+	}
+
+	if r.opts.parallel == 1 {
+		r.finish(r.run(args))
+		return
+	}
+	if r.slots != nil {
+		r.slots <- struct{}{}
+	}
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.finish(r.run(args))
+		if r.slots != nil {
+			<-r.slots
+		}
+	}()
+}
+
+// run выполняет один вызов и возвращает код возврата xargs для него и
+// признак того, что дальше запускать команду нельзя.
+func (r *xargsRunner) run(args []string) (int, bool) {
+	name := r.command[0]
+	status := spawn(r.ctx, name, args, strings.NewReader(""))
+	switch {
+	case status == 0:
+		return 0, false
+	case status == utilityNotFound:
+		_ = warnf(r.ctx, "xargs: %s: команда не найдена", name)
+		return status, true
+	case status == utilityCannotRun:
+		_ = warnf(r.ctx, "xargs: %s: не удалось запустить команду", name)
+		return status, true
+	case status == 255:
+		_ = warnf(r.ctx, "xargs: %s: завершилась с кодом 255; работа прекращена", name)
+		return xargsAbortedCode, true
+	case status < 0 || status > 125:
+		return xargsKilledCode, false
+	}
+	return xargsFailedCode, false
+}
+
+// finish учитывает результат вызова. Коды, прекращающие работу, важнее
+// кода 123.
+func (r *xargsRunner) finish(code int, stop bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if stop {
+		if !r.stopped {
+			r.code = code
+		}
+		r.stopped = true
+		return
+	}
+	if code != 0 && !r.stopped {
+		r.code = code
+	}
+}
+
+func (r *xargsRunner) isStopped() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.stopped
+}
+
+// syncWriter упорядочивает запись одновременных вызовов в общий поток.
+type syncWriter struct {
+	lock *sync.Mutex
+	w    io.Writer
+}
+
+func (s *syncWriter) Write(p []byte) (int, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.w.Write(p)
+}
+
+// Help возвращает справку по команде xargs.
+func (x *XargsCommand) Help() string {
+	return `NAME
+    xargs - строит и выполняет команды из аргументов со стандартного ввода
+
+SYNOPSIS
+    xargs [OPTION]... [COMMAND [INITIAL-ARGS]...]
+
+DESCRIPTION
+    Читает элементы из стандартного ввода и запускает COMMAND (по умолчанию
+    echo) с INITIAL-ARGS и прочитанными элементами. Команда может быть
+    встроенной или внешней; её стандартный ввод пуст. Элементы
+    накапливаются, пока их суммарная длина не превысит 128 КиБ или их
+    число не достигнет -n, после чего команда запускается.
+
+    По умолчанию элементы разделяются пробелами и переводами строк,
+    а одинарные и двойные кавычки и обратная косая черта их экранируют.
+    С пустым вводом команда всё равно запускается один раз, если не
+    указан -r.
+
+OPTIONS
+    -0, --null              элементы разделены нулевым байтом (find -print0);
+                            кавычки не обрабатываются
+    -d, --delimiter=DELIM   элементы разделены символом DELIM (допускаются
+                            \n, \t, \0 и другие последовательности)
+    -n, --max-args=MAX      не больше MAX элементов в одном вызове
+    -I REPLACE              запускать команду для каждой строки ввода,
+                            заменяя REPLACE в INITIAL-ARGS на строку
+    -P, --max-procs=N       выполнять до N вызовов одновременно; 0 — сколько
+                            угодно. Вывод вызовов может перемешиваться
+    -r, --no-run-if-empty   не запускать команду, если ввод пуст
+    -t, --verbose           печатать каждую команду в stderr перед запуском
+
+EXIT STATUS
+    0   — все вызовы успешны
+    1   — неверные аргументы или ошибка в вводе
+    123 — хотя бы один вызов завершился с кодом 1–125
+    124 — вызов завершился с кодом 255, работа прекращена
+    125 — вызов был прерван сигналом
+    126 — команду не удалось запустить
+    127 — команда не найдена
+
+EXAMPLES
+    find . -name '*.tmp' -print0 | xargs -0 rm -f
+        → удалить временные файлы с любыми именами
+
+    echo a b c d | xargs -n 2
+        → "a b" и "c d" отдельными вызовами echo
+
+    cat hosts.txt | xargs -P 8 -I {} ping -c 1 {}
+        → проверить узлы параллельно`
+}
+
+var _ BuiltinCommand = (*XargsCommand)(nil)
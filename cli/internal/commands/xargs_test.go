@@ -0,0 +1,129 @@
+package commands
+
+import (
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestXargsCommand(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		stdin    string
+		expected string
+	}{
+		{"по умолчанию echo", nil, "a b\n  c\n", "a b c\n"},
+		{"начальные аргументы", []string{"echo", "-n", "x"}, "a b", "x a b"},
+		{"-n", []string{"-n", "2"}, "1 2 3 4 5", "1 2\n3 4\n5\n"},
+		{"-n слитно", []string{"-n1", "echo", "item"}, "a b", "item a\nitem b\n"},
+		{"кавычки", nil, `'a b' "c  d" e\ f` + "\n", "a b c  d e f\n"},
+		{"-0", []string{"-0", "-n", "1"}, "a b\x00c\nd\x00", "a b\nc\nd\n"},
+		{"-0 без последнего разделителя", []string{"--null"}, "x\x00y", "x y\n"},
+		{"-d", []string{"-d", ",", "-n", "2"}, "a b,c,d", "a b c\nd\n"},
+		{"-d escape", []string{"-d", `\n`}, "a b\nc 'd'\n", "a b c 'd'\n"},
+		{
+			"-I", []string{"-I", "{}", "echo", "[{}]", "{}.bak"}, "one\n  two words \n\nthree",
+			"[one] one.bak\n[two words ] two words .bak\n[three] three.bak\n",
+		},
+		{"пустой ввод", nil, "", "\n"},
+		{"-r", []string{"-r", "echo", "x"}, " \n", ""},
+		{"-I с пустым вводом", []string{"-I", "%", "echo", "%"}, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, stderr, status := runUtilities(t, &XargsCommand{}, "", tt.stdin, tt.args...)
+			if status != 0 || stderr != "" {
+				t.Fatalf("ожидался код 0, получено %d: %q", status, stderr)
+			}
+			if out != tt.expected {
+				t.Errorf("ожидалось %q, получено %q", tt.expected, out)
+			}
+		})
+	}
+}
+
+func TestXargsCommand_Batches(t *testing.T) {
+	input := strings.Repeat(strings.Repeat("x", 1000)+"\n", 300)
+	out, _, status := runUtilities(t, &XargsCommand{}, "", input)
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	if status != 0 || len(lines) != 3 {
+		t.Fatalf("ожидалось три вызова по ограничению длины, получено %d (код %d)", len(lines), status)
+	}
+	total := 0
+	for _, line := range lines {
+		if len(line) > xargsMaxChars {
+			t.Errorf("вызов длиннее %d байт: %d", xargsMaxChars, len(line))
+		}
+		total += len(strings.Fields(line))
+	}
+	if total != 300 {
+		t.Errorf("ожидалось 300 аргументов, получено %d", total)
+	}
+}
+
+func TestXargsCommand_Parallel(t *testing.T) {
+	input := strings.Repeat("a\nb\nc\nd\ne\nf\n", 5)
+	out, stderr, status := runUtilities(t, &XargsCommand{}, "", input, "-P", "4", "-n", "1", "echo", "item")
+	if status != 0 || stderr != "" {
+		t.Fatalf("ожидался код 0, получено %d: %q", status, stderr)
+	}
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	sort.Strings(lines)
+	if len(lines) != 30 || lines[0] != "item a" || lines[29] != "item f" {
+		t.Errorf("ожидалось 30 строк от параллельных вызовов, получено %q", out)
+	}
+
+	out, _, status = runUtilities(t, &XargsCommand{}, "", "x y z", "-P0", "-n1")
+	if status != 0 || len(strings.Fields(out)) != 3 {
+		t.Errorf("-P 0 запускает все вызовы, получено %q (код %d)", out, status)
+	}
+}
+
+func TestXargsCommand_Status(t *testing.T) {
+	dir := t.TempDir()
+	makeTree(t, dir, map[string]string{"a.txt": "A\n", "b.txt": "B\n"})
+
+	out, stderr, status := runUtilities(t, &XargsCommand{}, dir, "a.txt missing b.txt", "-n", "1", "cat")
+	if status != xargsFailedCode || out != "A\nB\n" || !strings.Contains(stderr, "missing") {
+		t.Errorf("ожидался код %d после всех вызовов, получено %d: %q, %q", xargsFailedCode, status, out, stderr)
+	}
+
+	_, stderr, status = runUtilities(t, &XargsCommand{}, dir, "a b", "-n", "1", "no-such-tool")
+	if status != utilityNotFound || strings.Count(stderr, "xargs: no-such-tool: команда не найдена") != 1 {
+		t.Errorf("ожидался код 127 и один вызов, получено %d: %q", status, stderr)
+	}
+
+	out, stderr, status = runUtilities(t, &XargsCommand{}, dir, "a.txt b.txt", "-t", "cat")
+	if status != 0 || out != "A\nB\n" || stderr != "cat a.txt b.txt\n" {
+		t.Errorf("-t печатает команду в stderr, получено %d: %q, %q", status, out, stderr)
+	}
+
+	// Команда выполняется в рабочем каталоге сеанса
+	out, _, _ = runUtilities(t, &XargsCommand{}, dir, "", "pwd")
+	if strings.TrimSpace(out) != dir {
+		t.Errorf("ожидалось %q, получено %q", dir, out)
+	}
+}
+
+func TestXargsCommand_InvalidArgs(t *testing.T) {
+	tests := []struct {
+		args    []string
+		stdin   string
+		message string
+	}{
+		{[]string{"-n", "0"}, "", "xargs: неверное число аргументов 0"},
+		{[]string{"-P", "-2"}, "", "xargs: неверное число процессов -2"},
+		{[]string{"-d", "ab"}, "", "xargs: разделитель должен быть одним символом"},
+		{[]string{"-I", ""}, "", "xargs: пустая заменяемая строка"},
+		{[]string{"--bogus"}, "", "xargs: ошибка разбора флагов"},
+		{nil, "a 'b\nc'", "xargs: непарная одинарная кавычка"},
+		{nil, `a "b`, "xargs: непарная двойная кавычка"},
+	}
+	for _, tt := range tests {
+		_, stderr, status := runUtilities(t, &XargsCommand{}, "", tt.stdin, tt.args...)
+		if status != xargsUsageCode || !strings.Contains(stderr, tt.message) {
+			t.Errorf("%v: ожидалась ошибка %q, получено %d: %q", tt.args, tt.message, status, stderr)
+		}
+	}
+}
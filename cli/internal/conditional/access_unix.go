@@ -0,0 +1,20 @@
+//go:build unix
+
+package conditional
+
+import (
+	"os"
+	"syscall"
+)
+
+// Биты режима доступа для access(2).
+const (
+	executable uint32 = 1
+	writable   uint32 = 2
+	readable   uint32 = 4
+)
+
+// access проверяет права текущего пользователя на файл через access(2).
+func access(path string, _ os.FileInfo, mode uint32) bool {
+	return syscall.Access(path, mode) == nil
+}
@@ -0,0 +1,304 @@
+// Package conditional вычисляет условные выражения: аргументы команд
+// test и [, а также составную команду [[ ... ]].
+//
+// Поддерживаются проверки файлов (-e, -f, -d, -r, -w, -x, -s и др.),
+// сравнение строк и целых чисел, отрицание и логические связки.
+// Относительные пути отсчитываются от переданного рабочего каталога.
+package conditional
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// unaryOperators перечисляет унарные операторы test и [[ ... ]].
+var unaryOperators = map[string]bool{
+	"-e": true, "-f": true, "-d": true, "-r": true, "-w": true, "-x": true,
+	"-s": true, "-L": true, "-h": true, "-p": true, "-S": true, "-b": true,
+	"-c": true, "-z": true, "-n": true,
+}
+
+// binaryOperators перечисляет бинарные операторы test.
+var binaryOperators = map[string]bool{
+	"=": true, "==": true, "!=": true, "<": true, ">": true,
+	"-eq": true, "-ne": true, "-lt": true, "-le": true, "-gt": true, "-ge": true,
+	"-nt": true, "-ot": true, "-ef": true,
+}
+
+// IsUnaryOperator сообщает, что op — унарный оператор условного выражения.
+func IsUnaryOperator(op string) bool {
+	return unaryOperators[op]
+}
+
+// IsBinaryOperator сообщает, что op — бинарный оператор условного выражения.
+// Операторы =~, && и || допустимы только внутри [[ ... ]] и здесь не учитываются.
+func IsBinaryOperator(op string) bool {
+	return binaryOperators[op]
+}
+
+// Test вычисляет выражение команды test по правилам POSIX: выражения
+// из одного–четырёх аргументов разбираются по их количеству, более
+// длинные — рекурсивным спуском с операторами !, -a, -o и скобками.
+func Test(args []string, dir string) (bool, error) {
+	switch len(args) {
+	case 0:
+		return false, nil
+	case 1:
+		return args[0] != "", nil
+	case 2:
+		if args[0] == "!" {
+			return args[1] == "", nil
+		}
+		if IsUnaryOperator(args[0]) {
+			return unaryTest(args[0], args[1], dir), nil
+		}
+		return false, fmt.Errorf("%s: ожидается унарный оператор", args[0])
+	case 3:
+		if IsBinaryOperator(args[1]) {
+			return binaryTest(args[1], args[0], args[2], dir)
+		}
+		switch {
+		case args[1] == "-a":
+			return args[0] != "" && args[2] != "", nil
+		case args[1] == "-o":
+			return args[0] != "" || args[2] != "", nil
+		case args[0] == "!":
+			result, err := Test(args[1:], dir)
+			return !result, err
+		case args[0] == "(" && args[2] == ")":
+			return args[1] != "", nil
+		}
+		return false, fmt.Errorf("%s: ожидается бинарный оператор", args[1])
+	case 4:
+		if args[0] == "!" {
+			result, err := Test(args[1:], dir)
+			return !result, err
+		}
+		if args[0] == "(" && args[3] == ")" {
+			return Test(args[1:3], dir)
+		}
+	}
+
+	p := &testParser{args: args, dir: dir}
+	result, err := p.parseOr()
+	if err != nil {
+		return false, err
+	}
+	if p.pos < len(p.args) {
+		return false, fmt.Errorf("%s: лишний аргумент", p.args[p.pos])
+	}
+	return result, nil
+}
+
+// testParser разбирает длинные выражения test рекурсивным спуском.
+type testParser struct {
+	args []string
+	pos  int
+	dir  string
+}
+
+func (p *testParser) peek(offset int) (string, bool) {
+	if p.pos+offset >= len(p.args) {
+		return "", false
+	}
+	return p.args[p.pos+offset], true
+}
+
+func (p *testParser) parseOr() (bool, error) {
+	result, err := p.parseAnd()
+	if err != nil {
+		return false, err
+	}
+	for {
+		if arg, ok := p.peek(0); !ok || arg != "-o" {
+			return result, nil
+		}
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return false, err
+		}
+		result = result || right
+	}
+}
+
+func (p *testParser) parseAnd() (bool, error) {
+	result, err := p.parseNot()
+	if err != nil {
+		return false, err
+	}
+	for {
+		if arg, ok := p.peek(0); !ok || arg != "-a" {
+			return result, nil
+		}
+		p.pos++
+		right, err := p.parseNot()
+		if err != nil {
+			return false, err
+		}
+		result = result && right
+	}
+}
+
+func (p *testParser) parseNot() (bool, error) {
+	if arg, ok := p.peek(0); ok && arg == "!" {
+		if _, hasNext := p.peek(1); hasNext {
+			p.pos++
+			result, err := p.parseNot()
+			return !result, err
+		}
+	}
+	return p.parsePrimary()
+}
+
+func (p *testParser) parsePrimary() (bool, error) {
+	arg, ok := p.peek(0)
+	if !ok {
+		return false, fmt.Errorf("ожидается аргумент")
+	}
+
+	if next, ok := p.peek(1); ok && IsBinaryOperator(next) {
+		if right, ok := p.peek(2); ok {
+			p.pos += 3
+			return binaryTest(next, arg, right, p.dir)
+		}
+	}
+
+	if arg == "(" {
+		p.pos++
+		result, err := p.parseOr()
+		if err != nil {
+			return false, err
+		}
+		if closer, ok := p.peek(0); !ok || closer != ")" {
+			return false, fmt.Errorf("ожидается ')'")
+		}
+		p.pos++
+		return result, nil
+	}
+
+	if IsUnaryOperator(arg) {
+		if operand, ok := p.peek(1); ok {
+			p.pos += 2
+			return unaryTest(arg, operand, p.dir), nil
+		}
+	}
+
+	p.pos++
+	return arg != "", nil
+}
+
+// unaryTest вычисляет унарный оператор.
+func unaryTest(op, arg, dir string) bool {
+	switch op {
+	case "-z":
+		return arg == ""
+	case "-n":
+		return arg != ""
+	}
+
+	path := resolve(arg, dir)
+	if op == "-L" || op == "-h" {
+		info, err := os.Lstat(path)
+		return err == nil && info.Mode()&os.ModeSymlink != 0
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+
+	switch op {
+	case "-e":
+		return true
+	case "-f":
+		return info.Mode().IsRegular()
+	case "-d":
+		return info.IsDir()
+	case "-s":
+		return info.Size() > 0
+	case "-p":
+		return info.Mode()&os.ModeNamedPipe != 0
+	case "-S":
+		return info.Mode()&os.ModeSocket != 0
+	case "-b":
+		return info.Mode()&os.ModeDevice != 0 && info.Mode()&os.ModeCharDevice == 0
+	case "-c":
+		return info.Mode()&os.ModeCharDevice != 0
+	case "-r":
+		return access(path, info, readable)
+	case "-w":
+		return access(path, info, writable)
+	case "-x":
+		return access(path, info, executable)
+	}
+	return false
+}
+
+// binaryTest вычисляет бинарный оператор test.
+func binaryTest(op, left, right, dir string) (bool, error) {
+	switch op {
+	case "=", "==":
+		return left == right, nil
+	case "!=":
+		return left != right, nil
+	case "<":
+		return left < right, nil
+	case ">":
+		return left > right, nil
+	case "-nt", "-ot":
+		if op == "-ot" {
+			left, right = right, left
+		}
+		newer, errNewer := os.Stat(resolve(left, dir))
+		older, errOlder := os.Stat(resolve(right, dir))
+		return errNewer == nil && (errOlder != nil || newer.ModTime().After(older.ModTime())), nil
+	case "-ef":
+		a, errA := os.Stat(resolve(left, dir))
+		b, errB := os.Stat(resolve(right, dir))
+		return errA == nil && errB == nil && os.SameFile(a, b), nil
+	}
+
+	a, err := parseInteger(left)
+	if err != nil {
+		return false, err
+	}
+	b, err := parseInteger(right)
+	if err != nil {
+		return false, err
+	}
+
+	switch op {
+	case "-eq":
+		return a == b, nil
+	case "-ne":
+		return a != b, nil
+	case "-lt":
+		return a < b, nil
+	case "-le":
+		return a <= b, nil
+	case "-gt":
+		return a > b, nil
+	case "-ge":
+		return a >= b, nil
+	}
+	return false, fmt.Errorf("%s: неизвестный оператор", op)
+}
+
+func parseInteger(s string) (int64, error) {
+	n, err := strconv.ParseInt(strings.TrimSpace(s), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s: ожидается целое число", s)
+	}
+	return n, nil
+}
+
+func resolve(path, dir string) string {
+	if dir == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(dir, path)
+}
@@ -0,0 +1,140 @@
+package conditional
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestTest_Strings(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected bool
+	}{
+		{nil, false},
+		{[]string{""}, false},
+		{[]string{"-n"}, true},
+		{[]string{"-z", ""}, true},
+		{[]string{"-n", ""}, false},
+		{[]string{"!", ""}, true},
+		{[]string{"a", "=", "a"}, true},
+		{[]string{"a", "!=", "a"}, false},
+		{[]string{"a", "<", "b"}, true},
+		{[]string{"!", "a", "=", "b"}, true},
+		{[]string{"(", "x", ")"}, true},
+		{[]string{"a", "-a", ""}, false},
+		{[]string{"", "-o", "b"}, true},
+		{[]string{"(", "-n", "", ")"}, false},
+		{[]string{"a", "=", "b", "-o", "(", "-z", "", ")"}, true},
+		{[]string{"!", "a", "=", "a", "-a", "x"}, false},
+		{[]string{"a", "-o", "b", "-a", ""}, true},
+	}
+
+	for _, tt := range tests {
+		result, err := Test(tt.args, "")
+		if err != nil {
+			t.Fatalf("%q: неожиданная ошибка: %v", tt.args, err)
+		}
+		if result != tt.expected {
+			t.Fatalf("%q: ожидалось %v, получено %v", tt.args, tt.expected, result)
+		}
+	}
+}
+
+func TestTest_Integers(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected bool
+	}{
+		{[]string{"3", "-eq", "3"}, true},
+		{[]string{"3", "-ne", "3"}, false},
+		{[]string{"-2", "-lt", "1"}, true},
+		{[]string{"2", "-le", "2"}, true},
+		{[]string{"10", "-gt", "9"}, true},
+		{[]string{" 7", "-ge", "8"}, false},
+	}
+
+	for _, tt := range tests {
+		result, err := Test(tt.args, "")
+		if err != nil {
+			t.Fatalf("%q: неожиданная ошибка: %v", tt.args, err)
+		}
+		if result != tt.expected {
+			t.Fatalf("%q: ожидалось %v, получено %v", tt.args, tt.expected, result)
+		}
+	}
+
+	if _, err := Test([]string{"abc", "-eq", "1"}, ""); err == nil {
+		t.Fatalf("ожидалась ошибка для нечислового операнда")
+	}
+}
+
+func TestTest_Errors(t *testing.T) {
+	for _, args := range [][]string{
+		{"a", "b"},
+		{"a", "b", "c"},
+		{"(", "a", "=", "a"},
+		{"a", "=", "a", "-a"},
+	} {
+		if _, err := Test(args, ""); err == nil {
+			t.Fatalf("%q: ожидалась ошибка", args)
+		}
+	}
+}
+
+func TestTest_Files(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("file", "data")
+	write("empty", "")
+	write("old", "")
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("file", filepath.Join(dir, "link")); err != nil {
+		t.Fatal(err)
+	}
+	past := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(filepath.Join(dir, "old"), past, past); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		args     []string
+		expected bool
+	}{
+		{[]string{"-e", "file"}, true},
+		{[]string{"-e", "missing"}, false},
+		{[]string{"-f", "file"}, true},
+		{[]string{"-f", "sub"}, false},
+		{[]string{"-d", "sub"}, true},
+		{[]string{"-s", "file"}, true},
+		{[]string{"-s", "empty"}, false},
+		{[]string{"-L", "link"}, true},
+		{[]string{"-L", "file"}, false},
+		{[]string{"-r", "file"}, true},
+		{[]string{"-x", "sub"}, true},
+		{[]string{"file", "-nt", "old"}, true},
+		{[]string{"old", "-ot", "file"}, true},
+		{[]string{"file", "-nt", "missing"}, true},
+		{[]string{"missing", "-ot", "file"}, true},
+		{[]string{"link", "-ef", "file"}, true},
+		{[]string{"-f", filepath.Join(dir, "file")}, true},
+	}
+
+	for _, tt := range tests {
+		result, err := Test(tt.args, dir)
+		if err != nil {
+			t.Fatalf("%q: неожиданная ошибка: %v", tt.args, err)
+		}
+		if result != tt.expected {
+			t.Fatalf("%q: ожидалось %v, получено %v", tt.args, tt.expected, result)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package conditional
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/glob"
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/lexer"
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
+)
+
+// RematchVariable — массив, в который оператор =~ сохраняет совпадение:
+// элемент 0 — всё совпадение, элемент N — N-я группа.
+const RematchVariable = "BASH_REMATCH"
+
+// Kind определяет вид узла выражения [[ ... ]].
+type Kind int
+
+const (
+	// StringExpr — проверка строки на непустоту: [[ $x ]].
+	StringExpr Kind = iota
+	// UnaryExpr — унарный оператор: [[ -f file ]].
+	UnaryExpr
+	// BinaryExpr — бинарный оператор: [[ $a == b* ]].
+	BinaryExpr
+	// NotExpr — отрицание: [[ ! expr ]].
+	NotExpr
+	// AndExpr — конъюнкция: [[ a && b ]].
+	AndExpr
+	// OrExpr — дизъюнкция: [[ a || b ]].
+	OrExpr
+)
+
+// Expr — узел выражения составной команды [[ ... ]].
+//
+// Для StringExpr, UnaryExpr и BinaryExpr операнды хранятся в Args уже без
+// кавычек. Правый операнд операторов ==, = и != хранится как шаблон
+// (см. PatternOperand), оператора =~ — как регулярное выражение
+// (см. RegexpOperand). Для NotExpr используется X, для AndExpr и OrExpr — X и Y.
+type Expr struct {
+	Kind     Kind
+	Operator string
+	Args     []string
+	X, Y     *Expr
+}
+
+// Eval вычисляет выражение. Относительные пути отсчитываются от dir,
+// результат оператора =~ сохраняется в vars (см. RematchVariable).
+// Операторы && и || вычисляются по короткой схеме.
+func (e *Expr) Eval(dir string, vars *session.Variables) (bool, error) {
+	switch e.Kind {
+	case StringExpr:
+		return e.Args[0] != "", nil
+	case UnaryExpr:
+		return unaryTest(e.Operator, e.Args[0], dir), nil
+	case NotExpr:
+		result, err := e.X.Eval(dir, vars)
+		return !result, err
+	case AndExpr, OrExpr:
+		left, err := e.X.Eval(dir, vars)
+		if err != nil || left == (e.Kind == OrExpr) {
+			return left, err
+		}
+		return e.Y.Eval(dir, vars)
+	}
+
+	left, right := e.Args[0], e.Args[1]
+	switch e.Operator {
+	case "==", "=":
+		return matchPattern(right, left), nil
+	case "!=":
+		return !matchPattern(right, left), nil
+	case "=~":
+		return matchRegexp(right, left, vars)
+	}
+	return binaryTest(e.Operator, left, right, dir)
+}
+
+// matchPattern сопоставляет строку с шаблоном. Как и в bash, внутри [[ ... ]]
+// расширенные шаблоны доступны всегда, а * совпадает и с "/", и с точкой в начале.
+func matchPattern(pattern, s string) bool {
+	return glob.Match(pattern, s, glob.Options{DotGlob: true, ExtGlob: true})
+}
+
+func matchRegexp(pattern, s string, vars *session.Variables) (bool, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return false, fmt.Errorf("%s: неверное регулярное выражение", pattern)
+	}
+
+	match := re.FindStringSubmatch(s)
+	if vars != nil {
+		// Как и в bash, после несовпадения массив пуст
+		_ = vars.SetArray(RematchVariable, session.NewIndexedArray(match...))
+	}
+	return match != nil, nil
+}
+
+// PatternOperand строит шаблон из правого операнда == и != в исходном виде
+// (с кавычками): части в кавычках сравниваются буквально.
+func PatternOperand(raw string) string {
+	var b strings.Builder
+	for _, seg := range lexer.Segments(raw) {
+		if seg.Quoted {
+			b.WriteString(glob.Escape(seg.Text))
+		} else {
+			b.WriteString(seg.Text)
+		}
+	}
+	return b.String()
+}
+
+// RegexpOperand строит регулярное выражение из правого операнда =~
+// в исходном виде: части в кавычках сопоставляются буквально.
+func RegexpOperand(raw string) string {
+	var b strings.Builder
+	for _, seg := range lexer.Segments(raw) {
+		if seg.Quoted {
+			b.WriteString(regexp.QuoteMeta(seg.Text))
+		} else {
+			b.WriteString(seg.Text)
+		}
+	}
+	return b.String()
+}
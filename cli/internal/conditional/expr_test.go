@@ -0,0 +1,98 @@
+package conditional
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
+)
+
+func binary(left, op, right string) *Expr {
+	return &Expr{Kind: BinaryExpr, Operator: op, Args: []string{left, right}}
+}
+
+func TestExpr_Eval(t *testing.T) {
+	tests := []struct {
+		name     string
+		expr     *Expr
+		expected bool
+	}{
+		{"непустая строка", &Expr{Kind: StringExpr, Args: []string{"x"}}, true},
+		{"пустая строка", &Expr{Kind: StringExpr, Args: []string{""}}, false},
+		{"унарный оператор", &Expr{Kind: UnaryExpr, Operator: "-z", Args: []string{""}}, true},
+		{"шаблон", binary("main.go", "==", "*.go"), true},
+		{"шаблон через /", binary("a/b.go", "==", "*.go"), true},
+		{"экранированный шаблон", binary("main.go", "==", PatternOperand(`"*.go"`)), false},
+		{"расширенный шаблон", binary("abab", "==", "+(ab)"), true},
+		{"несовпадение шаблона", binary("main.go", "!=", "*.c"), true},
+		{"сравнение строк", binary("abc", "<", "abd"), true},
+		{"целые числа", binary("10", "-gt", "9"), true},
+		{"отрицание", &Expr{Kind: NotExpr, X: binary("a", "==", "b")}, true},
+		{"и", &Expr{Kind: AndExpr, X: binary("a", "==", "a"), Y: binary("b", "==", "c")}, false},
+		{"или", &Expr{Kind: OrExpr, X: binary("a", "==", "b"), Y: binary("b", "==", "b")}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := tt.expr.Eval("", nil)
+			if err != nil {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+			if result != tt.expected {
+				t.Fatalf("ожидалось %v, получено %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestExpr_ShortCircuit(t *testing.T) {
+	failing := binary("x", "-eq", "1")
+
+	for _, expr := range []*Expr{
+		{Kind: AndExpr, X: binary("a", "==", "b"), Y: failing},
+		{Kind: OrExpr, X: binary("a", "==", "a"), Y: failing},
+	} {
+		if _, err := expr.Eval("", nil); err != nil {
+			t.Fatalf("правый операнд не должен вычисляться: %v", err)
+		}
+	}
+}
+
+func TestExpr_Rematch(t *testing.T) {
+	vars := session.NewVariables(nil)
+	if err := vars.SetArray("BASH_REMATCH", session.NewIndexedArray("a", "b", "c", "stale")); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := binary("version 1.25", "=~", RegexpOperand(`([0-9]+)\.([0-9]+)`)).Eval("", vars)
+	if err != nil || !result {
+		t.Fatalf("ожидалось совпадение, получено %v (%v)", result, err)
+	}
+
+	rematch, ok := vars.Array("BASH_REMATCH")
+	if !ok {
+		t.Fatalf("BASH_REMATCH должен быть массивом")
+	}
+	expected := []string{"1.25", "1", "25"}
+	if values := rematch.Values(); !reflect.DeepEqual(values, expected) {
+		t.Fatalf("ожидалось %q, получено %q", expected, values)
+	}
+
+	result, _ = binary("none", "=~", "[0-9]").Eval("", vars)
+	if rematch, _ := vars.Array("BASH_REMATCH"); result || rematch.Len() != 0 {
+		t.Fatalf("после несовпадения BASH_REMATCH должен быть пуст: %q", rematch.Values())
+	}
+
+	if _, err := binary("x", "=~", "(").Eval("", vars); err == nil {
+		t.Fatalf("ожидалась ошибка для неверного регулярного выражения")
+	}
+}
+
+func TestOperands(t *testing.T) {
+	if got := PatternOperand(`a*"b*"\?`); got != `a*b\*\?` {
+		t.Fatalf("ожидалось %q, получено %q", `a*b\*\?`, got)
+	}
+	if got := RegexpOperand(`^a."b."$`); got != `^a.b\.$` {
+		t.Fatalf("ожидалось %q, получено %q", `^a.b\.$`, got)
+	}
+}
@@ -20,6 +20,31 @@ func (e *CommandNotFoundError) Error() string {
 	return fmt.Sprintf("go-cli: command not found: %s", e.Command)
 }
 
+// SyntaxError представляет синтаксическую ошибку во введённой строке.
+// Token содержит токен, рядом с которым обнаружена ошибка.
+type SyntaxError struct {
+	Token string
+}
+
+func (e *SyntaxError) Error() string {
+	token := e.Token
+	if token == "" {
+		token = "newline"
+	}
+	return fmt.Sprintf("go-cli: syntax error near unexpected token `%s'", token)
+}
+
+// ExitStatusError сообщает, что команда завершилась с ненулевым кодом возврата.
+// Встроенные команды возвращают её, когда код возврата важен (например,
+// для операторов && и ||), а сообщение об ошибке уже выведено или не нужно.
+type ExitStatusError struct {
+	Code int
+}
+
+func (e *ExitStatusError) Error() string {
+	return fmt.Sprintf("exit status %d", e.Code)
+}
+
 // Is проверяет, является ли ошибка указанной ошибкой.
 // Обертка над стандартной функцией errors.Is для удобства использования.
 func Is(err, target error) bool {
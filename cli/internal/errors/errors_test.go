@@ -18,3 +18,22 @@ func TestIsWrapper(t *testing.T) {
 		t.Fatalf("Is не должен возвращать true для разных ошибок")
 	}
 }
+
+func TestSyntaxError_Error(t *testing.T) {
+	err := &SyntaxError{Token: ")"}
+	if err.Error() != "go-cli: syntax error near unexpected token `)'" {
+		t.Fatalf("неверный текст ошибки: %s", err.Error())
+	}
+
+	err = &SyntaxError{}
+	if err.Error() != "go-cli: syntax error near unexpected token `newline'" {
+		t.Fatalf("неверный текст ошибки для конца строки: %s", err.Error())
+	}
+}
+
+func TestExitStatusError_Error(t *testing.T) {
+	err := &ExitStatusError{Code: 2}
+	if err.Error() != "exit status 2" {
+		t.Fatalf("неверный текст ошибки: %s", err.Error())
+	}
+}
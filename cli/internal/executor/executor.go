@@ -3,18 +3,63 @@
 package executor
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/checkutils"
 	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/commands"
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/conditional"
+	customErrors "github.com/ggerlakh/software-design-mhs-itmo/cli/internal/errors"
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/lexer"
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
 )
 
+// Коды возврата, которые executor назначает сам.
+const (
+	statusFailure  = 1   // общая ошибка, в том числе ошибка перенаправления
+	statusSyntax   = 2   // синтаксическая ошибка, в том числе в выражении [[ ... ]]
+	statusNotFound = 127 // внешнюю команду не удалось запустить
+)
+
+// defaultPS4 — префикс трассировки xtrace, если переменная PS4 не задана.
+const defaultPS4 = "+ "
+
+// Redirect описывает перенаправление ввода-вывода команды.
+// Значения Op совпадают с parser.Redirect: "<", ">", ">>", ">|" и ">&".
+type Redirect struct {
+	FD     int
+	Op     string
+	Target string
+}
+
 // ExecutableCommand описывает команду, подготовленную к выполнению.
+// Для составного присваивания массиву NAME=(...) Name содержит "NAME=" или
+// "NAME+=", а Array — элементы (пустой, но не nil срез для "NAME=()").
+// Если заполнено поле Subshell, команда выполняется в подоболочке
+// с копией переменных и рабочего каталога. Если заполнено поле Group,
+// список выполняется в текущем окружении. Если заполнено поле Cond,
+// вычисляется условное выражение [[ ... ]].
+//
+// Если заполнено поле Source, команда ещё не раскрыта: непосредственно
+// перед выполнением Executor.Expand раскрывает и разбирает исходный текст
+// простой команды или [[ ... ]]. Для подоболочки и группы Source — текст
+// их перенаправлений.
 type ExecutableCommand struct {
-	Name string
-	Args []string
+	Name      string
+	Args      []string
+	Array     []string
+	Redirects []Redirect
+	Subshell  *Script
+	Group     *Script
+	Cond      *conditional.Expr
+	Source    string
 }
 
 // Plan представляет последовательность команд, которые необходимо выполнить.
@@ -22,112 +67,716 @@ type Plan struct {
 	Commands []ExecutableCommand
 }
 
+// ScriptItem — элемент списка команд. Op задаёт оператор, связывающий
+// элемент с предыдущим: "" для первого элемента, ";", "&&" или "||".
+type ScriptItem struct {
+	Op   string
+	Plan Plan
+}
+
+// Script — список пайплайнов, связанных операторами ;, && и ||.
+type Script struct {
+	Items []ScriptItem
+}
+
 // Executor отвечает за выполнение команд согласно плану.
 type Executor struct {
 	BuiltinCommands []commands.BuiltinCommand
-	Env             map[string]string
+	// Vars — переменные сеанса. Таблица может разделяться с шагами
+	// препроцессинга.
+	Vars *session.Variables
+	// Options — опции команды shopt. Набор может разделяться с шагами
+	// препроцессинга. В подоболочке это копия.
+	Options *session.Options
+	// SetOptions — опции команды set: errexit, pipefail, xtrace,
+	// noclobber и другие. В подоболочке это копия.
+	SetOptions *session.Options
+	// Traps — таблица ловушек команды trap. В подоболочке она своя и
+	// изначально пуста: ловушки, как и в bash, не наследуются. Ловушка
+	// EXIT подоболочки выполняется при её завершении.
+	Traps *session.Traps
+	// Aliases — псевдонимы команды alias. Таблица может разделяться
+	// с шагами препроцессинга. В подоболочке это копия.
+	Aliases *session.Aliases
+	// Compile превращает команду ловушки в список команд: разбирает её
+	// так же, как строку ввода. Задаётся интерпретатором; если он не
+	// задан, ловушки не выполняются.
+	Compile func(command string) (Script, error)
+	// Expand раскрывает и разбирает исходный текст команды
+	// (ExecutableCommand.Source) в состоянии ctx: с переменными, опциями,
+	// псевдонимами и каталогом оболочки, которая её выполняет. Задаётся
+	// интерпретатором.
+	Expand func(source string, ctx *commands.CommandContext) (Script, error)
+	// Dir — рабочий каталог сеанса. Встроенные команды могут изменить его
+	// через CommandContext.Dir (например, cd).
+	Dir string
+	// Status — код возврата последнего выполненного пайплайна.
+	Status int
+
+	// ctx отменяет выполнение встроенных команд, например по Ctrl-C.
+	ctx context.Context
+	// errexitIgnored истинно, пока выполняется условие: команда перед &&
+	// или ||. Ошибки в нём, как и в bash, не завершают оболочку при errexit.
+	errexitIgnored bool
+	// inTrap истинно, пока выполняется обработчик ловушки: другие ловушки
+	// в это время не срабатывают, а сигналы ждут его завершения.
+	inTrap bool
 }
 
-// NewExecutor создает новый Executor.
+// NewExecutor создает новый Executor. Переменные env экспортируются:
+// они передаются внешним программам.
 func NewExecutor(env map[string]string, builtins []commands.BuiltinCommand) *Executor {
+	currentDir, err := os.Getwd()
+	if err != nil {
+		currentDir = "."
+	}
+
 	return &Executor{
-		Env:             env,
+		Vars:            session.NewVariables(env),
+		Options:         session.NewOptions(),
+		SetOptions:      session.NewSetOptions(),
+		Traps:           session.NewTraps(),
+		Aliases:         session.NewAliases(),
 		BuiltinCommands: builtins,
+		Dir:             currentDir,
 	}
 }
 
-// Execute запускает команды в соответствии с планом.
-func (e *Executor) Execute(plan Plan) {
-	if len(plan.Commands) == 0 {
-		return
+// streams — стандартные потоки, в которых выполняется команда или список.
+type streams struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func standardStreams() streams {
+	return streams{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr}
+}
+
+// Execute запускает команды в соответствии с планом
+// и возвращает код возврата последней команды пайплайна.
+func (e *Executor) Execute(plan Plan) int {
+	status, _ := e.executePlan(plan, standardStreams())
+	return status
+}
+
+// ExecuteScript выполняет список команд и возвращает код возврата
+// последнего выполненного пайплайна. Если в текущей оболочке была
+// выполнена команда exit или при включённой опции errexit команда
+// завершилась с ошибкой, возвращается ошибка errors.ErrExit.
+func (e *Executor) ExecuteScript(script Script) (int, error) {
+	return e.ExecuteScriptContext(context.Background(), script)
+}
+
+// ExecuteScriptContext выполняет список команд, как ExecuteScript.
+// При отмене ctx встроенные команды, ожидающие данных (например,
+// tail -f), прерываются.
+func (e *Executor) ExecuteScriptContext(ctx context.Context, script Script) (int, error) {
+	previous := e.ctx
+	e.ctx = ctx
+	defer func() { e.ctx = previous }()
+
+	return e.executeScript(script, standardStreams())
+}
+
+// context возвращает контекст отмены текущего выполнения.
+func (e *Executor) context() context.Context {
+	if e.ctx == nil {
+		return context.Background()
 	}
+	return e.ctx
+}
 
-	if len(plan.Commands) == 1 {
-		ctx := e.newContext()
-		ctx.Stdin = os.Stdin
-		ctx.Stdout = os.Stdout
-		ctx.Stderr = os.Stderr
-		e.runCommand(plan.Commands[0], ctx)
+func (e *Executor) executeScript(script Script, std streams) (int, error) {
+	status := 0
+	for idx, item := range script.Items {
+		switch item.Op {
+		case "&&":
+			if status != 0 {
+				continue
+			}
+		case "||":
+			if status == 0 {
+				continue
+			}
+		}
+
+		if err := e.runPendingTraps(std); err != nil {
+			return status, err
+		}
+		if err := e.runTrap(session.TrapDebug, std); err != nil {
+			return status, err
+		}
+
+		// Пайплайн перед && или || — условие: его ошибка не завершает
+		// оболочку, как и ошибки команд внутри него
+		ignored := e.errexitIgnored
+		condition := idx+1 < len(script.Items) && script.Items[idx+1].Op != ";"
+		e.errexitIgnored = ignored || condition
+
+		var err error
+		status, err = e.executePlan(item.Plan, std)
+		e.errexitIgnored = ignored
+		if err != nil {
+			return status, err
+		}
+		if status == 0 || ignored || condition {
+			continue
+		}
+		if err := e.runTrap(session.TrapErr, std); err != nil {
+			return status, err
+		}
+		if e.SetOptions.Enabled(session.ErrExit) {
+			return status, customErrors.ErrExit
+		}
+	}
+	return status, nil
+}
+
+// RunExitTrap выполняет ловушку EXIT. Интерпретатор вызывает его при
+// выходе из оболочки. Ловушка снимается перед выполнением, поэтому
+// выполняется не больше одного раза, даже если обработчик вызывает exit.
+func (e *Executor) RunExitTrap() {
+	e.runExitTrap(standardStreams())
+}
+
+// runExitTrap выполняет ловушку EXIT в потоках std.
+func (e *Executor) runExitTrap(std streams) {
+	command, ok := e.Traps.Get(session.TrapExit)
+	if !ok {
 		return
 	}
+	e.Traps.Reset(session.TrapExit)
+	_ = e.runHandler(command, std)
+}
+
+// runPendingTraps выполняет ловушки полученных сигналов. Пока выполняется
+// другой обработчик, сигналы остаются в очереди.
+func (e *Executor) runPendingTraps(std streams) error {
+	if e.inTrap {
+		return nil
+	}
+	for _, name := range e.Traps.TakePending() {
+		if err := e.runTrap(name, std); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	var pipes []*os.File
+// runTrap выполняет ловушку name, если она установлена. Ошибка
+// errors.ErrExit означает, что обработчик завершил оболочку.
+func (e *Executor) runTrap(name string, std streams) error {
+	command, ok := e.Traps.Get(name)
+	if !ok || e.inTrap {
+		return nil
+	}
+	return e.runHandler(command, std)
+}
+
+// runHandler выполняет команду ловушки в текущей оболочке. Код возврата
+// последнего пайплайна после обработчика не меняется.
+func (e *Executor) runHandler(command string, std streams) error {
+	if command == "" || e.Compile == nil {
+		return nil
+	}
+
+	script, err := e.Compile(command)
+	switch {
+	case customErrors.Is(err, customErrors.ErrExit):
+		return err
+	case err != nil:
+		if _, writeErr := fmt.Fprintf(std.stderr, "go-cli: trap: %v\n", err); writeErr != nil {
+			_ = writeErr
+		}
+		return nil
+	}
 
+	status := e.Status
+	e.inTrap = true
+	_, err = e.executeScript(script, std)
+	e.inTrap = false
+	e.Status = status
+	return err
+}
+
+// executePlan выполняет пайплайн. Одиночная команда выполняется в текущей
+// оболочке, а команды пайплайна из нескольких команд — одновременно,
+// каждая в своей подоболочке, поэтому их изменения переменных и каталога
+// не сохраняются. Код возврата пайплайна — код последней команды,
+// а при опции pipefail — код последней команды, завершившейся с ошибкой.
+func (e *Executor) executePlan(plan Plan, std streams) (int, error) {
+	if len(plan.Commands) == 0 {
+		return 0, nil
+	}
+
+	if len(plan.Commands) == 1 {
+		ctx := e.newContext(std)
+		status, err := e.runCommand(plan.Commands[0], ctx)
+		e.Status = status
+		return status, err
+	}
+
+	count := len(plan.Commands)
+	stages := make([]*Executor, count)
+	cancels := make([]context.CancelFunc, count)
+	contexts := make([]*commands.CommandContext, count)
+	for i := range stages {
+		stages[i] = e.fork()
+		stages[i].ctx, cancels[i] = context.WithCancel(e.context())
+		contexts[i] = stages[i].newContext(std)
+	}
 	defer func() {
-		for _, pipe := range pipes {
-			_ = pipe.Close()
+		for _, cancel := range cancels {
+			cancel()
 		}
 	}()
 
-	contexts := make([]*commands.CommandContext, len(plan.Commands))
-	for i := range contexts {
-		contexts[i] = e.newContext()
-	}
+	// readers[i] — stdin команды i, writers[i] — её stdout
+	readers := make([]*os.File, count)
+	writers := make([]*os.File, count)
+	defer func() {
+		for i := range readers {
+			closePipe(readers[i])
+			closePipe(writers[i])
+		}
+	}()
 
-	for i := 0; i < len(plan.Commands)-1; i++ {
+	for i := 0; i < count-1; i++ {
 		reader, writer, err := os.Pipe()
 		if err != nil {
-			return
+			return statusFailure, nil
 		}
-		pipes = append(pipes, reader, writer)
-
+		writers[i], readers[i+1] = writer, reader
 		contexts[i].Stdout = writer
 		contexts[i+1].Stdin = reader
 	}
 
-	contexts[0].Stdin = os.Stdin
-	contexts[len(contexts)-1].Stdout = os.Stdout
-
+	statuses := make([]int, count)
+	var wg sync.WaitGroup
 	for i, cmd := range plan.Commands {
-		if contexts[i].Stdout == nil {
-			contexts[i].Stdout = os.Stdout
-		}
-		if contexts[i].Stderr == nil {
-			contexts[i].Stderr = os.Stderr
-		}
-		e.runCommand(cmd, contexts[i])
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			statuses[i], _ = stages[i].runCommand(cmd, contexts[i])
+
+			// Следующая команда получит EOF
+			closePipe(writers[i])
 
-		// Закрываем writer текущей команды.
-		if writer, ok := contexts[i].Stdout.(*os.File); ok {
-			_ = writer.Close()
+			// Предыдущая команда больше не может передать данные: её запись
+			// в пайп завершится ошибкой, а встроенные команды, ожидающие
+			// новых данных (tail -f), отменяются.
+			if i > 0 {
+				closePipe(readers[i])
+				cancels[i-1]()
+			}
+		}()
+	}
+	wg.Wait()
+
+	e.Status = statuses[count-1]
+	if e.SetOptions.Enabled(session.PipeFail) {
+		for _, status := range statuses {
+			if status != 0 {
+				e.Status = status
+			}
 		}
 	}
+	return e.Status, nil
 }
 
-func (e *Executor) newContext() *commands.CommandContext {
-	currentDir, err := os.Getwd()
-	if err != nil {
-		currentDir = "."
+// closePipe закрывает конец пайпа, если он есть. Повторное закрытие безопасно.
+func closePipe(pipe *os.File) {
+	if pipe != nil {
+		_ = pipe.Close()
+	}
+}
+
+// fork создаёт подоболочку: копию executor с собственными
+// переменными и рабочим каталогом.
+func (e *Executor) fork() *Executor {
+	return &Executor{
+		BuiltinCommands: e.BuiltinCommands,
+		Vars:            e.Vars.Copy(),
+		Options:         e.Options.Copy(),
+		SetOptions:      e.SetOptions.Copy(),
+		Traps:           session.NewTraps(),
+		Aliases:         e.Aliases.Copy(),
+		Compile:         e.Compile,
+		Expand:          e.Expand,
+		Dir:             e.Dir,
+		Status:          e.Status,
+		ctx:             e.ctx,
+		errexitIgnored:  e.errexitIgnored,
+		inTrap:          e.inTrap,
 	}
+}
 
+func (e *Executor) newContext(std streams) *commands.CommandContext {
+	if e.Vars == nil {
+		e.Vars = session.NewVariables(nil)
+	}
+	if e.Options == nil {
+		e.Options = session.NewOptions()
+	}
+	if e.SetOptions == nil {
+		e.SetOptions = session.NewSetOptions()
+	}
+	if e.Traps == nil {
+		e.Traps = session.NewTraps()
+	}
+	if e.Aliases == nil {
+		e.Aliases = session.NewAliases()
+	}
 	return &commands.CommandContext{
-		Env: e.Env,
-		Dir: currentDir,
+		Stdin:      std.stdin,
+		Stdout:     std.stdout,
+		Stderr:     std.stderr,
+		Vars:       e.Vars,
+		Options:    e.Options,
+		SetOptions: e.SetOptions,
+		Traps:      e.Traps,
+		Aliases:    e.Aliases,
+		Dir:        e.Dir,
+		Context:    e.context(),
+		Run:        e.runUtility,
 	}
 }
 
-func (e *Executor) runCommand(cmd ExecutableCommand, ctx *commands.CommandContext) {
+// runCommand выполняет одну команду и возвращает её код возврата.
+// Ошибка возвращается только для команды exit, выполненной в текущей оболочке.
+func (e *Executor) runCommand(cmd ExecutableCommand, ctx *commands.CommandContext) (int, error) {
+	if cmd.Source != "" {
+		expanded, status, err := e.expand(cmd, ctx)
+		if status != 0 || err != nil {
+			return status, err
+		}
+		cmd = expanded
+	}
+
+	if e.SetOptions.Enabled(session.XTrace) {
+		e.trace(cmd, ctx)
+	}
+
+	closeFiles, err := applyRedirects(cmd.Redirects, ctx)
+	defer closeFiles()
+	if err != nil {
+		if _, writeErr := fmt.Fprintf(ctx.Stderr, "go-cli: %v\n", err); writeErr != nil {
+			_ = writeErr
+		}
+		return statusFailure, nil
+	}
+
+	cmdStreams := streams{stdin: ctx.Stdin, stdout: ctx.Stdout, stderr: ctx.Stderr}
+
 	switch {
+	case cmd.Subshell != nil:
+		sub := e.fork()
+		status, _ := sub.executeScript(*cmd.Subshell, cmdStreams)
+		sub.runExitTrap(cmdStreams)
+		return status, nil
+	case cmd.Group != nil:
+		return e.executeScript(*cmd.Group, cmdStreams)
+	case cmd.Cond != nil:
+		return e.evalConditional(cmd.Cond, ctx), nil
+	case cmd.Name == "":
+		return 0, nil
 	case checkutils.IsEnvAssignmentCommand(cmd.Name):
-		parts := strings.SplitN(cmd.Name, "=", 2)
-		e.Env[parts[0]] = parts[1]
-	case checkutils.IsBuiltInCommand(cmd.Name, e.BuiltinCommands):
+		if err := commands.Assign(ctx, cmd.Name, cmd.Array); err != nil {
+			if _, writeErr := fmt.Fprintf(ctx.Stderr, "go-cli: %v\n", err); writeErr != nil {
+				_ = writeErr
+			}
+			return statusFailure, nil
+		}
+		return 0, nil
+	default:
+		return e.runSimple(cmd.Name, cmd.Args, ctx)
+	}
+}
+
+// expand раскрывает команду из исходного текста cmd.Source. Если
+// раскрытие не удалось, возвращается код возврата команды, а сообщение
+// выводится в stderr. Ошибка возвращается только для команды exit.
+func (e *Executor) expand(cmd ExecutableCommand, ctx *commands.CommandContext) (ExecutableCommand, int, error) {
+	if e.Expand == nil {
+		return cmd, expansionFailure(errors.New("раскрытие команд не настроено"), ctx), nil
+	}
+
+	script, err := e.Expand(cmd.Source, ctx)
+	switch {
+	case customErrors.Is(err, customErrors.ErrExit):
+		return cmd, 0, err
+	case err != nil:
+		return cmd, expansionFailure(err, ctx), nil
+	}
+
+	single := len(script.Items) == 1 && len(script.Items[0].Plan.Commands) == 1
+	if cmd.Subshell != nil || cmd.Group != nil {
+		// Перенаправления составной команды записаны как команда без имени
+		if !single || script.Items[0].Plan.Commands[0].Name != "" {
+			return cmd, expansionFailure(fmt.Errorf("%s: неоднозначное перенаправление", cmd.Source), ctx), nil
+		}
+		cmd.Redirects = script.Items[0].Plan.Commands[0].Redirects
+		cmd.Source = ""
+		return cmd, 0, nil
+	}
+
+	if single {
+		return script.Items[0].Plan.Commands[0], 0, nil
+	}
+	// Псевдоним раскрылся в список или пайплайн: он выполняется в
+	// текущей оболочке, как группа
+	return ExecutableCommand{Group: &script}, 0, nil
+}
+
+// expansionFailure выводит ошибку раскрытия и возвращает код возврата:
+// 127 для неизвестной команды, 2 для синтаксической ошибки, иначе 1.
+func expansionFailure(err error, ctx *commands.CommandContext) int {
+	var (
+		notFound  *customErrors.CommandNotFoundError
+		syntaxErr *customErrors.SyntaxError
+		message   = "go-cli: " + err.Error()
+		status    = statusFailure
+	)
+	switch {
+	case errors.As(err, &notFound):
+		message, status = err.Error(), statusNotFound
+	case errors.As(err, &syntaxErr):
+		message, status = err.Error(), statusSyntax
+	}
+	if _, writeErr := fmt.Fprintln(ctx.Stderr, message); writeErr != nil {
+		_ = writeErr
+	}
+	return status
+}
+
+// runSimple выполняет встроенную команду name или, если такой нет,
+// внешнюю программу. Ошибка возвращается только для команды exit.
+func (e *Executor) runSimple(name string, args []string, ctx *commands.CommandContext) (int, error) {
+	if checkutils.IsBuiltInCommand(name, e.BuiltinCommands) {
+		var err error
 		for _, builtin := range e.BuiltinCommands {
-			if builtin.Name() == cmd.Name {
-				_ = builtin.Exec(cmd.Args, ctx)
+			if builtin.Name() == name {
+				err = builtin.Exec(args, ctx)
+				break
 			}
 		}
+		e.Dir = ctx.Dir
+
+		if customErrors.Is(err, customErrors.ErrExit) {
+			return 0, err
+		}
+		return statusOf(err), nil
+	}
+
+	external := exec.Command(name, args...) //nolint:gosec
+	external.Stdin = ctx.Stdin
+	external.Stdout = ctx.Stdout
+	external.Stderr = ctx.Stderr
+	external.Dir = ctx.Dir
+	external.Env = ctx.Vars.Environ()
+
+	return externalStatus(external.Run()), nil
+}
+
+// trace выводит в stderr простую команду или присваивание после раскрытия,
+// как это делает bash с опцией xtrace: с префиксом $PS4 и со словами,
+// экранированными для повторного ввода. Подоболочки, группы и [[ ... ]]
+// не выводятся — выводятся команды внутри них.
+func (e *Executor) trace(cmd ExecutableCommand, ctx *commands.CommandContext) {
+	if cmd.Name == "" || cmd.Subshell != nil || cmd.Group != nil || cmd.Cond != nil {
+		return
+	}
+
+	var words []string
+	switch {
+	case cmd.Array != nil:
+		elements := make([]string, len(cmd.Array))
+		for i, element := range cmd.Array {
+			elements[i] = lexer.Quote(element)
+		}
+		words = []string{cmd.Name + "(" + strings.Join(elements, " ") + ")"}
+	case checkutils.IsEnvAssignmentCommand(cmd.Name):
+		name, value, _ := strings.Cut(cmd.Name, "=")
+		words = []string{name + "=" + lexer.Quote(value)}
 	default:
-		external := exec.Command(cmd.Name, cmd.Args...) //nolint:gosec
-		external.Stdin = ctx.Stdin
-		external.Stdout = ctx.Stdout
-		external.Stderr = ctx.Stderr
-		external.Dir = ctx.Dir
+		words = append(words, lexer.Quote(cmd.Name))
+		for _, arg := range cmd.Args {
+			words = append(words, lexer.Quote(arg))
+		}
+	}
 
-		for key, value := range ctx.Env {
-			external.Env = append(external.Env, key+"="+value)
+	prefix, ok := ctx.Vars.Get("PS4")
+	if !ok {
+		prefix = defaultPS4
+	}
+	if _, err := fmt.Fprintf(ctx.Stderr, "%s%s\n", prefix, strings.Join(words, " ")); err != nil {
+		_ = err
+	}
+}
+
+// runUtility запускает команду по просьбе другой команды (xargs,
+// find -exec). Она выполняется в подоболочке: изменения каталога и
+// переменных не сохраняются, а exit не завершает оболочку. Вызовы
+// могут выполняться одновременно (xargs -P).
+func (e *Executor) runUtility(name string, args []string, ctx *commands.CommandContext) int {
+	sub := e.fork()
+	sub.Dir = ctx.Dir
+	sub.Vars = ctx.Vars.Copy()
+
+	child := *ctx
+	child.Vars = sub.Vars
+	status, _ := sub.runSimple(name, args, &child)
+	return status
+}
+
+// evalConditional вычисляет выражение [[ ... ]]: 0 — истина, 1 — ложь,
+// 2 — ошибка в выражении.
+func (e *Executor) evalConditional(expr *conditional.Expr, ctx *commands.CommandContext) int {
+	result, err := expr.Eval(ctx.Dir, ctx.Vars)
+	if err != nil {
+		if _, writeErr := fmt.Fprintf(ctx.Stderr, "go-cli: [[: %v\n", err); writeErr != nil {
+			_ = writeErr
+		}
+		return statusSyntax
+	}
+	if result {
+		return 0
+	}
+	return statusFailure
+}
+
+// statusOf переводит ошибку встроенной команды в код возврата.
+func statusOf(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var exitErr *customErrors.ExitStatusError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	return statusFailure
+}
+
+// externalStatus переводит ошибку запуска внешней команды в код возврата.
+func externalStatus(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return statusNotFound
+}
+
+// applyRedirects применяет перенаправления к контексту команды.
+// Возвращает функцию, закрывающую открытые файлы; её нужно вызвать
+// после выполнения команды, даже если произошла ошибка.
+func applyRedirects(redirects []Redirect, ctx *commands.CommandContext) (func(), error) {
+	var files []*os.File
+	closeFiles := func() {
+		for _, f := range files {
+			_ = f.Close()
+		}
+	}
+
+	for _, r := range redirects {
+		var (
+			file *os.File
+			err  error
+		)
+
+		switch r.Op {
+		case "<":
+			file, err = os.Open(ctx.ResolvePath(r.Target))
+		case ">":
+			file, err = createFile(ctx, r.Target, ctx.SetOptions.Enabled(session.NoClobber))
+		case ">|":
+			file, err = createFile(ctx, r.Target, false)
+		case ">>":
+			file, err = os.OpenFile(ctx.ResolvePath(r.Target), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o644)
+		case ">&":
+			source, convErr := strconv.Atoi(r.Target)
+			if convErr != nil {
+				return closeFiles, fmt.Errorf("%s: неверный дескриптор", r.Target)
+			}
+			if err := dupStream(ctx, r.FD, source); err != nil {
+				return closeFiles, err
+			}
+			continue
+		default:
+			return closeFiles, fmt.Errorf("неизвестное перенаправление %q", r.Op)
+		}
+
+		if err != nil {
+			return closeFiles, err
+		}
+		files = append(files, file)
+
+		if err := setStream(ctx, r.FD, file); err != nil {
+			return closeFiles, err
 		}
+	}
+
+	return closeFiles, nil
+}
+
+// createFile открывает файл target для перенаправления ">" или ">|".
+// При noclobber существующий обычный файл не перезаписывается; устройства
+// вроде /dev/null открываются как обычно.
+func createFile(ctx *commands.CommandContext, target string, noclobber bool) (*os.File, error) {
+	path := ctx.ResolvePath(target)
+	if !noclobber {
+		return os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	}
 
-		_ = external.Run()
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if !errors.Is(err, os.ErrExist) {
+		return file, err
+	}
+	if info, statErr := os.Stat(path); statErr == nil && info.Mode().IsRegular() {
+		return nil, fmt.Errorf("%s: невозможно перезаписать существующий файл", target)
+	}
+	return os.OpenFile(path, os.O_WRONLY, 0)
+}
+
+// setStream назначает файл дескриптору fd контекста.
+func setStream(ctx *commands.CommandContext, fd int, file *os.File) error {
+	switch fd {
+	case 0:
+		ctx.Stdin = file
+	case 1:
+		ctx.Stdout = file
+	case 2:
+		ctx.Stderr = file
+	default:
+		return fmt.Errorf("%d: неподдерживаемый дескриптор", fd)
+	}
+	return nil
+}
+
+// dupStream делает дескриптор fd копией дескриптора source (fd>&source).
+func dupStream(ctx *commands.CommandContext, fd, source int) error {
+	var target io.Writer
+	switch source {
+	case 1:
+		target = ctx.Stdout
+	case 2:
+		target = ctx.Stderr
+	default:
+		return fmt.Errorf("%d: неподдерживаемый дескриптор", source)
+	}
+
+	switch fd {
+	case 1:
+		ctx.Stdout = target
+	case 2:
+		ctx.Stderr = target
+	default:
+		return fmt.Errorf("%d: неподдерживаемый дескриптор", fd)
 	}
+	return nil
 }
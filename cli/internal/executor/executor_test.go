@@ -2,12 +2,21 @@ package executor
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"io"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/commands"
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/conditional"
+	customErrors "github.com/ggerlakh/software-design-mhs-itmo/cli/internal/errors"
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
 )
 
 type mockBuiltin struct {
@@ -64,8 +73,7 @@ func TestExecutor_ExecuteBuiltin(t *testing.T) {
 }
 
 func TestExecutor_EnvAssignment(t *testing.T) {
-	env := map[string]string{}
-	ex := NewExecutor(env, nil)
+	ex := NewExecutor(map[string]string{}, nil)
 
 	ex.Execute(Plan{
 		Commands: []ExecutableCommand{
@@ -73,11 +81,30 @@ func TestExecutor_EnvAssignment(t *testing.T) {
 		},
 	})
 
-	if env["FOO"] != "bar" {
+	if foo, _ := ex.Vars.Get("FOO"); foo != "bar" {
 		t.Fatalf("переменная окружения не установлена")
 	}
 }
 
+func TestExecutor_ExportsOnlyExportedVariables(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("нет sh")
+	}
+	ex := NewExecutor(map[string]string{"FROM_ENV": "env"}, []commands.BuiltinCommand{&commands.DeclareCommand{}})
+	ex.Execute(Plan{Commands: []ExecutableCommand{{Name: "LOCAL=local"}}})
+	ex.Execute(Plan{Commands: []ExecutableCommand{{Name: "declare", Args: []string{"-x", "EXPORTED=exported"}}}})
+
+	output := captureStdout(t, func() {
+		ex.Execute(Plan{Commands: []ExecutableCommand{{
+			Name: "sh",
+			Args: []string{"-c", `echo "$FROM_ENV|$LOCAL|$EXPORTED"`},
+		}}})
+	})
+	if output != "env||exported\n" {
+		t.Fatalf("внешней программе должны передаваться только экспортируемые переменные, получено %q", output)
+	}
+}
+
 func TestExecutor_ExecutePipelineBuiltinFlow(t *testing.T) {
 	producer := &funcBuiltin{
 		name: "produce",
@@ -124,6 +151,34 @@ func TestExecutor_ExecutePipelineBuiltinFlow(t *testing.T) {
 	}
 }
 
+func TestExecutor_ArrayAssignment(t *testing.T) {
+	ex := NewExecutor(map[string]string{"S": "scalar"}, nil)
+
+	status := ex.Execute(Plan{Commands: []ExecutableCommand{{Name: "A=", Array: []string{"x", "y"}}}})
+	status += ex.Execute(Plan{Commands: []ExecutableCommand{{Name: "A+=", Array: []string{}}}})
+	status += ex.Execute(Plan{Commands: []ExecutableCommand{{Name: "A[3]=z"}}})
+	if status != 0 {
+		t.Fatalf("ожидался код 0, получено %d", status)
+	}
+	if a, ok := ex.Vars.Array("A"); !ok || !reflect.DeepEqual(a.Keys(), []string{"0", "1", "3"}) {
+		t.Fatalf("неверные индексы массива: %v", a)
+	}
+
+	if status := ex.Execute(Plan{Commands: []ExecutableCommand{{Name: "A[x y]=1"}}}); status != 1 {
+		t.Fatalf("неверный индекс должен давать код 1, получено %d", status)
+	}
+
+	// Внешним программам передаются только обычные переменные
+	output := captureStdout(t, func() {
+		ex.Execute(Plan{Commands: []ExecutableCommand{
+			{Name: "sh", Args: []string{"-c", `echo "${A-unset} $S"`}},
+		}})
+	})
+	if output != "unset scalar\n" {
+		t.Fatalf("ожидалось %q, получено %q", "unset scalar\n", output)
+	}
+}
+
 func TestExecutor_ExecuteExternalCommand(t *testing.T) {
 	ex := NewExecutor(map[string]string{}, nil)
 
@@ -148,3 +203,762 @@ func TestExecutor_ExecuteExternalCommand(t *testing.T) {
 		t.Fatalf("внешняя команда не записала ожидаемый вывод: %q", string(output))
 	}
 }
+
+// captureStdout выполняет fn, подменив os.Stdout, и возвращает записанный вывод.
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+
+	oldStdout := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout = w
+	defer func() {
+		os.Stdout = oldStdout
+		_ = r.Close()
+	}()
+
+	fn()
+
+	_ = w.Close()
+	output, _ := io.ReadAll(r)
+	return string(output)
+}
+
+func statusBuiltin(name string, err error) *funcBuiltin {
+	return &funcBuiltin{
+		name: name,
+		run: func(args []string, ctx *commands.CommandContext) error {
+			_, writeErr := ctx.Stdout.Write([]byte(name + "\n"))
+			if writeErr != nil {
+				return writeErr
+			}
+			return err
+		},
+	}
+}
+
+func item(op string, cmds ...ExecutableCommand) ScriptItem {
+	return ScriptItem{Op: op, Plan: Plan{Commands: cmds}}
+}
+
+func TestExecutor_ExecuteScript_Operators(t *testing.T) {
+	builtins := []commands.BuiltinCommand{
+		statusBuiltin("ok", nil),
+		statusBuiltin("fail", errors.New("fail")),
+		statusBuiltin("skipped", nil),
+	}
+	ex := NewExecutor(map[string]string{}, builtins)
+
+	var status int
+	output := captureStdout(t, func() {
+		status, _ = ex.ExecuteScript(Script{Items: []ScriptItem{
+			item("", ExecutableCommand{Name: "fail"}),
+			item("&&", ExecutableCommand{Name: "skipped"}),
+			item("||", ExecutableCommand{Name: "ok"}),
+			item("||", ExecutableCommand{Name: "skipped"}),
+			item(";", ExecutableCommand{Name: "fail"}),
+		}})
+	})
+
+	if output != "fail\nok\nfail\n" {
+		t.Fatalf("ожидалось %q, получено %q", "fail\nok\nfail\n", output)
+	}
+	if status != 1 || ex.Status != 1 {
+		t.Fatalf("ожидался код возврата 1, получено %d (Status=%d)", status, ex.Status)
+	}
+}
+
+func TestExecutor_ExitStatusError(t *testing.T) {
+	builtins := []commands.BuiltinCommand{statusBuiltin("three", &customErrors.ExitStatusError{Code: 3})}
+	ex := NewExecutor(map[string]string{}, builtins)
+
+	var status int
+	captureStdout(t, func() {
+		status = ex.Execute(Plan{Commands: []ExecutableCommand{{Name: "three"}}})
+	})
+
+	if status != 3 {
+		t.Fatalf("ожидался код возврата 3, получено %d", status)
+	}
+}
+
+func TestExecutor_SubshellIsolatesState(t *testing.T) {
+	dir := t.TempDir()
+	ex := NewExecutor(map[string]string{"X": "outer"}, []commands.BuiltinCommand{&commands.CdCommand{}})
+
+	subshell := &Script{Items: []ScriptItem{
+		item("", ExecutableCommand{Name: "X=inner"}),
+		item(";", ExecutableCommand{Name: "cd", Args: []string{dir}}),
+	}}
+	group := &Script{Items: []ScriptItem{
+		item("", ExecutableCommand{Name: "Y=group"}),
+	}}
+
+	start := ex.Dir
+	if _, err := ex.ExecuteScript(Script{Items: []ScriptItem{
+		item("", ExecutableCommand{Subshell: subshell}),
+		item(";", ExecutableCommand{Group: group}),
+	}}); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+
+	if x, _ := ex.Vars.Get("X"); x != "outer" || ex.Dir != start {
+		t.Fatalf("подоболочка не должна менять состояние: X=%q, Dir=%q", x, ex.Dir)
+	}
+	if y, _ := ex.Vars.Get("Y"); y != "group" {
+		t.Fatalf("группа должна выполняться в текущей оболочке: Y=%q", y)
+	}
+
+	if _, err := ex.ExecuteScript(Script{Items: []ScriptItem{
+		item("", ExecutableCommand{Name: "cd", Args: []string{dir}}),
+	}}); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if ex.Dir != dir {
+		t.Fatalf("cd в текущей оболочке должен менять каталог: ожидалось %q, получено %q", dir, ex.Dir)
+	}
+}
+
+func TestExecutor_SubshellIsolatesArrays(t *testing.T) {
+	setArray := &funcBuiltin{name: "setarray", run: func(args []string, ctx *commands.CommandContext) error {
+		return ctx.Vars.SetArray(args[0], session.NewIndexedArray(args[1:]...))
+	}}
+	ex := NewExecutor(map[string]string{}, []commands.BuiltinCommand{setArray})
+	if err := ex.Vars.SetArray("A", session.NewIndexedArray("outer")); err != nil {
+		t.Fatal(err)
+	}
+
+	subshell := &Script{Items: []ScriptItem{
+		item("", ExecutableCommand{Name: "setarray", Args: []string{"A", "inner"}}),
+	}}
+	group := &Script{Items: []ScriptItem{
+		item("", ExecutableCommand{Name: "setarray", Args: []string{"B", "x", "y"}}),
+	}}
+	if _, err := ex.ExecuteScript(Script{Items: []ScriptItem{
+		item("", ExecutableCommand{Subshell: subshell}),
+		item(";", ExecutableCommand{Group: group}),
+	}}); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+
+	if a, _ := ex.Vars.Array("A"); !reflect.DeepEqual(a.Values(), []string{"outer"}) {
+		t.Fatalf("подоболочка не должна менять массивы: A=%q", a.Values())
+	}
+	if b, ok := ex.Vars.Array("B"); !ok || !reflect.DeepEqual(b.Values(), []string{"x", "y"}) {
+		t.Fatalf("группа должна менять массивы текущей оболочки: B=%v", b)
+	}
+}
+
+func TestExecutor_SubshellIsolatesAliases(t *testing.T) {
+	ex := NewExecutor(map[string]string{}, []commands.BuiltinCommand{&commands.AliasCommand{}})
+	ex.Aliases.Set("ll", "ls -l")
+
+	subshell := &Script{Items: []ScriptItem{
+		item("", ExecutableCommand{Name: "alias", Args: []string{"ll=ls -la", "x=y"}}),
+	}}
+	if _, err := ex.ExecuteScript(Script{Items: []ScriptItem{
+		item("", ExecutableCommand{Subshell: subshell}),
+	}}); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+
+	if names := ex.Aliases.Names(); !reflect.DeepEqual(names, []string{"ll"}) {
+		t.Fatalf("подоболочка не должна менять псевдонимы: %q", names)
+	}
+	if ll, _ := ex.Aliases.Get("ll"); ll != "ls -l" {
+		t.Fatalf("ожидалось %q, получено %q", "ls -l", ll)
+	}
+}
+
+func TestExecutor_SubshellIsolatesShopt(t *testing.T) {
+	ex := NewExecutor(map[string]string{}, []commands.BuiltinCommand{&commands.ShoptCommand{}})
+
+	subshell := &Script{Items: []ScriptItem{
+		item("", ExecutableCommand{Name: "shopt", Args: []string{"-s", "dotglob"}}),
+	}}
+	group := &Script{Items: []ScriptItem{
+		item("", ExecutableCommand{Name: "shopt", Args: []string{"-s", "nullglob"}}),
+	}}
+	if _, err := ex.ExecuteScript(Script{Items: []ScriptItem{
+		item("", ExecutableCommand{Subshell: subshell}),
+		item(";", ExecutableCommand{Group: group}),
+	}}); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+
+	if ex.Options.Enabled(session.DotGlob) {
+		t.Fatalf("shopt в подоболочке не должен менять опции текущей оболочки")
+	}
+	if !ex.Options.Enabled(session.NullGlob) {
+		t.Fatalf("shopt в группе должен менять опции текущей оболочки")
+	}
+}
+
+func TestExecutor_GroupRedirectAndPipeline(t *testing.T) {
+	dir := t.TempDir()
+
+	echo := &funcBuiltin{
+		name: "say",
+		run: func(args []string, ctx *commands.CommandContext) error {
+			_, err := ctx.Stdout.Write([]byte(strings.Join(args, " ") + "\n"))
+			return err
+		},
+	}
+	upper := &funcBuiltin{
+		name: "upper",
+		run: func(args []string, ctx *commands.CommandContext) error {
+			payload, err := io.ReadAll(ctx.Stdin)
+			if err != nil {
+				return err
+			}
+			_, err = ctx.Stdout.Write([]byte(strings.ToUpper(string(payload))))
+			return err
+		},
+	}
+	ex := NewExecutor(map[string]string{}, []commands.BuiltinCommand{echo, upper})
+	ex.Dir = dir
+
+	group := &Script{Items: []ScriptItem{
+		item("", ExecutableCommand{Name: "say", Args: []string{"a"}}),
+		item(";", ExecutableCommand{Name: "say", Args: []string{"b"}}),
+	}}
+
+	if _, err := ex.ExecuteScript(Script{Items: []ScriptItem{
+		item("", ExecutableCommand{Group: group, Redirects: []Redirect{{FD: 1, Op: ">", Target: "out"}}}),
+		item(";", ExecutableCommand{Name: "say", Args: []string{"c"}, Redirects: []Redirect{{FD: 1, Op: ">>", Target: "out"}}}),
+	}}); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "out"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "a\nb\nc\n" {
+		t.Fatalf("ожидалось %q, получено %q", "a\nb\nc\n", string(content))
+	}
+
+	output := captureStdout(t, func() {
+		ex.Execute(Plan{Commands: []ExecutableCommand{
+			{Subshell: group},
+			{Name: "upper", Redirects: []Redirect{{FD: 0, Op: "<", Target: "out"}}},
+		}})
+	})
+	if output != "A\nB\nC\n" {
+		t.Fatalf("ожидалось %q, получено %q", "A\nB\nC\n", output)
+	}
+}
+
+func TestExecutor_RedirectError(t *testing.T) {
+	builtin := &mockBuiltin{name: "mock"}
+	ex := NewExecutor(map[string]string{}, []commands.BuiltinCommand{builtin})
+	ex.Dir = t.TempDir()
+
+	status := ex.Execute(Plan{Commands: []ExecutableCommand{
+		{Name: "mock", Redirects: []Redirect{{FD: 0, Op: "<", Target: "missing"}, {FD: 2, Op: ">", Target: "/dev/null"}}},
+	}})
+
+	if status != 1 || builtin.called {
+		t.Fatalf("при ошибке перенаправления команда не должна выполняться: status=%d", status)
+	}
+}
+
+func TestExecutor_ExitInSubshell(t *testing.T) {
+	ex := NewExecutor(map[string]string{}, []commands.BuiltinCommand{&commands.ExitCommand{}})
+	exit := &Script{Items: []ScriptItem{item("", ExecutableCommand{Name: "exit"})}}
+
+	if _, err := ex.ExecuteScript(Script{Items: []ScriptItem{item("", ExecutableCommand{Subshell: exit})}}); err != nil {
+		t.Fatalf("exit в подоболочке не должен завершать оболочку: %v", err)
+	}
+	_, err := ex.ExecuteScript(Script{Items: []ScriptItem{item("", ExecutableCommand{Group: exit})}})
+	if !customErrors.Is(err, customErrors.ErrExit) {
+		t.Fatalf("ожидалась ошибка ErrExit, получено: %v", err)
+	}
+}
+
+func TestExecutor_Conditional(t *testing.T) {
+	ex := NewExecutor(map[string]string{}, []commands.BuiltinCommand{statusBuiltin("ok", nil)})
+
+	match := &conditional.Expr{
+		Kind:     conditional.BinaryExpr,
+		Operator: "=~",
+		Args:     []string{"v1.2", `v([0-9]+)\.([0-9]+)`},
+	}
+
+	var status int
+	output := captureStdout(t, func() {
+		status, _ = ex.ExecuteScript(Script{Items: []ScriptItem{
+			item("", ExecutableCommand{Cond: match}),
+			item("&&", ExecutableCommand{Name: "ok"}),
+		}})
+	})
+	if status != 0 || output != "ok\n" {
+		t.Fatalf("ожидалось выполнение ok после истинного условия: status=%d, вывод %q", status, output)
+	}
+	if rematch, ok := ex.Vars.Array("BASH_REMATCH"); !ok || !reflect.DeepEqual(rematch.Values(), []string{"v1.2", "1", "2"}) {
+		t.Fatalf("BASH_REMATCH заполнен неверно: %v", rematch)
+	}
+
+	invalid := &conditional.Expr{Kind: conditional.BinaryExpr, Operator: "-eq", Args: []string{"x", "1"}}
+	quiet := []Redirect{{FD: 2, Op: ">", Target: "/dev/null"}}
+	if status := ex.Execute(Plan{Commands: []ExecutableCommand{{Cond: invalid, Redirects: quiet}}}); status != 2 {
+		t.Fatalf("ожидался код 2 для ошибки в выражении, получено %d", status)
+	}
+}
+
+func TestExecutor_PipelineStopsFollowingProducer(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "log"), []byte("first\nsecond\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ex := NewExecutor(map[string]string{}, []commands.BuiltinCommand{&commands.TailCommand{}, &commands.HeadCommand{}})
+	ex.Dir = dir
+
+	// tail -f не завершается сам: пайплайн должен прервать его,
+	// когда head прочитает нужную строку и завершится
+	done := make(chan string, 1)
+	go func() {
+		done <- captureStdout(t, func() {
+			ex.Execute(Plan{Commands: []ExecutableCommand{
+				{Name: "tail", Args: []string{"-f", "-s", "0.01", "log"}},
+				{Name: "head", Args: []string{"-n", "1"}},
+			}})
+		})
+	}()
+
+	select {
+	case output := <-done:
+		if output != "first\n" {
+			t.Fatalf("ожидалось %q, получено %q", "first\n", output)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("пайплайн с tail -f не завершился после выхода head")
+	}
+}
+
+func TestExecutor_ExecuteScriptContextCancel(t *testing.T) {
+	wait := &funcBuiltin{
+		name: "wait",
+		run: func(args []string, ctx *commands.CommandContext) error {
+			<-ctx.Done()
+			return &customErrors.ExitStatusError{Code: 130}
+		},
+	}
+	ex := NewExecutor(map[string]string{}, []commands.BuiltinCommand{wait})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan int, 1)
+	go func() {
+		status, _ := ex.ExecuteScriptContext(ctx, Script{Items: []ScriptItem{
+			item("", ExecutableCommand{Name: "wait"}, ExecutableCommand{Name: "wait"}),
+		}})
+		done <- status
+	}()
+	cancel()
+
+	select {
+	case status := <-done:
+		if status != 130 {
+			t.Fatalf("ожидался код 130, получено %d", status)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("команды не прерваны отменой контекста")
+	}
+}
+
+func TestExecutor_RunFromBuiltin(t *testing.T) {
+	var calls []string
+	statuses := map[string]int{}
+	builtins := []commands.BuiltinCommand{
+		statusBuiltin("fail", &customErrors.ExitStatusError{Code: 3}),
+		&funcBuiltin{name: "chdir", run: func(args []string, ctx *commands.CommandContext) error {
+			ctx.Dir = args[0]
+			return ctx.Vars.Set("CHANGED", "yes")
+		}},
+		&funcBuiltin{name: "exit", run: func(args []string, ctx *commands.CommandContext) error {
+			return customErrors.ErrExit
+		}},
+		&funcBuiltin{name: "spawn", run: func(args []string, ctx *commands.CommandContext) error {
+			for _, name := range args {
+				calls = append(calls, name)
+				statuses[name] = ctx.Run(name, []string{os.TempDir()}, ctx)
+			}
+			return nil
+		}},
+	}
+	ex := NewExecutor(map[string]string{}, builtins)
+	dir := ex.Dir
+
+	output := captureStdout(t, func() {
+		status, err := ex.ExecuteScript(Script{Items: []ScriptItem{
+			item("", ExecutableCommand{Name: "spawn", Args: []string{"fail", "chdir", "exit", "true", "no-such-command-xyz"}}),
+		}})
+		if status != 0 || err != nil {
+			t.Errorf("ожидался код 0 без ошибки, получено %d, %v", status, err)
+		}
+	})
+
+	if output != "fail\n" {
+		t.Errorf("ожидался вывод встроенной команды, получено %q", output)
+	}
+	expected := map[string]int{"fail": 3, "chdir": 0, "exit": 0, "true": 0, "no-such-command-xyz": statusNotFound}
+	for name, status := range expected {
+		if statuses[name] != status {
+			t.Errorf("%s: ожидался код %d, получено %d", name, status, statuses[name])
+		}
+	}
+	if len(calls) != len(expected) {
+		t.Errorf("ожидалось %d вызовов, получено %v", len(expected), calls)
+	}
+	if _, changed := ex.Vars.Get("CHANGED"); ex.Dir != dir || changed {
+		t.Errorf("команда, запущенная через Run, не должна менять состояние оболочки")
+	}
+}
+
+func TestExecutor_ErrExit(t *testing.T) {
+	builtins := []commands.BuiltinCommand{
+		statusBuiltin("ok", nil),
+		statusBuiltin("fail", errors.New("fail")),
+		statusBuiltin("after", nil),
+	}
+	failGroup := &Script{Items: []ScriptItem{
+		item("", ExecutableCommand{Name: "fail"}),
+		item(";", ExecutableCommand{Name: "after"}),
+	}}
+
+	tests := []struct {
+		name     string
+		items    []ScriptItem
+		output   string
+		exited   bool
+		expected int
+	}{
+		{"ошибка завершает список", []ScriptItem{
+			item("", ExecutableCommand{Name: "fail"}),
+			item(";", ExecutableCommand{Name: "after"}),
+		}, "fail\n", true, 1},
+		{"условие перед && и ||", []ScriptItem{
+			item("", ExecutableCommand{Name: "fail"}),
+			item("&&", ExecutableCommand{Name: "after"}),
+			item(";", ExecutableCommand{Name: "fail"}),
+			item("||", ExecutableCommand{Name: "ok"}),
+			item(";", ExecutableCommand{Name: "after"}),
+		}, "fail\nfail\nok\nafter\n", false, 0},
+		{"последняя команда списка ||", []ScriptItem{
+			item("", ExecutableCommand{Name: "fail"}),
+			item("||", ExecutableCommand{Name: "fail"}),
+			item(";", ExecutableCommand{Name: "after"}),
+		}, "fail\nfail\n", true, 1},
+		{"группа в условии", []ScriptItem{
+			item("", ExecutableCommand{Group: failGroup}),
+			item("&&", ExecutableCommand{Name: "after"}),
+		}, "fail\nafter\nafter\n", false, 0},
+		{"подоболочка", []ScriptItem{
+			item("", ExecutableCommand{Subshell: failGroup}),
+			item(";", ExecutableCommand{Name: "after"}),
+		}, "fail\n", true, 1},
+		{"пайплайн с кодом последней команды", []ScriptItem{
+			item("", ExecutableCommand{Name: "fail"}, ExecutableCommand{Name: "ok"}),
+			item(";", ExecutableCommand{Name: "after"}),
+		}, "ok\nafter\n", false, 0},
+	}
+
+	for _, tt := range tests {
+		ex := NewExecutor(map[string]string{}, builtins)
+		_ = ex.SetOptions.Set(session.ErrExit, true)
+
+		var (
+			status int
+			err    error
+		)
+		output := captureStdout(t, func() {
+			status, err = ex.ExecuteScript(Script{Items: tt.items})
+		})
+		if output != tt.output || status != tt.expected || customErrors.Is(err, customErrors.ErrExit) != tt.exited {
+			t.Errorf("%s: ожидалось %q (код %d, выход %v), получено %q (код %d, %v)",
+				tt.name, tt.output, tt.expected, tt.exited, output, status, err)
+		}
+	}
+}
+
+func TestExecutor_PipeFail(t *testing.T) {
+	// Команды ничего не пишут: ошибка записи в закрытый пайп изменила бы код
+	exitWith := func(name string, code int) *funcBuiltin {
+		return &funcBuiltin{name: name, run: func([]string, *commands.CommandContext) error {
+			if code == 0 {
+				return nil
+			}
+			return &customErrors.ExitStatusError{Code: code}
+		}}
+	}
+	builtins := []commands.BuiltinCommand{exitWith("ok", 0), exitWith("two", 2), exitWith("three", 3)}
+	ex := NewExecutor(map[string]string{}, builtins)
+	plan := Plan{Commands: []ExecutableCommand{{Name: "three"}, {Name: "two"}, {Name: "ok"}}}
+
+	if status := ex.Execute(plan); status != 0 {
+		t.Fatalf("без pipefail ожидался код последней команды 0, получено %d", status)
+	}
+
+	_ = ex.SetOptions.Set(session.PipeFail, true)
+	if status := ex.Execute(plan); status != 2 {
+		t.Fatalf("с pipefail ожидался код 2, получено %d", status)
+	}
+}
+
+func TestExecutor_XTrace(t *testing.T) {
+	ex := NewExecutor(map[string]string{}, []commands.BuiltinCommand{&mockBuiltin{name: "mock"}})
+	_ = ex.SetOptions.Set(session.XTrace, true)
+
+	var stderr bytes.Buffer
+	std := streams{stdin: strings.NewReader(""), stdout: io.Discard, stderr: &stderr}
+	group := &Script{Items: []ScriptItem{item("", ExecutableCommand{Name: "mock", Args: []string{"in group"}})}}
+	if _, err := ex.executeScript(Script{Items: []ScriptItem{
+		item("", ExecutableCommand{Name: "x=a b"}),
+		item(";", ExecutableCommand{Name: "a=", Array: []string{"1", "2 3"}}),
+		item(";", ExecutableCommand{Name: "mock", Args: []string{"plain", "it's", ""}}),
+		item(";", ExecutableCommand{Group: group}),
+		item(";", ExecutableCommand{Name: "PS4=>> "}),
+		item(";", ExecutableCommand{Name: "mock"}),
+	}}, std); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "+ x='a b'\n+ a=(1 '2 3')\n+ mock plain 'it'\\''s' ''\n+ mock 'in group'\n+ PS4='>> '\n>> mock\n"
+	if stderr.String() != expected {
+		t.Fatalf("ожидалось %q, получено %q", expected, stderr.String())
+	}
+}
+
+func TestExecutor_NoClobber(t *testing.T) {
+	ex := NewExecutor(map[string]string{}, []commands.BuiltinCommand{statusBuiltin("say", nil)})
+	ex.Dir = t.TempDir()
+	_ = ex.SetOptions.Set(session.NoClobber, true)
+
+	var stderr bytes.Buffer
+	std := streams{stdin: strings.NewReader(""), stdout: io.Discard, stderr: &stderr}
+	run := func(op, target string) int {
+		status, _ := ex.executePlan(Plan{Commands: []ExecutableCommand{
+			{Name: "say", Redirects: []Redirect{{FD: 1, Op: op, Target: target}}},
+		}}, std)
+		return status
+	}
+
+	if status := run(">", "out"); status != 0 {
+		t.Fatalf("новый файл должен создаваться, получено %d: %q", status, stderr.String())
+	}
+	if status := run(">", "out"); status != 1 || !strings.Contains(stderr.String(), "out: невозможно перезаписать существующий файл") {
+		t.Fatalf("ожидалась ошибка перезаписи, получено %d: %q", status, stderr.String())
+	}
+	if run(">>", "out") != 0 || run(">", os.DevNull) != 0 {
+		t.Fatalf(">> и устройства не должны запрещаться noclobber: %q", stderr.String())
+	}
+
+	content, err := os.ReadFile(filepath.Join(ex.Dir, "out"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(content) != "say\nsay\n" {
+		t.Fatalf("ожидалось %q, получено %q", "say\nsay\n", content)
+	}
+
+	// >| перезаписывает файл несмотря на noclobber
+	if status := run(">|", "out"); status != 0 {
+		t.Fatalf(">| не должен запрещаться noclobber, получено %d: %q", status, stderr.String())
+	}
+	if content, _ := os.ReadFile(filepath.Join(ex.Dir, "out")); string(content) != "say\n" {
+		t.Fatalf("ожидалось %q, получено %q", "say\n", content)
+	}
+}
+
+// newTrapExecutor создаёт executor, в котором команда ловушки "A B"
+// выполняется как "say A B", а say выводит свои аргументы.
+func newTrapExecutor(builtins ...commands.BuiltinCommand) *Executor {
+	say := &funcBuiltin{name: "say", run: func(args []string, ctx *commands.CommandContext) error {
+		_, err := ctx.Stdout.Write([]byte(strings.Join(args, " ") + "\n"))
+		return err
+	}}
+	ex := NewExecutor(map[string]string{}, append(builtins, say))
+	ex.Compile = func(command string) (Script, error) {
+		if command == "exit" {
+			return Script{}, customErrors.ErrExit
+		}
+		words := strings.Fields(command)
+		return Script{Items: []ScriptItem{item("", ExecutableCommand{Name: words[0], Args: words[1:]})}}, nil
+	}
+	return ex
+}
+
+func TestExecutor_ErrAndDebugTraps(t *testing.T) {
+	ex := newTrapExecutor(statusBuiltin("ok", nil), statusBuiltin("fail", errors.New("fail")))
+	ex.Traps.Set(session.TrapErr, "say err")
+	ex.Traps.Set(session.TrapDebug, "say debug")
+	_ = ex.SetOptions.Set(session.ErrExit, true)
+
+	var (
+		status int
+		err    error
+	)
+	output := captureStdout(t, func() {
+		status, err = ex.ExecuteScript(Script{Items: []ScriptItem{
+			item("", ExecutableCommand{Name: "fail"}),
+			item("||", ExecutableCommand{Name: "ok"}),
+			item(";", ExecutableCommand{Name: "fail"}),
+			item(";", ExecutableCommand{Name: "ok"}),
+		}})
+	})
+
+	// Условие перед || не вызывает ERR; ERR выполняется до выхода по set -e
+	expected := "debug\nfail\ndebug\nok\ndebug\nfail\nerr\n"
+	if output != expected || status != 1 || !customErrors.Is(err, customErrors.ErrExit) {
+		t.Fatalf("ожидалось %q (код 1, выход), получено %q (код %d, %v)", expected, output, status, err)
+	}
+}
+
+func TestExecutor_SignalTraps(t *testing.T) {
+	ex := newTrapExecutor()
+	ex.Traps.Set("INT", "say int")
+	ex.Traps.Set("TERM", "")
+	raise := &funcBuiltin{name: "raise", run: func(args []string, ctx *commands.CommandContext) error {
+		for _, name := range args {
+			ctx.Traps.Raise(name)
+		}
+		return nil
+	}}
+	ex.BuiltinCommands = append(ex.BuiltinCommands, raise)
+
+	output := captureStdout(t, func() {
+		_, _ = ex.ExecuteScript(Script{Items: []ScriptItem{
+			item("", ExecutableCommand{Name: "raise", Args: []string{"TERM", "INT"}}),
+			item(";", ExecutableCommand{Name: "say", Args: []string{"next"}}),
+		}})
+	})
+
+	// Сигнал обрабатывается перед следующей командой; пустая ловушка игнорирует его
+	if output != "int\nnext\n" {
+		t.Fatalf("ожидалось %q, получено %q", "int\nnext\n", output)
+	}
+}
+
+func TestExecutor_NestedTraps(t *testing.T) {
+	ex := newTrapExecutor(statusBuiltin("fail", errors.New("fail")))
+	ex.Traps.Set(session.TrapErr, "fail in handler")
+	ex.Traps.Set(session.TrapDebug, "say debug")
+
+	output := captureStdout(t, func() {
+		_, _ = ex.ExecuteScript(Script{Items: []ScriptItem{
+			item("", ExecutableCommand{Name: "fail"}),
+		}})
+	})
+
+	// Ошибка в обработчике ERR не вызывает ни ERR, ни DEBUG повторно
+	if output != "debug\nfail\nfail\n" || ex.Status != 1 {
+		t.Fatalf("ожидалось %q (код 1), получено %q (код %d)", "debug\nfail\nfail\n", output, ex.Status)
+	}
+}
+
+func TestExecutor_RunExitTrap(t *testing.T) {
+	ex := newTrapExecutor()
+	ex.Traps.Set(session.TrapExit, "say bye")
+
+	output := captureStdout(t, func() {
+		ex.RunExitTrap()
+		ex.RunExitTrap()
+	})
+	if output != "bye\n" {
+		t.Fatalf("ловушка EXIT должна выполниться один раз, получено %q", output)
+	}
+
+	// exit в обработчике не приводит к повторному выполнению
+	ex.Traps.Set(session.TrapExit, "exit")
+	ex.RunExitTrap()
+	if _, ok := ex.Traps.Get(session.TrapExit); ok {
+		t.Fatalf("ловушка EXIT должна быть снята")
+	}
+}
+
+func TestExecutor_SubshellResetsTraps(t *testing.T) {
+	ex := newTrapExecutor(statusBuiltin("fail", errors.New("fail")))
+	ex.Traps.Set(session.TrapErr, "say err")
+	subshell := &Script{Items: []ScriptItem{item("", ExecutableCommand{Name: "fail"})}}
+
+	output := captureStdout(t, func() {
+		_, _ = ex.ExecuteScript(Script{Items: []ScriptItem{
+			item("", ExecutableCommand{Subshell: subshell}),
+		}})
+	})
+
+	// Внутри подоболочки ловушки нет; ERR срабатывает на её код в текущей оболочке
+	if output != "fail\nerr\n" {
+		t.Fatalf("ожидалось %q, получено %q", "fail\nerr\n", output)
+	}
+}
+
+func TestExecutor_ExpandsSourceBeforeRunning(t *testing.T) {
+	ex := newTrapExecutor()
+	// Раскрытие подставляет $v из переменных оболочки, выполняющей команду
+	ex.Expand = func(source string, ctx *commands.CommandContext) (Script, error) {
+		value, _ := ctx.Vars.Get("v")
+		words := strings.Fields(strings.ReplaceAll(source, "$v", value))
+		switch {
+		case words[0] == "missing":
+			return Script{}, &customErrors.CommandNotFoundError{Command: words[0]}
+		case words[0] == ">":
+			return Script{Items: []ScriptItem{item("", ExecutableCommand{Redirects: []Redirect{{FD: 1, Op: ">", Target: words[1]}}})}}, nil
+		}
+		return Script{Items: []ScriptItem{item("", ExecutableCommand{Name: words[0], Args: words[1:]})}}, nil
+	}
+	ex.Dir = t.TempDir()
+
+	subshell := &Script{Items: []ScriptItem{
+		item("", ExecutableCommand{Source: "v=sub"}),
+		item(";", ExecutableCommand{Source: "say $v"}),
+	}}
+	var status int
+	output := captureStdout(t, func() {
+		status, _ = ex.ExecuteScript(Script{Items: []ScriptItem{
+			item("", ExecutableCommand{Source: "v=top"}),
+			item(";", ExecutableCommand{Subshell: subshell, Source: "> $v.txt"}),
+			item(";", ExecutableCommand{Source: "say $v"}),
+			item(";", ExecutableCommand{Source: "missing"}),
+		}})
+	})
+
+	if output != "top\n" || status != statusNotFound {
+		t.Fatalf("ожидалось %q (код %d), получено %q (код %d)", "top\n", statusNotFound, output, status)
+	}
+	// Перенаправление подоболочки раскрывается в текущей оболочке
+	if content, _ := os.ReadFile(filepath.Join(ex.Dir, "top.txt")); string(content) != "sub\n" {
+		t.Fatalf("ожидалось %q, получено %q", "sub\n", content)
+	}
+}
+
+func TestExecutor_SubshellRunsOwnExitTrap(t *testing.T) {
+	setTrap := &funcBuiltin{name: "settrap", run: func(args []string, ctx *commands.CommandContext) error {
+		ctx.Traps.Set(session.TrapExit, strings.Join(args, " "))
+		return nil
+	}}
+	ex := newTrapExecutor(setTrap)
+	ex.Traps.Set(session.TrapExit, "say outer")
+	subshell := &Script{Items: []ScriptItem{
+		item("", ExecutableCommand{Name: "settrap", Args: []string{"say", "inner"}}),
+		item(";", ExecutableCommand{Name: "say", Args: []string{"body"}}),
+	}}
+
+	output := captureStdout(t, func() {
+		_, _ = ex.ExecuteScript(Script{Items: []ScriptItem{
+			item("", ExecutableCommand{Subshell: subshell}),
+			item(";", ExecutableCommand{Name: "say", Args: []string{"after"}}),
+		}})
+	})
+
+	// Ловушка подоболочки выполняется при её завершении и не заменяет
+	// ловушку текущей оболочки
+	if output != "body\ninner\nafter\n" {
+		t.Fatalf("ожидалось %q, получено %q", "body\ninner\nafter\n", output)
+	}
+	if command, _ := ex.Traps.Get(session.TrapExit); command != "say outer" {
+		t.Fatalf("ловушка текущей оболочки изменилась: %q", command)
+	}
+}
@@ -0,0 +1,178 @@
+// Package gitignore разбирает файлы .gitignore и проверяет, исключён ли путь.
+//
+// Поддерживается основная часть формата git:
+//   - пустые строки и строки, начинающиеся с "#", пропускаются;
+//   - "!" в начале отменяет исключение, заданное ранее;
+//   - "/" в конце — правило применяется только к каталогам;
+//   - "/" в начале или в середине привязывает правило к каталогу .gitignore,
+//     иначе правило сравнивается с именем файла на любой глубине;
+//   - "*", "?" и "[...]" не совпадают с "/", "**" совпадает с любым
+//     количеством каталогов;
+//   - "\" экранирует "#", "!" и завершающие пробелы.
+package gitignore
+
+import (
+	"bufio"
+	"io"
+	"strings"
+
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/glob"
+)
+
+// FileName — имя файла с правилами исключения.
+const FileName = ".gitignore"
+
+// rule — одно правило файла .gitignore.
+type rule struct {
+	segments []string // части шаблона между "/"
+	negate   bool     // правило начинается с "!"
+	dirOnly  bool     // правило заканчивается на "/"
+	anchored bool     // шаблон сравнивается с путём от каталога .gitignore
+}
+
+// Ignore содержит правила одного файла .gitignore.
+type Ignore struct {
+	base  string // каталог файла относительно корня обхода, "" — корень
+	rules []rule
+}
+
+// Parse читает правила .gitignore из r. base — каталог, в котором лежит
+// файл, относительно корня обхода, с "/" в качестве разделителя.
+func Parse(r io.Reader, base string) (*Ignore, error) {
+	ig := &Ignore{base: strings.Trim(base, "/")}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if rl, ok := parseRule(scanner.Text()); ok {
+			ig.rules = append(ig.rules, rl)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return ig, nil
+}
+
+func parseRule(line string) (rule, bool) {
+	line = trimTrailingSpaces(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return rule{}, false
+	}
+
+	var rl rule
+	switch {
+	case strings.HasPrefix(line, "!"):
+		rl.negate = true
+		line = line[1:]
+	case strings.HasPrefix(line, `\!`), strings.HasPrefix(line, `\#`):
+		line = line[1:]
+	}
+
+	if strings.HasSuffix(line, "/") {
+		rl.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if strings.Contains(line, "/") {
+		rl.anchored = true
+		line = strings.TrimLeft(line, "/")
+	}
+	if line == "" {
+		return rule{}, false
+	}
+
+	rl.segments = strings.Split(line, "/")
+	return rl, true
+}
+
+// trimTrailingSpaces удаляет завершающие пробелы, кроме экранированных.
+func trimTrailingSpaces(line string) string {
+	for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, `\ `) {
+		line = line[:len(line)-1]
+	}
+	if strings.HasSuffix(line, `\ `) {
+		line = line[:len(line)-2] + " "
+	}
+	return line
+}
+
+// match сообщает, совпадает ли правило с путём rel относительно каталога .gitignore.
+func (rl rule) match(rel string, isDir bool) bool {
+	if rl.dirOnly && !isDir {
+		return false
+	}
+
+	parts := strings.Split(rel, "/")
+	if !rl.anchored {
+		return matchSegment(rl.segments[0], parts[len(parts)-1])
+	}
+	return matchSegments(rl.segments, parts)
+}
+
+// matchSegments сопоставляет части шаблона с частями пути.
+// Часть "**" совпадает с любым количеством частей пути, в том числе с нулём.
+func matchSegments(pattern, parts []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for k := 0; k <= len(parts); k++ {
+				if matchSegments(pattern[1:], parts[k:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(parts) == 0 || !matchSegment(pattern[0], parts[0]) {
+			return false
+		}
+		pattern, parts = pattern[1:], parts[1:]
+	}
+	return len(parts) == 0
+}
+
+func matchSegment(pattern, name string) bool {
+	return glob.Match(pattern, name, glob.Options{DotGlob: true})
+}
+
+// Matcher объединяет правила файлов .gitignore из вложенных каталогов.
+// Правила более глубоких файлов проверяются после правил внешних,
+// и, как в git, решение определяет последнее подходящее правило.
+type Matcher struct {
+	ignores []*Ignore
+}
+
+// With возвращает Matcher, дополненный правилами ig. Исходный Matcher
+// не изменяется, поэтому его можно использовать для соседних каталогов.
+func (m *Matcher) With(ig *Ignore) *Matcher {
+	next := &Matcher{}
+	if m != nil {
+		next.ignores = append(next.ignores, m.ignores...)
+	}
+	next.ignores = append(next.ignores, ig)
+	return next
+}
+
+// Ignored сообщает, исключён ли путь. path задаётся относительно корня
+// обхода с "/" в качестве разделителя, isDir — является ли путь каталогом.
+func (m *Matcher) Ignored(path string, isDir bool) bool {
+	if m == nil {
+		return false
+	}
+
+	path = strings.Trim(path, "/")
+	ignored := false
+	for _, ig := range m.ignores {
+		rel := path
+		if ig.base != "" {
+			var ok bool
+			if rel, ok = strings.CutPrefix(path, ig.base+"/"); !ok {
+				continue
+			}
+		}
+
+		for _, rl := range ig.rules {
+			if rl.match(rel, isDir) {
+				ignored = !rl.negate
+			}
+		}
+	}
+	return ignored
+}
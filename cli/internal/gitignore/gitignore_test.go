@@ -0,0 +1,84 @@
+package gitignore
+
+import (
+	"strings"
+	"testing"
+)
+
+func mustParse(t *testing.T, content, base string) *Ignore {
+	t.Helper()
+	ig, err := Parse(strings.NewReader(content), base)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	return ig
+}
+
+func TestMatcher_Ignored(t *testing.T) {
+	root := mustParse(t, `
+# комментарий
+*.log
+!keep.log
+build/
+/dist
+docs/**/*.tmp
+\#hash
+trailing\ 
+`, "")
+	m := (*Matcher)(nil).With(root)
+
+	tests := []struct {
+		path     string
+		isDir    bool
+		expected bool
+	}{
+		{"app.log", false, true},
+		{"sub/deep/app.log", false, true},
+		{"keep.log", false, false},
+		{"build", true, true},
+		{"build", false, false},
+		{"src/build", true, true},
+		{"dist", true, true},
+		{"src/dist", true, false},
+		{"docs/a.tmp", false, true},
+		{"docs/x/y/a.tmp", false, true},
+		{"other/a.tmp", false, false},
+		{"#hash", false, true},
+		{"trailing ", false, true},
+		{"main.go", false, false},
+	}
+
+	for _, tt := range tests {
+		if got := m.Ignored(tt.path, tt.isDir); got != tt.expected {
+			t.Errorf("Ignored(%q, %v): ожидалось %v, получено %v", tt.path, tt.isDir, tt.expected, got)
+		}
+	}
+}
+
+func TestMatcher_NestedFiles(t *testing.T) {
+	root := (*Matcher)(nil).With(mustParse(t, "*.gen\n", ""))
+	nested := root.With(mustParse(t, "!api.gen\n/local\n", "pkg"))
+
+	tests := []struct {
+		matcher  *Matcher
+		path     string
+		expected bool
+	}{
+		{nested, "pkg/api.gen", false},
+		{nested, "pkg/other.gen", true},
+		{nested, "pkg/local", true},
+		{nested, "local", false},
+		{root, "pkg/api.gen", true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.matcher.Ignored(tt.path, false); got != tt.expected {
+			t.Errorf("Ignored(%q): ожидалось %v, получено %v", tt.path, tt.expected, got)
+		}
+	}
+
+	var empty *Matcher
+	if empty.Ignored("anything", false) {
+		t.Errorf("пустой Matcher ничего не исключает")
+	}
+}
@@ -0,0 +1,172 @@
+package glob
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Expand раскрывает шаблон в список путей файловой системы.
+// Относительные шаблоны вычисляются относительно каталога dir, но пути
+// возвращаются в том виде, в котором их записал пользователь
+// (например, "src/*.go" → "src/main.go"). Результат отсортирован.
+// Если совпадений нет, возвращается пустой список.
+func Expand(pattern, dir string, opts Options) []string {
+	e := &expander{dir: dir, opts: opts}
+
+	prefix := ""
+	rest := pattern
+	if strings.HasPrefix(rest, "/") {
+		prefix = "/"
+		rest = strings.TrimLeft(rest, "/")
+	}
+
+	dirOnly := strings.HasSuffix(rest, "/")
+	var segments []string
+	for _, seg := range strings.Split(rest, "/") {
+		if seg != "" {
+			segments = append(segments, seg)
+		}
+	}
+	if len(segments) == 0 {
+		return nil
+	}
+
+	e.walk(prefix, segments)
+
+	var result []string
+	seen := make(map[string]bool, len(e.matches))
+	for _, m := range e.matches {
+		if dirOnly {
+			if !e.isDir(m) {
+				continue
+			}
+			m += "/"
+		}
+		if !seen[m] {
+			seen[m] = true
+			result = append(result, m)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+type expander struct {
+	dir     string
+	opts    Options
+	matches []string
+}
+
+// walk сопоставляет оставшиеся сегменты шаблона с содержимым каталога prefix.
+func (e *expander) walk(prefix string, segments []string) {
+	if len(segments) == 0 {
+		if _, err := os.Lstat(e.resolve(prefix)); err == nil {
+			e.matches = append(e.matches, prefix)
+		}
+		return
+	}
+
+	seg, rest := segments[0], segments[1:]
+
+	if seg == "**" && e.opts.GlobStar {
+		e.walkGlobStar(prefix, rest)
+		return
+	}
+
+	p := Compile(seg, e.opts)
+	if p.IsLiteral() {
+		e.walk(join(prefix, p.Literal()), rest)
+		return
+	}
+
+	for _, name := range e.readDir(prefix) {
+		if !e.visible(name, p) || !p.Match(name) {
+			continue
+		}
+		next := join(prefix, name)
+		if len(rest) == 0 {
+			e.matches = append(e.matches, next)
+		} else if e.isDir(next) {
+			e.walk(next, rest)
+		}
+	}
+}
+
+// walkGlobStar обрабатывает сегмент "**": он совпадает с нулём или более
+// каталогов. Если "**" — последний сегмент, он совпадает со всеми файлами
+// и каталогами в поддереве. Символьные ссылки на каталоги не раскрываются.
+func (e *expander) walkGlobStar(prefix string, rest []string) {
+	if len(rest) > 0 {
+		e.walk(prefix, rest)
+	}
+
+	for _, name := range e.readDir(prefix) {
+		if strings.HasPrefix(name, ".") && !e.opts.DotGlob {
+			continue
+		}
+		next := join(prefix, name)
+		if len(rest) == 0 {
+			e.matches = append(e.matches, next)
+		}
+
+		info, err := os.Lstat(e.resolve(next))
+		if err == nil && info.IsDir() {
+			e.walkGlobStar(next, rest)
+		}
+	}
+}
+
+// visible проверяет правило скрытых файлов: имена, начинающиеся с точки,
+// совпадают только с шаблоном, явно начинающимся с точки, либо при dotglob.
+// Имена "." и ".." никогда не совпадают с метасимволами.
+func (e *expander) visible(name string, p *Pattern) bool {
+	if !strings.HasPrefix(name, ".") {
+		return true
+	}
+	if name == "." || name == ".." {
+		return false
+	}
+	return p.leadingDot || e.opts.DotGlob
+}
+
+func (e *expander) readDir(prefix string) []string {
+	entries, err := os.ReadDir(e.resolve(prefix))
+	if err != nil {
+		return nil
+	}
+
+	names := make([]string, len(entries))
+	for i, entry := range entries {
+		names[i] = entry.Name()
+	}
+	return names
+}
+
+func (e *expander) isDir(path string) bool {
+	info, err := os.Stat(e.resolve(path))
+	return err == nil && info.IsDir()
+}
+
+// resolve превращает путь из результата в путь файловой системы.
+func (e *expander) resolve(path string) string {
+	if path == "" {
+		path = "."
+	}
+	if filepath.IsAbs(path) || e.dir == "" {
+		return path
+	}
+	return filepath.Join(e.dir, path)
+}
+
+func join(prefix, name string) string {
+	switch {
+	case prefix == "":
+		return name
+	case strings.HasSuffix(prefix, "/"):
+		return prefix + name
+	default:
+		return prefix + "/" + name
+	}
+}
@@ -0,0 +1,75 @@
+package glob
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// makeTree создаёт файлы (и промежуточные каталоги) в каталоге dir.
+func makeTree(t *testing.T, dir string, files ...string) {
+	t.Helper()
+	for _, f := range files {
+		path := filepath.Join(dir, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestExpand(t *testing.T) {
+	dir := t.TempDir()
+	makeTree(t, dir,
+		"b.log", "a.log", "c.txt", ".hidden.log",
+		"src/main.go", "src/util.go", "src/pkg/deep.go", "src/.cache/x.go",
+	)
+
+	tests := []struct {
+		name    string
+		pattern string
+		opts    Options
+		want    []string
+	}{
+		{"звёздочка", "*.log", Options{}, []string{"a.log", "b.log"}},
+		{"скрытые файлы с dotglob", "*.log", Options{DotGlob: true}, []string{".hidden.log", "a.log", "b.log"}},
+		{"явная точка", ".*.log", Options{}, []string{".hidden.log"}},
+		{"вложенный каталог", "src/*.go", Options{}, []string{"src/main.go", "src/util.go"}},
+		{"шаблон в каталоге", "s*/*.go", Options{}, []string{"src/main.go", "src/util.go"}},
+		{"нет совпадений", "*.none", Options{}, nil},
+		{"только каталоги", "*/", Options{}, []string{"src/"}},
+		{"** без globstar", "src/**/*.go", Options{}, []string{"src/pkg/deep.go"}},
+		{
+			"globstar", "**/*.go", Options{GlobStar: true},
+			[]string{"src/main.go", "src/pkg/deep.go", "src/util.go"},
+		},
+		{
+			"globstar в конце", "src/**", Options{GlobStar: true},
+			[]string{"src/main.go", "src/pkg", "src/pkg/deep.go", "src/util.go"},
+		},
+		{"extglob", "@(a|c).*", Options{ExtGlob: true}, []string{"a.log", "c.txt"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Expand(tt.pattern, dir, tt.opts)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("Expand(%q): ожидалось %q, получено %q", tt.pattern, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestExpand_AbsolutePattern(t *testing.T) {
+	dir := t.TempDir()
+	makeTree(t, dir, "one.txt", "two.txt")
+
+	got := Expand(filepath.Join(dir, "*.txt"), "/nonexistent", Options{})
+	want := []string{filepath.Join(dir, "one.txt"), filepath.Join(dir, "two.txt")}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ожидалось %q, получено %q", want, got)
+	}
+}
@@ -0,0 +1,363 @@
+// Package glob реализует сопоставление с шаблонами имён файлов в стиле bash:
+// *, ?, [...], [!...], классы символов [[:alpha:]] и, при включённом extglob,
+// расширенные шаблоны ?(...), *(...), +(...), @(...) и !(...).
+package glob
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Options управляет поведением сопоставления и раскрытия шаблонов.
+type Options struct {
+	DotGlob  bool // * и ? совпадают с именами, начинающимися с точки
+	GlobStar bool // ** совпадает с любым количеством каталогов
+	ExtGlob  bool // включает расширенные шаблоны ?(), *(), +(), @(), !()
+}
+
+type nodeKind int
+
+const (
+	literalNode nodeKind = iota
+	anyNode
+	starNode
+	classNode
+	groupNode
+)
+
+// node — элемент скомпилированного шаблона.
+type node struct {
+	kind nodeKind
+	r    rune       // literalNode
+	cls  *charClass // classNode
+	op   rune       // groupNode: один из ?*+@!
+	alts [][]node   // groupNode: альтернативы
+}
+
+// charClass описывает выражение в квадратных скобках.
+type charClass struct {
+	negated bool
+	ranges  [][2]rune
+	classes []string
+}
+
+func (c *charClass) matches(r rune) bool {
+	found := false
+	for _, rg := range c.ranges {
+		if r >= rg[0] && r <= rg[1] {
+			found = true
+			break
+		}
+	}
+	if !found {
+		for _, name := range c.classes {
+			if matchNamedClass(name, r) {
+				found = true
+				break
+			}
+		}
+	}
+	return found != c.negated
+}
+
+func matchNamedClass(name string, r rune) bool {
+	switch name {
+	case "alpha":
+		return unicode.IsLetter(r)
+	case "digit":
+		return r >= '0' && r <= '9'
+	case "alnum":
+		return unicode.IsLetter(r) || unicode.IsDigit(r)
+	case "upper":
+		return unicode.IsUpper(r)
+	case "lower":
+		return unicode.IsLower(r)
+	case "space":
+		return unicode.IsSpace(r)
+	case "blank":
+		return r == ' ' || r == '\t'
+	case "punct":
+		return unicode.IsPunct(r) || unicode.IsSymbol(r)
+	case "xdigit":
+		return strings.ContainsRune("0123456789abcdefABCDEF", r)
+	case "word":
+		return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+	case "cntrl":
+		return unicode.IsControl(r)
+	case "print":
+		return unicode.IsPrint(r)
+	case "graph":
+		return unicode.IsGraphic(r) && !unicode.IsSpace(r)
+	}
+	return false
+}
+
+// Pattern — скомпилированный шаблон.
+type Pattern struct {
+	nodes []node
+	// leadingDot показывает, что шаблон явно начинается с точки.
+	leadingDot bool
+	// literal показывает, что в шаблоне нет метасимволов.
+	literal bool
+}
+
+// Compile компилирует шаблон. Обратная косая черта экранирует следующий символ.
+// Некорректные конструкции (например, незакрытая "[") трактуются буквально,
+// как это делает bash.
+func Compile(pattern string, opts Options) *Pattern {
+	runes := []rune(pattern)
+	nodes, _ := compileSeq(runes, 0, opts, false)
+
+	p := &Pattern{nodes: nodes, literal: true}
+	for _, n := range nodes {
+		if n.kind != literalNode {
+			p.literal = false
+			break
+		}
+	}
+	p.leadingDot = len(nodes) > 0 && nodes[0].kind == literalNode && nodes[0].r == '.'
+	return p
+}
+
+// compileSeq компилирует последовательность до конца строки или,
+// если inGroup, до символа "|" или ")" верхнего уровня.
+func compileSeq(runes []rune, i int, opts Options, inGroup bool) ([]node, int) {
+	var nodes []node
+	for i < len(runes) {
+		r := runes[i]
+
+		if inGroup && (r == '|' || r == ')') {
+			return nodes, i
+		}
+
+		if opts.ExtGlob && strings.ContainsRune("?*+@!", r) && i+1 < len(runes) && runes[i+1] == '(' {
+			if group, next, ok := compileGroup(runes, i+2, r, opts); ok {
+				nodes = append(nodes, group)
+				i = next
+				continue
+			}
+		}
+
+		switch r {
+		case '\\':
+			if i+1 < len(runes) {
+				i++
+			}
+			nodes = append(nodes, node{kind: literalNode, r: runes[i]})
+		case '*':
+			// Несколько звёздочек подряд эквивалентны одной.
+			if len(nodes) == 0 || nodes[len(nodes)-1].kind != starNode {
+				nodes = append(nodes, node{kind: starNode})
+			}
+		case '?':
+			nodes = append(nodes, node{kind: anyNode})
+		case '[':
+			if cls, next, ok := compileClass(runes, i+1); ok {
+				nodes = append(nodes, node{kind: classNode, cls: cls})
+				i = next
+				continue
+			}
+			nodes = append(nodes, node{kind: literalNode, r: r})
+		default:
+			nodes = append(nodes, node{kind: literalNode, r: r})
+		}
+		i++
+	}
+	return nodes, i
+}
+
+// compileGroup компилирует расширенный шаблон op(alt1|alt2|...),
+// начиная с позиции сразу после открывающей скобки.
+func compileGroup(runes []rune, i int, op rune, opts Options) (node, int, bool) {
+	group := node{kind: groupNode, op: op}
+	for {
+		alt, next := compileSeq(runes, i, opts, true)
+		if next >= len(runes) {
+			return node{}, 0, false
+		}
+		group.alts = append(group.alts, alt)
+		if runes[next] == ')' {
+			return group, next + 1, true
+		}
+		i = next + 1
+	}
+}
+
+// compileClass разбирает выражение в квадратных скобках,
+// начиная с позиции сразу после "[". Возвращает позицию после "]".
+func compileClass(runes []rune, i int) (*charClass, int, bool) {
+	cls := &charClass{}
+	if i < len(runes) && (runes[i] == '!' || runes[i] == '^') {
+		cls.negated = true
+		i++
+	}
+
+	first := true
+	for i < len(runes) {
+		r := runes[i]
+		if r == ']' && !first {
+			return cls, i + 1, true
+		}
+		first = false
+
+		if r == '[' && i+1 < len(runes) && runes[i+1] == ':' {
+			end := indexRunes(runes[i+2:], ":]")
+			if end >= 0 {
+				cls.classes = append(cls.classes, string(runes[i+2:i+2+end]))
+				i += end + 4
+				continue
+			}
+		}
+
+		if r == '\\' && i+1 < len(runes) {
+			i++
+			r = runes[i]
+		}
+
+		lo, hi := r, r
+		if i+2 < len(runes) && runes[i+1] == '-' && runes[i+2] != ']' {
+			hi = runes[i+2]
+			if hi == '\\' && i+3 < len(runes) {
+				hi = runes[i+3]
+				i++
+			}
+			i += 2
+		}
+		cls.ranges = append(cls.ranges, [2]rune{lo, hi})
+		i++
+	}
+	return nil, 0, false
+}
+
+func indexRunes(runes []rune, sub string) int {
+	target := []rune(sub)
+	for i := 0; i+len(target) <= len(runes); i++ {
+		if string(runes[i:i+len(target)]) == sub {
+			return i
+		}
+	}
+	return -1
+}
+
+// Match сообщает, соответствует ли имя шаблону целиком.
+func (p *Pattern) Match(name string) bool {
+	return matchNodes(p.nodes, []rune(name))
+}
+
+// IsLiteral сообщает, что шаблон не содержит метасимволов
+// и совпадает только с самим собой (после удаления экранирования).
+func (p *Pattern) IsLiteral() bool {
+	return p.literal
+}
+
+// Literal возвращает текст шаблона без экранирования.
+// Имеет смысл только для шаблонов, у которых IsLiteral() == true.
+func (p *Pattern) Literal() string {
+	var b strings.Builder
+	for _, n := range p.nodes {
+		b.WriteRune(n.r)
+	}
+	return b.String()
+}
+
+func matchNodes(nodes []node, s []rune) bool {
+	for len(nodes) > 0 {
+		n := nodes[0]
+		switch n.kind {
+		case literalNode:
+			if len(s) == 0 || s[0] != n.r {
+				return false
+			}
+		case anyNode:
+			if len(s) == 0 {
+				return false
+			}
+		case classNode:
+			if len(s) == 0 || !n.cls.matches(s[0]) {
+				return false
+			}
+		case starNode:
+			if len(nodes) == 1 {
+				return true
+			}
+			for k := 0; k <= len(s); k++ {
+				if matchNodes(nodes[1:], s[k:]) {
+					return true
+				}
+			}
+			return false
+		case groupNode:
+			for k := 0; k <= len(s); k++ {
+				if matchGroup(n, s[:k]) && matchNodes(nodes[1:], s[k:]) {
+					return true
+				}
+			}
+			return false
+		}
+		nodes = nodes[1:]
+		s = s[1:]
+	}
+	return len(s) == 0
+}
+
+// matchGroup проверяет, что строка s целиком соответствует расширенному шаблону.
+func matchGroup(g node, s []rune) bool {
+	switch g.op {
+	case '?':
+		return len(s) == 0 || matchAnyAlt(g.alts, s)
+	case '@':
+		return matchAnyAlt(g.alts, s)
+	case '!':
+		return !matchAnyAlt(g.alts, s)
+	case '*':
+		return matchRepeat(g.alts, s)
+	case '+':
+		return matchAnyAlt(g.alts, s) || len(s) > 0 && matchRepeat(g.alts, s)
+	}
+	return false
+}
+
+func matchAnyAlt(alts [][]node, s []rune) bool {
+	for _, alt := range alts {
+		if matchNodes(alt, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchRepeat проверяет, что s разбивается на ноль или более подстрок,
+// каждая из которых соответствует одной из альтернатив.
+func matchRepeat(alts [][]node, s []rune) bool {
+	if len(s) == 0 {
+		return true
+	}
+	for k := 1; k <= len(s); k++ {
+		if matchAnyAlt(alts, s[:k]) && matchRepeat(alts, s[k:]) {
+			return true
+		}
+	}
+	return false
+}
+
+// Match сообщает, соответствует ли имя шаблону.
+func Match(pattern, name string, opts Options) bool {
+	return Compile(pattern, opts).Match(name)
+}
+
+// HasMeta сообщает, содержит ли шаблон неэкранированные метасимволы.
+func HasMeta(pattern string, opts Options) bool {
+	return !Compile(pattern, opts).IsLiteral()
+}
+
+// Escape экранирует метасимволы шаблона, чтобы строка совпадала только сама с собой.
+func Escape(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(`*?[]\!@+()|`, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -0,0 +1,95 @@
+package glob
+
+import "testing"
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"*.go", "main.go", true},
+		{"*.go", "main.txt", false},
+		{"?.txt", "a.txt", true},
+		{"?.txt", "ab.txt", false},
+		{"[abc].txt", "b.txt", true},
+		{"[!abc].txt", "b.txt", false},
+		{"[^abc].txt", "d.txt", true},
+		{"[a-c]*", "cat", true},
+		{"[a-c]*", "dog", false},
+		{"[[:digit:]][[:alpha:]]", "1x", true},
+		{"[[:upper:]]*", "readme", false},
+		{"[]]", "]", true},
+		{`\*`, "*", true},
+		{`\*`, "a", false},
+		{"[abc", "[abc", true},
+		{"файл?.txt", "файл1.txt", true},
+		{"a*b*c", "aXXbYYc", true},
+		{"a*b*c", "aXXbYY", false},
+	}
+
+	for _, tt := range tests {
+		if got := Match(tt.pattern, tt.name, Options{}); got != tt.want {
+			t.Errorf("Match(%q, %q) = %v, ожидалось %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMatch_ExtGlob(t *testing.T) {
+	opts := Options{ExtGlob: true}
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"@(foo|bar).txt", "foo.txt", true},
+		{"@(foo|bar).txt", "baz.txt", false},
+		{"?(x)y", "y", true},
+		{"?(x)y", "xy", true},
+		{"?(x)y", "xxy", false},
+		{"*(ab)", "ababab", true},
+		{"*(ab)", "", true},
+		{"+(ab)", "", false},
+		{"+(ab|c)", "abcab", true},
+		{"!(*.go)", "main.go", false},
+		{"!(*.go)", "README.md", true},
+	}
+
+	for _, tt := range tests {
+		if got := Match(tt.pattern, tt.name, opts); got != tt.want {
+			t.Errorf("Match(%q, %q) = %v, ожидалось %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+
+	if Match("@(a|b)", "a", Options{}) {
+		t.Errorf("без extglob расширенные шаблоны должны трактоваться буквально")
+	}
+}
+
+func TestHasMeta(t *testing.T) {
+	tests := map[string]bool{
+		"plain.txt": false,
+		"*.txt":     true,
+		"file?":     true,
+		"[ab]":      true,
+		"[":         false,
+		`\*`:        false,
+		"a]":        false,
+	}
+
+	for pattern, want := range tests {
+		if got := HasMeta(pattern, Options{}); got != want {
+			t.Errorf("HasMeta(%q) = %v, ожидалось %v", pattern, got, want)
+		}
+	}
+}
+
+func TestEscape(t *testing.T) {
+	name := "weird*[name]?.txt"
+	if !Match(Escape(name), name, Options{ExtGlob: true}) {
+		t.Fatalf("экранированный шаблон должен совпадать с исходной строкой")
+	}
+	if HasMeta(Escape(name), Options{}) {
+		t.Fatalf("экранированный шаблон не должен содержать метасимволов")
+	}
+}
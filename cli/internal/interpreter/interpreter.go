@@ -1,11 +1,13 @@
 package interpreter
 
 import (
-	"bufio"
+	"context"
 	"errors"
 	"fmt"
 	"os"
+	"os/signal"
 
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/commands"
 	customErrors "github.com/ggerlakh/software-design-mhs-itmo/cli/internal/errors"
 	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/executor"
 	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/parser"
@@ -22,26 +24,34 @@ type Interpreter struct {
 }
 
 // Start запускает основной цикл интерпретатора (REPL).
+// Строка ввода разбирается до раскрытия: каждая простая команда
+// раскрывается непосредственно перед выполнением, поэтому видит
+// результат предыдущих команд списка (a=1; echo $a).
+// При выходе — по exit, по концу ввода или при set -e — выполняется
+// ловушка EXIT.
 func (i *Interpreter) Start() {
 	fmt.Printf("Welcome to go-cli! To esacpe type %q.\n", exitCommand)
-	scanner := bufio.NewScanner(os.Stdin)
+
+	if i.Executor.Compile == nil {
+		i.Executor.Compile = i.compile
+	}
+	if i.Executor.Expand == nil {
+		i.Executor.Expand = i.expand
+	}
+	defer i.watchSignals()()
+	defer i.Executor.RunExitTrap()
 
 Loop:
 	for {
 		fmt.Print("> ")
-		if !scanner.Scan() {
-			break Loop
-		}
-
-		userInput := scanner.Text()
-
-		preprocessed, err := i.Preprocessor.Process(userInput)
+		// Строка читается без упреждающего чтения: read и mapfile в
+		// сценарии, поданном на stdin, читают его следующие строки
+		userInput, err := commands.ReadLine(os.Stdin)
 		if err != nil {
-			fmt.Printf("preprocessing error: %s\n", err)
-			continue
+			break Loop
 		}
 
-		parsedPipeline, err := i.Parser.Parse(preprocessed)
+		script, err := i.compile(userInput)
 		switch {
 		case errors.Is(err, customErrors.ErrExit):
 			break Loop
@@ -50,21 +60,102 @@ Loop:
 			continue
 		}
 
-		executionPlan := toExecutionPlan(parsedPipeline)
-		i.Executor.Execute(executionPlan)
+		if errors.Is(i.execute(script), customErrors.ErrExit) {
+			break Loop
+		}
 	}
 }
 
+// execute выполняет список команд. Пока он выполняется, Ctrl-C не завершает
+// интерпретатор, а прерывает встроенные команды (например, tail -f);
+// внешние команды получают сигнал от терминала сами.
+func (i *Interpreter) execute(script executor.Script) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	_, err := i.Executor.ExecuteScriptContext(ctx, script)
+	return err
+}
+
+// compile разбирает строку ввода или команду ловушки без раскрытия.
+func (i *Interpreter) compile(command string) (executor.Script, error) {
+	parsedList, err := i.Parser.ParseSource(command)
+	if err != nil {
+		return executor.Script{}, err
+	}
+	return toExecutionScript(parsedList), nil
+}
+
+// expand раскрывает и разбирает исходный текст команды в состоянии
+// оболочки, которая её выполняет.
+func (i *Interpreter) expand(source string, ctx *commands.CommandContext) (executor.Script, error) {
+	pre := i.Preprocessor.Bind(preprocessor.Session{
+		Vars:       ctx.Vars,
+		Options:    ctx.Options,
+		SetOptions: ctx.SetOptions,
+		Aliases:    ctx.Aliases,
+		Dir:        func() string { return ctx.Dir },
+	})
+	preprocessed, err := pre.Process(source)
+	if err != nil {
+		return executor.Script{}, err
+	}
+	parsedList, err := i.Parser.ParseList(preprocessed)
+	if err != nil {
+		return executor.Script{}, err
+	}
+	return toExecutionScript(parsedList), nil
+}
+
+func toExecutionScript(l parser.List) executor.Script {
+	script := executor.Script{
+		Items: make([]executor.ScriptItem, len(l.Items)),
+	}
+
+	for idx, item := range l.Items {
+		script.Items[idx] = executor.ScriptItem{
+			Op:   item.Op,
+			Plan: toExecutionPlan(item.Pipeline),
+		}
+	}
+
+	return script
+}
+
 func toExecutionPlan(p parser.Pipeline) executor.Plan {
 	plan := executor.Plan{
 		Commands: make([]executor.ExecutableCommand, len(p.Commands)),
 	}
 
 	for idx, cmd := range p.Commands {
-		plan.Commands[idx] = executor.ExecutableCommand{
-			Name: cmd.Name,
-			Args: append([]string{}, cmd.Args...),
+		converted := executor.ExecutableCommand{
+			Name:   cmd.Name,
+			Args:   append([]string{}, cmd.Args...),
+			Cond:   cmd.Cond,
+			Source: cmd.Source,
+		}
+		if cmd.Array != nil {
+			converted.Array = append([]string{}, cmd.Array...)
+		}
+
+		for _, r := range cmd.Redirects {
+			converted.Redirects = append(converted.Redirects, executor.Redirect{
+				FD:     r.FD,
+				Op:     r.Op,
+				Target: r.Target,
+			})
 		}
+
+		if cmd.Subshell != nil {
+			subshell := toExecutionScript(*cmd.Subshell)
+			converted.Subshell = &subshell
+		}
+		if cmd.Group != nil {
+			group := toExecutionScript(*cmd.Group)
+			converted.Group = &group
+		}
+
+		plan.Commands[idx] = converted
 	}
 
 	return plan
@@ -3,13 +3,21 @@ package interpreter
 import (
 	"io"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"runtime"
 	"strings"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/commands"
+	customErrors "github.com/ggerlakh/software-design-mhs-itmo/cli/internal/errors"
 	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/executor"
 	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/parser"
 	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/preprocessor"
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
 )
 
 func TestInterpreter_Creation(t *testing.T) {
@@ -43,6 +51,35 @@ func TestToExecutionPlan(t *testing.T) {
 	}
 }
 
+func TestToExecutionScript(t *testing.T) {
+	group := parser.List{Items: []parser.ListItem{
+		{Pipeline: parser.Pipeline{Commands: []parser.ParsedCommand{{Name: "echo", Args: []string{"a"}}}}},
+	}}
+	l := parser.List{Items: []parser.ListItem{
+		{Pipeline: parser.Pipeline{Commands: []parser.ParsedCommand{
+			{Group: &group, Redirects: []parser.Redirect{{FD: 1, Op: ">", Target: "out"}}},
+		}}},
+		{Op: "&&", Pipeline: parser.Pipeline{Commands: []parser.ParsedCommand{{Subshell: &group}}}},
+	}}
+
+	script := toExecutionScript(l)
+
+	if len(script.Items) != 2 || script.Items[1].Op != "&&" {
+		t.Fatalf("список сконвертирован неверно: %#v", script.Items)
+	}
+
+	first := script.Items[0].Plan.Commands[0]
+	if first.Group == nil || first.Group.Items[0].Plan.Commands[0].Name != "echo" {
+		t.Fatalf("группа сконвертирована неверно: %#v", first)
+	}
+	if len(first.Redirects) != 1 || first.Redirects[0] != (executor.Redirect{FD: 1, Op: ">", Target: "out"}) {
+		t.Fatalf("перенаправления сконвертированы неверно: %#v", first.Redirects)
+	}
+	if script.Items[1].Plan.Commands[0].Subshell == nil {
+		t.Fatalf("подоболочка сконвертирована неверно: %#v", script.Items[1])
+	}
+}
+
 type testBuiltin struct {
 	name string
 	run  func(args []string, ctx *commands.CommandContext) error
@@ -59,7 +96,6 @@ func (tb *testBuiltin) Exec(args []string, ctx *commands.CommandContext) error {
 
 func TestInterpreter_StartRunsCommands(t *testing.T) {
 	env := map[string]string{"TARGET": "world"}
-	pre := preprocessor.NewPreprocessor(&preprocessor.EnvSubstitutionStep{Env: env})
 	par := parser.NewParser([]string{"greet"})
 
 	var executed bool
@@ -72,6 +108,7 @@ func TestInterpreter_StartRunsCommands(t *testing.T) {
 		},
 	}
 	exec := executor.NewExecutor(env, []commands.BuiltinCommand{greet})
+	pre := preprocessor.NewPreprocessor(&preprocessor.EnvSubstitutionStep{Vars: exec.Vars})
 
 	inputReader, inputWriter, _ := os.Pipe()
 	_, _ = inputWriter.WriteString("greet $TARGET\nexit\n")
@@ -105,3 +142,370 @@ func TestInterpreter_StartRunsCommands(t *testing.T) {
 		t.Fatalf("не найден ожидаемый вывод: %q", string(output))
 	}
 }
+
+func TestInterpreter_StartSharesStdinWithRead(t *testing.T) {
+	env := map[string]string{}
+	par := parser.NewParser([]string{"read", "greet"})
+
+	var greeted []string
+	greet := &testBuiltin{
+		name: "greet",
+		run: func(args []string, ctx *commands.CommandContext) error {
+			greeted = append(greeted, strings.Join(args, " "))
+			return nil
+		},
+	}
+	exec := executor.NewExecutor(env, []commands.BuiltinCommand{&commands.ReadCommand{}, greet})
+	pre := preprocessor.NewPreprocessor(&preprocessor.EnvSubstitutionStep{Vars: exec.Vars})
+
+	// read забирает следующую строку сценария, и интерпретатор продолжает
+	// со строки после неё
+	inputReader, inputWriter, _ := os.Pipe()
+	_, _ = inputWriter.WriteString("read name\nworld\ngreet $name\nexit\ngreet unreachable\n")
+	_ = inputWriter.Close()
+
+	oldStdin, oldStdout := os.Stdin, os.Stdout
+	os.Stdin = inputReader
+	_, outputWriter, _ := os.Pipe()
+	os.Stdout = outputWriter
+	defer func() {
+		os.Stdin, os.Stdout = oldStdin, oldStdout
+		_ = outputWriter.Close()
+	}()
+
+	interpreter := &Interpreter{Preprocessor: pre, Parser: par, Executor: exec}
+	interpreter.Start()
+
+	if len(greeted) != 1 || greeted[0] != "world" {
+		t.Fatalf("ожидалось [world], получено %q", greeted)
+	}
+}
+
+func TestInterpreter_StartExpandsArrays(t *testing.T) {
+	env := map[string]string{}
+	par := parser.NewParser([]string{"declare", "unset", "greet"})
+
+	var greeted [][]string
+	greet := &testBuiltin{
+		name: "greet",
+		run: func(args []string, ctx *commands.CommandContext) error {
+			greeted = append(greeted, args)
+			return nil
+		},
+	}
+	exec := executor.NewExecutor(env, []commands.BuiltinCommand{&commands.DeclareCommand{}, &commands.UnsetCommand{}, greet})
+	pre := preprocessor.NewPreprocessor(&preprocessor.EnvSubstitutionStep{Vars: exec.Vars})
+
+	inputReader, inputWriter, _ := os.Pipe()
+	_, _ = inputWriter.WriteString(`files=(a.go "b c.go")
+files+=(d)
+unset 'files[0]'
+greet ${#files[@]} "${files[@]}" ${!files[@]}
+declare -A port=([http]=80)
+port[https]=443
+greet "${port[https]}" "${!port[@]}"
+`)
+	_ = inputWriter.Close()
+
+	oldStdin, oldStdout := os.Stdin, os.Stdout
+	os.Stdin = inputReader
+	_, outputWriter, _ := os.Pipe()
+	os.Stdout = outputWriter
+	defer func() {
+		os.Stdin, os.Stdout = oldStdin, oldStdout
+		_ = outputWriter.Close()
+	}()
+
+	interpreter := &Interpreter{Preprocessor: pre, Parser: par, Executor: exec}
+	interpreter.Start()
+
+	expected := [][]string{{"2", "b c.go", "d", "1", "2"}, {"443", "http", "https"}}
+	if !reflect.DeepEqual(greeted, expected) {
+		t.Fatalf("ожидалось %q, получено %q", expected, greeted)
+	}
+}
+
+func TestInterpreter_StartSetOptions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected [][]string
+	}{
+		{"set -e -o pipefail\ngreet $-\nfail || greet recovered\nfail\ngreet unreachable\n", [][]string{{"e"}, {"recovered"}}},
+		{"set -u\ngreet $missing\ngreet next\nset -e\ngreet ${missing}\ngreet unreachable\n", [][]string{{"next"}}},
+	}
+	for _, tt := range tests {
+		par := parser.NewParser([]string{"set", "greet", "fail"})
+
+		var greeted [][]string
+		greet := &testBuiltin{
+			name: "greet",
+			run: func(args []string, ctx *commands.CommandContext) error {
+				greeted = append(greeted, args)
+				return nil
+			},
+		}
+		fail := &testBuiltin{
+			name: "fail",
+			run: func([]string, *commands.CommandContext) error {
+				return &customErrors.ExitStatusError{Code: 1}
+			},
+		}
+		exec := executor.NewExecutor(map[string]string{}, []commands.BuiltinCommand{&commands.SetCommand{}, greet, fail})
+		pre := preprocessor.NewPreprocessor(&preprocessor.EnvSubstitutionStep{Vars: exec.Vars, SetOptions: exec.SetOptions})
+
+		inputReader, inputWriter, _ := os.Pipe()
+		_, _ = inputWriter.WriteString(tt.input)
+		_ = inputWriter.Close()
+
+		oldStdin, oldStdout := os.Stdin, os.Stdout
+		os.Stdin = inputReader
+		_, outputWriter, _ := os.Pipe()
+		os.Stdout = outputWriter
+
+		interpreter := &Interpreter{Preprocessor: pre, Parser: par, Executor: exec}
+		interpreter.Start()
+
+		os.Stdin, os.Stdout = oldStdin, oldStdout
+		_ = outputWriter.Close()
+
+		if !reflect.DeepEqual(greeted, tt.expected) {
+			t.Errorf("%q: ожидалось %q, получено %q", tt.input, tt.expected, greeted)
+		}
+		if exec.Status != 1 {
+			t.Errorf("%q: ожидался код 1, получено %d", tt.input, exec.Status)
+		}
+	}
+}
+
+// runGreeting выполняет сценарий input с командой greet, которая
+// запоминает свои аргументы, и с дополнительными командами builtins.
+func runGreeting(t *testing.T, input string, builtins ...commands.BuiltinCommand) [][]string {
+	t.Helper()
+	var greeted [][]string
+	greet := &testBuiltin{
+		name: "greet",
+		run: func(args []string, ctx *commands.CommandContext) error {
+			greeted = append(greeted, args)
+			return nil
+		},
+	}
+	builtins = append(builtins, greet, &commands.TrapCommand{}, &commands.ExitCommand{})
+	names := make([]string, len(builtins))
+	for idx, b := range builtins {
+		names[idx] = b.Name()
+	}
+
+	exec := executor.NewExecutor(map[string]string{}, builtins)
+	pre := preprocessor.NewPreprocessor(
+		&preprocessor.AliasExpansionStep{Aliases: exec.Aliases},
+		&preprocessor.BraceExpansionStep{},
+		&preprocessor.TildeExpansionStep{Vars: exec.Vars},
+		&preprocessor.EnvSubstitutionStep{Vars: exec.Vars, SetOptions: exec.SetOptions},
+		&preprocessor.GlobStep{Options: exec.Options, SetOptions: exec.SetOptions, Dir: func() string { return exec.Dir }},
+	)
+	interpreter := &Interpreter{Preprocessor: pre, Parser: parser.NewParser(names), Executor: exec}
+
+	inputReader, inputWriter, _ := os.Pipe()
+	_, _ = inputWriter.WriteString(input)
+	_ = inputWriter.Close()
+
+	oldStdin, oldStdout := os.Stdin, os.Stdout
+	os.Stdin = inputReader
+	_, outputWriter, _ := os.Pipe()
+	os.Stdout = outputWriter
+	defer func() {
+		os.Stdin, os.Stdout = oldStdin, oldStdout
+		_ = outputWriter.Close()
+	}()
+
+	interpreter.Start()
+	return greeted
+}
+
+func TestInterpreter_StartRunsExitTrap(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected [][]string
+	}{
+		// Команда ловушки раскрывается при выполнении, а не при установке
+		{"trap 'greet bye $name' EXIT\nname=world\nexit\ngreet unreachable\n", [][]string{{"bye", "world"}}},
+		{"trap 'greet bye' EXIT\ngreet hi\n", [][]string{{"hi"}, {"bye"}}},
+		// exit в обработчике не запускает ловушку EXIT повторно
+		{"trap 'greet bye; exit' EXIT\n", [][]string{{"bye"}}},
+		{"trap 'greet bye' 0\ntrap - EXIT\n", nil},
+		// Подоболочка выполняет свою ловушку EXIT при завершении
+		{"trap 'greet outer' EXIT; ( trap 'greet inner' EXIT; greet in ); greet after\n", [][]string{{"in"}, {"inner"}, {"after"}, {"outer"}}},
+		{"tmp=lock; trap 'greet rm $tmp' EXIT\n", [][]string{{"rm", "lock"}}},
+	}
+	for _, tt := range tests {
+		if greeted := runGreeting(t, tt.input); !reflect.DeepEqual(greeted, tt.expected) {
+			t.Errorf("%q: ожидалось %q, получено %q", tt.input, tt.expected, greeted)
+		}
+	}
+}
+
+func TestInterpreter_StartRunsSignalTrap(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("сигнал SIGTERM нельзя отправить процессу")
+	}
+
+	// raise отправляет процессу SIGTERM и ждёт, пока сигнал будет доставлен
+	raise := &testBuiltin{
+		name: "raise",
+		run: func(args []string, ctx *commands.CommandContext) error {
+			delivered := make(chan os.Signal, 1)
+			signal.Notify(delivered, syscall.SIGTERM)
+			defer signal.Stop(delivered)
+
+			process, err := os.FindProcess(os.Getpid())
+			if err != nil {
+				return err
+			}
+			if err := process.Signal(syscall.SIGTERM); err != nil {
+				return err
+			}
+			<-delivered
+			time.Sleep(50 * time.Millisecond)
+			return nil
+		},
+	}
+
+	input := "trap 'greet caught' TERM\nraise; greet next\ntrap - TERM\n"
+	expected := [][]string{{"caught"}, {"next"}}
+	if greeted := runGreeting(t, input, raise); !reflect.DeepEqual(greeted, expected) {
+		t.Fatalf("ожидалось %q, получено %q", expected, greeted)
+	}
+}
+
+func TestInterpreter_StartExpandsExtGlob(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.txt", "b.txt", "c.log"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var greeted [][]string
+	greet := &testBuiltin{
+		name: "greet",
+		run: func(args []string, ctx *commands.CommandContext) error {
+			greeted = append(greeted, args)
+			return nil
+		},
+	}
+	exec := executor.NewExecutor(map[string]string{}, []commands.BuiltinCommand{greet})
+	exec.Dir = dir
+	_ = exec.Options.Set(session.ExtGlob, true)
+	pre := preprocessor.NewPreprocessor(&preprocessor.GlobStep{
+		Options:    exec.Options,
+		SetOptions: exec.SetOptions,
+		Dir:        func() string { return exec.Dir },
+	})
+	interpreter := &Interpreter{Preprocessor: pre, Parser: parser.NewParser([]string{"greet"}), Executor: exec}
+
+	inputReader, inputWriter, _ := os.Pipe()
+	_, _ = inputWriter.WriteString("greet !(*.log); greet @(a|c).*\n")
+	_ = inputWriter.Close()
+
+	oldStdin, oldStdout := os.Stdin, os.Stdout
+	os.Stdin = inputReader
+	_, outputWriter, _ := os.Pipe()
+	os.Stdout = outputWriter
+	defer func() {
+		os.Stdin, os.Stdout = oldStdin, oldStdout
+		_ = outputWriter.Close()
+	}()
+
+	interpreter.Start()
+
+	expected := [][]string{{"a.txt", "b.txt"}, {"a.txt", "c.log"}}
+	if !reflect.DeepEqual(greeted, expected) {
+		t.Fatalf("ожидалось %q, получено %q", expected, greeted)
+	}
+}
+
+func TestInterpreter_StartGlobsInCurrentDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "d"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"top.log", filepath.Join("d", "a.log"), filepath.Join("d", "b.log")} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var greeted [][]string
+	greet := &testBuiltin{
+		name: "greet",
+		run: func(args []string, ctx *commands.CommandContext) error {
+			greeted = append(greeted, args)
+			return nil
+		},
+	}
+	exec := executor.NewExecutor(map[string]string{}, []commands.BuiltinCommand{&commands.CdCommand{}, greet})
+	exec.Dir = dir
+	pre := preprocessor.NewPreprocessor(&preprocessor.GlobStep{
+		Options:    exec.Options,
+		SetOptions: exec.SetOptions,
+		Dir:        func() string { return exec.Dir },
+	})
+	interpreter := &Interpreter{Preprocessor: pre, Parser: parser.NewParser([]string{"cd", "greet"}), Executor: exec}
+
+	inputReader, inputWriter, _ := os.Pipe()
+	_, _ = inputWriter.WriteString("greet *.log\ncd d\ngreet *.log\n")
+	_ = inputWriter.Close()
+
+	oldStdin, oldStdout := os.Stdin, os.Stdout
+	os.Stdin = inputReader
+	_, outputWriter, _ := os.Pipe()
+	os.Stdout = outputWriter
+	defer func() {
+		os.Stdin, os.Stdout = oldStdin, oldStdout
+		_ = outputWriter.Close()
+	}()
+
+	interpreter.Start()
+
+	// После cd шаблон раскрывается в новом рабочем каталоге
+	expected := [][]string{{"top.log"}, {"a.log", "b.log"}}
+	if !reflect.DeepEqual(greeted, expected) {
+		t.Fatalf("ожидалось %q, получено %q", expected, greeted)
+	}
+}
+
+func TestInterpreter_StartExpandsEachCommand(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "d"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"a.log", "b.log"} {
+		if err := os.WriteFile(filepath.Join(dir, "d", name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Каждая команда списка раскрывается перед выполнением и видит
+	// результат предыдущих команд той же строки
+	tests := []struct {
+		input    string
+		expected [][]string
+	}{
+		{"a=1; greet $a\n", [][]string{{"1"}}},
+		{"x=o; (x=i); greet $x\n", [][]string{{"o"}}},
+		{"echo k | ( read v; greet got $v )\n", [][]string{{"got", "k"}}},
+		{"cd '" + dir + "' && cd d && greet *.log\n", [][]string{{"a.log", "b.log"}}},
+		{"set -u; greet $undef || greet failed\n", [][]string{{"failed"}}},
+		{"set -f; greet *\n", [][]string{{"*"}}},
+		{"[[ s =~ (.) ]] && greet ${BASH_REMATCH[1]}\n", [][]string{{"s"}}},
+		{"alias hi='greet hi'; hi there\n", [][]string{{"hi", "there"}}},
+	}
+	for _, tt := range tests {
+		greeted := runGreeting(t, tt.input,
+			&commands.SetCommand{}, &commands.CdCommand{}, &commands.EchoCommand{},
+			&commands.ReadCommand{}, &commands.AliasCommand{})
+		if !reflect.DeepEqual(greeted, tt.expected) {
+			t.Errorf("%q: ожидалось %q, получено %q", tt.input, tt.expected, greeted)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package interpreter
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
+)
+
+// watchSignals подписывается на сигналы, для которых установлены ловушки,
+// и передаёт полученные сигналы в таблицу ловушек: их обработчики
+// выполняются между командами. Подписка обновляется при каждом изменении
+// таблицы, а сигналы без ловушек обрабатываются по умолчанию.
+// Возвращает функцию, отменяющую подписку.
+func (i *Interpreter) watchSignals() func() {
+	traps := i.Executor.Traps
+	channels := map[string]chan os.Signal{}
+
+	update := func() {
+		for _, s := range session.Signals {
+			_, trapped := traps.Get(s.Name)
+			ch, watched := channels[s.Name]
+			switch {
+			case trapped && !watched:
+				ch = make(chan os.Signal, 1)
+				signal.Notify(ch, syscall.Signal(s.Number))
+				channels[s.Name] = ch
+				go func(name string) {
+					for range ch {
+						traps.Raise(name)
+					}
+				}(s.Name)
+			case !trapped && watched:
+				// После Stop сигналы в канал не поступают, и его можно закрыть
+				signal.Stop(ch)
+				close(ch)
+				delete(channels, s.Name)
+			}
+		}
+	}
+
+	traps.Notify(update)
+	update()
+
+	return func() {
+		traps.Notify(nil)
+		for name, ch := range channels {
+			signal.Stop(ch)
+			close(ch)
+			delete(channels, name)
+		}
+	}
+}
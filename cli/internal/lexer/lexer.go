@@ -0,0 +1,345 @@
+// Package lexer разбивает строку ввода на слова и операторы с учётом кавычек.
+// Используется шагами препроцессинга (которым важно, какие символы были
+// экранированы) и парсером (которому нужны слова после удаления кавычек).
+package lexer
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Kind определяет тип токена.
+type Kind int
+
+const (
+	// Word — слово командной строки (имя команды, аргумент, присваивание).
+	Word Kind = iota
+	// Operator — управляющий оператор ("|", "&&", ";", "(", ...) или оператор перенаправления.
+	Operator
+)
+
+// Token описывает токен исходной строки.
+// Value хранит исходный текст токена вместе с кавычками,
+// Start и End — границы токена в исходной строке (End не включается).
+type Token struct {
+	Kind  Kind
+	Value string
+	Start int
+	End   int
+}
+
+// operators перечисляет поддерживаемые операторы.
+// Более длинные операторы должны идти раньше своих префиксов.
+var operators = []string{"||", "&&", ">>", ">|", "|", ";", "(", ")", ">", "<"}
+
+// Split разбивает строку на токены.
+// Кавычки и экранирование сохраняются в Value слов, чтобы последующие шаги
+// могли отличить экранированные символы от неэкранированных.
+func Split(input string) ([]Token, error) {
+	var tokens []Token
+
+	i := 0
+	for i < len(input) {
+		if isBlank(input[i]) {
+			i++
+			continue
+		}
+
+		if op := operatorAt(input, i); op != "" {
+			tokens = append(tokens, Token{Kind: Operator, Value: op, Start: i, End: i + len(op)})
+			i += len(op)
+			continue
+		}
+
+		end, err := scanWord(input, i)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, Token{Kind: Word, Value: input[i:end], Start: i, End: end})
+		i = end
+	}
+
+	return tokens, nil
+}
+
+// scanWord находит конец слова, начинающегося с позиции start.
+func scanWord(input string, start int) (int, error) {
+	i := start
+	for i < len(input) {
+		c := input[i]
+		switch {
+		case c == '(' && isCompoundPrefix(input[start:i]):
+			// Составное присваивание NAME=(...) — одно слово вместе со скобками
+			return scanCompound(input, i)
+		case strings.IndexByte("?*+@!", c) >= 0 && i+1 < len(input) && input[i+1] == '(':
+			// Шаблон extglob ?(...), *(...), +(...), @(...), !(...) — часть
+			// слова: скобки и | внутри него не являются операторами
+			end, err := scanExtGlob(input, i+1)
+			if err != nil {
+				return 0, err
+			}
+			i = end
+		case isBlank(c) || operatorAt(input, i) != "":
+			return i, nil
+		case c == '\\':
+			i += 2
+		case c == '\'':
+			end := strings.IndexByte(input[i+1:], '\'')
+			if end < 0 {
+				return 0, fmt.Errorf("lexer: незакрытая одинарная кавычка")
+			}
+			i += end + 2
+		case c == '"':
+			end, err := scanDoubleQuoted(input, i)
+			if err != nil {
+				return 0, err
+			}
+			i = end
+		case c == '$' && i+1 < len(input) && input[i+1] == '{':
+			end := strings.IndexByte(input[i:], '}')
+			if end < 0 {
+				return 0, fmt.Errorf("lexer: незакрытая фигурная скобка в ${...}")
+			}
+			i += end + 1
+		default:
+			i++
+		}
+	}
+
+	if i > len(input) {
+		i = len(input)
+	}
+	return i, nil
+}
+
+// scanCompound возвращает позицию сразу после скобки, закрывающей
+// составное присваивание; open — позиция открывающей скобки.
+func scanCompound(input string, open int) (int, error) {
+	for i := open + 1; i < len(input); i++ {
+		switch input[i] {
+		case '\\':
+			i++
+		case '\'':
+			end := strings.IndexByte(input[i+1:], '\'')
+			if end < 0 {
+				return 0, fmt.Errorf("lexer: незакрытая одинарная кавычка")
+			}
+			i += end + 1
+		case '"':
+			end, err := scanDoubleQuoted(input, i)
+			if err != nil {
+				return 0, err
+			}
+			i = end - 1
+		case ')':
+			return i + 1, nil
+		}
+	}
+	return 0, fmt.Errorf("lexer: незакрытая скобка в составном присваивании")
+}
+
+// scanExtGlob возвращает позицию сразу после скобки, закрывающей шаблон
+// extglob; open — позиция открывающей скобки. Шаблоны могут быть вложенными.
+func scanExtGlob(input string, open int) (int, error) {
+	depth := 0
+	for i := open; i < len(input); i++ {
+		switch input[i] {
+		case '\\':
+			i++
+		case '\'':
+			end := strings.IndexByte(input[i+1:], '\'')
+			if end < 0 {
+				return 0, fmt.Errorf("lexer: незакрытая одинарная кавычка")
+			}
+			i += end + 1
+		case '"':
+			end, err := scanDoubleQuoted(input, i)
+			if err != nil {
+				return 0, err
+			}
+			i = end - 1
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 {
+				return i + 1, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("lexer: незакрытая скобка в шаблоне extglob")
+}
+
+// isCompoundPrefix сообщает, что s — начало составного присваивания
+// массиву: "NAME=" или "NAME+=".
+func isCompoundPrefix(s string) bool {
+	name := strings.TrimSuffix(strings.TrimSuffix(s, "="), "+")
+	if len(name) == len(s) || name == "" || name[0] >= '0' && name[0] <= '9' {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if c != '_' && (c < '0' || c > '9') && (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+// SplitCompound разбирает составное присваивание массиву NAME=(...) или
+// NAME+=(...) и возвращает его часть до скобки ("NAME=" или "NAME+=") и
+// элементы после удаления кавычек. ok ложно, если word — не составное
+// присваивание.
+func SplitCompound(word string) (prefix string, elements []string, ok bool) {
+	open := strings.IndexByte(word, '(')
+	if open < 0 || !isCompoundPrefix(word[:open]) || !strings.HasSuffix(word, ")") {
+		return "", nil, false
+	}
+
+	tokens, err := Split(word[open+1 : len(word)-1])
+	if err != nil {
+		return "", nil, false
+	}
+	elements = make([]string, 0, len(tokens))
+	for _, tok := range tokens {
+		if tok.Kind == Operator {
+			return "", nil, false
+		}
+		elements = append(elements, Unquote(tok.Value))
+	}
+	return word[:open], elements, true
+}
+
+// scanDoubleQuoted возвращает позицию сразу после закрывающей двойной кавычки.
+func scanDoubleQuoted(input string, start int) (int, error) {
+	for i := start + 1; i < len(input); i++ {
+		switch input[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1, nil
+		}
+	}
+	return 0, fmt.Errorf("lexer: незакрытая двойная кавычка")
+}
+
+func operatorAt(input string, i int) string {
+	for _, op := range operators {
+		if strings.HasPrefix(input[i:], op) {
+			return op
+		}
+	}
+	return ""
+}
+
+func isBlank(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+// Segment — часть слова после удаления кавычек.
+// Quoted показывает, был ли текст заключён в кавычки или экранирован.
+type Segment struct {
+	Text   string
+	Quoted bool
+}
+
+// Segments удаляет кавычки из слова и возвращает его части
+// с пометкой о том, были ли они экранированы.
+func Segments(word string) []Segment {
+	var (
+		segments []Segment
+		current  strings.Builder
+		quoted   bool
+	)
+
+	emit := func(q bool) {
+		if q != quoted && current.Len() > 0 {
+			segments = append(segments, Segment{Text: current.String(), Quoted: quoted})
+			current.Reset()
+		}
+		quoted = q
+	}
+
+	for i := 0; i < len(word); i++ {
+		c := word[i]
+		switch c {
+		case '\\':
+			if i+1 < len(word) {
+				emit(true)
+				current.WriteByte(word[i+1])
+				i++
+			}
+		case '\'':
+			emit(true)
+			end := strings.IndexByte(word[i+1:], '\'')
+			if end < 0 {
+				end = len(word) - i - 1
+			}
+			current.WriteString(word[i+1 : i+1+end])
+			i += end + 1
+		case '"':
+			emit(true)
+			for i++; i < len(word) && word[i] != '"'; i++ {
+				if word[i] == '\\' && i+1 < len(word) && strings.IndexByte("$`\"\\\n", word[i+1]) >= 0 {
+					i++
+				}
+				current.WriteByte(word[i])
+			}
+		default:
+			emit(false)
+			current.WriteByte(c)
+		}
+	}
+
+	if current.Len() > 0 {
+		segments = append(segments, Segment{Text: current.String(), Quoted: quoted})
+	}
+
+	return segments
+}
+
+// Unquote удаляет кавычки и экранирование из слова.
+func Unquote(word string) string {
+	var b strings.Builder
+	for _, s := range Segments(word) {
+		b.WriteString(s.Text)
+	}
+	return b.String()
+}
+
+// IsQuoted сообщает, содержит ли слово кавычки или экранирование.
+func IsQuoted(word string) bool {
+	return strings.ContainsAny(word, `'"\`)
+}
+
+// Quote экранирует строку так, чтобы после разбора она превратилась
+// ровно в одно слово с исходным содержимым.
+func Quote(s string) string {
+	if s == "" {
+		return "''"
+	}
+
+	safe := true
+	for _, r := range s {
+		if !isSafeRune(r) {
+			safe = false
+			break
+		}
+	}
+	if safe {
+		return s
+	}
+
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func isSafeRune(r rune) bool {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		return true
+	case r > unicode.MaxASCII:
+		return !unicode.IsSpace(r)
+	}
+	return strings.ContainsRune("_@%+=:,./-", r)
+}
@@ -0,0 +1,134 @@
+package lexer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{"простые слова", "echo hello world", []string{"echo", "hello", "world"}},
+		{"одинарные кавычки", "echo 'a b' c", []string{"echo", "'a b'", "c"}},
+		{"двойные кавычки", `echo "a | b"`, []string{"echo", `"a | b"`}},
+		{"экранирование пробела", `echo a\ b`, []string{"echo", `a\ b`}},
+		{"пайп без пробелов", "echo a|wc", []string{"echo", "a", "|", "wc"}},
+		{"подстановка в фигурных скобках", "echo ${a b}", []string{"echo", "${a b}"}},
+		{"списки команд", "a&&b||c;d", []string{"a", "&&", "b", "||", "c", ";", "d"}},
+		{"подоболочка", "(cd x)", []string{"(", "cd", "x", ")"}},
+		{"перенаправления", "cmd 2>err >>out <in", []string{"cmd", "2", ">", "err", ">>", "out", "<", "in"}},
+		{"перенаправление без noclobber", "cmd >|out | wc", []string{"cmd", ">|", "out", "|", "wc"}},
+		{"операторы в кавычках", `echo "a;b" 'c&&d'`, []string{"echo", `"a;b"`, "'c&&d'"}},
+		{"составное присваивание", `a=(x "y z" ')') b+=() ; f (x)`, []string{`a=(x "y z" ')')`, "b+=()", ";", "f", "(", "x", ")"}},
+		{"шаблоны extglob", `ls !(*.log) +(a|b(c)).txt x*(")"|y)|wc`, []string{"ls", "!(*.log)", "+(a|b(c)).txt", `x*(")"|y)`, "|", "wc"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, err := Split(tt.input)
+			if err != nil {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+
+			var values []string
+			for _, tok := range tokens {
+				values = append(values, tok.Value)
+				if tt.input[tok.Start:tok.End] != tok.Value {
+					t.Fatalf("границы токена %q не совпадают с исходной строкой", tok.Value)
+				}
+			}
+			if !reflect.DeepEqual(values, tt.expected) {
+				t.Fatalf("ожидалось %q, получено %q", tt.expected, values)
+			}
+		})
+	}
+}
+
+func TestSplit_UnterminatedQuote(t *testing.T) {
+	for _, input := range []string{"echo 'abc", `echo "abc`, "echo ${abc", "a=(x y", "ls @(a|b"} {
+		if _, err := Split(input); err == nil {
+			t.Fatalf("ожидалась ошибка для %q", input)
+		}
+	}
+}
+
+func TestSplitCompound(t *testing.T) {
+	tests := []struct {
+		word     string
+		prefix   string
+		elements []string
+		ok       bool
+	}{
+		{`a=(x "y z" [k]=v)`, "a=", []string{"x", "y z", "[k]=v"}, true},
+		{"list+=()", "list+=", []string{}, true},
+		{"a=(x;y)", "", nil, false},
+		{"a=x", "", nil, false},
+		{"1a=(x)", "", nil, false},
+	}
+	for _, tt := range tests {
+		prefix, elements, ok := SplitCompound(tt.word)
+		if prefix != tt.prefix || !reflect.DeepEqual(elements, tt.elements) || ok != tt.ok {
+			t.Errorf("%q: ожидалось %q %q %v, получено %q %q %v", tt.word, tt.prefix, tt.elements, tt.ok, prefix, elements, ok)
+		}
+	}
+}
+
+func TestSplit_OperatorKind(t *testing.T) {
+	tokens, err := Split("a | b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tokens[1].Kind != Operator || tokens[0].Kind != Word {
+		t.Fatalf("неверные типы токенов: %#v", tokens)
+	}
+}
+
+func TestSegments(t *testing.T) {
+	segments := Segments(`a*'b*'"c\"d"\*`)
+	expected := []Segment{
+		{Text: "a*", Quoted: false},
+		{Text: `b*c"d*`, Quoted: true},
+	}
+	if !reflect.DeepEqual(segments, expected) {
+		t.Fatalf("ожидалось %#v, получено %#v", expected, segments)
+	}
+}
+
+func TestUnquote(t *testing.T) {
+	tests := map[string]string{
+		`'a b'`:      "a b",
+		`"a\$b"`:     "a$b",
+		`"a\nb"`:     `a\nb`,
+		`a\ b`:       "a b",
+		`x'y'"z"`:    "xyz",
+		`'it'\''s'`:  "it's",
+		`"single'"`:  "single'",
+		`plain-word`: "plain-word",
+	}
+
+	for input, expected := range tests {
+		if got := Unquote(input); got != expected {
+			t.Errorf("Unquote(%q): ожидалось %q, получено %q", input, expected, got)
+		}
+	}
+}
+
+func TestQuote_RoundTrip(t *testing.T) {
+	for _, s := range []string{"", "plain", "a b", "it's", "*.go", "$HOME", "файл.txt", `back\slash`} {
+		quoted := Quote(s)
+
+		tokens, err := Split(quoted)
+		if err != nil {
+			t.Fatalf("Quote(%q)=%q: ошибка разбора: %v", s, quoted, err)
+		}
+		if len(tokens) != 1 {
+			t.Fatalf("Quote(%q)=%q: ожидалось одно слово, получено %d", s, quoted, len(tokens))
+		}
+		if got := Unquote(tokens[0].Value); got != s {
+			t.Fatalf("Quote(%q)=%q: после разбора получено %q", s, quoted, got)
+		}
+	}
+}
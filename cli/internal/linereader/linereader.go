@@ -0,0 +1,65 @@
+// Package linereader читает поток построчно без ограничения на длину строки.
+//
+// В отличие от bufio.Scanner, Reader не прерывает чтение на строках длиннее
+// буфера и не изменяет данные: строка возвращается вместе с завершающим
+// "\n", символы "\r" сохраняются, а последняя строка без перевода строки
+// возвращается как есть. Склеив все строки, можно получить исходный поток
+// байт в байт.
+package linereader
+
+import (
+	"bufio"
+	"errors"
+	"io"
+)
+
+// BufferSize — размер буфера чтения. Строки длиннее буфера собираются
+// из нескольких частей.
+const BufferSize = 32 * 1024
+
+// Reader читает строки из потока.
+type Reader struct {
+	r    *bufio.Reader
+	line []byte // строка, собранная из нескольких частей буфера
+}
+
+// New создаёт Reader поверх r. Если r уже *bufio.Reader с буфером
+// не меньше BufferSize, он используется без дополнительной буферизации.
+func New(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReaderSize(r, BufferSize)}
+}
+
+// ReadLine возвращает следующую строку вместе с завершающим "\n".
+// Последняя строка потока может не заканчиваться "\n". В конце потока
+// возвращается io.EOF и пустая строка. Возвращённый срез действителен
+// только до следующего вызова ReadLine.
+//
+// При ошибке чтения возвращаются прочитанные до неё байты и ошибка.
+func (r *Reader) ReadLine() ([]byte, error) {
+	chunk, err := r.r.ReadSlice('\n')
+	if !errors.Is(err, bufio.ErrBufferFull) {
+		// Частый случай: строка целиком помещается в буфер
+		if errors.Is(err, io.EOF) && len(chunk) > 0 {
+			err = nil
+		}
+		return chunk, err
+	}
+
+	r.line = append(r.line[:0], chunk...)
+	for errors.Is(err, bufio.ErrBufferFull) {
+		chunk, err = r.r.ReadSlice('\n')
+		r.line = append(r.line, chunk...)
+	}
+	if errors.Is(err, io.EOF) {
+		err = nil
+	}
+	return r.line, err
+}
+
+// TrimNewline возвращает строку без завершающего "\n".
+func TrimNewline(line []byte) []byte {
+	if n := len(line); n > 0 && line[n-1] == '\n' {
+		return line[:n-1]
+	}
+	return line
+}
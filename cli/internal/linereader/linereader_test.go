@@ -0,0 +1,97 @@
+package linereader
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// readAll читает все строки из r.
+func readAll(t *testing.T, r io.Reader) []string {
+	t.Helper()
+	reader := New(r)
+	var lines []string
+	for {
+		line, err := reader.ReadLine()
+		if errors.Is(err, io.EOF) {
+			return lines
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, string(line))
+	}
+}
+
+func TestReadLine(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{"пустой поток", "", nil},
+		{"строки с переводом строки", "a\nb\n", []string{"a\n", "b\n"}},
+		{"последняя строка без перевода", "a\nb", []string{"a\n", "b"}},
+		{"CRLF сохраняется", "a\r\nb\r\n", []string{"a\r\n", "b\r\n"}},
+		{"пустые строки", "\n\n", []string{"\n", "\n"}},
+		{"нулевые байты", "a\x00b\n", []string{"a\x00b\n"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines := readAll(t, strings.NewReader(tt.input))
+			if strings.Join(lines, "|") != strings.Join(tt.expected, "|") || len(lines) != len(tt.expected) {
+				t.Fatalf("ожидалось %q, получено %q", tt.expected, lines)
+			}
+		})
+	}
+}
+
+func TestReadLineLong(t *testing.T) {
+	long := strings.Repeat("0123456789abcdef", 3*1024*1024/16)
+	input := "short\n" + long + "\n" + long + "\r\nend"
+
+	// iotest.HalfReader отдаёт данные мелкими порциями
+	for name, r := range map[string]io.Reader{
+		"целиком":   strings.NewReader(input),
+		"по частям": iotest.HalfReader(strings.NewReader(input)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			lines := readAll(t, r)
+			if len(lines) != 4 {
+				t.Fatalf("ожидалось 4 строки, получено %d", len(lines))
+			}
+			if lines[1] != long+"\n" || lines[2] != long+"\r\n" || lines[3] != "end" {
+				t.Fatal("длинные строки прочитаны неверно")
+			}
+			if strings.Join(lines, "") != input {
+				t.Fatal("склеенные строки не совпадают с исходным потоком")
+			}
+		})
+	}
+}
+
+func TestReadLineError(t *testing.T) {
+	failure := errors.New("сбой")
+	reader := New(io.MultiReader(strings.NewReader("a\nbc"), iotest.ErrReader(failure)))
+
+	line, err := reader.ReadLine()
+	if string(line) != "a\n" || err != nil {
+		t.Fatalf("ожидалось %q, получено %q (%v)", "a\n", line, err)
+	}
+	line, err = reader.ReadLine()
+	if string(line) != "bc" || !errors.Is(err, failure) {
+		t.Fatalf("ожидалось %q и ошибка чтения, получено %q (%v)", "bc", line, err)
+	}
+}
+
+func TestTrimNewline(t *testing.T) {
+	for input, expected := range map[string]string{"a\n": "a", "a": "a", "a\r\n": "a\r", "": ""} {
+		if got := TrimNewline([]byte(input)); !bytes.Equal(got, []byte(expected)) {
+			t.Errorf("ожидалось %q, получено %q", expected, got)
+		}
+	}
+}
@@ -1,22 +1,58 @@
 // Package parser отвечает за разбор пользовательского ввода на команды и пайпы.
 // Преобразует результат препроцессинга в независимую модель ParsedPipeline.
-// Поддерживает одиночные команды, пайпы и команду exit для завершения работы.
+// Поддерживает одиночные команды, пайпы и команду exit для завершения работы,
+// а также списки команд (;, &&, ||), подоболочки ( ... ), группы { ...; },
+// условные выражения [[ ... ]] и перенаправления ввода-вывода (<, >, >>, N>&M).
+// Слова разбиваются с учётом кавычек и экранирования, которые затем удаляются.
 package parser
 
 import (
+	"strconv"
 	"strings"
 
 	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/checkutils"
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/conditional"
 	customErrors "github.com/ggerlakh/software-design-mhs-itmo/cli/internal/errors"
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/lexer"
 	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/preprocessor"
 )
 
 const exitCommand = "exit"
 
+// Redirect описывает перенаправление ввода-вывода команды.
+//
+// Op принимает значения:
+//   - "<"  — чтение дескриптора FD из файла Target;
+//   - ">"  — запись дескриптора FD в файл Target с усечением;
+//   - ">>" — дозапись дескриптора FD в файл Target;
+//   - ">|" — запись с усечением, как ">", но без проверки noclobber;
+//   - ">&" — дублирование: FD становится копией дескриптора Target (например, 2>&1).
+type Redirect struct {
+	FD     int
+	Op     string
+	Target string
+}
+
 // ParsedCommand описывает команду, полученную после парсинга.
+// Для простой команды заполнены Name и Args. Для составного присваивания
+// массиву NAME=(...) Name содержит "NAME=" или "NAME+=", а Array — элементы
+// без кавычек (не nil, даже если список пуст). Для подоболочки ( ... )
+// заполнено поле Subshell, для группы { ...; } — поле Group,
+// для условного выражения [[ ... ]] — поле Cond.
+//
+// При разборе ParseSource простые команды и [[ ... ]] не разбираются:
+// поле Source содержит их исходный текст вместе с перенаправлениями. Для
+// подоболочки и группы Source содержит исходный текст перенаправлений
+// после закрывающей скобки.
 type ParsedCommand struct {
-	Name string
-	Args []string
+	Name      string
+	Args      []string
+	Array     []string
+	Redirects []Redirect
+	Subshell  *List
+	Group     *List
+	Cond      *conditional.Expr
+	Source    string
 }
 
 // Pipeline представляет результат парсинга командной строки.
@@ -24,6 +60,18 @@ type Pipeline struct {
 	Commands []ParsedCommand
 }
 
+// ListItem — элемент списка команд. Op задаёт оператор, которым элемент
+// связан с предыдущим: "" для первого элемента, ";", "&&" или "||".
+type ListItem struct {
+	Op       string
+	Pipeline Pipeline
+}
+
+// List — последовательность пайплайнов, связанных операторами ;, && и ||.
+type List struct {
+	Items []ListItem
+}
+
 // Parser отвечает за валидацию и разбор пользовательского ввода.
 // Хранит список известных встроенных команд для проверки.
 type Parser struct {
@@ -41,48 +89,486 @@ func NewParser(builtinNames []string) *Parser {
 }
 
 // Parse превращает результат препроцессинга в Pipeline.
+// Разбирает строку, содержащую один пайплайн; для списков команд
+// (;, &&, ||) используется ParseList.
 func (p *Parser) Parse(input preprocessor.PreprocessedInput) (Pipeline, error) {
-	if strings.TrimSpace(input.Value) == "" {
+	list, err := p.ParseList(input)
+	if err != nil {
+		return Pipeline{}, err
+	}
+
+	switch len(list.Items) {
+	case 0:
 		return Pipeline{}, nil
+	case 1:
+		return list.Items[0].Pipeline, nil
+	}
+	return Pipeline{}, &customErrors.SyntaxError{Token: list.Items[1].Op}
+}
+
+// ParseList превращает результат препроцессинга в список команд.
+func (p *Parser) ParseList(input preprocessor.PreprocessedInput) (List, error) {
+	return p.parse(input.Value, false)
+}
+
+// ParseSource разбирает строку до раскрытия: определяет структуру списка,
+// пайплайнов, подоболочек и групп, а простые команды и [[ ... ]]
+// сохраняет исходным текстом в ParsedCommand.Source. Их раскрывают и
+// разбирают через ParseList непосредственно перед выполнением, чтобы
+// раскрытие учитывало результат предыдущих команд списка.
+func (p *Parser) ParseSource(input string) (List, error) {
+	return p.parse(input, true)
+}
+
+func (p *Parser) parse(input string, deferred bool) (List, error) {
+	if strings.TrimSpace(input) == "" {
+		return List{}, nil
 	}
 
-	if strings.TrimSpace(input.Value) == exitCommand {
-		return Pipeline{}, customErrors.ErrExit
+	if strings.TrimSpace(input) == exitCommand {
+		return List{}, customErrors.ErrExit
 	}
 
-	segments := strings.Split(input.Value, "|")
-	var commands []ParsedCommand
+	tokens, err := lexer.Split(input)
+	if err != nil {
+		return List{}, err
+	}
 
-	for _, segment := range segments {
-		segment = strings.TrimSpace(segment)
-		if segment == "" {
-			continue
+	state := &parseState{parser: p, input: input, tokens: tokens, deferred: deferred}
+	list, err := state.parseList("")
+	if err != nil {
+		return List{}, err
+	}
+	if !state.atEnd() {
+		return List{}, &customErrors.SyntaxError{Token: state.peek().Value}
+	}
+
+	return list, nil
+}
+
+func (p *Parser) isKnownCommand(name string) bool {
+	_, ok := p.builtinNames[name]
+	return ok
+}
+
+// parseState хранит позицию рекурсивного спуска по токенам.
+type parseState struct {
+	parser *Parser
+	input  string
+	tokens []lexer.Token
+	pos    int
+	// deferred истинно при разборе ParseSource.
+	deferred bool
+}
+
+// source возвращает исходный текст токенов с позиции from до текущей.
+func (s *parseState) source(from int) string {
+	if from == s.pos {
+		return ""
+	}
+	return s.input[s.tokens[from].Start:s.tokens[s.pos-1].End]
+}
+
+func (s *parseState) atEnd() bool {
+	return s.pos >= len(s.tokens)
+}
+
+func (s *parseState) peek() lexer.Token {
+	return s.tokens[s.pos]
+}
+
+func (s *parseState) isOperator(values ...string) bool {
+	if s.atEnd() || s.peek().Kind != lexer.Operator {
+		return false
+	}
+	for _, v := range values {
+		if s.peek().Value == v {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *parseState) isReservedWord(word string) bool {
+	return !s.atEnd() && s.peek().Kind == lexer.Word && s.peek().Value == word
+}
+
+// unexpected возвращает синтаксическую ошибку для текущего токена.
+func (s *parseState) unexpected() error {
+	if s.atEnd() {
+		return &customErrors.SyntaxError{}
+	}
+	return &customErrors.SyntaxError{Token: s.peek().Value}
+}
+
+// atCloser сообщает, что текущий токен закрывает составную команду.
+func (s *parseState) atCloser(closer string) bool {
+	switch closer {
+	case ")":
+		return s.isOperator(")")
+	case "}":
+		return s.isReservedWord("}")
+	}
+	return false
+}
+
+// parseList разбирает список пайплайнов до конца ввода или до closer.
+func (s *parseState) parseList(closer string) (List, error) {
+	var list List
+	op := ""
+
+	for {
+		if s.atEnd() || s.atCloser(closer) {
+			if op == "&&" || op == "||" || len(list.Items) == 0 && closer != "" {
+				return List{}, s.unexpected()
+			}
+			return list, nil
+		}
+
+		pipeline, err := s.parsePipeline()
+		if err != nil {
+			return List{}, err
+		}
+		list.Items = append(list.Items, ListItem{Op: op, Pipeline: pipeline})
+
+		if s.atEnd() || s.atCloser(closer) {
+			return list, nil
+		}
+		if !s.isOperator(";", "&&", "||") {
+			return List{}, s.unexpected()
+		}
+		op = s.peek().Value
+		s.pos++
+	}
+}
+
+// parsePipeline разбирает команды, соединённые оператором "|".
+func (s *parseState) parsePipeline() (Pipeline, error) {
+	var pipeline Pipeline
+	for {
+		cmd, err := s.parseCommand()
+		if err != nil {
+			return Pipeline{}, err
+		}
+		pipeline.Commands = append(pipeline.Commands, cmd)
+
+		if !s.isOperator("|") {
+			return pipeline, nil
+		}
+		s.pos++
+	}
+}
+
+// parseCommand разбирает простую команду, подоболочку или группу.
+func (s *parseState) parseCommand() (ParsedCommand, error) {
+	var cmd ParsedCommand
+	start := s.pos
+
+	switch {
+	case s.isOperator("("):
+		s.pos++
+		list, err := s.parseList(")")
+		if err != nil {
+			return ParsedCommand{}, err
+		}
+		if !s.atCloser(")") {
+			return ParsedCommand{}, s.unexpected()
+		}
+		s.pos++
+		cmd.Subshell = &list
+	case s.isReservedWord("{"):
+		s.pos++
+		list, err := s.parseList("}")
+		if err != nil {
+			return ParsedCommand{}, err
+		}
+		if !s.atCloser("}") {
+			return ParsedCommand{}, s.unexpected()
+		}
+		s.pos++
+		cmd.Group = &list
+	case s.isReservedWord("[["):
+		s.pos++
+		expr, err := s.parseCondOr()
+		if err != nil {
+			return ParsedCommand{}, err
+		}
+		if !s.isReservedWord("]]") {
+			return ParsedCommand{}, s.unexpected()
+		}
+		s.pos++
+		cmd.Cond = expr
+	default:
+		return s.parseSimpleCommand()
+	}
+
+	// После составной команды допустимы только перенаправления
+	// и закрывающая скобка внешней группы.
+	redirects := s.pos
+	for s.isOperator("<", ">", ">>", ">|") {
+		redirect, err := s.parseRedirect(-1)
+		if err != nil {
+			return ParsedCommand{}, err
+		}
+		cmd.Redirects = append(cmd.Redirects, redirect)
+	}
+	if !s.atEnd() && s.peek().Kind == lexer.Word && s.peek().Value != "}" {
+		return ParsedCommand{}, s.unexpected()
+	}
+
+	if s.deferred {
+		if cmd.Cond != nil {
+			return ParsedCommand{Source: s.source(start)}, nil
+		}
+		cmd.Redirects = nil
+		cmd.Source = s.source(redirects)
+	}
+	return cmd, nil
+}
+
+// parseCondOr разбирает выражение [[ ... ]] с операторами ||.
+// Внутри [[ ... ]] слова не разделяются на команды: &&, ||, скобки,
+// < и > трактуются как операторы выражения.
+func (s *parseState) parseCondOr() (*conditional.Expr, error) {
+	left, err := s.parseCondAnd()
+	if err != nil {
+		return nil, err
+	}
+	for s.isOperator("||") {
+		s.pos++
+		right, err := s.parseCondAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &conditional.Expr{Kind: conditional.OrExpr, X: left, Y: right}
+	}
+	return left, nil
+}
+
+func (s *parseState) parseCondAnd() (*conditional.Expr, error) {
+	left, err := s.parseCondNot()
+	if err != nil {
+		return nil, err
+	}
+	for s.isOperator("&&") {
+		s.pos++
+		right, err := s.parseCondNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &conditional.Expr{Kind: conditional.AndExpr, X: left, Y: right}
+	}
+	return left, nil
+}
+
+func (s *parseState) parseCondNot() (*conditional.Expr, error) {
+	if s.isReservedWord("!") {
+		s.pos++
+		operand, err := s.parseCondNot()
+		if err != nil {
+			return nil, err
+		}
+		return &conditional.Expr{Kind: conditional.NotExpr, X: operand}, nil
+	}
+	return s.parseCondPrimary()
+}
+
+func (s *parseState) parseCondPrimary() (*conditional.Expr, error) {
+	if s.isOperator("(") {
+		s.pos++
+		expr, err := s.parseCondOr()
+		if err != nil {
+			return nil, err
+		}
+		if !s.isOperator(")") {
+			return nil, s.unexpected()
+		}
+		s.pos++
+		return expr, nil
+	}
+
+	if !s.isCondWord() {
+		return nil, s.unexpected()
+	}
+	first := s.peek()
+	s.pos++
+
+	if conditional.IsUnaryOperator(first.Value) && s.isCondWord() {
+		operand := s.peek()
+		s.pos++
+		return &conditional.Expr{
+			Kind:     conditional.UnaryExpr,
+			Operator: first.Value,
+			Args:     []string{lexer.Unquote(operand.Value)},
+		}, nil
+	}
+
+	if s.atEnd() {
+		return nil, s.unexpected()
+	}
+	op := s.peek().Value
+	if op != "=~" && !conditional.IsBinaryOperator(op) {
+		return &conditional.Expr{Kind: conditional.StringExpr, Args: []string{lexer.Unquote(first.Value)}}, nil
+	}
+	s.pos++
+
+	var right string
+	switch op {
+	case "=~":
+		raw, err := s.regexpOperand()
+		if err != nil {
+			return nil, err
+		}
+		right = conditional.RegexpOperand(raw)
+	case "==", "=", "!=":
+		if !s.isCondWord() {
+			return nil, s.unexpected()
 		}
+		right = conditional.PatternOperand(s.peek().Value)
+		s.pos++
+	default:
+		if !s.isCondWord() {
+			return nil, s.unexpected()
+		}
+		right = lexer.Unquote(s.peek().Value)
+		s.pos++
+	}
+
+	return &conditional.Expr{
+		Kind:     conditional.BinaryExpr,
+		Operator: op,
+		Args:     []string{lexer.Unquote(first.Value), right},
+	}, nil
+}
+
+// isCondWord сообщает, что текущий токен — операнд выражения [[ ... ]].
+func (s *parseState) isCondWord() bool {
+	return !s.atEnd() && s.peek().Kind == lexer.Word && s.peek().Value != "]]"
+}
+
+// regexpOperand собирает правый операнд =~ из токенов, записанных вплотную
+// друг к другу: в регулярном выражении допустимы неэкранированные (, ) и |.
+func (s *parseState) regexpOperand() (string, error) {
+	if s.atEnd() || s.isReservedWord("]]") {
+		return "", s.unexpected()
+	}
+
+	var b strings.Builder
+	prev := s.peek()
+	b.WriteString(prev.Value)
+	s.pos++
 
-		parts := strings.Fields(segment)
-		if len(parts) == 0 {
+	for !s.atEnd() && s.peek().Start == prev.End && !s.isReservedWord("]]") {
+		prev = s.peek()
+		b.WriteString(prev.Value)
+		s.pos++
+	}
+	return b.String(), nil
+}
+
+// parseSimpleCommand разбирает слова и перенаправления простой команды.
+func (s *parseState) parseSimpleCommand() (ParsedCommand, error) {
+	var (
+		cmd      ParsedCommand
+		words    []string
+		lastWord lexer.Token
+	)
+	start := s.pos
+
+	for !s.atEnd() {
+		tok := s.peek()
+
+		if tok.Kind == lexer.Word {
+			prefix, elements, compound := lexer.SplitCompound(tok.Value)
+			switch {
+			case compound && len(words) == 0:
+				words = append(words, prefix)
+				cmd.Array = elements
+			case compound:
+				// Аргумент вида NAME=(...) передаётся как есть: declare
+				// разбирает его сам
+				words = append(words, tok.Value)
+			default:
+				words = append(words, lexer.Unquote(tok.Value))
+			}
+			lastWord = tok
+			s.pos++
 			continue
 		}
 
-		name := parts[0]
-		args := parts[1:]
+		if !s.isOperator("<", ">", ">>", ">|") {
+			break
+		}
+
+		// Число, записанное вплотную к оператору, задаёт номер дескриптора: 2>file.
+		fd := -1
+		if len(words) > 0 && lastWord.End == tok.Start && isNumber(lastWord.Value) {
+			fd, _ = strconv.Atoi(lastWord.Value)
+			words = words[:len(words)-1]
+		}
+
+		redirect, err := s.parseRedirect(fd)
+		if err != nil {
+			return ParsedCommand{}, err
+		}
+		cmd.Redirects = append(cmd.Redirects, redirect)
+	}
+
+	switch {
+	case len(words) == 0 && len(cmd.Redirects) == 0:
+		return ParsedCommand{}, s.unexpected()
+	case s.deferred:
+		return ParsedCommand{Source: s.source(start)}, nil
+	case len(words) == 0:
+		return cmd, nil
+	}
+
+	name := words[0]
+	if !s.parser.isKnownCommand(name) &&
+		!checkutils.IsExternalCommand(name) &&
+		!checkutils.IsEnvAssignmentCommand(name) {
+		return ParsedCommand{}, &customErrors.CommandNotFoundError{Command: name}
+	}
+
+	cmd.Name = name
+	cmd.Args = words[1:]
+	return cmd, nil
+}
+
+// parseRedirect разбирает оператор перенаправления и его цель.
+// fd < 0 означает дескриптор по умолчанию: 0 для "<" и 1 для остальных.
+func (s *parseState) parseRedirect(fd int) (Redirect, error) {
+	op := s.peek().Value
+	s.pos++
 
-		if !p.isKnownCommand(name) &&
-			!checkutils.IsExternalCommand(name) &&
-			!checkutils.IsEnvAssignmentCommand(name) {
-			return Pipeline{}, &customErrors.CommandNotFoundError{Command: name}
+	if s.atEnd() || s.peek().Kind != lexer.Word {
+		return Redirect{}, s.unexpected()
+	}
+	target := s.peek().Value
+	s.pos++
+
+	if fd < 0 {
+		fd = 1
+		if op == "<" {
+			fd = 0
 		}
+	}
 
-		commands = append(commands, ParsedCommand{
-			Name: name,
-			Args: args,
-		})
+	if op == ">" && strings.HasPrefix(target, "&") && isNumber(target[1:]) {
+		return Redirect{FD: fd, Op: ">&", Target: target[1:]}, nil
 	}
 
-	return Pipeline{Commands: commands}, nil
+	return Redirect{FD: fd, Op: op, Target: lexer.Unquote(target)}, nil
 }
 
-func (p *Parser) isKnownCommand(name string) bool {
-	_, ok := p.builtinNames[name]
-	return ok
+func isNumber(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
 }
@@ -1,8 +1,10 @@
 package parser
 
 import (
+	"errors"
 	"testing"
 
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/conditional"
 	customErrors "github.com/ggerlakh/software-design-mhs-itmo/cli/internal/errors"
 	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/preprocessor"
 )
@@ -81,3 +83,230 @@ func TestParser_Parse_EmptyInput(t *testing.T) {
 		t.Fatalf("для пустой строки ожидается 0 команд")
 	}
 }
+
+func TestParser_Parse_QuotedArguments(t *testing.T) {
+	parser := newTestParser()
+
+	pipeline, err := parser.Parse(preprocessor.PreprocessedInput{Value: `echo 'a | b' "c d" e\ f`})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+
+	if len(pipeline.Commands) != 1 {
+		t.Fatalf("пайп в кавычках не должен разделять команды: %#v", pipeline.Commands)
+	}
+
+	args := pipeline.Commands[0].Args
+	if len(args) != 3 || args[0] != "a | b" || args[1] != "c d" || args[2] != "e f" {
+		t.Fatalf("кавычки обработаны неверно: %#v", args)
+	}
+}
+
+func TestParser_Parse_CompoundAssignment(t *testing.T) {
+	parser := NewParser([]string{"declare"})
+
+	pipeline, err := parser.Parse(preprocessor.PreprocessedInput{Value: `a+=(x 'y z')`})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	cmd := pipeline.Commands[0]
+	if cmd.Name != "a+=" || len(cmd.Array) != 2 || cmd.Array[0] != "x" || cmd.Array[1] != "y z" {
+		t.Fatalf("неверно распознано составное присваивание: %#v", cmd)
+	}
+
+	pipeline, err = parser.Parse(preprocessor.PreprocessedInput{Value: "a=()"})
+	if err != nil || pipeline.Commands[0].Array == nil || len(pipeline.Commands[0].Array) != 0 {
+		t.Fatalf("пустой список должен давать пустой, но не nil срез: %#v (%v)", pipeline.Commands, err)
+	}
+
+	// Аргумент команды передаётся без изменений: declare разбирает его сам
+	pipeline, err = parser.Parse(preprocessor.PreprocessedInput{Value: `declare -A m=([k]="v w")`})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	args := pipeline.Commands[0].Args
+	if len(args) != 2 || args[1] != `m=([k]="v w")` || pipeline.Commands[0].Array != nil {
+		t.Fatalf("неверно разобраны аргументы: %#v", pipeline.Commands[0])
+	}
+}
+
+func TestParser_Parse_UnterminatedQuote(t *testing.T) {
+	parser := newTestParser()
+
+	if _, err := parser.Parse(preprocessor.PreprocessedInput{Value: "echo 'oops"}); err == nil {
+		t.Fatalf("ожидалась ошибка для незакрытой кавычки")
+	}
+}
+
+func TestParser_ParseList_Operators(t *testing.T) {
+	parser := newTestParser()
+
+	list, err := parser.ParseList(preprocessor.PreprocessedInput{Value: "echo a; cat f && wc || pwd"})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+
+	expected := []struct{ op, name string }{{"", "echo"}, {";", "cat"}, {"&&", "wc"}, {"||", "pwd"}}
+	if len(list.Items) != len(expected) {
+		t.Fatalf("ожидалось %d элементов, получено: %#v", len(expected), list.Items)
+	}
+	for i, e := range expected {
+		item := list.Items[i]
+		if item.Op != e.op || item.Pipeline.Commands[0].Name != e.name {
+			t.Fatalf("элемент %d разобран неверно: %#v", i, item)
+		}
+	}
+}
+
+func TestParser_ParseList_SubshellAndGroup(t *testing.T) {
+	parser := newTestParser()
+
+	list, err := parser.ParseList(preprocessor.PreprocessedInput{Value: "(cat build && pwd) | wc; { echo a; echo b; } > out"})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if len(list.Items) != 2 {
+		t.Fatalf("ожидалось 2 элемента, получено: %#v", list.Items)
+	}
+
+	pipeline := list.Items[0].Pipeline
+	if len(pipeline.Commands) != 2 || pipeline.Commands[0].Subshell == nil || pipeline.Commands[1].Name != "wc" {
+		t.Fatalf("подоболочка в пайпе разобрана неверно: %#v", pipeline)
+	}
+	if items := pipeline.Commands[0].Subshell.Items; len(items) != 2 || items[1].Op != "&&" {
+		t.Fatalf("содержимое подоболочки разобрано неверно: %#v", items)
+	}
+
+	group := list.Items[1].Pipeline.Commands[0]
+	if group.Group == nil || len(group.Group.Items) != 2 {
+		t.Fatalf("группа разобрана неверно: %#v", group)
+	}
+	if len(group.Redirects) != 1 || group.Redirects[0] != (Redirect{FD: 1, Op: ">", Target: "out"}) {
+		t.Fatalf("перенаправление группы разобрано неверно: %#v", group.Redirects)
+	}
+}
+
+func TestParser_Parse_Redirects(t *testing.T) {
+	parser := newTestParser()
+
+	pipeline, err := parser.Parse(preprocessor.PreprocessedInput{Value: "cat < in >> 'out file' 2>&1 2> err >|log"})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+
+	expected := []Redirect{
+		{FD: 0, Op: "<", Target: "in"},
+		{FD: 1, Op: ">>", Target: "out file"},
+		{FD: 2, Op: ">&", Target: "1"},
+		{FD: 2, Op: ">", Target: "err"},
+		{FD: 1, Op: ">|", Target: "log"},
+	}
+	cmd := pipeline.Commands[0]
+	if cmd.Name != "cat" || len(cmd.Args) != 0 || len(cmd.Redirects) != len(expected) {
+		t.Fatalf("команда разобрана неверно: %#v", cmd)
+	}
+	for i, r := range expected {
+		if cmd.Redirects[i] != r {
+			t.Fatalf("ожидалось %#v, получено %#v", r, cmd.Redirects[i])
+		}
+	}
+}
+
+func TestParser_ParseList_SyntaxErrors(t *testing.T) {
+	parser := newTestParser()
+
+	inputs := []string{"echo a &&", "(echo a", "{ echo a; } }", "echo a; ; echo b", "( )", "{ echo a } x", "echo >", "| wc"}
+	for _, input := range inputs {
+		_, err := parser.ParseList(preprocessor.PreprocessedInput{Value: input})
+		var syntaxErr *customErrors.SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Fatalf("%q: ожидалась синтаксическая ошибка, получено: %v", input, err)
+		}
+	}
+}
+
+func TestParser_Parse_Conditional(t *testing.T) {
+	parser := newTestParser()
+
+	pipeline, err := parser.Parse(preprocessor.PreprocessedInput{
+		Value: `[[ -f go.mod && ( "a b" == "a"* || ! $v =~ ^(x|y)+$ ) ]] > out`,
+	})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+
+	cmd := pipeline.Commands[0]
+	if cmd.Cond == nil || len(cmd.Redirects) != 1 {
+		t.Fatalf("условное выражение разобрано неверно: %#v", cmd)
+	}
+
+	and := cmd.Cond
+	if and.Kind != conditional.AndExpr || and.X.Kind != conditional.UnaryExpr || and.X.Args[0] != "go.mod" {
+		t.Fatalf("оператор && разобран неверно: %#v", and)
+	}
+
+	or := and.Y
+	if or.Kind != conditional.OrExpr {
+		t.Fatalf("оператор || разобран неверно: %#v", or)
+	}
+	if pattern := or.X; pattern.Operator != "==" || pattern.Args[0] != "a b" || pattern.Args[1] != "a*" {
+		t.Fatalf("сравнение с шаблоном разобрано неверно: %#v", pattern)
+	}
+	if not := or.Y; not.Kind != conditional.NotExpr || not.X.Operator != "=~" || not.X.Args[1] != "^(x|y)+$" {
+		t.Fatalf("регулярное выражение разобрано неверно: %#v", not)
+	}
+}
+
+func TestParser_Parse_ConditionalSyntaxErrors(t *testing.T) {
+	parser := newTestParser()
+
+	for _, input := range []string{"[[ ]]", "[[ a == ]]", "[[ a", "[[ a b ]]", "[[ ( a ]]", "[[ a ]] b"} {
+		_, err := parser.Parse(preprocessor.PreprocessedInput{Value: input})
+		var syntaxErr *customErrors.SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Fatalf("%q: ожидалась синтаксическая ошибка, получено: %v", input, err)
+		}
+	}
+}
+
+func TestParser_ParseSource(t *testing.T) {
+	parser := newTestParser()
+
+	// Простые команды сохраняются исходным текстом, даже если имя команды
+	// станет известно только после раскрытия
+	list, err := parser.ParseSource(`a=1; $cmd "$a" 2>&1 | ( read v; echo $v ) > $out && [[ s =~ (.) ]]`)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if len(list.Items) != 3 || list.Items[1].Op != ";" || list.Items[2].Op != "&&" {
+		t.Fatalf("список разобран неверно: %#v", list.Items)
+	}
+
+	if source := list.Items[0].Pipeline.Commands[0].Source; source != "a=1" {
+		t.Fatalf("ожидалось %q, получено %q", "a=1", source)
+	}
+
+	pipeline := list.Items[1].Pipeline
+	if len(pipeline.Commands) != 2 || pipeline.Commands[0].Source != `$cmd "$a" 2>&1` {
+		t.Fatalf("пайплайн разобран неверно: %#v", pipeline.Commands)
+	}
+	subshell := pipeline.Commands[1]
+	if subshell.Subshell == nil || subshell.Source != "> $out" || subshell.Redirects != nil {
+		t.Fatalf("подоболочка разобрана неверно: %#v", subshell)
+	}
+	if body := subshell.Subshell.Items; len(body) != 2 || body[1].Pipeline.Commands[0].Source != "echo $v" {
+		t.Fatalf("тело подоболочки разобрано неверно: %#v", body)
+	}
+
+	if cond := list.Items[2].Pipeline.Commands[0]; cond.Cond != nil || cond.Source != "[[ s =~ (.) ]]" {
+		t.Fatalf("условное выражение разобрано неверно: %#v", cond)
+	}
+
+	for _, input := range []string{"echo a &&", "(echo a", "echo >", "{ echo a } x"} {
+		_, err := parser.ParseSource(input)
+		var syntaxErr *customErrors.SyntaxError
+		if !errors.As(err, &syntaxErr) {
+			t.Fatalf("%q: ожидалась синтаксическая ошибка, получено: %v", input, err)
+		}
+	}
+}
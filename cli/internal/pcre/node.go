@@ -0,0 +1,433 @@
+package pcre
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// matchLimit — сколько повторений и альтернатив может перебрать попытка
+// сопоставления с одной начальной позиции.
+const matchLimit = 1_000_000
+
+// errLimit — значение паники, которой machine прерывает сопоставление
+// при исчерпании лимита; find превращает её в ErrMatchLimit.
+type errLimit struct{}
+
+// machine хранит состояние одного сопоставления.
+type machine struct {
+	input string
+	caps  []int // границы групп: caps[2i], caps[2i+1]
+	steps int   // выполненные шаги перебора, см. matchLimit
+}
+
+// step учитывает очередной шаг перебора и прерывает сопоставление,
+// если лимит исчерпан.
+func (m *machine) step() {
+	m.steps++
+	if m.steps > matchLimit {
+		panic(errLimit{})
+	}
+}
+
+// cont — продолжение сопоставления: получает позицию, в которой закончился
+// текущий узел, и сообщает, удалось ли сопоставить остаток шаблона.
+type cont func(pos int) bool
+
+// node — узел дерева выражения. match пытается сопоставить узел,
+// начиная с позиции pos, и для каждого варианта вызывает k; возвраты
+// происходят, когда k возвращает false.
+type node interface {
+	match(m *machine, pos int, k cont) bool
+}
+
+type groupKind int
+
+const (
+	capturing groupKind = iota
+	nonCapturing
+	atomic
+	lookahead
+	negativeLookahead
+	lookbehind
+	negativeLookbehind
+)
+
+type repeatMode int
+
+const (
+	greedy repeatMode = iota
+	lazy
+	possessive
+)
+
+type assertionKind int
+
+const (
+	lineStart assertionKind = iota
+	lineEnd
+	textStart
+	textEnd
+	wordBoundary
+	notWordBoundary
+)
+
+// literal — один символ.
+type literal struct {
+	r    rune
+	fold bool
+}
+
+func (n *literal) match(m *machine, pos int, k cont) bool {
+	r, size := utf8.DecodeRuneInString(m.input[pos:])
+	if size == 0 || !(r == n.r || n.fold && equalFold(r, n.r)) {
+		return false
+	}
+	return k(pos + size)
+}
+
+// anyChar — "." — любой символ, кроме перевода строки.
+type anyChar struct{}
+
+func (n *anyChar) match(m *machine, pos int, k cont) bool {
+	r, size := utf8.DecodeRuneInString(m.input[pos:])
+	if size == 0 || r == '\n' {
+		return false
+	}
+	return k(pos + size)
+}
+
+// class — выражение в квадратных скобках или экранированный класс.
+type class struct {
+	ranges     [][2]rune
+	posix      []string
+	named      []rune   // d, w, s, h
+	subclasses []*class // \d, \W, ... внутри скобок
+	negate     bool
+	fold       bool
+}
+
+func (n *class) match(m *machine, pos int, k cont) bool {
+	r, size := utf8.DecodeRuneInString(m.input[pos:])
+	if size == 0 || !n.matches(r) {
+		return false
+	}
+	return k(pos + size)
+}
+
+func (n *class) matches(r rune) bool {
+	found := n.contains(r)
+	if !found && n.fold {
+		for f := unicode.SimpleFold(r); f != r && !found; f = unicode.SimpleFold(f) {
+			found = n.contains(f)
+		}
+	}
+	return found != n.negate
+}
+
+func (n *class) contains(r rune) bool {
+	for _, rg := range n.ranges {
+		if r >= rg[0] && r <= rg[1] {
+			return true
+		}
+	}
+	for _, name := range n.named {
+		if matchNamed(name, r) {
+			return true
+		}
+	}
+	for _, name := range n.posix {
+		if matchPosix(name, r) {
+			return true
+		}
+	}
+	for _, sub := range n.subclasses {
+		if sub.matches(r) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchNamed(name rune, r rune) bool {
+	switch name {
+	case 'd':
+		return r >= '0' && r <= '9'
+	case 'w':
+		return isWordChar(r)
+	case 's':
+		return unicode.IsSpace(r)
+	case 'h':
+		return r == ' ' || r == '\t' || r == 0xa0
+	}
+	return false
+}
+
+func matchPosix(name string, r rune) bool {
+	switch name {
+	case "alpha":
+		return unicode.IsLetter(r)
+	case "digit":
+		return r >= '0' && r <= '9'
+	case "alnum":
+		return unicode.IsLetter(r) || unicode.IsDigit(r)
+	case "upper":
+		return unicode.IsUpper(r)
+	case "lower":
+		return unicode.IsLower(r)
+	case "space":
+		return unicode.IsSpace(r)
+	case "blank":
+		return r == ' ' || r == '\t'
+	case "punct":
+		return unicode.IsPunct(r) || unicode.IsSymbol(r)
+	case "xdigit":
+		return strings.ContainsRune("0123456789abcdefABCDEF", r)
+	case "word":
+		return isWordChar(r)
+	case "cntrl":
+		return unicode.IsControl(r)
+	case "print":
+		return unicode.IsPrint(r)
+	case "graph":
+		return unicode.IsGraphic(r) && !unicode.IsSpace(r)
+	}
+	return false
+}
+
+func isWordChar(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_'
+}
+
+// equalFold сравнивает символы без учёта регистра.
+func equalFold(a, b rune) bool {
+	for f := unicode.SimpleFold(a); f != a; f = unicode.SimpleFold(f) {
+		if f == b {
+			return true
+		}
+	}
+	return false
+}
+
+// assertion — проверка позиции нулевой ширины.
+type assertion struct {
+	kind assertionKind
+}
+
+func (n *assertion) match(m *machine, pos int, k cont) bool {
+	ok := false
+	switch n.kind {
+	case lineStart, textStart:
+		ok = pos == 0
+	case lineEnd:
+		ok = pos == len(m.input) || pos == len(m.input)-1 && m.input[pos] == '\n'
+	case textEnd:
+		ok = pos == len(m.input)
+	case wordBoundary:
+		ok = atWordBoundary(m.input, pos)
+	case notWordBoundary:
+		ok = !atWordBoundary(m.input, pos)
+	}
+	return ok && k(pos)
+}
+
+func atWordBoundary(s string, pos int) bool {
+	before, after := false, false
+	if pos > 0 {
+		r, _ := utf8.DecodeLastRuneInString(s[:pos])
+		before = isWordChar(r)
+	}
+	if pos < len(s) {
+		r, _ := utf8.DecodeRuneInString(s[pos:])
+		after = isWordChar(r)
+	}
+	return before != after
+}
+
+// sequence — последовательность узлов.
+type sequence struct {
+	items []node
+}
+
+func (n *sequence) match(m *machine, pos int, k cont) bool {
+	return n.matchFrom(m, 0, pos, k)
+}
+
+func (n *sequence) matchFrom(m *machine, i, pos int, k cont) bool {
+	if i == len(n.items) {
+		return k(pos)
+	}
+	return n.items[i].match(m, pos, func(next int) bool {
+		return n.matchFrom(m, i+1, next, k)
+	})
+}
+
+// alternation — альтернативы, перебираемые слева направо.
+type alternation struct {
+	alts []node
+}
+
+func (n *alternation) match(m *machine, pos int, k cont) bool {
+	for _, alt := range n.alts {
+		m.step()
+		if alt.match(m, pos, k) {
+			return true
+		}
+	}
+	return false
+}
+
+// group — захватывающая группа.
+type group struct {
+	sub   node
+	index int
+}
+
+func (n *group) match(m *machine, pos int, k cont) bool {
+	return n.sub.match(m, pos, func(end int) bool {
+		oldStart, oldEnd := m.caps[2*n.index], m.caps[2*n.index+1]
+		m.caps[2*n.index], m.caps[2*n.index+1] = pos, end
+		if k(end) {
+			return true
+		}
+		m.caps[2*n.index], m.caps[2*n.index+1] = oldStart, oldEnd
+		return false
+	})
+}
+
+// atomicGroup — группа, внутрь которой не происходят возвраты.
+type atomicGroup struct {
+	sub node
+}
+
+func (n *atomicGroup) match(m *machine, pos int, k cont) bool {
+	end, ok := matchOnce(m, n.sub, pos)
+	return ok && k(end)
+}
+
+// matchOnce находит первый вариант сопоставления узла без возвратов.
+func matchOnce(m *machine, n node, pos int) (int, bool) {
+	end := -1
+	ok := n.match(m, pos, func(e int) bool {
+		end = e
+		return true
+	})
+	return end, ok
+}
+
+// lookaround — проверка (?=...), (?!...), (?<=...) или (?<!...).
+type lookaround struct {
+	sub    node
+	behind bool
+	negate bool
+}
+
+func (n *lookaround) match(m *machine, pos int, k cont) bool {
+	saved := append([]int(nil), m.caps...)
+
+	found := false
+	if n.behind {
+		// Ищем начало, из которого подвыражение заканчивается ровно в pos
+		for start := pos; start >= 0 && !found; start-- {
+			if start < pos && !utf8.RuneStart(m.input[start]) {
+				continue
+			}
+			found = n.sub.match(m, start, func(end int) bool { return end == pos })
+		}
+	} else {
+		_, found = matchOnce(m, n.sub, pos)
+	}
+
+	if found == n.negate {
+		copy(m.caps, saved)
+		return false
+	}
+	if n.negate {
+		// Группы внутри отрицательной проверки не захватываются
+		copy(m.caps, saved)
+	}
+	if k(pos) {
+		return true
+	}
+	copy(m.caps, saved)
+	return false
+}
+
+// backref — обратная ссылка на группу.
+type backref struct {
+	index int
+	fold  bool
+}
+
+func (n *backref) match(m *machine, pos int, k cont) bool {
+	start, end := m.caps[2*n.index], m.caps[2*n.index+1]
+	if start < 0 {
+		// Ссылка на неучаствовавшую группу не совпадает, как в PCRE
+		return false
+	}
+
+	text := m.input[start:end]
+	rest := m.input[pos:]
+	switch {
+	case strings.HasPrefix(rest, text):
+		return k(pos + len(text))
+	case n.fold:
+		// Длина в байтах при сравнении без регистра может отличаться
+		for size := 0; size <= len(rest); {
+			if strings.EqualFold(rest[:size], text) {
+				return k(pos + size)
+			}
+			if size == len(rest) {
+				break
+			}
+			_, runeSize := utf8.DecodeRuneInString(rest[size:])
+			size += runeSize
+		}
+	}
+	return false
+}
+
+// repeat — квантификатор.
+type repeat struct {
+	sub      node
+	min, max int // max < 0 — без ограничения
+	mode     repeatMode
+}
+
+func (n *repeat) match(m *machine, pos int, k cont) bool {
+	if n.mode == possessive {
+		end, count := pos, 0
+		for n.max < 0 || count < n.max {
+			next, ok := matchOnce(m, n.sub, end)
+			if !ok || next == end {
+				break
+			}
+			end = next
+			count++
+		}
+		return count >= n.min && k(end)
+	}
+	return n.matchCount(m, pos, 0, k)
+}
+
+// matchCount сопоставляет оставшиеся повторения после count выполненных.
+func (n *repeat) matchCount(m *machine, pos, count int, k cont) bool {
+	canStop := count >= n.min
+	canGo := n.max < 0 || count < n.max
+	m.step()
+
+	more := func() bool {
+		return n.sub.match(m, pos, func(next int) bool {
+			// Пустое повторение сверх минимума ничего не даёт: выходим из цикла
+			if next == pos && count >= n.min {
+				return false
+			}
+			return n.matchCount(m, next, count+1, k)
+		})
+	}
+
+	if n.mode == lazy {
+		return canStop && k(pos) || canGo && more()
+	}
+	return canGo && more() || canStop && k(pos)
+}
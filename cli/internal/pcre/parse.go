@@ -0,0 +1,498 @@
+package pcre
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// parser разбирает шаблон в дерево узлов методом рекурсивного спуска.
+type parser struct {
+	src        string
+	pos        int
+	ignoreCase bool
+	groups     int            // количество захватывающих групп
+	names      map[string]int // номера именованных групп
+	backrefs   []int          // номера групп в обратных ссылках для проверки
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	return fmt.Errorf("pcre: позиция %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *parser) peek() rune {
+	r, _ := utf8.DecodeRuneInString(p.src[p.pos:])
+	return r
+}
+
+func (p *parser) next() rune {
+	r, size := utf8.DecodeRuneInString(p.src[p.pos:])
+	p.pos += size
+	return r
+}
+
+func (p *parser) consume(prefix string) bool {
+	if strings.HasPrefix(p.src[p.pos:], prefix) {
+		p.pos += len(prefix)
+		return true
+	}
+	return false
+}
+
+// parseAlternation разбирает альтернативы, разделённые "|".
+func (p *parser) parseAlternation() (node, error) {
+	var alts []node
+	for {
+		seq, err := p.parseSequence()
+		if err != nil {
+			return nil, err
+		}
+		alts = append(alts, seq)
+		if !p.consume("|") {
+			break
+		}
+	}
+	if len(alts) == 1 {
+		return alts[0], nil
+	}
+	return &alternation{alts: alts}, nil
+}
+
+// parseSequence разбирает последовательность элементов до "|" или ")".
+func (p *parser) parseSequence() (node, error) {
+	var items []node
+	for !p.eof() && p.peek() != '|' && p.peek() != ')' {
+		// Встроенные флаги (?i) и (?-i) действуют до конца группы
+		if flags, ok := p.inlineFlags(); ok {
+			p.ignoreCase = flags
+			continue
+		}
+
+		atom, err := p.parseAtom()
+		if err != nil {
+			return nil, err
+		}
+		atom, err = p.parseQuantifier(atom)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, atom)
+	}
+	return &sequence{items: items}, nil
+}
+
+// inlineFlags разбирает (?i) или (?-i).
+func (p *parser) inlineFlags() (bool, bool) {
+	switch {
+	case p.consume("(?i)"):
+		return true, true
+	case p.consume("(?-i)"):
+		return false, true
+	}
+	return false, false
+}
+
+// parseQuantifier разбирает квантификатор после атома, если он есть.
+func (p *parser) parseQuantifier(atom node) (node, error) {
+	if p.eof() {
+		return atom, nil
+	}
+
+	start := p.pos
+	lower, upper := 0, 0
+	switch p.peek() {
+	case '*':
+		p.next()
+		lower, upper = 0, -1
+	case '+':
+		p.next()
+		lower, upper = 1, -1
+	case '?':
+		p.next()
+		lower, upper = 0, 1
+	case '{':
+		var ok bool
+		if lower, upper, ok = p.interval(); !ok {
+			// "{" без корректного интервала — обычный символ
+			return atom, nil
+		}
+	default:
+		return atom, nil
+	}
+
+	if !quantifiable(atom) {
+		p.pos = start
+		return nil, p.errorf("квантификатору нечего повторять")
+	}
+
+	q := &repeat{sub: atom, min: lower, max: upper, mode: greedy}
+	switch {
+	case p.consume("?"):
+		q.mode = lazy
+	case p.consume("+"):
+		q.mode = possessive
+	}
+	return q, nil
+}
+
+// interval разбирает {n}, {n,} или {n,m}.
+func (p *parser) interval() (int, int, bool) {
+	end := strings.IndexByte(p.src[p.pos:], '}')
+	if end < 0 {
+		return 0, 0, false
+	}
+	body := p.src[p.pos+1 : p.pos+end]
+
+	lowerText, upperText, hasComma := strings.Cut(body, ",")
+	lower, err := strconv.Atoi(lowerText)
+	if err != nil || lower < 0 {
+		return 0, 0, false
+	}
+	upper := lower
+	if hasComma {
+		upper = -1
+		if upperText != "" {
+			if upper, err = strconv.Atoi(upperText); err != nil || upper < lower {
+				return 0, 0, false
+			}
+		}
+	}
+
+	p.pos += end + 1
+	return lower, upper, true
+}
+
+func quantifiable(n node) bool {
+	switch n.(type) {
+	case *assertion, *lookaround:
+		return false
+	}
+	return true
+}
+
+// parseAtom разбирает один элемент шаблона.
+func (p *parser) parseAtom() (node, error) {
+	r := p.next()
+	switch r {
+	case '(':
+		return p.parseGroup()
+	case '[':
+		return p.parseClass()
+	case '.':
+		return &anyChar{}, nil
+	case '^':
+		return &assertion{kind: lineStart}, nil
+	case '$':
+		return &assertion{kind: lineEnd}, nil
+	case '\\':
+		return p.parseEscape()
+	case '*', '+', '?':
+		p.pos -= utf8.RuneLen(r)
+		return nil, p.errorf("квантификатору нечего повторять")
+	}
+	return &literal{r: r, fold: p.ignoreCase}, nil
+}
+
+// parseGroup разбирает группу, начиная с позиции после "(".
+func (p *parser) parseGroup() (node, error) {
+	kind := capturing
+	name := ""
+	switch {
+	case p.consume("?:"):
+		kind = nonCapturing
+	case p.consume("?>"):
+		kind = atomic
+	case p.consume("?="):
+		kind = lookahead
+	case p.consume("?!"):
+		kind = negativeLookahead
+	case p.consume("?<="):
+		kind = lookbehind
+	case p.consume("?<!"):
+		kind = negativeLookbehind
+	case p.consume("?P<"), p.consume("?<"), p.consume("?'"):
+		end := strings.IndexAny(p.src[p.pos:], ">'")
+		if end <= 0 {
+			return nil, p.errorf("некорректное имя группы")
+		}
+		name = p.src[p.pos : p.pos+end]
+		p.pos += end + 1
+	case p.consume("?i:"), p.consume("?-i:"):
+		return p.parseScopedFlags(strings.HasSuffix(p.src[:p.pos], "?i:"))
+	case strings.HasPrefix(p.src[p.pos:], "?"):
+		return nil, p.errorf("неподдерживаемая конструкция (%s", p.src[p.pos:min(p.pos+2, len(p.src))])
+	}
+
+	index := 0
+	if kind == capturing {
+		p.groups++
+		index = p.groups
+		if name != "" {
+			if p.names == nil {
+				p.names = map[string]int{}
+			}
+			p.names[name] = index
+		}
+	}
+
+	saved := p.ignoreCase
+	sub, err := p.parseAlternation()
+	p.ignoreCase = saved
+	if err != nil {
+		return nil, err
+	}
+	if !p.consume(")") {
+		return nil, p.errorf("незакрытая скобка")
+	}
+
+	switch kind {
+	case capturing:
+		return &group{sub: sub, index: index}, nil
+	case nonCapturing:
+		return sub, nil
+	case atomic:
+		return &atomicGroup{sub: sub}, nil
+	}
+	return &lookaround{
+		sub:    sub,
+		behind: kind == lookbehind || kind == negativeLookbehind,
+		negate: kind == negativeLookahead || kind == negativeLookbehind,
+	}, nil
+}
+
+// parseScopedFlags разбирает (?i:...) и (?-i:...).
+func (p *parser) parseScopedFlags(ignoreCase bool) (node, error) {
+	saved := p.ignoreCase
+	p.ignoreCase = ignoreCase
+	sub, err := p.parseAlternation()
+	p.ignoreCase = saved
+	if err != nil {
+		return nil, err
+	}
+	if !p.consume(")") {
+		return nil, p.errorf("незакрытая скобка")
+	}
+	return sub, nil
+}
+
+// parseEscape разбирает последовательность после обратной косой черты.
+func (p *parser) parseEscape() (node, error) {
+	if p.eof() {
+		return nil, p.errorf("завершающая обратная косая черта")
+	}
+
+	r := p.next()
+	switch r {
+	case 'b':
+		return &assertion{kind: wordBoundary}, nil
+	case 'B':
+		return &assertion{kind: notWordBoundary}, nil
+	case 'A':
+		return &assertion{kind: textStart}, nil
+	case 'z', 'Z':
+		return &assertion{kind: textEnd}, nil
+	case 'k':
+		return p.parseNamedBackref()
+	}
+
+	if r >= '1' && r <= '9' {
+		index := int(r - '0')
+		for !p.eof() && p.peek() >= '0' && p.peek() <= '9' && index*10+int(p.peek()-'0') <= p.groups {
+			index = index*10 + int(p.next()-'0')
+		}
+		p.backrefs = append(p.backrefs, index)
+		return &backref{index: index, fold: p.ignoreCase}, nil
+	}
+
+	if cls, ok := escapeClass(r); ok {
+		cls.fold = p.ignoreCase
+		return cls, nil
+	}
+
+	value, err := p.escapeRune(r)
+	if err != nil {
+		return nil, err
+	}
+	return &literal{r: value, fold: p.ignoreCase}, nil
+}
+
+// parseNamedBackref разбирает \k<name>, \k'name' или \k{name}.
+func (p *parser) parseNamedBackref() (node, error) {
+	if p.eof() || !strings.ContainsRune("<'{", p.peek()) {
+		return nil, p.errorf("ожидалось имя группы после \\k")
+	}
+	p.next()
+	end := strings.IndexAny(p.src[p.pos:], ">'}")
+	if end <= 0 {
+		return nil, p.errorf("некорректное имя группы")
+	}
+	name := p.src[p.pos : p.pos+end]
+	p.pos += end + 1
+
+	index, ok := p.names[name]
+	if !ok {
+		return nil, p.errorf("неизвестная группа %q", name)
+	}
+	return &backref{index: index, fold: p.ignoreCase}, nil
+}
+
+// escapeRune возвращает символ для экранированной последовательности.
+func (p *parser) escapeRune(r rune) (rune, error) {
+	switch r {
+	case 'n':
+		return '\n', nil
+	case 't':
+		return '\t', nil
+	case 'r':
+		return '\r', nil
+	case 'f':
+		return '\f', nil
+	case 'v':
+		return '\v', nil
+	case 'e':
+		return 0x1b, nil
+	case 'a':
+		return 0x07, nil
+	case '0':
+		return 0, nil
+	case 'x':
+		return p.parseHex()
+	}
+	if r < utf8.RuneSelf && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+		return 0, p.errorf("неизвестная последовательность \\%c", r)
+	}
+	return r, nil
+}
+
+// parseHex разбирает \xHH или \x{HHHH}.
+func (p *parser) parseHex() (rune, error) {
+	var digits string
+	if p.consume("{") {
+		end := strings.IndexByte(p.src[p.pos:], '}')
+		if end < 0 {
+			return 0, p.errorf("незакрытая \\x{")
+		}
+		digits = p.src[p.pos : p.pos+end]
+		p.pos += end + 1
+	} else {
+		end := p.pos
+		for end < len(p.src) && end-p.pos < 2 && strings.IndexByte("0123456789abcdefABCDEF", p.src[end]) >= 0 {
+			end++
+		}
+		digits = p.src[p.pos:end]
+		p.pos = end
+	}
+
+	value, err := strconv.ParseUint(digits, 16, 32)
+	if err != nil {
+		return 0, p.errorf("некорректный код символа \\x%s", digits)
+	}
+	return rune(value), nil
+}
+
+// escapeClass возвращает класс для \d, \w, \s и их отрицаний.
+func escapeClass(r rune) (*class, bool) {
+	switch r {
+	case 'd', 'D', 'w', 'W', 's', 'S', 'h', 'H':
+		lower := unicode.ToLower(r)
+		return &class{named: []rune{lower}, negate: r != lower}, true
+	}
+	return nil, false
+}
+
+// parseClass разбирает выражение в квадратных скобках после "[".
+func (p *parser) parseClass() (node, error) {
+	cls := &class{fold: p.ignoreCase}
+	if p.consume("^") {
+		cls.negate = true
+	}
+
+	first := true
+	for {
+		if p.eof() {
+			return nil, p.errorf("незакрытая [")
+		}
+		if p.peek() == ']' && !first {
+			p.next()
+			return cls, nil
+		}
+		first = false
+
+		if p.consume("[:") {
+			end := strings.Index(p.src[p.pos:], ":]")
+			if end < 0 {
+				return nil, p.errorf("незакрытый класс [:")
+			}
+			name := p.src[p.pos : p.pos+end]
+			if !isPosixClass(name) {
+				return nil, p.errorf("неизвестный класс [:%s:]", name)
+			}
+			cls.posix = append(cls.posix, name)
+			p.pos += end + 2
+			continue
+		}
+
+		lo, isClass, err := p.classRune(cls)
+		if err != nil {
+			return nil, err
+		}
+		if isClass {
+			continue
+		}
+
+		hi := lo
+		if strings.HasPrefix(p.src[p.pos:], "-") && !strings.HasPrefix(p.src[p.pos:], "-]") && p.pos+1 < len(p.src) {
+			p.next()
+			if hi, isClass, err = p.classRune(cls); err != nil {
+				return nil, err
+			}
+			if isClass {
+				// [a-\d]: дефис обозначает сам себя
+				cls.ranges = append(cls.ranges, [2]rune{lo, lo}, [2]rune{'-', '-'})
+				continue
+			}
+			if hi < lo {
+				return nil, p.errorf("неверный диапазон %c-%c", lo, hi)
+			}
+		}
+		cls.ranges = append(cls.ranges, [2]rune{lo, hi})
+	}
+}
+
+// classRune читает один символ внутри скобок. Экранированные классы
+// (\d, \w, ...) добавляются в cls; в этом случае isClass == true.
+func (p *parser) classRune(cls *class) (rune, bool, error) {
+	r := p.next()
+	if r != '\\' {
+		return r, false, nil
+	}
+	if p.eof() {
+		return 0, false, p.errorf("незакрытая [")
+	}
+
+	r = p.next()
+	if esc, ok := escapeClass(r); ok {
+		cls.subclasses = append(cls.subclasses, esc)
+		return 0, true, nil
+	}
+	if r == 'b' {
+		return '\b', false, nil
+	}
+	value, err := p.escapeRune(r)
+	return value, false, err
+}
+
+func isPosixClass(name string) bool {
+	switch name {
+	case "alpha", "digit", "alnum", "upper", "lower", "space", "blank",
+		"punct", "xdigit", "word", "cntrl", "print", "graph":
+		return true
+	}
+	return false
+}
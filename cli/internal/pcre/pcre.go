@@ -0,0 +1,200 @@
+// Package pcre реализует подмножество регулярных выражений Perl (PCRE)
+// с помощью сопоставления с возвратами (backtracking).
+//
+// В отличие от RE2 (пакет regexp), движок поддерживает конструкции,
+// требующие возвратов:
+//   - обратные ссылки \1–\99 и \k<name>;
+//   - опережающие и ретроспективные проверки (?=...), (?!...), (?<=...), (?<!...);
+//   - атомарные группы (?>...) и захватывающие квантификаторы *+, ++, ?+, {n,m}+;
+//   - ленивые квантификаторы *?, +?, ??, {n,m}?;
+//   - именованные группы (?<name>...), (?P<name>...), (?'name'...);
+//   - встроенные флаги (?i), (?-i), (?i:...).
+//
+// Также поддерживаются классы \d, \w, \s, \h и их отрицания, классы POSIX
+// внутри скобок ([[:alpha:]]), якоря ^, $, \A, \z, \Z, границы слова \b, \B
+// и коды символов \xHH, \x{HHHH}.
+//
+// Время перебора в худшем случае экспоненциально: шаблоны вида (a+)+$
+// на длинных строках без совпадения перебирают варианты бесконечно долго.
+// Поэтому, как и match_limit в PCRE, число шагов перебора с одной
+// начальной позиции ограничено: при превышении Match и FindAll возвращают ErrMatchLimit.
+// Шаблон применяется к одной строке, поэтому ^ и $ обозначают её начало и конец.
+package pcre
+
+import (
+	"errors"
+	"fmt"
+	"unicode/utf8"
+)
+
+// ErrMatchLimit сообщает, что сопоставление прервано: перебор вариантов
+// превысил лимит возвратов.
+var ErrMatchLimit = errors.New("pcre: превышен лимит возвратов")
+
+// Regexp — скомпилированное выражение. Безопасно для одновременного
+// использования из нескольких горутин.
+type Regexp struct {
+	root   node
+	groups int
+	// anchored показывает, что выражение может совпасть только в начале строки.
+	anchored bool
+}
+
+// Compile компилирует шаблон. При ignoreCase регистр не учитывается,
+// как с флагом (?i) в начале шаблона.
+func Compile(pattern string, ignoreCase bool) (*Regexp, error) {
+	p := &parser{src: pattern, ignoreCase: ignoreCase}
+	root, err := p.parseAlternation()
+	if err != nil {
+		return nil, err
+	}
+	if !p.eof() {
+		return nil, p.errorf("лишняя закрывающая скобка")
+	}
+	for _, index := range p.backrefs {
+		if index > p.groups {
+			return nil, fmt.Errorf("pcre: обратная ссылка на несуществующую группу \\%d", index)
+		}
+	}
+
+	return &Regexp{root: root, groups: p.groups, anchored: startsAnchored(root)}, nil
+}
+
+// MustCompile работает как Compile, но паникует при ошибке.
+func MustCompile(pattern string) *Regexp {
+	re, err := Compile(pattern, false)
+	if err != nil {
+		panic(err)
+	}
+	return re
+}
+
+// Match сообщает, есть ли в s совпадение с выражением. Если перебор
+// превысил лимит возвратов, возвращает ErrMatchLimit.
+func (re *Regexp) Match(s string) (bool, error) {
+	match, err := re.find(s, 0, -1)
+	return match != nil, err
+}
+
+// MatchString сообщает, есть ли в s совпадение с выражением. При
+// превышении лимита возвратов совпадение считается ненайденным.
+func (re *Regexp) MatchString(s string) bool {
+	ok, _ := re.Match(s)
+	return ok
+}
+
+// FindStringIndex возвращает границы самого левого совпадения или nil.
+func (re *Regexp) FindStringIndex(s string) []int {
+	match, _ := re.find(s, 0, -1)
+	if match == nil {
+		return nil
+	}
+	return match[:2]
+}
+
+// FindStringSubmatchIndex возвращает границы самого левого совпадения
+// и всех групп: пары индексов, -1 для неучаствовавших групп.
+func (re *Regexp) FindStringSubmatchIndex(s string) []int {
+	match, _ := re.find(s, 0, -1)
+	return match
+}
+
+// FindAll возвращает до n непересекающихся совпадений (все при n < 0).
+// Пустое совпадение сразу после предыдущего пропускается, как в пакете
+// regexp. Если перебор превысил лимит возвратов, возвращает
+// ErrMatchLimit.
+func (re *Regexp) FindAll(s string, n int) ([][]int, error) {
+	var matches [][]int
+	prevEnd := -1
+	for pos := 0; pos <= len(s) && (n < 0 || len(matches) < n); {
+		match, err := re.find(s, pos, prevEnd)
+		if err != nil {
+			return nil, err
+		}
+		if match == nil {
+			break
+		}
+		matches = append(matches, match[:2])
+		prevEnd = match[1]
+
+		if match[1] > match[0] {
+			pos = match[1]
+			continue
+		}
+		if match[1] == len(s) {
+			break
+		}
+		_, size := utf8.DecodeRuneInString(s[match[1]:])
+		pos = match[1] + size
+	}
+	return matches, nil
+}
+
+// FindAllStringIndex работает как FindAll, но при превышении лимита
+// возвратов возвращает nil.
+func (re *Regexp) FindAllStringIndex(s string, n int) [][]int {
+	matches, _ := re.FindAll(s, n)
+	return matches
+}
+
+// find ищет самое левое совпадение, начиная с позиции from. Пустое
+// совпадение в позиции skipEmpty не принимается.
+func (re *Regexp) find(s string, from, skipEmpty int) (match []int, err error) {
+	m := &machine{input: s, caps: make([]int, 2*(re.groups+1))}
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(errLimit); !ok {
+				panic(r)
+			}
+			match, err = nil, ErrMatchLimit
+		}
+	}()
+
+	for start := from; start <= len(s); {
+		for i := range m.caps {
+			m.caps[i] = -1
+		}
+		m.steps = 0
+
+		end := -1
+		if re.root.match(m, start, func(pos int) bool {
+			if pos == start && start == skipEmpty {
+				return false
+			}
+			end = pos
+			return true
+		}) {
+			m.caps[0], m.caps[1] = start, end
+			return m.caps, nil
+		}
+
+		if re.anchored || start == len(s) {
+			break
+		}
+		_, size := utf8.DecodeRuneInString(s[start:])
+		start += size
+	}
+	return nil, nil
+}
+
+// startsAnchored сообщает, что каждая альтернатива начинается с ^ или \A.
+func startsAnchored(n node) bool {
+	switch n := n.(type) {
+	case *assertion:
+		return n.kind == lineStart || n.kind == textStart
+	case *sequence:
+		return len(n.items) > 0 && startsAnchored(n.items[0])
+	case *group:
+		return startsAnchored(n.sub)
+	case *atomicGroup:
+		return startsAnchored(n.sub)
+	case *alternation:
+		for _, alt := range n.alts {
+			if !startsAnchored(alt) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,174 @@
+package pcre
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFindStringIndex(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		input    string
+		expected []int
+	}{
+		{`abc`, "xxabcxx", []int{2, 5}},
+		{`a.c`, "abc", []int{0, 3}},
+		{`^ab`, "cab", nil},
+		{`b$`, "ab", []int{1, 2}},
+		{`\d+`, "abc 123 def", []int{4, 7}},
+		{`\bfoo\b`, "foobar foo", []int{7, 10}},
+		{`a|ab|abc`, "abc", []int{0, 1}},
+		{`(a|ab)(c|bcd)`, "abcd", []int{0, 4}},
+		{`a*?b`, "aaab", []int{0, 4}},
+		{`a+?`, "aaa", []int{0, 1}},
+		{`x{2,3}`, "xxxxx", []int{0, 3}},
+		{`x{2,3}?`, "xxxxx", []int{0, 2}},
+		{`x{2,}`, "x xx", []int{2, 4}},
+		{`a{,2}`, "a{,2}", []int{0, 5}},
+		{`[[:upper:]][a-z]+`, "hello World", []int{6, 11}},
+		{`[^\d\s]+`, "12 ab 3", []int{3, 5}},
+		{`[\w-]+`, "  foo-bar  ", []int{2, 9}},
+		{`\x41\x{42}`, "zAB", []int{1, 3}},
+		{`привет`, "ну привет", []int{5, 17}},
+		{`П.ИВЕТ`, "привет", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			got := MustCompile(tt.pattern).FindStringIndex(tt.input)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ожидалось %v, получено %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestBacktrackingFeatures(t *testing.T) {
+	tests := []struct {
+		name     string
+		pattern  string
+		input    string
+		expected bool
+	}{
+		{"обратная ссылка", `(\w+) \1`, "hello hello", true},
+		{"обратная ссылка не совпала", `(\w+) \1\b`, "hello help", false},
+		{"именованная группа", `(?<q>['"]).*?\k<q>`, `say "hi'`, false},
+		{"именованная группа совпала", `(?P<q>['"]).*?\k<q>`, `say 'hi'`, true},
+		{"опережающая проверка", `foo(?=bar)`, "foobar", true},
+		{"опережающая проверка не выполнена", `foo(?=bar)`, "foobaz", false},
+		{"отрицательная опережающая", `foo(?!bar)`, "foobar", false},
+		{"ретроспективная", `(?<=\$)\d+`, "cost $42", true},
+		{"ретроспективная не выполнена", `(?<=\$)\d+`, "cost 42", false},
+		{"отрицательная ретроспективная", `(?<!\$)\b\d+`, "$42", false},
+		{"атомарная группа", `(?>a+)ab`, "aaab", false},
+		{"захватывающий квантификатор", `a++b`, "aaab", true},
+		{"захватывающий без возврата", `a++a`, "aaaa", false},
+		{"флаг (?i)", `(?i)hello`, "HeLLo", true},
+		{"флаг (?i:...)", `a(?i:b)c`, "aBc", true},
+		{"флаг (?i:...) ограничен группой", `a(?i:b)c`, "aBC", false},
+		{"обратная ссылка без регистра", `(?i)(ab)\1`, "abAB", true},
+		{"вложенный квантификатор", `(a|b)*c`, "ababab", false},
+		{"пустая итерация", `(a*)*b`, "aaab", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re, err := Compile(tt.pattern, false)
+			if err != nil {
+				t.Fatalf("ошибка компиляции: %v", err)
+			}
+			if got := re.MatchString(tt.input); got != tt.expected {
+				t.Errorf("%q на %q: ожидалось %v, получено %v", tt.pattern, tt.input, tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestIgnoreCase(t *testing.T) {
+	re, err := Compile(`straße [a-c]+`, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !re.MatchString("STRAßE ABC") {
+		t.Error("ожидалось совпадение без учёта регистра")
+	}
+
+	re, err = Compile(`(?-i)abc`, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if re.MatchString("ABC") {
+		t.Error("(?-i) должен включать учёт регистра")
+	}
+}
+
+func TestSubmatches(t *testing.T) {
+	got := MustCompile(`(\w+)@(\w+)?(x)?`).FindStringSubmatchIndex("mail: user@host")
+	expected := []int{6, 15, 6, 10, 11, 15, -1, -1}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("ожидалось %v, получено %v", expected, got)
+	}
+}
+
+func TestFindAllStringIndex(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		input    string
+		expected [][]int
+	}{
+		{`\d+`, "1 22 333", [][]int{{0, 1}, {2, 4}, {5, 8}}},
+		{`a*`, "baaac", [][]int{{0, 0}, {1, 4}, {5, 5}}},
+		{`x`, "abc", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			got := MustCompile(tt.pattern).FindAllStringIndex(tt.input, -1)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("ожидалось %v, получено %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestMatchLimit(t *testing.T) {
+	re := MustCompile(`(a+)+$`)
+	line := strings.Repeat("a", 40) + "!"
+
+	if ok, err := re.Match(line); ok || !errors.Is(err, ErrMatchLimit) {
+		t.Errorf("ожидалась ошибка %v, получено %v, %v", ErrMatchLimit, ok, err)
+	}
+	if _, err := re.FindAll(line, -1); !errors.Is(err, ErrMatchLimit) {
+		t.Errorf("ожидалась ошибка %v, получено %v", ErrMatchLimit, err)
+	}
+	if ok, err := re.Match(strings.Repeat("a", 40)); !ok || err != nil {
+		t.Errorf("ожидалось совпадение без ошибки, получено %v, %v", ok, err)
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	patterns := []string{
+		`(abc`,
+		`abc)`,
+		`[abc`,
+		`*a`,
+		`a**`,
+		`\2(a)`,
+		`(?#comment)`,
+		`\k<missing>`,
+		`[z-a]`,
+		`[[:nope:]]`,
+		`\q`,
+		`abc\`,
+	}
+
+	for _, pattern := range patterns {
+		t.Run(pattern, func(t *testing.T) {
+			if _, err := Compile(pattern, false); err == nil {
+				t.Errorf("ожидалась ошибка для %q", pattern)
+			}
+		})
+	}
+}
@@ -0,0 +1,254 @@
+// Package posixre переводит регулярные выражения POSIX (BRE и ERE)
+// в синтаксис RE2, который понимает пакет regexp.
+//
+// Основные различия, которые учитывает перевод:
+//   - в BRE группы, интервалы и альтернатива записываются как \( \), \{ \}
+//     и \| (расширение GNU), а символы ( ) { } | + ? обозначают сами себя;
+//   - в BRE "^" и "$" — якоря только в начале и в конце выражения или
+//     подвыражения, в остальных местах это обычные символы;
+//   - "*" в начале выражения или подвыражения обозначает сам себя;
+//   - внутри квадратных скобок обратная косая черта — обычный символ;
+//   - \< и \> (границы слова GNU) заменяются на \b.
+//
+// Обратные ссылки (\1–\9) в RE2 невозможны, для них возвращается ошибка.
+package posixre
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrBackReference возвращается для выражений с обратными ссылками.
+var ErrBackReference = errors.New("обратные ссылки не поддерживаются")
+
+// Translate переводит выражение POSIX в синтаксис RE2: базовое (BRE)
+// при extended == false и расширенное (ERE) при extended == true.
+func Translate(pattern string, extended bool) (string, error) {
+	t := &translator{src: pattern, extended: extended}
+	if err := t.run(); err != nil {
+		return "", err
+	}
+	return t.out.String(), nil
+}
+
+type translator struct {
+	src      string
+	pos      int
+	extended bool
+	out      strings.Builder
+	// atStart — текущая позиция в начале выражения или подвыражения,
+	// где "*" обозначает сам себя, а "^" в BRE является якорем.
+	atStart bool
+}
+
+func (t *translator) run() error {
+	t.atStart = true
+	for t.pos < len(t.src) {
+		c := t.src[t.pos]
+		t.pos++
+
+		start := t.atStart
+		t.atStart = false
+
+		switch {
+		case c == '\\':
+			if err := t.escape(start); err != nil {
+				return err
+			}
+		case c == '[':
+			if err := t.bracket(); err != nil {
+				return err
+			}
+		case c == '^':
+			if t.extended || start {
+				t.out.WriteByte('^')
+				// После якоря "*" всё ещё обозначает сам себя
+				t.atStart = true
+			} else {
+				t.out.WriteString(`\^`)
+			}
+		case c == '$':
+			if t.extended || t.atEnd() {
+				t.out.WriteByte('$')
+			} else {
+				t.out.WriteString(`\$`)
+			}
+		case c == '*':
+			t.repeat(start, "*")
+		case t.extended:
+			t.extendedChar(c, start)
+		case strings.IndexByte("+?(){}|", c) >= 0:
+			t.out.WriteByte('\\')
+			t.out.WriteByte(c)
+		default:
+			t.out.WriteByte(c)
+		}
+	}
+	return nil
+}
+
+// extendedChar переводит символ, особый только в ERE.
+func (t *translator) extendedChar(c byte, start bool) {
+	switch c {
+	case '(', '|':
+		t.out.WriteByte(c)
+		t.atStart = true
+	case '+', '?':
+		t.repeat(start, string(c))
+	case '{':
+		// В начале выражения или без корректного интервала "{" — обычный символ
+		if !start {
+			if interval, ok := t.interval("}"); ok {
+				t.out.WriteString("{" + interval + "}")
+				return
+			}
+		}
+		t.out.WriteString(`\{`)
+	default:
+		t.out.WriteByte(c)
+	}
+}
+
+// repeat записывает квантификатор op или, в начале выражения, сам символ.
+func (t *translator) repeat(start bool, op string) {
+	if start {
+		t.out.WriteString(regexp.QuoteMeta(op))
+		t.atStart = true
+		return
+	}
+	t.out.WriteString(op)
+}
+
+// escape переводит последовательность, начинающуюся с обратной косой черты.
+func (t *translator) escape(start bool) error {
+	if t.pos >= len(t.src) {
+		return errors.New("завершающая обратная косая черта")
+	}
+	c := t.src[t.pos]
+	t.pos++
+
+	switch {
+	case c >= '1' && c <= '9':
+		return fmt.Errorf("\\%c: %w", c, ErrBackReference)
+	case c == '<' || c == '>':
+		t.out.WriteString(`\b`)
+	case strings.IndexByte("wWsSbB", c) >= 0:
+		t.out.WriteByte('\\')
+		t.out.WriteByte(c)
+	case t.extended:
+		t.literal(c)
+	case c == '(' || c == '|':
+		t.out.WriteByte(c)
+		t.atStart = true
+	case c == ')':
+		t.out.WriteByte(c)
+	case c == '+' || c == '?':
+		t.repeat(start, string(c))
+	case c == '{':
+		interval, ok := t.interval(`\}`)
+		if !ok {
+			return errors.New("некорректный интервал \\{")
+		}
+		t.out.WriteString("{" + interval + "}")
+	default:
+		t.literal(c)
+	}
+	return nil
+}
+
+// literal записывает байт c как обычный символ.
+func (t *translator) literal(c byte) {
+	if c < 0x80 {
+		t.out.WriteString(regexp.QuoteMeta(string(c)))
+		return
+	}
+	// Часть многобайтового символа копируется как есть
+	t.out.WriteByte(c)
+}
+
+// interval разбирает содержимое интервала "m", "m," или "m,n" до закрывающей
+// последовательности closing. При успехе позиция переходит за неё.
+func (t *translator) interval(closing string) (string, bool) {
+	end := strings.Index(t.src[t.pos:], closing)
+	if end < 0 {
+		return "", false
+	}
+	body := t.src[t.pos : t.pos+end]
+
+	lower, upper, hasComma := strings.Cut(body, ",")
+	if lower == "" || strings.Trim(lower, "0123456789") != "" ||
+		strings.Trim(upper, "0123456789") != "" || !hasComma && upper != "" {
+		return "", false
+	}
+
+	t.pos += end + len(closing)
+	return body, true
+}
+
+// atEnd сообщает, что "$" в BRE стоит в конце выражения или подвыражения.
+func (t *translator) atEnd() bool {
+	rest := t.src[t.pos:]
+	return rest == "" || strings.HasPrefix(rest, `\)`) || strings.HasPrefix(rest, `\|`)
+}
+
+// bracket переводит выражение в квадратных скобках, начиная с позиции
+// сразу после "[".
+func (t *translator) bracket() error {
+	t.out.WriteByte('[')
+	if t.pos < len(t.src) && t.src[t.pos] == '^' {
+		t.out.WriteByte('^')
+		t.pos++
+	}
+	// "]" сразу после открывающей скобки обозначает сам себя
+	if t.pos < len(t.src) && t.src[t.pos] == ']' {
+		t.out.WriteString(`\]`)
+		t.pos++
+	}
+
+	for t.pos < len(t.src) {
+		c := t.src[t.pos]
+		t.pos++
+
+		switch c {
+		case ']':
+			t.out.WriteByte(']')
+			return nil
+		case '[':
+			if t.pos < len(t.src) && strings.IndexByte(":=.", t.src[t.pos]) >= 0 {
+				if err := t.bracketClass(t.src[t.pos]); err != nil {
+					return err
+				}
+				continue
+			}
+			t.out.WriteString(`\[`)
+		case '\\':
+			t.out.WriteString(`\\`)
+		default:
+			t.out.WriteByte(c)
+		}
+	}
+	return errors.New("незакрытая [")
+}
+
+// bracketClass переводит [:class:], [=c=] или [.c.] внутри скобок.
+// Позиция указывает на символ kind сразу после "[".
+func (t *translator) bracketClass(kind byte) error {
+	closing := string(kind) + "]"
+	end := strings.Index(t.src[t.pos+1:], closing)
+	if end < 0 {
+		return fmt.Errorf("незакрытое [%c", kind)
+	}
+	body := t.src[t.pos+1 : t.pos+1+end]
+	t.pos += 1 + end + len(closing)
+
+	if kind == ':' {
+		t.out.WriteString("[:" + body + ":]")
+		return nil
+	}
+	// Классы эквивалентности и сортирующие элементы поддерживаются
+	// только для одного символа и обозначают сам этот символ
+	t.out.WriteString(regexp.QuoteMeta(body))
+	return nil
+}
@@ -0,0 +1,111 @@
+package posixre
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+)
+
+func TestTranslateBasic(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		expected string
+	}{
+		{`a.b`, `a.b`},
+		{`\(ab\)*`, `(ab)*`},
+		{`a\{2,3\}`, `a{2,3}`},
+		{`a\{2\}`, `a{2}`},
+		{`a+b?`, `a\+b\?`},
+		{`a\+b\?`, `a+b?`},
+		{`(x)|{y}`, `\(x\)\|\{y\}`},
+		{`a\|b`, `a|b`},
+		{`*a`, `\*a`},
+		{`^*a`, `^\*a`},
+		{`\(*a\)`, `(\*a)`},
+		{`a^b$c`, `a\^b\$c`},
+		{`^ab$`, `^ab$`},
+		{`\(^a$\)`, `(^a$)`},
+		{`[\d]`, `[\\d]`},
+		{`[]a]`, `[\]a]`},
+		{`[^]a]`, `[^\]a]`},
+		{`[[:alpha:]_]`, `[[:alpha:]_]`},
+		{`[[=e=]x]`, `[ex]`},
+		{`[a[]`, `[a\[]`},
+		{`\<word\>`, `\bword\b`},
+		{`\.\*`, `\.\*`},
+		{`\w\s`, `\w\s`},
+		{`привет.мир`, `привет.мир`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			got, err := Translate(tt.pattern, false)
+			if err != nil {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("ожидалось %q, получено %q", tt.expected, got)
+			}
+			if _, err := regexp.Compile(got); err != nil {
+				t.Errorf("результат не компилируется: %v", err)
+			}
+		})
+	}
+}
+
+func TestTranslateExtended(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		expected string
+	}{
+		{`(ab)+|c?`, `(ab)+|c?`},
+		{`a{2,}`, `a{2,}`},
+		{`a{x}`, `a\{x}`},
+		{`{1}a`, `\{1}a`},
+		{`*a`, `\*a`},
+		{`(+a|?b)`, `(\+a|\?b)`},
+		{`a^b$`, `a^b$`},
+		{`\(x\)`, `\(x\)`},
+		{`[\]`, `[\\]`},
+		{`\<a`, `\ba`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			got, err := Translate(tt.pattern, true)
+			if err != nil {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("ожидалось %q, получено %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestTranslateErrors(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		extended bool
+	}{
+		{`\(a\)\1`, false},
+		{`(a)\1`, true},
+		{`abc\`, false},
+		{`[abc`, false},
+		{`[[:alpha:]`, true},
+		{`a\{x\}`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			if _, err := Translate(tt.pattern, tt.extended); err == nil {
+				t.Errorf("ожидалась ошибка для %q", tt.pattern)
+			}
+		})
+	}
+
+	_, err := Translate(`\(a\)\1`, false)
+	if !errors.Is(err, ErrBackReference) {
+		t.Errorf("ожидалась ErrBackReference, получено %v", err)
+	}
+}
@@ -0,0 +1,110 @@
+package preprocessor
+
+import (
+	"strings"
+
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/checkutils"
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/lexer"
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
+)
+
+// AliasExpansionStep выполняет раскрытие псевдонимов команд.
+// Псевдоним подставляется вместо первого слова каждой простой команды,
+// если это слово записано без кавычек. Раскрытие рекурсивное: первое слово
+// значения псевдонима тоже проверяется, но псевдоним, который уже
+// раскрывается, повторно не раскрывается (защита от циклов, например
+// alias ls='ls -F'). Если значение псевдонима заканчивается пробелом,
+// следующее слово команды также проверяется на псевдоним.
+//
+// Шаг должен выполняться первым, до всех остальных раскрытий.
+type AliasExpansionStep struct {
+	Aliases *session.Aliases
+}
+
+// Bind реализует Binder.
+func (s *AliasExpansionStep) Bind(state Session) Step {
+	return &AliasExpansionStep{Aliases: state.Aliases}
+}
+
+// Apply реализует шаг раскрытия псевдонимов.
+func (s *AliasExpansionStep) Apply(input PreprocessedInput) (PreprocessedInput, error) {
+	value, err := s.expand(input.Value, map[string]bool{})
+	if err != nil {
+		return PreprocessedInput{}, err
+	}
+
+	return PreprocessedInput{
+		Original: input.Original,
+		Value:    value,
+	}, nil
+}
+
+// expand раскрывает псевдонимы в строке. active содержит псевдонимы,
+// раскрываемые в данный момент выше по рекурсии.
+func (s *AliasExpansionStep) expand(value string, active map[string]bool) (string, error) {
+	tokens, err := lexer.Split(value)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	last := 0
+	commandStart := true
+	inConditional := false
+
+	for _, tok := range tokens {
+		// Внутри [[ ... ]] нет команд, поэтому псевдонимы не раскрываются.
+		if inConditional {
+			inConditional = !(tok.Kind == lexer.Word && tok.Value == "]]")
+			continue
+		}
+		if tok.Kind == lexer.Operator {
+			commandStart = true
+			continue
+		}
+		if !commandStart {
+			continue
+		}
+
+		// Присваивания перед именем команды и открывающая скобка группы
+		// пропускаются, псевдоним ищется в следующем слове.
+		if tok.Value == "{" || checkutils.IsEnvAssignmentCommand(lexer.Unquote(tok.Value)) {
+			continue
+		}
+		commandStart = false
+
+		if tok.Value == "[[" {
+			inConditional = true
+			continue
+		}
+		if lexer.IsQuoted(tok.Value) || active[tok.Value] {
+			continue
+		}
+		aliasValue, ok := s.Aliases.Get(tok.Value)
+		if !ok {
+			continue
+		}
+
+		nested := make(map[string]bool, len(active)+1)
+		for name := range active {
+			nested[name] = true
+		}
+		nested[tok.Value] = true
+
+		expanded, err := s.expand(aliasValue, nested)
+		if err != nil {
+			return "", err
+		}
+
+		b.WriteString(value[last:tok.Start])
+		b.WriteString(expanded)
+		last = tok.End
+
+		if strings.HasSuffix(expanded, " ") || strings.HasSuffix(expanded, "\t") {
+			commandStart = true
+		}
+	}
+	b.WriteString(value[last:])
+
+	return b.String(), nil
+}
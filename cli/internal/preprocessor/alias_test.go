@@ -0,0 +1,52 @@
+package preprocessor
+
+import (
+	"testing"
+
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
+)
+
+func TestAliasExpansionStep(t *testing.T) {
+	aliases := session.NewAliases()
+	aliases.Set("ll", "ls -la")
+	aliases.Set("gs", "git status")
+	aliases.Set("ls", "ls --color")
+	aliases.Set("l", "ll")
+	aliases.Set("a", "b")
+	aliases.Set("b", "a")
+	aliases.Set("sudo", "sudo ")
+	aliases.Set("count", "wc -l | cat")
+
+	step := &AliasExpansionStep{Aliases: aliases}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"простой псевдоним", "gs --short", "git status --short"},
+		{"рекурсивное раскрытие", "l /tmp", "ls --color -la /tmp"},
+		{"защита от самоссылки", "ls", "ls --color"},
+		{"защита от цикла", "a", "a"},
+		{"только первое слово", "echo ll", "echo ll"},
+		{"каждая команда пайпа", "cat f | count", "cat f | wc -l | cat"},
+		{"пробел в конце значения", "sudo ll", "sudo  ls --color -la"},
+		{"слово в кавычках", "'ll' x", "'ll' x"},
+		{"экранированное слово", `\ll`, `\ll`},
+		{"после присваивания", "X=1 ll", "X=1 ls --color -la"},
+		{"список и группа", "gs; { ll; } && (ll)", "git status; { ls --color -la; } && (ls --color -la)"},
+		{"внутри условного выражения", "[[ -n ll && gs ]] && gs", "[[ -n ll && gs ]] && git status"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := step.Apply(PreprocessedInput{Value: tt.input})
+			if err != nil {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+			if result.Value != tt.expected {
+				t.Fatalf("ожидалось %q, получено %q", tt.expected, result.Value)
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package preprocessor
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// BraceExpansionStep выполняет раскрытие фигурных скобок:
+//   - списки: file{,.bak} → file file.bak
+//   - вложенные скобки: src/{api,web/{v1,v2}} → src/api src/web/v1 src/web/v2
+//   - числовые последовательности: {1..5}, {01..20..2}
+//   - символьные последовательности: {a..e}, {z..a..2}
+//
+// Скобки в кавычках, экранированные скобки и подстановки ${...}
+// не раскрываются. Шаг должен выполняться до подстановки переменных.
+type BraceExpansionStep struct{}
+
+var (
+	numericSequence = regexp.MustCompile(`^([-+]?\d+)\.\.([-+]?\d+)(?:\.\.([-+]?\d+))?$`)
+	letterSequence  = regexp.MustCompile(`^([a-zA-Z])\.\.([a-zA-Z])(?:\.\.([-+]?\d+))?$`)
+)
+
+// Apply реализует шаг раскрытия фигурных скобок.
+func (s *BraceExpansionStep) Apply(input PreprocessedInput) (PreprocessedInput, error) {
+	value, err := rewriteWords(input.Value, func(word string, kind wordKind) (string, bool, error) {
+		// Как и в bash, присваивания перед именем команды и операнды [[ ... ]]
+		// не раскрываются.
+		if kind != commandWord {
+			return "", false, nil
+		}
+
+		words := expandBraces(word)
+		if len(words) == 1 && words[0] == word {
+			return "", false, nil
+		}
+		return strings.Join(words, " "), true, nil
+	})
+	if err != nil {
+		return PreprocessedInput{}, err
+	}
+
+	return PreprocessedInput{
+		Original: input.Original,
+		Value:    value,
+	}, nil
+}
+
+// expandBraces раскрывает первое корректное выражение в фигурных скобках
+// и рекурсивно — всё, что получилось. Пустые слова отбрасываются.
+func expandBraces(word string) []string {
+	for i := 0; i < len(word); i++ {
+		switch word[i] {
+		case '\\':
+			i++
+		case '\'', '"':
+			i = skipQuoted(word, i)
+		case '$':
+			if i+1 < len(word) && word[i+1] == '{' {
+				i = skipBraced(word, i+1)
+			}
+		case '{':
+			end, alternatives, ok := parseBrace(word, i)
+			if !ok {
+				continue
+			}
+
+			prefix, suffixes := word[:i], expandBraces(word[end+1:])
+			var result []string
+			for _, alt := range alternatives {
+				for _, expanded := range expandBraces(alt) {
+					for _, suffix := range suffixes {
+						if w := prefix + expanded + suffix; w != "" {
+							result = append(result, w)
+						}
+					}
+				}
+			}
+			return result
+		}
+	}
+	return []string{word}
+}
+
+// parseBrace разбирает выражение в фигурных скобках, начинающееся с позиции start.
+// Возвращает позицию закрывающей скобки и список альтернатив. Выражение
+// корректно, если содержит запятую верхнего уровня или последовательность x..y[..шаг].
+func parseBrace(word string, start int) (int, []string, bool) {
+	depth := 0
+	commas := []int{start}
+
+	for i := start + 1; i < len(word); i++ {
+		switch word[i] {
+		case '\\':
+			i++
+		case '\'', '"':
+			i = skipQuoted(word, i)
+		case '$':
+			if i+1 < len(word) && word[i+1] == '{' {
+				i = skipBraced(word, i+1)
+			}
+		case '{':
+			depth++
+		case ',':
+			if depth == 0 {
+				commas = append(commas, i)
+			}
+		case '}':
+			if depth > 0 {
+				depth--
+				continue
+			}
+
+			if len(commas) > 1 {
+				var alternatives []string
+				for k, pos := range commas {
+					next := i
+					if k+1 < len(commas) {
+						next = commas[k+1]
+					}
+					alternatives = append(alternatives, word[pos+1:next])
+				}
+				return i, alternatives, true
+			}
+
+			if seq, ok := expandSequence(word[start+1 : i]); ok {
+				return i, seq, true
+			}
+			return 0, nil, false
+		}
+	}
+	return 0, nil, false
+}
+
+// expandSequence раскрывает последовательность вида x..y или x..y..шаг.
+func expandSequence(body string) ([]string, bool) {
+	if m := numericSequence.FindStringSubmatch(body); m != nil {
+		from, errFrom := strconv.Atoi(m[1])
+		to, errTo := strconv.Atoi(m[2])
+		if errFrom != nil || errTo != nil {
+			return nil, false
+		}
+
+		width := 0
+		if hasLeadingZero(m[1]) || hasLeadingZero(m[2]) {
+			width = max(len(m[1]), len(m[2]))
+		}
+
+		var result []string
+		for _, n := range sequence(from, to, sequenceStep(m[3])) {
+			result = append(result, padNumber(n, width))
+		}
+		return result, true
+	}
+
+	if m := letterSequence.FindStringSubmatch(body); m != nil {
+		var result []string
+		for _, n := range sequence(int(m[1][0]), int(m[2][0]), sequenceStep(m[3])) {
+			result = append(result, string(rune(n)))
+		}
+		return result, true
+	}
+
+	return nil, false
+}
+
+// sequence возвращает числа от from до to включительно с шагом step
+// (знак шага игнорируется, направление определяется границами).
+func sequence(from, to, step int) []int {
+	var result []int
+	if from <= to {
+		for n := from; n <= to; n += step {
+			result = append(result, n)
+		}
+	} else {
+		for n := from; n >= to; n -= step {
+			result = append(result, n)
+		}
+	}
+	return result
+}
+
+func sequenceStep(s string) int {
+	step, err := strconv.Atoi(s)
+	if err != nil || step == 0 {
+		return 1
+	}
+	if step < 0 {
+		return -step
+	}
+	return step
+}
+
+func hasLeadingZero(s string) bool {
+	s = strings.TrimLeft(s, "+-")
+	return len(s) > 1 && s[0] == '0'
+}
+
+// padNumber дополняет число нулями до ширины width (с учётом знака).
+func padNumber(n, width int) string {
+	if n < 0 {
+		return "-" + padNumber(-n, width-1)
+	}
+	s := strconv.Itoa(n)
+	if len(s) < width {
+		s = strings.Repeat("0", width-len(s)) + s
+	}
+	return s
+}
+
+// skipQuoted возвращает позицию закрывающей кавычки для кавычки в позиции i.
+func skipQuoted(word string, i int) int {
+	quote := word[i]
+	for j := i + 1; j < len(word); j++ {
+		if quote == '"' && word[j] == '\\' {
+			j++
+			continue
+		}
+		if word[j] == quote {
+			return j
+		}
+	}
+	return len(word)
+}
+
+// skipBraced возвращает позицию "}", закрывающей "{" в позиции i.
+func skipBraced(word string, i int) int {
+	if end := strings.IndexByte(word[i:], '}'); end >= 0 {
+		return i + end
+	}
+	return len(word)
+}
@@ -0,0 +1,107 @@
+package preprocessor
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExpandBraces(t *testing.T) {
+	tests := []struct {
+		word     string
+		expected string
+	}{
+		{"file{,.bak}", "file file.bak"},
+		{"src/{api,web}/{v1,v2}", "src/api/v1 src/api/v2 src/web/v1 src/web/v2"},
+		{"a{b,c{d,e}}f", "abf acdf acef"},
+		{"{1..5}", "1 2 3 4 5"},
+		{"{5..1..2}", "5 3 1"},
+		{"{01..20..4}", "01 05 09 13 17"},
+		{"{-2..2}", "-2 -1 0 1 2"},
+		{"{a..e}", "a b c d e"},
+		{"{z..t..2}", "z x v t"},
+		{"x{a..c}y", "xay xby xcy"},
+		{"{a}", "{a}"},
+		{"{}", "{}"},
+		{"{a,b", "{a,b"},
+		{"{a..1}", "{a..1}"},
+		{"{a{b,c}}", "{ab} {ac}"},
+		{"'{a,b}'", "'{a,b}'"},
+		{`\{a,b\}`, `\{a,b\}`},
+		{`"x"{1,2}`, `"x"1 "x"2`},
+		{"${HOME}{1,2}", "${HOME}1 ${HOME}2"},
+		{"{,}", ""},
+	}
+
+	for _, tt := range tests {
+		if got := strings.Join(expandBraces(tt.word), " "); got != tt.expected {
+			t.Errorf("expandBraces(%q): ожидалось %q, получено %q", tt.word, tt.expected, got)
+		}
+	}
+}
+
+func TestBraceExpansionStep(t *testing.T) {
+	step := &BraceExpansionStep{}
+
+	result, err := step.Apply(PreprocessedInput{Value: `mkdir -p src/{api,web}/{v1,v2} | echo "{x,y}" X={1,2}`})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+
+	expected := `mkdir -p src/api/v1 src/api/v2 src/web/v1 src/web/v2 | echo "{x,y}" X=1 X=2`
+	if result.Value != expected {
+		t.Fatalf("ожидалось %q, получено %q", expected, result.Value)
+	}
+}
+
+func TestBraceExpansionStep_SkipsAssignments(t *testing.T) {
+	step := &BraceExpansionStep{}
+
+	result, err := step.Apply(PreprocessedInput{Value: "X={1,2}"})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if result.Value != "X={1,2}" {
+		t.Fatalf("присваивание не должно раскрываться: %q", result.Value)
+	}
+}
+
+func TestBraceExpansionStep_ExpandsArrayElements(t *testing.T) {
+	step := &BraceExpansionStep{}
+
+	result, err := step.Apply(PreprocessedInput{Value: "a=(x{1,2} '{y,z}') && a+=({3..4})"})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+
+	expected := "a=(x1 x2 '{y,z}') && a+=(3 4)"
+	if result.Value != expected {
+		t.Fatalf("ожидалось %q, получено %q", expected, result.Value)
+	}
+}
+
+func TestBraceExpansionStep_SkipsConditionalOperands(t *testing.T) {
+	step := &BraceExpansionStep{}
+
+	result, err := step.Apply(PreprocessedInput{Value: "[[ $x == {a,b} ]] || echo {a,b}"})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+
+	expected := "[[ $x == {a,b} ]] || echo a b"
+	if result.Value != expected {
+		t.Fatalf("ожидалось %q, получено %q", expected, result.Value)
+	}
+}
+
+func TestExpandSequence_Invalid(t *testing.T) {
+	for _, body := range []string{"1..", "a..bb", "1..2..x", "1.2"} {
+		if seq, ok := expandSequence(body); ok {
+			t.Errorf("expandSequence(%q): ожидалась некорректная последовательность, получено %q", body, seq)
+		}
+	}
+
+	if seq, _ := expandSequence("3..3"); !reflect.DeepEqual(seq, []string{"3"}) {
+		t.Errorf("последовательность из одного элемента: %q", seq)
+	}
+}
@@ -0,0 +1,106 @@
+package preprocessor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/glob"
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/lexer"
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
+)
+
+// GlobStep выполняет раскрытие шаблонов имён файлов (*, ?, [...]) в
+// неэкранированных частях слов. Найденные пути подставляются в строку
+// отсортированными и заключёнными в кавычки, чтобы последующий разбор
+// не разбил их на части.
+//
+// Поведение для шаблонов без совпадений определяется опциями сеанса:
+//   - по умолчанию слово остаётся без изменений;
+//   - nullglob — слово удаляется;
+//   - failglob — возвращается ошибка "no match".
+//
+// При опции noglob из SetOptions (set -f) шаблоны не раскрываются.
+type GlobStep struct {
+	Options *session.Options
+	// SetOptions — опции команды set; учитывается noglob.
+	SetOptions *session.Options
+	// Dir возвращает рабочий каталог сеанса. Он читается при каждом
+	// раскрытии, поэтому учитывает cd. Если Dir не задан или возвращает
+	// пустую строку, используется текущий каталог процесса.
+	Dir func() string
+}
+
+// Bind реализует Binder.
+func (s *GlobStep) Bind(state Session) Step {
+	return &GlobStep{Options: state.Options, SetOptions: state.SetOptions, Dir: state.Dir}
+}
+
+// Apply реализует шаг раскрытия шаблонов имён файлов.
+func (s *GlobStep) Apply(input PreprocessedInput) (PreprocessedInput, error) {
+	if s.SetOptions.Enabled(session.NoGlob) {
+		return input, nil
+	}
+
+	opts := glob.Options{
+		DotGlob:  s.Options.Enabled(session.DotGlob),
+		GlobStar: s.Options.Enabled(session.GlobStar),
+		ExtGlob:  s.Options.Enabled(session.ExtGlob),
+	}
+
+	value, err := rewriteWords(input.Value, func(word string, kind wordKind) (string, bool, error) {
+		// Присваивания перед именем команды и операнды [[ ... ]] не раскрываются.
+		if kind != commandWord {
+			return "", false, nil
+		}
+
+		pattern := globPattern(word)
+		if !glob.HasMeta(pattern, opts) {
+			return "", false, nil
+		}
+
+		matches := glob.Expand(pattern, s.dir(), opts)
+		switch {
+		case len(matches) > 0:
+			quoted := make([]string, len(matches))
+			for i, m := range matches {
+				quoted[i] = lexer.Quote(m)
+			}
+			return strings.Join(quoted, " "), true, nil
+		case s.Options.Enabled(session.FailGlob):
+			return "", false, fmt.Errorf("no match: %s", lexer.Unquote(word))
+		case s.Options.Enabled(session.NullGlob):
+			return "", true, nil
+		}
+		return "", false, nil
+	})
+	if err != nil {
+		return PreprocessedInput{}, err
+	}
+
+	return PreprocessedInput{
+		Original: input.Original,
+		Value:    value,
+	}, nil
+}
+
+// dir возвращает каталог, в котором раскрываются шаблоны.
+func (s *GlobStep) dir() string {
+	if s.Dir == nil {
+		return ""
+	}
+	return s.Dir()
+}
+
+// globPattern строит шаблон из слова: части в кавычках экранируются,
+// чтобы их метасимволы трактовались буквально.
+func globPattern(word string) string {
+	var b strings.Builder
+	for _, seg := range lexer.Segments(word) {
+		if seg.Quoted {
+			b.WriteString(glob.Escape(seg.Text))
+		} else {
+			b.WriteString(seg.Text)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,146 @@
+package preprocessor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
+)
+
+func newGlobTestDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	for _, name := range []string{"b.log", "a.log", "with space.log", "notes.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// staticDir возвращает источник каталога для GlobStep.Dir.
+func staticDir(dir string) func() string {
+	return func() string { return dir }
+}
+
+func TestGlobStep_ExpandsUnquotedWords(t *testing.T) {
+	dir := newGlobTestDir(t)
+	step := &GlobStep{Options: session.NewOptions(), Dir: staticDir(dir)}
+
+	result, err := step.Apply(PreprocessedInput{Value: "cat *.log | wc -l"})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+
+	expected := "cat a.log b.log 'with space.log' | wc -l"
+	if result.Value != expected {
+		t.Fatalf("ожидалось %q, получено %q", expected, result.Value)
+	}
+}
+
+func TestGlobStep_QuotedWordsAreLiteral(t *testing.T) {
+	dir := newGlobTestDir(t)
+	step := &GlobStep{Options: session.NewOptions(), Dir: staticDir(dir)}
+
+	input := `echo '*.log' "*.txt" \*.log`
+	result, err := step.Apply(PreprocessedInput{Value: input})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+
+	if result.Value != input {
+		t.Fatalf("слова в кавычках не должны раскрываться: %q", result.Value)
+	}
+}
+
+func TestGlobStep_NoMatch(t *testing.T) {
+	dir := newGlobTestDir(t)
+
+	tests := []struct {
+		name     string
+		option   string
+		expected string
+		wantErr  bool
+	}{
+		{name: "по умолчанию", expected: "ls *.none"},
+		{name: "nullglob", option: session.NullGlob, expected: "ls "},
+		{name: "failglob", option: session.FailGlob, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := session.NewOptions()
+			if tt.option != "" {
+				_ = opts.Set(tt.option, true)
+			}
+			step := &GlobStep{Options: opts, Dir: staticDir(dir)}
+
+			result, err := step.Apply(PreprocessedInput{Value: "ls *.none"})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ошибка: %v, ожидалась ошибка: %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && result.Value != tt.expected {
+				t.Fatalf("ожидалось %q, получено %q", tt.expected, result.Value)
+			}
+		})
+	}
+}
+
+func TestGlobStep_SkipsAssignments(t *testing.T) {
+	dir := newGlobTestDir(t)
+	step := &GlobStep{Options: session.NewOptions(), Dir: staticDir(dir)}
+
+	result, err := step.Apply(PreprocessedInput{Value: "FILES=*.log"})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if result.Value != "FILES=*.log" {
+		t.Fatalf("присваивание не должно раскрываться: %q", result.Value)
+	}
+}
+
+func TestGlobStep_SkipsConditionalOperands(t *testing.T) {
+	dir := newGlobTestDir(t)
+	step := &GlobStep{Options: session.NewOptions(), Dir: staticDir(dir)}
+
+	result, err := step.Apply(PreprocessedInput{Value: "[[ $f == *.log ]] && cat *.txt"})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+
+	expected := "[[ $f == *.log ]] && cat notes.txt"
+	if result.Value != expected {
+		t.Fatalf("ожидалось %q, получено %q", expected, result.Value)
+	}
+}
+
+func TestGlobStep_NoGlob(t *testing.T) {
+	dir := newGlobTestDir(t)
+	setOptions := session.NewSetOptions()
+	_ = setOptions.Set(session.NoGlob, true)
+	step := &GlobStep{Options: session.NewOptions(), SetOptions: setOptions, Dir: staticDir(dir)}
+
+	result, err := step.Apply(PreprocessedInput{Value: "echo *.log"})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if result.Value != "echo *.log" {
+		t.Fatalf("при noglob шаблон не должен раскрываться, получено %q", result.Value)
+	}
+}
+
+func TestGlobStep_ReadsDirOnEachApply(t *testing.T) {
+	dir := newGlobTestDir(t)
+	current := t.TempDir()
+	step := &GlobStep{Options: session.NewOptions(), Dir: func() string { return current }}
+
+	if result, _ := step.Apply(PreprocessedInput{Value: "echo *.txt"}); result.Value != "echo *.txt" {
+		t.Fatalf("в пустом каталоге шаблон не должен раскрываться: %q", result.Value)
+	}
+
+	current = dir
+	if result, _ := step.Apply(PreprocessedInput{Value: "echo *.txt"}); result.Value != "echo notes.txt" {
+		t.Fatalf("ожидалось %q, получено %q", "echo notes.txt", result.Value)
+	}
+}
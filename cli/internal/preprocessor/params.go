@@ -0,0 +1,263 @@
+package preprocessor
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
+)
+
+// expansion — результат подстановки одного "$...".
+type expansion struct {
+	length int      // длина распознанного текста; 0 — "$" остаётся как есть
+	text   string   // экранированный текст для вставки
+	words  bool     // ${a[@]}: каждый элемент — отдельное слово
+	values []string // значения ${a[@]} до экранирования
+}
+
+// expand распознаёт подстановку в начале s: $NAME, $- или ${...}.
+// Неизвестная переменная без индекса остаётся в строке как есть,
+// а при опции nounset считается ошибкой.
+func (s *EnvSubstitutionStep) expand(str string, inDouble bool) (expansion, error) {
+	if strings.HasPrefix(str, "$-") {
+		return expansion{length: 2, text: s.SetOptions.Flags()}, nil
+	}
+
+	if strings.HasPrefix(str, "${") {
+		end := strings.IndexByte(str, '}')
+		if end < 0 {
+			return expansion{}, nil
+		}
+		exp, ok, err := s.expandParameter(str[2:end], inDouble)
+		if err != nil || !ok {
+			return expansion{}, err
+		}
+		exp.length = end + 1
+		return exp, nil
+	}
+
+	m := defaultPattern.FindStringSubmatch(str)
+	if m == nil {
+		return expansion{}, nil
+	}
+	value, ok := s.lookup(m[1])
+	if !ok {
+		return expansion{}, s.unbound(m[1])
+	}
+	return expansion{length: len(m[0]), text: escapeValue(value, inDouble)}, nil
+}
+
+// expandParameter раскрывает содержимое ${...}:
+//
+//	${NAME}                 значение переменной или элемент 0 массива
+//	${-}                    флаги включённых опций set
+//	${NAME[SUB]}            элемент массива
+//	${NAME[@]}, ${NAME[*]}  все элементы
+//	${NAME[@]:OFF[:LEN]}    элементы, начиная с индекса OFF
+//	${#NAME}, ${#NAME[SUB]} длина значения в символах
+//	${#NAME[@]}             число элементов
+//	${!NAME[@]}             индексы или ключи
+//
+// ok ложно, если запись не распознана или переменная без индекса не
+// задана: тогда текст остаётся без изменений.
+func (s *EnvSubstitutionStep) expandParameter(body string, inDouble bool) (expansion, bool, error) {
+	if body == "-" {
+		return expansion{text: s.SetOptions.Flags()}, true, nil
+	}
+
+	var op byte
+	if len(body) > 1 && (body[0] == '#' || body[0] == '!') {
+		op, body = body[0], body[1:]
+	}
+
+	ref, slice, _ := strings.Cut(body, ":")
+	name, sub, indexed := session.SplitSubscript(ref)
+	if !indexed {
+		name = ref
+	}
+	all := indexed && (sub == "@" || sub == "*")
+	switch {
+	case !session.IsName(name),
+		body != ref && (!all || op != 0),
+		op == '!' && !all:
+		return expansion{}, false, nil
+	}
+
+	if !indexed {
+		value, ok := s.lookup(name)
+		if !ok {
+			return expansion{}, false, s.unbound(name)
+		}
+		if op == '#' {
+			value = strconv.Itoa(utf8.RuneCountInString(value))
+		}
+		return expansion{text: escapeValue(value, inDouble)}, true, nil
+	}
+
+	array := s.array(name)
+	if !all {
+		key, err := array.Key(s.expandSubscript(sub), func(name string) string {
+			value, _ := s.lookup(name)
+			return value
+		})
+		if err != nil {
+			return expansion{}, false, fmt.Errorf("%s: %w", ref, err)
+		}
+		value, ok := array.Get(key)
+		if !ok && op == 0 {
+			if err := s.unbound(ref); err != nil {
+				return expansion{}, false, err
+			}
+		}
+		if op == '#' {
+			value = strconv.Itoa(utf8.RuneCountInString(value))
+		}
+		return expansion{text: escapeValue(value, inDouble)}, true, nil
+	}
+
+	var values []string
+	switch op {
+	case '#':
+		if _, ok := s.Vars.Lookup(name); !ok {
+			if err := s.unbound(name); err != nil {
+				return expansion{}, false, err
+			}
+		}
+		return expansion{text: strconv.Itoa(array.Len())}, true, nil
+	case '!':
+		values = array.Keys()
+	default:
+		var err error
+		if values, err = sliceArray(array, slice, body != ref); err != nil {
+			return expansion{}, false, fmt.Errorf("%s: %w", body, err)
+		}
+	}
+	return s.join(values, sub == "@", inDouble), true, nil
+}
+
+// join подставляет значения ${a[@]} или ${a[*]}. В двойных кавычках
+// "${a[@]}" даёт по слову на элемент, а "${a[*]}" — одно слово, в котором
+// элементы разделены первым символом IFS.
+func (s *EnvSubstitutionStep) join(values []string, each, inDouble bool) expansion {
+	escaped := make([]string, len(values))
+	for i, value := range values {
+		escaped[i] = escapeValue(value, inDouble)
+	}
+
+	sep := " "
+	switch {
+	case inDouble && each:
+		sep = `" "`
+	case inDouble:
+		if ifs, ok := s.Vars.Get("IFS"); ok {
+			sep = ""
+			if r, size := utf8.DecodeRuneInString(ifs); size > 0 {
+				sep = escapeValue(string(r), true)
+			}
+		}
+	}
+	return expansion{text: strings.Join(escaped, sep), words: each, values: values}
+}
+
+// sliceArray возвращает элементы массива для ${a[@]:OFF:LEN}. Для
+// индексированного массива OFF — наименьший индекс (отрицательный
+// отсчитывается от конца), для ассоциативного — номер элемента; LEN —
+// число элементов.
+func sliceArray(array *session.Array, slice string, sliced bool) ([]string, error) {
+	values := array.Values()
+	if !sliced || len(values) == 0 {
+		return values, nil
+	}
+
+	offText, lengthText, limited := strings.Cut(slice, ":")
+	offset, err := strconv.Atoi(strings.TrimSpace(offText))
+	if err != nil {
+		return nil, session.ErrBadSubscript
+	}
+	length := len(values)
+	if limited {
+		if length, err = strconv.Atoi(strings.TrimSpace(lengthText)); err != nil {
+			return nil, session.ErrBadSubscript
+		}
+		if length < 0 {
+			return nil, fmt.Errorf("%d: длина подстроки меньше нуля", length)
+		}
+	}
+
+	keys := array.Keys()
+	start := offset
+	switch {
+	case array.IsAssoc() && start < 0:
+		start += len(keys)
+	case !array.IsAssoc():
+		if offset < 0 {
+			last, _ := strconv.Atoi(keys[len(keys)-1])
+			offset += last + 1
+		}
+		start = sort.Search(len(keys), func(i int) bool {
+			index, _ := strconv.Atoi(keys[i])
+			return index >= offset
+		})
+		if offset < 0 {
+			start = -1
+		}
+	}
+	if start < 0 || start >= len(values) {
+		return nil, nil
+	}
+	return values[start:min(len(values), start+length)], nil
+}
+
+// unbound возвращает ошибку подстановки незаданной переменной ref,
+// если включена опция nounset, и nil в остальных случаях.
+func (s *EnvSubstitutionStep) unbound(ref string) error {
+	if !s.SetOptions.Enabled(session.NoUnset) {
+		return nil
+	}
+	return fmt.Errorf("%s: несвязанная переменная", ref)
+}
+
+// expandSubscript подставляет в индекс массива переменные $NAME.
+func (s *EnvSubstitutionStep) expandSubscript(sub string) string {
+	var b strings.Builder
+	for i := 0; i < len(sub); i++ {
+		if sub[i] != '$' {
+			b.WriteByte(sub[i])
+			continue
+		}
+		m := defaultPattern.FindString(sub[i:])
+		if m == "" {
+			b.WriteByte('$')
+			continue
+		}
+		value, _ := s.lookup(m[1:])
+		b.WriteString(value)
+		i += len(m) - 1
+	}
+	return b.String()
+}
+
+// lookup возвращает значение переменной name; для массива — значение
+// элемента 0.
+func (s *EnvSubstitutionStep) lookup(name string) (string, bool) {
+	if array, ok := s.Vars.Array(name); ok {
+		value, _ := array.Get("0")
+		return value, true
+	}
+	return s.Vars.Get(name)
+}
+
+// array возвращает массив name. Обычная переменная рассматривается как
+// массив из элемента 0, отсутствующая — как пустой массив.
+func (s *EnvSubstitutionStep) array(name string) *session.Array {
+	if array, ok := s.Vars.Array(name); ok {
+		return array
+	}
+	if value, ok := s.Vars.Get(name); ok {
+		return session.NewIndexedArray(value)
+	}
+	return session.NewIndexedArray()
+}
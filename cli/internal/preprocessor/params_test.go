@@ -0,0 +1,128 @@
+package preprocessor
+
+import (
+	"testing"
+
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
+)
+
+func newArrayStep() *EnvSubstitutionStep {
+	sparse := session.NewIndexedArray("a", "b", "c", "d")
+	sparse.Unset("1")
+
+	port := session.NewAssocArray()
+	port.Set("http", "80")
+	port.Set("https", "443")
+
+	vars := session.NewVariables(map[string]string{"i": "2", "s": "привет"})
+	arrays := map[string]*session.Array{
+		"a":      session.NewIndexedArray("x", "y z", `q"`),
+		"empty":  session.NewIndexedArray(),
+		"sparse": sparse,
+		"port":   port,
+	}
+	for name, array := range arrays {
+		if err := vars.SetArray(name, array); err != nil {
+			panic(err)
+		}
+	}
+	return &EnvSubstitutionStep{Vars: vars}
+}
+
+func TestEnvSubstitutionStep_Arrays(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"echo $a ${a}", "echo x x"},
+		{"echo ${a[1]} ${a[$i]} ${a[i]} ${a[-1]}", `echo y z q\" q\" q\"`},
+		{`echo "${a[@]}"`, `echo "x" "y z" "q\""`},
+		{`echo "<${a[@]}>"`, `echo "<x" "y z" "q\">"`},
+		{"echo ${a[@]}", `echo x y z q\"`},
+		{`echo "${a[*]}"`, `echo "x y z q\""`},
+		{`echo "${empty[@]}" x`, "echo  x"},
+		{`echo "-${empty[@]}-"`, `echo "--"`},
+		{"echo ${#a[@]} ${#a[1]} ${#s} ${#sparse[*]}", "echo 3 3 6 3"},
+		{"echo ${!sparse[@]} ${!port[@]}", "echo 0 2 3 http https"},
+		{"echo ${port[https]} ${port[ftp]}-", "echo 443 -"},
+		{"echo ${sparse[@]:1} ${sparse[@]: -1} ${sparse[@]:0:2}", "echo c d d a c"},
+		{"echo ${port[@]:1} ${port[@]: -2:1}", "echo 443 80"},
+		{"echo ${s[0]} ${s[1]}- ${missing[@]}- ${#missing[@]}", "echo привет - - 0"},
+		{"echo '${a[0]}' \\${a[0]} ${undefined}", "echo '${a[0]}' \\${a[0]} ${undefined}"},
+	}
+
+	for _, tt := range tests {
+		result, err := NewPreprocessor(newArrayStep()).Process(tt.input)
+		if err != nil {
+			t.Errorf("%q: неожиданная ошибка: %v", tt.input, err)
+			continue
+		}
+		if result.Value != tt.expected {
+			t.Errorf("%q: ожидалось %q, получено %q", tt.input, tt.expected, result.Value)
+		}
+	}
+}
+
+func TestEnvSubstitutionStep_StarUsesIFS(t *testing.T) {
+	step := newArrayStep()
+	if err := step.Vars.Set("IFS", ",;"); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := step.Apply(PreprocessedInput{Value: `echo "${a[*]}" ${a[*]}`})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	expected := `echo "x,y z,q\"" x y z q\"`
+	if result.Value != expected {
+		t.Fatalf("ожидалось %q, получено %q", expected, result.Value)
+	}
+}
+
+func TestEnvSubstitutionStep_BadSubscript(t *testing.T) {
+	for _, input := range []string{"echo ${a[-9]}", "echo ${a[x y]}", "echo ${a[@]:x}", "echo ${a[@]:0:-1}", "echo ${port[]}"} {
+		if _, err := newArrayStep().Apply(PreprocessedInput{Value: input}); err == nil {
+			t.Errorf("%q: ожидалась ошибка", input)
+		}
+	}
+}
+
+func TestEnvSubstitutionStep_NoUnset(t *testing.T) {
+	step := newArrayStep()
+	step.SetOptions = session.NewSetOptions()
+	_ = step.SetOptions.Set(session.NoUnset, true)
+	_ = step.SetOptions.Set(session.ErrExit, true)
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`echo $- ${-} "${missing[@]}" ${!missing[@]}`, `echo eu eu  `},
+		{"echo ${#a[5]} ${sparse[0]}", "echo 0 a"},
+	}
+	for _, tt := range tests {
+		result, err := step.Apply(PreprocessedInput{Value: tt.input})
+		if err != nil {
+			t.Errorf("%q: неожиданная ошибка: %v", tt.input, err)
+			continue
+		}
+		if result.Value != tt.expected {
+			t.Errorf("%q: ожидалось %q, получено %q", tt.input, tt.expected, result.Value)
+		}
+	}
+
+	failures := map[string]string{
+		"echo $missing":       "missing: несвязанная переменная",
+		"echo ${missing}":     "missing: несвязанная переменная",
+		"echo ${#missing}":    "missing: несвязанная переменная",
+		"echo ${a[5]}":        "a[5]: несвязанная переменная",
+		"echo ${port[ftp]}":   "port[ftp]: несвязанная переменная",
+		"echo ${#missing[@]}": "missing: несвязанная переменная",
+		`echo "${sparse[1]}"`: "sparse[1]: несвязанная переменная",
+	}
+	for input, message := range failures {
+		if _, err := step.Apply(PreprocessedInput{Value: input}); err == nil || err.Error() != message {
+			t.Errorf("%q: ожидалась ошибка %q, получено %v", input, message, err)
+		}
+	}
+}
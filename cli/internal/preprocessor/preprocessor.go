@@ -1,9 +1,21 @@
 // Package preprocessor предоставляет шаги предобработки пользовательского ввода
 // перед передачей строк в парсер.
+//
+// Шаги раскрытия следует подключать в порядке, принятом в bash:
+// AliasExpansionStep, BraceExpansionStep, TildeExpansionStep,
+// EnvSubstitutionStep, GlobStep.
+// Все шаги учитывают кавычки: текст в кавычках не раскрывается.
+//
+// Раскрытие выполняется непосредственно перед выполнением команды, в
+// состоянии оболочки, которая её выполняет: Preprocessor.Bind привязывает
+// шаги к переменным, опциям и каталогу, например, подоболочки.
 package preprocessor
 
 import (
 	"regexp"
+	"strings"
+
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
 )
 
 // PreprocessedInput описывает строку после выполнения шагов препроцессинга.
@@ -17,6 +29,22 @@ type Step interface {
 	Apply(input PreprocessedInput) (PreprocessedInput, error)
 }
 
+// Session — состояние оболочки, в котором выполняется раскрытие.
+type Session struct {
+	Vars       *session.Variables
+	Options    *session.Options
+	SetOptions *session.Options
+	Aliases    *session.Aliases
+	// Dir возвращает рабочий каталог оболочки.
+	Dir func() string
+}
+
+// Binder — шаг, который зависит от состояния оболочки. Bind возвращает
+// копию шага, использующую состояние s.
+type Binder interface {
+	Bind(s Session) Step
+}
+
 // Preprocessor выполняет последовательность шагов обработки пользовательского ввода.
 type Preprocessor struct {
 	steps []Step
@@ -27,6 +55,19 @@ func NewPreprocessor(steps ...Step) *Preprocessor {
 	return &Preprocessor{steps: steps}
 }
 
+// Bind возвращает препроцессор с теми же шагами, привязанными к состоянию
+// оболочки s. Шаги, не реализующие Binder, используются как есть.
+func (p *Preprocessor) Bind(s Session) *Preprocessor {
+	steps := make([]Step, len(p.steps))
+	for i, step := range p.steps {
+		if binder, ok := step.(Binder); ok {
+			step = binder.Bind(s)
+		}
+		steps[i] = step
+	}
+	return &Preprocessor{steps: steps}
+}
+
 // Process последовательно применяет шаги к исходной строке.
 func (p *Preprocessor) Process(input string) (PreprocessedInput, error) {
 	result := PreprocessedInput{
@@ -45,36 +86,101 @@ func (p *Preprocessor) Process(input string) (PreprocessedInput, error) {
 	return result, nil
 }
 
-// EnvSubstitutionStep выполняет подстановку переменных окружения.
+// EnvSubstitutionStep выполняет подстановку переменных и элементов
+// массивов из Vars.
+// Подстановка не выполняется внутри одинарных кавычек и для экранированного "$".
+// Подставленные значения экранируются так, чтобы кавычки и операторы в них
+// не интерпретировались повторно при разборе.
+//
+// $- раскрывается во флаги включённых опций SetOptions. При опции nounset
+// подстановка незаданной переменной или элемента массива — ошибка.
 type EnvSubstitutionStep struct {
-	Env map[string]string
+	Vars       *session.Variables
+	SetOptions *session.Options
 }
 
-var (
-	bracedPattern  = regexp.MustCompile(`\$\{([^}]+)\}`)
-	defaultPattern = regexp.MustCompile(`\$([A-Za-z_][A-Za-z0-9_]*)`)
-)
+var defaultPattern = regexp.MustCompile(`^\$([A-Za-z_][A-Za-z0-9_]*)`)
+
+// Bind реализует Binder.
+func (s *EnvSubstitutionStep) Bind(state Session) Step {
+	return &EnvSubstitutionStep{Vars: state.Vars, SetOptions: state.SetOptions}
+}
 
 // Apply реализует шаг подстановки переменных окружения.
 func (s *EnvSubstitutionStep) Apply(input PreprocessedInput) (PreprocessedInput, error) {
-	value := bracedPattern.ReplaceAllStringFunc(input.Value, func(match string) string {
-		varName := match[2 : len(match)-1]
-		if envValue, ok := s.Env[varName]; ok {
-			return envValue
-		}
-		return match
-	})
+	var out []byte
+	value := input.Value
+	inDouble := false
+	// quoteStart — позиция в out открывающей двойной кавычки
+	quoteStart := -1
 
-	value = defaultPattern.ReplaceAllStringFunc(value, func(match string) string {
-		varName := match[1:]
-		if envValue, ok := s.Env[varName]; ok {
-			return envValue
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c == '\\' && i+1 < len(value):
+			out = append(out, value[i:i+2]...)
+			i++
+		case c == '\'' && !inDouble:
+			end := strings.IndexByte(value[i+1:], '\'')
+			if end < 0 {
+				end = len(value) - i - 2
+			}
+			out = append(out, value[i:i+end+2]...)
+			i += end + 1
+		case c == '"':
+			inDouble = !inDouble
+			if inDouble {
+				quoteStart = len(out)
+			}
+			out = append(out, c)
+		case c == '$':
+			exp, err := s.expand(value[i:], inDouble)
+			if err != nil {
+				return PreprocessedInput{}, err
+			}
+			if exp.length == 0 {
+				out = append(out, c)
+				continue
+			}
+			i += exp.length - 1
+
+			// "${a[@]}" для пустого массива не даёт ни одного слова
+			if exp.words && len(exp.values) == 0 && inDouble &&
+				quoteStart == len(out)-1 && i+1 < len(value) && value[i+1] == '"' {
+				out = out[:quoteStart]
+				inDouble = false
+				i++
+				continue
+			}
+			out = append(out, exp.text...)
+		default:
+			out = append(out, c)
 		}
-		return match
-	})
+	}
 
 	return PreprocessedInput{
 		Original: input.Original,
-		Value:    value,
+		Value:    string(out),
 	}, nil
 }
+
+// escapeValue экранирует значение переменной для вставки в строку.
+// Внутри двойных кавычек экранируются символы, сохраняющие там особый смысл.
+// Вне кавычек экранируются кавычки и операторы, а пробелы и метасимволы
+// шаблонов остаются активными: значение разбивается на слова и раскрывается,
+// как в bash.
+func escapeValue(value string, inDouble bool) string {
+	special := "\\'\"|;&()<>"
+	if inDouble {
+		special = "\\\"$`"
+	}
+
+	var b strings.Builder
+	for _, r := range value {
+		if strings.ContainsRune(special, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
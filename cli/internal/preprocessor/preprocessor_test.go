@@ -1,6 +1,10 @@
 package preprocessor
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
+)
 
 func TestEnvSubstitutionStep(t *testing.T) {
 	env := map[string]string{
@@ -9,7 +13,7 @@ func TestEnvSubstitutionStep(t *testing.T) {
 		"USER": "tester",
 	}
 
-	step := &EnvSubstitutionStep{Env: env}
+	step := &EnvSubstitutionStep{Vars: session.NewVariables(env)}
 	pre := NewPreprocessor(step)
 
 	result, err := pre.Process("echo $HOME ${PATH} $UNDEFINED $USER")
@@ -35,3 +39,81 @@ func TestPreprocessor_NoSteps(t *testing.T) {
 		t.Fatalf("значение должно совпадать: %s", result.Value)
 	}
 }
+
+func TestEnvSubstitutionStep_RespectsQuoting(t *testing.T) {
+	env := map[string]string{"X": "value"}
+	pre := NewPreprocessor(&EnvSubstitutionStep{Vars: session.NewVariables(env)})
+
+	result, err := pre.Process(`echo '$X' "$X" \$X $X`)
+	if err != nil {
+		t.Fatalf("ожидался успех, получили ошибку: %v", err)
+	}
+
+	expected := `echo '$X' "value" \$X value`
+	if result.Value != expected {
+		t.Fatalf("ожидалось %q, получили %q", expected, result.Value)
+	}
+}
+
+func TestEnvSubstitutionStep_EscapesValues(t *testing.T) {
+	env := map[string]string{"Q": `say "hi" | it's`}
+	pre := NewPreprocessor(&EnvSubstitutionStep{Vars: session.NewVariables(env)})
+
+	result, err := pre.Process(`echo "$Q" $Q`)
+	if err != nil {
+		t.Fatalf("ожидался успех, получили ошибку: %v", err)
+	}
+
+	expected := `echo "say \"hi\" | it's" say \"hi\" \| it\'s`
+	if result.Value != expected {
+		t.Fatalf("ожидалось %q, получили %q", expected, result.Value)
+	}
+}
+
+func TestPreprocessor_ExpansionOrder(t *testing.T) {
+	vars := session.NewVariables(map[string]string{"HOME": "/home/u", "EXT": "txt"})
+	pre := NewPreprocessor(
+		&BraceExpansionStep{},
+		&TildeExpansionStep{Vars: vars},
+		&EnvSubstitutionStep{Vars: vars},
+	)
+
+	// Раскрытие скобок выполняется до подстановки переменных,
+	// поэтому скобки из значения переменной не раскрываются.
+	if err := vars.Set("B", "{x,y}"); err != nil {
+		t.Fatal(err)
+	}
+	result, err := pre.Process("cp ~/file.{$EXT,bak} $B")
+	if err != nil {
+		t.Fatalf("ожидался успех, получили ошибку: %v", err)
+	}
+
+	expected := "cp /home/u/file.txt /home/u/file.bak {x,y}"
+	if result.Value != expected {
+		t.Fatalf("ожидалось %q, получили %q", expected, result.Value)
+	}
+}
+
+func TestPreprocessor_Bind(t *testing.T) {
+	vars := session.NewVariables(map[string]string{"X": "outer"})
+	pre := NewPreprocessor(&BraceExpansionStep{}, &EnvSubstitutionStep{Vars: vars})
+
+	// Привязанный препроцессор читает переменные подоболочки,
+	// исходный — переменные текущей оболочки
+	sub := vars.Copy()
+	if err := sub.Set("X", "inner"); err != nil {
+		t.Fatal(err)
+	}
+	bound, err := pre.Bind(Session{Vars: sub, SetOptions: session.NewSetOptions()}).Process("echo {a,b}$X")
+	if err != nil {
+		t.Fatalf("ожидался успех, получили ошибку: %v", err)
+	}
+	if bound.Value != "echo ainner binner" {
+		t.Fatalf("ожидалось %q, получили %q", "echo ainner binner", bound.Value)
+	}
+
+	result, _ := pre.Process("echo $X")
+	if result.Value != "echo outer" {
+		t.Fatalf("ожидалось %q, получили %q", "echo outer", result.Value)
+	}
+}
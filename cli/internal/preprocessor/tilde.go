@@ -0,0 +1,114 @@
+package preprocessor
+
+import (
+	"os"
+	"os/user"
+	"strings"
+
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/lexer"
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
+)
+
+// TildeExpansionStep выполняет раскрытие тильды в начале слова:
+//   - ~       → $HOME
+//   - ~user   → домашний каталог пользователя user
+//   - ~+      → $PWD (текущий каталог)
+//   - ~-      → $OLDPWD (предыдущий каталог)
+//
+// В присваиваниях VAR=value тильда раскрывается также после "=" и после
+// каждого ":", например PATH=~/bin:~/.local/bin. Тильда в кавычках или
+// экранированная тильда не раскрывается. Если раскрыть префикс не удалось,
+// слово остаётся без изменений.
+type TildeExpansionStep struct {
+	Vars *session.Variables
+}
+
+// Bind реализует Binder.
+func (s *TildeExpansionStep) Bind(state Session) Step {
+	return &TildeExpansionStep{Vars: state.Vars}
+}
+
+// Apply реализует шаг раскрытия тильды.
+func (s *TildeExpansionStep) Apply(input PreprocessedInput) (PreprocessedInput, error) {
+	value, err := rewriteWords(input.Value, func(word string, kind wordKind) (string, bool, error) {
+		if !strings.Contains(word, "~") {
+			return "", false, nil
+		}
+
+		if kind != assignmentWord {
+			expanded, ok := s.expandPrefix(word)
+			return expanded, ok, nil
+		}
+
+		eq := strings.IndexByte(word, '=')
+		parts := strings.Split(word[eq+1:], ":")
+		changed := false
+		for i, part := range parts {
+			if expanded, ok := s.expandPrefix(part); ok {
+				parts[i] = expanded
+				changed = true
+			}
+		}
+		return word[:eq+1] + strings.Join(parts, ":"), changed, nil
+	})
+	if err != nil {
+		return PreprocessedInput{}, err
+	}
+
+	return PreprocessedInput{
+		Original: input.Original,
+		Value:    value,
+	}, nil
+}
+
+// expandPrefix раскрывает тильда-префикс слова (всё до первого "/").
+// Результат заключается в кавычки, чтобы к нему не применялись
+// последующие подстановки и раскрытие шаблонов.
+func (s *TildeExpansionStep) expandPrefix(word string) (string, bool) {
+	if !strings.HasPrefix(word, "~") {
+		return "", false
+	}
+
+	prefix, rest := word, ""
+	if slash := strings.IndexByte(word, '/'); slash >= 0 {
+		prefix, rest = word[:slash], word[slash:]
+	}
+
+	// Тильда-префикс не должен содержать кавычек и экранирования.
+	if lexer.IsQuoted(prefix) || strings.ContainsAny(prefix, "${") {
+		return "", false
+	}
+
+	dir, ok := s.lookup(prefix[1:])
+	if !ok {
+		return "", false
+	}
+	return lexer.Quote(dir) + rest, true
+}
+
+// lookup возвращает каталог для тильда-префикса без самой тильды.
+func (s *TildeExpansionStep) lookup(name string) (string, bool) {
+	switch name {
+	case "":
+		if home, ok := s.Vars.Get("HOME"); ok {
+			return home, true
+		}
+		home, err := os.UserHomeDir()
+		return home, err == nil
+	case "+":
+		if pwd, ok := s.Vars.Get("PWD"); ok {
+			return pwd, true
+		}
+		pwd, err := os.Getwd()
+		return pwd, err == nil
+	case "-":
+		oldpwd, ok := s.Vars.Get("OLDPWD")
+		return oldpwd, ok
+	}
+
+	u, err := user.Lookup(name)
+	if err != nil {
+		return "", false
+	}
+	return u.HomeDir, true
+}
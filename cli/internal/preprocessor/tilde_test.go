@@ -0,0 +1,69 @@
+package preprocessor
+
+import (
+	"os/user"
+	"testing"
+
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/session"
+)
+
+func TestTildeExpansionStep(t *testing.T) {
+	env := map[string]string{
+		"HOME":   "/home/tester",
+		"PWD":    "/work",
+		"OLDPWD": "/prev",
+	}
+	step := &TildeExpansionStep{Vars: session.NewVariables(env)}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"cd ~", "cd /home/tester"},
+		{"ls ~/docs", "ls /home/tester/docs"},
+		{"echo ~+ ~-", "echo /work /prev"},
+		{"echo '~' \"~\" \\~", "echo '~' \"~\" \\~"},
+		{"echo a~b", "echo a~b"},
+		{"echo ~nosuchuser12345/x", "echo ~nosuchuser12345/x"},
+		{"PATH=~/bin:~/sbin:/usr/bin cmd ~", "PATH=/home/tester/bin:/home/tester/sbin:/usr/bin cmd /home/tester"},
+		{"echo X=~/bin", "echo X=~/bin"},
+	}
+
+	for _, tt := range tests {
+		result, err := step.Apply(PreprocessedInput{Value: tt.input})
+		if err != nil {
+			t.Fatalf("неожиданная ошибка для %q: %v", tt.input, err)
+		}
+		if result.Value != tt.expected {
+			t.Errorf("для %q ожидалось %q, получено %q", tt.input, tt.expected, result.Value)
+		}
+	}
+}
+
+func TestTildeExpansionStep_QuotesResult(t *testing.T) {
+	step := &TildeExpansionStep{Vars: session.NewVariables(map[string]string{"HOME": "/home/with space"})}
+
+	result, err := step.Apply(PreprocessedInput{Value: "ls ~/x"})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if result.Value != "ls '/home/with space'/x" {
+		t.Fatalf("каталог с пробелом должен быть в кавычках: %q", result.Value)
+	}
+}
+
+func TestTildeExpansionStep_User(t *testing.T) {
+	current, err := user.Current()
+	if err != nil {
+		t.Skip("не удалось определить текущего пользователя")
+	}
+
+	step := &TildeExpansionStep{Vars: session.NewVariables(nil)}
+	result, err := step.Apply(PreprocessedInput{Value: "~" + current.Username})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if result.Value != current.HomeDir {
+		t.Fatalf("ожидалось %q, получено %q", current.HomeDir, result.Value)
+	}
+}
@@ -0,0 +1,93 @@
+package preprocessor
+
+import (
+	"strings"
+
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/checkutils"
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/lexer"
+)
+
+// wordKind описывает положение слова в команде.
+type wordKind int
+
+const (
+	// commandWord — имя команды или её аргумент.
+	commandWord wordKind = iota
+	// assignmentWord — присваивание VAR=value перед именем команды.
+	assignmentWord
+	// conditionalWord — операнд выражения [[ ... ]]: к нему, как и в bash,
+	// не применяются раскрытие фигурных скобок и шаблонов имён файлов.
+	conditionalWord
+)
+
+// wordRewriter возвращает замену для слова и признак того, что замена нужна.
+type wordRewriter func(word string, kind wordKind) (string, bool, error)
+
+// rewriteWords разбивает строку на токены и заменяет слова результатом fn.
+// Операторы и пробелы между токенами сохраняются без изменений.
+func rewriteWords(value string, fn wordRewriter) (string, error) {
+	tokens, err := lexer.Split(value)
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	last := 0
+	commandStart := true
+	inConditional := false
+
+	for _, tok := range tokens {
+		kind := commandWord
+		switch {
+		case inConditional && tok.Kind == lexer.Word && tok.Value == "]]":
+			inConditional = false
+			continue
+		case inConditional:
+			if tok.Kind == lexer.Operator {
+				continue
+			}
+			kind = conditionalWord
+		case tok.Kind == lexer.Operator:
+			commandStart = true
+			continue
+		case commandStart && tok.Value == "[[":
+			inConditional = true
+			commandStart = false
+			continue
+		case commandStart && tok.Value == "{":
+			// После открывающей скобки группы { начинается новая команда.
+			continue
+		case commandStart && checkutils.IsEnvAssignmentCommand(lexer.Unquote(tok.Value)):
+			kind = assignmentWord
+		default:
+			commandStart = false
+		}
+
+		rewrite := fn
+		if prefix, _, compound := lexer.SplitCompound(tok.Value); compound {
+			// Элементы составного присваивания NAME=(...) раскрываются
+			// как обычные слова
+			rewrite = func(word string, _ wordKind) (string, bool, error) {
+				elements, err := rewriteWords(word[len(prefix)+1:len(word)-1], func(element string, _ wordKind) (string, bool, error) {
+					return fn(element, commandWord)
+				})
+				return prefix + "(" + elements + ")", err == nil, err
+			}
+		}
+
+		replacement, ok, err := rewrite(tok.Value, kind)
+		if err != nil {
+			return "", err
+		}
+		if !ok {
+			continue
+		}
+
+		b.WriteString(value[last:tok.Start])
+		b.WriteString(replacement)
+		last = tok.End
+	}
+	b.WriteString(value[last:])
+
+	return b.String(), nil
+}
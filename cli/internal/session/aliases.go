@@ -0,0 +1,83 @@
+package session
+
+import (
+	"sort"
+	"sync"
+)
+
+// Aliases — таблица псевдонимов команд сеанса.
+// Безопасна для одновременного использования из нескольких горутин.
+type Aliases struct {
+	mu     sync.RWMutex
+	values map[string]string
+}
+
+// NewAliases создаёт пустую таблицу псевдонимов.
+func NewAliases() *Aliases {
+	return &Aliases{values: make(map[string]string)}
+}
+
+// Copy возвращает независимую копию таблицы. Для nil возвращает nil.
+func (a *Aliases) Copy() *Aliases {
+	if a == nil {
+		return nil
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	values := make(map[string]string, len(a.values))
+	for name, value := range a.values {
+		values[name] = value
+	}
+	return &Aliases{values: values}
+}
+
+// Set определяет или переопределяет псевдоним.
+func (a *Aliases) Set(name, value string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.values[name] = value
+}
+
+// Get возвращает значение псевдонима. Для nil-таблицы псевдонимов нет.
+func (a *Aliases) Get(name string) (string, bool) {
+	if a == nil {
+		return "", false
+	}
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	value, ok := a.values[name]
+	return value, ok
+}
+
+// Remove удаляет псевдоним. Возвращает false, если псевдоним не был определён.
+func (a *Aliases) Remove(name string) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	_, ok := a.values[name]
+	delete(a.values, name)
+	return ok
+}
+
+// Clear удаляет все псевдонимы.
+func (a *Aliases) Clear() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.values = make(map[string]string)
+}
+
+// Names возвращает отсортированный список имён псевдонимов.
+func (a *Aliases) Names() []string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	names := make([]string, 0, len(a.values))
+	for name := range a.values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,56 @@
+package session
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAliases_SetGetRemove(t *testing.T) {
+	aliases := NewAliases()
+
+	aliases.Set("ll", "ls -la")
+	aliases.Set("gs", "git status")
+
+	if value, ok := aliases.Get("ll"); !ok || value != "ls -la" {
+		t.Fatalf("ожидалось значение 'ls -la', получено %q (%v)", value, ok)
+	}
+
+	if names := aliases.Names(); !reflect.DeepEqual(names, []string{"gs", "ll"}) {
+		t.Fatalf("неверный список имён: %q", names)
+	}
+
+	if !aliases.Remove("ll") {
+		t.Fatalf("удаление существующего псевдонима должно вернуть true")
+	}
+	if aliases.Remove("ll") {
+		t.Fatalf("повторное удаление должно вернуть false")
+	}
+
+	aliases.Clear()
+	if len(aliases.Names()) != 0 {
+		t.Fatalf("после Clear таблица должна быть пустой")
+	}
+}
+
+func TestAliases_NilHasNoAliases(t *testing.T) {
+	var aliases *Aliases
+	if _, ok := aliases.Get("ll"); ok {
+		t.Fatalf("nil-таблица не должна содержать псевдонимов")
+	}
+}
+
+func TestAliases_Copy(t *testing.T) {
+	aliases := NewAliases()
+	aliases.Set("ll", "ls -la")
+
+	copied := aliases.Copy()
+	copied.Set("ll", "ls -l")
+	copied.Set("gs", "git status")
+
+	if value, _ := aliases.Get("ll"); value != "ls -la" || len(aliases.Names()) != 1 {
+		t.Fatalf("копия не должна менять исходную таблицу: %q", aliases.Names())
+	}
+	if (*Aliases)(nil).Copy() != nil {
+		t.Fatalf("копия nil должна быть nil")
+	}
+}
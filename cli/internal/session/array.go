@@ -0,0 +1,203 @@
+package session
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrBadSubscript — индекс массива записан с ошибкой или выходит за его
+// начало.
+var ErrBadSubscript = errors.New("неверный индекс массива")
+
+// Array — массив оболочки: индексированный или ассоциативный.
+//
+// Индексированный массив разрежен: его ключи — неотрицательные целые
+// числа, элементы перебираются по возрастанию индекса, а удалённые
+// элементы не сдвигают остальные. Ключи ассоциативного массива —
+// произвольные строки; элементы перебираются в порядке добавления.
+//
+// В отличие от Options, Array не защищён от одновременного изменения:
+// у каждой подоболочки своя копия.
+type Array struct {
+	assoc bool
+	keys  []string
+	items map[string]string
+}
+
+// NewIndexedArray создаёт индексированный массив с элементами values,
+// начиная с индекса 0.
+func NewIndexedArray(values ...string) *Array {
+	a := &Array{items: make(map[string]string, len(values))}
+	a.Append(values...)
+	return a
+}
+
+// NewAssocArray создаёт пустой ассоциативный массив.
+func NewAssocArray() *Array {
+	return &Array{assoc: true, items: map[string]string{}}
+}
+
+// IsAssoc сообщает, что массив ассоциативный.
+func (a *Array) IsAssoc() bool {
+	return a.assoc
+}
+
+// Len возвращает число элементов.
+func (a *Array) Len() int {
+	return len(a.keys)
+}
+
+// Keys возвращает ключи элементов по порядку.
+func (a *Array) Keys() []string {
+	return append([]string(nil), a.keys...)
+}
+
+// Values возвращает значения элементов по порядку ключей.
+func (a *Array) Values() []string {
+	values := make([]string, len(a.keys))
+	for i, key := range a.keys {
+		values[i] = a.items[key]
+	}
+	return values
+}
+
+// Get возвращает элемент с ключом key.
+func (a *Array) Get(key string) (string, bool) {
+	value, ok := a.items[key]
+	return value, ok
+}
+
+// Set присваивает значение элементу с ключом key. Ключ индексированного
+// массива должен быть получен из Key или быть десятичным числом без
+// знака и ведущих нулей.
+func (a *Array) Set(key, value string) {
+	if _, ok := a.items[key]; !ok {
+		pos := len(a.keys)
+		if !a.assoc {
+			index, _ := strconv.Atoi(key)
+			pos = sort.Search(len(a.keys), func(i int) bool {
+				n, _ := strconv.Atoi(a.keys[i])
+				return n > index
+			})
+		}
+		a.keys = append(a.keys, "")
+		copy(a.keys[pos+1:], a.keys[pos:])
+		a.keys[pos] = key
+	}
+	a.items[key] = value
+}
+
+// Unset удаляет элемент с ключом key.
+func (a *Array) Unset(key string) {
+	if _, ok := a.items[key]; !ok {
+		return
+	}
+	delete(a.items, key)
+	for i, k := range a.keys {
+		if k == key {
+			a.keys = append(a.keys[:i], a.keys[i+1:]...)
+			break
+		}
+	}
+}
+
+// Append добавляет элементы в конец индексированного массива: после
+// наибольшего индекса.
+func (a *Array) Append(values ...string) {
+	next := a.next()
+	for _, value := range values {
+		a.Set(strconv.Itoa(next), value)
+		next++
+	}
+}
+
+// next возвращает индекс после наибольшего.
+func (a *Array) next() int {
+	if len(a.keys) == 0 {
+		return 0
+	}
+	last, _ := strconv.Atoi(a.keys[len(a.keys)-1])
+	return last + 1
+}
+
+// Key возвращает ключ элемента для индекса sub из NAME[sub]. Для
+// ассоциативного массива индекс и есть ключ. Индекс индексированного
+// массива — целое число или имя переменной с числом, которое
+// возвращает lookup; отрицательный индекс отсчитывается от конца.
+func (a *Array) Key(sub string, lookup func(name string) string) (string, error) {
+	if a.assoc {
+		if sub == "" {
+			return "", ErrBadSubscript
+		}
+		return sub, nil
+	}
+
+	index, err := EvalIndex(sub, lookup)
+	if err != nil {
+		return "", err
+	}
+	if index < 0 {
+		index += a.next()
+		if index < 0 {
+			return "", ErrBadSubscript
+		}
+	}
+	return strconv.Itoa(index), nil
+}
+
+// Copy возвращает независимую копию массива.
+func (a *Array) Copy() *Array {
+	c := &Array{assoc: a.assoc, keys: a.Keys(), items: make(map[string]string, len(a.items))}
+	for key, value := range a.items {
+		c.items[key] = value
+	}
+	return c
+}
+
+// EvalIndex вычисляет индекс индексированного массива: целое число или
+// имя переменной, значение которой — целое число (пустое значение — 0).
+func EvalIndex(sub string, lookup func(name string) string) (int, error) {
+	sub = strings.TrimSpace(sub)
+	if index, err := strconv.Atoi(sub); err == nil {
+		return index, nil
+	}
+	if !IsName(sub) {
+		return 0, ErrBadSubscript
+	}
+
+	value := strings.TrimSpace(lookup(sub))
+	if value == "" {
+		return 0, nil
+	}
+	index, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, ErrBadSubscript
+	}
+	return index, nil
+}
+
+// SplitSubscript разбирает ссылку на элемент NAME[SUB] и возвращает имя и
+// индекс. ok ложно, если ref не записан как NAME[SUB].
+func SplitSubscript(ref string) (name, sub string, ok bool) {
+	open := strings.IndexByte(ref, '[')
+	if open <= 0 || !strings.HasSuffix(ref, "]") || !IsName(ref[:open]) {
+		return "", "", false
+	}
+	return ref[:open], ref[open+1 : len(ref)-1], true
+}
+
+// IsName сообщает, что s — допустимое имя переменной.
+func IsName(s string) bool {
+	if s == "" || s[0] >= '0' && s[0] <= '9' {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '_' && (c < '0' || c > '9') && (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,106 @@
+package session
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestArray_IndexedIsSparse(t *testing.T) {
+	a := NewIndexedArray("x", "y", "z")
+	a.Set("10", "ten")
+	a.Set("5", "five")
+	a.Unset("1")
+
+	if keys := a.Keys(); !reflect.DeepEqual(keys, []string{"0", "2", "5", "10"}) {
+		t.Fatalf("ключи должны идти по возрастанию индекса: %q", keys)
+	}
+	if values := a.Values(); !reflect.DeepEqual(values, []string{"x", "z", "five", "ten"}) {
+		t.Fatalf("неверные значения: %q", values)
+	}
+
+	a.Append("next")
+	if value, ok := a.Get("11"); !ok || value != "next" {
+		t.Fatalf("Append должен добавлять после наибольшего индекса, получено %q (%v)", value, ok)
+	}
+}
+
+func TestArray_AssocKeepsInsertionOrder(t *testing.T) {
+	a := NewAssocArray()
+	a.Set("b", "2")
+	a.Set("a", "1")
+	a.Set("b", "3")
+
+	if !a.IsAssoc() || a.Len() != 2 {
+		t.Fatalf("ожидался ассоциативный массив из двух элементов, получено %v", a.Keys())
+	}
+	if keys := a.Keys(); !reflect.DeepEqual(keys, []string{"b", "a"}) {
+		t.Fatalf("ключи должны идти в порядке добавления: %q", keys)
+	}
+}
+
+func TestArray_Key(t *testing.T) {
+	a := NewIndexedArray("x", "y", "z")
+	lookup := func(name string) string {
+		return map[string]string{"i": "2", "bad": "abc"}[name]
+	}
+
+	tests := []struct {
+		sub      string
+		expected string
+	}{
+		{"1", "1"},
+		{" 2 ", "2"},
+		{"-1", "2"},
+		{"i", "2"},
+		{"unset", "0"},
+	}
+	for _, tt := range tests {
+		key, err := a.Key(tt.sub, lookup)
+		if err != nil || key != tt.expected {
+			t.Errorf("%q: ожидалось %q, получено %q (%v)", tt.sub, tt.expected, key, err)
+		}
+	}
+
+	for _, sub := range []string{"-4", "bad", "1+1", ""} {
+		if _, err := a.Key(sub, lookup); !errors.Is(err, ErrBadSubscript) {
+			t.Errorf("%q: ожидалась ошибка ErrBadSubscript, получено %v", sub, err)
+		}
+	}
+
+	if key, err := NewAssocArray().Key("i", lookup); err != nil || key != "i" {
+		t.Errorf("ключ ассоциативного массива не должен вычисляться: %q (%v)", key, err)
+	}
+}
+
+func TestArray_CopyIsIndependent(t *testing.T) {
+	a := NewIndexedArray("x")
+	copied := a.Copy()
+	copied.Set("0", "changed")
+	copied.Append("y")
+
+	if values := a.Values(); !reflect.DeepEqual(values, []string{"x"}) {
+		t.Fatalf("исходный массив не должен меняться: %q", values)
+	}
+}
+
+func TestSplitSubscript(t *testing.T) {
+	tests := []struct {
+		ref       string
+		name, sub string
+		ok        bool
+	}{
+		{"a[1]", "a", "1", true},
+		{"map[k=v]", "map", "k=v", true},
+		{"a[]", "a", "", true},
+		{"a", "", "", false},
+		{"1a[0]", "", "", false},
+		{"a[0", "", "", false},
+	}
+	for _, tt := range tests {
+		name, sub, ok := SplitSubscript(tt.ref)
+		if name != tt.name || sub != tt.sub || ok != tt.ok {
+			t.Errorf("%q: ожидалось %q %q %v, получено %q %q %v", tt.ref, tt.name, tt.sub, tt.ok, name, sub, ok)
+		}
+	}
+}
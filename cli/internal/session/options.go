@@ -0,0 +1,150 @@
+// Package session хранит разделяемое состояние сеанса интерпретатора,
+// которое читают шаги препроцессинга и изменяют встроенные команды.
+package session
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Имена опций, управляющих раскрытием шаблонов имён файлов.
+const (
+	NullGlob = "nullglob" // шаблон без совпадений раскрывается в пустой список
+	FailGlob = "failglob" // шаблон без совпадений считается ошибкой
+	DotGlob  = "dotglob"  // * и ? совпадают с именами, начинающимися с точки
+	GlobStar = "globstar" // ** совпадает с любым количеством каталогов
+	ExtGlob  = "extglob"  // расширенные шаблоны ?(), *(), +(), @(), !()
+)
+
+// Имена опций команды set.
+const (
+	ErrExit   = "errexit"   // -e: выход при ошибке команды
+	NoGlob    = "noglob"    // -f: шаблоны имён файлов не раскрываются
+	NoUnset   = "nounset"   // -u: подстановка незаданной переменной — ошибка
+	XTrace    = "xtrace"    // -x: команды выводятся в stderr перед выполнением
+	NoClobber = "noclobber" // -C: > не перезаписывает существующие файлы
+	PipeFail  = "pipefail"  // код пайплайна — код последней неудачной команды
+)
+
+// SetFlags сопоставляет однобуквенные флаги set именам опций.
+// Порядок совпадает с порядком флагов в $-.
+var SetFlags = []struct {
+	Flag byte
+	Name string
+}{
+	{'e', ErrExit},
+	{'f', NoGlob},
+	{'u', NoUnset},
+	{'x', XTrace},
+	{'C', NoClobber},
+}
+
+// Options — набор именованных логических опций оболочки.
+// Безопасен для одновременного использования из нескольких горутин.
+type Options struct {
+	mu    sync.RWMutex
+	flags map[string]bool
+}
+
+// NewOptions создаёт набор опций, в котором все известные опции выключены.
+func NewOptions() *Options {
+	return &Options{
+		flags: map[string]bool{
+			NullGlob: false,
+			FailGlob: false,
+			DotGlob:  false,
+			GlobStar: false,
+			ExtGlob:  false,
+		},
+	}
+}
+
+// NewSetOptions создаёт набор опций команды set, в котором все опции
+// выключены.
+func NewSetOptions() *Options {
+	return &Options{
+		flags: map[string]bool{
+			ErrExit:   false,
+			NoGlob:    false,
+			NoUnset:   false,
+			XTrace:    false,
+			NoClobber: false,
+			PipeFail:  false,
+		},
+	}
+}
+
+// Copy возвращает независимую копию набора опций. Для nil возвращает nil.
+func (o *Options) Copy() *Options {
+	if o == nil {
+		return nil
+	}
+
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	flags := make(map[string]bool, len(o.flags))
+	for name, value := range o.flags {
+		flags[name] = value
+	}
+	return &Options{flags: flags}
+}
+
+// Flags возвращает однобуквенные флаги включённых опций set
+// в том виде, в каком их показывает $-.
+func (o *Options) Flags() string {
+	var b strings.Builder
+	for _, f := range SetFlags {
+		if o.Enabled(f.Name) {
+			b.WriteByte(f.Flag)
+		}
+	}
+	return b.String()
+}
+
+// Set включает или выключает опцию. Возвращает ошибку для неизвестного имени.
+func (o *Options) Set(name string, value bool) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if _, ok := o.flags[name]; !ok {
+		return fmt.Errorf("%s: недопустимое имя опции", name)
+	}
+	o.flags[name] = value
+	return nil
+}
+
+// Enabled сообщает, включена ли опция. Для nil и неизвестных опций возвращает false.
+func (o *Options) Enabled(name string) bool {
+	if o == nil {
+		return false
+	}
+
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.flags[name]
+}
+
+// Known сообщает, существует ли опция с таким именем.
+func (o *Options) Known(name string) bool {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	_, ok := o.flags[name]
+	return ok
+}
+
+// Names возвращает отсортированный список имён всех опций.
+func (o *Options) Names() []string {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	names := make([]string, 0, len(o.flags))
+	for name := range o.flags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
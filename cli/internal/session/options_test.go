@@ -0,0 +1,60 @@
+package session
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOptions_SetAndEnabled(t *testing.T) {
+	opts := NewOptions()
+
+	if opts.Enabled(NullGlob) {
+		t.Fatalf("по умолчанию опции должны быть выключены")
+	}
+
+	if err := opts.Set(NullGlob, true); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if !opts.Enabled(NullGlob) {
+		t.Fatalf("опция nullglob должна быть включена")
+	}
+
+	if err := opts.Set("unknown", true); err == nil {
+		t.Fatalf("ожидалась ошибка для неизвестной опции")
+	}
+}
+
+func TestOptions_NilIsDisabled(t *testing.T) {
+	var opts *Options
+	if opts.Enabled(DotGlob) {
+		t.Fatalf("для nil опции должны считаться выключенными")
+	}
+}
+
+func TestOptions_Names(t *testing.T) {
+	expected := []string{DotGlob, ExtGlob, FailGlob, GlobStar, NullGlob}
+	if names := NewOptions().Names(); !reflect.DeepEqual(names, expected) {
+		t.Fatalf("ожидалось %q, получено %q", expected, names)
+	}
+}
+
+func TestOptions_SetFlagsAndCopy(t *testing.T) {
+	opts := NewSetOptions()
+	for _, name := range []string{XTrace, ErrExit, PipeFail, NoClobber} {
+		if err := opts.Set(name, true); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if flags := opts.Flags(); flags != "exC" {
+		t.Errorf("ожидалось %q, получено %q", "exC", flags)
+	}
+
+	c := opts.Copy()
+	_ = c.Set(ErrExit, false)
+	if !opts.Enabled(ErrExit) || c.Enabled(ErrExit) {
+		t.Errorf("копия не должна разделять опции с исходным набором")
+	}
+	if NewOptions().Known(ErrExit) {
+		t.Errorf("опции set не должны смешиваться с опциями shopt")
+	}
+}
@@ -0,0 +1,159 @@
+package session
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Псевдосигналы команды trap.
+const (
+	TrapExit  = "EXIT"  // выход из оболочки
+	TrapErr   = "ERR"   // команда завершилась с ошибкой
+	TrapDebug = "DEBUG" // перед выполнением каждого пайплайна
+)
+
+// Signals — сигналы, для которых можно установить ловушку, в порядке
+// номеров. Имена записываются без префикса SIG.
+var Signals = []struct {
+	Number int
+	Name   string
+}{
+	{1, "HUP"},
+	{2, "INT"},
+	{3, "QUIT"},
+	{15, "TERM"},
+}
+
+// TrapName возвращает имя ловушки для записи spec: номера сигнала, имени
+// сигнала с префиксом SIG или без него или псевдосигнала. Регистр не
+// важен, номер 0 означает EXIT. ok ложно для неизвестного сигнала.
+func TrapName(spec string) (string, bool) {
+	if number, err := strconv.Atoi(spec); err == nil {
+		if number == 0 {
+			return TrapExit, true
+		}
+		for _, s := range Signals {
+			if s.Number == number {
+				return s.Name, true
+			}
+		}
+		return "", false
+	}
+
+	name := strings.ToUpper(spec)
+	switch name {
+	case TrapExit, TrapErr, TrapDebug:
+		return name, true
+	}
+	name = strings.TrimPrefix(name, "SIG")
+	for _, s := range Signals {
+		if s.Name == name {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// Traps — таблица ловушек: команд, которые оболочка выполняет при
+// получении сигнала или при наступлении псевдосигнала. Пустая команда
+// означает, что сигнал игнорируется. Безопасна для одновременного
+// использования из нескольких горутин: сигналы поступают асинхронно.
+type Traps struct {
+	mu       sync.Mutex
+	handlers map[string]string
+	pending  []string
+	notify   func()
+}
+
+// NewTraps создаёт пустую таблицу ловушек.
+func NewTraps() *Traps {
+	return &Traps{handlers: map[string]string{}}
+}
+
+// Set устанавливает команду command для ловушки name.
+func (t *Traps) Set(name, command string) {
+	t.mu.Lock()
+	t.handlers[name] = command
+	notify := t.notify
+	t.mu.Unlock()
+
+	if notify != nil {
+		notify()
+	}
+}
+
+// Reset удаляет ловушку name: сигнал снова обрабатывается по умолчанию.
+func (t *Traps) Reset(name string) {
+	t.mu.Lock()
+	delete(t.handlers, name)
+	notify := t.notify
+	t.mu.Unlock()
+
+	if notify != nil {
+		notify()
+	}
+}
+
+// Get возвращает команду ловушки name. Для nil ok ложно.
+func (t *Traps) Get(name string) (string, bool) {
+	if t == nil {
+		return "", false
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	command, ok := t.handlers[name]
+	return command, ok
+}
+
+// Names возвращает имена установленных ловушек в порядке вывода trap -p:
+// EXIT, сигналы по возрастанию номеров, DEBUG и ERR.
+func (t *Traps) Names() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	order := []string{TrapExit}
+	for _, s := range Signals {
+		order = append(order, s.Name)
+	}
+	order = append(order, TrapDebug, TrapErr)
+
+	var names []string
+	for _, name := range order {
+		if _, ok := t.handlers[name]; ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Notify задаёт функцию, которую таблица вызывает после каждого изменения,
+// например чтобы подписаться на перехватываемые сигналы. nil отменяет вызов.
+func (t *Traps) Notify(fn func()) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.notify = fn
+}
+
+// Raise отмечает, что получен сигнал name. Его ловушка выполнится между
+// командами (см. TakePending).
+func (t *Traps) Raise(name string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.pending = append(t.pending, name)
+}
+
+// TakePending возвращает полученные сигналы в порядке поступления и
+// очищает очередь. Для nil возвращает nil.
+func (t *Traps) TakePending() []string {
+	if t == nil {
+		return nil
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	pending := t.pending
+	t.pending = nil
+	return pending
+}
@@ -0,0 +1,60 @@
+package session
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTrapName(t *testing.T) {
+	tests := map[string]string{
+		"EXIT":    TrapExit,
+		"0":       TrapExit,
+		"err":     TrapErr,
+		"DEBUG":   TrapDebug,
+		"INT":     "INT",
+		"SIGint":  "INT",
+		"15":      "TERM",
+		"SIGHUP":  "HUP",
+		"SIGEXIT": "",
+		"KILL":    "",
+		"99":      "",
+	}
+	for spec, expected := range tests {
+		name, ok := TrapName(spec)
+		if name != expected || ok != (expected != "") {
+			t.Errorf("%s: ожидалось %q, получено %q (%v)", spec, expected, name, ok)
+		}
+	}
+}
+
+func TestTraps(t *testing.T) {
+	traps := NewTraps()
+	changes := 0
+	traps.Notify(func() { changes++ })
+
+	traps.Set(TrapErr, "echo err")
+	traps.Set("TERM", "")
+	traps.Set(TrapExit, "cleanup")
+	traps.Set("INT", "echo int")
+	traps.Reset("INT")
+
+	expected := []string{TrapExit, "TERM", TrapErr}
+	if names := traps.Names(); !reflect.DeepEqual(names, expected) {
+		t.Errorf("ожидалось %q, получено %q", expected, names)
+	}
+	if command, ok := traps.Get("TERM"); !ok || command != "" {
+		t.Errorf("пустая команда должна сохраняться, получено %q, %v", command, ok)
+	}
+	if changes != 5 {
+		t.Errorf("ожидалось 5 уведомлений, получено %d", changes)
+	}
+
+	traps.Raise("TERM")
+	traps.Raise("INT")
+	if pending := traps.TakePending(); !reflect.DeepEqual(pending, []string{"TERM", "INT"}) {
+		t.Errorf("ожидались сигналы TERM и INT, получено %q", pending)
+	}
+	if pending := traps.TakePending(); pending != nil {
+		t.Errorf("очередь должна очищаться, получено %q", pending)
+	}
+}
@@ -0,0 +1,472 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ggerlakh/software-design-mhs-itmo/cli/internal/arith"
+)
+
+// ErrReadonly — попытка изменить или удалить переменную только для чтения.
+var ErrReadonly = errors.New("переменная только для чтения")
+
+// maxRefs ограничивает длину цепочки ссылок -n: a → b → a не должно
+// приводить к бесконечному циклу.
+const maxRefs = 8
+
+// Attr — атрибут переменной, который задаёт declare.
+type Attr uint
+
+const (
+	// AttrInteger (-i): присваиваемое значение вычисляется как
+	// арифметическое выражение.
+	AttrInteger Attr = 1 << iota
+	// AttrNameref (-n): переменная — ссылка на другую переменную по имени.
+	AttrNameref
+	// AttrReadonly (-r): переменную нельзя изменить или удалить.
+	AttrReadonly
+	// AttrExport (-x): переменная передаётся внешним программам.
+	AttrExport
+	// AttrLower (-l): присваиваемое значение переводится в нижний регистр.
+	AttrLower
+	// AttrUpper (-u): присваиваемое значение переводится в верхний регистр.
+	AttrUpper
+)
+
+// Variable — переменная оболочки: обычная или массив.
+type Variable struct {
+	// Value — значение обычной переменной; для ссылки -n — имя
+	// переменной, на которую она ссылается.
+	Value string
+	// Array — значения массива; nil для обычной переменной.
+	Array *Array
+	Attrs Attr
+	// HasValue ложно, если переменная объявлена без значения
+	// (declare -i n).
+	HasValue bool
+}
+
+// Variables — переменные сеанса вместе с их атрибутами.
+//
+// Имена ссылок -n разрешаются во всех методах, кроме Lookup и UnsetRef.
+// Как и Array, таблица не защищена от одновременного изменения: у каждой
+// подоболочки своя копия (см. Copy).
+type Variables struct {
+	vars map[string]*Variable
+}
+
+// NewVariables создаёт таблицу переменных из окружения env. Переменные
+// окружения экспортируются. env может быть nil.
+func NewVariables(env map[string]string) *Variables {
+	v := &Variables{vars: make(map[string]*Variable, len(env))}
+	for name, value := range env {
+		v.vars[name] = &Variable{Value: value, Attrs: AttrExport, HasValue: true}
+	}
+	return v
+}
+
+// Copy возвращает независимую копию таблицы.
+func (v *Variables) Copy() *Variables {
+	c := &Variables{vars: make(map[string]*Variable, len(v.vars))}
+	for name, variable := range v.vars {
+		copied := *variable
+		if variable.Array != nil {
+			copied.Array = variable.Array.Copy()
+		}
+		c.vars[name] = &copied
+	}
+	return c
+}
+
+// Lookup возвращает переменную name без разрешения ссылок -n. Массив в
+// результате нельзя изменять.
+func (v *Variables) Lookup(name string) (Variable, bool) {
+	if v == nil {
+		return Variable{}, false
+	}
+	variable, ok := v.vars[name]
+	if !ok {
+		return Variable{}, false
+	}
+	return *variable, true
+}
+
+// Get возвращает значение переменной name; для массива — значение
+// элемента 0. ok ложно, если у переменной нет значения.
+func (v *Variables) Get(name string) (string, bool) {
+	variable := v.resolved(name)
+	switch {
+	case variable == nil:
+		return "", false
+	case variable.Array != nil:
+		return variable.Array.Get("0")
+	}
+	return variable.Value, variable.HasValue
+}
+
+// Array возвращает массив name. ok ложно, если name — не массив.
+// Возвращённый массив нельзя изменять.
+func (v *Variables) Array(name string) (*Array, bool) {
+	variable := v.resolved(name)
+	if variable == nil || variable.Array == nil {
+		return nil, false
+	}
+	return variable.Array, true
+}
+
+// Names возвращает отсортированный список имён переменных.
+func (v *Variables) Names() []string {
+	if v == nil {
+		return nil
+	}
+	names := make([]string, 0, len(v.vars))
+	for name := range v.vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Environ возвращает экспортируемые обычные переменные со значениями в
+// виде NAME=VALUE — окружение для внешних программ. Массивы не
+// экспортируются.
+func (v *Variables) Environ() []string {
+	var env []string
+	for _, name := range v.Names() {
+		variable := v.vars[name]
+		if variable.Attrs&AttrExport != 0 && variable.Array == nil && variable.HasValue &&
+			variable.Attrs&AttrNameref == 0 {
+			env = append(env, name+"="+variable.Value)
+		}
+	}
+	return env
+}
+
+// Set присваивает значение переменной name; см. Assign.
+func (v *Variables) Set(name, value string) error {
+	return v.Assign(name, value, false)
+}
+
+// Assign присваивает значение переменной name или, если это массив, его
+// элементу 0. При appendValue значение дописывается к старому (для
+// AttrInteger — прибавляется). Присваивание ссылке -n без цели задаёт
+// цель.
+func (v *Variables) Assign(name, value string, appendValue bool) error {
+	target, err := v.resolve(name)
+	if err != nil {
+		return err
+	}
+	variable := v.vars[target]
+	switch {
+	case variable == nil:
+		variable = &Variable{}
+		v.vars[target] = variable
+	case variable.Attrs&AttrReadonly != 0:
+		return fmt.Errorf("%s: %w", target, ErrReadonly)
+	case variable.Attrs&AttrNameref != 0:
+		return v.SetRef(target, value)
+	case variable.Array != nil:
+		return v.AssignElement(target, "0", value, appendValue)
+	}
+
+	value, err = v.convert(variable.Attrs, variable.Value, value, appendValue)
+	if err != nil {
+		return err
+	}
+	variable.Value, variable.HasValue = value, true
+	return nil
+}
+
+// AssignElement присваивает значение элементу массива name с индексом
+// или ключом sub (см. Array.Key). Обычная переменная становится
+// индексированным массивом со значением в элементе 0.
+func (v *Variables) AssignElement(name, sub, value string, appendValue bool) error {
+	target, err := v.resolve(name)
+	if err != nil {
+		return err
+	}
+	array, err := v.toArray(target)
+	if err != nil {
+		return err
+	}
+
+	key, err := array.Key(sub, v.value)
+	if err != nil {
+		return fmt.Errorf("%s[%s]: %w", target, sub, err)
+	}
+	old, _ := array.Get(key)
+	value, err = v.convert(v.vars[target].Attrs, old, value, appendValue)
+	if err != nil {
+		return err
+	}
+	array.Set(key, value)
+	v.vars[target].HasValue = true
+	return nil
+}
+
+// SetArray заменяет значения массива name значениями array с учётом
+// атрибутов переменной. Обычная переменная становится массивом.
+func (v *Variables) SetArray(name string, array *Array) error {
+	if err := v.ResetArray(name, array.IsAssoc()); err != nil {
+		return err
+	}
+	for _, key := range array.Keys() {
+		value, _ := array.Get(key)
+		if err := v.AssignElement(name, key, value, false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ResetArray делает переменную name пустым массивом. Ассоциативный
+// массив остаётся ассоциативным; остальные переменные становятся
+// ассоциативными, только если assoc истинно. Атрибуты сохраняются.
+func (v *Variables) ResetArray(name string, assoc bool) error {
+	target, err := v.resolve(name)
+	if err != nil {
+		return err
+	}
+	variable := v.vars[target]
+	switch {
+	case variable == nil:
+		variable = &Variable{}
+		v.vars[target] = variable
+	case variable.Attrs&AttrReadonly != 0:
+		return fmt.Errorf("%s: %w", target, ErrReadonly)
+	}
+
+	if assoc || variable.Array != nil && variable.Array.IsAssoc() {
+		variable.Array = NewAssocArray()
+	} else {
+		variable.Array = NewIndexedArray()
+	}
+	variable.Value, variable.HasValue = "", true
+	return nil
+}
+
+// DeclareArray делает переменную name индексированным или, если assoc
+// истинно, ассоциативным массивом. Значение обычной переменной
+// становится элементом 0. Индексированный массив нельзя превратить в
+// ассоциативный и наоборот.
+func (v *Variables) DeclareArray(name string, assoc bool) error {
+	target, err := v.resolve(name)
+	if err != nil {
+		return err
+	}
+	variable := v.vars[target]
+	switch {
+	case variable != nil && variable.Array != nil && assoc && !variable.Array.IsAssoc():
+		return fmt.Errorf("%s: невозможно преобразовать индексированный массив в ассоциативный", target)
+	case variable != nil && variable.Array != nil && !assoc && variable.Array.IsAssoc():
+		return fmt.Errorf("%s: невозможно преобразовать ассоциативный массив в индексированный", target)
+	case variable != nil && variable.Array != nil:
+		return nil
+	}
+
+	array := NewIndexedArray()
+	if assoc {
+		array = NewAssocArray()
+	}
+	if variable == nil {
+		// Как и в bash, объявленный массив без элементов не имеет значения
+		v.vars[target] = &Variable{Array: array}
+		return nil
+	}
+	if variable.Attrs&AttrReadonly != 0 {
+		return fmt.Errorf("%s: %w", target, ErrReadonly)
+	}
+	if variable.HasValue {
+		array.Set("0", variable.Value)
+	}
+	variable.Array, variable.Value = array, ""
+	return nil
+}
+
+// Declare объявляет переменную name: добавляет атрибуты set и снимает
+// атрибуты clear. Значение не меняется. Снять AttrReadonly нельзя.
+// AttrLower и AttrUpper взаимоисключающие: побеждает добавленный.
+func (v *Variables) Declare(name string, set, clear Attr) error {
+	target := name
+	if set&AttrNameref == 0 && clear&AttrNameref == 0 {
+		var err error
+		if target, err = v.resolve(name); err != nil {
+			return err
+		}
+	}
+
+	variable := v.vars[target]
+	if variable == nil {
+		variable = &Variable{}
+		v.vars[target] = variable
+	}
+	if variable.Attrs&AttrReadonly != 0 && clear&AttrReadonly != 0 {
+		return fmt.Errorf("%s: %w", target, ErrReadonly)
+	}
+	if set&AttrNameref != 0 && variable.Array != nil {
+		return fmt.Errorf("%s: ссылкой не может быть массив", target)
+	}
+
+	switch {
+	case set&AttrLower != 0:
+		clear |= AttrUpper
+	case set&AttrUpper != 0:
+		clear |= AttrLower
+	}
+	variable.Attrs = variable.Attrs&^clear | set
+	return nil
+}
+
+// SetRef делает переменную name ссылкой на переменную target.
+func (v *Variables) SetRef(name, target string) error {
+	if !IsName(target) {
+		return fmt.Errorf("%s: '%s': недопустимое имя переменной для ссылки", name, target)
+	}
+	if target == name {
+		return fmt.Errorf("%s: ссылка на саму себя недопустима", name)
+	}
+
+	variable := v.vars[name]
+	switch {
+	case variable == nil:
+		variable = &Variable{}
+		v.vars[name] = variable
+	case variable.Attrs&AttrReadonly != 0:
+		return fmt.Errorf("%s: %w", name, ErrReadonly)
+	}
+	variable.Attrs |= AttrNameref
+	variable.Value, variable.HasValue = target, true
+	return nil
+}
+
+// Unset удаляет переменную name; для ссылки -n — переменную, на которую
+// она ссылается.
+func (v *Variables) Unset(name string) error {
+	target, err := v.resolve(name)
+	if err != nil {
+		return err
+	}
+	return v.UnsetRef(target)
+}
+
+// UnsetRef удаляет саму переменную name, даже если это ссылка -n.
+func (v *Variables) UnsetRef(name string) error {
+	if variable, ok := v.vars[name]; ok && variable.Attrs&AttrReadonly != 0 {
+		return fmt.Errorf("%s: невозможно удалить: %w", name, ErrReadonly)
+	}
+	delete(v.vars, name)
+	return nil
+}
+
+// UnsetElement удаляет элемент sub массива name. Для обычной переменной
+// элемент 0 — сама переменная.
+func (v *Variables) UnsetElement(name, sub string) error {
+	target, err := v.resolve(name)
+	if err != nil {
+		return err
+	}
+	variable, ok := v.vars[target]
+	if !ok {
+		return nil
+	}
+
+	array := variable.Array
+	if array == nil {
+		array = NewIndexedArray(variable.Value)
+	}
+	key, err := array.Key(sub, v.value)
+	if err != nil {
+		return fmt.Errorf("%s[%s]: %w", target, sub, err)
+	}
+	switch {
+	case variable.Attrs&AttrReadonly != 0:
+		return fmt.Errorf("%s: невозможно удалить: %w", target, ErrReadonly)
+	case variable.Array != nil:
+		variable.Array.Unset(key)
+	case key == "0":
+		delete(v.vars, target)
+	}
+	return nil
+}
+
+// resolve возвращает имя переменной, на которую указывает цепочка
+// ссылок -n, начиная с name.
+func (v *Variables) resolve(name string) (string, error) {
+	target := name
+	for range maxRefs {
+		variable, ok := v.vars[target]
+		if !ok || variable.Attrs&AttrNameref == 0 || !variable.HasValue {
+			return target, nil
+		}
+		target = variable.Value
+	}
+	return "", fmt.Errorf("%s: циклическая ссылка на имя", name)
+}
+
+// resolved возвращает переменную с учётом ссылок -n или nil.
+func (v *Variables) resolved(name string) *Variable {
+	if v == nil {
+		return nil
+	}
+	target, err := v.resolve(name)
+	if err != nil {
+		return nil
+	}
+	return v.vars[target]
+}
+
+// value возвращает значение переменной для арифметических выражений и
+// индексов массивов.
+func (v *Variables) value(name string) string {
+	value, _ := v.Get(name)
+	return value
+}
+
+// toArray возвращает массив target, превращая обычную переменную в
+// индексированный массив.
+func (v *Variables) toArray(target string) (*Array, error) {
+	variable := v.vars[target]
+	switch {
+	case variable != nil && variable.Attrs&AttrReadonly != 0:
+		return nil, fmt.Errorf("%s: %w", target, ErrReadonly)
+	case variable != nil && variable.Array != nil:
+		return variable.Array, nil
+	}
+	if err := v.DeclareArray(target, false); err != nil {
+		return nil, err
+	}
+	return v.vars[target].Array, nil
+}
+
+// convert применяет к присваиваемому значению атрибуты attrs; old —
+// прежнее значение для дописывания.
+func (v *Variables) convert(attrs Attr, old, value string, appendValue bool) (string, error) {
+	if attrs&AttrInteger != 0 {
+		n, err := arith.Eval(value, v.value)
+		if err != nil {
+			return "", err
+		}
+		if appendValue {
+			prev, err := arith.Eval(old, v.value)
+			if err != nil {
+				return "", err
+			}
+			n += prev
+		}
+		return strconv.FormatInt(n, 10), nil
+	}
+
+	if appendValue {
+		value = old + value
+	}
+	switch {
+	case attrs&AttrLower != 0:
+		value = strings.ToLower(value)
+	case attrs&AttrUpper != 0:
+		value = strings.ToUpper(value)
+	}
+	return value, nil
+}
@@ -0,0 +1,167 @@
+package session
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestVariables_Attributes(t *testing.T) {
+	v := NewVariables(map[string]string{"x": "4"})
+	steps := []struct {
+		name     string
+		set      Attr
+		value    string
+		append   bool
+		expected string
+	}{
+		{"n", AttrInteger, "x*2+1", false, "9"},
+		{"n", AttrInteger, "3", true, "12"},
+		{"n", AttrInteger, "abc", false, "0"},
+		{"low", AttrLower, "MiXeD", false, "mixed"},
+		{"low", AttrUpper, "MiXeD", false, "MIXED"},
+		{"low", AttrUpper, "!", true, "MIXED!"},
+	}
+	for _, s := range steps {
+		if err := v.Declare(s.name, s.set, 0); err != nil {
+			t.Fatalf("%s: неожиданная ошибка: %v", s.name, err)
+		}
+		if err := v.Assign(s.name, s.value, s.append); err != nil {
+			t.Fatalf("%s=%s: неожиданная ошибка: %v", s.name, s.value, err)
+		}
+		if value, _ := v.Get(s.name); value != s.expected {
+			t.Errorf("%s=%s: ожидалось %q, получено %q", s.name, s.value, s.expected, value)
+		}
+	}
+
+	if low, _ := v.Lookup("low"); low.Attrs&AttrLower != 0 {
+		t.Errorf("-u должен снимать -l, получено %b", low.Attrs)
+	}
+	if err := v.Assign("n", "1/0", false); err == nil {
+		t.Errorf("ожидалась ошибка деления на ноль")
+	}
+}
+
+func TestVariables_Readonly(t *testing.T) {
+	v := NewVariables(map[string]string{"r": "1"})
+	if err := v.Declare("r", AttrReadonly, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	checks := map[string]error{
+		"Set":           v.Set("r", "2"),
+		"AssignElement": v.AssignElement("r", "1", "2", false),
+		"DeclareArray":  v.DeclareArray("r", false),
+		"Unset":         v.Unset("r"),
+		"UnsetElement":  v.UnsetElement("r", "0"),
+		"Declare +r":    v.Declare("r", 0, AttrReadonly),
+	}
+	for name, err := range checks {
+		if !errors.Is(err, ErrReadonly) {
+			t.Errorf("%s: ожидалась ошибка ErrReadonly, получено %v", name, err)
+		}
+	}
+	if value, _ := v.Get("r"); value != "1" {
+		t.Errorf("значение не должно меняться, получено %q", value)
+	}
+}
+
+func TestVariables_Nameref(t *testing.T) {
+	v := NewVariables(map[string]string{"target": "old"})
+	if err := v.SetRef("ref", "target"); err != nil {
+		t.Fatal(err)
+	}
+
+	if value, _ := v.Get("ref"); value != "old" {
+		t.Errorf("чтение через ссылку: ожидалось %q, получено %q", "old", value)
+	}
+	if err := v.Set("ref", "new"); err != nil {
+		t.Fatal(err)
+	}
+	if value, _ := v.Get("target"); value != "new" {
+		t.Errorf("присваивание через ссылку: ожидалось %q, получено %q", "new", value)
+	}
+	if ref, _ := v.Lookup("ref"); ref.Value != "target" || ref.Attrs&AttrNameref == 0 {
+		t.Errorf("ссылка должна хранить имя цели, получено %+v", ref)
+	}
+
+	if err := v.Unset("ref"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := v.Lookup("target"); ok {
+		t.Errorf("unset через ссылку должен удалить цель")
+	}
+	if err := v.UnsetRef("ref"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := v.Lookup("ref"); ok {
+		t.Errorf("UnsetRef должен удалить саму ссылку")
+	}
+
+	if err := v.SetRef("self", "self"); err == nil {
+		t.Errorf("ожидалась ошибка для ссылки на саму себя")
+	}
+	if err := v.SetRef("bad", "1x"); err == nil {
+		t.Errorf("ожидалась ошибка для неверного имени цели")
+	}
+	_ = v.SetRef("a", "b")
+	_ = v.SetRef("b", "a")
+	if err := v.Set("a", "x"); err == nil {
+		t.Errorf("ожидалась ошибка для циклической ссылки")
+	}
+}
+
+func TestVariables_Arrays(t *testing.T) {
+	v := NewVariables(map[string]string{"s": "zero"})
+	if err := v.AssignElement("s", "2", "two", false); err != nil {
+		t.Fatal(err)
+	}
+	if s, _ := v.Array("s"); !reflect.DeepEqual(s.Values(), []string{"zero", "two"}) {
+		t.Errorf("переменная должна стать массивом, получено %q", s.Values())
+	}
+	if value, _ := v.Get("s"); value != "zero" {
+		t.Errorf("значение массива — элемент 0, получено %q", value)
+	}
+
+	if err := v.DeclareArray("s", true); err == nil {
+		t.Errorf("ожидалась ошибка преобразования в ассоциативный массив")
+	}
+	if err := v.DeclareArray("e", false); err != nil {
+		t.Fatal(err)
+	}
+	if e, _ := v.Lookup("e"); e.HasValue || e.Array == nil {
+		t.Errorf("объявленный массив не должен иметь значения, получено %+v", e)
+	}
+
+	if err := v.UnsetElement("s", "0"); err != nil {
+		t.Fatal(err)
+	}
+	if s, _ := v.Array("s"); !reflect.DeepEqual(s.Keys(), []string{"2"}) {
+		t.Errorf("ожидался элемент 2, получено %q", s.Keys())
+	}
+}
+
+func TestVariables_EnvironAndCopy(t *testing.T) {
+	v := NewVariables(map[string]string{"PATH": "/bin"})
+	_ = v.Set("local", "1")
+	_ = v.Set("exported", "2")
+	_ = v.Declare("exported", AttrExport, 0)
+	_ = v.Declare("empty", AttrExport, 0)
+	_ = v.SetArray("list", NewIndexedArray("x"))
+	_ = v.Declare("list", AttrExport, 0)
+
+	expected := []string{"PATH=/bin", "exported=2"}
+	if env := v.Environ(); !reflect.DeepEqual(env, expected) {
+		t.Errorf("ожидалось %q, получено %q", expected, env)
+	}
+
+	c := v.Copy()
+	_ = c.Set("local", "changed")
+	_ = c.AssignElement("list", "1", "y", false)
+	if value, _ := v.Get("local"); value != "1" {
+		t.Errorf("копия не должна менять исходную таблицу, получено %q", value)
+	}
+	if list, _ := v.Array("list"); list.Len() != 1 {
+		t.Errorf("массив копии не должен разделяться, получено %q", list.Values())
+	}
+}